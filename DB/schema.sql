@@ -0,0 +1,165 @@
+-- 追加テーブルの定義
+-- 既存のusers, income_forecast_dataテーブルは作成済みの前提
+
+-- ユーザーのロール(user | admin | support)と管理者による無効化
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(10) NOT NULL DEFAULT 'user'
+	CHECK (role IN ('user', 'admin', 'support'));
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMP;
+
+-- 退会したユーザーの削除予定日時(この日時を過ぎたユーザーと年収推移データを削除する)
+-- 退会中は delete_flag = 1 とする
+ALTER TABLE users ADD COLUMN IF NOT EXISTS purge_at TIMESTAMP;
+-- 以前はサインアップ時に delete_flag = 1 を登録していたため、退会中でないユーザーは 0 に揃える
+UPDATE users SET delete_flag = '0' WHERE purge_at IS NULL;
+CREATE INDEX IF NOT EXISTS users_purge_at_idx
+	ON users (purge_at) WHERE purge_at IS NOT NULL;
+
+-- パスワードのハッシュはArgon2idのPHC文字列形式(約100文字)で登録する
+ALTER TABLE users ALTER COLUMN user_password TYPE VARCHAR(255);
+
+-- ローン情報
+CREATE TABLE IF NOT EXISTS loan_data (
+	loan_id          UUID PRIMARY KEY,
+	user_id          INTEGER      NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	loan_name        VARCHAR(100) NOT NULL,
+	principal        BIGINT       NOT NULL,
+	annual_rate      NUMERIC(6,3) NOT NULL,
+	term_months      INTEGER      NOT NULL,
+	start_date       DATE         NOT NULL,
+	rate_type        VARCHAR(10)  NOT NULL, -- fixed | variable
+	repayment_method VARCHAR(20)  NOT NULL, -- equal_payment | equal_principal
+	created_at       TIMESTAMP    NOT NULL,
+	update_at        TIMESTAMP
+);
+
+-- 収支シナリオ(PriceCalcの入力値)
+-- ユーザー毎に有効なシナリオは1件のみ
+CREATE TABLE IF NOT EXISTS price_scenario (
+	scenario_id    UUID PRIMARY KEY,
+	user_id        INTEGER      NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	scenario_name  VARCHAR(100) NOT NULL,
+	money_received BIGINT       NOT NULL,
+	bouns          BIGINT       NOT NULL,
+	fixed_cost     BIGINT       NOT NULL,
+	loan           BIGINT       NOT NULL,
+	private        BIGINT       NOT NULL,
+	insurance      BIGINT       NOT NULL,
+	active_flag    BOOLEAN      NOT NULL DEFAULT false,
+	created_at     TIMESTAMP    NOT NULL,
+	update_at      TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS price_scenario_active_idx
+	ON price_scenario (user_id) WHERE active_flag;
+
+-- 貯金目標
+CREATE TABLE IF NOT EXISTS savings_goal (
+	goal_id         UUID PRIMARY KEY,
+	user_id         INTEGER      NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	goal_name       VARCHAR(100) NOT NULL,
+	target_amount   BIGINT       NOT NULL,
+	target_date     DATE         NOT NULL,
+	current_balance BIGINT       NOT NULL,
+	created_at      TIMESTAMP    NOT NULL,
+	update_at       TIMESTAMP
+);
+
+-- 貯金目標の進捗履歴
+CREATE TABLE IF NOT EXISTS savings_goal_snapshot (
+	snapshot_id      UUID PRIMARY KEY,
+	goal_id          UUID        NOT NULL REFERENCES savings_goal(goal_id) ON DELETE CASCADE,
+	balance          BIGINT      NOT NULL,
+	required_monthly BIGINT      NOT NULL,
+	left_amount      BIGINT      NOT NULL,
+	status           VARCHAR(10) NOT NULL, -- on_track | at_risk | off_track
+	created_at       TIMESTAMP   NOT NULL
+);
+
+-- サインインセッション(サインイン毎に1件)
+CREATE TABLE IF NOT EXISTS user_session (
+	session_id   UUID PRIMARY KEY,
+	user_id      INTEGER      NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	sign_in_type VARCHAR(10)  NOT NULL, -- password | magic_link | 外部認証の名称(google | line など)
+	user_agent   VARCHAR(512) NOT NULL,
+	ip_address   VARCHAR(45)  NOT NULL,
+	created_at   TIMESTAMP    NOT NULL,
+	last_seen_at TIMESTAMP    NOT NULL,
+	revoked_at   TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS user_session_user_idx
+	ON user_session (user_id) WHERE revoked_at IS NULL;
+
+-- 二段階認証(TOTP)
+-- 登録開始時に enabled = false で作成し、コードの確認後に有効にする
+CREATE TABLE IF NOT EXISTS user_totp (
+	user_id    INTEGER     PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+	secret     VARCHAR(64) NOT NULL,
+	enabled    BOOLEAN     NOT NULL DEFAULT false,
+	created_at TIMESTAMP   NOT NULL,
+	enabled_at TIMESTAMP
+);
+
+-- 二段階認証のリカバリーコード(SHA-256でハッシュ化して保存、1回のみ使用可)
+CREATE TABLE IF NOT EXISTS user_recovery_code (
+	user_id   INTEGER   NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	code_hash CHAR(64)  NOT NULL,
+	used_at   TIMESTAMP,
+	PRIMARY KEY (user_id, code_hash)
+);
+
+-- 外部認証(Google・LINE など設定で登録した外部認証)の連携
+-- 外部認証のユーザーIDで本人を特定するため、メールアドレスが異なっていても同じユーザーとしてサインインできる
+CREATE TABLE IF NOT EXISTS user_identity (
+	provider         VARCHAR(20)  NOT NULL, -- 外部認証の名称(google | line など)
+	provider_subject VARCHAR(255) NOT NULL,
+	user_id          INTEGER      NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	provider_email   VARCHAR(255) NOT NULL,
+	linked_at        TIMESTAMP    NOT NULL,
+	PRIMARY KEY (provider, provider_subject),
+	UNIQUE (user_id, provider)
+);
+
+-- 管理者用APIの操作履歴
+-- 対象ユーザーが削除されても履歴は残すため、ユーザーへの外部キーは設定しない
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+	audit_id       UUID PRIMARY KEY,
+	actor_user_id  INTEGER     NOT NULL,
+	actor_role     VARCHAR(10) NOT NULL,
+	action         VARCHAR(50) NOT NULL,
+	target_user_id INTEGER,
+	detail         TEXT        NOT NULL,
+	ip_address     VARCHAR(45) NOT NULL,
+	created_at     TIMESTAMP   NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS admin_audit_log_created_idx
+	ON admin_audit_log (created_at DESC);
+
+-- 個人データのエクスポート(ZIPファイルはサーバーのローカルに保存する)
+-- 作成中(pending)のエクスポートはユーザーごとに1件のみ
+CREATE TABLE IF NOT EXISTS data_export (
+	export_id    UUID PRIMARY KEY,
+	user_id      INTEGER     NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	status       VARCHAR(10) NOT NULL, -- pending | ready | failed
+	file_path    TEXT,
+	token_hash   CHAR(64)    UNIQUE,
+	created_at   TIMESTAMP   NOT NULL,
+	completed_at TIMESTAMP,
+	expires_at   TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS data_export_pending_idx
+	ON data_export (user_id) WHERE status = 'pending';
+
+-- パスワードの変更履歴(変更前のパスワードのハッシュ)
+-- 直近のパスワードの再利用を禁止するため、ポリシーの件数分のみ保持する
+CREATE TABLE IF NOT EXISTS password_history (
+	history_id    BIGSERIAL PRIMARY KEY,
+	user_id       INTEGER      NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+	password_hash VARCHAR(255) NOT NULL,
+	created_at    TIMESTAMP    NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS password_history_user_idx
+	ON password_history (user_id, created_at DESC);
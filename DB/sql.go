@@ -54,6 +54,12 @@ const GetSignInSyntax = `
 			WHERE user_email = $1;
 			`
 
+const GetSignInByUserIdSyntax = `
+			SELECT user_id, user_email, user_password
+			FROM users
+			WHERE user_id = $1;
+			`
+
 const PasswordCheckSyntax = `
 			SELECT user_email
 			FROM users
@@ -83,6 +89,26 @@ const PutSignInEditSyntax = `
 				user_id = $4;
 			`
 
+// 変更先のメールアドレスが他のユーザーに登録済みの場合は更新しない
+const ChangeUserEmailSyntax = `
+			UPDATE users
+			SET
+				user_email = $1,
+				update_at  = $2
+			WHERE
+				user_id = $3
+				AND user_email = $4
+				AND NOT EXISTS (SELECT 1 FROM users WHERE user_email = $1);
+			`
+
+// 外部認証のユーザーはパスワードに外部認証の名称を登録している
+// パスワードのハッシュは $ から始まるため、名称と区別できる
+const GetUserAccountSyntax = `
+			SELECT user_id, user_email, user_password LIKE '$%'
+			FROM users
+			WHERE user_id = $1;
+			`
+
 const PutPasswordSyntax = `
 			UPDATE users
 			SET
@@ -92,8 +118,464 @@ const PutPasswordSyntax = `
 				user_id = $3;
 			`
 
+// 現在のパスワードと直近の変更前のパスワードのハッシュ
+// 外部認証のユーザーはパスワードのハッシュがないため、現在のパスワードは含めない
+const GetPasswordHistorySyntax = `
+			SELECT user_password
+			FROM users
+			WHERE user_id = $1 AND user_password LIKE '$%'
+			UNION ALL
+			(SELECT password_hash
+			FROM password_history
+			WHERE user_id = $1
+			ORDER BY created_at DESC, history_id DESC
+			LIMIT $2);
+			`
+
+// パスワードの変更前に現在のパスワードのハッシュを履歴に登録する
+const InsertPasswordHistorySyntax = `
+			INSERT INTO password_history (user_id, password_hash, created_at)
+			SELECT user_id, user_password, $2
+			FROM users
+			WHERE user_id = $1 AND user_password LIKE '$%';
+			`
+
+// 直近の履歴のみ残し、古い履歴を削除する
+const PrunePasswordHistorySyntax = `
+			DELETE FROM password_history
+			WHERE user_id = $1
+				AND history_id NOT IN (
+					SELECT history_id
+					FROM password_history
+					WHERE user_id = $1
+					ORDER BY created_at DESC, history_id DESC
+					LIMIT $2
+				);
+			`
+
+// サインイン時にハッシュの方式・パラメーターを更新する
+// 照合後にパスワードが変更されている場合は上書きしない
+const UpdatePasswordHashSyntax = `
+			UPDATE users
+			SET
+				user_password = $1
+			WHERE
+				user_id = $2
+				AND user_password = $3;
+			`
+
+// 退会は削除フラグと削除予定日時を登録し、削除予定日時まで復元できるようにする
 const DeleteSignInSyntax = `
+			UPDATE users
+			SET
+				delete_flag = $1,
+				purge_at = $2,
+				update_at = $3
+			WHERE user_id = $4
+			and user_email = $5
+			and purge_at IS NULL;
+			`
+
+// 削除予定日時を過ぎたユーザーは復元しない
+const RestoreSignInSyntax = `
+			UPDATE users
+			SET
+				delete_flag = $1,
+				purge_at = NULL,
+				update_at = $2
+			WHERE user_id = $3
+			and purge_at > $2;
+			`
+
+// 年収推移データはユーザーへの外部キーがないため、ユーザーより先に削除する
+const PurgeIncomeSyntax = `
+			DELETE FROM income_forecast_data
+			WHERE user_id IN (
+				SELECT user_id FROM users
+				WHERE purge_at <= $1
+			);
+			`
+
+// その他のユーザーのデータは外部キーの ON DELETE CASCADE で削除する
+const PurgeUsersSyntax = `
 			DELETE FROM users
+			WHERE purge_at <= $1
+			RETURNING user_id, user_email, coalesce(create_user, '');
+			`
+
+const GetLoansSyntax = `
+			SELECT loan_id, user_id, loan_name, principal, annual_rate, term_months, start_date, rate_type, repayment_method
+			FROM loan_data
+			WHERE user_id = $1
+			ORDER BY start_date ASC;
+			`
+
+const GetLoanSyntax = `
+			SELECT loan_id, user_id, loan_name, principal, annual_rate, term_months, start_date, rate_type, repayment_method
+			FROM loan_data
+			WHERE loan_id = $1 AND user_id = $2;
+			`
+
+const InsertLoanSyntax = `
+			INSERT INTO loan_data
+			(loan_id, user_id, loan_name, principal, annual_rate, term_months, start_date, rate_type, repayment_method, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+			`
+
+const UpdateLoanSyntax = `
+			UPDATE loan_data
+			SET
+				loan_name = $1,
+				principal = $2,
+				annual_rate = $3,
+				term_months = $4,
+				start_date = $5,
+				rate_type = $6,
+				repayment_method = $7,
+				update_at = $8
+			WHERE loan_id = $9 AND user_id = $10;
+			`
+
+const DeleteLoanSyntax = `
+			DELETE FROM loan_data
+			WHERE loan_id = $1 AND user_id = $2;
+			`
+
+const GetPriceScenariosSyntax = `
+			SELECT scenario_id, user_id, scenario_name, money_received, bouns, fixed_cost, loan, private, insurance, active_flag
+			FROM price_scenario
+			WHERE user_id = $1
+			ORDER BY created_at ASC;
+			`
+
+const GetActivePriceScenarioSyntax = `
+			SELECT scenario_id, user_id, scenario_name, money_received, bouns, fixed_cost, loan, private, insurance, active_flag
+			FROM price_scenario
+			WHERE user_id = $1 AND active_flag = true;
+			`
+
+const InsertPriceScenarioSyntax = `
+			INSERT INTO price_scenario
+			(scenario_id, user_id, scenario_name, money_received, bouns, fixed_cost, loan, private, insurance, active_flag, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);
+			`
+
+const DeactivatePriceScenarioSyntax = `
+			UPDATE price_scenario
+			SET
+				active_flag = false,
+				update_at = $1
+			WHERE user_id = $2 AND active_flag = true;
+			`
+
+const ActivatePriceScenarioSyntax = `
+			UPDATE price_scenario
+			SET
+				active_flag = true,
+				update_at = $1
+			WHERE scenario_id = $2 AND user_id = $3;
+			`
+
+const DeletePriceScenarioSyntax = `
+			DELETE FROM price_scenario
+			WHERE scenario_id = $1 AND user_id = $2;
+			`
+
+const GetSavingsGoalsSyntax = `
+			SELECT goal_id, user_id, goal_name, target_amount, target_date, current_balance
+			FROM savings_goal
+			WHERE user_id = $1
+			ORDER BY target_date ASC;
+			`
+
+const InsertSavingsGoalSyntax = `
+			INSERT INTO savings_goal
+			(goal_id, user_id, goal_name, target_amount, target_date, current_balance, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7);
+			`
+
+const UpdateSavingsGoalSyntax = `
+			UPDATE savings_goal
+			SET
+				goal_name = $1,
+				target_amount = $2,
+				target_date = $3,
+				current_balance = $4,
+				update_at = $5
+			WHERE goal_id = $6 AND user_id = $7;
+			`
+
+const DeleteSavingsGoalSyntax = `
+			DELETE FROM savings_goal
+			WHERE goal_id = $1 AND user_id = $2;
+			`
+
+const GetSavingsGoalSnapshotsSyntax = `
+			SELECT s.snapshot_id, s.goal_id, s.balance, s.required_monthly, s.left_amount, s.status, s.created_at
+			FROM savings_goal_snapshot s
+			INNER JOIN savings_goal g ON g.goal_id = s.goal_id
+			WHERE s.goal_id = $1 AND g.user_id = $2
+			ORDER BY s.created_at ASC;
+			`
+
+const InsertSavingsGoalSnapshotSyntax = `
+			INSERT INTO savings_goal_snapshot
+			(snapshot_id, goal_id, balance, required_monthly, left_amount, status, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7);
+			`
+
+const GetSessionsSyntax = `
+			SELECT session_id, user_id, sign_in_type, user_agent, ip_address, created_at, last_seen_at
+			FROM user_session
+			WHERE user_id = $1 AND revoked_at IS NULL AND last_seen_at >= $2
+			ORDER BY last_seen_at DESC;
+			`
+
+const InsertSessionSyntax = `
+			INSERT INTO user_session
+			(session_id, user_id, sign_in_type, user_agent, ip_address, created_at, last_seen_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7);
+			`
+
+const TouchSessionSyntax = `
+			UPDATE user_session
+			SET last_seen_at = $1
+			WHERE session_id = $2 AND revoked_at IS NULL;
+			`
+
+const RevokeSessionSyntax = `
+			UPDATE user_session
+			SET revoked_at = $1
+			WHERE session_id = $2 AND user_id = $3 AND revoked_at IS NULL;
+			`
+
+const RevokeOtherSessionsSyntax = `
+			UPDATE user_session
+			SET revoked_at = $1
+			WHERE user_id = $2 AND session_id <> $3 AND revoked_at IS NULL
+			RETURNING session_id;
+			`
+
+// 外部認証のユーザーはパスワードに外部認証の名称を登録している
+// パスワードのハッシュは $ から始まるため、名称と区別できる
+const GetTotpSyntax = `
+			SELECT u.user_id, u.user_email, u.user_password LIKE '$%',
+				coalesce(t.secret, ''), coalesce(t.enabled, false)
+			FROM users u
+			LEFT JOIN user_totp t ON t.user_id = u.user_id
+			WHERE u.user_id = $1;
+			`
+
+const UpsertTotpSecretSyntax = `
+			INSERT INTO user_totp
+			(user_id, secret, enabled, created_at)
+			VALUES ($1, $2, false, $3)
+			ON CONFLICT (user_id) DO UPDATE
+			SET secret = EXCLUDED.secret, created_at = EXCLUDED.created_at
+			WHERE user_totp.enabled = false;
+			`
+
+const EnableTotpSyntax = `
+			UPDATE user_totp
+			SET enabled = true, enabled_at = $1
+			WHERE user_id = $2 AND enabled = false;
+			`
+
+const DeleteTotpSyntax = `
+			DELETE FROM user_totp
+			WHERE user_id = $1;
+			`
+
+const DeleteRecoveryCodesSyntax = `
+			DELETE FROM user_recovery_code
+			WHERE user_id = $1;
+			`
+
+const InsertRecoveryCodeSyntax = `
+			INSERT INTO user_recovery_code
+			(user_id, code_hash)
+			VALUES ($1, $2);
+			`
+
+const UseRecoveryCodeSyntax = `
+			UPDATE user_recovery_code
+			SET used_at = $1
+			WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL;
+			`
+
+const GetIdentitiesSyntax = `
+			SELECT provider, provider_subject, provider_email, linked_at
+			FROM user_identity
+			WHERE user_id = $1
+			ORDER BY linked_at ASC;
+			`
+
+const GetIdentityUserSyntax = `
+			SELECT u.user_id, u.user_email
+			FROM user_identity i
+			JOIN users u ON u.user_id = i.user_id
+			WHERE i.provider = $1 AND i.provider_subject = $2;
+			`
+
+// 連携テーブル追加前に外部認証で登録したユーザー(パスワードに認証方法の名称を登録している)
+const GetLegacyExternalUserSyntax = `
+			SELECT user_id, user_email
+			FROM users
+			WHERE user_email = $1 AND user_password = $2
+				AND NOT EXISTS (
+					SELECT 1 FROM user_identity
+					WHERE user_identity.user_id = users.user_id AND user_identity.provider = $2
+				);
+			`
+
+// 外部アカウントが他のユーザーに連携済み、又は同じ認証方法を連携済みの場合は登録しない
+const InsertIdentitySyntax = `
+			INSERT INTO user_identity
+			(provider, provider_subject, user_id, provider_email, linked_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT DO NOTHING;
+			`
+
+// 連携解除中に他のサインイン方法が変更されないようユーザーをロックする
+const LockSignInMethodsSyntax = `
+			SELECT user_password LIKE '$%',
+				(SELECT count(*) FROM user_identity WHERE user_id = $1)
+			FROM users
+			WHERE user_id = $1
+			FOR UPDATE;
+			`
+
+const DeleteIdentitySyntax = `
+			DELETE FROM user_identity
+			WHERE user_id = $1 AND provider = $2;
+			`
+
+const GetUserRoleSyntax = `
+			SELECT role, disabled_at IS NOT NULL, purge_at IS NOT NULL
+			FROM users
+			WHERE user_id = $1;
+			`
+
+// 検索文字列が空の場合は全てのユーザーを対象にする
+// 件数はページングの前の総件数
+const GetAdminUsersSyntax = `
+			SELECT user_id, user_email, coalesce(create_user, ''), role,
+				user_password LIKE '$%', disabled_at, create_at, count(*) OVER()
+			FROM users
+			WHERE ($1 = '' OR user_email ILIKE '%' || $1 || '%' OR create_user ILIKE '%' || $1 || '%')
+				AND ($2 = '' OR role = $2)
+			ORDER BY user_id ASC
+			LIMIT $3 OFFSET $4;
+			`
+
+// 有効にする場合は disabled_at に null を指定する
+const SetUserDisabledSyntax = `
+			UPDATE users
+			SET
+				disabled_at = $1,
+				update_at  = $2
+			WHERE
+				user_id = $3;
+			`
+
+const GetUserCountsSyntax = `
+			SELECT count(*), count(disabled_at)
+			FROM users;
+			`
+
+const GetSignUpStatsSyntax = `
+			SELECT TO_CHAR(create_at, 'YYYY-MM-DD') as "sign_up_date", count(*)
+			FROM users
+			WHERE create_at >= $1 AND create_at < $2
+			GROUP BY TO_CHAR(create_at, 'YYYY-MM-DD')
+			ORDER BY TO_CHAR(create_at, 'YYYY-MM-DD') ASC;
+			`
+
+const InsertAuditLogSyntax = `
+			INSERT INTO admin_audit_log
+			(audit_id, actor_user_id, actor_role, action, target_user_id, detail, ip_address, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+			`
+
+// 対象ユーザーIDが0の場合は全ての操作履歴を対象にする
+const GetAuditLogsSyntax = `
+			SELECT audit_id, actor_user_id, actor_role, action, target_user_id, detail, ip_address, created_at,
+				count(*) OVER()
+			FROM admin_audit_log
+			WHERE $1 = 0 OR target_user_id = $1
+			ORDER BY created_at DESC
+			LIMIT $2 OFFSET $3;
+			`
+
+// 作成中のまま一定時間を過ぎたエクスポート(サーバー停止などで中断したもの)は失敗扱いにする
+const FailStaleDataExportSyntax = `
+			UPDATE data_export
+			SET status = $1, completed_at = $2, expires_at = $2
+			WHERE user_id = $3 AND status = $4 AND created_at < $5;
+			`
+
+// 作成中のエクスポートがある場合は一意制約で登録しない
+const InsertDataExportSyntax = `
+			INSERT INTO data_export
+			(export_id, user_id, status, created_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT DO NOTHING;
+			`
+
+const CompleteDataExportSyntax = `
+			UPDATE data_export
+			SET status = $1, file_path = $2, token_hash = $3, completed_at = $4, expires_at = $5
+			WHERE export_id = $6;
+			`
+
+// 失敗したエクスポートは次回の期限切れの削除で削除する
+const FailDataExportSyntax = `
+			UPDATE data_export
+			SET status = $1, completed_at = $2, expires_at = $2
+			WHERE export_id = $3;
+			`
+
+const GetDataExportSyntax = `
+			SELECT export_id, user_id, status, file_path, created_at, expires_at
+			FROM data_export
+			WHERE token_hash = $1 AND status = $2 AND expires_at > $3;
+			`
+
+const DeleteExpiredDataExportsSyntax = `
+			DELETE FROM data_export
+			WHERE expires_at <= $1
+			RETURNING coalesce(file_path, '');
+			`
+
+const GetExportProfileSyntax = `
+			SELECT user_id, user_email, coalesce(create_user, ''), role, create_at
+			FROM users
+			WHERE user_id = $1;
+			`
+
+const GetExportIncomeSyntax = `
+			SELECT income_forecast_id, payment_date, age, industry, total_amount, deduction_amount, take_home_amount, classification, user_id
+			FROM income_forecast_data
+			WHERE user_id = $1
+			ORDER BY payment_date ASC;
+			`
+
+// 操作した管理者のユーザーID・IPアドレスは含めない
+const GetExportAuditLogsSyntax = `
+			SELECT audit_id, actor_role, action, detail, created_at
+			FROM admin_audit_log
+			WHERE target_user_id = $1
+			ORDER BY created_at ASC;
+			`
+
+// インポート中に他のインポートと重複しないようユーザーをロックする
+const LockImportUserSyntax = `
+			SELECT
+				(SELECT count(*) FROM income_forecast_data WHERE user_id = $1),
+				(SELECT count(*) FROM price_scenario WHERE user_id = $1),
+				(SELECT count(*) FROM loan_data WHERE user_id = $1),
+				(SELECT count(*) FROM savings_goal WHERE user_id = $1)
+			FROM users
 			WHERE user_id = $1
-			and user_email = $2;
+			FOR UPDATE;
 			`
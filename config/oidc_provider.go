@@ -0,0 +1,150 @@
+// config/oidc_provider.go
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+type (
+	// OIDCProviderConfig は外部認証(OpenID Connect)の設定
+	// エンドポイントは発行者のディスカバリードキュメントから取得する
+	OIDCProviderConfig struct {
+		// 外部認証の名称(ルートの :provider)
+		Name         string
+		Issuer       string
+		ClientID     string
+		ClientSecret string
+		Scopes       []string
+		// 認可リクエストに追加するパラメータ
+		AuthParams map[string]string
+		// ディスカバリードキュメントの issuer 以外に許可する発行者(iss)
+		ExtraIssuers []string
+		// email_verified が true のIDトークンのみ受け付ける
+		RequireEmailVerified bool
+		// IDトークンのHS256署名をクライアントシークレットで検証する(LINE)
+		SymmetricIdToken bool
+		// トークンリクエストのクライアント認証をBASIC認証ではなくリクエストボディで行う
+		TokenAuthInParams bool
+		// ディスカバリードキュメントに revocation_endpoint がない場合のトークン無効化のURL
+		RevocationURL string
+		// トークン無効化でアクセストークンを渡すパラメータ名(既定は RFC 7009 の token)
+		RevocationTokenParam string
+	}
+)
+
+// 外部認証の名称はルート・ユーザーのパスワード欄・セッションのサインイン方法に使用するため
+// 英小文字・数字の10文字以内(user_session.sign_in_type に収まる長さ)
+var oidcProviderNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,9}$`)
+
+// 設定を省略した場合のスコープ
+var defaultOIDCScopes = []string{"openid", "email", "profile"}
+
+const GoogleIssuer = "https://accounts.google.com"
+const LineIssuer = "https://access.line.me"
+const OauthLineRevokeURLAPI = "https://api.line.me/oauth2/v2.1/revoke"
+
+// OIDCProviderConfigs は有効な外部認証の設定を返す
+// Google・LINEはクライアントIDを設定した場合に有効になり、
+// それ以外は OIDC_PROVIDERS に列挙した名称ごとに OIDC_<名称>_* の環境変数から読み込む
+//
+// 戻り値:
+//
+//	戻り値1: 外部認証の設定
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func OIDCProviderConfigs() ([]OIDCProviderConfig, error) {
+	var providers []OIDCProviderConfig
+
+	if GlobalEnv.GoogleClientID != "" {
+		providers = append(providers, OIDCProviderConfig{
+			Name:                 "google",
+			Issuer:               GoogleIssuer,
+			ClientID:             GlobalEnv.GoogleClientID,
+			ClientSecret:         GlobalEnv.GoogleClientSecret,
+			Scopes:               defaultOIDCScopes,
+			AuthParams:           map[string]string{"prompt": "select_account"},
+			ExtraIssuers:         []string{"accounts.google.com"},
+			RequireEmailVerified: true,
+		})
+	}
+
+	if GlobalEnv.LineClientID != "" {
+		providers = append(providers, OIDCProviderConfig{
+			Name:                 "line",
+			Issuer:               LineIssuer,
+			ClientID:             GlobalEnv.LineClientID,
+			ClientSecret:         GlobalEnv.LineClientSecret,
+			Scopes:               []string{"openid", "profile", "email"},
+			SymmetricIdToken:     true,
+			TokenAuthInParams:    true,
+			RevocationURL:        OauthLineRevokeURLAPI,
+			RevocationTokenParam: "access_token",
+		})
+	}
+
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+
+		scopes := strings.Fields(os.Getenv(prefix + "SCOPES"))
+		if len(scopes) == 0 {
+			scopes = defaultOIDCScopes
+		}
+		providers = append(providers, OIDCProviderConfig{
+			Name:                 name,
+			Issuer:               os.Getenv(prefix + "ISSUER"),
+			ClientID:             os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret:         os.Getenv(prefix + "CLIENT_SECRET"),
+			Scopes:               scopes,
+			RequireEmailVerified: os.Getenv(prefix+"REQUIRE_EMAIL_VERIFIED") != "false",
+		})
+	}
+
+	seen := map[string]bool{}
+	for _, provider := range providers {
+		if err := provider.Validate(); err != nil {
+			return nil, err
+		}
+		if seen[provider.Name] {
+			return nil, fmt.Errorf("外部認証の名称が重複しています: %s", provider.Name)
+		}
+		seen[provider.Name] = true
+	}
+
+	return providers, nil
+}
+
+// Validate は外部認証の設定を検証する
+func (pc OIDCProviderConfig) Validate() error {
+	if !oidcProviderNamePattern.MatchString(pc.Name) {
+		return fmt.Errorf("外部認証の名称が不正です: %q", pc.Name)
+	}
+	if !strings.HasPrefix(pc.Issuer, "https://") && !strings.HasPrefix(pc.Issuer, "http://") {
+		return fmt.Errorf("外部認証(%s)の発行者が不正です: %q", pc.Name, pc.Issuer)
+	}
+	if pc.ClientID == "" {
+		return fmt.Errorf("外部認証(%s)のクライアントIDが設定されていません。", pc.Name)
+	}
+	if pc.SymmetricIdToken && pc.ClientSecret == "" {
+		return fmt.Errorf("外部認証(%s)のクライアントシークレットが設定されていません。", pc.Name)
+	}
+	return nil
+}
+
+// OAuthRedirectURI は外部認証の認可後のリダイレクト先
+//
+// 引数:
+//   - provider: 外部認証の名称
+//   - purpose: 外部認証の用途(signin | signup | delete | link)
+//
+
+func OAuthRedirectURI(provider, purpose string) string {
+	return fmt.Sprintf("%sauth/%s/%s/callback", GlobalEnv.RedirectURI, provider, purpose)
+}
@@ -16,6 +16,11 @@ type (
 		RedisSet(key string, value interface{}, duration time.Duration) error
 		RedisGet(key string) (string, error)
 		RedisDel(key string) error
+		RedisExists(key string) (bool, error)
+		RedisIncr(key string, duration time.Duration) (int64, error)
+		RedisGetDel(key string) (string, error)
+		RedisCompareAndSet(key string, expected string, value interface{}, duration time.Duration) (bool, error)
+		RedisSetIfGreater(key string, value int64, duration time.Duration) (bool, error)
 	}
 
 	RedisManager struct{}
@@ -46,17 +51,24 @@ func (rm *RedisManager) InitRedisClient() *redis.Client {
 	return rdb
 }
 
-func (rm *RedisManager) RedisSet(key string, value interface{}, duration time.Duration) error {
-	var data string
+// redisValue は保存する値を文字列に変換する(文字列以外はJSONに変換する)
+func redisValue(value interface{}) (string, error) {
 	switch v := value.(type) {
 	case string:
-		data = v
+		return v, nil
 	default:
 		bytes, err := json.Marshal(v)
 		if err != nil {
-			return fmt.Errorf("値のJSON変換エラー: %s", err.Error())
+			return "", fmt.Errorf("値のJSON変換エラー: %s", err.Error())
 		}
-		data = string(bytes)
+		return string(bytes), nil
+	}
+}
+
+func (rm *RedisManager) RedisSet(key string, value interface{}, duration time.Duration) error {
+	data, err := redisValue(value)
+	if err != nil {
+		return err
 	}
 
 	if err := rm.InitRedisClient().Set(Ctx, key, data, duration).Err(); err != nil {
@@ -85,3 +97,79 @@ func (rm *RedisManager) RedisDel(key string) error {
 	}
 	return nil
 }
+
+func (rm *RedisManager) RedisExists(key string) (bool, error) {
+	count, err := rm.InitRedisClient().Exists(Ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("存在確認エラー: %w", err)
+	}
+	return count > 0, nil
+}
+
+// RedisIncr はキーの値を1加算し、加算後の値を返す
+// キーの有効期限は加算の度に duration へ更新する
+func (rm *RedisManager) RedisIncr(key string, duration time.Duration) (int64, error) {
+	pipe := rm.InitRedisClient().TxPipeline()
+	incr := pipe.Incr(Ctx, key)
+	pipe.Expire(Ctx, key, duration)
+	if _, err := pipe.Exec(Ctx); err != nil {
+		return 0, fmt.Errorf("加算エラー: %w", err)
+	}
+	return incr.Val(), nil
+}
+
+// RedisGetDel はキーの値を取得すると同時に削除する
+// 一度しか使用できない値(ワンタイムトークン等)を同時に使用されないよう、取得と削除を1回の操作で行う
+func (rm *RedisManager) RedisGetDel(key string) (string, error) {
+	value, err := rm.InitRedisClient().GetDel(Ctx, key).Result()
+	if err == redis.Nil {
+		return "", fmt.Errorf("キーが存在しません: %s", key)
+	} else if err != nil {
+		return "", fmt.Errorf("取得エラー: %w", err)
+	}
+	return value, nil
+}
+
+// compareAndSetScript は現在の値が期待する値と一致する場合のみ値を更新する
+var compareAndSetScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+	return 1
+end
+return 0
+`)
+
+// RedisCompareAndSet はキーの値が expected と一致する場合のみ value に更新する
+// 取得・比較・更新を1回の操作で行い、同時に更新された場合は更新しない
+func (rm *RedisManager) RedisCompareAndSet(key string, expected string, value interface{}, duration time.Duration) (bool, error) {
+	data, err := redisValue(value)
+	if err != nil {
+		return false, err
+	}
+
+	swapped, err := compareAndSetScript.Run(Ctx, rm.InitRedisClient(), []string{key}, expected, data, duration.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("更新エラー: %w", err)
+	}
+	return swapped == 1, nil
+}
+
+// setIfGreaterScript は現在の値より大きい場合のみ値を更新する(キーが存在しない場合は登録する)
+var setIfGreaterScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if current and tonumber(current) >= tonumber(ARGV[1]) then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+return 1
+`)
+
+// RedisSetIfGreater はキーの値が value より小さい(又は存在しない)場合のみ value に更新する
+// 取得・比較・更新を1回の操作で行い、同時に更新された場合も同じ値で2回成功しない
+func (rm *RedisManager) RedisSetIfGreater(key string, value int64, duration time.Duration) (bool, error) {
+	updated, err := setIfGreaterScript.Run(Ctx, rm.InitRedisClient(), []string{key}, value, duration.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("更新エラー: %w", err)
+	}
+	return updated == 1, nil
+}
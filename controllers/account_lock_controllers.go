@@ -0,0 +1,225 @@
+// controllers/account_lock_controllers.go
+package controllers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"server/config"
+	"server/models"
+	"server/utils"
+	"server/validation"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	RequestAdminAccountUnlockData struct {
+		UserEmail string `json:"user_email"`
+	}
+)
+
+const (
+	signInAttemptsMessage         = "サインインの試行回数が多すぎます。しばらく時間をおいて再度お試しください。"
+	confirmCodeAttemptsMessage    = "認証コードの試行回数が多すぎます。しばらく時間をおいて再度お試しください。"
+	authEmailRetryAttemptsMessage = "認証コードの再送信回数が多すぎます。しばらく時間をおいて再度お試しください。"
+)
+
+// accountUnlockKey はロック解除用のトークンを保存するRedisのキー
+func accountUnlockKey(token string) string {
+	return fmt.Sprintf("account_unlock:%s", token)
+}
+
+// tooManyAttempts は試行回数の制限中であることを Retry-After ヘッダー付きで返す
+func tooManyAttempts(c *gin.Context, wait time.Duration, message string) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	response := utils.ErrorMessageResponse{
+		Result: message,
+	}
+	c.JSON(http.StatusTooManyRequests, response)
+}
+
+// lockedWait はいずれかの試行キーがロックされている場合に最も長い待機時間を返す
+func lockedWait(statuses []utils.AttemptStatus) (time.Duration, bool) {
+	var wait time.Duration
+	locked := false
+	for _, status := range statuses {
+		if status.Locked {
+			wait = max(wait, status.Wait)
+			locked = true
+		}
+	}
+	return wait, locked
+}
+
+// checkAttempts は試行を受け付けられるかを確認し、待機中の場合はレスポンスを返す
+//
+// 引数:
+//   - c: Ginコンテキスト
+//   - message: 待機中の場合のメッセージ
+//   - keys: 試行キー
+//
+// 戻り値:
+//
+//	戻り値1: 試行を受け付ける場合はtrue
+//
+
+func (af *apiSignDataFetcher) checkAttempts(c *gin.Context, message string, keys ...utils.AttemptKey) bool {
+	wait, err := af.AttemptLimiter.Check(keys...)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "試行回数の確認に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return false
+	}
+	if wait > 0 {
+		tooManyAttempts(c, wait, message)
+		return false
+	}
+	return true
+}
+
+// recordFailedAttempts は全ての試行キーに失敗を記録し、キーの順に状態を返す
+func (af *apiSignDataFetcher) recordFailedAttempts(keys ...utils.AttemptKey) ([]utils.AttemptStatus, error) {
+	statuses := make([]utils.AttemptStatus, 0, len(keys))
+	for _, key := range keys {
+		status, err := af.AttemptLimiter.Fail(key)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// accountLockedNotice はアカウントのロックとロック解除のリンクをメールで通知する
+// 登録されていないメールアドレスの場合は通知しない
+func (af *apiSignDataFetcher) accountLockedNotice(userEmail string, lockout time.Duration) error {
+	dbFetcher, _, _ := models.NewSignDataFetcher(
+		config.GetDataBaseSource(),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+	)
+	if _, err := dbFetcher.GetUserId(userEmail); err != nil {
+		return nil
+	}
+
+	token := uuid.New().String()
+	if err := af.RedisService.RedisSet(accountUnlockKey(token), userEmail, lockout); err != nil {
+		return fmt.Errorf("ロック解除トークンの保存エラー: %v", err)
+	}
+
+	link := fmt.Sprintf("%saccount_unlock?token=%s", utils.GetBaseURL(), token)
+	subject, body, err := af.EmailTemplateService.AccountLockedTemplate(
+		userEmail,
+		link,
+		af.UtilsFetcher.DateTimeStr(time.Now(), "2006年01月02日 15:04"),
+	)
+	if err != nil {
+		return fmt.Errorf("メールテンプレート生成エラー(アカウントロック): %v", err)
+	}
+
+	if err := af.UtilsFetcher.SendMail(userEmail, subject, body, true); err != nil {
+		return fmt.Errorf("メール送信エラー(アカウントロック): %v", err)
+	}
+	return nil
+}
+
+// AccountUnlockApi はメールで通知したリンクからアカウントのロックを解除するAPI
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (af *apiSignDataFetcher) AccountUnlockApi(c *gin.Context) {
+	token := c.Query("token")
+
+	validator := validation.RequestAccountUnlockData{
+		Token: token,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userEmail, err := af.RedisService.RedisGet(accountUnlockKey(token))
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "ロック解除のリンクが無効又は有効期限切れです。",
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	if err := af.AttemptLimiter.Reset(utils.SignInAccountAttemptKey(userEmail)); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "アカウントのロック解除に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// リンクは1回のみ使用できる
+	_ = af.RedisService.RedisDel(accountUnlockKey(token))
+
+	response := utils.ResponseData[string]{
+		Result: "アカウントのロックを解除しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// AdminAccountUnlockApi は管理者がアカウントのロックを解除するAPI
+// 管理者用APIとして操作履歴を登録する
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (af *apiSignDataFetcher) AdminAccountUnlockApi(c *gin.Context) {
+	var requestData RequestAdminAccountUnlockData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	validator := validation.EmailCheckRequestData{
+		UserEmail: requestData.UserEmail,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := af.AttemptLimiter.Reset(utils.SignInAccountAttemptKey(requestData.UserEmail)); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "アカウントのロック解除に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if !recordAdminAudit(c, auditActionAccountUnlock, nil, gin.H{
+		"user_email": requestData.UserEmail,
+	}) {
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "アカウントのロックを解除しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,569 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"server/common"
+	mock_config "server/mock/config"
+	mock_limiter "server/mock/limiter"
+	mock_utils "server/mock/utils"
+	"server/models"
+	"server/templates"
+	"server/utils"
+	"testing"
+	"time"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// allowAllAttempts は試行回数の制限を行わないAttemptLimiterのモックを作成する
+func allowAllAttempts(t *testing.T) utils.AttemptLimiter {
+	mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(gomock.NewController(t))
+	mockAttemptLimiter.EXPECT().Check(gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+	mockAttemptLimiter.EXPECT().Check(gomock.Any(), gomock.Any()).Return(time.Duration(0), nil).AnyTimes()
+	mockAttemptLimiter.EXPECT().Fail(gomock.Any()).Return(utils.AttemptStatus{Failures: 1, Wait: time.Second}, nil).AnyTimes()
+	mockAttemptLimiter.EXPECT().Reset(gomock.Any()).Return(nil).AnyTimes()
+	return mockAttemptLimiter
+}
+
+func signInRequest(c *gin.Context) {
+	body, _ := json.Marshal(models.RequestSignInData{
+		UserEmail:    "test@example.com",
+		UserPassword: "Test123456!!",
+	})
+	c.Request = httptest.NewRequest("POST", "/api/signin", bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.RemoteAddr = "192.0.2.1:12345"
+}
+
+func TestPostSignInApiAttemptLimit(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	accountKey := utils.SignInAccountAttemptKey("test@example.com")
+	ipKey := utils.SignInIPAttemptKey("192.0.2.1")
+
+	t.Run("TestPostSignInApi 試行の待機中", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		signInRequest(c)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().
+			Check(accountKey, ipKey).
+			Return(1500*time.Millisecond, nil)
+
+		// 待機中はパスワードを照合しない
+		called := false
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"GetSignIn",
+			func(_ *models.SignDataFetcher, data models.RequestSignInData) (models.SignInData, error) {
+				called = true
+				return models.SignInData{}, nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mock_config.NewMockRedisService(ctrl),
+			AttemptLimiter:       mockAttemptLimiter,
+		}
+		fetcher.PostSignInApi(c)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "2", w.Header().Get("Retry-After"))
+		assert.False(t, called)
+
+		var responseBody utils.ErrorMessageResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, signInAttemptsMessage, responseBody.Result)
+	})
+
+	t.Run("TestPostSignInApi ロック前の失敗", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		signInRequest(c)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(accountKey, ipKey).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().Fail(accountKey).Return(utils.AttemptStatus{Failures: 2, Wait: 2 * time.Second}, nil)
+		mockAttemptLimiter.EXPECT().Fail(ipKey).Return(utils.AttemptStatus{Failures: 2, Wait: 2 * time.Second}, nil)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"GetSignIn",
+			func(_ *models.SignDataFetcher, data models.RequestSignInData) (models.SignInData, error) {
+				return models.SignInData{}, fmt.Errorf("パスワードが間違っています。")
+			})
+		defer patches.Reset()
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mock_config.NewMockRedisService(ctrl),
+			AttemptLimiter:       mockAttemptLimiter,
+		}
+		fetcher.PostSignInApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var responseBody utils.ErrorMessageResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, "パスワードが間違っています。", responseBody.Result)
+	})
+
+	t.Run("TestPostSignInApi 失敗回数が上限に達しロック", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		signInRequest(c)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(accountKey, ipKey).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().
+			Fail(accountKey).
+			Return(utils.AttemptStatus{Failures: 5, Wait: 30 * time.Minute, Locked: true, JustLocked: true}, nil)
+		mockAttemptLimiter.EXPECT().Fail(ipKey).Return(utils.AttemptStatus{Failures: 5, Wait: 16 * time.Second}, nil)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"GetSignIn",
+			func(_ *models.SignDataFetcher, data models.RequestSignInData) (models.SignInData, error) {
+				return models.SignInData{}, fmt.Errorf("パスワードが間違っています。")
+			})
+		defer patches.Reset()
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"GetUserId",
+			func(_ *models.SignDataFetcher, UserEmail string) (int, error) {
+				return 1, nil
+			})
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisSet(gomock.Any(), "test@example.com", 30*time.Minute).
+			Return(nil)
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().
+			DateTimeStr(gomock.Any(), gomock.Any()).
+			Return("2024年12月07日 20:00")
+		mockUtilsFetcher.EXPECT().
+			SendMail("test@example.com", "【たくわえる】アカウントを一時的にロックしました", gomock.Any(), true).
+			Return(nil)
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mockUtilsFetcher,
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mockRedisService,
+			AttemptLimiter:       mockAttemptLimiter,
+		}
+		fetcher.PostSignInApi(c)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "1800", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("TestPostSignInApi 未登録のメールアドレスはロックを通知しない", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		signInRequest(c)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(accountKey, ipKey).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().
+			Fail(accountKey).
+			Return(utils.AttemptStatus{Failures: 5, Wait: 30 * time.Minute, Locked: true, JustLocked: true}, nil)
+		mockAttemptLimiter.EXPECT().Fail(ipKey).Return(utils.AttemptStatus{Failures: 5, Wait: 16 * time.Second}, nil)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"GetSignIn",
+			func(_ *models.SignDataFetcher, data models.RequestSignInData) (models.SignInData, error) {
+				return models.SignInData{}, fmt.Errorf("ユーザーが存在しません。")
+			})
+		defer patches.Reset()
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"GetUserId",
+			func(_ *models.SignDataFetcher, UserEmail string) (int, error) {
+				return 0, fmt.Errorf("ユーザーが存在しません。")
+			})
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mock_utils.NewMockUtilsFetcher(ctrl),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mock_config.NewMockRedisService(ctrl),
+			AttemptLimiter:       mockAttemptLimiter,
+		}
+		fetcher.PostSignInApi(c)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+
+	t.Run("TestPostSignInApi 試行回数の記録に失敗", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		signInRequest(c)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(accountKey, ipKey).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().Fail(accountKey).Return(utils.AttemptStatus{}, fmt.Errorf("加算エラー"))
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"GetSignIn",
+			func(_ *models.SignDataFetcher, data models.RequestSignInData) (models.SignInData, error) {
+				return models.SignInData{}, fmt.Errorf("パスワードが間違っています。")
+			})
+		defer patches.Reset()
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mock_config.NewMockRedisService(ctrl),
+			AttemptLimiter:       mockAttemptLimiter,
+		}
+		fetcher.PostSignInApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var responseBody utils.ErrorMessageResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, "試行回数の記録に失敗しました。", responseBody.Result)
+	})
+}
+
+func TestPostSignUpApiAttemptLimit(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	redisKey := "71eb75e7-79b8-40d1-b581-d819d8470239"
+	// 認証コードの失敗回数は仮登録のIDではなく、仮登録したメールアドレス単位で数える
+	codeKey := utils.ConfirmCodeAttemptKey("test@example.com")
+	ipKey := utils.ConfirmCodeIPAttemptKey("192.0.2.1")
+
+	signUpRequest := func(c *gin.Context) {
+		body, _ := json.Marshal(RequestRedisKeyData{
+			RedisKey:      redisKey,
+			AuthEmailCode: "1234",
+		})
+		c.Request = httptest.NewRequest("POST", "/api/signup", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.RemoteAddr = "192.0.2.1:12345"
+	}
+
+	t.Run("PostSignUpApi 認証コードの試行の待機中", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		signUpRequest(c)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(ipKey).Return(4*time.Second, nil)
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mock_config.NewMockRedisService(ctrl),
+			AttemptLimiter:       mockAttemptLimiter,
+		}
+		fetcher.PostSignUpApi(c)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "4", w.Header().Get("Retry-After"))
+
+		var responseBody utils.ErrorMessageResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, confirmCodeAttemptsMessage, responseBody.Result)
+	})
+
+	t.Run("PostSignUpApi 再送信で仮登録のIDが変わっても失敗回数を引き継ぐ", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		signUpRequest(c)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// 再送信前の仮登録で失敗した回数の待機中
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(ipKey).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().Check(codeKey).Return(8*time.Second, nil)
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisGet(pendingSignUpKey(redisKey)).
+			Return(pendingSignUpValue("Test@Example.com", "Test12345!", "test"), nil)
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mockRedisService,
+			AttemptLimiter:       mockAttemptLimiter,
+		}
+		fetcher.PostSignUpApi(c)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "8", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("PostSignUpApi 認証コードの失敗回数が上限に達し仮登録を破棄", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		signUpRequest(c)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(ipKey).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().Check(codeKey).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().
+			Fail(codeKey).
+			Return(utils.AttemptStatus{Failures: 5, Wait: time.Hour, Locked: true, JustLocked: true}, nil)
+		mockAttemptLimiter.EXPECT().Fail(ipKey).Return(utils.AttemptStatus{Failures: 5, Wait: 16 * time.Second}, nil)
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisGet(pendingSignUpKey(redisKey)).
+			Return(pendingSignUpValue("test@example.com", "Test12345!", "test"), nil)
+		mockRedisService.EXPECT().RedisDel(pendingSignUpKey(redisKey)).Return(nil)
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mockRedisService,
+			AttemptLimiter:       mockAttemptLimiter,
+		}
+		fetcher.PostSignUpApi(c)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+		var responseBody utils.ErrorMessageResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, "認証コードの試行回数が上限に達しました。仮登録からやり直してください。", responseBody.Result)
+	})
+
+	t.Run("PostSignUpApi 存在しない仮登録も失敗として数える", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		signUpRequest(c)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		// 仮登録のメールアドレスが分からないため、IPアドレスの試行回数に数える
+		mockAttemptLimiter.EXPECT().Check(ipKey).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().Fail(ipKey).Return(utils.AttemptStatus{Failures: 1}, nil)
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisGet(pendingSignUpKey(redisKey)).
+			Return("", fmt.Errorf("キーが存在しません: %s", pendingSignUpKey(redisKey)))
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mockRedisService,
+			AttemptLimiter:       mockAttemptLimiter,
+		}
+		fetcher.PostSignUpApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var responseBody utils.ErrorMessageResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, "メール認証コードが間違っています。", responseBody.Result)
+	})
+}
+
+func TestAccountUnlockApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	token := "8df939de-5a97-4f20-b41b-9ac355c16e36"
+
+	t.Run("AccountUnlockApi バリデーション トークン不正", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/account_unlock?token=invalid", nil)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		fetcher := apiSignDataFetcher{
+			RedisService:   mock_config.NewMockRedisService(ctrl),
+			AttemptLimiter: mock_limiter.NewMockAttemptLimiter(ctrl),
+		}
+		fetcher.AccountUnlockApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var responseBody utils.ErrorValidationResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, []utils.ErrorMessages{
+			{Field: "token", Message: "トークンの形式が間違っています。"},
+		}, responseBody.Result)
+	})
+
+	t.Run("AccountUnlockApi 無効なトークン", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/account_unlock?token="+token, nil)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisGet("account_unlock:"+token).
+			Return("", fmt.Errorf("キーが存在しません: account_unlock:%s", token))
+
+		fetcher := apiSignDataFetcher{
+			RedisService:   mockRedisService,
+			AttemptLimiter: mock_limiter.NewMockAttemptLimiter(ctrl),
+		}
+		fetcher.AccountUnlockApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var responseBody utils.ErrorMessageResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, "ロック解除のリンクが無効又は有効期限切れです。", responseBody.Result)
+	})
+
+	t.Run("AccountUnlockApi 成功", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/account_unlock?token="+token, nil)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGet("account_unlock:"+token).Return("test@example.com", nil)
+		mockRedisService.EXPECT().RedisDel("account_unlock:" + token).Return(nil)
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Reset(utils.SignInAccountAttemptKey("test@example.com")).Return(nil)
+
+		fetcher := apiSignDataFetcher{
+			RedisService:   mockRedisService,
+			AttemptLimiter: mockAttemptLimiter,
+		}
+		fetcher.AccountUnlockApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var responseBody utils.ResponseData[string]
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, "アカウントのロックを解除しました。", responseBody.Result)
+	})
+}
+
+func TestAdminAccountUnlockApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("AdminAccountUnlockApi バリデーション メールアドレス不正", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/api/admin/account_unlock", bytes.NewBufferString(`{"user_email": "test"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		fetcher := apiSignDataFetcher{
+			AttemptLimiter: mock_limiter.NewMockAttemptLimiter(ctrl),
+		}
+		fetcher.AdminAccountUnlockApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("AdminAccountUnlockApi ロック解除に失敗", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/api/admin/account_unlock", bytes.NewBufferString(`{"user_email": "test@example.com"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().
+			Reset(utils.SignInAccountAttemptKey("test@example.com")).
+			Return(fmt.Errorf("削除エラー"))
+
+		fetcher := apiSignDataFetcher{
+			AttemptLimiter: mockAttemptLimiter,
+		}
+		fetcher.AdminAccountUnlockApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("AdminAccountUnlockApi 成功", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/api/admin/account_unlock", bytes.NewBufferString(`{"user_email": "Test@example.com"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// メールアドレスの大文字・小文字は区別しない
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Reset(utils.SignInAccountAttemptKey("test@example.com")).Return(nil)
+
+		var logs []models.AuditLogData
+		auditPatches := patchInsertAuditLog(&logs, nil)
+		defer auditPatches.Reset()
+
+		fetcher := apiSignDataFetcher{
+			AttemptLimiter: mockAttemptLimiter,
+		}
+		fetcher.AdminAccountUnlockApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, logs, 1)
+		assert.Equal(t, auditActionAccountUnlock, logs[0].Action)
+	})
+}
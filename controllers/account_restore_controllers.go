@@ -0,0 +1,191 @@
+// controllers/account_restore_controllers.go
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"server/common"
+	"server/config"
+	"server/models"
+	"server/templates"
+	"server/utils"
+	"server/validation"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	accountDeactivatedMessage = "このアカウントは退会手続き中です。退会時のメールに記載したリンクから復元してください。"
+	accountRestoreLinkMessage = "アカウント復元のリンクが無効か有効期限が切れています。"
+	// 削除予定日時を過ぎたユーザーを削除する間隔
+	accountPurgeInterval = time.Hour
+)
+
+// accountRestoreKey は復元用トークンのハッシュからユーザーIDを引くRedisのキー
+func accountRestoreKey(tokenHash string) string {
+	return fmt.Sprintf("account_restore:%s", tokenHash)
+}
+
+// accountPurgeAt は現在日時から退会したユーザーの削除予定日時を返す
+func accountPurgeAt(now time.Time) time.Time {
+	return now.AddDate(0, 0, utils.AccountRestoreDays)
+}
+
+// issueAccountRestoreToken はアカウント復元用のトークンを発行し、ハッシュのみをRedisに保存する
+// トークンは削除予定日時まで有効とする
+//
+// 引数:
+//   - redisService: Redisの操作
+//   - UserId: 退会するユーザーID
+//   - PurgeAt: 削除予定日時
+//
+// 戻り値:
+//
+//	戻り値1: 復元用のトークン
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func issueAccountRestoreToken(redisService config.RedisService, UserId int, PurgeAt time.Time) (string, error) {
+	token, err := utils.GenerateLinkToken()
+	if err != nil {
+		return "", err
+	}
+	if err := redisService.RedisSet(
+		accountRestoreKey(utils.HashLinkToken(token)),
+		common.AnyToStr(UserId),
+		time.Until(PurgeAt),
+	); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// accountRestoreLink は退会時のメールに記載する復元リンクを返す
+func accountRestoreLink(token string) string {
+	return fmt.Sprintf("%saccount_restore?token=%s", utils.GetBaseURL(), token)
+}
+
+// AccountRestoreApi は退会時のメールに記載した復元リンクから退会中のアカウントを復元するAPI
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (af *apiSignDataFetcher) AccountRestoreApi(c *gin.Context) {
+	token := c.Query("token")
+
+	validator := validation.RequestAccountRestoreData{
+		Token: token,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// 取得と削除を同時に行い、同じリンクで2回以上復元できないようにする
+	value, err := af.RedisService.RedisGetDel(accountRestoreKey(utils.HashLinkToken(token)))
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: accountRestoreLinkMessage,
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+	userId, err := af.CommonFetcher.StrToInt(value)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: accountRestoreLinkMessage,
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	dbFetcher, _, _ := models.NewSignDataFetcher(
+		config.GetDataBaseSource(),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+	)
+	if err := dbFetcher.RestoreSignIn(userId); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: accountRestoreLinkMessage,
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "アカウントを復元しました。再度サインインしてください。",
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// purgeDeactivatedAccounts は削除予定日時を過ぎたユーザーを削除し、削除完了のメールを送信する
+// メールの送信に失敗しても削除は取り消さない
+//
+// 引数:
+//   - emailTemplateService: メールテンプレート
+//   - utilsFetcher: メール送信
+//   - now: 基準日時
+//
+// 戻り値:
+//
+//	戻り値1: 削除したユーザー
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func purgeDeactivatedAccounts(
+	emailTemplateService templates.EmailTemplateService,
+	utilsFetcher utils.UtilsFetcher,
+	now time.Time,
+) ([]models.DeactivatedUserData, error) {
+	dbFetcher, _, _ := models.NewSignDataFetcher(
+		config.GetDataBaseSource(),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+	)
+	users, err := dbFetcher.PurgeDeactivatedUsers(now)
+	if err != nil {
+		return nil, err
+	}
+
+	dateTime := utilsFetcher.DateTimeStr(now, "2006年01月02日 15:04")
+	for _, user := range users {
+		subject, body, err := emailTemplateService.DeleteSignInTemplate(user.UserName, user.UserEmail, dateTime)
+		if err != nil {
+			log.Printf("メールテンプレート生成エラー(削除) user_id=%d: %v", user.UserId, err)
+			continue
+		}
+		if err := utilsFetcher.SendMail(user.UserEmail, subject, body, true); err != nil {
+			log.Printf("メール送信エラー(削除) user_id=%d: %v", user.UserId, err)
+		}
+	}
+	return users, nil
+}
+
+// StartAccountPurgeJob は削除予定日時を過ぎたユーザーを定期的に削除するジョブを開始する
+//
+// 引数:
+//   - emailTemplateService: メールテンプレート
+//   - utilsFetcher: メール送信
+//
+
+func StartAccountPurgeJob(emailTemplateService templates.EmailTemplateService, utilsFetcher utils.UtilsFetcher) {
+	go func() {
+		ticker := time.NewTicker(accountPurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			users, err := purgeDeactivatedAccounts(emailTemplateService, utilsFetcher, time.Now())
+			if err != nil {
+				log.Printf("退会ユーザーの削除に失敗しました: %v", err)
+			} else if len(users) > 0 {
+				log.Printf("退会ユーザーを削除しました: %d件", len(users))
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,280 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"server/common"
+	mock_config "server/mock/config"
+	mock_templates "server/mock/templates"
+	mock_utils "server/mock/utils"
+	"server/models"
+	"server/utils"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// expectIssueAccountRestoreToken は退会時の復元用トークンの保存を期待するRedisのモックを返す
+func expectIssueAccountRestoreToken(ctrl *gomock.Controller, userId int) *mock_config.MockRedisService {
+	mockRedisService := mock_config.NewMockRedisService(ctrl)
+	mockRedisService.EXPECT().
+		RedisSet(gomock.Any(), common.AnyToStr(userId), gomock.Any()).
+		Return(nil)
+	return mockRedisService
+}
+
+func TestIssueAccountRestoreToken(t *testing.T) {
+
+	t.Run("success issueAccountRestoreToken", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var savedKey string
+		var savedDuration time.Duration
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisSet(gomock.Any(), "1", gomock.Any()).
+			DoAndReturn(func(key string, value interface{}, duration time.Duration) error {
+				savedKey, savedDuration = key, duration
+				return nil
+			})
+
+		purgeAt := accountPurgeAt(time.Now())
+		token, err := issueAccountRestoreToken(mockRedisService, 1, purgeAt)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token)
+		// 平文のトークンは保存しない
+		assert.Equal(t, accountRestoreKey(utils.HashLinkToken(token)), savedKey)
+		assert.NotContains(t, savedKey, token)
+		// 削除予定日時まで有効
+		assert.InDelta(t, float64(utils.AccountRestoreDays*24*time.Hour), float64(savedDuration), float64(time.Minute))
+	})
+
+	t.Run("error issueAccountRestoreToken 保存エラー", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisSet(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(errors.New("Redisエラー"))
+
+		_, err := issueAccountRestoreToken(mockRedisService, 1, accountPurgeAt(time.Now()))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestAccountRestoreApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	restoreRequest := func(w *httptest.ResponseRecorder, token string) *gin.Context {
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/account_restore?token="+token, nil)
+		return c
+	}
+
+	t.Run("success AccountRestoreApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := restoreRequest(w, "restore-token")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisGetDel(accountRestoreKey(utils.HashLinkToken("restore-token"))).
+			Return("1", nil)
+
+		restoredUserId := 0
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"RestoreSignIn",
+			func(_ *models.SignDataFetcher, UserId int) error {
+				restoredUserId = UserId
+				return nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiSignDataFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+			RedisService:  mockRedisService,
+		}
+		fetcher.AccountRestoreApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 1, restoredUserId)
+		assertErrorMessage(t, w, "アカウントを復元しました。再度サインインしてください。")
+	})
+
+	t.Run("error AccountRestoreApi トークン必須", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := restoreRequest(w, "")
+
+		fetcher := apiSignDataFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.AccountRestoreApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("error AccountRestoreApi 無効なリンク", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := restoreRequest(w, "restore-token")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisGetDel(gomock.Any()).
+			Return("", errors.New("redis: nil"))
+
+		fetcher := apiSignDataFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+			RedisService:  mockRedisService,
+		}
+		fetcher.AccountRestoreApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assertErrorMessage(t, w, accountRestoreLinkMessage)
+	})
+
+	t.Run("error AccountRestoreApi 削除予定日時を過ぎている", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := restoreRequest(w, "restore-token")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisGetDel(gomock.Any()).
+			Return("1", nil)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"RestoreSignIn",
+			func(_ *models.SignDataFetcher, UserId int) error {
+				return errors.New("復元できるユーザーが存在しません。")
+			})
+		defer patches.Reset()
+
+		fetcher := apiSignDataFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+			RedisService:  mockRedisService,
+		}
+		fetcher.AccountRestoreApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assertErrorMessage(t, w, accountRestoreLinkMessage)
+	})
+}
+
+func TestSignInRoleDeactivated(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	patches := patchGetUserRole(models.UserRoleData{Role: "user", Deactivated: true}, nil)
+	defer patches.Reset()
+
+	_, ok := signInRole(c, 1)
+
+	// 退会中のユーザーにはトークンを発行しない
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assertErrorMessage(t, w, accountDeactivatedMessage)
+}
+
+func TestPurgeDeactivatedAccounts(t *testing.T) {
+
+	now := time.Date(2025, 1, 6, 20, 0, 0, 0, time.Local)
+
+	t.Run("success purgeDeactivatedAccounts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		users := []models.DeactivatedUserData{
+			{UserId: 1, UserEmail: "test@example.com", UserName: "test"},
+			{UserId: 2, UserEmail: "test_2@example.com", UserName: "test_2"},
+		}
+		var purgedAt time.Time
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"PurgeDeactivatedUsers",
+			func(_ *models.SignDataFetcher, Now time.Time) ([]models.DeactivatedUserData, error) {
+				purgedAt = Now
+				return users, nil
+			})
+		defer patches.Reset()
+
+		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
+		mockEmailTemplateService.EXPECT().
+			DeleteSignInTemplate(gomock.Any(), gomock.Any(), "2025年01月06日 20:00").
+			DoAndReturn(func(Name, UserEmail, DateTime string) (string, string, error) {
+				return "件名", UserEmail, nil
+			}).
+			Times(2)
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().
+			DateTimeStr(now, gomock.Any()).
+			Return("2025年01月06日 20:00")
+		// 1件目のメール送信に失敗しても残りのユーザーに送信する
+		mockUtilsFetcher.EXPECT().
+			SendMail("test@example.com", "件名", "test@example.com", true).
+			Return(errors.New("メール送信エラー"))
+		mockUtilsFetcher.EXPECT().
+			SendMail("test_2@example.com", "件名", "test_2@example.com", true).
+			Return(nil)
+
+		result, err := purgeDeactivatedAccounts(mockEmailTemplateService, mockUtilsFetcher, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, users, result)
+		assert.Equal(t, now, purgedAt)
+	})
+
+	t.Run("error purgeDeactivatedAccounts 削除エラー", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"PurgeDeactivatedUsers",
+			func(_ *models.SignDataFetcher, Now time.Time) ([]models.DeactivatedUserData, error) {
+				return nil, errors.New("ユーザー削除に失敗しました")
+			})
+		defer patches.Reset()
+
+		// 削除に失敗した場合はメールを送信しない
+		result, err := purgeDeactivatedAccounts(
+			mock_templates.NewMockEmailTemplateService(ctrl),
+			mock_utils.NewMockUtilsFetcher(ctrl),
+			now,
+		)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestAccountRestoreLink(t *testing.T) {
+	link := accountRestoreLink("restore-token")
+
+	assert.True(t, strings.HasPrefix(link, utils.GetBaseURL()))
+	assert.True(t, strings.HasSuffix(link, "account_restore?token=restore-token"))
+}
@@ -0,0 +1,592 @@
+// controllers/admin_controllers.go
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"server/common"
+	"server/config"
+	"server/models"
+	"server/templates"
+	"server/utils"
+	"server/validation"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	AdminManagementFetcher interface {
+		GetAdminUsersApi(c *gin.Context)
+		DisableUserApi(c *gin.Context)
+		EnableUserApi(c *gin.Context)
+		ForcePasswordResetApi(c *gin.Context)
+		GetSignUpStatsApi(c *gin.Context)
+		GetAuditLogApi(c *gin.Context)
+	}
+
+	AdminUsersResult struct {
+		Total int                    `json:"total"`
+		Users []models.AdminUserData `json:"users"`
+	}
+
+	AuditLogResult struct {
+		Total int                   `json:"total"`
+		Logs  []models.AuditLogData `json:"logs"`
+	}
+
+	apiAdminManagementFetcher struct {
+		CommonFetcher        common.CommonFetcher
+		UtilsFetcher         utils.UtilsFetcher
+		EmailTemplateService templates.EmailTemplateService
+		RedisService         config.RedisService
+	}
+)
+
+// 管理者用APIの操作履歴の操作名
+const (
+	auditActionUserSearch    = "user_search"
+	auditActionUserDisable   = "user_disable"
+	auditActionUserEnable    = "user_enable"
+	auditActionPasswordReset = "password_reset"
+	auditActionSignUpStats   = "sign_up_stats"
+	auditActionAuditLogView  = "audit_log_view"
+	auditActionAccountUnlock = "account_unlock"
+)
+
+// 一覧の取得件数の既定値
+const adminDefaultLimit = 20
+
+// 登録数の集計期間を省略した場合の日数
+const adminDefaultStatsDays = 30
+
+func NewAdminManagementFetcher(
+	CommonFetcher common.CommonFetcher,
+	UtilsFetcher utils.UtilsFetcher,
+	EmailTemplateService templates.EmailTemplateService,
+	RedisService config.RedisService,
+) AdminManagementFetcher {
+	return &apiAdminManagementFetcher{
+		CommonFetcher:        CommonFetcher,
+		UtilsFetcher:         UtilsFetcher,
+		EmailTemplateService: EmailTemplateService,
+		RedisService:         RedisService,
+	}
+}
+
+// signInRole はトークンを発行するユーザーのロールを返す
+// 管理者に無効化されたユーザー・退会中のユーザーにはトークンを発行しない
+//
+// 引数:
+//   - c: Ginコンテキスト
+//   - userId: トークンを発行するユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: ユーザーのロール
+//	戻り値2: トークンを発行できる場合はtrue(falseの場合はレスポンス済み)
+//
+
+func signInRole(c *gin.Context, userId int) (string, bool) {
+	dbFetcher, _, _ := models.NewAdminDataFetcher(config.GetDataBaseSource())
+	result, err := dbFetcher.GetUserRole(userId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "ユーザーのロールの取得に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return "", false
+	}
+	if result.Disabled {
+		response := utils.ErrorMessageResponse{
+			Result: "このアカウントは無効化されています。",
+		}
+		c.JSON(http.StatusForbidden, response)
+		return "", false
+	}
+	if result.Deactivated {
+		response := utils.ErrorMessageResponse{
+			Result: accountDeactivatedMessage,
+		}
+		c.JSON(http.StatusForbidden, response)
+		return "", false
+	}
+	return result.Role, true
+}
+
+// recordAdminAudit は管理者用APIの操作履歴を登録する
+// 登録に失敗した場合は500を返す
+//
+// 引数:
+//   - c: Ginコンテキスト
+//   - action: 操作名
+//   - targetUserId: 操作対象のユーザーID(特定のユーザーに対する操作でない場合はnil)
+//   - detail: 操作の内容
+//
+// 戻り値:
+//
+//	戻り値1: 登録できた場合はtrue(falseの場合はレスポンス済み)
+//
+
+func recordAdminAudit(c *gin.Context, action string, targetUserId *int, detail gin.H) bool {
+	actorUserId, _ := c.Get(utils.UserId)
+	actorId, _ := actorUserId.(int)
+	detailJson, _ := json.Marshal(detail)
+
+	dbFetcher, _, _ := models.NewAdminDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.InsertAuditLog(models.AuditLogData{
+		AuditId:      uuid.New().String(),
+		ActorUserId:  actorId,
+		ActorRole:    c.GetString(utils.Role),
+		Action:       action,
+		TargetUserId: targetUserId,
+		Detail:       string(detailJson),
+		IpAddress:    c.ClientIP(),
+		CreatedAt:    time.Now(),
+	}); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "操作履歴の記録に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return false
+	}
+	return true
+}
+
+// adminPage は一覧の取得件数と取得開始位置を返す(省略した場合は既定値)
+func (am *apiAdminManagementFetcher) adminPage(limitPrams, offsetPrams string) (int, int) {
+	limit := adminDefaultLimit
+	if limitPrams != "" {
+		limit, _ = am.CommonFetcher.StrToInt(limitPrams)
+	}
+	offset := 0
+	if offsetPrams != "" {
+		offset, _ = am.CommonFetcher.StrToInt(offsetPrams)
+	}
+	return limit, offset
+}
+
+// adminTargetUser はパスパラメータから操作対象のユーザーIDを取得する
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+// 戻り値:
+//
+//	戻り値1: 操作対象のユーザーID
+//	戻り値2: 取得できた場合はtrue(falseの場合はレスポンス済み)
+//
+
+func (am *apiAdminManagementFetcher) adminTargetUser(c *gin.Context) (int, bool) {
+	userIdPrams := c.Param("user_id")
+	validator := validation.RequestAdminUserData{
+		UserId: userIdPrams,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return 0, false
+	}
+
+	userId, _ := am.CommonFetcher.StrToInt(userIdPrams)
+	return userId, true
+}
+
+// revokeUserSignIn はユーザーの全てのトークンとセッションを失効させる
+//
+// 引数:
+//   - c: Ginコンテキスト
+//   - userId: 対象のユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: 失効できた場合はtrue(falseの場合はレスポンス済み)
+//
+
+func (am *apiAdminManagementFetcher) revokeUserSignIn(c *gin.Context, userId int) bool {
+	if err := am.UtilsFetcher.RevokeUserTokens(userId); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "トークンの失効に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return false
+	}
+
+	sessionFetcher, _, _ := models.NewSessionDataFetcher(config.GetDataBaseSource())
+	if _, err := sessionFetcher.RevokeOtherSessions(userId, ""); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "セッションの失効に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return false
+	}
+	return true
+}
+
+// GetAdminUsersApi はユーザーを検索するAPI
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (am *apiAdminManagementFetcher) GetAdminUsersApi(c *gin.Context) {
+	validator := validation.RequestAdminUsersData{
+		Search: c.Query("search"),
+		Role:   c.Query("role"),
+		Limit:  c.Query("limit"),
+		Offset: c.Query("offset"),
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	limit, offset := am.adminPage(validator.Limit, validator.Offset)
+	dbFetcher, _, _ := models.NewAdminDataFetcher(config.GetDataBaseSource())
+	users, total, err := dbFetcher.GetAdminUsers(models.RequestAdminUsersData{
+		Search: validator.Search,
+		Role:   validator.Role,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if !recordAdminAudit(c, auditActionUserSearch, nil, gin.H{
+		"search": validator.Search,
+		"role":   validator.Role,
+		"limit":  limit,
+		"offset": offset,
+	}) {
+		return
+	}
+
+	response := utils.ResponseData[AdminUsersResult]{
+		RecodeRows: len(users),
+		Result: AdminUsersResult{
+			Total: total,
+			Users: users,
+		},
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DisableUserApi はユーザーを無効化し、全ての端末からサインアウトさせるAPI
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (am *apiAdminManagementFetcher) DisableUserApi(c *gin.Context) {
+	userId, ok := am.adminTargetUser(c)
+	if !ok {
+		return
+	}
+
+	// 管理者がいなくなることを防ぐため、自分自身は無効化できない
+	if isSignInUser(c, userId) {
+		response := utils.ErrorMessageResponse{
+			Result: "自分自身のアカウントは無効化できません。",
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	dbFetcher, _, _ := models.NewAdminDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.SetUserDisabled(userId, true); errors.Is(err, models.ErrUserNotFound) {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	} else if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if !am.revokeUserSignIn(c, userId) {
+		return
+	}
+
+	if !recordAdminAudit(c, auditActionUserDisable, &userId, gin.H{}) {
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "ユーザーを無効化しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// EnableUserApi は無効化したユーザーを有効に戻すAPI
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (am *apiAdminManagementFetcher) EnableUserApi(c *gin.Context) {
+	userId, ok := am.adminTargetUser(c)
+	if !ok {
+		return
+	}
+
+	dbFetcher, _, _ := models.NewAdminDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.SetUserDisabled(userId, false); errors.Is(err, models.ErrUserNotFound) {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	} else if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if !recordAdminAudit(c, auditActionUserEnable, &userId, gin.H{}) {
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "ユーザーを有効化しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ForcePasswordResetApi はユーザーのパスワードを無効にし、パスワード再設定のメールを送信するAPI
+// 現在のパスワードではサインインできなくなり、全ての端末からサインアウトさせる
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (am *apiAdminManagementFetcher) ForcePasswordResetApi(c *gin.Context) {
+	userId, ok := am.adminTargetUser(c)
+	if !ok {
+		return
+	}
+
+	accountFetcher, _, _ := models.NewSignDataFetcher(
+		config.GetDataBaseSource(),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+	)
+	account, err := accountFetcher.GetUserAccount(userId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+	if !account.PasswordAccount {
+		response := utils.ErrorMessageResponse{
+			Result: "外部認証で登録したユーザーのため、パスワードを再設定できません。",
+		}
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+
+	// 誰にも知らせないランダムな値に置き換え、現在のパスワードを無効にする
+	unusablePassword, err := utils.GenerateLinkToken()
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "パスワードの無効化に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	editFetcher, _, _ := models.NewSignDataFetcher(
+		config.GetDataBaseSource(),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+	)
+	// 置き換えた値はユーザーが設定したパスワードではないため、履歴には登録しない
+	if err := editFetcher.InvalidatePassword(userId, unusablePassword); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "パスワードの無効化に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if !am.revokeUserSignIn(c, userId) {
+		return
+	}
+
+	tokenId, err := issuePasswordResetToken(am.RedisService, userId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "パスワード再設定トークンの発行に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	var link string = fmt.Sprintf("%ssign_password_reset?token_id=%s", utils.GetBaseURL(), tokenId)
+
+	subject, body, err := am.EmailTemplateService.RegisterEmailCheckNoticeTemplate(
+		link,
+		am.UtilsFetcher.DateTimeStr(time.Now(), "2006年01月02日 15:04"),
+	)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メールテンプレート生成エラー(パスワード再設定): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// メール送信ユーティリティを呼び出し
+	if err := am.UtilsFetcher.SendMail(account.UserEmail, subject, body, true); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メール送信エラー(パスワード再設定): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if !recordAdminAudit(c, auditActionPasswordReset, &userId, gin.H{}) {
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "パスワード再設定のメールを送信しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSignUpStatsApi はユーザー数と期間内の日別の登録数を返すAPI
+// 期間を省略した場合は直近30日間を集計する
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (am *apiAdminManagementFetcher) GetSignUpStatsApi(c *gin.Context) {
+	validator := validation.RequestAdminStatsData{
+		StartDate: c.Query("start_date"),
+		EndDate:   c.Query("end_date"),
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	now := time.Now()
+	endDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if validator.EndDate != "" {
+		endDate, _ = time.ParseInLocation("2006-01-02", validator.EndDate, time.Local)
+	}
+	startDate := endDate.AddDate(0, 0, -(adminDefaultStatsDays - 1))
+	if validator.StartDate != "" {
+		startDate, _ = time.ParseInLocation("2006-01-02", validator.StartDate, time.Local)
+	}
+	if startDate.After(endDate) {
+		response := utils.ErrorMessageResponse{
+			Result: "開始日は終了日以前の日付を指定してください。",
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	dbFetcher, _, _ := models.NewAdminDataFetcher(config.GetDataBaseSource())
+	// 終了日を含めるため翌日の0時までを集計する
+	stats, err := dbFetcher.GetSignUpStats(startDate, endDate.AddDate(0, 0, 1))
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if !recordAdminAudit(c, auditActionSignUpStats, nil, gin.H{
+		"start_date": startDate.Format("2006-01-02"),
+		"end_date":   endDate.Format("2006-01-02"),
+	}) {
+		return
+	}
+
+	response := utils.ResponseData[models.SignUpStatsData]{
+		Result: stats,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetAuditLogApi は管理者用APIの操作履歴を新しい順で返すAPI
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (am *apiAdminManagementFetcher) GetAuditLogApi(c *gin.Context) {
+	validator := validation.RequestAdminAuditLogData{
+		UserId: c.Query("user_id"),
+		Limit:  c.Query("limit"),
+		Offset: c.Query("offset"),
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	targetUserId := 0
+	if validator.UserId != "" {
+		targetUserId, _ = am.CommonFetcher.StrToInt(validator.UserId)
+	}
+	limit, offset := am.adminPage(validator.Limit, validator.Offset)
+
+	dbFetcher, _, _ := models.NewAdminDataFetcher(config.GetDataBaseSource())
+	logs, total, err := dbFetcher.GetAuditLogs(targetUserId, limit, offset)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if !recordAdminAudit(c, auditActionAuditLogView, nil, gin.H{
+		"user_id": targetUserId,
+		"limit":   limit,
+		"offset":  offset,
+	}) {
+		return
+	}
+
+	response := utils.ResponseData[AuditLogResult]{
+		RecodeRows: len(logs),
+		Result: AuditLogResult{
+			Total: total,
+			Logs:  logs,
+		},
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,663 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"server/common"
+	"server/enum"
+	mock_config "server/mock/config"
+	mock_templates "server/mock/templates"
+	mock_utils "server/mock/utils"
+	"server/models"
+	"server/utils"
+	"testing"
+	"time"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// patchGetUserRole はトークン発行時のロールの取得をモック化する
+func patchGetUserRole(data models.UserRoleData, err error) *Patches {
+	return ApplyMethod(
+		reflect.TypeOf(&models.AdminDataFetcher{}),
+		"GetUserRole",
+		func(_ *models.AdminDataFetcher, UserId int) (models.UserRoleData, error) {
+			return data, err
+		})
+}
+
+// patchInsertAuditLog は操作履歴の登録をモック化し、登録した操作履歴を記録する
+func patchInsertAuditLog(logs *[]models.AuditLogData, err error) *Patches {
+	return ApplyMethod(
+		reflect.TypeOf(&models.AdminDataFetcher{}),
+		"InsertAuditLog",
+		func(_ *models.AdminDataFetcher, data models.AuditLogData) error {
+			if logs != nil {
+				*logs = append(*logs, data)
+			}
+			return err
+		})
+}
+
+// patchRevokeOtherSessions は全てのセッションの失効をモック化する
+func patchRevokeOtherSessions(err error) *Patches {
+	return ApplyMethod(
+		reflect.TypeOf(&models.SessionDataFetcher{}),
+		"RevokeOtherSessions",
+		func(_ *models.SessionDataFetcher, UserId int, CurrentSessionId string) ([]string, error) {
+			return []string{}, err
+		})
+}
+
+// adminContext は管理者用APIのミドルウェアを通過した後のコンテキストを作成する
+func adminContext(w *httptest.ResponseRecorder, req *http.Request, userId int, role string) *gin.Context {
+	c := signInContext(w, req, userId, "")
+	c.Set(utils.Role, role)
+	return c
+}
+
+func newTestAdminFetcher(ctrl *gomock.Controller) (*apiAdminManagementFetcher, *mock_utils.MockUtilsFetcher) {
+	mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+	return &apiAdminManagementFetcher{
+		CommonFetcher: common.NewCommonFetcher(),
+		UtilsFetcher:  mockUtilsFetcher,
+	}, mockUtilsFetcher
+}
+
+func TestSignInRole(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success signInRole", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		patches := patchGetUserRole(models.UserRoleData{Role: enum.ROLE_ADMIN}, nil)
+		defer patches.Reset()
+
+		role, ok := signInRole(c, 1)
+
+		assert.True(t, ok)
+		assert.Equal(t, enum.ROLE_ADMIN, role)
+	})
+
+	t.Run("error signInRole 無効化されたユーザー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		patches := patchGetUserRole(models.UserRoleData{Role: enum.ROLE_USER, Disabled: true}, nil)
+		defer patches.Reset()
+
+		_, ok := signInRole(c, 1)
+
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assertErrorMessage(t, w, "このアカウントは無効化されています。")
+	})
+
+	t.Run("error signInRole 取得エラー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		patches := patchGetUserRole(models.UserRoleData{}, models.ErrUserNotFound)
+		defer patches.Reset()
+
+		_, ok := signInRole(c, 1)
+
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assertErrorMessage(t, w, "ユーザーのロールの取得に失敗しました。")
+	})
+}
+
+func TestRecordAdminAudit(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success recordAdminAudit", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("PUT", "/api/admin/users/2/disable", nil)
+		req.RemoteAddr = "192.0.2.1:12345"
+		c := adminContext(w, req, 1, enum.ROLE_ADMIN)
+
+		var logs []models.AuditLogData
+		patches := patchInsertAuditLog(&logs, nil)
+		defer patches.Reset()
+
+		targetUserId := 2
+		ok := recordAdminAudit(c, auditActionUserDisable, &targetUserId, gin.H{"reason": "test"})
+
+		assert.True(t, ok)
+		assert.Len(t, logs, 1)
+		assert.NotEmpty(t, logs[0].AuditId)
+		assert.Equal(t, 1, logs[0].ActorUserId)
+		assert.Equal(t, enum.ROLE_ADMIN, logs[0].ActorRole)
+		assert.Equal(t, auditActionUserDisable, logs[0].Action)
+		assert.Equal(t, &targetUserId, logs[0].TargetUserId)
+		assert.JSONEq(t, `{"reason": "test"}`, logs[0].Detail)
+		assert.Equal(t, "192.0.2.1", logs[0].IpAddress)
+	})
+
+	t.Run("error recordAdminAudit 登録エラー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := adminContext(w, httptest.NewRequest("GET", "/api/admin/users", nil), 1, enum.ROLE_ADMIN)
+
+		patches := patchInsertAuditLog(nil, errors.New("クエリー実行エラー"))
+		defer patches.Reset()
+
+		ok := recordAdminAudit(c, auditActionUserSearch, nil, gin.H{})
+
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assertErrorMessage(t, w, "操作履歴の記録に失敗しました。")
+	})
+}
+
+func TestGetAdminUsersApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success GetAdminUsersApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := adminContext(w, httptest.NewRequest("GET", "/api/admin/users?search=test&role=user&limit=10&offset=20", nil), 1, enum.ROLE_SUPPORT)
+
+		var requested models.RequestAdminUsersData
+		users := []models.AdminUserData{
+			{UserId: 2, UserEmail: "test@example.com", UserName: "test", Role: enum.ROLE_USER, PasswordAccount: true},
+		}
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.AdminDataFetcher{}),
+			"GetAdminUsers",
+			func(_ *models.AdminDataFetcher, data models.RequestAdminUsersData) ([]models.AdminUserData, int, error) {
+				requested = data
+				return users, 21, nil
+			})
+		defer patches.Reset()
+		var logs []models.AuditLogData
+		auditPatches := patchInsertAuditLog(&logs, nil)
+		defer auditPatches.Reset()
+
+		fetcher := NewAdminManagementFetcher(common.NewCommonFetcher(), nil, nil, nil)
+		fetcher.GetAdminUsersApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, models.RequestAdminUsersData{Search: "test", Role: enum.ROLE_USER, Limit: 10, Offset: 20}, requested)
+
+		var responseBody utils.ResponseData[AdminUsersResult]
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, responseBody.RecodeRows)
+		assert.Equal(t, 21, responseBody.Result.Total)
+		assert.Equal(t, users, responseBody.Result.Users)
+
+		// 参照も操作履歴に残す
+		assert.Len(t, logs, 1)
+		assert.Equal(t, auditActionUserSearch, logs[0].Action)
+		assert.Equal(t, enum.ROLE_SUPPORT, logs[0].ActorRole)
+	})
+
+	t.Run("success GetAdminUsersApi 取得件数の既定値", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := adminContext(w, httptest.NewRequest("GET", "/api/admin/users", nil), 1, enum.ROLE_ADMIN)
+
+		var requested models.RequestAdminUsersData
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.AdminDataFetcher{}),
+			"GetAdminUsers",
+			func(_ *models.AdminDataFetcher, data models.RequestAdminUsersData) ([]models.AdminUserData, int, error) {
+				requested = data
+				return []models.AdminUserData{}, 0, nil
+			})
+		defer patches.Reset()
+		auditPatches := patchInsertAuditLog(nil, nil)
+		defer auditPatches.Reset()
+
+		fetcher := NewAdminManagementFetcher(common.NewCommonFetcher(), nil, nil, nil)
+		fetcher.GetAdminUsersApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, models.RequestAdminUsersData{Limit: adminDefaultLimit}, requested)
+	})
+
+	t.Run("error GetAdminUsersApi バリデーションエラー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := adminContext(w, httptest.NewRequest("GET", "/api/admin/users?role=owner&limit=101&offset=-1", nil), 1, enum.ROLE_ADMIN)
+
+		fetcher := NewAdminManagementFetcher(common.NewCommonFetcher(), nil, nil, nil)
+		fetcher.GetAdminUsersApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var responseBody utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []utils.ErrorMessages{
+			{Field: "role", Message: "ロールはuser・admin・supportのいずれかです。"},
+			{Field: "limit", Message: "取得件数は1～100の整数値のみです。"},
+			{Field: "offset", Message: "取得開始位置は0以上の整数値のみです。"},
+		}, responseBody.Result)
+	})
+
+	t.Run("error GetAdminUsersApi 取得エラー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := adminContext(w, httptest.NewRequest("GET", "/api/admin/users", nil), 1, enum.ROLE_ADMIN)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.AdminDataFetcher{}),
+			"GetAdminUsers",
+			func(_ *models.AdminDataFetcher, data models.RequestAdminUsersData) ([]models.AdminUserData, int, error) {
+				return nil, 0, errors.New("クエリー実行エラー")
+			})
+		defer patches.Reset()
+
+		fetcher := NewAdminManagementFetcher(common.NewCommonFetcher(), nil, nil, nil)
+		fetcher.GetAdminUsersApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestDisableUserApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	disableRequest := func(w *httptest.ResponseRecorder, userId string) *gin.Context {
+		c := adminContext(w, httptest.NewRequest("PUT", "/api/admin/users/"+userId+"/disable", nil), 1, enum.ROLE_ADMIN)
+		c.Params = gin.Params{{Key: "user_id", Value: userId}}
+		return c
+	}
+
+	t.Run("success DisableUserApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := disableRequest(w, "2")
+
+		var disabledUserId int
+		var disabled bool
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.AdminDataFetcher{}),
+			"SetUserDisabled",
+			func(_ *models.AdminDataFetcher, UserId int, Disabled bool) error {
+				disabledUserId = UserId
+				disabled = Disabled
+				return nil
+			})
+		defer patches.Reset()
+		sessionPatches := patchRevokeOtherSessions(nil)
+		defer sessionPatches.Reset()
+		var logs []models.AuditLogData
+		auditPatches := patchInsertAuditLog(&logs, nil)
+		defer auditPatches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		fetcher, mockUtilsFetcher := newTestAdminFetcher(ctrl)
+		// 無効化したユーザーのトークンは全て失効させる
+		mockUtilsFetcher.EXPECT().RevokeUserTokens(2).Return(nil)
+
+		fetcher.DisableUserApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 2, disabledUserId)
+		assert.True(t, disabled)
+		assert.Len(t, logs, 1)
+		assert.Equal(t, auditActionUserDisable, logs[0].Action)
+		assert.Equal(t, 2, *logs[0].TargetUserId)
+	})
+
+	t.Run("error DisableUserApi 自分自身", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := disableRequest(w, "1")
+
+		fetcher := NewAdminManagementFetcher(common.NewCommonFetcher(), nil, nil, nil)
+		fetcher.DisableUserApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertErrorMessage(t, w, "自分自身のアカウントは無効化できません。")
+	})
+
+	t.Run("error DisableUserApi ユーザーIDが不正", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := disableRequest(w, "abc")
+
+		fetcher := NewAdminManagementFetcher(common.NewCommonFetcher(), nil, nil, nil)
+		fetcher.DisableUserApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("error DisableUserApi 存在しないユーザー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := disableRequest(w, "2")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.AdminDataFetcher{}),
+			"SetUserDisabled",
+			func(_ *models.AdminDataFetcher, UserId int, Disabled bool) error {
+				return models.ErrUserNotFound
+			})
+		defer patches.Reset()
+
+		fetcher := NewAdminManagementFetcher(common.NewCommonFetcher(), nil, nil, nil)
+		fetcher.DisableUserApi(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("error DisableUserApi トークンの失効エラー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := disableRequest(w, "2")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.AdminDataFetcher{}),
+			"SetUserDisabled",
+			func(_ *models.AdminDataFetcher, UserId int, Disabled bool) error {
+				return nil
+			})
+		defer patches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		fetcher, mockUtilsFetcher := newTestAdminFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().RevokeUserTokens(2).Return(errors.New("Redisエラー"))
+
+		fetcher.DisableUserApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assertErrorMessage(t, w, "トークンの失効に失敗しました。")
+	})
+}
+
+func TestEnableUserApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success EnableUserApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := adminContext(w, httptest.NewRequest("PUT", "/api/admin/users/2/enable", nil), 1, enum.ROLE_ADMIN)
+		c.Params = gin.Params{{Key: "user_id", Value: "2"}}
+
+		var disabled = true
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.AdminDataFetcher{}),
+			"SetUserDisabled",
+			func(_ *models.AdminDataFetcher, UserId int, Disabled bool) error {
+				disabled = Disabled
+				return nil
+			})
+		defer patches.Reset()
+		var logs []models.AuditLogData
+		auditPatches := patchInsertAuditLog(&logs, nil)
+		defer auditPatches.Reset()
+
+		fetcher := NewAdminManagementFetcher(common.NewCommonFetcher(), nil, nil, nil)
+		fetcher.EnableUserApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.False(t, disabled)
+		assert.Len(t, logs, 1)
+		assert.Equal(t, auditActionUserEnable, logs[0].Action)
+	})
+
+	t.Run("error EnableUserApi 操作履歴の登録エラー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := adminContext(w, httptest.NewRequest("PUT", "/api/admin/users/2/enable", nil), 1, enum.ROLE_ADMIN)
+		c.Params = gin.Params{{Key: "user_id", Value: "2"}}
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.AdminDataFetcher{}),
+			"SetUserDisabled",
+			func(_ *models.AdminDataFetcher, UserId int, Disabled bool) error {
+				return nil
+			})
+		defer patches.Reset()
+		auditPatches := patchInsertAuditLog(nil, errors.New("クエリー実行エラー"))
+		defer auditPatches.Reset()
+
+		fetcher := NewAdminManagementFetcher(common.NewCommonFetcher(), nil, nil, nil)
+		fetcher.EnableUserApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assertErrorMessage(t, w, "操作履歴の記録に失敗しました。")
+	})
+}
+
+func TestForcePasswordResetApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	resetRequest := func(w *httptest.ResponseRecorder) *gin.Context {
+		c := adminContext(w, httptest.NewRequest("POST", "/api/admin/users/2/password_reset", nil), 1, enum.ROLE_ADMIN)
+		c.Params = gin.Params{{Key: "user_id", Value: "2"}}
+		return c
+	}
+
+	patchGetUserAccount := func(data models.UserAccountData, err error) *Patches {
+		return ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"GetUserAccount",
+			func(_ *models.SignDataFetcher, UserId int) (models.UserAccountData, error) {
+				return data, err
+			})
+	}
+
+	t.Run("success ForcePasswordResetApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := resetRequest(w)
+
+		var unusablePassword string
+		patches := patchGetUserAccount(models.UserAccountData{UserId: 2, UserEmail: "test@example.com", PasswordAccount: true}, nil)
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"InvalidatePassword",
+			func(_ *models.SignDataFetcher, UserId int, password string) error {
+				unusablePassword = password
+				return nil
+			})
+		defer patches.Reset()
+		sessionPatches := patchRevokeOtherSessions(nil)
+		defer sessionPatches.Reset()
+		var logs []models.AuditLogData
+		auditPatches := patchInsertAuditLog(&logs, nil)
+		defer auditPatches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGet(passwordResetUserKey(2)).Return("", errors.New("redis: nil"))
+		mockRedisService.EXPECT().RedisSet(gomock.Any(), "2", utils.PasswordResetTokenMinutes*time.Minute).Return(nil)
+		mockRedisService.EXPECT().RedisSet(passwordResetUserKey(2), gomock.Any(), utils.PasswordResetTokenMinutes*time.Minute).Return(nil)
+
+		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
+		mockEmailTemplateService.EXPECT().
+			RegisterEmailCheckNoticeTemplate(gomock.Any(), gomock.Any()).
+			Return("件名", "本文", nil)
+
+		fetcher, mockUtilsFetcher := newTestAdminFetcher(ctrl)
+		fetcher.RedisService = mockRedisService
+		fetcher.EmailTemplateService = mockEmailTemplateService
+		mockUtilsFetcher.EXPECT().RevokeUserTokens(2).Return(nil)
+		mockUtilsFetcher.EXPECT().DateTimeStr(gomock.Any(), gomock.Any()).Return("2024年01月01日 00:00")
+		mockUtilsFetcher.EXPECT().SendMail("test@example.com", "件名", "本文", true).Return(nil)
+
+		fetcher.ForcePasswordResetApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		// 現在のパスワードはランダムな値に置き換える
+		assert.NotEmpty(t, unusablePassword)
+		assert.Len(t, logs, 1)
+		assert.Equal(t, auditActionPasswordReset, logs[0].Action)
+	})
+
+	t.Run("error ForcePasswordResetApi 外部認証で登録したユーザー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := resetRequest(w)
+
+		patches := patchGetUserAccount(models.UserAccountData{UserId: 2, UserEmail: "test@gmail.com"}, nil)
+		defer patches.Reset()
+
+		fetcher := NewAdminManagementFetcher(common.NewCommonFetcher(), nil, nil, nil)
+		fetcher.ForcePasswordResetApi(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("error ForcePasswordResetApi 存在しないユーザー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := resetRequest(w)
+
+		patches := patchGetUserAccount(models.UserAccountData{}, errors.New("登録ユーザーが存在しません"))
+		defer patches.Reset()
+
+		fetcher := NewAdminManagementFetcher(common.NewCommonFetcher(), nil, nil, nil)
+		fetcher.ForcePasswordResetApi(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestGetSignUpStatsApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success GetSignUpStatsApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := adminContext(w, httptest.NewRequest("GET", "/api/admin/stats/sign_up?start_date=2024-04-01&end_date=2024-04-30", nil), 1, enum.ROLE_SUPPORT)
+
+		var from, to time.Time
+		stats := models.SignUpStatsData{
+			TotalUsers:    10,
+			DisabledUsers: 1,
+			SignUps:       3,
+			Daily:         []models.DailySignUpsData{{Date: "2024-04-01", Count: 3}},
+		}
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.AdminDataFetcher{}),
+			"GetSignUpStats",
+			func(_ *models.AdminDataFetcher, From, To time.Time) (models.SignUpStatsData, error) {
+				from, to = From, To
+				return stats, nil
+			})
+		defer patches.Reset()
+		var logs []models.AuditLogData
+		auditPatches := patchInsertAuditLog(&logs, nil)
+		defer auditPatches.Reset()
+
+		fetcher := NewAdminManagementFetcher(common.NewCommonFetcher(), nil, nil, nil)
+		fetcher.GetSignUpStatsApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		// 終了日を含めて集計する
+		assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.Local), from)
+		assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local), to)
+
+		var responseBody utils.ResponseData[models.SignUpStatsData]
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+		assert.Equal(t, stats, responseBody.Result)
+		assert.Len(t, logs, 1)
+		assert.Equal(t, auditActionSignUpStats, logs[0].Action)
+	})
+
+	t.Run("success GetSignUpStatsApi 期間の既定値", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := adminContext(w, httptest.NewRequest("GET", "/api/admin/stats/sign_up", nil), 1, enum.ROLE_ADMIN)
+
+		var from, to time.Time
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.AdminDataFetcher{}),
+			"GetSignUpStats",
+			func(_ *models.AdminDataFetcher, From, To time.Time) (models.SignUpStatsData, error) {
+				from, to = From, To
+				return models.SignUpStatsData{}, nil
+			})
+		defer patches.Reset()
+		auditPatches := patchInsertAuditLog(nil, nil)
+		defer auditPatches.Reset()
+
+		fetcher := NewAdminManagementFetcher(common.NewCommonFetcher(), nil, nil, nil)
+		fetcher.GetSignUpStatsApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, adminDefaultStatsDays, int(to.Sub(from).Hours()/24+0.5))
+		assert.True(t, to.After(time.Now()))
+	})
+
+	t.Run("error GetSignUpStatsApi 開始日が終了日より後", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := adminContext(w, httptest.NewRequest("GET", "/api/admin/stats/sign_up?start_date=2024-05-01&end_date=2024-04-30", nil), 1, enum.ROLE_ADMIN)
+
+		fetcher := NewAdminManagementFetcher(common.NewCommonFetcher(), nil, nil, nil)
+		fetcher.GetSignUpStatsApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertErrorMessage(t, w, "開始日は終了日以前の日付を指定してください。")
+	})
+
+	t.Run("error GetSignUpStatsApi 日付の形式", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := adminContext(w, httptest.NewRequest("GET", "/api/admin/stats/sign_up?start_date=2024/04/01", nil), 1, enum.ROLE_ADMIN)
+
+		fetcher := NewAdminManagementFetcher(common.NewCommonFetcher(), nil, nil, nil)
+		fetcher.GetSignUpStatsApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetAuditLogApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success GetAuditLogApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := adminContext(w, httptest.NewRequest("GET", "/api/admin/audit_log?user_id=2&limit=5", nil), 1, enum.ROLE_ADMIN)
+
+		var targetUserId, limit, offset int
+		targetId := 2
+		auditLogs := []models.AuditLogData{
+			{AuditId: "b3f1c1a0-0000-4000-8000-000000000001", ActorUserId: 1, ActorRole: enum.ROLE_ADMIN, Action: auditActionUserDisable, TargetUserId: &targetId, Detail: "{}"},
+		}
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.AdminDataFetcher{}),
+			"GetAuditLogs",
+			func(_ *models.AdminDataFetcher, TargetUserId, Limit, Offset int) ([]models.AuditLogData, int, error) {
+				targetUserId, limit, offset = TargetUserId, Limit, Offset
+				return auditLogs, 1, nil
+			})
+		defer patches.Reset()
+		var logs []models.AuditLogData
+		auditPatches := patchInsertAuditLog(&logs, nil)
+		defer auditPatches.Reset()
+
+		fetcher := NewAdminManagementFetcher(common.NewCommonFetcher(), nil, nil, nil)
+		fetcher.GetAuditLogApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []int{2, 5, 0}, []int{targetUserId, limit, offset})
+
+		var responseBody utils.ResponseData[AuditLogResult]
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, responseBody.Result.Total)
+		assert.Equal(t, auditLogs[0].AuditId, responseBody.Result.Logs[0].AuditId)
+		assert.Len(t, logs, 1)
+		assert.Equal(t, auditActionAuditLogView, logs[0].Action)
+	})
+
+	t.Run("error GetAuditLogApi バリデーションエラー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := adminContext(w, httptest.NewRequest("GET", "/api/admin/audit_log?user_id=abc", nil), 1, enum.ROLE_ADMIN)
+
+		fetcher := NewAdminManagementFetcher(common.NewCommonFetcher(), nil, nil, nil)
+		fetcher.GetAuditLogApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,603 @@
+// controllers/data_export_controllers.go
+package controllers
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"server/common"
+	"server/config"
+	"server/models"
+	"server/templates"
+	"server/utils"
+	"server/validation"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	DataExportManagementFetcher interface {
+		RequestDataExportApi(c *gin.Context)
+		DownloadDataExportApi(c *gin.Context)
+		ImportDataApi(c *gin.Context)
+	}
+
+	requestDataExportData struct {
+		UserId interface{} `json:"user_id"`
+	}
+
+	// エクスポートファイルに含めるデータ
+	exportArchive struct {
+		Manifest       models.ExportManifestData
+		Profile        models.ExportProfileData
+		Identities     []models.IdentityData
+		Income         []models.IncomeData
+		PriceScenarios []models.PriceScenarioData
+		Loans          []models.LoanData
+		SavingsGoals   []models.SavingsGoalData
+		AuditLogs      []models.ExportAuditLogData
+	}
+
+	apiDataExportManagementFetcher struct {
+		CommonFetcher        common.CommonFetcher
+		UtilsFetcher         utils.UtilsFetcher
+		EmailTemplateService templates.EmailTemplateService
+	}
+)
+
+const (
+	dataExportLinkMessage = "ダウンロードのリンクが無効か有効期限が切れています。"
+	// 作成中のまま中断したとみなすまでの時間
+	dataExportStaleInterval = 30 * time.Minute
+	// 有効期限を過ぎたエクスポートを削除する間隔
+	dataExportCleanupInterval = time.Hour
+)
+
+// startDataExportJob はエクスポートファイルの作成を非同期で開始する
+// テストでは同期実行に差し替える
+var startDataExportJob = func(job func()) { go job() }
+
+func NewDataExportManagementFetcher(
+	CommonFetcher common.CommonFetcher,
+	UtilsFetcher utils.UtilsFetcher,
+	EmailTemplateService templates.EmailTemplateService,
+) DataExportManagementFetcher {
+	return &apiDataExportManagementFetcher{
+		CommonFetcher:        CommonFetcher,
+		UtilsFetcher:         UtilsFetcher,
+		EmailTemplateService: EmailTemplateService,
+	}
+}
+
+// dataExportDir はエクスポートファイルの保存先を返す
+// 環境変数 EXPORT_DIR が未設定の場合は一時ディレクトリに保存する
+func dataExportDir() string {
+	if config.GlobalEnv.ExportDir != "" {
+		return config.GlobalEnv.ExportDir
+	}
+	return filepath.Join(os.TempDir(), "takuwaeru_export")
+}
+
+// dataExportLink はエクスポート完了のメールに記載するダウンロードリンクを返す
+func dataExportLink(token string) string {
+	return fmt.Sprintf("%sdata_export_download?token=%s", utils.GetBaseURL(), token)
+}
+
+// nonNilSlice はJSONで null ではなく [] を出力するため、nilのスライスを空のスライスにする
+func nonNilSlice[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// collectExportArchive はエクスポートするユーザーのデータを集める
+//
+// 引数:
+//   - userId: ユーザーID
+//   - now: エクスポート日時
+//
+// 戻り値:
+//
+//	戻り値1: エクスポートするデータ
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func collectExportArchive(userId int, now time.Time) (exportArchive, error) {
+	archive := exportArchive{
+		Manifest: models.ExportManifestData{
+			Version:    models.ExportArchiveVersion,
+			UserId:     userId,
+			ExportedAt: now,
+		},
+	}
+	var err error
+
+	profileFetcher, _, _ := models.NewExportDataFetcher(config.GetDataBaseSource())
+	if archive.Profile, err = profileFetcher.GetExportProfile(userId); err != nil {
+		return archive, err
+	}
+	identityFetcher, _, _ := models.NewIdentityDataFetcher(config.GetDataBaseSource())
+	if archive.Identities, err = identityFetcher.GetIdentities(userId); err != nil {
+		return archive, err
+	}
+	incomeFetcher, _, _ := models.NewExportDataFetcher(config.GetDataBaseSource())
+	if archive.Income, err = incomeFetcher.GetExportIncome(userId); err != nil {
+		return archive, err
+	}
+	scenarioFetcher, _, _ := models.NewPriceScenarioDataFetcher(config.GetDataBaseSource())
+	if archive.PriceScenarios, err = scenarioFetcher.GetPriceScenarios(userId); err != nil {
+		return archive, err
+	}
+	loanFetcher, _, _ := models.NewLoanDataFetcher(config.GetDataBaseSource())
+	if archive.Loans, err = loanFetcher.GetLoans(userId); err != nil {
+		return archive, err
+	}
+	savingsGoalFetcher, _, _ := models.NewSavingsGoalDataFetcher(config.GetDataBaseSource())
+	if archive.SavingsGoals, err = savingsGoalFetcher.GetSavingsGoals(userId); err != nil {
+		return archive, err
+	}
+	auditLogFetcher, _, _ := models.NewExportDataFetcher(config.GetDataBaseSource())
+	if archive.AuditLogs, err = auditLogFetcher.GetExportAuditLogs(userId); err != nil {
+		return archive, err
+	}
+
+	archive.Identities = nonNilSlice(archive.Identities)
+	archive.Income = nonNilSlice(archive.Income)
+	archive.PriceScenarios = nonNilSlice(archive.PriceScenarios)
+	archive.Loans = nonNilSlice(archive.Loans)
+	archive.SavingsGoals = nonNilSlice(archive.SavingsGoals)
+	archive.AuditLogs = nonNilSlice(archive.AuditLogs)
+
+	return archive, nil
+}
+
+// writeExportJSON はZIPファイルにJSONファイルを追加する
+func writeExportJSON(zw *zip.Writer, name string, v interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeExportCSV はZIPファイルにCSVファイルを追加する
+// Excelで文字化けしないよう、先頭にUTF-8のBOMを付ける
+func writeExportCSV(zw *zip.Writer, name string, header []string, records [][]string) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte("\ufeff")); err != nil {
+		return err
+	}
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write(header); err != nil {
+		return err
+	}
+	if err := csvWriter.WriteAll(records); err != nil {
+		return err
+	}
+	return csvWriter.Error()
+}
+
+// writeExportArchive はエクスポートするデータをZIP形式で書き込む
+// manifest.json にファイル形式のバージョンを記載し、インポート時に確認する
+//
+// 引数:
+//   - w: 書き込み先
+//   - archive: エクスポートするデータ
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func writeExportArchive(w io.Writer, archive exportArchive) error {
+	zw := zip.NewWriter(w)
+
+	jsonFiles := []struct {
+		name  string
+		value interface{}
+	}{
+		{"manifest.json", archive.Manifest},
+		{"profile.json", archive.Profile},
+		{"identities.json", archive.Identities},
+		{"income.json", archive.Income},
+		{"price_scenarios.json", archive.PriceScenarios},
+		{"loans.json", archive.Loans},
+		{"savings_goals.json", archive.SavingsGoals},
+		{"audit_log.json", archive.AuditLogs},
+	}
+	for _, file := range jsonFiles {
+		if err := writeExportJSON(zw, file.name, file.value); err != nil {
+			return err
+		}
+	}
+
+	incomeRecords := make([][]string, 0, len(archive.Income))
+	for _, income := range archive.Income {
+		incomeRecords = append(incomeRecords, []string{
+			income.IncomeForecastID.String(),
+			income.PaymentDate.Format("2006-01-02"),
+			income.Age,
+			income.Industry,
+			strconv.Itoa(income.TotalAmount),
+			strconv.Itoa(income.DeductionAmount),
+			strconv.Itoa(income.TakeHomeAmount),
+			income.Classification,
+		})
+	}
+	if err := writeExportCSV(zw, "income.csv", []string{
+		"income_forecast_id", "payment_date", "age", "industry",
+		"total_amount", "deduction_amount", "take_home_amount", "classification",
+	}, incomeRecords); err != nil {
+		return err
+	}
+
+	scenarioRecords := make([][]string, 0, len(archive.PriceScenarios))
+	for _, scenario := range archive.PriceScenarios {
+		scenarioRecords = append(scenarioRecords, []string{
+			scenario.ScenarioId.String(),
+			scenario.ScenarioName,
+			strconv.Itoa(scenario.MoneyReceived),
+			strconv.Itoa(scenario.Bouns),
+			strconv.Itoa(scenario.FixedCost),
+			strconv.Itoa(scenario.Loan),
+			strconv.Itoa(scenario.Private),
+			strconv.Itoa(scenario.Insurance),
+			strconv.FormatBool(scenario.ActiveFlag),
+		})
+	}
+	if err := writeExportCSV(zw, "price_scenarios.csv", []string{
+		"scenario_id", "scenario_name", "money_received", "bouns",
+		"fixed_cost", "loan", "private", "insurance", "active_flag",
+	}, scenarioRecords); err != nil {
+		return err
+	}
+
+	auditLogRecords := make([][]string, 0, len(archive.AuditLogs))
+	for _, auditLog := range archive.AuditLogs {
+		auditLogRecords = append(auditLogRecords, []string{
+			auditLog.AuditId.String(),
+			auditLog.ActorRole,
+			auditLog.Action,
+			auditLog.Detail,
+			auditLog.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	if err := writeExportCSV(zw, "audit_log.csv", []string{
+		"audit_id", "actor_role", "action", "detail", "created_at",
+	}, auditLogRecords); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// saveExportArchive はエクスポートファイルを保存先に作成する
+// 作成途中のファイルをダウンロードさせないよう、一時ファイルに書き込んでから名前を変更する
+//
+// 引数:
+//   - exportId: エクスポートID
+//   - archive: エクスポートするデータ
+//
+// 戻り値:
+//
+//	戻り値1: 作成したファイルのパス
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func saveExportArchive(exportId string, archive exportArchive) (string, error) {
+	dir := dataExportDir()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	filePath := filepath.Join(dir, exportId+".zip")
+	tmpPath := filePath + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return "", err
+	}
+	if err := writeExportArchive(file, archive); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	return filePath, nil
+}
+
+// buildDataExport はエクスポートファイルを作成し、ダウンロードリンクをメールで送信する
+//
+// 引数:
+//   - userId: ユーザーID
+//   - exportId: エクスポートID
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (de *apiDataExportManagementFetcher) buildDataExport(userId int, exportId string) error {
+	now := time.Now()
+	archive, err := collectExportArchive(userId, now)
+	if err != nil {
+		return err
+	}
+
+	filePath, err := saveExportArchive(exportId, archive)
+	if err != nil {
+		return err
+	}
+
+	token, err := utils.GenerateLinkToken()
+	if err != nil {
+		os.Remove(filePath)
+		return err
+	}
+	expiresAt := now.Add(utils.DataExportLinkHours * time.Hour)
+
+	dbFetcher, _, _ := models.NewExportDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.CompleteDataExport(exportId, filePath, utils.HashLinkToken(token), expiresAt); err != nil {
+		os.Remove(filePath)
+		return err
+	}
+
+	subject, body, err := de.EmailTemplateService.DataExportReadyTemplate(
+		archive.Profile.UserEmail,
+		dataExportLink(token),
+		de.UtilsFetcher.DateTimeStr(expiresAt, "2006年01月02日 15:04"),
+		de.UtilsFetcher.DateTimeStr(now, "2006年01月02日 15:04"),
+	)
+	if err != nil {
+		return err
+	}
+	return de.UtilsFetcher.SendMail(archive.Profile.UserEmail, subject, body, true)
+}
+
+// runDataExport はエクスポートファイルを作成し、失敗した場合はエクスポートを失敗にする
+// 失敗したエクスポートのファイルは期限切れの削除で削除する
+//
+// 引数:
+//   - userId: ユーザーID
+//   - exportId: エクスポートID
+//
+
+func (de *apiDataExportManagementFetcher) runDataExport(userId int, exportId string) {
+	if err := de.buildDataExport(userId, exportId); err != nil {
+		log.Printf("データエクスポートの作成に失敗しました user_id=%d: %v", userId, err)
+
+		dbFetcher, _, _ := models.NewExportDataFetcher(config.GetDataBaseSource())
+		if err := dbFetcher.FailDataExport(exportId); err != nil {
+			log.Printf("データエクスポートの失敗の登録に失敗しました user_id=%d: %v", userId, err)
+		}
+	}
+}
+
+// RequestDataExportApi は個人データのエクスポートを受け付けるAPI
+// ファイルは非同期で作成し、完了後にダウンロードリンクをメールで送信する
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (de *apiDataExportManagementFetcher) RequestDataExportApi(c *gin.Context) {
+	var requestData requestDataExportData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userIdPrams := common.AnyToStr(requestData.UserId)
+	validator := validation.RequestDataExportData{
+		UserId: userIdPrams,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, _ := de.CommonFetcher.StrToInt(userIdPrams)
+	if !isSignInUser(c, userId) {
+		signInUserMismatch(c)
+		return
+	}
+
+	now := time.Now()
+	exportId := uuid.New().String()
+	dbFetcher, _, _ := models.NewExportDataFetcher(config.GetDataBaseSource())
+	err := dbFetcher.InsertDataExport(models.DataExportData{
+		ExportId:  exportId,
+		UserId:    userId,
+		CreatedAt: now,
+	}, now.Add(-dataExportStaleInterval))
+	if errors.Is(err, models.ErrExportPending) {
+		response := utils.ErrorMessageResponse{
+			Result: "作成中のエクスポートがあります。完了のメールをお待ちください。",
+		}
+		c.JSON(http.StatusConflict, response)
+		return
+	} else if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	startDataExportJob(func() { de.runDataExport(userId, exportId) })
+
+	response := utils.ResponseData[string]{
+		Result: "エクスポートを受け付けました。準備ができたらメールでお知らせします。",
+	}
+	c.JSON(http.StatusAccepted, response)
+}
+
+// DownloadDataExportApi はエクスポート完了のメールに記載したリンクからファイルをダウンロードするAPI
+// リンクを知っていても、エクスポートしたユーザー本人でなければダウンロードできない
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (de *apiDataExportManagementFetcher) DownloadDataExportApi(c *gin.Context) {
+	token := c.Query("token")
+
+	validator := validation.RequestDataExportDownloadData{
+		Token: token,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	dbFetcher, _, _ := models.NewExportDataFetcher(config.GetDataBaseSource())
+	export, err := dbFetcher.GetDataExport(utils.HashLinkToken(token), time.Now())
+	if errors.Is(err, models.ErrExportNotFound) {
+		response := utils.ErrorMessageResponse{
+			Result: dataExportLinkMessage,
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	} else if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if !isSignInUser(c, export.UserId) {
+		signInUserMismatch(c)
+		return
+	}
+
+	if _, err := os.Stat(export.FilePath); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: dataExportLinkMessage,
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	c.FileAttachment(export.FilePath, fmt.Sprintf("takuwaeru_export_%s.zip", export.CreatedAt.Format("20060102")))
+}
+
+// isDataExportFile はエクスポートで作成したファイル(エクスポートID.zip 又は作成途中の .zip.tmp)かどうかを返す
+func isDataExportFile(name string) bool {
+	exportId, ok := strings.CutSuffix(name, ".zip.tmp")
+	if !ok {
+		exportId, ok = strings.CutSuffix(name, ".zip")
+	}
+	if !ok {
+		return false
+	}
+	id, err := uuid.Parse(exportId)
+	return err == nil && id.String() == exportId
+}
+
+// cleanupDataExports は有効期限を過ぎたエクスポートとファイルを削除する
+// 退会したユーザーのエクスポートはDBから先に削除されるため、有効期限より古いファイルも削除する
+//
+// 引数:
+//   - now: 基準日時
+//
+// 戻り値:
+//
+//	戻り値1: 削除したファイルの件数
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func cleanupDataExports(now time.Time) (int, error) {
+	dbFetcher, _, _ := models.NewExportDataFetcher(config.GetDataBaseSource())
+	filePaths, err := dbFetcher.DeleteExpiredDataExports(now)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, filePath := range filePaths {
+		if filePath == "" {
+			continue
+		}
+		if err := os.Remove(filePath); err == nil {
+			removed++
+		} else if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("エクスポートファイルの削除に失敗しました %s: %v", filePath, err)
+		}
+	}
+
+	entries, err := os.ReadDir(dataExportDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return removed, nil
+	} else if err != nil {
+		return removed, err
+	}
+	expiredBefore := now.Add(-utils.DataExportLinkHours * time.Hour)
+	for _, entry := range entries {
+		// エクスポートで作成したファイル以外は削除しない
+		if entry.IsDir() || !isDataExportFile(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(expiredBefore) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dataExportDir(), entry.Name())); err == nil {
+			removed++
+		}
+	}
+
+	return removed, nil
+}
+
+// StartDataExportCleanupJob は有効期限を過ぎたエクスポートを定期的に削除するジョブを開始する
+func StartDataExportCleanupJob() {
+	go func() {
+		ticker := time.NewTicker(dataExportCleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			removed, err := cleanupDataExports(time.Now())
+			if err != nil {
+				log.Printf("期限切れのエクスポートの削除に失敗しました: %v", err)
+			} else if removed > 0 {
+				log.Printf("期限切れのエクスポートを削除しました: %d件", removed)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,463 @@
+package controllers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"server/common"
+	"server/config"
+	mock_templates "server/mock/templates"
+	mock_utils "server/mock/utils"
+	"server/models"
+	"server/utils"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// useTempExportDir はエクスポートファイルの保存先をテスト用の一時ディレクトリにする
+func useTempExportDir(t *testing.T) string {
+	dir := t.TempDir()
+	exportDir := config.GlobalEnv.ExportDir
+	config.GlobalEnv.ExportDir = dir
+	t.Cleanup(func() { config.GlobalEnv.ExportDir = exportDir })
+	return dir
+}
+
+// patchExportArchive はエクスポートするデータの取得をモック化する
+func patchExportArchive(archive exportArchive, profileErr error) *Patches {
+	patches := ApplyMethod(
+		reflect.TypeOf(&models.ExportDataFetcher{}),
+		"GetExportProfile",
+		func(_ *models.ExportDataFetcher, UserId int) (models.ExportProfileData, error) {
+			return archive.Profile, profileErr
+		})
+	patches.ApplyMethod(
+		reflect.TypeOf(&models.IdentityDataFetcher{}),
+		"GetIdentities",
+		func(_ *models.IdentityDataFetcher, UserId int) ([]models.IdentityData, error) {
+			return archive.Identities, nil
+		})
+	patches.ApplyMethod(
+		reflect.TypeOf(&models.ExportDataFetcher{}),
+		"GetExportIncome",
+		func(_ *models.ExportDataFetcher, UserId int) ([]models.IncomeData, error) {
+			return archive.Income, nil
+		})
+	patches.ApplyMethod(
+		reflect.TypeOf(&models.PriceScenarioDataFetcher{}),
+		"GetPriceScenarios",
+		func(_ *models.PriceScenarioDataFetcher, UserId int) ([]models.PriceScenarioData, error) {
+			return archive.PriceScenarios, nil
+		})
+	patches.ApplyMethod(
+		reflect.TypeOf(&models.LoanDataFetcher{}),
+		"GetLoans",
+		func(_ *models.LoanDataFetcher, UserId int) ([]models.LoanData, error) {
+			return archive.Loans, nil
+		})
+	patches.ApplyMethod(
+		reflect.TypeOf(&models.SavingsGoalDataFetcher{}),
+		"GetSavingsGoals",
+		func(_ *models.SavingsGoalDataFetcher, UserId int) ([]models.SavingsGoalData, error) {
+			return archive.SavingsGoals, nil
+		})
+	patches.ApplyMethod(
+		reflect.TypeOf(&models.ExportDataFetcher{}),
+		"GetExportAuditLogs",
+		func(_ *models.ExportDataFetcher, UserId int) ([]models.ExportAuditLogData, error) {
+			return archive.AuditLogs, nil
+		})
+	return patches
+}
+
+// readExportArchive はZIPファイルの内容をファイル名ごとに返す
+func readExportArchive(t *testing.T, data []byte) map[string]string {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+
+	files := map[string]string{}
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		assert.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		assert.NoError(t, err)
+		rc.Close()
+		files[file.Name] = string(content)
+	}
+	return files
+}
+
+var testExportArchive = exportArchive{
+	Profile: models.ExportProfileData{UserId: 1, UserEmail: "test@example.com", UserName: "test", Role: "user"},
+	Income: []models.IncomeData{{
+		IncomeForecastID: uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"),
+		PaymentDate:      time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC),
+		Age:              "30",
+		Industry:         "IT",
+		TotalAmount:      300000,
+		DeductionAmount:  50000,
+		TakeHomeAmount:   250000,
+		Classification:   "給料",
+		UserID:           1,
+	}},
+}
+
+func TestWriteExportArchive(t *testing.T) {
+	archive := testExportArchive
+	archive.Manifest = models.ExportManifestData{Version: models.ExportArchiveVersion, UserId: 1}
+
+	var buf bytes.Buffer
+	err := writeExportArchive(&buf, archive)
+	assert.NoError(t, err)
+
+	files := readExportArchive(t, buf.Bytes())
+	for _, name := range []string{
+		"manifest.json", "profile.json", "identities.json", "income.json", "price_scenarios.json",
+		"loans.json", "savings_goals.json", "audit_log.json", "income.csv", "price_scenarios.csv", "audit_log.csv",
+	} {
+		assert.Contains(t, files, name)
+	}
+
+	var manifest models.ExportManifestData
+	assert.NoError(t, json.Unmarshal([]byte(files["manifest.json"]), &manifest))
+	assert.Equal(t, models.ExportArchiveVersion, manifest.Version)
+
+	var income []models.IncomeData
+	assert.NoError(t, json.Unmarshal([]byte(files["income.json"]), &income))
+	assert.Equal(t, archive.Income, income)
+
+	// ExcelのためにBOMを付ける
+	assert.True(t, strings.HasPrefix(files["income.csv"], "\ufeff"))
+	assert.Contains(t, files["income.csv"], "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11,2024-02-10,30,IT,300000,50000,250000,給料")
+}
+
+func TestRequestDataExportApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	exportRequest := func(w *httptest.ResponseRecorder, body string, signInUserId int) *gin.Context {
+		req := httptest.NewRequest("POST", "/api/data_export", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return signInContext(w, req, signInUserId, "session-id")
+	}
+
+	// ジョブは実行せずに受け付けたことのみ確認する
+	patchStartDataExportJob := func(t *testing.T) *int {
+		started := 0
+		startJob := startDataExportJob
+		startDataExportJob = func(job func()) { started++ }
+		t.Cleanup(func() { startDataExportJob = startJob })
+		return &started
+	}
+
+	t.Run("success RequestDataExportApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := exportRequest(w, `{"user_id": 1}`, 1)
+		started := patchStartDataExportJob(t)
+
+		var inserted models.DataExportData
+		var staleBefore time.Time
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.ExportDataFetcher{}),
+			"InsertDataExport",
+			func(_ *models.ExportDataFetcher, data models.DataExportData, StaleBefore time.Time) error {
+				inserted, staleBefore = data, StaleBefore
+				return nil
+			})
+		defer patches.Reset()
+
+		fetcher := NewDataExportManagementFetcher(common.NewCommonFetcher(), nil, nil)
+		fetcher.RequestDataExportApi(c)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, 1, inserted.UserId)
+		assert.NotEmpty(t, inserted.ExportId)
+		assert.Equal(t, inserted.CreatedAt.Add(-dataExportStaleInterval), staleBefore)
+		assert.Equal(t, 1, *started)
+	})
+
+	t.Run("error RequestDataExportApi 作成中のエクスポートがある", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := exportRequest(w, `{"user_id": 1}`, 1)
+		started := patchStartDataExportJob(t)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.ExportDataFetcher{}),
+			"InsertDataExport",
+			func(_ *models.ExportDataFetcher, data models.DataExportData, StaleBefore time.Time) error {
+				return models.ErrExportPending
+			})
+		defer patches.Reset()
+
+		fetcher := NewDataExportManagementFetcher(common.NewCommonFetcher(), nil, nil)
+		fetcher.RequestDataExportApi(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assertErrorMessage(t, w, "作成中のエクスポートがあります。完了のメールをお待ちください。")
+		assert.Equal(t, 0, *started)
+	})
+
+	t.Run("error RequestDataExportApi サインインユーザーが異なる", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := exportRequest(w, `{"user_id": 2}`, 1)
+
+		fetcher := NewDataExportManagementFetcher(common.NewCommonFetcher(), nil, nil)
+		fetcher.RequestDataExportApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("error RequestDataExportApi ユーザーID必須", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := exportRequest(w, `{}`, 1)
+
+		fetcher := NewDataExportManagementFetcher(common.NewCommonFetcher(), nil, nil)
+		fetcher.RequestDataExportApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestRunDataExport(t *testing.T) {
+
+	t.Run("success runDataExport", func(t *testing.T) {
+		dir := useTempExportDir(t)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		patches := patchExportArchive(testExportArchive, nil)
+		defer patches.Reset()
+		var completedPath, tokenHash string
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.ExportDataFetcher{}),
+			"CompleteDataExport",
+			func(_ *models.ExportDataFetcher, ExportId, FilePath, TokenHash string, ExpiresAt time.Time) error {
+				completedPath, tokenHash = FilePath, TokenHash
+				return nil
+			})
+
+		var link string
+		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
+		mockEmailTemplateService.EXPECT().
+			DataExportReadyTemplate("test@example.com", gomock.Any(), "2025年01月07日 20:00", "2025年01月06日 20:00").
+			DoAndReturn(func(UserEmail, Link, ExpireDate, DateTime string) (string, string, error) {
+				link = Link
+				return "件名", "本文", nil
+			})
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		gomock.InOrder(
+			mockUtilsFetcher.EXPECT().DateTimeStr(gomock.Any(), gomock.Any()).Return("2025年01月07日 20:00"),
+			mockUtilsFetcher.EXPECT().DateTimeStr(gomock.Any(), gomock.Any()).Return("2025年01月06日 20:00"),
+		)
+		mockUtilsFetcher.EXPECT().
+			SendMail("test@example.com", "件名", "本文", true).
+			Return(nil)
+
+		fetcher := apiDataExportManagementFetcher{
+			UtilsFetcher:         mockUtilsFetcher,
+			EmailTemplateService: mockEmailTemplateService,
+		}
+		fetcher.runDataExport(1, "export-id")
+
+		assert.Equal(t, filepath.Join(dir, "export-id.zip"), completedPath)
+		data, err := os.ReadFile(completedPath)
+		assert.NoError(t, err)
+		assert.Contains(t, readExportArchive(t, data), "manifest.json")
+
+		// リンクのトークンはハッシュのみを保存する
+		token := link[strings.LastIndex(link, "=")+1:]
+		assert.Equal(t, utils.HashLinkToken(token), tokenHash)
+		assert.True(t, strings.HasPrefix(link, utils.GetBaseURL()))
+	})
+
+	t.Run("error runDataExport データ取得エラー", func(t *testing.T) {
+		dir := useTempExportDir(t)
+
+		patches := patchExportArchive(exportArchive{}, errors.New("database error"))
+		defer patches.Reset()
+		failedExportId := ""
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.ExportDataFetcher{}),
+			"FailDataExport",
+			func(_ *models.ExportDataFetcher, ExportId string) error {
+				failedExportId = ExportId
+				return nil
+			})
+
+		fetcher := apiDataExportManagementFetcher{}
+		fetcher.runDataExport(1, "export-id")
+
+		assert.Equal(t, "export-id", failedExportId)
+		entries, _ := os.ReadDir(dir)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("error runDataExport 完了の登録エラー", func(t *testing.T) {
+		dir := useTempExportDir(t)
+
+		patches := patchExportArchive(testExportArchive, nil)
+		defer patches.Reset()
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.ExportDataFetcher{}),
+			"CompleteDataExport",
+			func(_ *models.ExportDataFetcher, ExportId, FilePath, TokenHash string, ExpiresAt time.Time) error {
+				return errors.New("database error")
+			})
+		failed := false
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.ExportDataFetcher{}),
+			"FailDataExport",
+			func(_ *models.ExportDataFetcher, ExportId string) error {
+				failed = true
+				return nil
+			})
+
+		fetcher := apiDataExportManagementFetcher{}
+		fetcher.runDataExport(1, "export-id")
+
+		// 作成したファイルは削除する
+		assert.True(t, failed)
+		entries, _ := os.ReadDir(dir)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestDownloadDataExportApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	downloadRequest := func(w *httptest.ResponseRecorder, token string, signInUserId int) *gin.Context {
+		req := httptest.NewRequest("GET", "/api/data_export_download?token="+token, nil)
+		return signInContext(w, req, signInUserId, "session-id")
+	}
+
+	patchGetDataExport := func(data models.DataExportData, err error) *Patches {
+		return ApplyMethod(
+			reflect.TypeOf(&models.ExportDataFetcher{}),
+			"GetDataExport",
+			func(_ *models.ExportDataFetcher, TokenHash string, Now time.Time) (models.DataExportData, error) {
+				if TokenHash != utils.HashLinkToken("export-token") {
+					return models.DataExportData{}, models.ErrExportNotFound
+				}
+				return data, err
+			})
+	}
+
+	t.Run("success DownloadDataExportApi", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), "export-id.zip")
+		assert.NoError(t, os.WriteFile(filePath, []byte("zip"), 0o600))
+
+		w := httptest.NewRecorder()
+		c := downloadRequest(w, "export-token", 1)
+
+		patches := patchGetDataExport(models.DataExportData{
+			UserId:    1,
+			FilePath:  filePath,
+			CreatedAt: time.Date(2025, 1, 6, 20, 0, 0, 0, time.Local),
+		}, nil)
+		defer patches.Reset()
+
+		fetcher := NewDataExportManagementFetcher(common.NewCommonFetcher(), nil, nil)
+		fetcher.DownloadDataExportApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "zip", w.Body.String())
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "takuwaeru_export_20250106.zip")
+	})
+
+	t.Run("error DownloadDataExportApi 無効なリンク", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := downloadRequest(w, "other-token", 1)
+
+		patches := patchGetDataExport(models.DataExportData{}, nil)
+		defer patches.Reset()
+
+		fetcher := NewDataExportManagementFetcher(common.NewCommonFetcher(), nil, nil)
+		fetcher.DownloadDataExportApi(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assertErrorMessage(t, w, dataExportLinkMessage)
+	})
+
+	t.Run("error DownloadDataExportApi 他のユーザーのエクスポート", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := downloadRequest(w, "export-token", 2)
+
+		patches := patchGetDataExport(models.DataExportData{UserId: 1, FilePath: "/tmp/export-id.zip"}, nil)
+		defer patches.Reset()
+
+		fetcher := NewDataExportManagementFetcher(common.NewCommonFetcher(), nil, nil)
+		fetcher.DownloadDataExportApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("error DownloadDataExportApi トークン必須", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := downloadRequest(w, "", 1)
+
+		fetcher := NewDataExportManagementFetcher(common.NewCommonFetcher(), nil, nil)
+		fetcher.DownloadDataExportApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCleanupDataExports(t *testing.T) {
+	dir := useTempExportDir(t)
+	now := time.Now()
+
+	expiredPath := filepath.Join(dir, "expired.zip")
+	orphanPath := filepath.Join(dir, "8df939de-5a97-4f20-b41b-9ac355c16e36.zip")
+	orphanTmpPath := filepath.Join(dir, "71eb75e7-79b8-40d1-b581-d819d8470239.zip.tmp")
+	currentPath := filepath.Join(dir, "current.zip")
+	// エクスポートID以外の名前のファイルは古くても削除しない
+	otherPaths := []string{
+		filepath.Join(dir, "backup.zip"),
+		filepath.Join(dir, "8df939de-5a97-4f20-b41b-9ac355c16e36.txt"),
+		filepath.Join(dir, "{8df939de-5a97-4f20-b41b-9ac355c16e36}.zip"),
+	}
+	for _, path := range append([]string{expiredPath, orphanPath, orphanTmpPath, currentPath}, otherPaths...) {
+		assert.NoError(t, os.WriteFile(path, []byte("zip"), 0o600))
+	}
+	// 退会などでDBから削除されたエクスポートのファイル
+	old := now.Add(-(utils.DataExportLinkHours + 1) * time.Hour)
+	for _, path := range append([]string{orphanPath, orphanTmpPath}, otherPaths...) {
+		assert.NoError(t, os.Chtimes(path, old, old))
+	}
+
+	patches := ApplyMethod(
+		reflect.TypeOf(&models.ExportDataFetcher{}),
+		"DeleteExpiredDataExports",
+		func(_ *models.ExportDataFetcher, Now time.Time) ([]string, error) {
+			return []string{expiredPath, ""}, nil
+		})
+	defer patches.Reset()
+
+	removed, err := cleanupDataExports(now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, removed)
+	assert.NoFileExists(t, expiredPath)
+	assert.NoFileExists(t, orphanPath)
+	assert.NoFileExists(t, orphanTmpPath)
+	assert.FileExists(t, currentPath)
+	for _, path := range otherPaths {
+		assert.FileExists(t, path)
+	}
+}
@@ -0,0 +1,360 @@
+// controllers/data_import_controllers.go
+package controllers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"server/common"
+	"server/config"
+	"server/models"
+	"server/utils"
+	"server/validation"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// インポートするファイルに含めるデータ
+type importArchive struct {
+	Manifest       models.ExportManifestData
+	Income         []models.IncomeData
+	PriceScenarios []models.PriceScenarioData
+	Loans          []models.LoanData
+	SavingsGoals   []models.SavingsGoalData
+}
+
+const (
+	// アップロードできるファイルの最大サイズ
+	dataImportMaxBytes = 10 << 20
+	// 展開後のファイル1件あたりの最大サイズ(圧縮爆弾の対策)
+	dataImportMaxEntryBytes = 20 << 20
+)
+
+// readImportEntry はZIPファイル内のJSONファイルを読み込む
+// ファイルが存在しない場合はfalseを返す
+func readImportEntry(reader *zip.Reader, name string, v interface{}) (bool, error) {
+	file, err := reader.Open(name)
+	if err != nil {
+		return false, nil
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, dataImportMaxEntryBytes+1))
+	if err != nil || len(data) > dataImportMaxEntryBytes {
+		return true, fmt.Errorf("%s を読み込めません。", name)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return true, fmt.Errorf("%s の形式が間違っています。", name)
+	}
+	return true, nil
+}
+
+// readImportArchive はエクスポートしたZIPファイルからインポートするデータを読み込む
+// manifest.json のバージョンが現在のファイル形式と一致しない場合はエラーとする
+//
+// 引数:
+//   - data: ZIPファイルの内容
+//
+// 戻り値:
+//
+//	戻り値1: インポートするデータ
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func readImportArchive(data []byte) (importArchive, error) {
+	var archive importArchive
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return archive, errors.New("ZIP形式のファイルを指定してください。")
+	}
+
+	found, err := readImportEntry(reader, "manifest.json", &archive.Manifest)
+	if err != nil {
+		return archive, err
+	} else if !found {
+		return archive, errors.New("manifest.json が見つかりません。エクスポートしたファイルを指定してください。")
+	}
+	if archive.Manifest.Version != models.ExportArchiveVersion {
+		return archive, fmt.Errorf("対応していないファイル形式のバージョンです。(version: %d)", archive.Manifest.Version)
+	}
+
+	if _, err := readImportEntry(reader, "income.json", &archive.Income); err != nil {
+		return archive, err
+	}
+	if _, err := readImportEntry(reader, "price_scenarios.json", &archive.PriceScenarios); err != nil {
+		return archive, err
+	}
+	if _, err := readImportEntry(reader, "loans.json", &archive.Loans); err != nil {
+		return archive, err
+	}
+	if _, err := readImportEntry(reader, "savings_goals.json", &archive.SavingsGoals); err != nil {
+		return archive, err
+	}
+	return archive, nil
+}
+
+// importValidationErrors はエラーの項目名にファイル名を付ける
+func importValidationErrors(fileName string, errMsgList []utils.ErrorMessages) []utils.ErrorMessages {
+	for i := range errMsgList {
+		errMsgList[i].Field = fileName + "." + errMsgList[i].Field
+	}
+	return errMsgList
+}
+
+// buildImportData はインポートするデータを新規登録と同じバリデーションで検証し、登録用のデータにする
+// ユーザーIDはインポート先のユーザーに置き換える
+//
+// 引数:
+//   - commonFetcher: 文字列の変換
+//   - userId: インポート先のユーザーID
+//   - archive: インポートするファイルの内容
+//
+// 戻り値:
+//
+//	戻り値1: 登録用のデータ
+//	戻り値2: バリデーションエラー(エラーがない場合はnil)
+//
+
+func buildImportData(commonFetcher common.CommonFetcher, userId int, archive importArchive) (models.ImportData, *utils.ErrorValidationResponse) {
+	data := models.ImportData{
+		Income:         make([]models.InsertIncomeData, 0, len(archive.Income)),
+		PriceScenarios: make([]models.InsertPriceScenarioData, 0, len(archive.PriceScenarios)),
+		Loans:          make([]models.InsertLoanData, 0, len(archive.Loans)),
+		SavingsGoals:   make([]models.InsertSavingsGoalData, 0, len(archive.SavingsGoals)),
+	}
+	userIdStr := common.AnyToStr(userId)
+
+	for idx, income := range archive.Income {
+		// 年齢が整数でない場合は必須エラーとする
+		age, _ := commonFetcher.StrToInt(income.Age)
+		insertData := models.InsertIncomeData{
+			PaymentDate:     income.PaymentDate.Format("2006-01-02"),
+			Age:             age,
+			Industry:        income.Industry,
+			TotalAmount:     income.TotalAmount,
+			DeductionAmount: income.DeductionAmount,
+			TakeHomeAmount:  income.TakeHomeAmount,
+			Classification:  income.Classification,
+			UserID:          userId,
+		}
+		validator := validation.RequestInsertIncomeData{
+			PaymentDate:     insertData.PaymentDate,
+			Age:             insertData.Age,
+			Industry:        insertData.Industry,
+			TotalAmount:     common.AnyToStr(insertData.TotalAmount),
+			DeductionAmount: common.AnyToStr(insertData.DeductionAmount),
+			TakeHomeAmount:  common.AnyToStr(insertData.TakeHomeAmount),
+			Classification:  insertData.Classification,
+			UserId:          userIdStr,
+		}
+		if valid, errMsgList := validator.Validate(); !valid {
+			return data, &utils.ErrorValidationResponse{
+				RecodeRows: idx + 1,
+				Result:     importValidationErrors("income", errMsgList),
+			}
+		}
+		data.Income = append(data.Income, insertData)
+	}
+
+	activeScenario := false
+	for idx, scenario := range archive.PriceScenarios {
+		validator := validation.RequestInsertPriceScenarioData{
+			ScenarioName:  scenario.ScenarioName,
+			MoneyReceived: common.AnyToStr(scenario.MoneyReceived),
+			Bouns:         common.AnyToStr(scenario.Bouns),
+			FixedCost:     common.AnyToStr(scenario.FixedCost),
+			Loan:          common.AnyToStr(scenario.Loan),
+			Private:       common.AnyToStr(scenario.Private),
+			Insurance:     common.AnyToStr(scenario.Insurance),
+			UserId:        userIdStr,
+		}
+		if valid, errMsgList := validator.Validate(); !valid {
+			return data, &utils.ErrorValidationResponse{
+				RecodeRows: idx + 1,
+				Result:     importValidationErrors("price_scenarios", errMsgList),
+			}
+		}
+		// 有効なシナリオはユーザー毎に1件のみ登録できる
+		if scenario.ActiveFlag {
+			if activeScenario {
+				return data, &utils.ErrorValidationResponse{
+					RecodeRows: idx + 1,
+					Result: importValidationErrors("price_scenarios", []utils.ErrorMessages{{
+						Field:   "active_flag",
+						Message: "有効なシナリオは1件のみ指定してください。",
+					}}),
+				}
+			}
+			activeScenario = true
+		}
+		data.PriceScenarios = append(data.PriceScenarios, models.InsertPriceScenarioData{
+			ScenarioName:  scenario.ScenarioName,
+			MoneyReceived: scenario.MoneyReceived,
+			Bouns:         scenario.Bouns,
+			FixedCost:     scenario.FixedCost,
+			Loan:          scenario.Loan,
+			Private:       scenario.Private,
+			Insurance:     scenario.Insurance,
+			ActiveFlag:    scenario.ActiveFlag,
+			UserId:        userId,
+		})
+	}
+
+	for idx, loan := range archive.Loans {
+		insertData := models.InsertLoanData{
+			LoanName:        loan.LoanName,
+			Principal:       loan.Principal,
+			AnnualRate:      loan.AnnualRate,
+			TermMonths:      loan.TermMonths,
+			StartDate:       loan.StartDate.Format("2006-01-02"),
+			RateType:        loan.RateType,
+			RepaymentMethod: loan.RepaymentMethod,
+			UserId:          userId,
+		}
+		validator := validation.RequestInsertLoanData{
+			LoanName:        insertData.LoanName,
+			Principal:       common.AnyToStr(insertData.Principal),
+			AnnualRate:      strconv.FormatFloat(insertData.AnnualRate, 'f', -1, 64),
+			TermMonths:      common.AnyToStr(insertData.TermMonths),
+			StartDate:       insertData.StartDate,
+			RateType:        insertData.RateType,
+			RepaymentMethod: insertData.RepaymentMethod,
+			UserId:          userIdStr,
+		}
+		if valid, errMsgList := validator.Validate(); !valid {
+			return data, &utils.ErrorValidationResponse{
+				RecodeRows: idx + 1,
+				Result:     importValidationErrors("loans", errMsgList),
+			}
+		}
+		data.Loans = append(data.Loans, insertData)
+	}
+
+	for idx, goal := range archive.SavingsGoals {
+		insertData := models.InsertSavingsGoalData{
+			GoalName:       goal.GoalName,
+			TargetAmount:   goal.TargetAmount,
+			TargetDate:     goal.TargetDate.Format("2006-01-02"),
+			CurrentBalance: goal.CurrentBalance,
+			UserId:         userId,
+		}
+		validator := validation.RequestInsertSavingsGoalData{
+			GoalName:       insertData.GoalName,
+			TargetAmount:   common.AnyToStr(insertData.TargetAmount),
+			TargetDate:     insertData.TargetDate,
+			CurrentBalance: common.AnyToStr(insertData.CurrentBalance),
+			UserId:         userIdStr,
+		}
+		if valid, errMsgList := validator.Validate(); !valid {
+			return data, &utils.ErrorValidationResponse{
+				RecodeRows: idx + 1,
+				Result:     importValidationErrors("savings_goals", errMsgList),
+			}
+		}
+		data.SavingsGoals = append(data.SavingsGoals, insertData)
+	}
+
+	return data, nil
+}
+
+// ImportDataApi はエクスポートしたファイルから年収推移・収支シナリオ・ローン・貯蓄目標を登録するAPI
+// dry_run=false を指定しない場合は登録せずに結果のみ返す
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (de *apiDataExportManagementFetcher) ImportDataApi(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, dataImportMaxBytes)
+
+	userIdPrams := c.PostForm("user_id")
+	dryRunPrams := c.DefaultPostForm("dry_run", "true")
+
+	validator := validation.RequestDataImportData{
+		UserId: userIdPrams,
+		DryRun: dryRunPrams,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, _ := de.CommonFetcher.StrToInt(userIdPrams)
+	if !isSignInUser(c, userId) {
+		signInUserMismatch(c)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "インポートするファイルを指定してください。",
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	archive, err := readImportArchive(content)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	importData, validationErr := buildImportData(de.CommonFetcher, userId, archive)
+	if validationErr != nil {
+		c.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	dbFetcher, _, _ := models.NewExportDataFetcher(config.GetDataBaseSource())
+	report, err := dbFetcher.ImportUserData(userId, importData, dryRunPrams == "true")
+	if errors.Is(err, models.ErrImportNotEmpty) {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusConflict, response)
+		return
+	} else if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "インポート時にエラーが発生しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[models.ImportReportData]{
+		RecodeRows: report.Income + report.PriceScenarios + report.Loans + report.SavingsGoals,
+		Result:     report,
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,351 @@
+package controllers
+
+import (
+	"archive/zip"
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"server/common"
+	"server/models"
+	"server/utils"
+	"testing"
+	"time"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// testImportArchive はエクスポートと同じ形式のZIPファイルを作成する
+func testImportArchive(t *testing.T, version int) []byte {
+	archive := testExportArchive
+	archive.Manifest = models.ExportManifestData{Version: version, UserId: 1}
+	archive.PriceScenarios = []models.PriceScenarioData{{
+		ScenarioId:    uuid.MustParse("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"),
+		UserId:        1,
+		ScenarioName:  "基本",
+		MoneyReceived: 250000,
+		FixedCost:     80000,
+		Private:       50000,
+		Insurance:     10000,
+		ActiveFlag:    true,
+	}}
+	archive.Loans = []models.LoanData{{
+		LoanId:          uuid.MustParse("d0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"),
+		UserId:          1,
+		LoanName:        "住宅ローン",
+		Principal:       30000000,
+		AnnualRate:      0.5,
+		TermMonths:      420,
+		StartDate:       time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		RateType:        "variable",
+		RepaymentMethod: "equal_payment",
+	}}
+	archive.SavingsGoals = []models.SavingsGoalData{{
+		GoalId:         uuid.MustParse("e0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"),
+		UserId:         1,
+		GoalName:       "車の購入",
+		TargetAmount:   2000000,
+		TargetDate:     time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+		CurrentBalance: 500000,
+	}}
+
+	var buf bytes.Buffer
+	assert.NoError(t, writeExportArchive(&buf, archive))
+	return buf.Bytes()
+}
+
+func TestReadImportArchive(t *testing.T) {
+
+	t.Run("success readImportArchive", func(t *testing.T) {
+		archive, err := readImportArchive(testImportArchive(t, models.ExportArchiveVersion))
+
+		assert.NoError(t, err)
+		assert.Equal(t, testExportArchive.Income, archive.Income)
+		assert.Len(t, archive.PriceScenarios, 1)
+		assert.Len(t, archive.Loans, 1)
+		assert.Len(t, archive.SavingsGoals, 1)
+	})
+
+	t.Run("error readImportArchive 対応していないバージョン", func(t *testing.T) {
+		_, err := readImportArchive(testImportArchive(t, models.ExportArchiveVersion+1))
+
+		assert.EqualError(t, err, "対応していないファイル形式のバージョンです。(version: 2)")
+	})
+
+	t.Run("error readImportArchive ZIP形式でない", func(t *testing.T) {
+		_, err := readImportArchive([]byte("not zip"))
+
+		assert.EqualError(t, err, "ZIP形式のファイルを指定してください。")
+	})
+
+	t.Run("error readImportArchive manifest.json がない", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		w, _ := zw.Create("income.json")
+		w.Write([]byte("[]"))
+		zw.Close()
+
+		_, err := readImportArchive(buf.Bytes())
+
+		assert.Error(t, err)
+	})
+
+	t.Run("error readImportArchive JSONの形式が間違っている", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		w, _ := zw.Create("manifest.json")
+		w.Write([]byte(`{"version": 1}`))
+		w, _ = zw.Create("income.json")
+		w.Write([]byte(`{`))
+		zw.Close()
+
+		_, err := readImportArchive(buf.Bytes())
+
+		assert.EqualError(t, err, "income.json の形式が間違っています。")
+	})
+}
+
+func TestBuildImportData(t *testing.T) {
+
+	t.Run("success buildImportData インポート先のユーザーIDに置き換える", func(t *testing.T) {
+		archive, _ := readImportArchive(testImportArchive(t, models.ExportArchiveVersion))
+
+		data, validationErr := buildImportData(common.NewCommonFetcher(), 2, archive)
+
+		assert.Nil(t, validationErr)
+		assert.Equal(t, []models.InsertIncomeData{{
+			PaymentDate:     "2024-02-10",
+			Age:             30,
+			Industry:        "IT",
+			TotalAmount:     300000,
+			DeductionAmount: 50000,
+			TakeHomeAmount:  250000,
+			Classification:  "給料",
+			UserID:          2,
+		}}, data.Income)
+		assert.Equal(t, 2, data.PriceScenarios[0].UserId)
+		assert.True(t, data.PriceScenarios[0].ActiveFlag)
+		assert.Equal(t, []models.InsertLoanData{{
+			LoanName:        "住宅ローン",
+			Principal:       30000000,
+			AnnualRate:      0.5,
+			TermMonths:      420,
+			StartDate:       "2024-04-01",
+			RateType:        "variable",
+			RepaymentMethod: "equal_payment",
+			UserId:          2,
+		}}, data.Loans)
+		assert.Equal(t, []models.InsertSavingsGoalData{{
+			GoalName:       "車の購入",
+			TargetAmount:   2000000,
+			TargetDate:     "2026-03-31",
+			CurrentBalance: 500000,
+			UserId:         2,
+		}}, data.SavingsGoals)
+	})
+
+	t.Run("error buildImportData 年収推移のバリデーションエラー", func(t *testing.T) {
+		archive := importArchive{Income: []models.IncomeData{
+			testExportArchive.Income[0],
+			{PaymentDate: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), Age: "30", Classification: "給料"},
+		}}
+
+		_, validationErr := buildImportData(common.NewCommonFetcher(), 2, archive)
+
+		assert.NotNil(t, validationErr)
+		assert.Equal(t, 2, validationErr.RecodeRows)
+		assert.Contains(t, validationErr.Result, utils.ErrorMessages{Field: "income.industry", Message: "業種は必須です。"})
+	})
+
+	t.Run("error buildImportData 収支シナリオのバリデーションエラー", func(t *testing.T) {
+		archive := importArchive{PriceScenarios: []models.PriceScenarioData{{ScenarioName: ""}}}
+
+		_, validationErr := buildImportData(common.NewCommonFetcher(), 2, archive)
+
+		assert.NotNil(t, validationErr)
+		assert.Equal(t, 1, validationErr.RecodeRows)
+		assert.Contains(t, validationErr.Result, utils.ErrorMessages{Field: "price_scenarios.scenario_name", Message: "シナリオ名は必須です。"})
+	})
+
+	t.Run("error buildImportData ローンのバリデーションエラー", func(t *testing.T) {
+		archive := importArchive{Loans: []models.LoanData{{
+			LoanName:        "住宅ローン",
+			Principal:       30000000,
+			TermMonths:      420,
+			StartDate:       time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			RateType:        "unknown",
+			RepaymentMethod: "equal_payment",
+		}}}
+
+		_, validationErr := buildImportData(common.NewCommonFetcher(), 2, archive)
+
+		assert.NotNil(t, validationErr)
+		assert.Equal(t, 1, validationErr.RecodeRows)
+		assert.Contains(t, validationErr.Result, utils.ErrorMessages{Field: "loans.rate_type", Message: "金利タイプはfixedかvariableのみです。"})
+	})
+
+	t.Run("error buildImportData 貯蓄目標のバリデーションエラー", func(t *testing.T) {
+		archive := importArchive{SavingsGoals: []models.SavingsGoalData{{
+			TargetAmount:   2000000,
+			TargetDate:     time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+			CurrentBalance: 500000,
+		}}}
+
+		_, validationErr := buildImportData(common.NewCommonFetcher(), 2, archive)
+
+		assert.NotNil(t, validationErr)
+		assert.Equal(t, 1, validationErr.RecodeRows)
+		assert.Contains(t, validationErr.Result, utils.ErrorMessages{Field: "savings_goals.goal_name", Message: "目標名は必須です。"})
+	})
+
+	t.Run("error buildImportData 有効なシナリオが複数ある", func(t *testing.T) {
+		archive := importArchive{PriceScenarios: []models.PriceScenarioData{
+			{ScenarioName: "基本", ActiveFlag: true},
+			{ScenarioName: "節約"},
+			{ScenarioName: "転職後", ActiveFlag: true},
+		}}
+
+		_, validationErr := buildImportData(common.NewCommonFetcher(), 2, archive)
+
+		assert.NotNil(t, validationErr)
+		assert.Equal(t, 3, validationErr.RecodeRows)
+		assert.Equal(t, []utils.ErrorMessages{
+			{Field: "price_scenarios.active_flag", Message: "有効なシナリオは1件のみ指定してください。"},
+		}, validationErr.Result)
+	})
+}
+
+func TestImportDataApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	importRequest := func(w *httptest.ResponseRecorder, fields map[string]string, file []byte, signInUserId int) *gin.Context {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		for key, value := range fields {
+			mw.WriteField(key, value)
+		}
+		if file != nil {
+			fw, _ := mw.CreateFormFile("file", "takuwaeru_export.zip")
+			fw.Write(file)
+		}
+		mw.Close()
+
+		req := httptest.NewRequest("POST", "/api/data_import", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		return signInContext(w, req, signInUserId, "session-id")
+	}
+
+	patchImportUserData := func(dryRun *bool, err error) *Patches {
+		return ApplyMethod(
+			reflect.TypeOf(&models.ExportDataFetcher{}),
+			"ImportUserData",
+			func(_ *models.ExportDataFetcher, UserId int, data models.ImportData, DryRun bool) (models.ImportReportData, error) {
+				*dryRun = DryRun
+				return models.ImportReportData{
+					DryRun:         DryRun,
+					Importable:     err == nil,
+					Income:         len(data.Income),
+					PriceScenarios: len(data.PriceScenarios),
+					Loans:          len(data.Loans),
+					SavingsGoals:   len(data.SavingsGoals),
+				}, err
+			})
+	}
+
+	t.Run("success ImportDataApi 指定がない場合はドライラン", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := importRequest(w, map[string]string{"user_id": "1"}, testImportArchive(t, models.ExportArchiveVersion), 1)
+
+		var dryRun bool
+		patches := patchImportUserData(&dryRun, nil)
+		defer patches.Reset()
+
+		fetcher := NewDataExportManagementFetcher(common.NewCommonFetcher(), nil, nil)
+		fetcher.ImportDataApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, dryRun)
+		assert.Contains(t, w.Body.String(), `"income":1`)
+		assert.Contains(t, w.Body.String(), `"price_scenarios":1`)
+		assert.Contains(t, w.Body.String(), `"loans":1`)
+		assert.Contains(t, w.Body.String(), `"savings_goals":1`)
+		assert.Contains(t, w.Body.String(), `"recode_rows":4`)
+	})
+
+	t.Run("success ImportDataApi 登録", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := importRequest(w, map[string]string{"user_id": "1", "dry_run": "false"}, testImportArchive(t, models.ExportArchiveVersion), 1)
+
+		dryRun := true
+		patches := patchImportUserData(&dryRun, nil)
+		defer patches.Reset()
+
+		fetcher := NewDataExportManagementFetcher(common.NewCommonFetcher(), nil, nil)
+		fetcher.ImportDataApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.False(t, dryRun)
+	})
+
+	t.Run("error ImportDataApi 登録データがある", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := importRequest(w, map[string]string{"user_id": "1", "dry_run": "false"}, testImportArchive(t, models.ExportArchiveVersion), 1)
+
+		var dryRun bool
+		patches := patchImportUserData(&dryRun, models.ErrImportNotEmpty)
+		defer patches.Reset()
+
+		fetcher := NewDataExportManagementFetcher(common.NewCommonFetcher(), nil, nil)
+		fetcher.ImportDataApi(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assertErrorMessage(t, w, models.ErrImportNotEmpty.Error())
+	})
+
+	t.Run("error ImportDataApi 対応していないバージョン", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := importRequest(w, map[string]string{"user_id": "1"}, testImportArchive(t, 99), 1)
+
+		fetcher := NewDataExportManagementFetcher(common.NewCommonFetcher(), nil, nil)
+		fetcher.ImportDataApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertErrorMessage(t, w, "対応していないファイル形式のバージョンです。(version: 99)")
+	})
+
+	t.Run("error ImportDataApi ファイルなし", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := importRequest(w, map[string]string{"user_id": "1"}, nil, 1)
+
+		fetcher := NewDataExportManagementFetcher(common.NewCommonFetcher(), nil, nil)
+		fetcher.ImportDataApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertErrorMessage(t, w, "インポートするファイルを指定してください。")
+	})
+
+	t.Run("error ImportDataApi サインインユーザーが異なる", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := importRequest(w, map[string]string{"user_id": "2"}, testImportArchive(t, models.ExportArchiveVersion), 1)
+
+		fetcher := NewDataExportManagementFetcher(common.NewCommonFetcher(), nil, nil)
+		fetcher.ImportDataApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("error ImportDataApi ドライランの値が不正", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := importRequest(w, map[string]string{"user_id": "1", "dry_run": "yes"}, nil, 1)
+
+		fetcher := NewDataExportManagementFetcher(common.NewCommonFetcher(), nil, nil)
+		fetcher.ImportDataApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
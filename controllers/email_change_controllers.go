@@ -0,0 +1,422 @@
+// controllers/email_change_controllers.go
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"server/common"
+	"server/config"
+	"server/models"
+	"server/utils"
+	"server/validation"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	RequestEmailChangeData struct {
+		UserId    interface{} `json:"user_id"`
+		UserEmail string      `json:"user_email"`
+	}
+
+	// emailChange は確認待ち(又は取り消し可能)のメールアドレス変更
+	emailChange struct {
+		UserId       int    `json:"user_id"`
+		CurrentEmail string `json:"current_email"`
+		NewEmail     string `json:"new_email"`
+		// 取り消し時に確認待ちのリンクも無効にするため保持する
+		ConfirmHash string `json:"confirm_hash"`
+	}
+)
+
+const emailChangeLinkMessage = "メールアドレス変更のリンクが無効か有効期限が切れています。"
+
+// emailChangeConfirmKey は変更の確認用トークンのハッシュから変更内容を引くRedisのキー
+func emailChangeConfirmKey(tokenHash string) string {
+	return fmt.Sprintf("email_change_confirm:%s", tokenHash)
+}
+
+// emailChangeCancelKey は変更の取り消し用トークンのハッシュから変更内容を引くRedisのキー
+func emailChangeCancelKey(tokenHash string) string {
+	return fmt.Sprintf("email_change_cancel:%s", tokenHash)
+}
+
+// emailChangeUserKey はユーザーの確認待ちの変更(確認用トークンのハッシュ)を保存するRedisのキー
+func emailChangeUserKey(UserId int) string {
+	return fmt.Sprintf("email_change_user:%d", UserId)
+}
+
+// issueEmailChange は確認用と取り消し用のトークンを発行し、ハッシュのみをRedisに保存する
+// 確認待ちの変更がある場合は以前の確認リンクを無効にする
+//
+// 引数:
+//   - change: 変更内容
+//
+// 戻り値:
+//
+//	戻り値1: 確認用のトークン
+//	戻り値2: 取り消し用のトークン
+//	戻り値3: エラー内容(エラーがない場合はnil)
+//
+
+func (af *apiSignDataFetcher) issueEmailChange(change emailChange) (string, string, error) {
+	confirmToken, err := utils.GenerateLinkToken()
+	if err != nil {
+		return "", "", err
+	}
+	cancelToken, err := utils.GenerateLinkToken()
+	if err != nil {
+		return "", "", err
+	}
+	change.ConfirmHash = utils.HashLinkToken(confirmToken)
+	confirmDuration := utils.EmailChangeConfirmMinutes * time.Minute
+
+	if previousHash, err := af.RedisService.RedisGet(emailChangeUserKey(change.UserId)); err == nil {
+		_ = af.RedisService.RedisDel(emailChangeConfirmKey(previousHash))
+	}
+	if err := af.RedisService.RedisSet(emailChangeConfirmKey(change.ConfirmHash), change, confirmDuration); err != nil {
+		return "", "", err
+	}
+	if err := af.RedisService.RedisSet(
+		emailChangeCancelKey(utils.HashLinkToken(cancelToken)),
+		change,
+		utils.EmailChangeCancelHours*time.Hour,
+	); err != nil {
+		return "", "", err
+	}
+	if err := af.RedisService.RedisSet(emailChangeUserKey(change.UserId), change.ConfirmHash, confirmDuration); err != nil {
+		return "", "", err
+	}
+	return confirmToken, cancelToken, nil
+}
+
+// consumeEmailChange はトークンに紐づく変更内容を取得し、トークンを使用済みにする
+func (af *apiSignDataFetcher) consumeEmailChange(key string) (emailChange, error) {
+	var change emailChange
+	value, err := af.RedisService.RedisGetDel(key)
+	if err != nil {
+		return change, err
+	}
+	if err := json.Unmarshal([]byte(value), &change); err != nil {
+		return change, err
+	}
+	return change, nil
+}
+
+// EmailChangeRequestApi はメールアドレスの変更を受け付けるAPI
+// 新しいメールアドレスへ確認リンクを、現在のメールアドレスへ取り消しリンク付きの通知を送信する
+// 確認リンクを開くまでメールアドレスは変更しない
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (af *apiSignDataFetcher) EmailChangeRequestApi(c *gin.Context) {
+	var requestData RequestEmailChangeData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userIdParams := common.AnyToStr(requestData.UserId)
+
+	validator := validation.RequestEmailChangeData{
+		UserId:    userIdParams,
+		UserEmail: requestData.UserEmail,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, _ := af.CommonFetcher.StrToInt(userIdParams)
+	if !isSignInUser(c, userId) {
+		signInUserMismatch(c)
+		return
+	}
+
+	accountFetcher, _, _ := models.NewSignDataFetcher(
+		config.GetDataBaseSource(),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+	)
+	account, err := accountFetcher.GetUserAccount(userId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// 外部認証のユーザーはメールアドレスで外部アカウントと紐づけている
+	if !account.PasswordAccount {
+		response := utils.ErrorMessageResponse{
+			Result: "外部認証で登録したユーザーはメールアドレスを変更できません。",
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if strings.EqualFold(account.UserEmail, requestData.UserEmail) {
+		response := utils.ErrorMessageResponse{
+			Result: "現在のメールアドレスと同じです。",
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	existsFetcher, _, _ := models.NewSignDataFetcher(
+		config.GetDataBaseSource(),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+	)
+	if _, err := existsFetcher.GetUserId(requestData.UserEmail); err == nil {
+		response := utils.ErrorMessageResponse{
+			Result: "このメールアドレスは既に登録されています。",
+		}
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+
+	confirmToken, cancelToken, err := af.issueEmailChange(emailChange{
+		UserId:       userId,
+		CurrentEmail: account.UserEmail,
+		NewEmail:     requestData.UserEmail,
+	})
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メールアドレス変更の受付に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	dateTime := af.UtilsFetcher.DateTimeStr(time.Now(), "2006年01月02日 15:04")
+
+	// 新しいメールアドレスへ確認リンクを送信
+	subject, body, err := af.EmailTemplateService.EmailChangeConfirmTemplate(
+		requestData.UserEmail,
+		fmt.Sprintf("%semail_change_confirm?token=%s", utils.GetBaseURL(), confirmToken),
+		dateTime,
+	)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メールテンプレート生成エラー(メールアドレス変更確認): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	if err := af.UtilsFetcher.SendMail(requestData.UserEmail, subject, body, true); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メール送信エラー(メールアドレス変更確認): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// 現在のメールアドレスへ取り消しリンク付きの通知を送信
+	subject, body, err = af.EmailTemplateService.EmailChangeNoticeTemplate(
+		requestData.UserEmail,
+		fmt.Sprintf("%semail_change_cancel?token=%s", utils.GetBaseURL(), cancelToken),
+		dateTime,
+	)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メールテンプレート生成エラー(メールアドレス変更通知): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	if err := af.UtilsFetcher.SendMail(account.UserEmail, subject, body, true); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メール送信エラー(メールアドレス変更通知): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "確認メールを送信しました。新しいメールアドレスに届いたリンクから変更を完了してください。",
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// EmailChangeConfirmApi は新しいメールアドレスに送信した確認リンクからメールアドレスを変更するAPI
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (af *apiSignDataFetcher) EmailChangeConfirmApi(c *gin.Context) {
+	token := c.Query("token")
+
+	validator := validation.RequestEmailChangeTokenData{
+		Token: token,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	change, err := af.consumeEmailChange(emailChangeConfirmKey(utils.HashLinkToken(token)))
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: emailChangeLinkMessage,
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+	_ = af.RedisService.RedisDel(emailChangeUserKey(change.UserId))
+
+	dbFetcher, _, _ := models.NewSignDataFetcher(
+		config.GetDataBaseSource(),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+	)
+	if err := dbFetcher.ChangeUserEmail(change.UserId, change.CurrentEmail, change.NewEmail); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+
+	subject, body, err := af.EmailTemplateService.PostSignInEditTemplate(
+		"メールアドレス更新",
+		change.NewEmail,
+		af.UtilsFetcher.DateTimeStr(time.Now(), "2006年01月02日 15:04"),
+	)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メールテンプレート生成エラー(更新): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	if err := af.UtilsFetcher.SendMail(change.NewEmail, subject, body, true); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メール送信エラー(更新): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "メールアドレスを変更しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// EmailChangeCancelApi は現在のメールアドレスに送信した取り消しリンクからメールアドレスの変更を取り消すAPI
+// 確認待ちの場合は確認リンクを無効にし、変更済みの場合は元のメールアドレスに戻す
+// 第三者による変更の可能性があるため、全ての端末をサインアウトさせる
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (af *apiSignDataFetcher) EmailChangeCancelApi(c *gin.Context) {
+	token := c.Query("token")
+
+	validator := validation.RequestEmailChangeTokenData{
+		Token: token,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	change, err := af.consumeEmailChange(emailChangeCancelKey(utils.HashLinkToken(token)))
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: emailChangeLinkMessage,
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+	_ = af.RedisService.RedisDel(emailChangeConfirmKey(change.ConfirmHash))
+
+	accountFetcher, _, _ := models.NewSignDataFetcher(
+		config.GetDataBaseSource(),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+	)
+	account, err := accountFetcher.GetUserAccount(change.UserId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// 変更済みの場合のみ元に戻す(その後さらに変更されている場合は変更しない)
+	if account.UserEmail == change.NewEmail {
+		dbFetcher, _, _ := models.NewSignDataFetcher(
+			config.GetDataBaseSource(),
+			utils.NewUtilsFetcher(utils.JwtKeys),
+		)
+		if err := dbFetcher.ChangeUserEmail(change.UserId, change.NewEmail, change.CurrentEmail); err != nil {
+			response := utils.ErrorMessageResponse{
+				Result: err.Error(),
+			}
+			c.JSON(http.StatusConflict, response)
+			return
+		}
+	}
+
+	if err := af.UtilsFetcher.RevokeUserTokens(change.UserId); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "トークンの失効に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	sessionFetcher, _, _ := models.NewSessionDataFetcher(config.GetDataBaseSource())
+	if _, err := sessionFetcher.RevokeOtherSessions(change.UserId, ""); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "セッションの失効に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	subject, body, err := af.EmailTemplateService.EmailChangeCanceledTemplate(
+		change.CurrentEmail,
+		af.UtilsFetcher.DateTimeStr(time.Now(), "2006年01月02日 15:04"),
+	)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メールテンプレート生成エラー(メールアドレス変更取り消し): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	if err := af.UtilsFetcher.SendMail(change.CurrentEmail, subject, body, true); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メール送信エラー(メールアドレス変更取り消し): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "メールアドレスの変更を取り消しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
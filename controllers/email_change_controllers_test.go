@@ -0,0 +1,367 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"server/common"
+	mock_config "server/mock/config"
+	mock_templates "server/mock/templates"
+	mock_utils "server/mock/utils"
+	"server/models"
+	"server/templates"
+	"server/utils"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// patchGetUserAccount は登録メールアドレスと認証方法の取得をモック化する
+func patchGetUserAccount(data models.UserAccountData, err error) *Patches {
+	return ApplyMethod(
+		reflect.TypeOf(&models.SignDataFetcher{}),
+		"GetUserAccount",
+		func(_ *models.SignDataFetcher, UserId int) (models.UserAccountData, error) {
+			return data, err
+		})
+}
+
+// patchChangeUserEmail はメールアドレスの変更をモック化し、変更内容を記録する
+func patchChangeUserEmail(patches *Patches, changed *[]string, err error) {
+	patches.ApplyMethod(
+		reflect.TypeOf(&models.SignDataFetcher{}),
+		"ChangeUserEmail",
+		func(_ *models.SignDataFetcher, UserId int, CurrentEmail, NewEmail string) error {
+			*changed = append(*changed, CurrentEmail+"->"+NewEmail)
+			return err
+		})
+}
+
+// linkToken はメールのリンクからトークンを取り出す
+func linkToken(link string) string {
+	return link[strings.Index(link, "token=")+len("token="):]
+}
+
+func testEmailChange() emailChange {
+	return emailChange{
+		UserId:       3,
+		CurrentEmail: "old@example.com",
+		NewEmail:     "new@example.com",
+		ConfirmHash:  "confirm_hash",
+	}
+}
+
+func TestEmailChangeRequestApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	passwordAccount := models.UserAccountData{UserId: 3, UserEmail: "old@example.com", PasswordAccount: true}
+
+	t.Run("EmailChangeRequestApi サインインユーザーが異なっています", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, nil, 2, testChallengeId)
+		totpRequest(c, "POST", "/api/email_change", map[string]interface{}{"user_id": 3, "user_email": "new@example.com"})
+
+		fetcher := apiSignDataFetcher{CommonFetcher: common.NewCommonFetcher()}
+		fetcher.EmailChangeRequestApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("EmailChangeRequestApi バリデーション メールアドレス不正", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, nil, 3, testChallengeId)
+		totpRequest(c, "POST", "/api/email_change", map[string]interface{}{"user_id": 3, "user_email": "new"})
+
+		fetcher := apiSignDataFetcher{CommonFetcher: common.NewCommonFetcher()}
+		fetcher.EmailChangeRequestApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var responseBody utils.ErrorValidationResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, []utils.ErrorMessages{
+			{Field: "user_email", Message: "正しいメールアドレス形式である必要があります。"},
+		}, responseBody.Result)
+	})
+
+	t.Run("EmailChangeRequestApi 外部認証のユーザー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, nil, 3, testChallengeId)
+		totpRequest(c, "POST", "/api/email_change", map[string]interface{}{"user_id": 3, "user_email": "new@example.com"})
+
+		patches := patchGetUserAccount(models.UserAccountData{UserId: 3, UserEmail: "old@example.com"}, nil)
+		defer patches.Reset()
+
+		fetcher := apiSignDataFetcher{CommonFetcher: common.NewCommonFetcher()}
+		fetcher.EmailChangeRequestApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var responseBody utils.ErrorMessageResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, "外部認証で登録したユーザーはメールアドレスを変更できません。", responseBody.Result)
+	})
+
+	t.Run("EmailChangeRequestApi 登録済みのメールアドレス", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, nil, 3, testChallengeId)
+		totpRequest(c, "POST", "/api/email_change", map[string]interface{}{"user_id": 3, "user_email": "new@example.com"})
+
+		patches := patchGetUserAccount(passwordAccount, nil)
+		defer patches.Reset()
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"GetUserId",
+			func(_ *models.SignDataFetcher, UserEmail string) (int, error) {
+				return 5, nil
+			})
+
+		fetcher := apiSignDataFetcher{CommonFetcher: common.NewCommonFetcher()}
+		fetcher.EmailChangeRequestApi(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("EmailChangeRequestApi 成功", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, nil, 3, testChallengeId)
+		totpRequest(c, "POST", "/api/email_change", map[string]interface{}{"user_id": "3", "user_email": "new@example.com"})
+
+		patches := patchGetUserAccount(passwordAccount, nil)
+		defer patches.Reset()
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"GetUserId",
+			func(_ *models.SignDataFetcher, UserEmail string) (int, error) {
+				return -1, fmt.Errorf("登録ユーザーが存在しません")
+			})
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// 確認待ちの変更は以前の確認リンクを無効にする
+		saved := map[string]interface{}{}
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGet("email_change_user:3").Return("previous_hash", nil)
+		mockRedisService.EXPECT().RedisDel("email_change_confirm:previous_hash").Return(nil)
+		mockRedisService.EXPECT().
+			RedisSet(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(key string, value interface{}, duration time.Duration) error {
+				saved[key] = value
+				return nil
+			}).
+			Times(3)
+
+		var confirmLink, cancelLink string
+		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
+		mockEmailTemplateService.EXPECT().
+			EmailChangeConfirmTemplate("new@example.com", gomock.Any(), "2024年12月2日").
+			DoAndReturn(func(UserEmail, Link, DateTime string) (string, string, error) {
+				confirmLink = Link
+				return "確認", "本文", nil
+			})
+		mockEmailTemplateService.EXPECT().
+			EmailChangeNoticeTemplate("new@example.com", gomock.Any(), "2024年12月2日").
+			DoAndReturn(func(UserEmail, Link, DateTime string) (string, string, error) {
+				cancelLink = Link
+				return "通知", "本文", nil
+			})
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().DateTimeStr(gomock.Any(), gomock.Any()).Return("2024年12月2日")
+		mockUtilsFetcher.EXPECT().SendMail("new@example.com", "確認", "本文", true).Return(nil)
+		mockUtilsFetcher.EXPECT().SendMail("old@example.com", "通知", "本文", true).Return(nil)
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mockUtilsFetcher,
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: mockEmailTemplateService,
+			RedisService:         mockRedisService,
+		}
+		fetcher.EmailChangeRequestApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// Redisにはトークンのハッシュのみを保存する
+		confirmHash := utils.HashLinkToken(linkToken(confirmLink))
+		expected := emailChange{
+			UserId:       3,
+			CurrentEmail: "old@example.com",
+			NewEmail:     "new@example.com",
+			ConfirmHash:  confirmHash,
+		}
+		assert.Equal(t, expected, saved["email_change_confirm:"+confirmHash])
+		assert.Equal(t, expected, saved["email_change_cancel:"+utils.HashLinkToken(linkToken(cancelLink))])
+		assert.Equal(t, confirmHash, saved["email_change_user:3"])
+	})
+}
+
+func TestEmailChangeConfirmApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	change := testEmailChange()
+	changeValue, _ := json.Marshal(change)
+
+	t.Run("EmailChangeConfirmApi 無効又は使用済みのトークン", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/email_change_confirm?token=token", nil)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisGetDel("email_change_confirm:"+utils.HashLinkToken("token")).
+			Return("", fmt.Errorf("キーが存在しません"))
+
+		fetcher := apiSignDataFetcher{RedisService: mockRedisService}
+		fetcher.EmailChangeConfirmApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var responseBody utils.ErrorMessageResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, "メールアドレス変更のリンクが無効か有効期限が切れています。", responseBody.Result)
+	})
+
+	t.Run("EmailChangeConfirmApi 変更先のメールアドレスが登録済み", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/email_change_confirm?token=token", nil)
+
+		var changed []string
+		patches := NewPatches()
+		defer patches.Reset()
+		patchChangeUserEmail(patches, &changed, fmt.Errorf("メールアドレスを変更できませんでした。"))
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGetDel(gomock.Any()).Return(string(changeValue), nil)
+		mockRedisService.EXPECT().RedisDel("email_change_user:3").Return(nil)
+
+		fetcher := apiSignDataFetcher{RedisService: mockRedisService}
+		fetcher.EmailChangeConfirmApi(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("EmailChangeConfirmApi 成功", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/email_change_confirm?token=token", nil)
+
+		var changed []string
+		patches := NewPatches()
+		defer patches.Reset()
+		patchChangeUserEmail(patches, &changed, nil)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGetDel(gomock.Any()).Return(string(changeValue), nil)
+		mockRedisService.EXPECT().RedisDel("email_change_user:3").Return(nil)
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().DateTimeStr(gomock.Any(), gomock.Any()).Return("2024年12月2日")
+		mockUtilsFetcher.EXPECT().SendMail("new@example.com", gomock.Any(), gomock.Any(), true).Return(nil)
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mockUtilsFetcher,
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mockRedisService,
+		}
+		fetcher.EmailChangeConfirmApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{"old@example.com->new@example.com"}, changed)
+	})
+}
+
+func TestEmailChangeCancelApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	change := testEmailChange()
+	changeValue, _ := json.Marshal(change)
+
+	cases := []struct {
+		name         string
+		currentEmail string
+		changed      []string
+	}{
+		{
+			name:         "EmailChangeCancelApi 確認前の取り消し",
+			currentEmail: "old@example.com",
+			changed:      nil,
+		},
+		{
+			name:         "EmailChangeCancelApi 確認後の取り消しは元のメールアドレスに戻す",
+			currentEmail: "new@example.com",
+			changed:      []string{"new@example.com->old@example.com"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/email_change_cancel?token=token", nil)
+
+			var changed []string
+			patches := patchGetUserAccount(models.UserAccountData{UserId: 3, UserEmail: tc.currentEmail, PasswordAccount: true}, nil)
+			defer patches.Reset()
+			patchChangeUserEmail(patches, &changed, nil)
+			sessionPatches := patchRevokeAllSessions(nil)
+			defer sessionPatches.Reset()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRedisService := mock_config.NewMockRedisService(ctrl)
+			mockRedisService.EXPECT().
+				RedisGetDel("email_change_cancel:"+utils.HashLinkToken("token")).
+				Return(string(changeValue), nil)
+			mockRedisService.EXPECT().RedisDel("email_change_confirm:confirm_hash").Return(nil)
+
+			// 全ての端末をサインアウトさせ、元のメールアドレスへ通知する
+			mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+			mockUtilsFetcher.EXPECT().RevokeUserTokens(3).Return(nil)
+			mockUtilsFetcher.EXPECT().DateTimeStr(gomock.Any(), gomock.Any()).Return("2024年12月2日")
+			mockUtilsFetcher.EXPECT().SendMail("old@example.com", gomock.Any(), gomock.Any(), true).Return(nil)
+
+			fetcher := apiSignDataFetcher{
+				UtilsFetcher:         mockUtilsFetcher,
+				EmailTemplateService: templates.NewEmailTemplateManager(),
+				RedisService:         mockRedisService,
+			}
+			fetcher.EmailChangeCancelApi(c)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tc.changed, changed)
+		})
+	}
+
+	t.Run("EmailChangeCancelApi バリデーション トークン必須", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/email_change_cancel", nil)
+
+		fetcher := apiSignDataFetcher{}
+		fetcher.EmailChangeCancelApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,171 @@
+// controllers/identity_controllers.go
+package controllers
+
+import (
+	"net/http"
+	"server/common"
+	"server/config"
+	"server/models"
+	"server/utils"
+	"server/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	IdentityManagementFetcher interface {
+		GetIdentityApi(c *gin.Context)
+		UnlinkIdentityApi(c *gin.Context)
+	}
+
+	requestUnlinkIdentityData struct {
+		UserId   interface{} `json:"user_id"`
+		Provider string      `json:"provider"`
+	}
+
+	apiIdentityManagementFetcher struct {
+		CommonFetcher common.CommonFetcher
+	}
+)
+
+func NewIdentityManagementFetcher(
+	CommonFetcher common.CommonFetcher,
+) IdentityManagementFetcher {
+	return &apiIdentityManagementFetcher{
+		CommonFetcher: CommonFetcher,
+	}
+}
+
+// findExternalUser は外部アカウントを連携しているユーザーを返す
+// 連携テーブルの追加前に外部認証で登録したユーザーは、メールアドレスで特定して連携する
+//
+// 引数:
+//   - provider: 外部認証の名称
+//   - providerId: 外部認証のユーザーID
+//   - providerEmail: 外部認証のメールアドレス
+//
+// 戻り値:
+//
+//	戻り値1: 連携しているユーザー
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func findExternalUser(provider, providerId, providerEmail string) (models.ExternalAuthData, error) {
+	identityFetcher, _, _ := models.NewIdentityDataFetcher(config.GetDataBaseSource())
+	result, err := identityFetcher.GetIdentityUser(provider, providerId)
+	if err == nil {
+		return result, nil
+	}
+
+	legacyFetcher, _, _ := models.NewIdentityDataFetcher(config.GetDataBaseSource())
+	result, err = legacyFetcher.GetLegacyExternalUser(provider, providerEmail)
+	if err != nil {
+		return result, err
+	}
+
+	linkFetcher, _, _ := models.NewIdentityDataFetcher(config.GetDataBaseSource())
+	err = linkFetcher.LinkIdentity(result.UserId, models.IdentityData{
+		Provider:        provider,
+		ProviderSubject: providerId,
+		ProviderEmail:   providerEmail,
+	})
+	return result, err
+}
+
+// GetIdentityApi は連携済みの外部認証の一覧を返すAPI
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (im *apiIdentityManagementFetcher) GetIdentityApi(c *gin.Context) {
+	// パラメータからユーザー情報取得
+	userIdPrams := c.Query("user_id")
+
+	validator := validation.RequestIdentityUserData{
+		UserId: userIdPrams,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, _ := im.CommonFetcher.StrToInt(userIdPrams)
+	if !isSignInUser(c, userId) {
+		signInUserMismatch(c)
+		return
+	}
+
+	dbFetcher, _, _ := models.NewIdentityDataFetcher(config.GetDataBaseSource())
+	identities, err := dbFetcher.GetIdentities(userId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// JSONレスポンスを返す
+	response := utils.ResponseData[[]models.IdentityData]{
+		RecodeRows: len(identities),
+		Result:     identities,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// UnlinkIdentityApi は外部アカウントの連携を解除するAPI
+// 最後のサインイン方法は解除できない
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (im *apiIdentityManagementFetcher) UnlinkIdentityApi(c *gin.Context) {
+	var requestData requestUnlinkIdentityData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userIdPrams := common.AnyToStr(requestData.UserId)
+	validator := validation.RequestIdentityUnlinkData{
+		UserId:   userIdPrams,
+		Provider: requestData.Provider,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, _ := im.CommonFetcher.StrToInt(userIdPrams)
+	if !isSignInUser(c, userId) {
+		signInUserMismatch(c)
+		return
+	}
+
+	dbFetcher, _, _ := models.NewIdentityDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.UnlinkIdentity(userId, requestData.Provider); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: requestData.Provider + "外部認証の連携を解除しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,283 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"server/common"
+	"server/models"
+	"server/utils"
+	"testing"
+	"time"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// patchGetIdentityUser は外部アカウントを連携しているユーザーの取得をモック化する
+func patchGetIdentityUser(data models.ExternalAuthData, err error) *Patches {
+	return ApplyMethod(
+		reflect.TypeOf(&models.IdentityDataFetcher{}),
+		"GetIdentityUser",
+		func(_ *models.IdentityDataFetcher, Provider, ProviderSubject string) (models.ExternalAuthData, error) {
+			return data, err
+		})
+}
+
+func TestFindExternalUser(t *testing.T) {
+
+	t.Run("success findExternalUser 連携済み", func(t *testing.T) {
+		patches := patchGetIdentityUser(models.ExternalAuthData{UserId: 1, UserEmail: "test@example.com"}, nil)
+		defer patches.Reset()
+
+		result, err := findExternalUser("google", "109876543210", "test@gmail.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.ExternalAuthData{UserId: 1, UserEmail: "test@example.com"}, result)
+	})
+
+	t.Run("success findExternalUser 連携前に登録したユーザーを連携", func(t *testing.T) {
+		var linked models.IdentityData
+		var linkedUserId int
+
+		patches := patchGetIdentityUser(models.ExternalAuthData{}, errors.New("連携された外部アカウントが存在しません。"))
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetLegacyExternalUser",
+			func(_ *models.IdentityDataFetcher, Provider, UserEmail string) (models.ExternalAuthData, error) {
+				return models.ExternalAuthData{UserId: 2, UserEmail: UserEmail}, nil
+			})
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"LinkIdentity",
+			func(_ *models.IdentityDataFetcher, UserId int, data models.IdentityData) error {
+				linkedUserId = UserId
+				linked = data
+				return nil
+			})
+		defer patches.Reset()
+
+		result, err := findExternalUser("line", "U1234567890", "test@line.me")
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.ExternalAuthData{UserId: 2, UserEmail: "test@line.me"}, result)
+		assert.Equal(t, 2, linkedUserId)
+		assert.Equal(t, models.IdentityData{
+			Provider:        "line",
+			ProviderSubject: "U1234567890",
+			ProviderEmail:   "test@line.me",
+		}, linked)
+	})
+
+	t.Run("error findExternalUser 連携なし", func(t *testing.T) {
+		patches := patchGetIdentityUser(models.ExternalAuthData{}, errors.New("連携された外部アカウントが存在しません。"))
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetLegacyExternalUser",
+			func(_ *models.IdentityDataFetcher, Provider, UserEmail string) (models.ExternalAuthData, error) {
+				return models.ExternalAuthData{}, errors.New("連携された外部アカウントが存在しません。")
+			})
+		defer patches.Reset()
+
+		_, err := findExternalUser("google", "109876543210", "test@gmail.com")
+
+		assert.EqualError(t, err, "連携された外部アカウントが存在しません。")
+	})
+
+	t.Run("error findExternalUser 連携の登録エラー", func(t *testing.T) {
+		patches := patchGetIdentityUser(models.ExternalAuthData{}, errors.New("連携された外部アカウントが存在しません。"))
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetLegacyExternalUser",
+			func(_ *models.IdentityDataFetcher, Provider, UserEmail string) (models.ExternalAuthData, error) {
+				return models.ExternalAuthData{UserId: 2, UserEmail: UserEmail}, nil
+			})
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"LinkIdentity",
+			func(_ *models.IdentityDataFetcher, UserId int, data models.IdentityData) error {
+				return errors.New("外部アカウントが他のユーザーに連携済みか、同じ外部認証が連携済みです。")
+			})
+		defer patches.Reset()
+
+		_, err := findExternalUser("google", "109876543210", "test@gmail.com")
+
+		assert.EqualError(t, err, "外部アカウントが他のユーザーに連携済みか、同じ外部認証が連携済みです。")
+	})
+}
+
+func TestGetIdentityApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success GetIdentityApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("GET", "/?user_id=1", nil), 1, "")
+
+		linkedAt := time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC)
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetIdentities",
+			func(_ *models.IdentityDataFetcher, UserId int) ([]models.IdentityData, error) {
+				return []models.IdentityData{
+					{Provider: "google", ProviderSubject: "109876543210", ProviderEmail: "test@gmail.com", LinkedAt: linkedAt},
+				}, nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiIdentityManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetIdentityApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response utils.ResponseData[[]models.IdentityData]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, response.RecodeRows)
+		assert.Equal(t, "google", response.Result[0].Provider)
+		assert.Equal(t, "109876543210", response.Result[0].ProviderSubject)
+	})
+
+	t.Run("error GetIdentityApi DB取得エラー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("GET", "/?user_id=1", nil), 1, "")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetIdentities",
+			func(_ *models.IdentityDataFetcher, UserId int) ([]models.IdentityData, error) {
+				return nil, errors.New("クエリー実行エラー： connection refused")
+			})
+		defer patches.Reset()
+
+		fetcher := apiIdentityManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetIdentityApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("error GetIdentityApi サインインユーザーが異なる", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("GET", "/?user_id=2", nil), 1, "")
+
+		fetcher := apiIdentityManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetIdentityApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("バリデーションエラー GetIdentityApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("GET", "/?user_id=a", nil), 1, "")
+
+		fetcher := apiIdentityManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetIdentityApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, []utils.ErrorMessages{
+			{Field: "user_id", Message: "ユーザーIDは整数値のみです。"},
+		}, response.Result)
+	})
+}
+
+func TestUnlinkIdentityApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	requestBody := requestUnlinkIdentityData{
+		UserId:   1,
+		Provider: "google",
+	}
+
+	t.Run("success UnlinkIdentityApi", func(t *testing.T) {
+		body, _ := json.Marshal(requestBody)
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("POST", "/", bytes.NewBuffer(body)), 1, "")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"UnlinkIdentity",
+			func(_ *models.IdentityDataFetcher, UserId int, Provider string) error {
+				return nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiIdentityManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.UnlinkIdentityApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("error UnlinkIdentityApi 最後のサインイン方法", func(t *testing.T) {
+		body, _ := json.Marshal(requestBody)
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("POST", "/", bytes.NewBuffer(body)), 1, "")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"UnlinkIdentity",
+			func(_ *models.IdentityDataFetcher, UserId int, Provider string) error {
+				return errors.New("最後のサインイン方法は解除できません。")
+			})
+		defer patches.Reset()
+
+		fetcher := apiIdentityManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.UnlinkIdentityApi(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		var response utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "最後のサインイン方法は解除できません。", response.Result)
+	})
+
+	t.Run("error UnlinkIdentityApi サインインユーザーが異なる", func(t *testing.T) {
+		body, _ := json.Marshal(requestBody)
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("POST", "/", bytes.NewBuffer(body)), 2, "")
+
+		fetcher := apiIdentityManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.UnlinkIdentityApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("バリデーションエラー UnlinkIdentityApi", func(t *testing.T) {
+		body, _ := json.Marshal(requestUnlinkIdentityData{UserId: 1})
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("POST", "/", bytes.NewBuffer(body)), 1, "")
+
+		fetcher := apiIdentityManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.UnlinkIdentityApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, []utils.ErrorMessages{
+			{Field: "provider", Message: "外部認証は必須です。"},
+		}, response.Result)
+	})
+}
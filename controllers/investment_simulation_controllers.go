@@ -0,0 +1,259 @@
+// controllers/investment_simulation_controllers.go
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"server/common"
+	"server/config"
+	"server/models"
+	"server/tax"
+	"server/utils"
+	"server/validation"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	InvestmentSimulationFetcher interface {
+		GetInvestmentSimulationApi(c *gin.Context)
+	}
+
+	InvestmentSimulationInput struct {
+		Years         int
+		NisaTsumitate int
+		NisaGrowth    int
+		Ideco         int
+		IdecoCategory string
+		AnnualReturn  float64
+	}
+
+	InvestmentSimulationRow struct {
+		Year                   int `json:"year"`
+		NisaTsumitate          int `json:"nisa_tsumitate"`
+		NisaGrowth             int `json:"nisa_growth"`
+		NisaContributionTotal  int `json:"nisa_contribution_total"`
+		NisaLifetimeRemaining  int `json:"nisa_lifetime_remaining"`
+		NisaBalance            int `json:"nisa_balance"`
+		IdecoContribution      int `json:"ideco_contribution"`
+		IdecoContributionTotal int `json:"ideco_contribution_total"`
+		IdecoBalance           int `json:"ideco_balance"`
+		IdecoTaxSaving         int `json:"ideco_tax_saving"`
+		IdecoTaxSavingTotal    int `json:"ideco_tax_saving_total"`
+	}
+
+	InvestmentSimulation struct {
+		AnnualIncome      int                       `json:"annual_income"`
+		IdecoMonthlyLimit int                       `json:"ideco_monthly_limit"`
+		Series            []InvestmentSimulationRow `json:"series"`
+	}
+
+	apiInvestmentSimulationFetcher struct {
+		CommonFetcher common.CommonFetcher
+	}
+)
+
+const (
+	// NISAのつみたて投資枠の年間上限
+	NisaTsumitateAnnualLimit = 1200000
+	// NISAの成長投資枠の年間上限
+	NisaGrowthAnnualLimit = 2400000
+	// NISAの生涯投資枠(簿価)
+	NisaLifetimeLimit = 18000000
+	// 生涯投資枠のうち成長投資枠で使える上限
+	NisaGrowthLifetimeLimit = 12000000
+)
+
+// iDeCoの加入区分毎の掛金の月額上限
+var idecoMonthlyLimits = map[string]int{
+	"employee":      23000,
+	"corporate_dc":  20000,
+	"civil_servant": 20000,
+	"self_employed": 68000,
+	"housewife":     23000,
+}
+
+func NewInvestmentSimulationFetcher(CommonFetcher common.CommonFetcher) InvestmentSimulationFetcher {
+	return &apiInvestmentSimulationFetcher{
+		CommonFetcher: CommonFetcher,
+	}
+}
+
+// growBalance は残高に年間の積立額を毎月均等に積み立て、月複利で1年運用した残高を返す
+func growBalance(balance float64, annualContribution int, monthlyRate float64) float64 {
+	monthly := float64(annualContribution) / 12
+	for month := 0; month < 12; month++ {
+		balance = balance*(1+monthlyRate) + monthly
+	}
+	return balance
+}
+
+// calcInvestmentSimulation はNISAとiDeCoの積立を年単位でシミュレーションする。
+// NISAは年間投資枠と生涯投資枠(成長投資枠は1,200万円まで)を超える分は積み立てない。
+// iDeCoは加入区分の上限を超える分は積み立てず、掛金の所得控除による節税額を年収から算出する。
+//
+// 引数:
+//   - input: 積立条件
+//   - table: 節税額の算出に使う料率表
+//   - annualIncome: 給与の年収
+//   - startYear: 開始年
+//
+// 戻り値:
+//   - InvestmentSimulation: 年毎のシミュレーション結果
+
+func calcInvestmentSimulation(input InvestmentSimulationInput, table tax.RateTable, annualIncome int, startYear int) InvestmentSimulation {
+	monthlyRate := input.AnnualReturn / 100 / 12
+	idecoLimit := idecoMonthlyLimits[input.IdecoCategory]
+	idecoAnnual := min(input.Ideco, idecoLimit) * 12
+	socialInsurance := int(float64(annualIncome) * tax.SocialInsuranceRate)
+
+	simulation := InvestmentSimulation{
+		AnnualIncome:      annualIncome,
+		IdecoMonthlyLimit: idecoLimit,
+		Series:            []InvestmentSimulationRow{},
+	}
+
+	var nisaBalance, idecoBalance float64
+	var nisaUsed, nisaGrowthUsed, idecoTotal, taxSavingTotal int
+
+	for idx := 0; idx < input.Years; idx++ {
+		tsumitate := min(input.NisaTsumitate*12, NisaTsumitateAnnualLimit)
+		growth := min(input.NisaGrowth*12, NisaGrowthAnnualLimit, NisaGrowthLifetimeLimit-nisaGrowthUsed)
+		tsumitate = min(tsumitate, NisaLifetimeLimit-nisaUsed)
+		growth = min(growth, NisaLifetimeLimit-nisaUsed-tsumitate)
+
+		nisaUsed += tsumitate + growth
+		nisaGrowthUsed += growth
+		nisaBalance = growBalance(nisaBalance, tsumitate+growth, monthlyRate)
+
+		idecoTotal += idecoAnnual
+		idecoBalance = growBalance(idecoBalance, idecoAnnual, monthlyRate)
+		taxSaving := table.IdecoTaxSaving(annualIncome, socialInsurance, idecoAnnual)
+		taxSavingTotal += taxSaving
+
+		simulation.Series = append(simulation.Series, InvestmentSimulationRow{
+			Year:                   startYear + idx,
+			NisaTsumitate:          tsumitate,
+			NisaGrowth:             growth,
+			NisaContributionTotal:  nisaUsed,
+			NisaLifetimeRemaining:  NisaLifetimeLimit - nisaUsed,
+			NisaBalance:            int(math.Round(nisaBalance)),
+			IdecoContribution:      idecoAnnual,
+			IdecoContributionTotal: idecoTotal,
+			IdecoBalance:           int(math.Round(idecoBalance)),
+			IdecoTaxSaving:         taxSaving,
+			IdecoTaxSavingTotal:    taxSavingTotal,
+		})
+	}
+
+	return simulation
+}
+
+// completedYearIncome は節税額の算出に使う年収を返す。
+// 今年以降の年は支給が途中までの集計のため、前年以前で直近の年の総支給額を使う。
+// 前年以前の登録がない場合は、登録されている直近の年の総支給額を使う。
+//
+// 引数:
+//   - yearsIncome: 年の昇順の年毎の総支給額
+//   - currentYear: 今年
+//
+// 戻り値:
+//   - int: 年収
+
+func completedYearIncome(yearsIncome []models.YearsIncomeData, currentYear int) int {
+	for idx := len(yearsIncome) - 1; idx >= 0; idx-- {
+		year, err := strconv.Atoi(yearsIncome[idx].Years)
+		if err == nil && year < currentYear {
+			return yearsIncome[idx].TotalAmount
+		}
+	}
+	return yearsIncome[len(yearsIncome)-1].TotalAmount
+}
+
+// GetInvestmentSimulationApi はNISAとiDeCoの積立シミュレーション結果を年毎に返すAPI
+// iDeCoの節税額は income_forecast_data に登録された前年以前で直近の年の総支給額を年収として算出する
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+// 期待するURL:
+//
+//	GET /investment_simulation?user_id=1&years=20&nisa_tsumitate=50000&nisa_growth=0&ideco=23000&ideco_category=employee&annual_return=3
+//
+
+func (is *apiInvestmentSimulationFetcher) GetInvestmentSimulationApi(c *gin.Context) {
+
+	validator := validation.RequestInvestmentSimulationData{
+		UserId:        c.Query("user_id"),
+		Years:         c.Query("years"),
+		NisaTsumitate: c.Query("nisa_tsumitate"),
+		NisaGrowth:    c.Query("nisa_growth"),
+		Ideco:         c.Query("ideco"),
+		IdecoCategory: c.DefaultQuery("ideco_category", "employee"),
+		AnnualReturn:  c.Query("annual_return"),
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	data, err := is.CommonFetcher.IntgetPrameter(c, "user_id", "years", "nisa_tsumitate", "nisa_growth", "ideco")
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	annualReturn, _ := strconv.ParseFloat(c.DefaultQuery("annual_return", "0"), 64)
+
+	dbFetcher, _, _ := models.NewAnnualIncomeDataFetcher(config.GetDataBaseSource())
+	yearsIncome, err := dbFetcher.GetYearsIncomeAndDeduction(data["user_id"])
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if len(yearsIncome) == 0 {
+		response := utils.ErrorMessageResponse{
+			Result: "年収データが登録されていません。",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	now := time.Now()
+	annualIncome := completedYearIncome(yearsIncome, now.Year())
+
+	table, err := tax.LookupRateTable(now.Year())
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	input := InvestmentSimulationInput{
+		Years:         data["years"],
+		NisaTsumitate: data["nisa_tsumitate"],
+		NisaGrowth:    data["nisa_growth"],
+		Ideco:         data["ideco"],
+		IdecoCategory: validator.IdecoCategory,
+		AnnualReturn:  annualReturn,
+	}
+
+	response := utils.ResponseData[InvestmentSimulation]{
+		Result: calcInvestmentSimulation(input, table, annualIncome, now.Year()),
+	}
+	c.JSON(http.StatusOK, response)
+}
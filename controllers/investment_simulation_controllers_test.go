@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"server/common"
+	"server/models"
+	"server/tax"
+	"server/utils"
+	"testing"
+	"time"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalcInvestmentSimulation(t *testing.T) {
+	table, _ := tax.LookupRateTable(2024)
+
+	t.Run("success NISAの生涯投資枠で積立が止まる", func(t *testing.T) {
+		input := InvestmentSimulationInput{
+			Years:         7,
+			NisaTsumitate: 150000,
+			NisaGrowth:    200000,
+			IdecoCategory: "employee",
+		}
+
+		result := calcInvestmentSimulation(input, table, 5000000, 2024)
+
+		assert.Len(t, result.Series, 7)
+		assert.Equal(t, 2024, result.Series[0].Year)
+		// つみたて投資枠は年間120万円が上限
+		assert.Equal(t, NisaTsumitateAnnualLimit, result.Series[0].NisaTsumitate)
+		assert.Equal(t, NisaGrowthAnnualLimit, result.Series[0].NisaGrowth)
+		// 5年で生涯投資枠1,800万円に到達
+		assert.Equal(t, NisaLifetimeLimit, result.Series[4].NisaContributionTotal)
+		assert.Equal(t, 0, result.Series[4].NisaLifetimeRemaining)
+		assert.Equal(t, 0, result.Series[5].NisaTsumitate+result.Series[5].NisaGrowth)
+		// 利回り0%の場合、残高は積立額と一致する
+		assert.Equal(t, NisaLifetimeLimit, result.Series[6].NisaBalance)
+	})
+
+	t.Run("success 成長投資枠は生涯1,200万円まで", func(t *testing.T) {
+		input := InvestmentSimulationInput{
+			Years:         6,
+			NisaGrowth:    200000,
+			IdecoCategory: "employee",
+		}
+
+		result := calcInvestmentSimulation(input, table, 5000000, 2024)
+
+		assert.Equal(t, NisaGrowthLifetimeLimit, result.Series[5].NisaContributionTotal)
+		assert.Equal(t, 0, result.Series[5].NisaGrowth)
+	})
+
+	t.Run("success iDeCoの上限と節税額", func(t *testing.T) {
+		input := InvestmentSimulationInput{
+			Years:         2,
+			Ideco:         30000,
+			IdecoCategory: "employee",
+			AnnualReturn:  3,
+		}
+
+		result := calcInvestmentSimulation(input, table, 5000000, 2024)
+
+		assert.Equal(t, 23000, result.IdecoMonthlyLimit)
+		assert.Equal(t, 276000, result.Series[0].IdecoContribution)
+		assert.Equal(t, 55800, result.Series[0].IdecoTaxSaving)
+		assert.Equal(t, 111600, result.Series[1].IdecoTaxSavingTotal)
+		assert.Equal(t, 552000, result.Series[1].IdecoContributionTotal)
+		// 運用益の分だけ残高が積立額を上回る
+		assert.Greater(t, result.Series[1].IdecoBalance, 552000)
+	})
+}
+
+func TestCompletedYearIncome(t *testing.T) {
+	yearsIncome := []models.YearsIncomeData{
+		{Years: "2023", TotalAmount: 4000000},
+		{Years: "2024", TotalAmount: 5000000},
+		{Years: "2025", TotalAmount: 1200000},
+	}
+
+	t.Run("success 今年は支給途中のため前年の年収を使う", func(t *testing.T) {
+		assert.Equal(t, 5000000, completedYearIncome(yearsIncome, 2025))
+	})
+
+	t.Run("success 前年以前の登録がない場合は直近の年収を使う", func(t *testing.T) {
+		assert.Equal(t, 1200000, completedYearIncome(yearsIncome[2:], 2025))
+	})
+}
+
+func TestGetInvestmentSimulationApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success GetInvestmentSimulationApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?user_id=1&years=3&nisa_tsumitate=50000&ideco=23000&ideco_category=employee&annual_return=3", nil)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.AnnualIncomeDataFetcher{}),
+			"GetYearsIncomeAndDeduction",
+			func(_ *models.AnnualIncomeDataFetcher, userId int) ([]models.YearsIncomeData, error) {
+				return []models.YearsIncomeData{
+					{Years: "2023", TotalAmount: 4000000},
+					{Years: "2024", TotalAmount: 5000000},
+					// 今年は支給途中のため年収に使わない
+					{Years: fmt.Sprint(time.Now().Year()), TotalAmount: 800000},
+				}, nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiInvestmentSimulationFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetInvestmentSimulationApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response utils.ResponseData[InvestmentSimulation]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		// 直近の年の年収を使う
+		assert.Equal(t, 5000000, response.Result.AnnualIncome)
+		assert.Len(t, response.Result.Series, 3)
+		assert.Equal(t, time.Now().Year(), response.Result.Series[0].Year)
+		assert.Equal(t, 55800, response.Result.Series[0].IdecoTaxSaving)
+	})
+
+	t.Run("error GetInvestmentSimulationApi 年収データなし", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?user_id=1&years=3", nil)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.AnnualIncomeDataFetcher{}),
+			"GetYearsIncomeAndDeduction",
+			func(_ *models.AnnualIncomeDataFetcher, userId int) ([]models.YearsIncomeData, error) {
+				return nil, nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiInvestmentSimulationFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetInvestmentSimulationApi(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var response utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "年収データが登録されていません。", response.Result)
+	})
+
+	t.Run("error GetInvestmentSimulationApi DBエラー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?user_id=1&years=3", nil)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.AnnualIncomeDataFetcher{}),
+			"GetYearsIncomeAndDeduction",
+			func(_ *models.AnnualIncomeDataFetcher, userId int) ([]models.YearsIncomeData, error) {
+				return nil, errors.New("database error")
+			})
+		defer patches.Reset()
+
+		fetcher := apiInvestmentSimulationFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetInvestmentSimulationApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("バリデーションエラー GetInvestmentSimulationApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?user_id=1&years=51&ideco=-1&ideco_category=student&annual_return=25", nil)
+
+		fetcher := apiInvestmentSimulationFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetInvestmentSimulationApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []utils.ErrorMessages{
+			{Field: "ideco_category", Message: "iDeCoの加入区分が正しくありません。"},
+			{Field: "years", Message: "シミュレーション年数は1以上50以下の整数値のみです。"},
+			{Field: "ideco", Message: "iDeCoの月額は0以上の整数値のみです。"},
+			{Field: "annual_return", Message: "想定利回りは0以上20以下の数値のみです。"},
+		}, response.Result)
+	})
+}
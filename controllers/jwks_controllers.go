@@ -0,0 +1,45 @@
+// controllers/jwks_controllers.go
+package controllers
+
+import (
+	"net/http"
+	"server/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	JwksFetcher interface {
+		GetJwksApi(c *gin.Context)
+	}
+
+	apiJwksFetcher struct {
+		KeyManager *utils.KeyManager
+	}
+)
+
+// JWKSをキャッシュしてよい秒数
+// 鍵のローテーション時は新しい鍵をこの時間以上前から公開しておく
+const jwksMaxAge = "max-age=300"
+
+func NewJwksFetcher(KeyManager *utils.KeyManager) JwksFetcher {
+	return &apiJwksFetcher{
+		KeyManager: KeyManager,
+	}
+}
+
+// GetJwksApi はトークンの検証に使う公開鍵をJWKS(RFC 7517)の形式で返すAPI
+// HS256の共有鍵は公開しない
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+// 期待するURL:
+//
+//	GET /.well-known/jwks.json
+//
+
+func (jf *apiJwksFetcher) GetJwksApi(c *gin.Context) {
+	c.Header("Cache-Control", jwksMaxAge)
+	c.JSON(http.StatusOK, jf.KeyManager.JWKS())
+}
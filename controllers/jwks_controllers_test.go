@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"server/utils"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetJwksApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success GetJwksApi 公開鍵のみ返す", func(t *testing.T) {
+		_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+		keyManager, _ := utils.NewKeyManager("ed-1",
+			utils.NewEd25519Key("ed-1", edKey),
+			utils.NewHMACKey(utils.DefaultJwtKid, []byte("secret")),
+		)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+
+		fetcher := NewJwksFetcher(keyManager)
+		fetcher.GetJwksApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "max-age=300", w.Header().Get("Cache-Control"))
+
+		var response utils.JSONWebKeySet
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Keys, 1)
+		assert.Equal(t, "ed-1", response.Keys[0].Kid)
+		assert.Equal(t, "EdDSA", response.Keys[0].Alg)
+	})
+}
@@ -0,0 +1,506 @@
+// controllers/loan_controllers.go
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"server/common"
+	"server/config"
+	"server/models"
+	"server/utils"
+	"server/validation"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	LoanManagementFetcher interface {
+		GetLoanApi(c *gin.Context)
+		InsertLoanApi(c *gin.Context)
+		UpdateLoanApi(c *gin.Context)
+		DeleteLoanApi(c *gin.Context)
+		GetLoanScheduleApi(c *gin.Context)
+	}
+
+	requestInsertLoanData struct {
+		Data []models.InsertLoanData `json:"data"`
+	}
+
+	requestUpdateLoanData struct {
+		Data []models.UpdateLoanData `json:"data"`
+	}
+
+	requestDeleteLoanData struct {
+		Data []models.DeleteLoanData `json:"data"`
+	}
+
+	requestLoanScheduleData struct {
+		LoanId      string           `json:"loan_id"`
+		UserId      interface{}      `json:"user_id"`
+		Prepayments []LoanPrepayment `json:"prepayments"`
+	}
+
+	LoanPrepayment struct {
+		Month  int    `json:"month"`
+		Amount int    `json:"amount"`
+		Type   string `json:"type"`
+	}
+
+	LoanScheduleRow struct {
+		Number      int    `json:"number"`
+		PaymentDate string `json:"payment_date"`
+		Payment     int    `json:"payment"`
+		Principal   int    `json:"principal"`
+		Interest    int    `json:"interest"`
+		Prepayment  int    `json:"prepayment"`
+		Balance     int    `json:"balance"`
+	}
+
+	LoanSchedule struct {
+		LoanId          string            `json:"loan_id"`
+		RepaymentMethod string            `json:"repayment_method"`
+		MonthlyPayment  int               `json:"monthly_payment"`
+		PaymentCount    int               `json:"payment_count"`
+		TotalPayment    int               `json:"total_payment"`
+		TotalInterest   int               `json:"total_interest"`
+		InterestSaved   int               `json:"interest_saved"`
+		MonthsSaved     int               `json:"months_saved"`
+		Schedule        []LoanScheduleRow `json:"schedule"`
+	}
+
+	apiLoanManagementFetcher struct {
+		CommonFetcher common.CommonFetcher
+	}
+)
+
+const (
+	EqualPayment       = "equal_payment"
+	EqualPrincipal     = "equal_principal"
+	PrepaymentShorten  = "shorten"
+	PrepaymentReduce   = "reduce"
+	loanScheduleFormat = "2006-01-02"
+)
+
+func NewLoanManagementFetcher(CommonFetcher common.CommonFetcher) LoanManagementFetcher {
+	return &apiLoanManagementFetcher{
+		CommonFetcher: CommonFetcher,
+	}
+}
+
+// equalPaymentAmount は元利均等返済の毎月の返済額を返す
+func equalPaymentAmount(balance int, monthlyRate float64, months int) int {
+	if months <= 0 {
+		return balance
+	}
+	if monthlyRate == 0 {
+		return int(math.Ceil(float64(balance) / float64(months)))
+	}
+	pow := math.Pow(1+monthlyRate, float64(months))
+	return int(math.Round(float64(balance) * monthlyRate * pow / (pow - 1)))
+}
+
+// equalPrincipalAmount は元金均等返済の毎月の元金部分を返す
+func equalPrincipalAmount(balance int, months int) int {
+	if months <= 0 {
+		return balance
+	}
+	return int(math.Ceil(float64(balance) / float64(months)))
+}
+
+// loanPaymentDate は返済開始日から number か月後の返済日を返す
+// 返済開始日が月末の場合、日付のない月(2月30日等)は翌月に繰り越さずその月の末日とする
+func loanPaymentDate(startDate time.Time, number int) time.Time {
+	firstDay := time.Date(startDate.Year(), startDate.Month()+time.Month(number), 1, 0, 0, 0, 0, startDate.Location())
+	lastDay := firstDay.AddDate(0, 1, -1).Day()
+	return firstDay.AddDate(0, 0, min(startDate.Day(), lastDay)-1)
+}
+
+// calcLoanSchedule はローン情報と繰上返済の内容から返済予定表を作成する。
+// 利息は1円未満切り捨て、最終回で残高を精算する。
+// 変動金利の場合は登録されている現在の金利で試算する。
+//
+// 引数:
+//   - loan: ローン情報
+//   - prepayments: 繰上返済(返済回数の支払い後に充当する)
+//
+// 戻り値:
+//   - LoanSchedule: 返済予定表
+
+func calcLoanSchedule(loan models.LoanData, prepayments []LoanPrepayment) LoanSchedule {
+	monthlyRate := loan.AnnualRate / 100 / 12
+	balance := loan.Principal
+	payment := equalPaymentAmount(balance, monthlyRate, loan.TermMonths)
+	principalPart := equalPrincipalAmount(balance, loan.TermMonths)
+
+	prepaymentMap := map[int]LoanPrepayment{}
+	for _, prepayment := range prepayments {
+		prepaymentMap[prepayment.Month] = prepayment
+	}
+
+	schedule := LoanSchedule{
+		LoanId:          loan.LoanId.String(),
+		RepaymentMethod: loan.RepaymentMethod,
+		Schedule:        []LoanScheduleRow{},
+	}
+
+	for number := 1; number <= loan.TermMonths && balance > 0; number++ {
+		interest := int(math.Floor(float64(balance) * monthlyRate))
+
+		var principal int
+		if loan.RepaymentMethod == EqualPrincipal {
+			principal = principalPart
+		} else {
+			principal = payment - interest
+		}
+		if principal > balance || number == loan.TermMonths {
+			principal = balance
+		}
+		balance -= principal
+
+		row := LoanScheduleRow{
+			Number:      number,
+			PaymentDate: loanPaymentDate(loan.StartDate, number).Format(loanScheduleFormat),
+			Payment:     principal + interest,
+			Principal:   principal,
+			Interest:    interest,
+		}
+
+		if prepayment, ok := prepaymentMap[number]; ok && balance > 0 {
+			amount := min(prepayment.Amount, balance)
+			balance -= amount
+			row.Prepayment = amount
+
+			// 返済額軽減型は残りの期間で返済額を再計算する
+			// 期間短縮型は返済額を変えずに完済を早める
+			if prepayment.Type == PrepaymentReduce {
+				left := loan.TermMonths - number
+				payment = equalPaymentAmount(balance, monthlyRate, left)
+				principalPart = equalPrincipalAmount(balance, left)
+			}
+		}
+		row.Balance = balance
+
+		schedule.TotalPayment += row.Payment + row.Prepayment
+		schedule.TotalInterest += row.Interest
+		schedule.Schedule = append(schedule.Schedule, row)
+	}
+
+	schedule.PaymentCount = len(schedule.Schedule)
+	if schedule.PaymentCount > 0 {
+		schedule.MonthlyPayment = schedule.Schedule[0].Payment
+	}
+
+	return schedule
+}
+
+// currentLoanPayment は対象月に支払うローン返済額の合計を返す
+//
+// 引数:
+//   - loans: ローン情報
+//   - now: 対象月
+//
+// 戻り値:
+//   - int: 対象月の返済額合計
+
+func currentLoanPayment(loans []models.LoanData, now time.Time) int {
+	var total int
+	for _, loan := range loans {
+		for _, row := range calcLoanSchedule(loan, nil).Schedule {
+			if row.PaymentDate[:7] == now.Format("2006-01") {
+				total += row.Payment
+				break
+			}
+		}
+	}
+	return total
+}
+
+// GetLoanApi は登録済みのローン一覧を返すAPI
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (lm *apiLoanManagementFetcher) GetLoanApi(c *gin.Context) {
+	// パラメータからユーザー情報取得
+	userIdPrams := c.Query("user_id")
+
+	validator := validation.RequestDateRangeData{
+		UserId: userIdPrams,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, _ := lm.CommonFetcher.StrToInt(userIdPrams)
+
+	dbFetcher, _, _ := models.NewLoanDataFetcher(config.GetDataBaseSource())
+	loans, err := dbFetcher.GetLoans(userId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// JSONレスポンスを返す
+	response := utils.ResponseData[[]models.LoanData]{
+		Result: loans,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// InsertLoanApi はローンの新規登録API
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (lm *apiLoanManagementFetcher) InsertLoanApi(c *gin.Context) {
+	var requestData requestInsertLoanData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if len(requestData.Data) == 0 {
+		response := utils.ErrorMessageResponse{
+			Result: "登録するデータが存在しません。",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	for idx, data := range requestData.Data {
+		validator := validation.RequestInsertLoanData{
+			LoanName:        data.LoanName,
+			Principal:       common.AnyToStr(data.Principal),
+			AnnualRate:      strconv.FormatFloat(data.AnnualRate, 'f', -1, 64),
+			TermMonths:      common.AnyToStr(data.TermMonths),
+			StartDate:       data.StartDate,
+			RateType:        data.RateType,
+			RepaymentMethod: data.RepaymentMethod,
+			UserId:          common.AnyToStr(data.UserId),
+		}
+		if valid, errMsgList := validator.Validate(); !valid {
+			response := utils.ErrorValidationResponse{
+				RecodeRows: idx + 1,
+				Result:     errMsgList,
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	dbFetcher, _, _ := models.NewLoanDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.InsertLoan(requestData.Data); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "ローン登録時にエラーが発生。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "ローン情報を登録致しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateLoanApi はローンの更新API
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (lm *apiLoanManagementFetcher) UpdateLoanApi(c *gin.Context) {
+	var requestData requestUpdateLoanData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if len(requestData.Data) == 0 {
+		response := utils.ErrorMessageResponse{
+			Result: "更新するデータが存在しません。",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	for idx, data := range requestData.Data {
+		validator := validation.RequestUpdateLoanData{
+			LoanId:          data.LoanId,
+			LoanName:        data.LoanName,
+			Principal:       common.AnyToStr(data.Principal),
+			AnnualRate:      strconv.FormatFloat(data.AnnualRate, 'f', -1, 64),
+			TermMonths:      common.AnyToStr(data.TermMonths),
+			StartDate:       data.StartDate,
+			RateType:        data.RateType,
+			RepaymentMethod: data.RepaymentMethod,
+			UserId:          common.AnyToStr(data.UserId),
+		}
+		if valid, errMsgList := validator.Validate(); !valid {
+			response := utils.ErrorValidationResponse{
+				RecodeRows: idx + 1,
+				Result:     errMsgList,
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	dbFetcher, _, _ := models.NewLoanDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.UpdateLoan(requestData.Data); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "ローン更新時にエラーが発生。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "ローン情報の更新が問題なく成功しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteLoanApi はローンの削除API
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (lm *apiLoanManagementFetcher) DeleteLoanApi(c *gin.Context) {
+	var requestData requestDeleteLoanData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if len(requestData.Data) == 0 {
+		response := utils.ErrorMessageResponse{
+			Result: "削除するデータが存在しません。",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	for idx, data := range requestData.Data {
+		validator := validation.RequestDeleteLoanData{
+			LoanId: data.LoanId,
+			UserId: common.AnyToStr(data.UserId),
+		}
+		if valid, errMsgList := validator.Validate(); !valid {
+			response := utils.ErrorValidationResponse{
+				RecodeRows: idx + 1,
+				Result:     errMsgList,
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	dbFetcher, _, _ := models.NewLoanDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.DeleteLoan(requestData.Data); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "ローン削除中にエラーが発生しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "ローン情報の削除が問題なく成功しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetLoanScheduleApi はローンの返済予定表を返すAPI
+// 繰上返済を指定した場合は、繰上返済なしの場合と比較した利息軽減額も返す
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (lm *apiLoanManagementFetcher) GetLoanScheduleApi(c *gin.Context) {
+	var requestData requestLoanScheduleData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userIdPrams := common.AnyToStr(requestData.UserId)
+	validator := validation.RequestLoanScheduleData{
+		LoanId: requestData.LoanId,
+		UserId: userIdPrams,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	for idx, prepayment := range requestData.Prepayments {
+		prepaymentValidator := validation.RequestLoanPrepaymentData{
+			Month:  common.AnyToStr(prepayment.Month),
+			Amount: common.AnyToStr(prepayment.Amount),
+			Type:   prepayment.Type,
+		}
+		if valid, errMsgList := prepaymentValidator.Validate(); !valid {
+			response := utils.ErrorValidationResponse{
+				RecodeRows: idx + 1,
+				Result:     errMsgList,
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	userId, _ := lm.CommonFetcher.StrToInt(userIdPrams)
+
+	dbFetcher, _, _ := models.NewLoanDataFetcher(config.GetDataBaseSource())
+	loan, err := dbFetcher.GetLoan(requestData.LoanId, userId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	schedule := calcLoanSchedule(loan, requestData.Prepayments)
+	if len(requestData.Prepayments) > 0 {
+		base := calcLoanSchedule(loan, nil)
+		schedule.InterestSaved = base.TotalInterest - schedule.TotalInterest
+		schedule.MonthsSaved = base.PaymentCount - schedule.PaymentCount
+	}
+
+	response := utils.ResponseData[LoanSchedule]{
+		Result: schedule,
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,592 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"server/common"
+	"server/models"
+	"server/utils"
+	"testing"
+	"time"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func testLoanData(method string) models.LoanData {
+	return models.LoanData{
+		LoanId:          uuid.MustParse("8df939de-5a97-4f20-b41b-9ac355c16e36"),
+		UserId:          1,
+		LoanName:        "車のローン",
+		Principal:       1200000,
+		AnnualRate:      12,
+		TermMonths:      12,
+		StartDate:       time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC),
+		RateType:        "fixed",
+		RepaymentMethod: method,
+	}
+}
+
+func sumPrincipal(schedule LoanSchedule) int {
+	var total int
+	for _, row := range schedule.Schedule {
+		total += row.Principal + row.Prepayment
+	}
+	return total
+}
+
+func TestCalcLoanSchedule(t *testing.T) {
+	t.Run("success 元利均等返済", func(t *testing.T) {
+		loan := testLoanData(EqualPayment)
+
+		result := calcLoanSchedule(loan, nil)
+
+		assert.Equal(t, 12, result.PaymentCount)
+		assert.Equal(t, 106619, result.MonthlyPayment)
+		// 初回の利息は 1,200,000 * 1% = 12,000
+		assert.Equal(t, 12000, result.Schedule[0].Interest)
+		assert.Equal(t, "2024-05-01", result.Schedule[0].PaymentDate)
+		assert.Equal(t, loan.Principal, sumPrincipal(result))
+		assert.Equal(t, 0, result.Schedule[11].Balance)
+		assert.Equal(t, loan.Principal+result.TotalInterest, result.TotalPayment)
+		// 最終回以外は返済額が一定
+		for _, row := range result.Schedule[:11] {
+			assert.Equal(t, 106619, row.Payment)
+		}
+	})
+
+	t.Run("success 元金均等返済", func(t *testing.T) {
+		loan := testLoanData(EqualPrincipal)
+
+		result := calcLoanSchedule(loan, nil)
+
+		assert.Equal(t, 12, result.PaymentCount)
+		assert.Equal(t, 112000, result.MonthlyPayment)
+		for _, row := range result.Schedule {
+			assert.Equal(t, 100000, row.Principal)
+		}
+		assert.Equal(t, 101000, result.Schedule[11].Payment)
+		assert.Equal(t, 78000, result.TotalInterest)
+		// 元金均等返済の方が元利均等返済より総利息が少ない
+		assert.Less(t, result.TotalInterest, calcLoanSchedule(testLoanData(EqualPayment), nil).TotalInterest)
+	})
+
+	t.Run("success 金利0%", func(t *testing.T) {
+		loan := testLoanData(EqualPayment)
+		loan.AnnualRate = 0
+
+		result := calcLoanSchedule(loan, nil)
+
+		assert.Equal(t, 100000, result.MonthlyPayment)
+		assert.Equal(t, 0, result.TotalInterest)
+		assert.Equal(t, loan.Principal, result.TotalPayment)
+	})
+
+	t.Run("success 繰上返済 期間短縮型", func(t *testing.T) {
+		loan := testLoanData(EqualPayment)
+		base := calcLoanSchedule(loan, nil)
+
+		result := calcLoanSchedule(loan, []LoanPrepayment{
+			{Month: 3, Amount: 300000, Type: PrepaymentShorten},
+		})
+
+		assert.Equal(t, 300000, result.Schedule[2].Prepayment)
+		assert.Less(t, result.PaymentCount, base.PaymentCount)
+		assert.Less(t, result.TotalInterest, base.TotalInterest)
+		assert.Equal(t, loan.Principal, sumPrincipal(result))
+		// 返済額は変わらない
+		assert.Equal(t, base.MonthlyPayment, result.Schedule[3].Payment)
+	})
+
+	t.Run("success 繰上返済 返済額軽減型", func(t *testing.T) {
+		loan := testLoanData(EqualPayment)
+		base := calcLoanSchedule(loan, nil)
+
+		result := calcLoanSchedule(loan, []LoanPrepayment{
+			{Month: 3, Amount: 300000, Type: PrepaymentReduce},
+		})
+
+		assert.Equal(t, base.PaymentCount, result.PaymentCount)
+		assert.Less(t, result.Schedule[3].Payment, base.MonthlyPayment)
+		assert.Less(t, result.TotalInterest, base.TotalInterest)
+		assert.Equal(t, loan.Principal, sumPrincipal(result))
+		assert.Equal(t, 0, result.Schedule[len(result.Schedule)-1].Balance)
+	})
+
+	t.Run("success 繰上返済 残高以上の金額", func(t *testing.T) {
+		loan := testLoanData(EqualPayment)
+
+		result := calcLoanSchedule(loan, []LoanPrepayment{
+			{Month: 1, Amount: 99999999, Type: PrepaymentShorten},
+		})
+
+		assert.Equal(t, 1, result.PaymentCount)
+		assert.Equal(t, 0, result.Schedule[0].Balance)
+		assert.Equal(t, loan.Principal, sumPrincipal(result))
+	})
+}
+
+func TestLoanPaymentDate(t *testing.T) {
+	t.Run("success 月末開始の返済日は各月の末日", func(t *testing.T) {
+		loan := testLoanData(EqualPayment)
+		loan.StartDate = time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+
+		result := calcLoanSchedule(loan, nil)
+
+		// 2月・4月等の日付のない月は翌月に繰り越さない
+		assert.Equal(t, "2024-02-29", result.Schedule[0].PaymentDate)
+		assert.Equal(t, "2024-03-31", result.Schedule[1].PaymentDate)
+		assert.Equal(t, "2024-04-30", result.Schedule[2].PaymentDate)
+		assert.Equal(t, "2025-01-31", result.Schedule[11].PaymentDate)
+	})
+}
+
+func TestCurrentLoanPayment(t *testing.T) {
+	loans := []models.LoanData{
+		testLoanData(EqualPayment),
+		testLoanData(EqualPrincipal),
+	}
+
+	t.Run("success 月末開始のローンは短い月にも返済がある", func(t *testing.T) {
+		loan := testLoanData(EqualPayment)
+		loan.StartDate = time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+
+		now := time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC)
+		assert.Equal(t, 106619, currentLoanPayment([]models.LoanData{loan}, now))
+	})
+
+	t.Run("success 返済期間中", func(t *testing.T) {
+		now := time.Date(2024, time.May, 20, 0, 0, 0, 0, time.UTC)
+		assert.Equal(t, 106619+112000, currentLoanPayment(loans, now))
+	})
+
+	t.Run("success 返済期間外", func(t *testing.T) {
+		now := time.Date(2026, time.May, 20, 0, 0, 0, 0, time.UTC)
+		assert.Equal(t, 0, currentLoanPayment(loans, now))
+	})
+}
+
+func TestGetLoanApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success GetLoanApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?user_id=1", nil)
+
+		mockData := []models.LoanData{testLoanData(EqualPayment)}
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.LoanDataFetcher{}),
+			"GetLoans",
+			func(_ *models.LoanDataFetcher, userId int) ([]models.LoanData, error) {
+				return mockData, nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiLoanManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetLoanApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var rawResponse struct {
+			Result json.RawMessage `json:"result"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &rawResponse)
+		assert.NoError(t, err)
+		expectedResponse, err := json.Marshal(mockData)
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(expectedResponse), string(rawResponse.Result))
+	})
+
+	t.Run("error GetLoanApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?user_id=1", nil)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.LoanDataFetcher{}),
+			"GetLoans",
+			func(_ *models.LoanDataFetcher, userId int) ([]models.LoanData, error) {
+				return nil, errors.New("database error")
+			})
+		defer patches.Reset()
+
+		fetcher := apiLoanManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetLoanApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		var response utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "database error", response.Result)
+	})
+
+	t.Run("バリデーションエラー user_id 必須", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?user_id=", nil)
+
+		fetcher := apiLoanManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetLoanApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestInsertLoanApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success InsertLoanApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		data := testData{
+			Data: []models.InsertLoanData{
+				{
+					LoanName:        "住宅ローン",
+					Principal:       30000000,
+					AnnualRate:      0.5,
+					TermMonths:      420,
+					StartDate:       "2024-04-01",
+					RateType:        "variable",
+					RepaymentMethod: EqualPayment,
+					UserId:          1,
+				},
+			},
+		}
+		body, _ := json.Marshal(data)
+		c.Request = httptest.NewRequest("POST", "/", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.LoanDataFetcher{}),
+			"InsertLoan",
+			func(_ *models.LoanDataFetcher, data []models.InsertLoanData) error {
+				return nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiLoanManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.InsertLoanApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response utils.ResponseData[string]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "ローン情報を登録致しました。", response.Result)
+	})
+
+	t.Run("error InsertLoanApi 登録データなし", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		body, _ := json.Marshal(testData{Data: []models.InsertLoanData{}})
+		c.Request = httptest.NewRequest("POST", "/", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		fetcher := apiLoanManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.InsertLoanApi(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("バリデーションエラー InsertLoanApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		data := testData{
+			Data: []models.InsertLoanData{
+				{
+					LoanName:        "住宅ローン",
+					Principal:       30000000,
+					AnnualRate:      31,
+					TermMonths:      420,
+					StartDate:       "2024-04-01",
+					RateType:        "variable",
+					RepaymentMethod: "bullet",
+					UserId:          1,
+				},
+			},
+		}
+		body, _ := json.Marshal(data)
+		c.Request = httptest.NewRequest("POST", "/", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		fetcher := apiLoanManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.InsertLoanApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, response.RecodeRows)
+		assert.Len(t, response.Result, 2)
+	})
+
+	t.Run("error InsertLoanApi DBエラー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		data := testData{
+			Data: []models.InsertLoanData{
+				{
+					LoanName:        "住宅ローン",
+					Principal:       30000000,
+					AnnualRate:      0.5,
+					TermMonths:      420,
+					StartDate:       "2024-04-01",
+					RateType:        "variable",
+					RepaymentMethod: EqualPayment,
+					UserId:          1,
+				},
+			},
+		}
+		body, _ := json.Marshal(data)
+		c.Request = httptest.NewRequest("POST", "/", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.LoanDataFetcher{}),
+			"InsertLoan",
+			func(_ *models.LoanDataFetcher, data []models.InsertLoanData) error {
+				return errors.New("database error")
+			})
+		defer patches.Reset()
+
+		fetcher := apiLoanManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.InsertLoanApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		var response utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "ローン登録時にエラーが発生。", response.Result)
+	})
+}
+
+func TestUpdateLoanApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success UpdateLoanApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		data := testData{
+			Data: []models.UpdateLoanData{
+				{
+					LoanId:          "8df939de-5a97-4f20-b41b-9ac355c16e36",
+					LoanName:        "住宅ローン",
+					Principal:       30000000,
+					AnnualRate:      0.75,
+					TermMonths:      420,
+					StartDate:       "2024-04-01",
+					RateType:        "variable",
+					RepaymentMethod: EqualPayment,
+					UserId:          1,
+				},
+			},
+		}
+		body, _ := json.Marshal(data)
+		c.Request = httptest.NewRequest("PUT", "/", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.LoanDataFetcher{}),
+			"UpdateLoan",
+			func(_ *models.LoanDataFetcher, data []models.UpdateLoanData) error {
+				return nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiLoanManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.UpdateLoanApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response utils.ResponseData[string]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "ローン情報の更新が問題なく成功しました。", response.Result)
+	})
+
+	t.Run("バリデーションエラー UpdateLoanApi loan_id", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		data := testData{
+			Data: []models.UpdateLoanData{
+				{
+					LoanId:          "",
+					LoanName:        "住宅ローン",
+					Principal:       30000000,
+					AnnualRate:      0.75,
+					TermMonths:      420,
+					StartDate:       "2024-04-01",
+					RateType:        "variable",
+					RepaymentMethod: EqualPayment,
+					UserId:          1,
+				},
+			},
+		}
+		body, _ := json.Marshal(data)
+		c.Request = httptest.NewRequest("PUT", "/", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		fetcher := apiLoanManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.UpdateLoanApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestDeleteLoanApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success DeleteLoanApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		data := testData{
+			Data: []models.DeleteLoanData{
+				{
+					LoanId: "8df939de-5a97-4f20-b41b-9ac355c16e36",
+					UserId: 1,
+				},
+			},
+		}
+		body, _ := json.Marshal(data)
+		c.Request = httptest.NewRequest("POST", "/", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.LoanDataFetcher{}),
+			"DeleteLoan",
+			func(_ *models.LoanDataFetcher, data []models.DeleteLoanData) error {
+				return nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiLoanManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.DeleteLoanApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response utils.ResponseData[string]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "ローン情報の削除が問題なく成功しました。", response.Result)
+	})
+}
+
+func TestGetLoanScheduleApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success GetLoanScheduleApi 繰上返済あり", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		body := []byte(`{"loan_id":"8df939de-5a97-4f20-b41b-9ac355c16e36","user_id":1,"prepayments":[{"month":3,"amount":300000,"type":"shorten"}]}`)
+		c.Request = httptest.NewRequest("POST", "/", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.LoanDataFetcher{}),
+			"GetLoan",
+			func(_ *models.LoanDataFetcher, loanId string, userId int) (models.LoanData, error) {
+				return testLoanData(EqualPayment), nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiLoanManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetLoanScheduleApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response utils.ResponseData[LoanSchedule]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+
+		base := calcLoanSchedule(testLoanData(EqualPayment), nil)
+		assert.Equal(t, base.TotalInterest-response.Result.TotalInterest, response.Result.InterestSaved)
+		assert.Greater(t, response.Result.InterestSaved, 0)
+		assert.Greater(t, response.Result.MonthsSaved, 0)
+	})
+
+	t.Run("error GetLoanScheduleApi ローンなし", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		body := []byte(`{"loan_id":"8df939de-5a97-4f20-b41b-9ac355c16e36","user_id":1}`)
+		c.Request = httptest.NewRequest("POST", "/", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.LoanDataFetcher{}),
+			"GetLoan",
+			func(_ *models.LoanDataFetcher, loanId string, userId int) (models.LoanData, error) {
+				return models.LoanData{}, errors.New("対象のローンが存在しません。")
+			})
+		defer patches.Reset()
+
+		fetcher := apiLoanManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetLoanScheduleApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		var response utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "対象のローンが存在しません。", response.Result)
+	})
+
+	t.Run("バリデーションエラー GetLoanScheduleApi 繰上返済種別", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		body := []byte(`{"loan_id":"8df939de-5a97-4f20-b41b-9ac355c16e36","user_id":1,"prepayments":[{"month":3,"amount":300000,"type":"other"}]}`)
+		c.Request = httptest.NewRequest("POST", "/", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		fetcher := apiLoanManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetLoanScheduleApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, response.RecodeRows)
+	})
+}
@@ -0,0 +1,266 @@
+// controllers/magic_link_controllers.go
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"server/config"
+	"server/enum"
+	"server/models"
+	"server/utils"
+	"server/validation"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	RequestMagicLinkData struct {
+		UserEmail string `json:"user_email"`
+	}
+
+	RequestMagicLinkSignInData struct {
+		Token string `json:"token"`
+	}
+)
+
+const (
+	magicLinkAttemptsMessage = "サインイン用リンクの送信回数が多すぎます。しばらく時間をおいて再度お試しください。"
+	// 登録されていないメールアドレスかどうかを判別できないよう、常に同じメッセージを返す
+	magicLinkSentMessage = "登録済みのメールアドレスの場合、サインイン用のリンクを送信しました。"
+)
+
+// magicLinkDeviceId はマジックリンクを要求した端末の識別子を返す
+// クッキーがない場合は新しく発行し、リンクの有効期限の間クッキーに保存する
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+// 戻り値:
+//
+//	戻り値1: 端末の識別子
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func magicLinkDeviceId(c *gin.Context) (string, error) {
+	deviceId, err := c.Cookie(utils.MagicLinkDevice)
+	if err != nil || deviceId == "" {
+		deviceId, err = utils.GenerateLinkToken()
+		if err != nil {
+			return "", err
+		}
+	}
+	// JavaScriptから読み取れないよう、設定に関わらずHttpOnlyとする
+	c.SetCookie(utils.MagicLinkDevice, deviceId, utils.MagicLinkMinutes*60, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, true)
+	return deviceId, nil
+}
+
+// MagicLinkRequestApi はパスワードなしでサインインするためのリンクをメールで送信するAPI
+// リンクは要求したブラウザでのみ使用でき、転送されたリンクではサインインできない
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (af *apiSignDataFetcher) MagicLinkRequestApi(c *gin.Context) {
+	var requestData RequestMagicLinkData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	validator := validation.EmailCheckRequestData{
+		UserEmail: requestData.UserEmail,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// 同じアカウント・IPアドレスへのメールの連続送信を制限する
+	accountKey := utils.MagicLinkAccountAttemptKey(requestData.UserEmail)
+	ipKey := utils.MagicLinkIPAttemptKey(c.ClientIP())
+	if !af.checkAttempts(c, magicLinkAttemptsMessage, accountKey, ipKey) {
+		return
+	}
+	// 失敗ではなく送信の度に数える(登録されていないメールアドレスも同様に数える)
+	if _, err := af.recordFailedAttempts(accountKey, ipKey); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "試行回数の記録に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	deviceId, err := magicLinkDeviceId(c)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "サインイン用リンクの発行に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: magicLinkSentMessage,
+	}
+
+	dbFetcher, _, _ := models.NewSignDataFetcher(
+		config.GetDataBaseSource(),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+	)
+	userId, err := dbFetcher.GetUserId(requestData.UserEmail)
+	if err != nil {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	token, err := af.UtilsFetcher.NewMagicLinkToken(userId, deviceId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "サインイン用リンクの発行に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	now := time.Now()
+	link := fmt.Sprintf("%smagic_link_signin?token=%s", utils.GetBaseURL(), token)
+	subject, body, err := af.EmailTemplateService.MagicLinkTemplate(
+		requestData.UserEmail,
+		link,
+		af.UtilsFetcher.DateTimeStr(now.Add(utils.MagicLinkMinutes*time.Minute), "2006年01月02日 15:04"),
+		af.UtilsFetcher.DateTimeStr(now, "2006年01月02日 15:04"),
+	)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メールテンプレート生成エラー(サインイン用リンク): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if err := af.UtilsFetcher.SendMail(requestData.UserEmail, subject, body, true); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メール送信エラー(サインイン用リンク): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// MagicLinkSignInApi はメールのリンクのトークンを確認し、認証トークン・リフレッシュトークンを発行するAPI
+// リンクは1回のみ使用でき、要求したブラウザ(端末の識別子のクッキー)以外では使用できない
+// 二段階認証が有効な場合はワンタイムパスワードの確認後にサインインを完了する
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (af *apiSignDataFetcher) MagicLinkSignInApi(c *gin.Context) {
+	var requestData RequestMagicLinkSignInData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	validator := validation.RequestMagicLinkSignInData{
+		Token: requestData.Token,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// 無効なリンクを繰り返し試すIPアドレスからの試行を制限する
+	ipKey := utils.SignInIPAttemptKey(c.ClientIP())
+	if !af.checkAttempts(c, signInAttemptsMessage, ipKey) {
+		return
+	}
+
+	deviceId, _ := c.Cookie(utils.MagicLinkDevice)
+	userId, err := af.UtilsFetcher.ConsumeMagicLinkToken(requestData.Token, deviceId)
+	if err != nil {
+		if !errors.Is(err, utils.ErrMagicLinkInvalid) && !errors.Is(err, utils.ErrMagicLinkDeviceMismatch) {
+			response := utils.ErrorMessageResponse{
+				Result: "サインイン用リンクの確認に失敗しました。",
+			}
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+
+		statuses, lockErr := af.recordFailedAttempts(ipKey)
+		if lockErr != nil {
+			response := utils.ErrorMessageResponse{
+				Result: "試行回数の記録に失敗しました。",
+			}
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+		if wait, locked := lockedWait(statuses); locked {
+			tooManyAttempts(c, wait, signInAttemptsMessage)
+			return
+		}
+
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	dbFetcher, _, _ := models.NewSignDataFetcher(
+		config.GetDataBaseSource(),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+	)
+	userEmail, err := dbFetcher.GetUserEmail(userId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: utils.ErrMagicLinkInvalid.Error(),
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// リンクの使用後は端末の識別子とリンクの送信回数を消去する(失敗してもサインインは継続する)
+	c.SetCookie(utils.MagicLinkDevice, "", -1, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, true)
+	_ = af.AttemptLimiter.Reset(utils.MagicLinkAccountAttemptKey(userEmail))
+
+	result := models.SignInData{
+		UserId:    userId,
+		UserEmail: userEmail,
+	}
+
+	totpFetcher, _, _ := models.NewTotpDataFetcher(config.GetDataBaseSource())
+	totp, err := totpFetcher.GetTotp(userId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "二段階認証の設定の取得に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	if totp.Enabled {
+		af.issueTotpChallenge(c, result, enum.SIGN_IN_MAGIC_LINK)
+		return
+	}
+
+	af.completeSignIn(c, result, enum.SIGN_IN_MAGIC_LINK)
+}
@@ -0,0 +1,426 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"server/common"
+	"server/enum"
+	mock_config "server/mock/config"
+	mock_limiter "server/mock/limiter"
+	mock_utils "server/mock/utils"
+	"server/models"
+	"server/templates"
+	"server/utils"
+	"testing"
+	"time"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// magicLinkRequest はJSONのリクエストを作成し、端末の識別子のクッキーを付与する
+func magicLinkRequest(c *gin.Context, path string, data interface{}, deviceId string) {
+	body, _ := json.Marshal(data)
+	c.Request = httptest.NewRequest("POST", path, bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.RemoteAddr = "192.0.2.1:12345"
+	if deviceId != "" {
+		c.Request.AddCookie(&http.Cookie{Name: utils.MagicLinkDevice, Value: deviceId})
+	}
+}
+
+// patchGetUserId はメールアドレスからのユーザーIDの取得をモック化する
+func patchGetUserId(userId int, err error) *Patches {
+	return ApplyMethod(
+		reflect.TypeOf(&models.SignDataFetcher{}),
+		"GetUserId",
+		func(_ *models.SignDataFetcher, UserEmail string) (int, error) {
+			return userId, err
+		})
+}
+
+// responseCookie はレスポンスで設定したクッキーを返す
+func responseCookie(w *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestMagicLinkRequestApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	accountKey := utils.MagicLinkAccountAttemptKey("test@example.com")
+	ipKey := utils.MagicLinkIPAttemptKey("192.0.2.1")
+
+	t.Run("MagicLinkRequestApi リンクを送信し、端末の識別子をクッキーに保存する", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		magicLinkRequest(c, "/api/magic_link", RequestMagicLinkData{UserEmail: "test@example.com"}, "")
+
+		patches := patchGetUserId(3, nil)
+		defer patches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(accountKey, ipKey).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().Fail(accountKey).Return(utils.AttemptStatus{Failures: 1, Wait: 30 * time.Second}, nil)
+		mockAttemptLimiter.EXPECT().Fail(ipKey).Return(utils.AttemptStatus{Failures: 1, Wait: time.Second}, nil)
+
+		var deviceId string
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().
+			NewMagicLinkToken(3, gomock.Any()).
+			DoAndReturn(func(UserId int, DeviceId string) (string, error) {
+				deviceId = DeviceId
+				return "magic.link.token", nil
+			})
+		mockUtilsFetcher.EXPECT().DateTimeStr(gomock.Any(), gomock.Any()).Return("2024年12月2日").Times(2)
+		mockUtilsFetcher.EXPECT().
+			SendMail("test@example.com", "【たくわえる】サインイン用リンクのお知らせ", gomock.Any(), true).
+			DoAndReturn(func(toEmail, subject, body string, isHTML bool) error {
+				assert.Contains(t, body, "magic_link_signin?token=magic.link.token")
+				return nil
+			})
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mockUtilsFetcher,
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			AttemptLimiter:       mockAttemptLimiter,
+		}
+		fetcher.MagicLinkRequestApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assertErrorMessage(t, w, magicLinkSentMessage)
+
+		cookie := responseCookie(w, utils.MagicLinkDevice)
+		assert.NotNil(t, cookie)
+		assert.Equal(t, deviceId, cookie.Value)
+		assert.NotEmpty(t, deviceId)
+		assert.True(t, cookie.HttpOnly)
+		assert.Equal(t, utils.MagicLinkMinutes*60, cookie.MaxAge)
+	})
+
+	t.Run("MagicLinkRequestApi 端末の識別子のクッキーがある場合は引き継ぐ", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		magicLinkRequest(c, "/api/magic_link", RequestMagicLinkData{UserEmail: "test@example.com"}, "device-1")
+
+		patches := patchGetUserId(3, nil)
+		defer patches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().NewMagicLinkToken(3, "device-1").Return("magic.link.token", nil)
+		mockUtilsFetcher.EXPECT().DateTimeStr(gomock.Any(), gomock.Any()).Return("2024年12月2日").Times(2)
+		mockUtilsFetcher.EXPECT().SendMail("test@example.com", gomock.Any(), gomock.Any(), true).Return(nil)
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mockUtilsFetcher,
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			AttemptLimiter:       allowAllAttempts(t),
+		}
+		fetcher.MagicLinkRequestApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "device-1", responseCookie(w, utils.MagicLinkDevice).Value)
+	})
+
+	t.Run("MagicLinkRequestApi 登録されていないメールアドレスは送信せず同じレスポンスを返す", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		magicLinkRequest(c, "/api/magic_link", RequestMagicLinkData{UserEmail: "test@example.com"}, "")
+
+		patches := patchGetUserId(-1, fmt.Errorf("登録ユーザーが存在しません"))
+		defer patches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// リンクの発行・メールの送信は行わない
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mock_utils.NewMockUtilsFetcher(ctrl),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			AttemptLimiter:       allowAllAttempts(t),
+		}
+		fetcher.MagicLinkRequestApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assertErrorMessage(t, w, magicLinkSentMessage)
+	})
+
+	t.Run("MagicLinkRequestApi 送信回数の制限中", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		magicLinkRequest(c, "/api/magic_link", RequestMagicLinkData{UserEmail: "test@example.com"}, "")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(accountKey, ipKey).Return(30*time.Second, nil)
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:   mock_utils.NewMockUtilsFetcher(ctrl),
+			AttemptLimiter: mockAttemptLimiter,
+		}
+		fetcher.MagicLinkRequestApi(c)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "30", w.Header().Get("Retry-After"))
+		assertErrorMessage(t, w, magicLinkAttemptsMessage)
+	})
+
+	t.Run("MagicLinkRequestApi メールアドレスの形式が間違っている", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		magicLinkRequest(c, "/api/magic_link", RequestMagicLinkData{UserEmail: "test"}, "")
+
+		fetcher := apiSignDataFetcher{}
+		fetcher.MagicLinkRequestApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "正しいメールアドレス形式である必要があります。")
+	})
+
+	t.Run("MagicLinkRequestApi リンクの発行エラー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		magicLinkRequest(c, "/api/magic_link", RequestMagicLinkData{UserEmail: "test@example.com"}, "device-1")
+
+		patches := patchGetUserId(3, nil)
+		defer patches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().NewMagicLinkToken(3, "device-1").Return("", fmt.Errorf("保存エラー"))
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:   mockUtilsFetcher,
+			AttemptLimiter: allowAllAttempts(t),
+		}
+		fetcher.MagicLinkRequestApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assertErrorMessage(t, w, "サインイン用リンクの発行に失敗しました。")
+	})
+}
+
+func TestMagicLinkSignInApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	ipKey := utils.SignInIPAttemptKey("192.0.2.1")
+	accountKey := utils.MagicLinkAccountAttemptKey("test@example.com")
+
+	patchGetUserEmail := func(userEmail string, err error) *Patches {
+		return ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"GetUserEmail",
+			func(_ *models.SignDataFetcher, userId int) (string, error) {
+				return userEmail, err
+			})
+	}
+
+	t.Run("MagicLinkSignInApi リンクを要求したブラウザでサインインできる", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		magicLinkRequest(c, "/api/magic_link_signin", RequestMagicLinkSignInData{Token: "magic.link.token"}, "device-1")
+
+		patches := patchGetUserEmail("test@example.com", nil)
+		defer patches.Reset()
+		totpPatches := patchGetTotp(models.TotpData{}, nil)
+		defer totpPatches.Reset()
+		rolePatches := patchGetUserRole(models.UserRoleData{Role: enum.ROLE_USER}, nil)
+		defer rolePatches.Reset()
+		var signInType string
+		sessionPatches := ApplyMethod(
+			reflect.TypeOf(&models.SessionDataFetcher{}),
+			"InsertSession",
+			func(_ *models.SessionDataFetcher, data models.SessionData) error {
+				signInType = data.SignInType
+				return nil
+			})
+		defer sessionPatches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(ipKey).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().Reset(accountKey).Return(nil)
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().ConsumeMagicLinkToken("magic.link.token", "device-1").Return(3, nil)
+		mockUtilsFetcher.EXPECT().NewToken(3, gomock.Any(), enum.ROLE_USER, utils.AuthTokenHour).Return("new_token", nil)
+		mockUtilsFetcher.EXPECT().RefreshToken(3, gomock.Any(), utils.RefreshAuthTokenHour).Return("refresh_token", nil)
+		mockUtilsFetcher.EXPECT().DateTimeStr(gomock.Any(), gomock.Any()).Return("2024年12月2日")
+		mockUtilsFetcher.EXPECT().SendMail("test@example.com", gomock.Any(), gomock.Any(), true).Return(nil)
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mockUtilsFetcher,
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			AttemptLimiter:       mockAttemptLimiter,
+		}
+		fetcher.MagicLinkSignInApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, enum.SIGN_IN_MAGIC_LINK, signInType)
+		assert.Equal(t, "new_token", responseCookie(w, utils.AuthToken).Value)
+		assert.Equal(t, "refresh_token", responseCookie(w, utils.RefreshAuthToken).Value)
+		// 使用後は端末の識別子を削除する
+		assert.True(t, responseCookie(w, utils.MagicLinkDevice).MaxAge < 0)
+
+		var responseBody utils.ResponseData[SignInResult]
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, 3, responseBody.Result.UserId)
+		assert.Equal(t, "test@example.com", responseBody.Result.UserEmail)
+	})
+
+	t.Run("MagicLinkSignInApi 二段階認証が有効な場合はチャレンジを返す", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		magicLinkRequest(c, "/api/magic_link_signin", RequestMagicLinkSignInData{Token: "magic.link.token"}, "device-1")
+
+		patches := patchGetUserEmail("test@example.com", nil)
+		defer patches.Reset()
+		totpPatches := patchGetTotp(enabledTotp(), nil)
+		defer totpPatches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisSet(gomock.Any(), totpChallenge{UserId: 3, UserEmail: "test@example.com", SignInType: enum.SIGN_IN_MAGIC_LINK}, 5*time.Minute).
+			Return(nil)
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().ConsumeMagicLinkToken("magic.link.token", "device-1").Return(3, nil)
+
+		// トークンは発行しない
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:   mockUtilsFetcher,
+			RedisService:   mockRedisService,
+			AttemptLimiter: allowAllAttempts(t),
+		}
+		fetcher.MagicLinkSignInApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Nil(t, responseCookie(w, utils.AuthToken))
+
+		var responseBody utils.ResponseData[TotpChallengeResult]
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.True(t, responseBody.Result.TwoFactorRequired)
+	})
+
+	t.Run("MagicLinkSignInApi 転送されたリンク(端末が異なる)", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		magicLinkRequest(c, "/api/magic_link_signin", RequestMagicLinkSignInData{Token: "magic.link.token"}, "other-device")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(ipKey).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().Fail(ipKey).Return(utils.AttemptStatus{Failures: 1, Wait: time.Second}, nil)
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().
+			ConsumeMagicLinkToken("magic.link.token", "other-device").
+			Return(0, utils.ErrMagicLinkDeviceMismatch)
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:   mockUtilsFetcher,
+			AttemptLimiter: mockAttemptLimiter,
+		}
+		fetcher.MagicLinkSignInApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assertErrorMessage(t, w, utils.ErrMagicLinkDeviceMismatch.Error())
+		assert.Nil(t, responseCookie(w, utils.AuthToken))
+	})
+
+	t.Run("MagicLinkSignInApi 無効なリンクを繰り返すとIPアドレスがロックされる", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		magicLinkRequest(c, "/api/magic_link_signin", RequestMagicLinkSignInData{Token: "magic.link.token"}, "device-1")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(ipKey).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().
+			Fail(ipKey).
+			Return(utils.AttemptStatus{Failures: 20, Wait: 30 * time.Minute, Locked: true, JustLocked: true}, nil)
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().
+			ConsumeMagicLinkToken("magic.link.token", "device-1").
+			Return(0, utils.ErrMagicLinkInvalid)
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:   mockUtilsFetcher,
+			AttemptLimiter: mockAttemptLimiter,
+		}
+		fetcher.MagicLinkSignInApi(c)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "1800", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("MagicLinkSignInApi 試行の待機中", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		magicLinkRequest(c, "/api/magic_link_signin", RequestMagicLinkSignInData{Token: "magic.link.token"}, "device-1")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(ipKey).Return(10*time.Second, nil)
+
+		// トークンは確認しない
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:   mock_utils.NewMockUtilsFetcher(ctrl),
+			AttemptLimiter: mockAttemptLimiter,
+		}
+		fetcher.MagicLinkSignInApi(c)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assertErrorMessage(t, w, signInAttemptsMessage)
+	})
+
+	t.Run("MagicLinkSignInApi トークンなし", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		magicLinkRequest(c, "/api/magic_link_signin", RequestMagicLinkSignInData{}, "device-1")
+
+		fetcher := apiSignDataFetcher{}
+		fetcher.MagicLinkSignInApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "トークンは必須です。")
+	})
+}
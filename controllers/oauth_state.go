@@ -0,0 +1,136 @@
+// controllers/oauth_state.go
+package controllers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"server/config"
+	"server/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/oauth2"
+)
+
+type (
+	// oauthState は外部認証の開始から認可コードの受け取りまで保持する値
+	oauthState struct {
+		Provider     string `json:"provider"`
+		Purpose      string `json:"purpose"`
+		CodeVerifier string `json:"code_verifier"`
+		Nonce        string `json:"nonce"`
+		// 連携(link)の場合は認証を開始したユーザーID
+		UserId int `json:"user_id,omitempty"`
+	}
+)
+
+// 外部認証の用途
+const (
+	oauthPurposeSignIn = "signin"
+	oauthPurposeSignUp = "signup"
+	oauthPurposeDelete = "delete"
+	oauthPurposeLink   = "link"
+)
+
+// 外部認証の開始から認可コードを受け取るまでの有効期限
+const oauthStateMinutes = 10
+
+// oauthStateKey はstateを保存するRedisのキー
+func oauthStateKey(state string) string {
+	return fmt.Sprintf("oauth_state:%s", utils.HashLinkToken(state))
+}
+
+// issueOAuthState は外部認証のstate・PKCEのコードベリファイア・nonceを発行する
+// stateはRedisに保存し、認証を開始したブラウザにのみクッキーで渡す
+//
+// 引数:
+//   - c: Ginコンテキスト
+//   - redisService: Redisの操作
+//   - provider: 外部認証の名称
+//   - purpose: 外部認証の用途(oauthPurpose*)
+//   - userId: 連携の場合は認証を開始したユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: state
+//	戻り値2: 保存した値
+//	戻り値3: エラー内容(エラーがない場合はnil)
+//
+
+func issueOAuthState(c *gin.Context, redisService config.RedisService, provider, purpose string, userId int) (string, oauthState, error) {
+	state, err := utils.GenerateLinkToken()
+	if err != nil {
+		return "", oauthState{}, err
+	}
+	nonce, err := utils.GenerateLinkToken()
+	if err != nil {
+		return "", oauthState{}, err
+	}
+
+	data := oauthState{
+		Provider:     provider,
+		Purpose:      purpose,
+		CodeVerifier: oauth2.GenerateVerifier(),
+		Nonce:        nonce,
+		UserId:       userId,
+	}
+	if err := redisService.RedisSet(oauthStateKey(state), data, oauthStateMinutes*time.Minute); err != nil {
+		return "", oauthState{}, fmt.Errorf("stateの保存エラー: %v", err)
+	}
+
+	c.SetCookie(utils.OauthState, state, oauthStateMinutes*60, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, true)
+	return state, data, nil
+}
+
+// consumeOAuthState はコールバックのstateを検証し、保存した値を返す
+// stateは1回のみ使用でき、検証に失敗した場合はレスポンスを返す
+//
+// 引数:
+//   - c: Ginコンテキスト
+//   - redisService: Redisの操作
+//   - state: コールバックで受け取ったstate
+//   - provider: 外部認証の名称
+//   - purpose: 外部認証の用途(oauthPurpose*)
+//
+// 戻り値:
+//
+//	戻り値1: 保存した値
+//	戻り値2: 検証に成功した場合はtrue
+//
+
+func consumeOAuthState(c *gin.Context, redisService config.RedisService, state, provider, purpose string) (oauthState, bool) {
+	var data oauthState
+
+	cookieState, _ := c.Cookie(utils.OauthState)
+	c.SetCookie(utils.OauthState, "", -1, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, true)
+
+	// 認証を開始したブラウザ以外からのコールバックは受け付けない
+	if cookieState == "" || subtle.ConstantTimeCompare([]byte(cookieState), []byte(state)) != 1 {
+		oauthStateInvalid(c)
+		return data, false
+	}
+
+	value, err := redisService.RedisGetDel(oauthStateKey(state))
+	if err != nil {
+		oauthStateInvalid(c)
+		return data, false
+	}
+	if err := json.Unmarshal([]byte(value), &data); err != nil ||
+		data.Provider != provider ||
+		data.Purpose != purpose {
+		oauthStateInvalid(c)
+		return oauthState{}, false
+	}
+
+	return data, true
+}
+
+// oauthStateInvalid はstateが無効な場合のレスポンスを返す
+func oauthStateInvalid(c *gin.Context) {
+	response := utils.ErrorMessageResponse{
+		Result: "認証の有効期限が切れたか、不正なリクエストです。最初からやり直してください。",
+	}
+	c.JSON(http.StatusUnauthorized, response)
+}
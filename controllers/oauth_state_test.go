@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"server/config"
+	mock_config "server/mock/config"
+	"server/test_utils"
+	"server/utils"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const testOAuthClientId = "test-oauth-client"
+
+// oauthTestFlow はテスト用の外部認証サーバーと、発行したstateを保存するRedisのモック
+type oauthTestFlow struct {
+	fake  *test_utils.FakeOIDCServer
+	redis *mock_config.MockRedisService
+	// Redisに保存したstate
+	saved map[string]string
+	// テスト用の外部認証サーバーに接続する外部認証
+	provider utils.OIDCProvider
+}
+
+// newOAuthTestFlow はテスト用の外部認証サーバーを起動する
+func newOAuthTestFlow(t *testing.T) *oauthTestFlow {
+	fake := test_utils.NewFakeOIDCServer(testOAuthClientId)
+	t.Cleanup(fake.Close)
+
+	globalEnv := config.GlobalEnv
+	config.GlobalEnv.RedirectURI = "http://localhost:3000/"
+	t.Cleanup(func() { config.GlobalEnv = globalEnv })
+
+	flow := &oauthTestFlow{
+		fake:  fake,
+		redis: mock_config.NewMockRedisService(gomock.NewController(t)),
+		saved: map[string]string{},
+	}
+
+	flow.redis.EXPECT().
+		RedisSet(gomock.Any(), gomock.Any(), oauthStateMinutes*time.Minute).
+		DoAndReturn(func(key string, value interface{}, duration time.Duration) error {
+			data, _ := json.Marshal(value)
+			flow.saved[key] = string(data)
+			return nil
+		}).
+		AnyTimes()
+	// 退会時に発行するアカウント復元用のトークン
+	flow.redis.EXPECT().
+		RedisSet(gomock.Any(), gomock.Any(), gomock.Not(oauthStateMinutes*time.Minute)).
+		Return(nil).
+		AnyTimes()
+	flow.redis.EXPECT().
+		RedisGetDel(gomock.Any()).
+		DoAndReturn(func(key string) (string, error) {
+			value, ok := flow.saved[key]
+			if !ok {
+				return "", fmt.Errorf("redis: nil")
+			}
+			delete(flow.saved, key)
+			return value, nil
+		}).
+		AnyTimes()
+
+	return flow
+}
+
+// authorize は認証を開始し、外部認証サーバーで認可した認可コード・stateと、stateのクッキーを返す
+//
+// 引数:
+//   - start: 認証を開始するAPI
+//   - userId: 認証を開始するユーザーID(サインイン前は0)
+//
+
+func (flow *oauthTestFlow) authorize(t *testing.T, start func(c *gin.Context), userId int) (string, string, *http.Cookie) {
+	w := httptest.NewRecorder()
+	c := signInContext(w, httptest.NewRequest("GET", "/api/auth", nil), userId, "")
+
+	start(c)
+	if w.Code != http.StatusFound {
+		t.Fatalf("認証の開始に失敗しました: %d %s", w.Code, w.Body.String())
+	}
+
+	var cookie *http.Cookie
+	for _, resCookie := range w.Result().Cookies() {
+		if resCookie.Name == utils.OauthState {
+			cookie = resCookie
+		}
+	}
+
+	code, state, err := flow.fake.Authorize(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("認可に失敗しました: %v", err)
+	}
+	return code, state, cookie
+}
+
+// callbackRequest は認可コードとstateを受け取るコールバックのリクエストを作成する
+func (flow *oauthTestFlow) callbackRequest(path, code, state string, cookie *http.Cookie) (*httptest.ResponseRecorder, *gin.Context) {
+	query := url.Values{}
+	query.Set("code", code)
+	query.Set("state", state)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", path+"?"+query.Encode(), nil)
+	if cookie != nil {
+		c.Request.AddCookie(cookie)
+	}
+	return w, c
+}
+
+// savedState はRedisに保存したstateの値を返す
+func (flow *oauthTestFlow) savedState(state string) oauthState {
+	var saved oauthState
+	_ = json.Unmarshal([]byte(flow.saved[oauthStateKey(state)]), &saved)
+	return saved
+}
+
+// assertErrorMessage はエラーメッセージのレスポンスを確認する
+func assertErrorMessage(t *testing.T, w *httptest.ResponseRecorder, message string) {
+	var responseBody utils.ErrorMessageResponse
+	err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+	assert.NoError(t, err)
+	assert.Equal(t, message, responseBody.Result)
+}
+
+func TestConsumeOAuthState(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	flow := newOAuthTestFlow(t)
+
+	issue := func(provider, purpose string) (string, *http.Cookie) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		state, _, err := issueOAuthState(c, flow.redis, provider, purpose, 0)
+		assert.NoError(t, err)
+		return state, w.Result().Cookies()[0]
+	}
+
+	t.Run("success consumeOAuthState", func(t *testing.T) {
+		state, cookie := issue("google", oauthPurposeSignIn)
+		_, c := flow.callbackRequest("/", "code", state, cookie)
+
+		data, ok := consumeOAuthState(c, flow.redis, state, "google", oauthPurposeSignIn)
+		assert.True(t, ok)
+		assert.Equal(t, "google", data.Provider)
+		assert.NotEmpty(t, data.CodeVerifier)
+		assert.NotEmpty(t, data.Nonce)
+		// stateは1回のみ使用できる
+		assert.Empty(t, flow.saved)
+	})
+
+	t.Run("error consumeOAuthState 外部認証が異なる", func(t *testing.T) {
+		state, cookie := issue("google", oauthPurposeSignIn)
+		w, c := flow.callbackRequest("/", "code", state, cookie)
+
+		_, ok := consumeOAuthState(c, flow.redis, state, "line", oauthPurposeSignIn)
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("error consumeOAuthState クッキーと不一致", func(t *testing.T) {
+		state, _ := issue("google", oauthPurposeSignIn)
+		w, c := flow.callbackRequest("/", "code", state, &http.Cookie{Name: utils.OauthState, Value: "other"})
+
+		_, ok := consumeOAuthState(c, flow.redis, state, "google", oauthPurposeSignIn)
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		// 不一致の場合は保存したstateを消費しない
+		assert.Len(t, flow.saved, 1)
+		flow.saved = map[string]string{}
+	})
+}
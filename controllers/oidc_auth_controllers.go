@@ -0,0 +1,578 @@
+// controllers/oidc_auth_controllers.go
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"server/config"
+	"server/enum"
+	"server/models"
+	"server/templates"
+	"server/utils"
+	"server/validation"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+)
+
+type (
+	// OIDCService は設定で登録した外部認証(OpenID Connect)の共通API
+	// 外部認証はルートの :provider で選ぶ
+	OIDCService interface {
+		OIDCSignInAuth(c *gin.Context)
+		OIDCSignUpAuth(c *gin.Context)
+		OIDCDeleteAuth(c *gin.Context)
+		OIDCLinkAuth(c *gin.Context)
+		OIDCSignInCallback(c *gin.Context)
+		OIDCSignUpCallback(c *gin.Context)
+		OIDCDeleteCallback(c *gin.Context)
+		OIDCLinkCallback(c *gin.Context)
+	}
+
+	// OIDCPrams はIDトークンで確認した外部アカウントの情報
+	OIDCPrams struct {
+		UserEmail string
+		UserName  string
+		// 外部認証のユーザーID
+		ProviderId string
+	}
+
+	OIDCManager struct {
+		// 外部認証の名称ごとの外部認証
+		Providers            map[string]utils.OIDCProvider
+		EmailTemplateService templates.EmailTemplateService
+		UtilsFetcher         utils.UtilsFetcher
+		RedisService         config.RedisService
+	}
+)
+
+func NewOIDCService(
+	providers []utils.OIDCProvider,
+	EmailTemplateService templates.EmailTemplateService,
+	utilsFetcher utils.UtilsFetcher,
+	RedisService config.RedisService,
+) OIDCService {
+	providerMap := make(map[string]utils.OIDCProvider, len(providers))
+	for _, provider := range providers {
+		providerMap[provider.Config().Name] = provider
+	}
+
+	return &OIDCManager{
+		Providers:            providerMap,
+		EmailTemplateService: EmailTemplateService,
+		UtilsFetcher:         utilsFetcher,
+		RedisService:         RedisService,
+	}
+}
+
+// provider はルートの :provider の外部認証を返す
+// 登録されていない場合はレスポンスを返す
+func (om *OIDCManager) provider(c *gin.Context) (utils.OIDCProvider, bool) {
+	provider, ok := om.Providers[c.Param("provider")]
+	if !ok {
+		response := utils.ErrorMessageResponse{
+			Result: "対応していない外部認証です。",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return nil, false
+	}
+	return provider, true
+}
+
+// oidcAuth はstateを発行して外部認証の認可画面へリダイレクトする
+//
+// 引数:
+//   - c: Ginコンテキスト
+//   - purpose: 外部認証の用途(oauthPurpose*)
+//   - userId: 連携の場合は認証を開始したユーザーID
+//
+
+func (om *OIDCManager) oidcAuth(c *gin.Context, purpose string, userId int) {
+	provider, ok := om.provider(c)
+	if !ok {
+		return
+	}
+	name := provider.Config().Name
+
+	state, data, err := issueOAuthState(c, om.RedisService, name, purpose, userId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "外部認証の開始に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	authURL, err := provider.AuthURL(config.OAuthRedirectURI(name, purpose), state, data.CodeVerifier, data.Nonce)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "外部認証の開始に失敗しました。",
+		}
+		c.JSON(http.StatusBadGateway, response)
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// oidcCallback は認可コードをトークンと交換し、IDトークンで確認した外部アカウントを返す
+// 検証に失敗した場合はレスポンスを返す
+//
+// 引数:
+//   - c: Ginコンテキスト
+//   - purpose: 外部認証の用途(oauthPurpose*)
+//
+// 戻り値:
+//
+//	戻り値1: 外部認証
+//	戻り値2: 外部アカウントの情報
+//	戻り値3: 認証開始時に保存した値
+//	戻り値4: 認可コードと交換したトークン
+//	戻り値5: 検証に成功した場合はtrue
+//
+
+func (om *OIDCManager) oidcCallback(c *gin.Context, purpose string) (utils.OIDCProvider, OIDCPrams, oauthState, *oauth2.Token, bool) {
+	provider, ok := om.provider(c)
+	if !ok {
+		return nil, OIDCPrams{}, oauthState{}, nil, false
+	}
+	providerConfig := provider.Config()
+
+	code := c.Query("code")
+	state := c.Query("state")
+
+	validator := validation.RequestOAuthCallbackData{
+		Code:  code,
+		State: state,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return nil, OIDCPrams{}, oauthState{}, nil, false
+	}
+
+	data, ok := consumeOAuthState(c, om.RedisService, state, providerConfig.Name, purpose)
+	if !ok {
+		return nil, OIDCPrams{}, oauthState{}, nil, false
+	}
+
+	token, err := provider.Exchange(c, code, config.OAuthRedirectURI(providerConfig.Name, purpose), data.CodeVerifier)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "外部認証に失敗しました。",
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return nil, OIDCPrams{}, oauthState{}, nil, false
+	}
+
+	rawIdToken, _ := token.Extra("id_token").(string)
+	claims, err := provider.VerifyIdToken(rawIdToken, data.Nonce)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "外部アカウントの確認に失敗しました。",
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return nil, OIDCPrams{}, oauthState{}, nil, false
+	}
+
+	// メールアドレスの取得を許可していない場合はIDトークンに含まれない
+	if claims.Email == "" {
+		response := utils.ErrorMessageResponse{
+			Result: "メールアドレスを取得できない外部アカウントです。",
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return nil, OIDCPrams{}, oauthState{}, nil, false
+	}
+
+	if providerConfig.RequireEmailVerified && !claims.EmailVerified {
+		response := utils.ErrorMessageResponse{
+			Result: "メールアドレスが確認されていない外部アカウントです。",
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return nil, OIDCPrams{}, oauthState{}, nil, false
+	}
+
+	// IDトークンに名前がない場合はユーザー情報から取得する
+	userName := claims.Name
+	if userName == "" {
+		if userInfo, err := provider.UserInfo(c, token); err == nil {
+			userName = userInfo.Name
+		}
+	}
+	if userName == "" {
+		userName = claims.Email
+	}
+
+	params := OIDCPrams{
+		UserEmail:  claims.Email,
+		UserName:   userName,
+		ProviderId: claims.Subject,
+	}
+	return provider, params, data, token, true
+}
+
+// OIDCSignInAuth は外部認証でのサインインを開始するAPI
+func (om *OIDCManager) OIDCSignInAuth(c *gin.Context) {
+	om.oidcAuth(c, oauthPurposeSignIn, 0)
+}
+
+// OIDCSignUpAuth は外部認証での登録を開始するAPI
+func (om *OIDCManager) OIDCSignUpAuth(c *gin.Context) {
+	om.oidcAuth(c, oauthPurposeSignUp, 0)
+}
+
+// OIDCDeleteAuth は外部認証で登録したユーザーの削除を開始するAPI
+func (om *OIDCManager) OIDCDeleteAuth(c *gin.Context) {
+	om.oidcAuth(c, oauthPurposeDelete, 0)
+}
+
+// OIDCLinkAuth はサインイン中のユーザーへの外部アカウントの連携を開始するAPI
+func (om *OIDCManager) OIDCLinkAuth(c *gin.Context) {
+	userId, _ := c.Get(utils.UserId)
+	signInUserId, _ := userId.(int)
+	om.oidcAuth(c, oauthPurposeLink, signInUserId)
+}
+
+// OIDCSignInCallback は外部認証の認可コードでサインインするAPI
+// 二段階認証が有効な場合はトークンを発行せず、ワンタイムパスワードの確認を求める
+func (om *OIDCManager) OIDCSignInCallback(c *gin.Context) {
+	var err error
+	provider, params, _, _, ok := om.oidcCallback(c, oauthPurposeSignIn)
+	if !ok {
+		return
+	}
+
+	// 外部認証のユーザーIDで連携しているユーザーを特定する
+	result, err := findExternalUser(provider.Config().Name, params.ProviderId, params.UserEmail)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "ユーザー情報取得に失敗しました。",
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// 管理者に無効化されたユーザーはサインインできない
+	role, ok := signInRole(c, result.UserId)
+	if !ok {
+		return
+	}
+
+	// 二段階認証が有効な場合はワンタイムパスワードの確認後にサインインを完了する
+	totpFetcher, _, _ := models.NewTotpDataFetcher(config.GetDataBaseSource())
+	totp, err := totpFetcher.GetTotp(result.UserId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "二段階認証の設定の取得に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	if totp.Enabled {
+		signInFetcher := &apiSignDataFetcher{
+			UtilsFetcher:         om.UtilsFetcher,
+			EmailTemplateService: om.EmailTemplateService,
+			RedisService:         om.RedisService,
+		}
+		signInFetcher.issueTotpChallenge(c, models.SignInData{
+			UserId:    result.UserId,
+			UserEmail: result.UserEmail,
+		}, provider.Config().Name)
+		return
+	}
+
+	sessionId := uuid.New().String()
+	// UtilsFetcher を使用してトークンを生成
+	newToken, err := om.UtilsFetcher.NewToken(result.UserId, sessionId, role, utils.AuthTokenHour)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "新規トークンの生成に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	refreshToken, err := om.UtilsFetcher.RefreshToken(result.UserId, sessionId, utils.RefreshAuthTokenHour)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "リフレッシュトークンの生成に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if err := recordSession(c, result.UserId, sessionId, provider.Config().Name); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "セッションの記録に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	c.SetCookie(utils.UserId, fmt.Sprintf("%d", result.UserId), 0, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
+	c.SetCookie(utils.AuthToken, newToken, utils.AuthTokenHour*utils.SecondsInHour, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
+	c.SetCookie(utils.RefreshAuthToken, refreshToken, utils.RefreshAuthTokenHour*utils.SecondsInHour, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
+
+	subject, body, err := om.EmailTemplateService.PostSignInTemplate(
+		result.UserEmail,
+		om.UtilsFetcher.DateTimeStr(time.Now(), "2006年01月02日 15:04"),
+	)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メールテンプレート生成エラー(サインイン): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// メール送信ユーティリティを呼び出し
+	if err := om.UtilsFetcher.SendMail(result.UserEmail, subject, body, true); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メール送信エラー(サインイン): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[SignInResult]{
+		// Token: token,
+		Result: SignInResult{
+			UserId:       result.UserId,
+			UserEmail:    result.UserEmail,
+			UserPassword: "",
+		},
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// OIDCSignUpCallback は外部認証の認可コードでユーザーを登録するAPI
+func (om *OIDCManager) OIDCSignUpCallback(c *gin.Context) {
+	var err error
+	provider, params, _, _, ok := om.oidcCallback(c, oauthPurposeSignUp)
+	if !ok {
+		return
+	}
+
+	dbFetcher, _, _ := models.NewIdentityDataFetcher(config.GetDataBaseSource())
+	registerData := models.RequestSignUpData{
+		UserEmail:    params.UserEmail,
+		UserPassword: provider.Config().Name,
+		UserName:     params.UserName,
+	}
+	identity := models.IdentityData{
+		Provider:        provider.Config().Name,
+		ProviderSubject: params.ProviderId,
+		ProviderEmail:   params.UserEmail,
+	}
+	userId, err := dbFetcher.PostIdentitySignUp(registerData, identity)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "既に登録されたメールアドレスか、連携済みの外部アカウントです。",
+		}
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+
+	sessionId := uuid.New().String()
+	// UtilsFetcher を使用してトークンを生成
+	// 登録したユーザーは一般ユーザー
+	newToken, err := om.UtilsFetcher.NewToken(userId, sessionId, enum.ROLE_USER, utils.AuthTokenHour)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "新規トークンの生成に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	refreshToken, err := om.UtilsFetcher.RefreshToken(userId, sessionId, utils.RefreshAuthTokenHour)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "リフレッシュトークンの生成に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if err := recordSession(c, userId, sessionId, provider.Config().Name); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "セッションの記録に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	c.SetCookie(utils.UserId, fmt.Sprintf("%d", userId), 0, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
+	c.SetCookie(utils.AuthToken, newToken, utils.AuthTokenHour*utils.SecondsInHour, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
+	c.SetCookie(utils.RefreshAuthToken, refreshToken, utils.RefreshAuthTokenHour*utils.SecondsInHour, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
+
+	subject, body, err := om.EmailTemplateService.PostSignUpTemplate(
+		params.UserName,
+		params.UserEmail,
+		om.UtilsFetcher.DateTimeStr(time.Now(), "2006年01月02日 15:04"),
+	)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メールテンプレート生成エラー(登録): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// メール送信ユーティリティを呼び出し
+	if err := om.UtilsFetcher.SendMail(params.UserEmail, subject, body, true); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メール送信エラー(登録): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// サインアップ成功のレスポンス
+	response := utils.ResponseData[SignUpResult]{
+		Result: SignUpResult{
+			UserId:    userId,
+			UserEmail: params.UserEmail,
+		},
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// OIDCDeleteCallback は外部認証の認可コードで本人を確認し、ユーザーを退会状態にするAPI
+func (om *OIDCManager) OIDCDeleteCallback(c *gin.Context) {
+	var err error
+	provider, params, _, token, ok := om.oidcCallback(c, oauthPurposeDelete)
+	if !ok {
+		return
+	}
+
+	// 削除する登録ユーザー取得
+	result, err := findExternalUser(provider.Config().Name, params.ProviderId, params.UserEmail)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// ユーザーを削除する前に外部認証のアクセストークンを無効化し、アプリとの連携を解除する
+	if err := provider.Revoke(c, token); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "外部認証のアクセストークンの無効化に失敗しました。",
+		}
+		c.JSON(http.StatusBadGateway, response)
+		return
+	}
+
+	now := time.Now()
+	purgeAt := accountPurgeAt(now)
+
+	// 退会後にメールで送信する復元用のトークンを先に発行する
+	restoreToken, err := issueAccountRestoreToken(om.RedisService, result.UserId, purgeAt)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "アカウント復元用のトークンの発行に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	deleteDbFetcher, _, _ := models.NewSignDataFetcher(
+		config.GetDataBaseSource(),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+	)
+	data := models.RequestSignInDeleteData{
+		UserEmail: result.UserEmail,
+	}
+	err = deleteDbFetcher.DeleteSignIn(result.UserId, data, purgeAt)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// ユーザーに発行済みの全てのトークンを失効させる
+	if err := om.UtilsFetcher.RevokeUserTokens(result.UserId); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "トークンの失効に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	revokeCookieTokens(c, om.UtilsFetcher)
+
+	// Cookie無効化
+	c.SetCookie(utils.UserId, "", 0, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
+	c.SetCookie(utils.AuthToken, "", -1, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
+	c.SetCookie(utils.RefreshAuthToken, "", -1, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
+
+	subject, body, err := om.EmailTemplateService.AccountDeactivatedTemplate(
+		params.UserName,
+		result.UserEmail,
+		accountRestoreLink(restoreToken),
+		om.UtilsFetcher.DateTimeStr(purgeAt, "2006年01月02日 15:04"),
+		om.UtilsFetcher.DateTimeStr(now, "2006年01月02日 15:04"),
+	)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メールテンプレート生成エラー(削除): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// メール送信ユーティリティを呼び出し
+	if err := om.UtilsFetcher.SendMail(result.UserEmail, subject, body, true); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "メール送信エラー(削除): " + err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: provider.Config().Name + "外部認証の削除が成功しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// OIDCLinkCallback は外部認証の認可コードで確認した外部アカウントをサインイン中のユーザーに連携するAPI
+func (om *OIDCManager) OIDCLinkCallback(c *gin.Context) {
+	provider, params, data, _, ok := om.oidcCallback(c, oauthPurposeLink)
+	if !ok {
+		return
+	}
+
+	// 連携を開始したユーザーと異なる場合は連携しない
+	if !isSignInUser(c, data.UserId) {
+		signInUserMismatch(c)
+		return
+	}
+
+	dbFetcher, _, _ := models.NewIdentityDataFetcher(config.GetDataBaseSource())
+	err := dbFetcher.LinkIdentity(data.UserId, models.IdentityData{
+		Provider:        provider.Config().Name,
+		ProviderSubject: params.ProviderId,
+		ProviderEmail:   params.UserEmail,
+	})
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: provider.Config().Name + "外部認証を連携しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
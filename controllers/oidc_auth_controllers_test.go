@@ -0,0 +1,1829 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"time"
+
+	"server/config"
+	"server/enum"
+	mock_templates "server/mock/templates"
+	mock_utils "server/mock/utils"
+	"server/models"
+	"server/templates"
+	"server/test_utils"
+	"server/utils"
+	"testing"
+
+	mock_config "server/mock/config"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const testLineChannelSecret = "test-line-channel-secret"
+
+// newGoogleTestFlow はテスト用の外部認証サーバーをGoogleと同じ設定で登録する
+func newGoogleTestFlow(t *testing.T) *oauthTestFlow {
+	flow := newOAuthTestFlow(t)
+	flow.provider = utils.NewOIDCProvider(config.OIDCProviderConfig{
+		Name:                 "google",
+		Issuer:               flow.fake.Issuer(),
+		ClientID:             testOAuthClientId,
+		ClientSecret:         "test-google-client-secret",
+		Scopes:               []string{"openid", "email", "profile"},
+		AuthParams:           map[string]string{"prompt": "select_account"},
+		RequireEmailVerified: true,
+	})
+	return flow
+}
+
+// newLineTestFlow はLINEのIDトークン(HS256)を発行するテスト用の外部認証サーバーをLINEと同じ設定で登録する
+func newLineTestFlow(t *testing.T) *oauthTestFlow {
+	flow := newOAuthTestFlow(t)
+	flow.fake.Subject = "U1234567890"
+	flow.fake.SigningSecret = []byte(testLineChannelSecret)
+	flow.provider = utils.NewOIDCProvider(config.OIDCProviderConfig{
+		Name:                 "line",
+		Issuer:               flow.fake.Issuer(),
+		ClientID:             testOAuthClientId,
+		ClientSecret:         testLineChannelSecret,
+		Scopes:               []string{"openid", "profile", "email"},
+		SymmetricIdToken:     true,
+		TokenAuthInParams:    true,
+		RevocationTokenParam: "access_token",
+	})
+	return flow
+}
+
+// params は登録した外部認証を選ぶルートのパラメータ
+func (flow *oauthTestFlow) params() gin.Params {
+	return gin.Params{{Key: "provider", Value: flow.provider.Config().Name}}
+}
+
+// oidcManager はテスト用の外部認証サーバーに接続するOIDCManagerを返す
+func (flow *oauthTestFlow) oidcManager(utilsFetcher utils.UtilsFetcher, emailTemplateService templates.EmailTemplateService) OIDCManager {
+	return OIDCManager{
+		Providers: map[string]utils.OIDCProvider{
+			flow.provider.Config().Name: flow.provider,
+		},
+		EmailTemplateService: emailTemplateService,
+		UtilsFetcher:         utilsFetcher,
+		RedisService:         flow.redis,
+	}
+}
+
+// oidcAuthorize は外部認証を開始し、認可コード・stateと、stateのクッキーを返す
+func (flow *oauthTestFlow) oidcAuthorize(t *testing.T, purpose string, userId int) (string, string, *http.Cookie) {
+	oidcManager := flow.oidcManager(nil, nil)
+	start := map[string]func(c *gin.Context){
+		oauthPurposeSignIn: oidcManager.OIDCSignInAuth,
+		oauthPurposeSignUp: oidcManager.OIDCSignUpAuth,
+		oauthPurposeDelete: oidcManager.OIDCDeleteAuth,
+		oauthPurposeLink:   oidcManager.OIDCLinkAuth,
+	}[purpose]
+	return flow.authorize(t, func(c *gin.Context) {
+		c.Params = flow.params()
+		start(c)
+	}, userId)
+}
+
+// oidcCallbackRequest は登録した外部認証のコールバックのリクエストを作成する
+func (flow *oauthTestFlow) oidcCallbackRequest(purpose, code, state string, cookie *http.Cookie) (*httptest.ResponseRecorder, *gin.Context) {
+	path := fmt.Sprintf("/api/auth/%s/%s/callback", flow.provider.Config().Name, purpose)
+	w, c := flow.callbackRequest(path, code, state, cookie)
+	c.Params = flow.params()
+	return w, c
+}
+
+// oidcCallback は外部認証を開始してから認可コードを受け取るまでを行い、コールバックのリクエストを作成する
+func (flow *oauthTestFlow) oidcCallback(t *testing.T, purpose string) (*httptest.ResponseRecorder, *gin.Context) {
+	code, state, cookie := flow.oidcAuthorize(t, purpose, 0)
+	return flow.oidcCallbackRequest(purpose, code, state, cookie)
+}
+
+func TestOIDCAuth(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	flow := newGoogleTestFlow(t)
+
+	t.Run("OIDCSignInAuth 認可画面へリダイレクト", func(t *testing.T) {
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/auth/google/signin", nil)
+		c.Params = flow.params()
+
+		oidcManager := flow.oidcManager(nil, nil)
+		oidcManager.OIDCSignInAuth(c)
+
+		assert.Equal(t, http.StatusFound, w.Code)
+
+		location, err := url.Parse(w.Header().Get("Location"))
+		assert.NoError(t, err)
+		assert.Equal(t, flow.fake.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+
+		query := location.Query()
+		assert.Equal(t, testOAuthClientId, query.Get("client_id"))
+		assert.Equal(t, config.OAuthRedirectURI("google", oauthPurposeSignIn), query.Get("redirect_uri"))
+		assert.Equal(t, "S256", query.Get("code_challenge_method"))
+		assert.Equal(t, "openid email profile", query.Get("scope"))
+		assert.Equal(t, "select_account", query.Get("prompt"))
+
+		// stateは認証を開始したブラウザのクッキーとRedisに保存する
+		state := query.Get("state")
+		var cookieState string
+		for _, cookie := range w.Result().Cookies() {
+			if cookie.Name == utils.OauthState {
+				cookieState = cookie.Value
+				assert.True(t, cookie.HttpOnly)
+			}
+		}
+		assert.Equal(t, state, cookieState)
+
+		var saved oauthState
+		err = json.Unmarshal([]byte(flow.saved[oauthStateKey(state)]), &saved)
+		assert.NoError(t, err)
+		assert.Equal(t, "google", saved.Provider)
+		assert.Equal(t, oauthPurposeSignIn, saved.Purpose)
+		assert.Equal(t, query.Get("nonce"), saved.Nonce)
+
+		// チャレンジはコードベリファイアのS256
+		verifier := sha256.Sum256([]byte(saved.CodeVerifier))
+		assert.Equal(t, base64.RawURLEncoding.EncodeToString(verifier[:]), query.Get("code_challenge"))
+	})
+
+	t.Run("OIDCLinkAuth 認証を開始したユーザーを保存", func(t *testing.T) {
+
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("GET", "/api/auth/google/link", nil), 5, "session")
+		c.Params = flow.params()
+
+		oidcManager := flow.oidcManager(nil, nil)
+		oidcManager.OIDCLinkAuth(c)
+
+		assert.Equal(t, http.StatusFound, w.Code)
+
+		location, _ := url.Parse(w.Header().Get("Location"))
+		var saved oauthState
+		err := json.Unmarshal([]byte(flow.saved[oauthStateKey(location.Query().Get("state"))]), &saved)
+		assert.NoError(t, err)
+		assert.Equal(t, oauthPurposeLink, saved.Purpose)
+		assert.Equal(t, 5, saved.UserId)
+	})
+
+	t.Run("OIDCSignInAuth stateの保存エラー", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedis := mock_config.NewMockRedisService(ctrl)
+		mockRedis.EXPECT().
+			RedisSet(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("redis接続エラー"))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/auth/google/signin", nil)
+		c.Params = flow.params()
+
+		oidcManager := flow.oidcManager(nil, nil)
+		oidcManager.RedisService = mockRedis
+		oidcManager.OIDCSignInAuth(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assertErrorMessage(t, w, "外部認証の開始に失敗しました。")
+	})
+
+	t.Run("OIDCSignInAuth 登録されていない外部認証", func(t *testing.T) {
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/auth/unknown/signin", nil)
+		c.Params = gin.Params{{Key: "provider", Value: "unknown"}}
+
+		oidcManager := flow.oidcManager(nil, nil)
+		oidcManager.OIDCSignInAuth(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assertErrorMessage(t, w, "対応していない外部認証です。")
+	})
+
+	t.Run("OIDCSignInAuth ディスカバリードキュメントの取得エラー", func(t *testing.T) {
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/auth/google/signin", nil)
+		c.Params = flow.params()
+
+		oidcManager := flow.oidcManager(nil, nil)
+		// 発行者にディスカバリードキュメントがない
+		oidcManager.Providers["google"] = utils.NewOIDCProvider(config.OIDCProviderConfig{
+			Name:     "google",
+			Issuer:   flow.fake.URL + "/not_found",
+			ClientID: testOAuthClientId,
+		})
+		oidcManager.OIDCSignInAuth(c)
+
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		assertErrorMessage(t, w, "外部認証の開始に失敗しました。")
+	})
+}
+
+func TestOIDCSignInCallback(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	// Googleとして登録したテスト用の外部認証サーバー
+	flow := newGoogleTestFlow(t)
+
+	// セッションの記録はDBに接続しない
+	sessionPatches := patchInsertSession(nil)
+	defer sessionPatches.Reset()
+	rolePatches := patchGetUserRole(models.UserRoleData{Role: enum.ROLE_USER}, nil)
+	defer rolePatches.Reset()
+	totpPatches := patchGetTotp(models.TotpData{}, nil)
+	defer totpPatches.Reset()
+
+	ResMock := models.ExternalAuthData{
+		UserId:    1,
+		UserEmail: "test@example.com",
+	}
+
+	t.Run("OIDCSignInCallback バリデーション必須チェック", func(t *testing.T) {
+
+		w, c := test_utils.CreateTestRequest(
+			"GET", "/api/auth/google/signin/callback?code=&state=",
+			nil,
+			map[string]string{
+				"code":  "",
+				"state": "",
+			},
+		)
+		c.Params = flow.params()
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignInCallback(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var responseBody utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+
+		expectedErrorMessage := utils.ErrorValidationResponse{
+			Result: []utils.ErrorMessages{
+				{
+					Field:   "code",
+					Message: "認可コードは必須です。",
+				},
+				{
+					Field:   "state",
+					Message: "stateは必須です。",
+				},
+			},
+		}
+		test_utils.SortErrorMessages(responseBody.Result)
+		test_utils.SortErrorMessages(expectedErrorMessage.Result)
+		assert.Equal(t, responseBody, expectedErrorMessage)
+	})
+
+	t.Run("OIDCSignInCallback stateがクッキーと一致しない", func(t *testing.T) {
+
+		code, state, _ := flow.oidcAuthorize(t, oauthPurposeSignIn, 0)
+
+		// 認証を開始していないブラウザからのコールバック
+		w, c := flow.oidcCallbackRequest(oauthPurposeSignIn, code, state,
+			&http.Cookie{Name: utils.OauthState, Value: "other-state"},
+		)
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignInCallback(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assertErrorMessage(t, w, "認証の有効期限が切れたか、不正なリクエストです。最初からやり直してください。")
+	})
+
+	t.Run("OIDCSignInCallback stateのクッキーなし", func(t *testing.T) {
+
+		code, state, _ := flow.oidcAuthorize(t, oauthPurposeSignIn, 0)
+		w, c := flow.oidcCallbackRequest(oauthPurposeSignIn, code, state, nil)
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignInCallback(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assertErrorMessage(t, w, "認証の有効期限が切れたか、不正なリクエストです。最初からやり直してください。")
+	})
+
+	t.Run("OIDCSignInCallback stateの再利用", func(t *testing.T) {
+
+		code, state, cookie := flow.oidcAuthorize(t, oauthPurposeSignIn, 0)
+
+		patches := patchGetIdentityUser(ResMock, nil)
+		defer patches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("", fmt.Errorf("トークン生成エラー"))
+
+		oidcManager := flow.oidcManager(mockUtilsFetcher, templates.NewEmailTemplateManager())
+		_, c := flow.oidcCallbackRequest(oauthPurposeSignIn, code, state, cookie)
+		oidcManager.OIDCSignInCallback(c)
+
+		// 同じstateのコールバックは2回目以降受け付けない
+		w, c := flow.oidcCallbackRequest(oauthPurposeSignIn, code, state, cookie)
+		oidcManager.OIDCSignInCallback(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assertErrorMessage(t, w, "認証の有効期限が切れたか、不正なリクエストです。最初からやり直してください。")
+	})
+
+	t.Run("OIDCSignInCallback 用途の異なるstate", func(t *testing.T) {
+
+		// 登録で開始した認証をサインインのコールバックに使用する
+		code, state, cookie := flow.oidcAuthorize(t, oauthPurposeSignUp, 0)
+		w, c := flow.oidcCallbackRequest(oauthPurposeSignIn, code, state, cookie)
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignInCallback(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assertErrorMessage(t, w, "認証の有効期限が切れたか、不正なリクエストです。最初からやり直してください。")
+	})
+
+	t.Run("OIDCSignInCallback コードベリファイア不一致", func(t *testing.T) {
+
+		code, state, cookie := flow.oidcAuthorize(t, oauthPurposeSignIn, 0)
+
+		// 保存したコードベリファイアを差し替え、認可リクエストのチャレンジと一致させない
+		key := oauthStateKey(state)
+		var saved oauthState
+		_ = json.Unmarshal([]byte(flow.saved[key]), &saved)
+		saved.CodeVerifier = oauth2.GenerateVerifier()
+		value, _ := json.Marshal(saved)
+		flow.saved[key] = string(value)
+
+		w, c := flow.oidcCallbackRequest(oauthPurposeSignIn, code, state, cookie)
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignInCallback(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assertErrorMessage(t, w, "外部認証に失敗しました。")
+	})
+
+	t.Run("OIDCSignInCallback 認可コード不正", func(t *testing.T) {
+
+		_, state, cookie := flow.oidcAuthorize(t, oauthPurposeSignIn, 0)
+		w, c := flow.oidcCallbackRequest(oauthPurposeSignIn, "invalid-code", state, cookie)
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignInCallback(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assertErrorMessage(t, w, "外部認証に失敗しました。")
+	})
+
+	invalidIdTokenTests := []struct {
+		name   string
+		claims jwt.MapClaims
+	}{
+		{name: "対象者(aud)不正", claims: jwt.MapClaims{"aud": "other-client"}},
+		{name: "発行者(iss)不正", claims: jwt.MapClaims{"iss": "https://evil.example.com"}},
+		{name: "有効期限切れ", claims: jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}},
+		{name: "nonce不一致", claims: jwt.MapClaims{"nonce": "other-nonce"}},
+		{name: "ユーザーIDなし", claims: jwt.MapClaims{"sub": ""}},
+	}
+	for _, tt := range invalidIdTokenTests {
+		t.Run("OIDCSignInCallback IDトークン"+tt.name, func(t *testing.T) {
+
+			flow.fake.OverrideClaims = tt.claims
+			defer func() { flow.fake.OverrideClaims = nil }()
+
+			w, c := flow.oidcCallback(t, oauthPurposeSignIn)
+
+			oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+			oidcManager.OIDCSignInCallback(c)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assertErrorMessage(t, w, "外部アカウントの確認に失敗しました。")
+		})
+	}
+
+	t.Run("OIDCSignInCallback IDトークンの署名不正", func(t *testing.T) {
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		// 公開鍵を取得してから、取得済みの公開鍵と異なる鍵でIDトークンに署名する
+		_, err := flow.provider.VerifyIdToken(flow.fake.SignIdToken(jwt.MapClaims{}), "")
+		assert.Error(t, err)
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err)
+		key := flow.fake.Key
+		flow.fake.Key = otherKey
+		defer func() { flow.fake.Key = key }()
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignIn)
+		oidcManager.OIDCSignInCallback(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assertErrorMessage(t, w, "外部アカウントの確認に失敗しました。")
+	})
+
+	t.Run("OIDCSignInCallback メールアドレス未確認", func(t *testing.T) {
+
+		flow.fake.EmailVerified = false
+		defer func() { flow.fake.EmailVerified = true }()
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignIn)
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignInCallback(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assertErrorMessage(t, w, "メールアドレスが確認されていない外部アカウントです。")
+	})
+
+	t.Run("OIDCSignInCallback DB取得エラー", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignIn)
+
+		resMock := models.ExternalAuthData{}
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetIdentityUser",
+			func(_ *models.IdentityDataFetcher, Provider, ProviderSubject string) (models.ExternalAuthData, error) {
+				return resMock, fmt.Errorf("sql取得失敗")
+			})
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetLegacyExternalUser",
+			func(_ *models.IdentityDataFetcher, Provider, UserEmail string) (models.ExternalAuthData, error) {
+				return resMock, fmt.Errorf("sql取得失敗")
+			})
+		defer patches.Reset()
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignInCallback(c)
+
+		// ステータスコードの確認
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		var responseBody utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+
+		expectedErrorMessage := utils.ErrorMessageResponse{
+			Result: "ユーザー情報取得に失敗しました。",
+		}
+		assert.Equal(t, responseBody.Result, expectedErrorMessage.Result)
+	})
+
+	t.Run("OIDCSignInCallback トークン生成に失敗 1", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignIn)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetIdentityUser",
+			func(_ *models.IdentityDataFetcher, Provider, ProviderSubject string) (models.ExternalAuthData, error) {
+				return ResMock, nil
+			})
+		defer patches.Reset()
+
+		// gomock のコントローラを作成
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// UtilsFetcher のモックを作成
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+
+		// モックの挙動を定義
+		mockUtilsFetcher.EXPECT().
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("", fmt.Errorf("トークン生成エラー"))
+
+		oidcManager := flow.oidcManager(mockUtilsFetcher, templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignInCallback(c)
+
+		// ステータスコードの確認
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		// レスポンスボディの確認
+		var responseBody utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+
+		expectedErrorMessage := utils.ErrorMessageResponse{
+			Result: "新規トークンの生成に失敗しました。",
+		}
+		assert.Equal(t, responseBody, expectedErrorMessage)
+	})
+
+	t.Run("OIDCSignInCallback トークン生成に失敗 2", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignIn)
+
+		// gomock のコントローラ作成
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetIdentityUser",
+			func(_ *models.IdentityDataFetcher, Provider, ProviderSubject string) (models.ExternalAuthData, error) {
+				return ResMock, nil
+			})
+		defer patches.Reset()
+
+		// UtilsFetcher のモックを作成
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+
+		// モックの挙動を定義
+		mockUtilsFetcher.EXPECT().
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("token", nil)
+
+		mockUtilsFetcher.EXPECT().
+			RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("", fmt.Errorf("トークン生成エラー"))
+
+		oidcManager := flow.oidcManager(mockUtilsFetcher, templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignInCallback(c)
+
+		// レスポンスボディの確認
+		var responseBody utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+
+		expectedErrorMessage := utils.ErrorMessageResponse{
+			Result: "リフレッシュトークンの生成に失敗しました。",
+		}
+		assert.Equal(t, responseBody, expectedErrorMessage)
+	})
+
+	t.Run("OIDCSignInCallback メールテンプレート生成エラー(サインイン)", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignIn)
+
+		// gomock のコントローラ作成
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetIdentityUser",
+			func(_ *models.IdentityDataFetcher, Provider, ProviderSubject string) (models.ExternalAuthData, error) {
+				return ResMock, nil
+			})
+		defer patches.Reset()
+
+		// UtilsFetcher のモックを作成
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		// EmailTemplateService のモックを作成
+		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
+
+		// モックの挙動を定義
+		mockUtilsFetcher.EXPECT().
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("new_token", nil)
+
+		mockUtilsFetcher.EXPECT().
+			RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("refresh_token", nil)
+
+		mockUtilsFetcher.EXPECT().
+			DateTimeStr(gomock.Any(), gomock.Any()).
+			Return("2024年12月2日")
+
+		mockEmailTemplateService.EXPECT().
+			PostSignInTemplate(gomock.Any(), gomock.Any()).
+			Return("", "", fmt.Errorf("テンプレート生成エラー"))
+
+		oidcManager := flow.oidcManager(mockUtilsFetcher, mockEmailTemplateService)
+		oidcManager.OIDCSignInCallback(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var responseBody utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+
+		expectedErrorMessage := utils.ErrorMessageResponse{
+			Result: "メールテンプレート生成エラー(サインイン): テンプレート生成エラー",
+		}
+		assert.Equal(t, responseBody.Result, expectedErrorMessage.Result)
+	})
+
+	t.Run("OIDCSignInCallback メール送信エラー(サインイン)", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignIn)
+
+		// gomock のコントローラ作成
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetIdentityUser",
+			func(_ *models.IdentityDataFetcher, Provider, ProviderSubject string) (models.ExternalAuthData, error) {
+				return ResMock, nil
+			})
+		defer patches.Reset()
+
+		// UtilsFetcher のモックを作成
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+
+		// モックの挙動を定義
+		mockUtilsFetcher.EXPECT().
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("new_token", nil)
+
+		mockUtilsFetcher.EXPECT().
+			RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("refresh_token", nil)
+
+		mockUtilsFetcher.EXPECT().
+			DateTimeStr(gomock.Any(), gomock.Any()).
+			Return("2024年12月2日")
+
+		mockUtilsFetcher.EXPECT().
+			SendMail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("メール送信エラー"))
+
+		oidcManager := flow.oidcManager(mockUtilsFetcher, templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignInCallback(c)
+
+		// ステータスコードの確認
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var responseBody utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+
+		expectedErrorMessage := utils.ErrorMessageResponse{
+			Result: "メール送信エラー(サインイン): メール送信エラー",
+		}
+		assert.Equal(t, responseBody.Result, expectedErrorMessage.Result)
+	})
+
+	t.Run("OIDCSignInCallback result 成功", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignIn)
+
+		// gomock のコントローラ作成
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		resMock := models.ExternalAuthData{
+			UserId:    3,
+			UserEmail: "test@example.com",
+		}
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetIdentityUser",
+			func(_ *models.IdentityDataFetcher, Provider, ProviderSubject string) (models.ExternalAuthData, error) {
+				return resMock, nil
+			})
+		defer patches.Reset()
+
+		// UtilsFetcher のモックを作成
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+
+		// モックの挙動を定義
+		mockUtilsFetcher.EXPECT().
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("new_token", nil)
+
+		mockUtilsFetcher.EXPECT().
+			RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("refresh_token", nil)
+
+		mockUtilsFetcher.EXPECT().
+			DateTimeStr(gomock.Any(), gomock.Any()).
+			Return("2024年12月2日")
+
+		mockUtilsFetcher.EXPECT().
+			SendMail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil)
+
+		oidcManager := flow.oidcManager(mockUtilsFetcher, templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignInCallback(c)
+
+		// ステータスコードの確認
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var responseBody utils.ResponseData[SignInResult]
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+
+		// assert.Equal(t, len(responseBody.Token), 120)
+
+		expectedOk := utils.ResponseData[SignInResult]{
+			Result: SignInResult{
+				UserId:       3,
+				UserEmail:    "test@example.com",
+				UserPassword: "",
+			},
+		}
+		assert.Equal(t, responseBody.Result.UserId, expectedOk.Result.UserId)
+		assert.Equal(t, responseBody.Result.UserEmail, expectedOk.Result.UserEmail)
+		assert.Equal(t, responseBody.Result.UserPassword, expectedOk.Result.UserPassword)
+	})
+
+	t.Run("OIDCSignInCallback 二段階認証が有効な場合はトークンを発行しない", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignIn)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetIdentityUser",
+			func(_ *models.IdentityDataFetcher, Provider, ProviderSubject string) (models.ExternalAuthData, error) {
+				return models.ExternalAuthData{UserId: 3, UserEmail: "test@example.com"}, nil
+			})
+		defer patches.Reset()
+		enabledPatches := patchGetTotp(enabledTotp(), nil)
+		defer enabledPatches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// stateの確認は外部認証のテスト用のRedisで行い、チャレンジの保存のみを確認する
+		var challenge totpChallenge
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGetDel(gomock.Any()).DoAndReturn(flow.redis.RedisGetDel)
+		mockRedisService.EXPECT().
+			RedisSet(gomock.Any(), gomock.Any(), totpChallengeDuration).
+			DoAndReturn(func(key string, value interface{}, duration time.Duration) error {
+				challenge = value.(totpChallenge)
+				return nil
+			})
+
+		// トークンの発行・メール送信は行わない
+		oidcManager := flow.oidcManager(mock_utils.NewMockUtilsFetcher(ctrl), templates.NewEmailTemplateManager())
+		oidcManager.RedisService = mockRedisService
+		oidcManager.OIDCSignInCallback(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var responseBody utils.ResponseData[TotpChallengeResult]
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+		assert.True(t, responseBody.Result.TwoFactorRequired)
+		assert.NotEmpty(t, responseBody.Result.ChallengeId)
+		assert.Equal(t, totpChallenge{
+			UserId:     3,
+			UserEmail:  "test@example.com",
+			SignInType: "google",
+		}, challenge)
+		assert.Nil(t, responseCookie(w, utils.AuthToken))
+		assert.Nil(t, responseCookie(w, utils.RefreshAuthToken))
+	})
+}
+
+func TestOIDCSignUpCallback(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	// Googleとして登録したテスト用の外部認証サーバー
+	flow := newGoogleTestFlow(t)
+
+	// セッションの記録はDBに接続しない
+	sessionPatches := patchInsertSession(nil)
+	defer sessionPatches.Reset()
+
+	t.Run("OIDCSignUpCallback バリデーション必須チェック", func(t *testing.T) {
+
+		w, c := test_utils.CreateTestRequest(
+			"GET", "/api/auth/google/signup/callback?code=&state=",
+			nil,
+			map[string]string{
+				"code":  "",
+				"state": "",
+			},
+		)
+		c.Params = flow.params()
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignUpCallback(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var responseBody utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+
+		expectedErrorMessage := utils.ErrorValidationResponse{
+			Result: []utils.ErrorMessages{
+				{
+					Field:   "code",
+					Message: "認可コードは必須です。",
+				},
+				{
+					Field:   "state",
+					Message: "stateは必須です。",
+				},
+			},
+		}
+		test_utils.SortErrorMessages(responseBody.Result)
+		test_utils.SortErrorMessages(expectedErrorMessage.Result)
+		assert.Equal(t, responseBody, expectedErrorMessage)
+	})
+
+	t.Run("OIDCSignUpCallback メールアドレス未確認", func(t *testing.T) {
+
+		flow.fake.EmailVerified = false
+		defer func() { flow.fake.EmailVerified = true }()
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignUp)
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignUpCallback(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assertErrorMessage(t, w, "メールアドレスが確認されていない外部アカウントです。")
+	})
+
+	t.Run("OIDCSignUpCallback IDトークンに名前がない場合はユーザー情報から取得", func(t *testing.T) {
+
+		flow.fake.Name = ""
+		flow.fake.UserInfoName = "userinfo"
+		defer func() {
+			flow.fake.Name = "test"
+			flow.fake.UserInfoName = ""
+		}()
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignUp)
+
+		var registered models.RequestSignUpData
+		var identity models.IdentityData
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"PostIdentitySignUp",
+			func(_ *models.IdentityDataFetcher, data models.RequestSignUpData, identityData models.IdentityData) (int, error) {
+				registered = data
+				identity = identityData
+				return 0, fmt.Errorf("sql登録失敗")
+			})
+		defer patches.Reset()
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignUpCallback(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "userinfo", registered.UserName)
+		assert.Equal(t, "test@example.com", registered.UserEmail)
+		assert.Equal(t, models.IdentityData{
+			Provider:        "google",
+			ProviderSubject: "109876543210",
+			ProviderEmail:   "test@example.com",
+		}, identity)
+	})
+
+	t.Run("OIDCSignUpCallback DB取得エラー", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignUp)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"PostIdentitySignUp",
+			func(_ *models.IdentityDataFetcher, data models.RequestSignUpData, identity models.IdentityData) (int, error) {
+				return 0, fmt.Errorf("sql登録失敗")
+			})
+		defer patches.Reset()
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignUpCallback(c)
+
+		// ステータスコードの確認
+		assert.Equal(t, http.StatusConflict, w.Code)
+		var responseBody utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Nil(t, err)
+
+		expectedErrorMessage := utils.ErrorMessageResponse{
+			Result: "既に登録されたメールアドレスか、連携済みの外部アカウントです。",
+		}
+		assert.Equal(t, responseBody.Result, expectedErrorMessage.Result)
+	})
+
+	t.Run("OIDCSignUpCallback トークン生成に失敗 1", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignUp)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"PostIdentitySignUp",
+			func(_ *models.IdentityDataFetcher, data models.RequestSignUpData, identity models.IdentityData) (int, error) {
+				return 1, nil
+			})
+		defer patches.Reset()
+
+		// gomock のコントローラを作成
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// UtilsFetcher のモックを作成
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+
+		// モックの挙動を定義
+		mockUtilsFetcher.EXPECT().
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("", fmt.Errorf("トークン生成エラー"))
+
+		oidcManager := flow.oidcManager(mockUtilsFetcher, templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignUpCallback(c)
+
+		// ステータスコードの確認
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		// レスポンスボディの確認
+		var responseBody utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+
+		expectedErrorMessage := utils.ErrorMessageResponse{
+			Result: "新規トークンの生成に失敗しました。",
+		}
+		assert.Equal(t, responseBody, expectedErrorMessage)
+	})
+
+	t.Run("OIDCSignUpCallback トークン生成に失敗 2", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignUp)
+
+		// gomock のコントローラ作成
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"PostIdentitySignUp",
+			func(_ *models.IdentityDataFetcher, data models.RequestSignUpData, identity models.IdentityData) (int, error) {
+				return 1, nil
+			})
+		defer patches.Reset()
+
+		// UtilsFetcher のモックを作成
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+
+		// モックの挙動を定義
+		mockUtilsFetcher.EXPECT().
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("token", nil)
+
+		mockUtilsFetcher.EXPECT().
+			RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("", fmt.Errorf("トークン生成エラー"))
+
+		oidcManager := flow.oidcManager(mockUtilsFetcher, templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignUpCallback(c)
+
+		// レスポンスボディの確認
+		var responseBody utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+
+		expectedErrorMessage := utils.ErrorMessageResponse{
+			Result: "リフレッシュトークンの生成に失敗しました。",
+		}
+		assert.Equal(t, responseBody, expectedErrorMessage)
+	})
+
+	t.Run("OIDCSignUpCallback メールテンプレート生成エラー(登録)", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignUp)
+
+		// gomock のコントローラ作成
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"PostIdentitySignUp",
+			func(_ *models.IdentityDataFetcher, data models.RequestSignUpData, identity models.IdentityData) (int, error) {
+				return 1, nil
+			})
+		defer patches.Reset()
+
+		// UtilsFetcher のモックを作成
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		// EmailTemplateService のモックを作成
+		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
+
+		// モックの挙動を定義
+		mockUtilsFetcher.EXPECT().
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("token", nil)
+
+		mockUtilsFetcher.EXPECT().
+			RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("", nil)
+
+		mockUtilsFetcher.EXPECT().
+			DateTimeStr(gomock.Any(), gomock.Any()).
+			Return("2024年12月2日")
+
+		mockEmailTemplateService.EXPECT().
+			PostSignUpTemplate(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("件名", "本文", fmt.Errorf("メールテンプレートエラー"))
+
+		oidcManager := flow.oidcManager(mockUtilsFetcher, mockEmailTemplateService)
+		oidcManager.OIDCSignUpCallback(c)
+
+		// ステータスコードの確認
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		var responseBody utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Nil(t, err)
+
+		expectedErrorMessage := utils.ErrorMessageResponse{
+			Result: "メールテンプレート生成エラー(登録): メールテンプレートエラー",
+		}
+		assert.Equal(t, responseBody.Result, expectedErrorMessage.Result)
+	})
+
+	t.Run("OIDCSignUpCallback メール送信エラー(登録)", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignUp)
+
+		// gomock のコントローラ作成
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"PostIdentitySignUp",
+			func(_ *models.IdentityDataFetcher, data models.RequestSignUpData, identity models.IdentityData) (int, error) {
+				return 1, nil
+			})
+		defer patches.Reset()
+
+		// UtilsFetcher のモックを作成
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
+
+		// モックの挙動を定義
+		mockUtilsFetcher.EXPECT().
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("token", nil)
+
+		mockUtilsFetcher.EXPECT().
+			RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("", nil)
+
+		mockUtilsFetcher.EXPECT().
+			DateTimeStr(gomock.Any(), gomock.Any()).
+			Return("2024年12月2日")
+
+		mockEmailTemplateService.EXPECT().
+			PostSignUpTemplate(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("件名", "本文", nil)
+
+		mockUtilsFetcher.EXPECT().
+			SendMail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("メール送信エラー"))
+
+		oidcManager := flow.oidcManager(mockUtilsFetcher, mockEmailTemplateService)
+		oidcManager.OIDCSignUpCallback(c)
+
+		// ステータスコードの確認
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		var responseBody utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Nil(t, err)
+
+		expectedErrorMessage := utils.ErrorMessageResponse{
+			Result: "メール送信エラー(登録): メール送信エラー",
+		}
+		assert.Equal(t, responseBody.Result, expectedErrorMessage.Result)
+	})
+
+	t.Run("OIDCSignUpCallback result 成功", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignUp)
+
+		// gomock のコントローラ作成
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"PostIdentitySignUp",
+			func(_ *models.IdentityDataFetcher, data models.RequestSignUpData, identity models.IdentityData) (int, error) {
+				return 1, nil
+			})
+		defer patches.Reset()
+
+		// UtilsFetcher のモックを作成
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+
+		// モックの挙動を定義
+		mockUtilsFetcher.EXPECT().
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("token", nil)
+
+		mockUtilsFetcher.EXPECT().
+			RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("", nil)
+
+		mockUtilsFetcher.EXPECT().
+			DateTimeStr(gomock.Any(), gomock.Any()).
+			Return("2024年12月2日")
+
+		mockUtilsFetcher.EXPECT().
+			SendMail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil)
+
+		oidcManager := flow.oidcManager(mockUtilsFetcher, templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignUpCallback(c)
+
+		// ステータスコードの確認
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var responseBody utils.ResponseData[SignUpResult]
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Nil(t, err)
+
+		expectedOk := utils.ResponseData[SignUpResult]{
+			Result: SignUpResult{
+				UserId:    1,
+				UserEmail: "test@example.com",
+			},
+		}
+		assert.Equal(t, responseBody.Result.UserId, expectedOk.Result.UserId)
+		assert.Equal(t, responseBody.Result.UserEmail, expectedOk.Result.UserEmail)
+	})
+}
+
+func TestOIDCDeleteCallback(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	// Googleとして登録したテスト用の外部認証サーバー
+	flow := newGoogleTestFlow(t)
+
+	t.Run("OIDCDeleteCallback バリデーション必須チェック", func(t *testing.T) {
+
+		w, c := test_utils.CreateTestRequest(
+			"GET", "/api/auth/google/delete/callback?code=&state=",
+			nil,
+			map[string]string{
+				"code":  "",
+				"state": "",
+			},
+		)
+		c.Params = flow.params()
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCDeleteCallback(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var responseBody utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+
+		expectedErrorMessage := utils.ErrorValidationResponse{
+			Result: []utils.ErrorMessages{
+				{
+					Field:   "code",
+					Message: "認可コードは必須です。",
+				},
+				{
+					Field:   "state",
+					Message: "stateは必須です。",
+				},
+			},
+		}
+		test_utils.SortErrorMessages(responseBody.Result)
+		test_utils.SortErrorMessages(expectedErrorMessage.Result)
+		assert.Equal(t, responseBody, expectedErrorMessage)
+	})
+
+	t.Run("OIDCDeleteCallback 用途の異なるstate", func(t *testing.T) {
+
+		// サインインで開始した認証で削除はできない
+		code, state, cookie := flow.oidcAuthorize(t, oauthPurposeSignIn, 0)
+		w, c := flow.oidcCallbackRequest(oauthPurposeDelete, code, state, cookie)
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCDeleteCallback(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assertErrorMessage(t, w, "認証の有効期限が切れたか、不正なリクエストです。最初からやり直してください。")
+	})
+
+	t.Run("OIDCDeleteCallback DB取得エラー", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeDelete)
+
+		resMock := models.ExternalAuthData{}
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetIdentityUser",
+			func(_ *models.IdentityDataFetcher, Provider, ProviderSubject string) (models.ExternalAuthData, error) {
+				return resMock, fmt.Errorf("sql取得失敗")
+			})
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetLegacyExternalUser",
+			func(_ *models.IdentityDataFetcher, Provider, UserEmail string) (models.ExternalAuthData, error) {
+				return resMock, fmt.Errorf("sql取得失敗")
+			})
+		defer patches.Reset()
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCDeleteCallback(c)
+
+		// ステータスコードの確認
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		var responseBody utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Nil(t, err)
+
+		expectedErrorMessage := utils.ErrorMessageResponse{
+			Result: "sql取得失敗",
+		}
+
+		assert.Equal(t, responseBody.Result, expectedErrorMessage.Result)
+	})
+
+	t.Run("OIDCDeleteCallback アクセストークンの無効化エラー", func(t *testing.T) {
+
+		flow.fake.RevokeStatus = http.StatusServiceUnavailable
+		defer func() { flow.fake.RevokeStatus = 0 }()
+
+		w, c := flow.oidcCallback(t, oauthPurposeDelete)
+
+		patches := patchGetIdentityUser(models.ExternalAuthData{UserId: 1, UserEmail: "test@example.com"}, nil)
+		defer patches.Reset()
+
+		deleted := false
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"DeleteSignIn",
+			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData, PurgeAt time.Time) error {
+				deleted = true
+				return nil
+			})
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCDeleteCallback(c)
+
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		assertErrorMessage(t, w, "外部認証のアクセストークンの無効化に失敗しました。")
+		// 無効化に失敗した場合はユーザーを削除しない
+		assert.False(t, deleted)
+	})
+
+	t.Run("OIDCDeleteCallback DB削除エラー", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeDelete)
+
+		resMock := models.ExternalAuthData{
+			UserId:    1,
+			UserEmail: "test@example.com",
+		}
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetIdentityUser",
+			func(_ *models.IdentityDataFetcher, Provider, ProviderSubject string) (models.ExternalAuthData, error) {
+				return resMock, nil
+			})
+		defer patches.Reset()
+
+		patches1 := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"DeleteSignIn",
+			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData, PurgeAt time.Time) error {
+				return fmt.Errorf("DB削除エラー")
+			})
+		defer patches1.Reset()
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCDeleteCallback(c)
+
+		// ステータスコードの確認
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		var responseBody utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Nil(t, err)
+
+		expectedErrorMessage := utils.ErrorMessageResponse{
+			Result: "DB削除エラー",
+		}
+
+		assert.Equal(t, responseBody.Result, expectedErrorMessage.Result)
+	})
+
+	t.Run("OIDCDeleteCallback メールテンプレート生成エラー(削除)", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeDelete)
+
+		// gomock のコントローラ作成
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// UtilsFetcher のモックを作成
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+
+		// 発行済みのトークンを失効させること
+		mockUtilsFetcher.EXPECT().
+			RevokeUserTokens(gomock.Any()).
+			Return(nil)
+
+		// EmailTemplateService のモックを作成
+		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
+
+		resMock := models.ExternalAuthData{
+			UserId:    1,
+			UserEmail: "test@example.com",
+		}
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetIdentityUser",
+			func(_ *models.IdentityDataFetcher, Provider, ProviderSubject string) (models.ExternalAuthData, error) {
+				return resMock, nil
+			})
+		defer patches.Reset()
+
+		patches1 := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"DeleteSignIn",
+			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData, PurgeAt time.Time) error {
+				return nil
+			})
+		defer patches1.Reset()
+
+		// モックの挙動を定義
+		mockUtilsFetcher.EXPECT().
+			DateTimeStr(gomock.Any(), gomock.Any()).
+			Return("2024年12月2日").
+			Times(2)
+
+		mockEmailTemplateService.EXPECT().
+			AccountDeactivatedTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("件名", "本文", fmt.Errorf("メールテンプレートエラー"))
+
+		oidcManager := flow.oidcManager(mockUtilsFetcher, mockEmailTemplateService)
+		oidcManager.OIDCDeleteCallback(c)
+
+		// ステータスコードの確認
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		var responseBody utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Nil(t, err)
+
+		expectedErrorMessage := utils.ErrorMessageResponse{
+			Result: "メールテンプレート生成エラー(削除): メールテンプレートエラー",
+		}
+
+		assert.Equal(t, responseBody.Result, expectedErrorMessage.Result)
+	})
+
+	t.Run("OIDCDeleteCallback メール送信エラー(削除)", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeDelete)
+
+		// gomock のコントローラ作成
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// UtilsFetcher のモックを作成
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+
+		// 発行済みのトークンを失効させること
+		mockUtilsFetcher.EXPECT().
+			RevokeUserTokens(gomock.Any()).
+			Return(nil)
+
+		// EmailTemplateService のモックを作成
+		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
+
+		resMock := models.ExternalAuthData{
+			UserId:    1,
+			UserEmail: "test@example.com",
+		}
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetIdentityUser",
+			func(_ *models.IdentityDataFetcher, Provider, ProviderSubject string) (models.ExternalAuthData, error) {
+				return resMock, nil
+			})
+		defer patches.Reset()
+
+		patches1 := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"DeleteSignIn",
+			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData, PurgeAt time.Time) error {
+				return nil
+			})
+		defer patches1.Reset()
+
+		// モックの挙動を定義
+		mockUtilsFetcher.EXPECT().
+			DateTimeStr(gomock.Any(), gomock.Any()).
+			Return("2024年12月2日").
+			Times(2)
+
+		mockEmailTemplateService.EXPECT().
+			AccountDeactivatedTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("件名", "本文", nil)
+
+		mockUtilsFetcher.EXPECT().
+			SendMail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("メール送信エラー"))
+
+		oidcManager := flow.oidcManager(mockUtilsFetcher, mockEmailTemplateService)
+		oidcManager.OIDCDeleteCallback(c)
+
+		// ステータスコードの確認
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		var responseBody utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Nil(t, err)
+
+		expectedErrorMessage := utils.ErrorMessageResponse{
+			Result: "メール送信エラー(削除): メール送信エラー",
+		}
+
+		assert.Equal(t, responseBody.Result, expectedErrorMessage.Result)
+	})
+
+	t.Run("OIDCDeleteCallback result 成功", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeDelete)
+
+		// gomock のコントローラ作成
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// UtilsFetcher のモックを作成
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+
+		// 発行済みのトークンを失効させること
+		mockUtilsFetcher.EXPECT().
+			RevokeUserTokens(gomock.Any()).
+			Return(nil)
+
+		resMock := models.ExternalAuthData{
+			UserId:    1,
+			UserEmail: "test@example.com",
+		}
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetIdentityUser",
+			func(_ *models.IdentityDataFetcher, Provider, ProviderSubject string) (models.ExternalAuthData, error) {
+				return resMock, nil
+			})
+		defer patches.Reset()
+
+		patches1 := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"DeleteSignIn",
+			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData, PurgeAt time.Time) error {
+				return nil
+			})
+		defer patches1.Reset()
+
+		// モックの挙動を定義
+		mockUtilsFetcher.EXPECT().
+			DateTimeStr(gomock.Any(), gomock.Any()).
+			Return("2024年12月2日").
+			Times(2)
+
+		mockUtilsFetcher.EXPECT().
+			SendMail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil)
+
+		oidcManager := flow.oidcManager(mockUtilsFetcher, templates.NewEmailTemplateManager())
+		oidcManager.OIDCDeleteCallback(c)
+
+		// ステータスコードの確認
+		assert.Equal(t, http.StatusOK, w.Code)
+		// 外部認証のアクセストークンを無効化すること
+		assert.Contains(t, flow.fake.RevokedTokens, "fake-access-token")
+		var responseBody utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Nil(t, err)
+
+		expectedResponse := utils.ResponseData[string]{
+			Result: "google外部認証の削除が成功しました。",
+		}
+
+		assert.Equal(t, responseBody.Result, expectedResponse.Result)
+	})
+}
+
+func TestOIDCLinkCallback(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	// Googleとして登録したテスト用の外部認証サーバー
+	flow := newGoogleTestFlow(t)
+
+	t.Run("OIDCLinkCallback サインイン中のユーザーと不一致", func(t *testing.T) {
+
+		code, state, cookie := flow.oidcAuthorize(t, oauthPurposeLink, 1)
+
+		// 別のユーザーでサインインしたブラウザからのコールバック
+		w, c := flow.oidcCallbackRequest(oauthPurposeLink, code, state, cookie)
+		c.Set(utils.UserId, 2)
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCLinkCallback(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("OIDCLinkCallback 連携済み", func(t *testing.T) {
+
+		code, state, cookie := flow.oidcAuthorize(t, oauthPurposeLink, 1)
+		w, c := flow.oidcCallbackRequest(oauthPurposeLink, code, state, cookie)
+		c.Set(utils.UserId, 1)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"LinkIdentity",
+			func(_ *models.IdentityDataFetcher, UserId int, data models.IdentityData) error {
+				return fmt.Errorf("外部アカウントが他のユーザーに連携済みか、同じ外部認証が連携済みです。")
+			})
+		defer patches.Reset()
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCLinkCallback(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assertErrorMessage(t, w, "外部アカウントが他のユーザーに連携済みか、同じ外部認証が連携済みです。")
+	})
+
+	t.Run("OIDCLinkCallback result 成功", func(t *testing.T) {
+
+		code, state, cookie := flow.oidcAuthorize(t, oauthPurposeLink, 1)
+		w, c := flow.oidcCallbackRequest(oauthPurposeLink, code, state, cookie)
+		c.Set(utils.UserId, 1)
+
+		var linkedUserId int
+		var linked models.IdentityData
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"LinkIdentity",
+			func(_ *models.IdentityDataFetcher, UserId int, data models.IdentityData) error {
+				linkedUserId = UserId
+				linked = data
+				return nil
+			})
+		defer patches.Reset()
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCLinkCallback(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assertErrorMessage(t, w, "google外部認証を連携しました。")
+
+		// IDトークンで確認した外部アカウントを連携する
+		assert.Equal(t, 1, linkedUserId)
+		assert.Equal(t, models.IdentityData{
+			Provider:        "google",
+			ProviderSubject: "109876543210",
+			ProviderEmail:   "test@example.com",
+		}, linked)
+	})
+}
+
+func TestOIDCLineCallback(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	// LINEとして登録したテスト用の外部認証サーバー
+	flow := newLineTestFlow(t)
+
+	// セッションの記録はDBに接続しない
+	sessionPatches := patchInsertSession(nil)
+	defer sessionPatches.Reset()
+	rolePatches := patchGetUserRole(models.UserRoleData{Role: enum.ROLE_USER}, nil)
+	defer rolePatches.Reset()
+	totpPatches := patchGetTotp(models.TotpData{}, nil)
+	defer totpPatches.Reset()
+
+	t.Run("OIDCSignInAuth LINEの認可画面へリダイレクト", func(t *testing.T) {
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/auth/line/signin", nil)
+		c.Params = flow.params()
+
+		oidcManager := flow.oidcManager(nil, nil)
+		oidcManager.OIDCSignInAuth(c)
+
+		assert.Equal(t, http.StatusFound, w.Code)
+
+		location, err := url.Parse(w.Header().Get("Location"))
+		assert.NoError(t, err)
+
+		query := location.Query()
+		assert.Equal(t, config.OAuthRedirectURI("line", oauthPurposeSignIn), query.Get("redirect_uri"))
+		assert.Equal(t, "openid profile email", query.Get("scope"))
+		assert.Empty(t, query.Get("prompt"))
+
+		saved := flow.savedState(query.Get("state"))
+		assert.Equal(t, "line", saved.Provider)
+		assert.Equal(t, oauth2.S256ChallengeFromVerifier(saved.CodeVerifier), query.Get("code_challenge"))
+	})
+
+	t.Run("OIDCSignInCallback LINEのIDトークン(HS256)でサインイン", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignIn)
+
+		var provider, subject string
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"GetIdentityUser",
+			func(_ *models.IdentityDataFetcher, Provider, ProviderSubject string) (models.ExternalAuthData, error) {
+				provider = Provider
+				subject = ProviderSubject
+				return models.ExternalAuthData{UserId: 3, UserEmail: "test@example.com"}, nil
+			})
+		defer patches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("new_token", nil)
+		mockUtilsFetcher.EXPECT().
+			RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("refresh_token", nil)
+		mockUtilsFetcher.EXPECT().
+			DateTimeStr(gomock.Any(), gomock.Any()).
+			Return("2024年12月2日")
+		mockUtilsFetcher.EXPECT().
+			SendMail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil)
+
+		oidcManager := flow.oidcManager(mockUtilsFetcher, templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignInCallback(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "line", provider)
+		assert.Equal(t, "U1234567890", subject)
+
+		// LINEのトークンリクエストはクライアントシークレットをリクエストボディで送る
+		tokenRequest := flow.fake.TokenRequests[len(flow.fake.TokenRequests)-1]
+		assert.Equal(t, testOAuthClientId, tokenRequest.Get("client_id"))
+		assert.Equal(t, testLineChannelSecret, tokenRequest.Get("client_secret"))
+	})
+
+	t.Run("OIDCSignInCallback IDトークンの署名不正(チャネルシークレット不一致)", func(t *testing.T) {
+
+		flow.fake.SigningSecret = []byte("other-channel-secret")
+		defer func() { flow.fake.SigningSecret = []byte(testLineChannelSecret) }()
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignIn)
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignInCallback(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assertErrorMessage(t, w, "外部アカウントの確認に失敗しました。")
+	})
+
+	t.Run("OIDCSignInCallback メールアドレスの取得が未許可", func(t *testing.T) {
+
+		flow.fake.Email = ""
+		defer func() { flow.fake.Email = "test@example.com" }()
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignIn)
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignInCallback(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assertErrorMessage(t, w, "メールアドレスを取得できない外部アカウントです。")
+	})
+
+	t.Run("OIDCSignUpCallback email_verifiedを要求しない外部認証", func(t *testing.T) {
+
+		// LINEのIDトークンは email_verified を含まない
+		flow.fake.EmailVerified = false
+		flow.fake.Name = ""
+		flow.fake.UserInfoName = "userinfo"
+		defer func() {
+			flow.fake.EmailVerified = true
+			flow.fake.Name = "test"
+			flow.fake.UserInfoName = ""
+		}()
+
+		w, c := flow.oidcCallback(t, oauthPurposeSignUp)
+
+		var registered models.RequestSignUpData
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"PostIdentitySignUp",
+			func(_ *models.IdentityDataFetcher, data models.RequestSignUpData, identityData models.IdentityData) (int, error) {
+				registered = data
+				return 0, fmt.Errorf("sql登録失敗")
+			})
+		defer patches.Reset()
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCSignUpCallback(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "userinfo", registered.UserName)
+		// 外部認証のユーザーはパスワードに外部認証の名称を登録する
+		assert.Equal(t, "line", registered.UserPassword)
+	})
+
+	t.Run("OIDCDeleteCallback LINEのアクセストークンを無効化", func(t *testing.T) {
+
+		w, c := flow.oidcCallback(t, oauthPurposeDelete)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().
+			RevokeUserTokens(gomock.Any()).
+			Return(nil)
+		mockUtilsFetcher.EXPECT().
+			DateTimeStr(gomock.Any(), gomock.Any()).
+			Return("2024年12月2日").
+			Times(2)
+		mockUtilsFetcher.EXPECT().
+			SendMail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil)
+
+		patches := patchGetIdentityUser(models.ExternalAuthData{UserId: 1, UserEmail: "test@example.com"}, nil)
+		defer patches.Reset()
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"DeleteSignIn",
+			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData, PurgeAt time.Time) error {
+				return nil
+			})
+
+		revoked := len(flow.fake.RevokedTokens)
+		oidcManager := flow.oidcManager(mockUtilsFetcher, templates.NewEmailTemplateManager())
+		oidcManager.OIDCDeleteCallback(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assertErrorMessage(t, w, "line外部認証の削除が成功しました。")
+		assert.Len(t, flow.fake.RevokedTokens, revoked+1)
+	})
+
+	t.Run("OIDCLinkCallback LINEアカウントを連携", func(t *testing.T) {
+
+		code, state, cookie := flow.oidcAuthorize(t, oauthPurposeLink, 1)
+		w, c := flow.oidcCallbackRequest(oauthPurposeLink, code, state, cookie)
+		c.Set(utils.UserId, 1)
+
+		var linked models.IdentityData
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.IdentityDataFetcher{}),
+			"LinkIdentity",
+			func(_ *models.IdentityDataFetcher, UserId int, data models.IdentityData) error {
+				linked = data
+				return nil
+			})
+		defer patches.Reset()
+
+		oidcManager := flow.oidcManager(utils.NewUtilsFetcher(utils.JwtKeys), templates.NewEmailTemplateManager())
+		oidcManager.OIDCLinkCallback(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assertErrorMessage(t, w, "line外部認証を連携しました。")
+		assert.Equal(t, models.IdentityData{
+			Provider:        "line",
+			ProviderSubject: "U1234567890",
+			ProviderEmail:   "test@example.com",
+		}, linked)
+	})
+}
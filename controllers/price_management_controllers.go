@@ -4,8 +4,12 @@ package controllers
 import (
 	"net/http"
 	"server/common"
+	"server/config"
+	"server/enum"
+	"server/models"
 	"server/utils"
 	"server/validation"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,8 +21,20 @@ type (
 	}
 
 	PriceInfo struct {
-		LeftAmount  int `json:"left_amount"`
-		TotalAmount int `json:"total_amount"`
+		LeftAmount   int                `json:"left_amount"`
+		TotalAmount  int                `json:"total_amount"`
+		IncomeSource *PriceIncomeSource `json:"income_source,omitempty"`
+	}
+
+	// PriceIncomeSource は年収推移データから算出した収入とその根拠
+	PriceIncomeSource struct {
+		MoneyReceived int    `json:"money_received"`
+		Bouns         int    `json:"bouns"`
+		Months        int    `json:"months"`
+		SalaryCount   int    `json:"salary_count"`
+		BonusCount    int    `json:"bonus_count"`
+		StartDate     string `json:"start_date"`
+		EndDate       string `json:"end_date"`
 	}
 
 	apiPriceManagementFetcher struct {
@@ -26,6 +42,13 @@ type (
 	}
 )
 
+const (
+	// 平均手取りを算出する月数の既定値
+	defaultIncomeMonths = 3
+	// ボーナスを合計する月数
+	bonusMonths = 12
+)
+
 func NewPriceManagementFetcher(CommonFetcher common.CommonFetcher) PriceManagementFetcher {
 	return &apiPriceManagementFetcher{
 		CommonFetcher: CommonFetcher,
@@ -54,12 +77,62 @@ func (af *apiPriceManagementFetcher) PriceCalc(moneyReceived, bouns, fixedCost,
 	return priceinfo
 }
 
+// calcPriceIncomeSource は年収推移データから月の収入とボーナスを算出する。
+// 月の収入は直近 months ヶ月の給料の手取り額の平均、ボーナスは直近12ヶ月の賞与の手取り額の合計とする。
+//
+// 引数:
+//   - records: 年収推移データ
+//   - now: 基準日
+//   - months: 平均する月数
+//
+// 戻り値:
+//   - PriceIncomeSource: 算出した収入とその根拠
+
+func calcPriceIncomeSource(records []models.IncomeData, now time.Time, months int) PriceIncomeSource {
+	salaryFrom := now.AddDate(0, -months, 0)
+	bonusFrom := now.AddDate(0, -bonusMonths, 0)
+
+	source := PriceIncomeSource{
+		Months:    months,
+		StartDate: salaryFrom.Format("2006-01-02"),
+		EndDate:   now.Format("2006-01-02"),
+	}
+
+	var salaryTotal int
+	for _, record := range records {
+		if record.PaymentDate.After(now) {
+			continue
+		}
+		switch record.Classification {
+		case enum.SALARY:
+			if record.PaymentDate.After(salaryFrom) {
+				salaryTotal += record.TakeHomeAmount
+				source.SalaryCount++
+			}
+		case enum.BONUS:
+			if record.PaymentDate.After(bonusFrom) {
+				source.Bouns += record.TakeHomeAmount
+				source.BonusCount++
+			}
+		}
+	}
+
+	if source.SalaryCount > 0 {
+		source.MoneyReceived = salaryTotal / source.SalaryCount
+	}
+
+	return source
+}
+
 // GetPriceInfoApi は価格情報を取得するエンドポイントハンドラーです。
 //
 // クライアントから送信されたクエリーパラメータ money_received、bouns、fixed_cost、loan、private を
 // 解析し、それらの値を使用して価格計算を行います。正常な場合、計算結果を JSON レスポンスとして
 // 返し、HTTPステータスコード 200 (OK) を返します。エラーが発生した場合、エラーメッセージを JSON
 // レスポンスとして返し、HTTPステータスコード 400 (Bad Request) を返します。
+// loan_auto=true と user_id を指定した場合、loan は登録済みローンの今月の返済額で上書きします。
+// income_auto=true と user_id を指定した場合、money_received は直近 months ヶ月(既定3ヶ月)の給料の平均手取り、
+// bouns は直近12ヶ月の賞与の手取り合計で上書きし、算出に使った値を income_source として返します。
 //
 // 引数:
 //   - c: Ginコンテキスト
@@ -94,6 +167,10 @@ func (pm *apiPriceManagementFetcher) GetPriceInfoApi(c *gin.Context) {
 		Loan:          c.Query("loan"),
 		Private:       c.Query("private"),
 		Insurance:     c.Query("insurance"),
+		LoanAuto:      c.Query("loan_auto"),
+		IncomeAuto:    c.Query("income_auto"),
+		Months:        c.Query("months"),
+		UserId:        c.Query("user_id"),
 	}
 
 	if valid, errMsgList := validator.Validate(); !valid {
@@ -106,13 +183,56 @@ func (pm *apiPriceManagementFetcher) GetPriceInfoApi(c *gin.Context) {
 
 	data, err := pm.CommonFetcher.IntgetPrameter(c, "money_received", "bouns", "fixed_cost", "loan", "private", "insurance")
 
+	if err == nil && validator.LoanAuto == "true" {
+		// 登録済みローンの今月の返済額をローンに反映する
+		userId, _ := pm.CommonFetcher.StrToInt(validator.UserId)
+		dbFetcher, _, _ := models.NewLoanDataFetcher(config.GetDataBaseSource())
+		loans, loanErr := dbFetcher.GetLoans(userId)
+		if loanErr != nil {
+			response := utils.ErrorMessageResponse{
+				Result: loanErr.Error(),
+			}
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+		data["loan"] = currentLoanPayment(loans, time.Now())
+	}
+
+	var incomeSource *PriceIncomeSource
+	if err == nil && validator.IncomeAuto == "true" {
+		// 年収推移データの給料・賞与を収入とボーナスに反映する
+		userId, _ := pm.CommonFetcher.StrToInt(validator.UserId)
+		months := defaultIncomeMonths
+		if validator.Months != "" {
+			months, _ = pm.CommonFetcher.StrToInt(validator.Months)
+		}
+
+		now := time.Now()
+		startDate := now.AddDate(0, -max(months, bonusMonths), 0)
+		dbFetcher, _, _ := models.NewAnnualIncomeDataFetcher(config.GetDataBaseSource())
+		records, incomeErr := dbFetcher.GetIncomeDataInRange(pm.CommonFetcher.TimeToStr(startDate), pm.CommonFetcher.TimeToStr(now), userId)
+		if incomeErr != nil {
+			response := utils.ErrorMessageResponse{
+				Result: incomeErr.Error(),
+			}
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+
+		source := calcPriceIncomeSource(records, now, months)
+		data["money_received"] = source.MoneyReceived
+		data["bouns"] = source.Bouns
+		incomeSource = &source
+	}
+
 	if err == nil {
 		res := pm.PriceCalc(data["money_received"], data["bouns"], data["fixed_cost"], data["loan"], data["private"], data["insurance"])
 
 		response := utils.ResponseData[PriceInfo]{
 			Result: PriceInfo{
-				LeftAmount:  res.LeftAmount,
-				TotalAmount: res.TotalAmount,
+				LeftAmount:   res.LeftAmount,
+				TotalAmount:  res.TotalAmount,
+				IncomeSource: incomeSource,
 			},
 		}
 		c.JSON(http.StatusOK, response)
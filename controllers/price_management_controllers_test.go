@@ -5,13 +5,17 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 
 	"server/common"
+	"server/models"
 	"server/utils"
 	"testing"
+	"time"
 
 	common_mock "server/mock/common"
 
+	. "github.com/agiledragon/gomonkey/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -294,3 +298,119 @@ func TestGetPriceInfo(t *testing.T) {
 		assert.Equal(t, responseBody, expectedErrorMessage)
 	})
 }
+
+func TestCalcPriceIncomeSource(t *testing.T) {
+	now := time.Date(2024, time.October, 20, 0, 0, 0, 0, time.UTC)
+	records := []models.IncomeData{
+		{PaymentDate: time.Date(2024, time.October, 25, 0, 0, 0, 0, time.UTC), TakeHomeAmount: 999999, Classification: "給料"},
+		{PaymentDate: time.Date(2024, time.September, 25, 0, 0, 0, 0, time.UTC), TakeHomeAmount: 260000, Classification: "給料"},
+		{PaymentDate: time.Date(2024, time.August, 25, 0, 0, 0, 0, time.UTC), TakeHomeAmount: 250000, Classification: "給料"},
+		{PaymentDate: time.Date(2024, time.July, 25, 0, 0, 0, 0, time.UTC), TakeHomeAmount: 240000, Classification: "給料"},
+		{PaymentDate: time.Date(2024, time.July, 10, 0, 0, 0, 0, time.UTC), TakeHomeAmount: 500000, Classification: "賞与"},
+		{PaymentDate: time.Date(2024, time.June, 25, 0, 0, 0, 0, time.UTC), TakeHomeAmount: 100000, Classification: "給料"},
+		{PaymentDate: time.Date(2023, time.December, 10, 0, 0, 0, 0, time.UTC), TakeHomeAmount: 600000, Classification: "賞与"},
+		{PaymentDate: time.Date(2023, time.July, 10, 0, 0, 0, 0, time.UTC), TakeHomeAmount: 450000, Classification: "賞与"},
+	}
+
+	t.Run("success 直近3ヶ月の給料平均と12ヶ月の賞与合計", func(t *testing.T) {
+		result := calcPriceIncomeSource(records, now, 3)
+
+		// 基準日より後の支給分は含めない
+		assert.Equal(t, 250000, result.MoneyReceived)
+		assert.Equal(t, 3, result.SalaryCount)
+		assert.Equal(t, 1100000, result.Bouns)
+		assert.Equal(t, 2, result.BonusCount)
+		assert.Equal(t, "2024-07-20", result.StartDate)
+		assert.Equal(t, "2024-10-20", result.EndDate)
+	})
+
+	t.Run("success 給料データなし", func(t *testing.T) {
+		result := calcPriceIncomeSource(nil, now, 3)
+
+		assert.Equal(t, 0, result.MoneyReceived)
+		assert.Equal(t, 0, result.SalaryCount)
+	})
+}
+
+func TestGetPriceInfoIncomeAuto(t *testing.T) {
+	t.Run("success GetPriceInfoApi() income_auto", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?money_received=1&bouns=1&fixed_cost=50000&loan=50000&private=50000&insurance=30000&income_auto=true&user_id=1&months=2", nil)
+
+		now := time.Now()
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.AnnualIncomeDataFetcher{}),
+			"GetIncomeDataInRange",
+			func(_ *models.AnnualIncomeDataFetcher, startDate string, endDate string, userId int) ([]models.IncomeData, error) {
+				return []models.IncomeData{
+					{PaymentDate: now.AddDate(0, 0, -10), TakeHomeAmount: 310000, Classification: "給料"},
+					{PaymentDate: now.AddDate(0, -1, -10), TakeHomeAmount: 290000, Classification: "給料"},
+					{PaymentDate: now.AddDate(0, -3, 0), TakeHomeAmount: 100000, Classification: "給料"},
+					{PaymentDate: now.AddDate(0, -5, 0), TakeHomeAmount: 400000, Classification: "賞与"},
+				}, nil
+			})
+		defer patches.Reset()
+
+		pm := apiPriceManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		pm.GetPriceInfoApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response utils.ResponseData[PriceInfo]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+
+		// 300,000 - 50,000 - 50,000 - 50,000
+		assert.Equal(t, 150000, response.Result.LeftAmount)
+		// 150,000 * 12 + 400,000 - 30,000
+		assert.Equal(t, 2170000, response.Result.TotalAmount)
+		assert.NotNil(t, response.Result.IncomeSource)
+		assert.Equal(t, 300000, response.Result.IncomeSource.MoneyReceived)
+		assert.Equal(t, 400000, response.Result.IncomeSource.Bouns)
+		assert.Equal(t, 2, response.Result.IncomeSource.Months)
+		assert.Equal(t, 2, response.Result.IncomeSource.SalaryCount)
+	})
+
+	t.Run("error GetPriceInfoApi() income_auto DBエラー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?income_auto=true&user_id=1", nil)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.AnnualIncomeDataFetcher{}),
+			"GetIncomeDataInRange",
+			func(_ *models.AnnualIncomeDataFetcher, startDate string, endDate string, userId int) ([]models.IncomeData, error) {
+				return nil, fmt.Errorf("database error")
+			})
+		defer patches.Reset()
+
+		pm := apiPriceManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		pm.GetPriceInfoApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("バリデーションエラー income_auto user_id・months", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?income_auto=true&months=25", nil)
+
+		pm := apiPriceManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		pm.GetPriceInfoApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var responseBody utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+		assert.Equal(t, []utils.ErrorMessages{
+			{Field: "user_id", Message: "収入自動反映時はユーザーIDが必須又は整数値のみです。"},
+			{Field: "months", Message: "平均する月数は1以上24以下の整数値のみです。"},
+		}, responseBody.Result)
+	})
+}
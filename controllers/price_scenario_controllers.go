@@ -0,0 +1,248 @@
+// controllers/price_scenario_controllers.go
+package controllers
+
+import (
+	"net/http"
+	"server/common"
+	"server/config"
+	"server/models"
+	"server/utils"
+	"server/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	PriceScenarioManagementFetcher interface {
+		GetPriceScenarioApi(c *gin.Context)
+		InsertPriceScenarioApi(c *gin.Context)
+		ActivatePriceScenarioApi(c *gin.Context)
+		DeletePriceScenarioApi(c *gin.Context)
+	}
+
+	requestInsertPriceScenarioData struct {
+		Data []models.InsertPriceScenarioData `json:"data"`
+	}
+
+	requestDeletePriceScenarioData struct {
+		Data []models.DeletePriceScenarioData `json:"data"`
+	}
+
+	requestActivatePriceScenarioData struct {
+		ScenarioId string      `json:"scenario_id"`
+		UserId     interface{} `json:"user_id"`
+	}
+
+	apiPriceScenarioManagementFetcher struct {
+		CommonFetcher common.CommonFetcher
+	}
+)
+
+func NewPriceScenarioManagementFetcher(CommonFetcher common.CommonFetcher) PriceScenarioManagementFetcher {
+	return &apiPriceScenarioManagementFetcher{
+		CommonFetcher: CommonFetcher,
+	}
+}
+
+// GetPriceScenarioApi は登録済みの収支シナリオ一覧を返すAPI
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (ps *apiPriceScenarioManagementFetcher) GetPriceScenarioApi(c *gin.Context) {
+	// パラメータからユーザー情報取得
+	userIdPrams := c.Query("user_id")
+
+	validator := validation.RequestDateRangeData{
+		UserId: userIdPrams,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, _ := ps.CommonFetcher.StrToInt(userIdPrams)
+
+	dbFetcher, _, _ := models.NewPriceScenarioDataFetcher(config.GetDataBaseSource())
+	scenarios, err := dbFetcher.GetPriceScenarios(userId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// JSONレスポンスを返す
+	response := utils.ResponseData[[]models.PriceScenarioData]{
+		Result: scenarios,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// InsertPriceScenarioApi は収支シナリオの新規登録API
+// active_flag=true で登録した場合は、そのシナリオが有効なシナリオになる
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (ps *apiPriceScenarioManagementFetcher) InsertPriceScenarioApi(c *gin.Context) {
+	var requestData requestInsertPriceScenarioData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if len(requestData.Data) == 0 {
+		response := utils.ErrorMessageResponse{
+			Result: "登録するデータが存在しません。",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	for idx, data := range requestData.Data {
+		validator := validation.RequestInsertPriceScenarioData{
+			ScenarioName:  data.ScenarioName,
+			MoneyReceived: common.AnyToStr(data.MoneyReceived),
+			Bouns:         common.AnyToStr(data.Bouns),
+			FixedCost:     common.AnyToStr(data.FixedCost),
+			Loan:          common.AnyToStr(data.Loan),
+			Private:       common.AnyToStr(data.Private),
+			Insurance:     common.AnyToStr(data.Insurance),
+			UserId:        common.AnyToStr(data.UserId),
+		}
+		if valid, errMsgList := validator.Validate(); !valid {
+			response := utils.ErrorValidationResponse{
+				RecodeRows: idx + 1,
+				Result:     errMsgList,
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	dbFetcher, _, _ := models.NewPriceScenarioDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.InsertPriceScenario(requestData.Data); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "収支シナリオ登録時にエラーが発生。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "収支シナリオを登録致しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ActivatePriceScenarioApi は指定した収支シナリオを有効にするAPI
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (ps *apiPriceScenarioManagementFetcher) ActivatePriceScenarioApi(c *gin.Context) {
+	var requestData requestActivatePriceScenarioData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userIdPrams := common.AnyToStr(requestData.UserId)
+	validator := validation.RequestPriceScenarioData{
+		ScenarioId: requestData.ScenarioId,
+		UserId:     userIdPrams,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, _ := ps.CommonFetcher.StrToInt(userIdPrams)
+
+	dbFetcher, _, _ := models.NewPriceScenarioDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.ActivatePriceScenario(requestData.ScenarioId, userId); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "収支シナリオを有効にしました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DeletePriceScenarioApi は収支シナリオの削除API
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (ps *apiPriceScenarioManagementFetcher) DeletePriceScenarioApi(c *gin.Context) {
+	var requestData requestDeletePriceScenarioData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if len(requestData.Data) == 0 {
+		response := utils.ErrorMessageResponse{
+			Result: "削除するデータが存在しません。",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	for idx, data := range requestData.Data {
+		validator := validation.RequestPriceScenarioData{
+			ScenarioId: data.ScenarioId,
+			UserId:     common.AnyToStr(data.UserId),
+		}
+		if valid, errMsgList := validator.Validate(); !valid {
+			response := utils.ErrorValidationResponse{
+				RecodeRows: idx + 1,
+				Result:     errMsgList,
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	dbFetcher, _, _ := models.NewPriceScenarioDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.DeletePriceScenario(requestData.Data); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "収支シナリオ削除中にエラーが発生しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "収支シナリオの削除が問題なく成功しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"server/common"
+	"server/models"
+	"server/utils"
+	"testing"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertPriceScenarioApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success InsertPriceScenarioApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		body, _ := json.Marshal(testData{
+			Data: []models.InsertPriceScenarioData{
+				{
+					ScenarioName:  "現状",
+					MoneyReceived: 300000,
+					Bouns:         600000,
+					FixedCost:     100000,
+					Loan:          80000,
+					Private:       50000,
+					Insurance:     10000,
+					ActiveFlag:    true,
+					UserId:        1,
+				},
+			},
+		})
+		c.Request = httptest.NewRequest("POST", "/", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.PriceScenarioDataFetcher{}),
+			"InsertPriceScenario",
+			func(_ *models.PriceScenarioDataFetcher, data []models.InsertPriceScenarioData) error {
+				return nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiPriceScenarioManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.InsertPriceScenarioApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response utils.ResponseData[string]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "収支シナリオを登録致しました。", response.Result)
+	})
+
+	t.Run("バリデーションエラー InsertPriceScenarioApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		body, _ := json.Marshal(testData{
+			Data: []models.InsertPriceScenarioData{
+				{
+					ScenarioName:  "",
+					MoneyReceived: 300000,
+					Bouns:         600000,
+					FixedCost:     100000,
+					Loan:          80000,
+					Private:       50000,
+					Insurance:     10000,
+					UserId:        1,
+				},
+			},
+		})
+		c.Request = httptest.NewRequest("POST", "/", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		fetcher := apiPriceScenarioManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.InsertPriceScenarioApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, []utils.ErrorMessages{{Field: "scenario_name", Message: "シナリオ名は必須です。"}}, response.Result)
+	})
+}
+
+func TestActivatePriceScenarioApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success ActivatePriceScenarioApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("PUT", "/", bytes.NewBufferString(`{"scenario_id":"8df939de-5a97-4f20-b41b-9ac355c16e36","user_id":1}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.PriceScenarioDataFetcher{}),
+			"ActivatePriceScenario",
+			func(_ *models.PriceScenarioDataFetcher, scenarioId string, userId int) error {
+				return nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiPriceScenarioManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.ActivatePriceScenarioApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("error ActivatePriceScenarioApi 対象なし", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("PUT", "/", bytes.NewBufferString(`{"scenario_id":"8df939de-5a97-4f20-b41b-9ac355c16e36","user_id":1}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.PriceScenarioDataFetcher{}),
+			"ActivatePriceScenario",
+			func(_ *models.PriceScenarioDataFetcher, scenarioId string, userId int) error {
+				return errors.New("対象の収支シナリオが存在しません。")
+			})
+		defer patches.Reset()
+
+		fetcher := apiPriceScenarioManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.ActivatePriceScenarioApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		var response utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "対象の収支シナリオが存在しません。", response.Result)
+	})
+}
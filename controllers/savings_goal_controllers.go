@@ -0,0 +1,517 @@
+// controllers/savings_goal_controllers.go
+package controllers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"server/common"
+	"server/config"
+	"server/models"
+	"server/utils"
+	"server/validation"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	SavingsGoalManagementFetcher interface {
+		GetSavingsGoalApi(c *gin.Context)
+		InsertSavingsGoalApi(c *gin.Context)
+		UpdateSavingsGoalApi(c *gin.Context)
+		DeleteSavingsGoalApi(c *gin.Context)
+		RecordSavingsGoalSnapshotApi(c *gin.Context)
+		GetSavingsGoalSnapshotApi(c *gin.Context)
+	}
+
+	requestInsertSavingsGoalData struct {
+		Data []models.InsertSavingsGoalData `json:"data"`
+	}
+
+	requestUpdateSavingsGoalData struct {
+		Data []models.UpdateSavingsGoalData `json:"data"`
+	}
+
+	requestDeleteSavingsGoalData struct {
+		Data []models.DeleteSavingsGoalData `json:"data"`
+	}
+
+	requestSavingsGoalSnapshotData struct {
+		UserId interface{} `json:"user_id"`
+	}
+
+	SavingsGoalStatus struct {
+		models.SavingsGoalData
+		RemainingAmount   int    `json:"remaining_amount"`
+		RemainingMonths   int    `json:"remaining_months"`
+		RequiredMonthly   int    `json:"required_monthly"`
+		AllocatableAmount int    `json:"allocatable_amount"`
+		Status            string `json:"status"`
+	}
+
+	SavingsGoalPlan struct {
+		ScenarioId    uuid.UUID           `json:"scenario_id"`
+		LeftAmount    int                 `json:"left_amount"`
+		TotalRequired int                 `json:"total_required"`
+		Goals         []SavingsGoalStatus `json:"goals"`
+	}
+
+	apiSavingsGoalManagementFetcher struct {
+		CommonFetcher          common.CommonFetcher
+		PriceManagementFetcher PriceManagementFetcher
+	}
+)
+
+const (
+	SavingsGoalOnTrack  = "on_track"
+	SavingsGoalAtRisk   = "at_risk"
+	SavingsGoalOffTrack = "off_track"
+	// 必要積立額に対してこの割合以上を確保できていれば at_risk とする
+	savingsGoalAtRiskRate = 0.8
+)
+
+func NewSavingsGoalManagementFetcher(
+	CommonFetcher common.CommonFetcher,
+	PriceManagementFetcher PriceManagementFetcher,
+) SavingsGoalManagementFetcher {
+	return &apiSavingsGoalManagementFetcher{
+		CommonFetcher:          CommonFetcher,
+		PriceManagementFetcher: PriceManagementFetcher,
+	}
+}
+
+// savingsGoalMonths は基準日から目標日までに積み立てられる月数を返す
+// 目標日が過ぎている場合は0を返す
+func savingsGoalMonths(now, targetDate time.Time) int {
+	if !targetDate.After(now) {
+		return 0
+	}
+	months := (targetDate.Year()-now.Year())*12 + int(targetDate.Month()-now.Month())
+	return max(months, 1)
+}
+
+// calcSavingsGoalPlan は有効な収支シナリオの月の残額と貯金目標を比較し、目標毎の状況を返す。
+// 月の残額は目標日が近い順に必要積立額を割り当て、割り当て可能額と必要積立額で状況を判定する。
+//
+// 引数:
+//   - leftAmount: 有効な収支シナリオの月の残額
+//   - goals: 貯金目標(目標日の昇順)
+//   - now: 基準日
+//
+// 戻り値:
+//   - []SavingsGoalStatus: 目標毎の状況
+//   - int: 必要積立額の合計
+
+func calcSavingsGoalPlan(leftAmount int, goals []models.SavingsGoalData, now time.Time) ([]SavingsGoalStatus, int) {
+	statuses := []SavingsGoalStatus{}
+	allocatable := leftAmount
+	totalRequired := 0
+
+	for _, goal := range goals {
+		status := SavingsGoalStatus{
+			SavingsGoalData: goal,
+			RemainingAmount: max(goal.TargetAmount-goal.CurrentBalance, 0),
+			RemainingMonths: savingsGoalMonths(now, goal.TargetDate),
+		}
+		status.AllocatableAmount = max(allocatable, 0)
+
+		switch {
+		case status.RemainingAmount == 0:
+			// 目標達成済み
+			status.Status = SavingsGoalOnTrack
+		case status.RemainingMonths == 0:
+			// 目標日を過ぎて未達成
+			status.RequiredMonthly = status.RemainingAmount
+			status.Status = SavingsGoalOffTrack
+		default:
+			status.RequiredMonthly = int(math.Ceil(float64(status.RemainingAmount) / float64(status.RemainingMonths)))
+			if status.AllocatableAmount >= status.RequiredMonthly {
+				status.Status = SavingsGoalOnTrack
+			} else if float64(status.AllocatableAmount) >= float64(status.RequiredMonthly)*savingsGoalAtRiskRate {
+				status.Status = SavingsGoalAtRisk
+			} else {
+				status.Status = SavingsGoalOffTrack
+			}
+		}
+
+		allocatable -= status.RequiredMonthly
+		totalRequired += status.RequiredMonthly
+		statuses = append(statuses, status)
+	}
+
+	return statuses, totalRequired
+}
+
+// savingsGoalPlan はユーザーの有効な収支シナリオと貯金目標から計画を作成する
+func (sg *apiSavingsGoalManagementFetcher) savingsGoalPlan(userId int, now time.Time) (SavingsGoalPlan, error) {
+	var plan SavingsGoalPlan
+
+	scenarioFetcher, _, _ := models.NewPriceScenarioDataFetcher(config.GetDataBaseSource())
+	scenario, err := scenarioFetcher.GetActivePriceScenario(userId)
+	if err != nil {
+		return plan, err
+	}
+
+	goalFetcher, _, _ := models.NewSavingsGoalDataFetcher(config.GetDataBaseSource())
+	goals, err := goalFetcher.GetSavingsGoals(userId)
+	if err != nil {
+		return plan, err
+	}
+
+	priceInfo := sg.PriceManagementFetcher.PriceCalc(
+		scenario.MoneyReceived,
+		scenario.Bouns,
+		scenario.FixedCost,
+		scenario.Loan,
+		scenario.Private,
+		scenario.Insurance,
+	)
+
+	plan.ScenarioId = scenario.ScenarioId
+	plan.LeftAmount = priceInfo.LeftAmount
+	plan.Goals, plan.TotalRequired = calcSavingsGoalPlan(priceInfo.LeftAmount, goals, now)
+
+	return plan, nil
+}
+
+// GetSavingsGoalApi は貯金目標と、有効な収支シナリオに対する目標毎の状況を返すAPI
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (sg *apiSavingsGoalManagementFetcher) GetSavingsGoalApi(c *gin.Context) {
+	// パラメータからユーザー情報取得
+	userIdPrams := c.Query("user_id")
+
+	validator := validation.RequestDateRangeData{
+		UserId: userIdPrams,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, _ := sg.CommonFetcher.StrToInt(userIdPrams)
+
+	plan, err := sg.savingsGoalPlan(userId, time.Now())
+	if errors.Is(err, models.ErrActivePriceScenarioNotFound) {
+		// 目標の状況は有効な収支シナリオの残額から判定するため、シナリオの登録を促す
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	} else if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// JSONレスポンスを返す
+	response := utils.ResponseData[SavingsGoalPlan]{
+		Result: plan,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// InsertSavingsGoalApi は貯金目標の新規登録API
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (sg *apiSavingsGoalManagementFetcher) InsertSavingsGoalApi(c *gin.Context) {
+	var requestData requestInsertSavingsGoalData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if len(requestData.Data) == 0 {
+		response := utils.ErrorMessageResponse{
+			Result: "登録するデータが存在しません。",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	for idx, data := range requestData.Data {
+		validator := validation.RequestInsertSavingsGoalData{
+			GoalName:       data.GoalName,
+			TargetAmount:   common.AnyToStr(data.TargetAmount),
+			TargetDate:     data.TargetDate,
+			CurrentBalance: common.AnyToStr(data.CurrentBalance),
+			UserId:         common.AnyToStr(data.UserId),
+		}
+		if valid, errMsgList := validator.Validate(); !valid {
+			response := utils.ErrorValidationResponse{
+				RecodeRows: idx + 1,
+				Result:     errMsgList,
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	dbFetcher, _, _ := models.NewSavingsGoalDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.InsertSavingsGoal(requestData.Data); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "貯金目標登録時にエラーが発生。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "貯金目標を登録致しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateSavingsGoalApi は貯金目標の更新API
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (sg *apiSavingsGoalManagementFetcher) UpdateSavingsGoalApi(c *gin.Context) {
+	var requestData requestUpdateSavingsGoalData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if len(requestData.Data) == 0 {
+		response := utils.ErrorMessageResponse{
+			Result: "更新するデータが存在しません。",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	for idx, data := range requestData.Data {
+		validator := validation.RequestUpdateSavingsGoalData{
+			GoalId:         data.GoalId,
+			GoalName:       data.GoalName,
+			TargetAmount:   common.AnyToStr(data.TargetAmount),
+			TargetDate:     data.TargetDate,
+			CurrentBalance: common.AnyToStr(data.CurrentBalance),
+			UserId:         common.AnyToStr(data.UserId),
+		}
+		if valid, errMsgList := validator.Validate(); !valid {
+			response := utils.ErrorValidationResponse{
+				RecodeRows: idx + 1,
+				Result:     errMsgList,
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	dbFetcher, _, _ := models.NewSavingsGoalDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.UpdateSavingsGoal(requestData.Data); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "貯金目標更新時にエラーが発生。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "貯金目標の更新が問題なく成功しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteSavingsGoalApi は貯金目標の削除API
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (sg *apiSavingsGoalManagementFetcher) DeleteSavingsGoalApi(c *gin.Context) {
+	var requestData requestDeleteSavingsGoalData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if len(requestData.Data) == 0 {
+		response := utils.ErrorMessageResponse{
+			Result: "削除するデータが存在しません。",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	for idx, data := range requestData.Data {
+		validator := validation.RequestSavingsGoalData{
+			GoalId: data.GoalId,
+			UserId: common.AnyToStr(data.UserId),
+		}
+		if valid, errMsgList := validator.Validate(); !valid {
+			response := utils.ErrorValidationResponse{
+				RecodeRows: idx + 1,
+				Result:     errMsgList,
+			}
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	dbFetcher, _, _ := models.NewSavingsGoalDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.DeleteSavingsGoal(requestData.Data); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "貯金目標削除中にエラーが発生しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "貯金目標の削除が問題なく成功しました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// RecordSavingsGoalSnapshotApi は現時点の目標毎の状況を進捗履歴として記録するAPI
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (sg *apiSavingsGoalManagementFetcher) RecordSavingsGoalSnapshotApi(c *gin.Context) {
+	var requestData requestSavingsGoalSnapshotData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userIdPrams := common.AnyToStr(requestData.UserId)
+	validator := validation.RequestDateRangeData{
+		UserId: userIdPrams,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, _ := sg.CommonFetcher.StrToInt(userIdPrams)
+
+	plan, err := sg.savingsGoalPlan(userId, time.Now())
+	if errors.Is(err, models.ErrActivePriceScenarioNotFound) {
+		// 目標の状況は有効な収支シナリオの残額から判定するため、シナリオの登録を促す
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	} else if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if len(plan.Goals) == 0 {
+		response := utils.ErrorMessageResponse{
+			Result: "記録する貯金目標が存在しません。",
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	var snapshots []models.SavingsGoalSnapshotData
+	for _, goal := range plan.Goals {
+		snapshots = append(snapshots, models.SavingsGoalSnapshotData{
+			GoalId:          goal.GoalId,
+			Balance:         goal.CurrentBalance,
+			RequiredMonthly: goal.RequiredMonthly,
+			LeftAmount:      plan.LeftAmount,
+			Status:          goal.Status,
+		})
+	}
+
+	dbFetcher, _, _ := models.NewSavingsGoalDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.InsertSavingsGoalSnapshot(snapshots); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "貯金目標の進捗記録時にエラーが発生。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[SavingsGoalPlan]{
+		Result: plan,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSavingsGoalSnapshotApi は貯金目標の進捗履歴を返すAPI
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (sg *apiSavingsGoalManagementFetcher) GetSavingsGoalSnapshotApi(c *gin.Context) {
+	// パラメータから目標とユーザー情報取得
+	goalId := c.Query("goal_id")
+	userIdPrams := c.Query("user_id")
+
+	validator := validation.RequestSavingsGoalData{
+		GoalId: goalId,
+		UserId: userIdPrams,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, _ := sg.CommonFetcher.StrToInt(userIdPrams)
+
+	dbFetcher, _, _ := models.NewSavingsGoalDataFetcher(config.GetDataBaseSource())
+	snapshots, err := dbFetcher.GetSavingsGoalSnapshots(goalId, userId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// JSONレスポンスを返す
+	response := utils.ResponseData[[]models.SavingsGoalSnapshotData]{
+		Result: snapshots,
+	}
+	c.JSON(http.StatusOK, response)
+}
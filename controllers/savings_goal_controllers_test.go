@@ -0,0 +1,361 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"server/common"
+	"server/models"
+	"server/utils"
+	"testing"
+	"time"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSavingsGoal(name string, target, balance int, targetDate time.Time) models.SavingsGoalData {
+	return models.SavingsGoalData{
+		GoalId:         uuid.New(),
+		UserId:         1,
+		GoalName:       name,
+		TargetAmount:   target,
+		TargetDate:     targetDate,
+		CurrentBalance: balance,
+	}
+}
+
+func testSavingsGoalFetcher() apiSavingsGoalManagementFetcher {
+	return apiSavingsGoalManagementFetcher{
+		CommonFetcher:          common.NewCommonFetcher(),
+		PriceManagementFetcher: NewPriceManagementFetcher(common.NewCommonFetcher()),
+	}
+}
+
+func TestSavingsGoalMonths(t *testing.T) {
+	now := time.Date(2024, time.October, 15, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 5, savingsGoalMonths(now, time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)))
+	// 同月内でも1ヶ月分は積み立てられる
+	assert.Equal(t, 1, savingsGoalMonths(now, time.Date(2024, time.October, 31, 0, 0, 0, 0, time.UTC)))
+	// 目標日を過ぎている
+	assert.Equal(t, 0, savingsGoalMonths(now, time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestCalcSavingsGoalPlan(t *testing.T) {
+	now := time.Date(2024, time.October, 15, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success 目標日が近い順に割り当てる", func(t *testing.T) {
+		goals := []models.SavingsGoalData{
+			// 残り200,000円を5ヶ月 → 月40,000円
+			testSavingsGoal("旅行", 300000, 100000, time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)),
+			// 残り100,000円を10ヶ月 → 月10,000円(割り当て可能額 5,000円)
+			testSavingsGoal("家電", 100000, 0, time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)),
+			// 残り60,000円を10ヶ月 → 月6,000円(割り当て可能額 0円)
+			testSavingsGoal("予備費", 60000, 0, time.Date(2025, time.August, 2, 0, 0, 0, 0, time.UTC)),
+		}
+
+		result, totalRequired := calcSavingsGoalPlan(45000, goals, now)
+
+		assert.Equal(t, 56000, totalRequired)
+		assert.Equal(t, 40000, result[0].RequiredMonthly)
+		assert.Equal(t, SavingsGoalOnTrack, result[0].Status)
+		assert.Equal(t, 5000, result[1].AllocatableAmount)
+		assert.Equal(t, SavingsGoalOffTrack, result[1].Status)
+		assert.Equal(t, 0, result[2].AllocatableAmount)
+		assert.Equal(t, SavingsGoalOffTrack, result[2].Status)
+	})
+
+	t.Run("success at_risk 判定", func(t *testing.T) {
+		goals := []models.SavingsGoalData{
+			testSavingsGoal("旅行", 300000, 100000, time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)),
+		}
+
+		result, _ := calcSavingsGoalPlan(32000, goals, now)
+
+		assert.Equal(t, SavingsGoalAtRisk, result[0].Status)
+	})
+
+	t.Run("success 達成済み・期限切れ", func(t *testing.T) {
+		goals := []models.SavingsGoalData{
+			testSavingsGoal("達成済み", 100000, 120000, time.Date(2024, time.September, 1, 0, 0, 0, 0, time.UTC)),
+			testSavingsGoal("期限切れ", 100000, 50000, time.Date(2024, time.September, 1, 0, 0, 0, 0, time.UTC)),
+		}
+
+		result, totalRequired := calcSavingsGoalPlan(100000, goals, now)
+
+		assert.Equal(t, SavingsGoalOnTrack, result[0].Status)
+		assert.Equal(t, 0, result[0].RequiredMonthly)
+		assert.Equal(t, SavingsGoalOffTrack, result[1].Status)
+		assert.Equal(t, 50000, result[1].RequiredMonthly)
+		assert.Equal(t, 50000, totalRequired)
+	})
+}
+
+func TestGetSavingsGoalApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	scenario := models.PriceScenarioData{
+		ScenarioId:    uuid.MustParse("8df939de-5a97-4f20-b41b-9ac355c16e36"),
+		UserId:        1,
+		ScenarioName:  "現状",
+		MoneyReceived: 300000,
+		Bouns:         600000,
+		FixedCost:     100000,
+		Loan:          80000,
+		Private:       50000,
+		Insurance:     10000,
+		ActiveFlag:    true,
+	}
+
+	t.Run("success GetSavingsGoalApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?user_id=1", nil)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.PriceScenarioDataFetcher{}),
+			"GetActivePriceScenario",
+			func(_ *models.PriceScenarioDataFetcher, userId int) (models.PriceScenarioData, error) {
+				return scenario, nil
+			})
+		defer patches.Reset()
+
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.SavingsGoalDataFetcher{}),
+			"GetSavingsGoals",
+			func(_ *models.SavingsGoalDataFetcher, userId int) ([]models.SavingsGoalData, error) {
+				return []models.SavingsGoalData{
+					testSavingsGoal("旅行", 300000, 100000, time.Now().AddDate(0, 4, 0)),
+				}, nil
+			})
+
+		fetcher := testSavingsGoalFetcher()
+		fetcher.GetSavingsGoalApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response utils.ResponseData[SavingsGoalPlan]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		// 300,000 - 100,000 - 80,000 - 50,000
+		assert.Equal(t, 70000, response.Result.LeftAmount)
+		assert.Equal(t, scenario.ScenarioId, response.Result.ScenarioId)
+		assert.Equal(t, 50000, response.Result.Goals[0].RequiredMonthly)
+		assert.Equal(t, SavingsGoalOnTrack, response.Result.Goals[0].Status)
+	})
+
+	t.Run("error GetSavingsGoalApi 有効なシナリオなし", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?user_id=1", nil)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.PriceScenarioDataFetcher{}),
+			"GetActivePriceScenario",
+			func(_ *models.PriceScenarioDataFetcher, userId int) (models.PriceScenarioData, error) {
+				return models.PriceScenarioData{}, models.ErrActivePriceScenarioNotFound
+			})
+		defer patches.Reset()
+
+		fetcher := testSavingsGoalFetcher()
+		fetcher.GetSavingsGoalApi(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var response utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "有効な収支シナリオが存在しません。", response.Result)
+	})
+
+	t.Run("バリデーションエラー user_id 必須", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?user_id=", nil)
+
+		fetcher := testSavingsGoalFetcher()
+		fetcher.GetSavingsGoalApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestInsertSavingsGoalApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success InsertSavingsGoalApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		body, _ := json.Marshal(testData{
+			Data: []models.InsertSavingsGoalData{
+				{GoalName: "旅行", TargetAmount: 300000, TargetDate: "2025-03-01", CurrentBalance: 100000, UserId: 1},
+			},
+		})
+		c.Request = httptest.NewRequest("POST", "/", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SavingsGoalDataFetcher{}),
+			"InsertSavingsGoal",
+			func(_ *models.SavingsGoalDataFetcher, data []models.InsertSavingsGoalData) error {
+				return nil
+			})
+		defer patches.Reset()
+
+		fetcher := testSavingsGoalFetcher()
+		fetcher.InsertSavingsGoalApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response utils.ResponseData[string]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "貯金目標を登録致しました。", response.Result)
+	})
+
+	t.Run("バリデーションエラー InsertSavingsGoalApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		body, _ := json.Marshal(testData{
+			Data: []models.InsertSavingsGoalData{
+				{GoalName: "旅行", TargetAmount: 0, TargetDate: "2025/03/01", CurrentBalance: 100000, UserId: 1},
+			},
+		})
+		c.Request = httptest.NewRequest("POST", "/", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		fetcher := testSavingsGoalFetcher()
+		fetcher.InsertSavingsGoalApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, response.RecodeRows)
+		assert.ElementsMatch(t, []utils.ErrorMessages{
+			{Field: "target_amount", Message: "目標金額は1以上の整数値のみです。"},
+			{Field: "target_date", Message: "目標日の形式が間違っています。"},
+		}, response.Result)
+	})
+}
+
+func TestRecordSavingsGoalSnapshotApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success RecordSavingsGoalSnapshotApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"user_id":1}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		goal := testSavingsGoal("旅行", 300000, 100000, time.Now().AddDate(0, 4, 0))
+		var recorded []models.SavingsGoalSnapshotData
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.PriceScenarioDataFetcher{}),
+			"GetActivePriceScenario",
+			func(_ *models.PriceScenarioDataFetcher, userId int) (models.PriceScenarioData, error) {
+				return models.PriceScenarioData{MoneyReceived: 300000, FixedCost: 280000}, nil
+			})
+		defer patches.Reset()
+
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.SavingsGoalDataFetcher{}),
+			"GetSavingsGoals",
+			func(_ *models.SavingsGoalDataFetcher, userId int) ([]models.SavingsGoalData, error) {
+				return []models.SavingsGoalData{goal}, nil
+			})
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.SavingsGoalDataFetcher{}),
+			"InsertSavingsGoalSnapshot",
+			func(_ *models.SavingsGoalDataFetcher, data []models.SavingsGoalSnapshotData) error {
+				recorded = data
+				return nil
+			})
+
+		fetcher := testSavingsGoalFetcher()
+		fetcher.RecordSavingsGoalSnapshotApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, recorded, 1)
+		assert.Equal(t, goal.GoalId, recorded[0].GoalId)
+		assert.Equal(t, 100000, recorded[0].Balance)
+		assert.Equal(t, 20000, recorded[0].LeftAmount)
+		assert.Equal(t, SavingsGoalOffTrack, recorded[0].Status)
+	})
+
+	t.Run("error RecordSavingsGoalSnapshotApi 目標なし", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"user_id":1}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.PriceScenarioDataFetcher{}),
+			"GetActivePriceScenario",
+			func(_ *models.PriceScenarioDataFetcher, userId int) (models.PriceScenarioData, error) {
+				return models.PriceScenarioData{}, nil
+			})
+		defer patches.Reset()
+
+		patches.ApplyMethod(
+			reflect.TypeOf(&models.SavingsGoalDataFetcher{}),
+			"GetSavingsGoals",
+			func(_ *models.SavingsGoalDataFetcher, userId int) ([]models.SavingsGoalData, error) {
+				return nil, nil
+			})
+
+		fetcher := testSavingsGoalFetcher()
+		fetcher.RecordSavingsGoalSnapshotApi(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("error RecordSavingsGoalSnapshotApi 有効なシナリオなし", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"user_id":1}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.PriceScenarioDataFetcher{}),
+			"GetActivePriceScenario",
+			func(_ *models.PriceScenarioDataFetcher, userId int) (models.PriceScenarioData, error) {
+				return models.PriceScenarioData{}, models.ErrActivePriceScenarioNotFound
+			})
+		defer patches.Reset()
+
+		fetcher := testSavingsGoalFetcher()
+		fetcher.RecordSavingsGoalSnapshotApi(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assertErrorMessage(t, w, "有効な収支シナリオが存在しません。")
+	})
+}
+
+func TestGetSavingsGoalSnapshotApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("バリデーションエラー goal_id 必須", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?goal_id=&user_id=1", nil)
+
+		fetcher := testSavingsGoalFetcher()
+		fetcher.GetSavingsGoalSnapshotApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, []utils.ErrorMessages{{Field: "goal_id", Message: "目標IDは必須です。"}}, response.Result)
+	})
+}
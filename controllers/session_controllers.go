@@ -0,0 +1,278 @@
+// controllers/session_controllers.go
+package controllers
+
+import (
+	"net/http"
+	"server/common"
+	"server/config"
+	"server/models"
+	"server/utils"
+	"server/validation"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	SessionManagementFetcher interface {
+		GetSessionApi(c *gin.Context)
+		RevokeSessionApi(c *gin.Context)
+		RevokeOtherSessionsApi(c *gin.Context)
+	}
+
+	requestRevokeSessionData struct {
+		SessionId string      `json:"session_id"`
+		UserId    interface{} `json:"user_id"`
+	}
+
+	requestRevokeOtherSessionsData struct {
+		UserId interface{} `json:"user_id"`
+	}
+
+	apiSessionManagementFetcher struct {
+		CommonFetcher common.CommonFetcher
+		UtilsFetcher  utils.UtilsFetcher
+	}
+)
+
+// ユーザーエージェントの最大長(DBのカラム長)
+const sessionUserAgentMaxLength = 512
+
+func NewSessionManagementFetcher(
+	CommonFetcher common.CommonFetcher,
+	UtilsFetcher utils.UtilsFetcher,
+) SessionManagementFetcher {
+	return &apiSessionManagementFetcher{
+		CommonFetcher: CommonFetcher,
+		UtilsFetcher:  UtilsFetcher,
+	}
+}
+
+// recordSession はサインインに成功したセッションを登録する
+//
+// 引数:
+//   - c: Ginコンテキスト
+//   - userId: ユーザーID
+//   - sessionId: トークンに含めたセッションID
+//   - signInType: サインイン方法(enum.SIGN_IN_PASSWORD, enum.SIGN_IN_MAGIC_LINK 又は外部認証の名称)
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func recordSession(c *gin.Context, userId int, sessionId, signInType string) error {
+	userAgent := []rune(c.Request.UserAgent())
+	if len(userAgent) > sessionUserAgentMaxLength {
+		userAgent = userAgent[:sessionUserAgentMaxLength]
+	}
+
+	now := time.Now()
+	dbFetcher, _, _ := models.NewSessionDataFetcher(config.GetDataBaseSource())
+	return dbFetcher.InsertSession(models.SessionData{
+		SessionId:  sessionId,
+		UserId:     userId,
+		SignInType: signInType,
+		UserAgent:  string(userAgent),
+		IpAddress:  c.ClientIP(),
+		CreatedAt:  now,
+		LastSeenAt: now,
+	})
+}
+
+// isSignInUser はリクエストのユーザーIDがトークンのユーザーと一致するかを返す
+func isSignInUser(c *gin.Context, userId int) bool {
+	signInUserId, ok := c.Get(utils.UserId)
+	return ok && signInUserId == userId
+}
+
+// signInUserMismatch はサインインユーザーと異なるユーザーが指定された場合のレスポンスを返す
+func signInUserMismatch(c *gin.Context) {
+	response := utils.ErrorMessageResponse{
+		Result: "サインインユーザーが異なっています。",
+	}
+	c.JSON(http.StatusUnauthorized, response)
+}
+
+// GetSessionApi はサインイン中のセッション一覧を返すAPI
+// リクエストに使われたセッションには current を立てる
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (sm *apiSessionManagementFetcher) GetSessionApi(c *gin.Context) {
+	// パラメータからユーザー情報取得
+	userIdPrams := c.Query("user_id")
+
+	validator := validation.RequestSessionUserData{
+		UserId: userIdPrams,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, _ := sm.CommonFetcher.StrToInt(userIdPrams)
+	if !isSignInUser(c, userId) {
+		signInUserMismatch(c)
+		return
+	}
+
+	// リフレッシュトークンの有効期限内に利用されたセッションのみ対象とする
+	since := time.Now().Add(-time.Duration(utils.RefreshAuthTokenHour) * time.Hour)
+
+	dbFetcher, _, _ := models.NewSessionDataFetcher(config.GetDataBaseSource())
+	sessions, err := dbFetcher.GetSessions(userId, since)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	currentSessionId := c.GetString(utils.SessionId)
+	for idx := range sessions {
+		sessions[idx].Current = sessions[idx].SessionId == currentSessionId
+	}
+
+	// JSONレスポンスを返す
+	response := utils.ResponseData[[]models.SessionData]{
+		RecodeRows: len(sessions),
+		Result:     sessions,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeSessionApi は指定したセッションをサインアウトさせるAPI
+// 現在のセッションを指定した場合はクッキーも削除する
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (sm *apiSessionManagementFetcher) RevokeSessionApi(c *gin.Context) {
+	var requestData requestRevokeSessionData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userIdPrams := common.AnyToStr(requestData.UserId)
+	validator := validation.RequestSessionData{
+		SessionId: requestData.SessionId,
+		UserId:    userIdPrams,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, _ := sm.CommonFetcher.StrToInt(userIdPrams)
+	if !isSignInUser(c, userId) {
+		signInUserMismatch(c)
+		return
+	}
+
+	dbFetcher, _, _ := models.NewSessionDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.RevokeSession(requestData.SessionId, userId); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if err := sm.UtilsFetcher.RevokeSession(requestData.SessionId); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "セッションの失効に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	if requestData.SessionId == c.GetString(utils.SessionId) {
+		c.SetCookie(utils.UserId, "", 0, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
+		c.SetCookie(utils.AuthToken, "", -1, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
+		c.SetCookie(utils.RefreshAuthToken, "", -1, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
+	}
+
+	response := utils.ResponseData[string]{
+		Result: "セッションをサインアウトしました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeOtherSessionsApi は現在のセッション以外を全てサインアウトさせるAPI
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (sm *apiSessionManagementFetcher) RevokeOtherSessionsApi(c *gin.Context) {
+	var requestData requestRevokeOtherSessionsData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userIdPrams := common.AnyToStr(requestData.UserId)
+	validator := validation.RequestSessionUserData{
+		UserId: userIdPrams,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, _ := sm.CommonFetcher.StrToInt(userIdPrams)
+	if !isSignInUser(c, userId) {
+		signInUserMismatch(c)
+		return
+	}
+
+	dbFetcher, _, _ := models.NewSessionDataFetcher(config.GetDataBaseSource())
+	sessionIds, err := dbFetcher.RevokeOtherSessions(userId, c.GetString(utils.SessionId))
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	for _, sessionId := range sessionIds {
+		if err := sm.UtilsFetcher.RevokeSession(sessionId); err != nil {
+			response := utils.ErrorMessageResponse{
+				Result: "セッションの失効に失敗しました。",
+			}
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+	}
+
+	response := utils.ResponseData[[]string]{
+		RecodeRows: len(sessionIds),
+		Result:     sessionIds,
+	}
+	c.JSON(http.StatusOK, response)
+}
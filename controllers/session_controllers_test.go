@@ -0,0 +1,373 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"server/common"
+	mock_utils "server/mock/utils"
+	"server/models"
+	"server/utils"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// patchInsertSession はサインイン時のセッション登録をモック化する
+func patchInsertSession(err error) *Patches {
+	return ApplyMethod(
+		reflect.TypeOf(&models.SessionDataFetcher{}),
+		"InsertSession",
+		func(_ *models.SessionDataFetcher, data models.SessionData) error {
+			return err
+		})
+}
+
+// signInContext はJWTAuthMiddlewareを通過した後のコンテキストを作成する
+func signInContext(w *httptest.ResponseRecorder, req *http.Request, userId int, sessionId string) *gin.Context {
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Set(utils.UserId, userId)
+	c.Set(utils.SessionId, sessionId)
+	return c
+}
+
+func TestRecordSession(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success recordSession", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/api/signin", nil)
+		c.Request.Header.Set("User-Agent", strings.Repeat("a", 600))
+		c.Request.RemoteAddr = "192.0.2.1:12345"
+
+		var recorded models.SessionData
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SessionDataFetcher{}),
+			"InsertSession",
+			func(_ *models.SessionDataFetcher, data models.SessionData) error {
+				recorded = data
+				return nil
+			})
+		defer patches.Reset()
+
+		err := recordSession(c, 1, "8df939de-5a97-4f20-b41b-9ac355c16e36", "password")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "8df939de-5a97-4f20-b41b-9ac355c16e36", recorded.SessionId)
+		assert.Equal(t, 1, recorded.UserId)
+		assert.Equal(t, "password", recorded.SignInType)
+		assert.Equal(t, "192.0.2.1", recorded.IpAddress)
+		// ユーザーエージェントはカラム長で切り詰める
+		assert.Len(t, recorded.UserAgent, sessionUserAgentMaxLength)
+		assert.Equal(t, recorded.CreatedAt, recorded.LastSeenAt)
+	})
+}
+
+func TestGetSessionApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success GetSessionApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("GET", "/?user_id=1", nil), 1, "8df939de-5a97-4f20-b41b-9ac355c16e37")
+
+		now := time.Now()
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SessionDataFetcher{}),
+			"GetSessions",
+			func(_ *models.SessionDataFetcher, UserId int, since time.Time) ([]models.SessionData, error) {
+				return []models.SessionData{
+					{SessionId: "8df939de-5a97-4f20-b41b-9ac355c16e36", UserId: 1, SignInType: "google", LastSeenAt: now},
+					{SessionId: "8df939de-5a97-4f20-b41b-9ac355c16e37", UserId: 1, SignInType: "password", LastSeenAt: now},
+				}, nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiSessionManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetSessionApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response utils.ResponseData[[]models.SessionData]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, response.RecodeRows)
+		assert.False(t, response.Result[0].Current)
+		assert.True(t, response.Result[1].Current)
+	})
+
+	t.Run("error GetSessionApi サインインユーザーが異なる", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("GET", "/?user_id=2", nil), 1, "8df939de-5a97-4f20-b41b-9ac355c16e37")
+
+		fetcher := apiSessionManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetSessionApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		var response utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "サインインユーザーが異なっています。", response.Result)
+	})
+
+	t.Run("バリデーションエラー GetSessionApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("GET", "/?user_id=a", nil), 1, "")
+
+		fetcher := apiSessionManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetSessionApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, []utils.ErrorMessages{
+			{Field: "user_id", Message: "ユーザーIDは整数値のみです。"},
+		}, response.Result)
+	})
+}
+
+func TestRevokeSessionApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success RevokeSessionApi 他の端末のセッション", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().
+			RevokeSession("8df939de-5a97-4f20-b41b-9ac355c16e36").
+			Return(nil)
+
+		body, _ := json.Marshal(requestRevokeSessionData{
+			SessionId: "8df939de-5a97-4f20-b41b-9ac355c16e36",
+			UserId:    1,
+		})
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("POST", "/", bytes.NewBuffer(body)), 1, "8df939de-5a97-4f20-b41b-9ac355c16e37")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SessionDataFetcher{}),
+			"RevokeSession",
+			func(_ *models.SessionDataFetcher, SessionId string, UserId int) error {
+				return nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiSessionManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+			UtilsFetcher:  mockUtilsFetcher,
+		}
+		fetcher.RevokeSessionApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		// 他の端末のセッションの場合はクッキーを削除しない
+		assert.Empty(t, w.Result().Cookies())
+	})
+
+	t.Run("success RevokeSessionApi 現在のセッション", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().
+			RevokeSession("8df939de-5a97-4f20-b41b-9ac355c16e37").
+			Return(nil)
+
+		body, _ := json.Marshal(requestRevokeSessionData{
+			SessionId: "8df939de-5a97-4f20-b41b-9ac355c16e37",
+			UserId:    1,
+		})
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("POST", "/", bytes.NewBuffer(body)), 1, "8df939de-5a97-4f20-b41b-9ac355c16e37")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SessionDataFetcher{}),
+			"RevokeSession",
+			func(_ *models.SessionDataFetcher, SessionId string, UserId int) error {
+				return nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiSessionManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+			UtilsFetcher:  mockUtilsFetcher,
+		}
+		fetcher.RevokeSessionApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, w.Result().Cookies(), 3)
+	})
+
+	t.Run("error RevokeSessionApi 対象のセッションなし", func(t *testing.T) {
+		body, _ := json.Marshal(requestRevokeSessionData{
+			SessionId: "8df939de-5a97-4f20-b41b-9ac355c16e36",
+			UserId:    1,
+		})
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("POST", "/", bytes.NewBuffer(body)), 1, "8df939de-5a97-4f20-b41b-9ac355c16e37")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SessionDataFetcher{}),
+			"RevokeSession",
+			func(_ *models.SessionDataFetcher, SessionId string, UserId int) error {
+				return errors.New("対象のセッションが存在しません。")
+			})
+		defer patches.Reset()
+
+		fetcher := apiSessionManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.RevokeSessionApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		var response utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "対象のセッションが存在しません。", response.Result)
+	})
+
+	t.Run("error RevokeSessionApi サインインユーザーが異なる", func(t *testing.T) {
+		body, _ := json.Marshal(requestRevokeSessionData{
+			SessionId: "8df939de-5a97-4f20-b41b-9ac355c16e36",
+			UserId:    2,
+		})
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("POST", "/", bytes.NewBuffer(body)), 1, "8df939de-5a97-4f20-b41b-9ac355c16e37")
+
+		fetcher := apiSessionManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.RevokeSessionApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("バリデーションエラー RevokeSessionApi", func(t *testing.T) {
+		body, _ := json.Marshal(requestRevokeSessionData{
+			SessionId: "session",
+		})
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("POST", "/", bytes.NewBuffer(body)), 1, "")
+
+		fetcher := apiSessionManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.RevokeSessionApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []utils.ErrorMessages{
+			{Field: "session_id", Message: "セッションIDの形式が間違っています。"},
+			{Field: "user_id", Message: "ユーザーIDは必須です。"},
+		}, response.Result)
+	})
+}
+
+func TestRevokeOtherSessionsApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success RevokeOtherSessionsApi", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().RevokeSession("8df939de-5a97-4f20-b41b-9ac355c16e36").Return(nil)
+		mockUtilsFetcher.EXPECT().RevokeSession("8df939de-5a97-4f20-b41b-9ac355c16e38").Return(nil)
+
+		body, _ := json.Marshal(requestRevokeOtherSessionsData{UserId: 1})
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("POST", "/", bytes.NewBuffer(body)), 1, "8df939de-5a97-4f20-b41b-9ac355c16e37")
+
+		var currentSessionId string
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SessionDataFetcher{}),
+			"RevokeOtherSessions",
+			func(_ *models.SessionDataFetcher, UserId int, CurrentSessionId string) ([]string, error) {
+				currentSessionId = CurrentSessionId
+				return []string{
+					"8df939de-5a97-4f20-b41b-9ac355c16e36",
+					"8df939de-5a97-4f20-b41b-9ac355c16e38",
+				}, nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiSessionManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+			UtilsFetcher:  mockUtilsFetcher,
+		}
+		fetcher.RevokeOtherSessionsApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "8df939de-5a97-4f20-b41b-9ac355c16e37", currentSessionId)
+		var response utils.ResponseData[[]string]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, response.RecodeRows)
+	})
+
+	t.Run("error RevokeOtherSessionsApi 失効リストの登録に失敗", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().RevokeSession(gomock.Any()).Return(errors.New("保存エラー"))
+
+		body, _ := json.Marshal(requestRevokeOtherSessionsData{UserId: 1})
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("POST", "/", bytes.NewBuffer(body)), 1, "8df939de-5a97-4f20-b41b-9ac355c16e37")
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SessionDataFetcher{}),
+			"RevokeOtherSessions",
+			func(_ *models.SessionDataFetcher, UserId int, CurrentSessionId string) ([]string, error) {
+				return []string{"8df939de-5a97-4f20-b41b-9ac355c16e36"}, nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiSessionManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+			UtilsFetcher:  mockUtilsFetcher,
+		}
+		fetcher.RevokeOtherSessionsApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		var response utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "セッションの失効に失敗しました。", response.Result)
+	})
+
+	t.Run("error RevokeOtherSessionsApi サインインユーザーが異なる", func(t *testing.T) {
+		body, _ := json.Marshal(requestRevokeOtherSessionsData{UserId: 2})
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("POST", "/", bytes.NewBuffer(body)), 1, "8df939de-5a97-4f20-b41b-9ac355c16e37")
+
+		fetcher := apiSessionManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.RevokeOtherSessionsApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
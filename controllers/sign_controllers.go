@@ -3,16 +3,20 @@ package controllers
 
 import (
 	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"math/big"
 	"net/http"
 	"server/common"
 	"server/config"
+	"server/enum"
 	"server/models" // モデルのインポート
 	"server/templates"
 	"server/utils"
 	"server/validation"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -30,9 +34,18 @@ type (
 		PostSignUpApi(c *gin.Context)
 		PutSignInEditApi(c *gin.Context)
 		DeleteSignInApi(c *gin.Context)
+		AccountRestoreApi(c *gin.Context)
 		SignOutApi(c *gin.Context)
 		RegisterEmailCheckNotice(c *gin.Context)
 		NewPasswordUpdate(c *gin.Context)
+		PostSignInTotpApi(c *gin.Context)
+		AccountUnlockApi(c *gin.Context)
+		AdminAccountUnlockApi(c *gin.Context)
+		EmailChangeRequestApi(c *gin.Context)
+		EmailChangeConfirmApi(c *gin.Context)
+		EmailChangeCancelApi(c *gin.Context)
+		MagicLinkRequestApi(c *gin.Context)
+		MagicLinkSignInApi(c *gin.Context)
 	}
 
 	// JSONデータを受け取るための構造体を定義
@@ -65,6 +78,16 @@ type (
 		UserName  string `json:"user_name"`
 	}
 
+	// pendingSignUp は仮登録の情報をRedisにJSONで保存するための構造体
+	// ユーザー名・パスワードのハッシュにカンマ等が含まれても分割しない
+	// 認証コードはサーバー側でのみ保持し、クライアントには返さない
+	pendingSignUp struct {
+		UserEmail    string `json:"user_email"`
+		UserPassword string `json:"user_password"`
+		UserName     string `json:"user_name"`
+		ConfirmCode  string `json:"confirm_code"`
+	}
+
 	RequestRefreshToken struct {
 		UserId int `json:"user_id"`
 	}
@@ -74,20 +97,26 @@ type (
 		CommonFetcher        common.CommonFetcher
 		EmailTemplateService templates.EmailTemplateService
 		RedisService         config.RedisService
+		AttemptLimiter       utils.AttemptLimiter
 	}
 )
 
+// signInEditPasswordNotice はパスワード変更の通知メールに記載する文言(新しいパスワードは記載しない)
+const signInEditPasswordNotice = "パスワードを変更しました。お心当たりがない場合は、至急パスワードを再設定してください。"
+
 func NewSignDataFetcher(
 	tokenFetcher utils.UtilsFetcher,
 	CommonFetcher common.CommonFetcher,
 	EmailTemplateService templates.EmailTemplateService,
 	RedisService config.RedisService,
+	AttemptLimiter utils.AttemptLimiter,
 ) SignDataFetcher {
 	return &apiSignDataFetcher{
 		UtilsFetcher:         tokenFetcher,
 		CommonFetcher:        CommonFetcher,
 		EmailTemplateService: EmailTemplateService,
 		RedisService:         RedisService,
+		AttemptLimiter:       AttemptLimiter,
 	}
 }
 
@@ -121,12 +150,44 @@ func (af *apiSignDataFetcher) PostSignInApi(c *gin.Context) {
 		return
 	}
 
+	// 連続して失敗しているアカウント・IPアドレスからの試行を制限する
+	accountKey := utils.SignInAccountAttemptKey(requestData.UserEmail)
+	ipKey := utils.SignInIPAttemptKey(c.ClientIP())
+	if !af.checkAttempts(c, signInAttemptsMessage, accountKey, ipKey) {
+		return
+	}
+
 	dbFetcher, _, _ := models.NewSignDataFetcher(
 		config.GetDataBaseSource(),
-		utils.NewUtilsFetcher(utils.JwtSecret),
+		utils.NewUtilsFetcher(utils.JwtKeys),
 	)
 	result, err := dbFetcher.GetSignIn(requestData)
 	if err != nil {
+		statuses, lockErr := af.recordFailedAttempts(accountKey, ipKey)
+		if lockErr != nil {
+			response := utils.ErrorMessageResponse{
+				Result: "試行回数の記録に失敗しました。",
+			}
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+
+		// 今回の失敗でアカウントがロックされた場合はロック解除のリンクを通知する
+		if statuses[0].JustLocked {
+			if err := af.accountLockedNotice(requestData.UserEmail, accountKey.Policy.Lockout); err != nil {
+				response := utils.ErrorMessageResponse{
+					Result: err.Error(),
+				}
+				c.JSON(http.StatusInternalServerError, response)
+				return
+			}
+		}
+
+		if wait, locked := lockedWait(statuses); locked {
+			tooManyAttempts(c, wait, signInAttemptsMessage)
+			return
+		}
+
 		response := utils.ErrorMessageResponse{
 			Result: err.Error(),
 		}
@@ -134,8 +195,46 @@ func (af *apiSignDataFetcher) PostSignInApi(c *gin.Context) {
 		return
 	}
 
+	// サインインに成功したためアカウントの失敗回数を消去する(失敗してもサインインは継続する)
+	_ = af.AttemptLimiter.Reset(accountKey)
+
+	// 二段階認証が有効な場合はワンタイムパスワードの確認後にサインインを完了する
+	totpFetcher, _, _ := models.NewTotpDataFetcher(config.GetDataBaseSource())
+	totp, err := totpFetcher.GetTotp(result.UserId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "二段階認証の設定の取得に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	if totp.Enabled {
+		af.issueTotpChallenge(c, result, enum.SIGN_IN_PASSWORD)
+		return
+	}
+
+	af.completeSignIn(c, result, enum.SIGN_IN_PASSWORD)
+}
+
+// completeSignIn はトークンを発行してサインインを完了する
+// パスワード・マジックリンク等の確認後(二段階認証が有効な場合はワンタイムパスワードの確認後)に呼び出す
+//
+// 引数:
+//   - c: Ginコンテキスト
+//   - result: サインインするユーザー
+//   - signInType: サインイン方法(enum.SIGN_IN_PASSWORD・enum.SIGN_IN_MAGIC_LINK 又は外部認証の名称)
+//
+
+func (af *apiSignDataFetcher) completeSignIn(c *gin.Context, result models.SignInData, signInType string) {
+	// 管理者に無効化されたユーザーはサインインできない
+	role, ok := signInRole(c, result.UserId)
+	if !ok {
+		return
+	}
+
+	sessionId := uuid.New().String()
 	// UtilsFetcher を使用してトークンを生成
-	newToken, err := af.UtilsFetcher.NewToken(result.UserId, utils.AuthTokenHour)
+	newToken, err := af.UtilsFetcher.NewToken(result.UserId, sessionId, role, utils.AuthTokenHour)
 	if err != nil {
 		response := utils.ErrorMessageResponse{
 			Result: "新規トークンの生成に失敗しました。",
@@ -144,7 +243,7 @@ func (af *apiSignDataFetcher) PostSignInApi(c *gin.Context) {
 		return
 	}
 
-	refreshToken, err := af.UtilsFetcher.RefreshToken(result.UserId, utils.RefreshAuthTokenHour)
+	refreshToken, err := af.UtilsFetcher.RefreshToken(result.UserId, sessionId, utils.RefreshAuthTokenHour)
 	if err != nil {
 		response := utils.ErrorMessageResponse{
 			Result: "リフレッシュトークンの生成に失敗しました。",
@@ -153,6 +252,14 @@ func (af *apiSignDataFetcher) PostSignInApi(c *gin.Context) {
 		return
 	}
 
+	if err := recordSession(c, result.UserId, sessionId, signInType); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "セッションの記録に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
 	c.SetCookie(utils.UserId, fmt.Sprintf("%d", result.UserId), 0, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
 	c.SetCookie(utils.AuthToken, newToken, utils.AuthTokenHour*utils.SecondsInHour, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
 	c.SetCookie(utils.RefreshAuthToken, refreshToken, utils.RefreshAuthTokenHour*utils.SecondsInHour, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
@@ -248,7 +355,7 @@ func (af *apiSignDataFetcher) GetRefreshTokenApi(c *gin.Context) {
 	}
 
 	// クレームからユーザー情報を取得
-	_, ok := af.UtilsFetcher.MapClaims(token.(*jwt.Token))
+	claims, ok := af.UtilsFetcher.MapClaims(token.(*jwt.Token))
 	if !ok {
 		response := utils.ErrorMessageResponse{
 			Result: "無効なリフレッシュトークン。",
@@ -257,9 +364,38 @@ func (af *apiSignDataFetcher) GetRefreshTokenApi(c *gin.Context) {
 		return
 	}
 
+	// アクセストークンが提示された場合は受け付けない
+	if err := utils.CheckTokenType(claims.(jwt.MapClaims), utils.TokenTypeRefresh); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "無効なリフレッシュトークン。",
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// アクセストークンはリフレッシュトークンに紐づくユーザーにのみ発行する
+	// パラメータ・クッキーのユーザーIDが異なる場合は他のユーザーのトークンを要求しているため受け付けない
+	claimUserId, ok := claims.(jwt.MapClaims)["UserId"].(float64)
 	userId, _ := common.StrToInt(userIdCheck)
+	if !ok || int(claimUserId) != userId {
+		response := utils.ErrorMessageResponse{
+			Result: "サインインユーザーが異なっています。",
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+	userId = int(claimUserId)
+	// サインイン時のセッションを引き継ぐ
+	sessionId, _ := claims.(jwt.MapClaims)["sid"].(string)
+
+	// ロールの変更・無効化を反映するため、再発行の度にロールを取得する
+	// ロールはリフレッシュトークンに紐づくユーザーのものを取得し、他のユーザーのロールで発行しない
+	role, ok := signInRole(c, userId)
+	if !ok {
+		return
+	}
 
-	newToken, err := af.UtilsFetcher.NewToken(userId, utils.RefreshAuthTokenHour)
+	newToken, err := af.UtilsFetcher.NewToken(userId, sessionId, role, utils.AuthTokenHour)
 	if err != nil {
 		response := utils.ErrorMessageResponse{
 			Result: "新しいアクセストークンの生成に失敗しました。",
@@ -268,12 +404,59 @@ func (af *apiSignDataFetcher) GetRefreshTokenApi(c *gin.Context) {
 		return
 	}
 
-	// 新しいアクセストークンをクッキーとしてセット（またはJSONとして返す）
-	c.SetCookie(utils.AuthToken, newToken, utils.AuthTokenHour*utils.SecondsInHour, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
-	// // リフレッシュトークンも更新しておく
-	// c.SetCookie(utils.RefreshAuthToken, newToken, 2*60*60, "/", domain, secure, true)
+	// リフレッシュトークンをローテーションし、古いトークンを無効にする
+	newRefreshToken, err := af.UtilsFetcher.RotateRefreshToken(claims.(jwt.MapClaims), utils.RefreshAuthTokenHour)
+	if errors.Is(err, utils.ErrRefreshTokenReused) {
+		// 使用済みのトークンが提示されたためファミリーは失効済み
+		// 漏洩したトークンで発行済みのアクセストークンも使えないよう、ファミリーのセッションを失効させる
+		if sessionId != "" {
+			if err := af.UtilsFetcher.RevokeSession(sessionId); err != nil {
+				response := utils.ErrorMessageResponse{
+					Result: "セッションの失効に失敗しました。",
+				}
+				c.JSON(http.StatusInternalServerError, response)
+				return
+			}
+			// セッション一覧に表示しないよう記録する(既に失効済みの場合もあるため失敗してもエラーにしない)
+			sessionFetcher, _, _ := models.NewSessionDataFetcher(config.GetDataBaseSource())
+			if err := sessionFetcher.RevokeSession(sessionId, userId); err != nil {
+				log.Printf("セッションの失効記録に失敗しました session_id=%s: %v", sessionId, err)
+			}
+		}
 
-	// log.Println("INFO: ", newToken)
+		c.SetCookie(utils.AuthToken, "", -1, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
+		c.SetCookie(utils.RefreshAuthToken, "", -1, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
+
+		if err := af.refreshTokenReuseNotice(userId); err != nil {
+			response := utils.ErrorMessageResponse{
+				Result: err.Error(),
+			}
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+
+		response := utils.ErrorMessageResponse{
+			Result: "使用済みのリフレッシュトークンが提示されたため、全てのサインインを無効にしました。再ログインしてください。",
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	} else if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "リフレッシュトークンが無効です。再ログインしてください。",
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// セッションの最終利用日時を更新(失敗してもトークンの発行は継続する)
+	if sessionId != "" {
+		sessionFetcher, _, _ := models.NewSessionDataFetcher(config.GetDataBaseSource())
+		_ = sessionFetcher.TouchSession(sessionId)
+	}
+
+	// 新しいアクセストークンとリフレッシュトークンをクッキーとしてセット
+	c.SetCookie(utils.AuthToken, newToken, utils.AuthTokenHour*utils.SecondsInHour, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
+	c.SetCookie(utils.RefreshAuthToken, newRefreshToken, utils.RefreshAuthTokenHour*utils.SecondsInHour, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
 
 	// リフレッシュトークン成功のレスポンス
 	response := utils.ResponseData[string]{
@@ -282,6 +465,77 @@ func (af *apiSignDataFetcher) GetRefreshTokenApi(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// refreshTokenReuseNotice はリフレッシュトークンの再利用を検知したことをユーザーにメールで通知する
+func (af *apiSignDataFetcher) refreshTokenReuseNotice(userId int) error {
+	dbFetcher, _, _ := models.NewSignDataFetcher(
+		config.GetDataBaseSource(),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+	)
+	userEmail, err := dbFetcher.GetUserEmail(userId)
+	if err != nil {
+		return fmt.Errorf("ユーザー情報取得エラー(トークン再利用): %v", err)
+	}
+
+	subject, body, err := af.EmailTemplateService.RefreshTokenReuseTemplate(
+		userEmail,
+		af.UtilsFetcher.DateTimeStr(time.Now(), "2006年01月02日 15:04"),
+	)
+	if err != nil {
+		return fmt.Errorf("メールテンプレート生成エラー(トークン再利用): %v", err)
+	}
+
+	if err := af.UtilsFetcher.SendMail(userEmail, subject, body, true); err != nil {
+		return fmt.Errorf("メール送信エラー(トークン再利用): %v", err)
+	}
+	return nil
+}
+
+// revokeCookieTokens はCookieに保存されているアクセストークンとリフレッシュトークンを失効させる
+// 有効期限切れや失効済みの場合もあるためエラーは無視する
+func revokeCookieTokens(c *gin.Context, utilsFetcher utils.UtilsFetcher) {
+	if authToken, err := c.Cookie(utils.AuthToken); err == nil {
+		if token, err := utilsFetcher.ParseWithClaims(authToken); err == nil {
+			if claims, ok := utilsFetcher.MapClaims(token.(*jwt.Token)); ok {
+				utilsFetcher.RevokeToken(claims.(jwt.MapClaims))
+			}
+		}
+	}
+	if refreshToken, err := c.Cookie(utils.RefreshAuthToken); err == nil {
+		utilsFetcher.RevokeRefreshTokenFamily(refreshToken)
+	}
+}
+
+// cookieSession はCookieのトークンからサインイン中のセッションIDとユーザーIDを取得する
+// アクセストークンが期限切れの場合もあるため、リフレッシュトークンからも取得する
+func cookieSession(c *gin.Context, utilsFetcher utils.UtilsFetcher) (string, int, bool) {
+	for _, name := range []string{utils.AuthToken, utils.RefreshAuthToken} {
+		tokenString, err := c.Cookie(name)
+		if err != nil {
+			continue
+		}
+		token, err := utilsFetcher.ParseWithClaims(tokenString)
+		if err != nil {
+			continue
+		}
+		claims, ok := utilsFetcher.MapClaims(token.(*jwt.Token))
+		if !ok {
+			continue
+		}
+		sessionId, _ := claims.(jwt.MapClaims)["sid"].(string)
+		userId, ok := claims.(jwt.MapClaims)["UserId"].(float64)
+		if sessionId != "" && ok {
+			return sessionId, int(userId), true
+		}
+	}
+	return "", 0, false
+}
+
+// pendingSignUpKey は仮登録の情報を保存するRedisのキー
+// クライアントには仮登録ID(uuid)のみを返し、認証コードはキーに含めない
+func pendingSignUpKey(registrationId string) string {
+	return fmt.Sprintf("pending_sign_up:%s", registrationId)
+}
+
 // TemporaryPostSignUpApi はサインイン情報を仮登録API
 //
 // 引数:
@@ -318,19 +572,17 @@ func (af *apiSignDataFetcher) TemporaryPostSignUpApi(c *gin.Context) {
 	hashPassword, _ := af.UtilsFetcher.EncryptPassword(requestData.UserPassword)
 	uid := uuid.New().String()
 	confirmCode, _ := rand.Int(rand.Reader, big.NewInt(10000))
-	// redisに登録する際のkey
 	confirmCodeStr := fmt.Sprintf("%04d", confirmCode.Int64())
-	key := fmt.Sprintf("%s:%s", confirmCodeStr, uid)
-	// redisに登録する際のvalue
-	userInfo := [...]string{
-		requestData.UserEmail,
-		hashPassword,
-		requestData.UserName,
+	// redisに登録する際のvalue(JSONで保存する)
+	value := pendingSignUp{
+		UserEmail:    requestData.UserEmail,
+		UserPassword: hashPassword,
+		UserName:     requestData.UserName,
+		ConfirmCode:  confirmCodeStr,
 	}
-	value := strings.Join(userInfo[:], ",") // 配列をカンマ区切りの文字列に変換
 
 	// 保存
-	if err = af.RedisService.RedisSet(key, value, time.Hour); err != nil {
+	if err = af.RedisService.RedisSet(pendingSignUpKey(uid), value, time.Hour); err != nil {
 		response := utils.ErrorMessageResponse{
 			Result: err.Error(),
 		}
@@ -359,7 +611,7 @@ func (af *apiSignDataFetcher) TemporaryPostSignUpApi(c *gin.Context) {
 	// サインアップ仮登録成功のレスポンス
 	response := utils.ResponseData[TemporayPostSignUpResult]{
 		Result: TemporayPostSignUpResult{
-			RedisKey:  key,
+			RedisKey:  uid,
 			UserEmail: requestData.UserEmail,
 			UserName:  requestData.UserName,
 		},
@@ -368,6 +620,7 @@ func (af *apiSignDataFetcher) TemporaryPostSignUpApi(c *gin.Context) {
 }
 
 // RetryAuthEmail はAPIはメール認証を再通知するために使用
+// 同じ仮登録のメールアドレス・IPアドレスへの連続送信は制限する
 //
 // 引数:
 //   - c: Ginコンテキスト
@@ -395,7 +648,7 @@ func (af *apiSignDataFetcher) RetryAuthEmail(c *gin.Context) {
 	}
 
 	// サインアップ仮登録した情報を取得
-	redisGet, err := af.RedisService.RedisGet(RedisKey)
+	redisGet, err := af.RedisService.RedisGet(pendingSignUpKey(RedisKey))
 	if err != nil {
 		response := utils.ErrorMessageResponse{
 			Result: err.Error(),
@@ -404,15 +657,37 @@ func (af *apiSignDataFetcher) RetryAuthEmail(c *gin.Context) {
 		return
 	}
 
+	var pending pendingSignUp
+	if err := json.Unmarshal([]byte(redisGet), &pending); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "仮登録の情報が不正です。仮登録からやり直してください。",
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// 同じ仮登録・IPアドレスへのメールの連続送信を制限する
+	accountKey := utils.AuthEmailRetryAccountAttemptKey(pending.UserEmail)
+	ipKey := utils.AuthEmailRetryIPAttemptKey(c.ClientIP())
+	if !af.checkAttempts(c, authEmailRetryAttemptsMessage, accountKey, ipKey) {
+		return
+	}
+	// 失敗ではなく送信の度に数える
+	if _, err := af.recordFailedAttempts(accountKey, ipKey); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "試行回数の記録に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
 	uid := uuid.New().String()
 	confirmCode, _ := rand.Int(rand.Reader, big.NewInt(10000))
-
-	// redisに再登録する際のキー
 	confirmCodeStr := fmt.Sprintf("%04d", confirmCode.Int64())
-	newKey := fmt.Sprintf("%s:%s", confirmCodeStr, uid)
+	pending.ConfirmCode = confirmCodeStr
 
-	// 更新して保存
-	if err = af.RedisService.RedisSet(newKey, redisGet, time.Hour); err != nil {
+	// 新しい認証コードで更新して保存
+	if err = af.RedisService.RedisSet(pendingSignUpKey(uid), pending, time.Hour); err != nil {
 		response := utils.ErrorMessageResponse{
 			Result: err.Error(),
 		}
@@ -421,7 +696,7 @@ func (af *apiSignDataFetcher) RetryAuthEmail(c *gin.Context) {
 	}
 
 	// 前の情報は削除する
-	if err = af.RedisService.RedisDel(RedisKey); err != nil {
+	if err = af.RedisService.RedisDel(pendingSignUpKey(RedisKey)); err != nil {
 		response := utils.ErrorMessageResponse{
 			Result: err.Error(),
 		}
@@ -429,7 +704,8 @@ func (af *apiSignDataFetcher) RetryAuthEmail(c *gin.Context) {
 		return
 	}
 
-	subject, body, err := af.EmailTemplateService.TemporayPostSignUpTemplate(UserName, confirmCodeStr)
+	// 認証コードは仮登録したメールアドレスにのみ送信する
+	subject, body, err := af.EmailTemplateService.TemporayPostSignUpTemplate(pending.UserName, confirmCodeStr)
 	if err != nil {
 		response := utils.ErrorMessageResponse{
 			Result: "メールテンプレート生成エラー(メール再通知): " + err.Error(),
@@ -439,7 +715,7 @@ func (af *apiSignDataFetcher) RetryAuthEmail(c *gin.Context) {
 	}
 
 	// メール送信ユーティリティを呼び出し
-	if err := af.UtilsFetcher.SendMail(UserEmail, subject, body, false); err != nil {
+	if err := af.UtilsFetcher.SendMail(pending.UserEmail, subject, body, false); err != nil {
 		response := utils.ErrorMessageResponse{
 			Result: "メール送信エラー(メール再通知): " + err.Error(),
 		}
@@ -450,14 +726,50 @@ func (af *apiSignDataFetcher) RetryAuthEmail(c *gin.Context) {
 	// メール再通知成功のレスポンス
 	response := utils.ResponseData[RetryAuthEmailResult]{
 		Result: RetryAuthEmailResult{
-			RedisKey:  newKey,
-			UserEmail: UserEmail,
-			UserName:  UserName,
+			RedisKey:  uid,
+			UserEmail: pending.UserEmail,
+			UserName:  pending.UserName,
 		},
 	}
 	c.JSON(http.StatusOK, response)
 }
 
+// confirmCodeFailed は認証コードの誤りを試行回数に記録し、エラーを返す
+// 仮登録の失敗回数が上限に達した場合は仮登録を破棄する
+//
+// 引数:
+//   - c: Ginコンテキスト
+//   - registrationId: 仮登録ID(仮登録が存在しない場合は空文字)
+//   - keys: 失敗を記録する試行回数のキー(仮登録が存在する場合、先頭は仮登録のメールアドレスのキー)
+//
+
+func (af *apiSignDataFetcher) confirmCodeFailed(c *gin.Context, registrationId string, keys ...utils.AttemptKey) {
+	statuses, err := af.recordFailedAttempts(keys...)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "試行回数の記録に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// 仮登録がロックされた場合は仮登録を破棄し、仮登録からやり直してもらう
+	if registrationId != "" && statuses[0].Locked {
+		_ = af.RedisService.RedisDel(pendingSignUpKey(registrationId))
+		tooManyAttempts(c, statuses[0].Wait, "認証コードの試行回数が上限に達しました。仮登録からやり直してください。")
+		return
+	}
+	if wait, locked := lockedWait(statuses); locked {
+		tooManyAttempts(c, wait, confirmCodeAttemptsMessage)
+		return
+	}
+
+	response := utils.ErrorMessageResponse{
+		Result: "メール認証コードが間違っています。",
+	}
+	c.JSON(http.StatusUnauthorized, response)
+}
+
 // PostSignUpApi はサインイン情報を新規登録API
 //
 // 引数:
@@ -475,32 +787,45 @@ func (af *apiSignDataFetcher) PostSignUpApi(c *gin.Context) {
 		return
 	}
 
-	// 認証コード取得
-	auth := strings.Split(requestData.RedisKey, ":")
-	if auth[0] != requestData.AuthEmailCode {
-		response := utils.ErrorMessageResponse{
-			Result: "メール認証コードが間違っています。",
-		}
-		c.JSON(http.StatusUnauthorized, response)
+	// IPアドレス毎に認証コードの試行を制限する
+	ipKey := utils.ConfirmCodeIPAttemptKey(c.ClientIP())
+	if !af.checkAttempts(c, confirmCodeAttemptsMessage, ipKey) {
 		return
 	}
 
 	// サインアップ仮登録した情報を取得
-	redisGet, err := af.RedisService.RedisGet(requestData.RedisKey)
+	// 仮登録が存在しない場合も認証コードの誤りとしてIPアドレスの試行回数に数える
+	redisGet, err := af.RedisService.RedisGet(pendingSignUpKey(requestData.RedisKey))
 	if err != nil {
+		af.confirmCodeFailed(c, "", ipKey)
+		return
+	}
+
+	var pending pendingSignUp
+	if err := json.Unmarshal([]byte(redisGet), &pending); err != nil {
 		response := utils.ErrorMessageResponse{
-			Result: err.Error(),
+			Result: "仮登録の情報が不正です。仮登録からやり直してください。",
 		}
-		c.JSON(http.StatusInternalServerError, response)
+		c.JSON(http.StatusBadRequest, response)
 		return
 	}
 
-	// username,password,usernameの順で文字列が連結されている
-	info := strings.Split(redisGet, ",")
+	// 認証コードの再送信で仮登録のIDが変わっても引き継ぐよう、仮登録したメールアドレス毎に制限する
+	codeKey := utils.ConfirmCodeAttemptKey(pending.UserEmail)
+	if !af.checkAttempts(c, confirmCodeAttemptsMessage, codeKey) {
+		return
+	}
 
-	userEmail := info[0]
-	userPassword := info[1]
-	userName := info[2]
+	// 認証コードはサーバー側で保持した値と比較する
+	if pending.ConfirmCode == "" ||
+		subtle.ConstantTimeCompare([]byte(pending.ConfirmCode), []byte(requestData.AuthEmailCode)) != 1 {
+		af.confirmCodeFailed(c, requestData.RedisKey, codeKey, ipKey)
+		return
+	}
+
+	userEmail := pending.UserEmail
+	userPassword := pending.UserPassword
+	userName := pending.UserName
 
 	validator := validation.RequestSignUpData{
 		UserEmail:    userEmail,
@@ -518,7 +843,7 @@ func (af *apiSignDataFetcher) PostSignUpApi(c *gin.Context) {
 
 	dbFetcher, _, _ := models.NewSignDataFetcher(
 		config.GetDataBaseSource(),
-		utils.NewUtilsFetcher(utils.JwtSecret),
+		utils.NewUtilsFetcher(utils.JwtKeys),
 	)
 	// requesTemporaySignUpDataの構造体を流用してデータ構造作成
 	data := models.RequestSignUpData{
@@ -536,16 +861,20 @@ func (af *apiSignDataFetcher) PostSignUpApi(c *gin.Context) {
 	}
 
 	// 情報は削除する
-	if err = af.RedisService.RedisDel(requestData.RedisKey); err != nil {
+	if err = af.RedisService.RedisDel(pendingSignUpKey(requestData.RedisKey)); err != nil {
 		response := utils.ErrorMessageResponse{
 			Result: err.Error(),
 		}
 		c.JSON(http.StatusInternalServerError, response)
 		return
 	}
+	// 認証済みの仮登録の失敗回数は不要(失敗しても登録は継続する)
+	_ = af.AttemptLimiter.Reset(codeKey)
 
+	sessionId := uuid.New().String()
 	// UtilsFetcher を使用してトークンを生成
-	newToken, err := af.UtilsFetcher.NewToken(userId, utils.AuthTokenHour)
+	// 登録したユーザーは一般ユーザー
+	newToken, err := af.UtilsFetcher.NewToken(userId, sessionId, enum.ROLE_USER, utils.AuthTokenHour)
 	if err != nil {
 		response := utils.ErrorMessageResponse{
 			Result: "新規トークンの生成に失敗しました。",
@@ -554,7 +883,7 @@ func (af *apiSignDataFetcher) PostSignUpApi(c *gin.Context) {
 		return
 	}
 
-	refreshToken, err := af.UtilsFetcher.RefreshToken(userId, utils.RefreshAuthTokenHour)
+	refreshToken, err := af.UtilsFetcher.RefreshToken(userId, sessionId, utils.RefreshAuthTokenHour)
 	if err != nil {
 		response := utils.ErrorMessageResponse{
 			Result: "リフレッシュトークンの生成に失敗しました。",
@@ -563,6 +892,14 @@ func (af *apiSignDataFetcher) PostSignUpApi(c *gin.Context) {
 		return
 	}
 
+	if err := recordSession(c, userId, sessionId, enum.SIGN_IN_PASSWORD); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "セッションの記録に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
 	c.SetCookie(utils.UserId, fmt.Sprintf("%d", userId), 0, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
 	c.SetCookie(utils.AuthToken, newToken, utils.AuthTokenHour*utils.SecondsInHour, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
 	c.SetCookie(utils.RefreshAuthToken, refreshToken, utils.RefreshAuthTokenHour*utils.SecondsInHour, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
@@ -600,6 +937,7 @@ func (af *apiSignDataFetcher) PostSignUpApi(c *gin.Context) {
 }
 
 // PutSignInEditApi はサインイン情報を編集API
+// サインイン中のユーザー本人のみ変更でき、現在のパスワードをユーザーIDの登録情報と照合する
 //
 // 引数:
 //   - c: Ginコンテキスト
@@ -607,7 +945,6 @@ func (af *apiSignDataFetcher) PostSignUpApi(c *gin.Context) {
 
 func (af *apiSignDataFetcher) PutSignInEditApi(c *gin.Context) {
 	var requestData models.RequestSignInEditData
-	var updateValue string
 	var result string
 	if err := c.ShouldBindJSON(&requestData); err != nil {
 		// エラーメッセージを出力して確認
@@ -621,9 +958,10 @@ func (af *apiSignDataFetcher) PutSignInEditApi(c *gin.Context) {
 	userIdCheck := common.AnyToStr(c.Param("user_id"))
 
 	validator := validation.RequestSignInEditData{
-		UserId:       userIdCheck,
-		UserEmail:    requestData.UserEmail,
-		UserPassword: requestData.UserPassword,
+		UserId:          userIdCheck,
+		UserEmail:       requestData.UserEmail,
+		UserPassword:    requestData.UserPassword,
+		CurrentPassword: requestData.CurrentPassword,
 	}
 
 	if valid, errMsgList := validator.Validate(); !valid {
@@ -634,12 +972,24 @@ func (af *apiSignDataFetcher) PutSignInEditApi(c *gin.Context) {
 		return
 	}
 
+	UserId, _ := af.CommonFetcher.StrToInt(userIdCheck)
+	if !isSignInUser(c, UserId) {
+		signInUserMismatch(c)
+		return
+	}
+
 	dbFetcher, _, _ := models.NewSignDataFetcher(
 		config.GetDataBaseSource(),
-		utils.NewUtilsFetcher(utils.JwtSecret),
+		utils.NewUtilsFetcher(utils.JwtKeys),
 	)
-	result, err := dbFetcher.PutCheck(requestData)
-	if err != nil {
+	result, err := dbFetcher.PutCheck(UserId, requestData)
+	if errors.Is(err, models.ErrCurrentPasswordMismatch) {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	} else if err != nil {
 		response := utils.ErrorMessageResponse{
 			Result: "更新チェックエラー",
 		}
@@ -647,8 +997,27 @@ func (af *apiSignDataFetcher) PutSignInEditApi(c *gin.Context) {
 		return
 	}
 
-	UserId, _ := af.CommonFetcher.StrToInt(userIdCheck)
-	if err := dbFetcher.PutSignInEdit(UserId, requestData); err != nil {
+	// メールアドレスの変更は新しいメールアドレスでの確認が必要なため、即時には変更しない
+	if result == "メールアドレス更新" {
+		response := utils.ErrorMessageResponse{
+			Result: "メールアドレスの変更は確認メールによる手続きが必要です。",
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := dbFetcher.PutSignInEdit(UserId, models.RequestSignInEditData{
+		UserPassword: requestData.UserPassword,
+	}); errors.Is(err, models.ErrPasswordReused) {
+		response := utils.ErrorValidationResponse{
+			Result: []utils.ErrorMessages{{
+				Field:   validation.UserPassword,
+				Message: utils.PasswordHistoryMessage("パスワード"),
+			}},
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	} else if err != nil {
 		response := utils.ErrorMessageResponse{
 			Result: "サインイン情報編集に失敗しました。",
 		}
@@ -656,15 +1025,29 @@ func (af *apiSignDataFetcher) PutSignInEditApi(c *gin.Context) {
 		return
 	}
 
-	if result == "メールアドレス更新" {
-		updateValue = requestData.UserEmail
-	} else {
-		updateValue = requestData.UserPassword
+	// パスワード変更時は発行済みの全てのトークンを失効させる
+	if err := af.UtilsFetcher.RevokeUserTokens(UserId); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "トークンの失効に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
 	}
 
+	// 全ての端末のセッションをサインアウト済みにする
+	sessionFetcher, _, _ := models.NewSessionDataFetcher(config.GetDataBaseSource())
+	if _, err := sessionFetcher.RevokeOtherSessions(UserId, ""); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "セッションの失効に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// 新しいパスワードはメールに記載しない
 	subject, body, err := af.EmailTemplateService.PostSignInEditTemplate(
 		result,
-		updateValue,
+		signInEditPasswordNotice,
 		af.UtilsFetcher.DateTimeStr(time.Now(), "2006年01月02日 15:04"),
 	)
 	if err != nil {
@@ -686,12 +1069,13 @@ func (af *apiSignDataFetcher) PutSignInEditApi(c *gin.Context) {
 
 	// サインイン編集の成功レスポンス
 	response := utils.ResponseData[string]{
-		Result: fmt.Sprintf("%s:%s", "サインイン編集に成功", updateValue),
+		Result: "サインイン編集に成功",
 	}
 	c.JSON(http.StatusOK, response)
 }
 
 // DeleteSignInApi はサインイン情報を削除API
+// ユーザーは退会状態にし、削除予定日時までは復元リンクから復元できる
 //
 // 引数:
 //   - c: Ginコンテキスト
@@ -726,11 +1110,24 @@ func (af *apiSignDataFetcher) DeleteSignInApi(c *gin.Context) {
 
 	dbFetcher, _, _ := models.NewSignDataFetcher(
 		config.GetDataBaseSource(),
-		utils.NewUtilsFetcher(utils.JwtSecret),
+		utils.NewUtilsFetcher(utils.JwtKeys),
 	)
 
 	UserId, _ := af.CommonFetcher.StrToInt(userIdCheck)
-	err := dbFetcher.DeleteSignIn(UserId, requestData)
+	now := time.Now()
+	purgeAt := accountPurgeAt(now)
+
+	// 退会後にメールで送信する復元用のトークンを先に発行する
+	restoreToken, err := issueAccountRestoreToken(af.RedisService, UserId, purgeAt)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "アカウント復元用のトークンの発行に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	err = dbFetcher.DeleteSignIn(UserId, requestData, purgeAt)
 	if err != nil {
 		response := utils.ErrorMessageResponse{
 			Result: "サインインの削除に失敗しました。",
@@ -739,15 +1136,27 @@ func (af *apiSignDataFetcher) DeleteSignInApi(c *gin.Context) {
 		return
 	}
 
+	// ユーザーに発行済みの全てのトークンを失効させる
+	if err := af.UtilsFetcher.RevokeUserTokens(UserId); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "トークンの失効に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	revokeCookieTokens(c, af.UtilsFetcher)
+
 	// Cookie無効化
 	c.SetCookie(utils.UserId, "", 0, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
 	c.SetCookie(utils.AuthToken, "", -1, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
 	c.SetCookie(utils.RefreshAuthToken, "", -1, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
 
-	subject, body, err := af.EmailTemplateService.DeleteSignInTemplate(
+	subject, body, err := af.EmailTemplateService.AccountDeactivatedTemplate(
 		requestData.DeleteName,
 		requestData.UserEmail,
-		af.UtilsFetcher.DateTimeStr(time.Now(), "2006年01月02日 15:04"),
+		accountRestoreLink(restoreToken),
+		af.UtilsFetcher.DateTimeStr(purgeAt, "2006年01月02日 15:04"),
+		af.UtilsFetcher.DateTimeStr(now, "2006年01月02日 15:04"),
 	)
 	if err != nil {
 		response := utils.ErrorMessageResponse{
@@ -795,6 +1204,18 @@ func (af *apiSignDataFetcher) SignOutApi(c *gin.Context) {
 		return
 	}
 
+	// サインアウトした端末のセッションをサインアウト済みにする
+	// 失効済み等でセッションが存在しない場合もあるため、記録に失敗してもサインアウトは継続する
+	if sessionId, userId, ok := cookieSession(c, af.UtilsFetcher); ok {
+		sessionFetcher, _, _ := models.NewSessionDataFetcher(config.GetDataBaseSource())
+		if err := sessionFetcher.RevokeSession(sessionId, userId); err != nil {
+			log.Printf("セッションの失効記録に失敗しました session_id=%s: %v", sessionId, err)
+		}
+	}
+
+	// サインアウトした端末のトークンを失効させる
+	revokeCookieTokens(c, af.UtilsFetcher)
+
 	// Cookie無効化
 	c.SetCookie(utils.UserId, "", 0, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
 	c.SetCookie(utils.AuthToken, "", -1, "/", config.GlobalEnv.Domain, config.GlobalEnv.Secure, config.GlobalEnv.HttpOnly)
@@ -851,7 +1272,7 @@ func (af *apiSignDataFetcher) RegisterEmailCheckNotice(c *gin.Context) {
 
 	dbFetcher, _, _ := models.NewSignDataFetcher(
 		config.GetDataBaseSource(),
-		utils.NewUtilsFetcher(utils.JwtSecret),
+		utils.NewUtilsFetcher(utils.JwtKeys),
 	)
 	// user_id取得
 	userId, err := dbFetcher.GetUserId(UserEmail)
@@ -863,7 +1284,14 @@ func (af *apiSignDataFetcher) RegisterEmailCheckNotice(c *gin.Context) {
 		return
 	}
 
-	tokenId := uuid.New().String() + common.AnyToStr(userId)
+	tokenId, err := issuePasswordResetToken(af.RedisService, userId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "パスワード再設定トークンの発行に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
 
 	var link string = fmt.Sprintf("%ssign_password_reset?token_id=%s", utils.GetBaseURL(), tokenId)
 
@@ -926,11 +1354,48 @@ func (af *apiSignDataFetcher) NewPasswordUpdate(c *gin.Context) {
 		return
 	}
 
-	dbFetcher, _, _ := models.NewSignDataFetcher(
+	// 入力ミスでトークンを消費しないよう、トークンの使用前に確認する
+	if requestData.NewUserPassword != requestData.ConfirmPassword {
+		response := utils.ErrorMessageResponse{
+			Result: "新しいパスワードと確認用のパスワードが一致しませんでした。",
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, err := af.passwordResetTokenUser(requestData.TokenId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	reusedResponse := utils.ErrorValidationResponse{
+		Result: []utils.ErrorMessages{{
+			Field:   validation.NewUserPassword,
+			Message: utils.PasswordHistoryMessage("新しいパスワード"),
+		}},
+	}
+
+	// 再設定のリンクから別のパスワードを指定できるよう、直近に使用したパスワードはトークンの使用前に確認する
+	historyFetcher, _, _ := models.NewSignDataFetcher(
 		config.GetDataBaseSource(),
-		utils.NewUtilsFetcher(utils.JwtSecret),
+		utils.NewUtilsFetcher(utils.JwtKeys),
 	)
-	userEmail, err := dbFetcher.NewPasswordUpdate(requestData)
+	if err := historyFetcher.CheckPasswordReuse(userId, requestData.NewUserPassword); errors.Is(err, models.ErrPasswordReused) {
+		c.JSON(http.StatusBadRequest, reusedResponse)
+		return
+	} else if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "パスワードの履歴の確認に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	userId, err = af.consumePasswordResetToken(requestData.TokenId)
 	if err != nil {
 		response := utils.ErrorMessageResponse{
 			Result: err.Error(),
@@ -939,8 +1404,42 @@ func (af *apiSignDataFetcher) NewPasswordUpdate(c *gin.Context) {
 		return
 	}
 
+	dbFetcher, _, _ := models.NewSignDataFetcher(
+		config.GetDataBaseSource(),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+	)
+	userEmail, err := dbFetcher.NewPasswordUpdate(userId, requestData)
+	if errors.Is(err, models.ErrPasswordReused) {
+		c.JSON(http.StatusBadRequest, reusedResponse)
+		return
+	} else if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// パスワード再発行前に発行済みの全てのトークンを失効させる
+	if err := af.UtilsFetcher.RevokeUserTokens(userId); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "トークンの失効に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// 全ての端末のセッションをサインアウト済みにする
+	sessionFetcher, _, _ := models.NewSessionDataFetcher(config.GetDataBaseSource())
+	if _, err := sessionFetcher.RevokeOtherSessions(userId, ""); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "セッションの失効に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
 	subject, body, err := af.EmailTemplateService.NewPasswordUpdateTemplate(
-		requestData.NewUserPassword,
 		af.UtilsFetcher.DateTimeStr(time.Now(), "2006年01月02日 15:04"),
 	)
 	if err != nil {
@@ -966,3 +1465,94 @@ func (af *apiSignDataFetcher) NewPasswordUpdate(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, response)
 }
+
+// passwordResetKey はパスワード再設定トークンのハッシュからユーザーIDを引くRedisのキー
+func passwordResetKey(tokenHash string) string {
+	return fmt.Sprintf("password_reset:%s", tokenHash)
+}
+
+// passwordResetUserKey はユーザーの有効なパスワード再設定トークンのハッシュを保存するRedisのキー
+func passwordResetUserKey(UserId int) string {
+	return fmt.Sprintf("password_reset_user:%d", UserId)
+}
+
+// issuePasswordResetToken はパスワード再設定トークンを発行し、ハッシュのみをRedisに保存する
+// 再発行した場合は以前のリンクを無効にする
+//
+// 引数:
+//   - redisService: トークンを保存するRedis
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: メールのリンクに含める平文のトークン
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func issuePasswordResetToken(redisService config.RedisService, UserId int) (string, error) {
+	token, err := utils.GenerateLinkToken()
+	if err != nil {
+		return "", err
+	}
+	tokenHash := utils.HashLinkToken(token)
+	duration := utils.PasswordResetTokenMinutes * time.Minute
+
+	if previousHash, err := redisService.RedisGet(passwordResetUserKey(UserId)); err == nil {
+		_ = redisService.RedisDel(passwordResetKey(previousHash))
+	}
+	if err := redisService.RedisSet(passwordResetKey(tokenHash), common.AnyToStr(UserId), duration); err != nil {
+		return "", err
+	}
+	if err := redisService.RedisSet(passwordResetUserKey(UserId), tokenHash, duration); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// passwordResetTokenUser はパスワード再設定トークンを使用済みにせずに、発行したユーザーIDを返す
+//
+// 引数:
+//   - token: メールのリンクに含めた平文のトークン
+//
+// 戻り値:
+//
+//	戻り値1: トークンを発行したユーザーID
+//	戻り値2: エラー内容(無効又は有効期限切れの場合はエラー)
+//
+
+func (af *apiSignDataFetcher) passwordResetTokenUser(token string) (int, error) {
+	value, err := af.RedisService.RedisGet(passwordResetKey(utils.HashLinkToken(token)))
+	if err != nil {
+		return 0, errors.New("パスワード再設定のリンクが無効か有効期限が切れています。")
+	}
+	userId, err := af.CommonFetcher.StrToInt(value)
+	if err != nil {
+		return 0, errors.New("パスワード再設定のリンクが無効か有効期限が切れています。")
+	}
+	return userId, nil
+}
+
+// consumePasswordResetToken はパスワード再設定トークンを検証し、使用済みにする
+// 取得と削除を同時に行い、同じトークンで2回以上再設定できないようにする
+//
+// 引数:
+//   - token: メールのリンクに含めた平文のトークン
+//
+// 戻り値:
+//
+//	戻り値1: トークンを発行したユーザーID
+//	戻り値2: エラー内容(無効又は有効期限切れの場合はエラー)
+//
+
+func (af *apiSignDataFetcher) consumePasswordResetToken(token string) (int, error) {
+	value, err := af.RedisService.RedisGetDel(passwordResetKey(utils.HashLinkToken(token)))
+	if err != nil {
+		return 0, errors.New("パスワード再設定のリンクが無効か有効期限が切れています。")
+	}
+	userId, err := af.CommonFetcher.StrToInt(value)
+	if err != nil {
+		return 0, errors.New("パスワード再設定のリンクが無効か有効期限が切れています。")
+	}
+	_ = af.RedisService.RedisDel(passwordResetUserKey(userId))
+	return userId, nil
+}
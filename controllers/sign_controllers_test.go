@@ -7,12 +7,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"time"
 
 	// "server/config"
 
 	"server/common"
 	"server/config"
+	"server/enum"
 	"server/models"
 	"server/templates"
 	"server/test_utils"
@@ -20,6 +22,7 @@ import (
 	"testing"
 
 	mock_config "server/mock/config"
+	mock_limiter "server/mock/limiter"
 	mock_templates "server/mock/templates"
 	mock_utils "server/mock/utils"
 
@@ -35,6 +38,15 @@ func TestPostSignInApi(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 
+	// セッションの記録はDBに接続しない
+	sessionPatches := patchInsertSession(nil)
+	defer sessionPatches.Reset()
+	rolePatches := patchGetUserRole(models.UserRoleData{Role: enum.ROLE_USER}, nil)
+	defer rolePatches.Reset()
+	// 二段階認証は無効
+	totpPatches := patchGetTotp(models.TotpData{}, nil)
+	defer totpPatches.Reset()
+
 	t.Run("TestPostSignInApi JSON不正", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -46,10 +58,11 @@ func TestPostSignInApi(t *testing.T) {
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignInApi(c)
 
@@ -88,10 +101,11 @@ func TestPostSignInApi(t *testing.T) {
 		defer patches.Reset()
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignInApi(c)
 
@@ -146,10 +160,11 @@ func TestPostSignInApi(t *testing.T) {
 		defer patches.Reset()
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignInApi(c)
 
@@ -172,14 +187,11 @@ func TestPostSignInApi(t *testing.T) {
 
 	t.Run("TestPostSignInApi バリデーション パスワード不正", func(t *testing.T) {
 
+		// ポリシー変更前のパスワードでもサインインできるよう、上限の文字数のみ確認する
 		dataList := []models.RequestSignInData{
 			{
 				UserEmail:    "test@example.com",
-				UserPassword: "Test12!",
-			},
-			{
-				UserEmail:    "test@example.com",
-				UserPassword: "Test123456",
+				UserPassword: strings.Repeat("Test12345!", 13),
 			},
 		}
 
@@ -206,10 +218,11 @@ func TestPostSignInApi(t *testing.T) {
 			defer patches.Reset()
 
 			fetcher := apiSignDataFetcher{
-				UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+				UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 				CommonFetcher:        common.NewCommonFetcher(),
 				EmailTemplateService: templates.NewEmailTemplateManager(),
 				RedisService:         config.NewRedisManager(),
+				AttemptLimiter:       allowAllAttempts(t),
 			}
 			fetcher.PostSignInApi(c)
 
@@ -261,7 +274,7 @@ func TestPostSignInApi(t *testing.T) {
 	// 	defer patches.Reset()
 
 	// 	fetcher := apiSignDataFetcher{
-	// 		UtilsFetcher:  utils.NewUtilsFetcher(utils.JwtSecret),
+	// 		UtilsFetcher:  utils.NewUtilsFetcher(utils.JwtKeys),
 	// 		CommonFetcher: common.NewCommonFetcher(),
 	// 			EmailTemplateService: templates.NewEmailTemplateManager(),
 	//		RedisService: config.NewRedisManager(),
@@ -317,11 +330,11 @@ func TestPostSignInApi(t *testing.T) {
 
 		// モックの挙動を定義
 		mockUtilsFetcher.EXPECT().
-			NewToken(gomock.Any(), gomock.Any()).
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("new_token", nil)
 
 		mockUtilsFetcher.EXPECT().
-			RefreshToken(gomock.Any(), gomock.Any()).
+			RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("refresh_token", nil)
 
 		mockUtilsFetcher.EXPECT().
@@ -337,6 +350,7 @@ func TestPostSignInApi(t *testing.T) {
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignInApi(c)
 
@@ -399,11 +413,11 @@ func TestPostSignInApi(t *testing.T) {
 
 		// モックの挙動を定義
 		mockUtilsFetcher.EXPECT().
-			NewToken(gomock.Any(), gomock.Any()).
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("new_token", nil)
 
 		mockUtilsFetcher.EXPECT().
-			RefreshToken(gomock.Any(), gomock.Any()).
+			RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("refresh_token", nil)
 
 		mockUtilsFetcher.EXPECT().
@@ -419,6 +433,7 @@ func TestPostSignInApi(t *testing.T) {
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: mockEmailTemplateService,
 			RedisService:         config.NewRedisManager(),
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignInApi(c)
 
@@ -473,11 +488,11 @@ func TestPostSignInApi(t *testing.T) {
 
 		// モックの挙動を定義
 		mockUtilsFetcher.EXPECT().
-			NewToken(gomock.Any(), gomock.Any()).
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("new_token", nil)
 
 		mockUtilsFetcher.EXPECT().
-			RefreshToken(gomock.Any(), gomock.Any()).
+			RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("refresh_token", nil)
 
 		mockUtilsFetcher.EXPECT().
@@ -493,6 +508,7 @@ func TestPostSignInApi(t *testing.T) {
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignInApi(c)
 
@@ -533,10 +549,11 @@ func TestPostSignInApi(t *testing.T) {
 		defer patches.Reset()
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignInApi(c)
 
@@ -573,7 +590,7 @@ func TestPostSignInApi(t *testing.T) {
 
 		// モックの挙動を定義
 		mockUtilsFetcher.EXPECT().
-			NewToken(gomock.Any(), gomock.Any()).
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("", fmt.Errorf("トークン生成エラー"))
 
 		w := httptest.NewRecorder()
@@ -598,6 +615,7 @@ func TestPostSignInApi(t *testing.T) {
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignInApi(c)
 
@@ -636,11 +654,11 @@ func TestPostSignInApi(t *testing.T) {
 
 		// モックの挙動を定義
 		mockUtilsFetcher.EXPECT().
-			NewToken(gomock.Any(), gomock.Any()).
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("token", nil)
 
 		mockUtilsFetcher.EXPECT().
-			RefreshToken(gomock.Any(), gomock.Any()).
+			RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("", fmt.Errorf("トークン生成エラー"))
 
 		w := httptest.NewRecorder()
@@ -665,6 +683,7 @@ func TestPostSignInApi(t *testing.T) {
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignInApi(c)
 
@@ -681,12 +700,185 @@ func TestPostSignInApi(t *testing.T) {
 		}
 		assert.Equal(t, responseBody, expectedErrorMessage)
 	})
+
+	t.Run("TestPostSignInApi セッションの記録に失敗", func(t *testing.T) {
+		data := models.RequestSignInData{
+			UserEmail:    "test@example.com",
+			UserPassword: "Test123456!!",
+		}
+
+		resMock := models.SignInData{
+			UserId:       3,
+			UserEmail:    "test@example.com",
+			UserPassword: "Test12345!",
+		}
+
+		// gomock のコントローラを作成
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// UtilsFetcher のモックを作成
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+
+		// モックの挙動を定義
+		mockUtilsFetcher.EXPECT().
+			NewToken(3, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("token", nil)
+
+		mockUtilsFetcher.EXPECT().
+			RefreshToken(3, gomock.Any(), gomock.Any()).
+			Return("refresh_token", nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		body, _ := json.Marshal(data)
+		c.Request = httptest.NewRequest("POST", "/api/signin", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		// GetSignIn のモック化
+		patches1 := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"GetSignIn",
+			func(_ *models.SignDataFetcher, data models.RequestSignInData) (models.SignInData, error) {
+				return resMock, nil
+			})
+		defer patches1.Reset()
+
+		patches2 := patchInsertSession(fmt.Errorf("クエリー実行エラー"))
+		defer patches2.Reset()
+
+		// モックを使って API を呼び出し
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mockUtilsFetcher,
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         config.NewRedisManager(),
+			AttemptLimiter:       allowAllAttempts(t),
+		}
+		fetcher.PostSignInApi(c)
+
+		// ステータスコードの確認
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		// クッキーは発行されない
+		assert.Empty(t, w.Result().Cookies())
+
+		// レスポンスボディの確認
+		var responseBody utils.ResponseData[string]
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+
+		expectedErrorMessage := utils.ResponseData[string]{
+			Result: "セッションの記録に失敗しました。",
+		}
+		assert.Equal(t, responseBody, expectedErrorMessage)
+	})
+}
+
+func TestGetRefreshTokenApiRole(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	refreshRequest := func(c *gin.Context, userId string) {
+		c.Request = httptest.NewRequest("GET", "/api/refresh_token?user_id="+userId, nil)
+		c.Request.AddCookie(&http.Cookie{
+			Name:  utils.RefreshAuthToken,
+			Value: "refresh_token",
+		})
+		c.Request.AddCookie(&http.Cookie{
+			Name:  utils.UserId,
+			Value: userId,
+		})
+	}
+
+	// 一般ユーザー(ID:1)のリフレッシュトークン
+	mockClaims := jwt.MapClaims{
+		"UserId": float64(1),
+		"typ":    utils.TokenTypeRefresh,
+		"aud":    utils.RefreshTokenAudience,
+		"iss":    utils.JwtIssuer,
+		"sid":    "session-id",
+		"exp":    float64(time.Now().Add(time.Hour).Unix()),
+	}
+
+	// patchRoles はユーザーIDごとのロールを返し、ロールを取得したユーザーIDを記録する
+	patchRoles := func(requested *[]int) *Patches {
+		return ApplyMethod(
+			reflect.TypeOf(&models.AdminDataFetcher{}),
+			"GetUserRole",
+			func(_ *models.AdminDataFetcher, UserId int) (models.UserRoleData, error) {
+				*requested = append(*requested, UserId)
+				if UserId == 2 {
+					return models.UserRoleData{Role: enum.ROLE_ADMIN}, nil
+				}
+				return models.UserRoleData{Role: enum.ROLE_USER}, nil
+			})
+	}
+
+	t.Run("TestGetRefreshTokenApi リフレッシュトークンのユーザーのロールで発行する", func(t *testing.T) {
+		var requested []int
+		patches := patchRoles(&requested)
+		defer patches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().ParseWithClaims(gomock.Any()).Return(&jwt.Token{Claims: mockClaims, Valid: true}, nil)
+		mockUtilsFetcher.EXPECT().MapClaims(gomock.Any()).Return(mockClaims, true)
+		mockUtilsFetcher.EXPECT().NewToken(1, "session-id", enum.ROLE_USER, utils.AuthTokenHour).Return("new_token", nil)
+		mockUtilsFetcher.EXPECT().RotateRefreshToken(mockClaims, utils.RefreshAuthTokenHour).Return("new_refresh_token", nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		refreshRequest(c, "1")
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:  mockUtilsFetcher,
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetRefreshTokenApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []int{1}, requested)
+	})
+
+	t.Run("TestGetRefreshTokenApi 管理者のIDを指定しても管理者のロールを取得しない", func(t *testing.T) {
+		var requested []int
+		patches := patchRoles(&requested)
+		defer patches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// トークンは発行しない
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().ParseWithClaims(gomock.Any()).Return(&jwt.Token{Claims: mockClaims, Valid: true}, nil)
+		mockUtilsFetcher.EXPECT().MapClaims(gomock.Any()).Return(mockClaims, true)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		refreshRequest(c, "2")
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:  mockUtilsFetcher,
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetRefreshTokenApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Empty(t, requested)
+	})
 }
 
 func TestGetRefreshTokenApi(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 
+	// ロールの取得はDBに接続しない
+	rolePatches := patchGetUserRole(models.UserRoleData{Role: enum.ROLE_USER}, nil)
+	defer rolePatches.Reset()
+
 	t.Run("TestGetRefreshTokenApi JSON不正", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -697,7 +889,7 @@ func TestGetRefreshTokenApi(t *testing.T) {
 
 		// UtilsFetcher のモックを使ってAPIを呼び出し
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -733,7 +925,7 @@ func TestGetRefreshTokenApi(t *testing.T) {
 
 		// UtilsFetcher のモックを使ってAPIを呼び出し
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -769,7 +961,7 @@ func TestGetRefreshTokenApi(t *testing.T) {
 
 		// UtilsFetcher のモックを使ってAPIを呼び出し
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -811,7 +1003,7 @@ func TestGetRefreshTokenApi(t *testing.T) {
 
 			// UtilsFetcher のモックを使ってAPIを呼び出し
 			fetcher := apiSignDataFetcher{
-				UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+				UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 				CommonFetcher:        common.NewCommonFetcher(),
 				EmailTemplateService: templates.NewEmailTemplateManager(),
 				RedisService:         config.NewRedisManager(),
@@ -857,7 +1049,7 @@ func TestGetRefreshTokenApi(t *testing.T) {
 
 		// モックを使ってAPIを呼び出し
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -898,7 +1090,7 @@ func TestGetRefreshTokenApi(t *testing.T) {
 
 			// モックを使ってAPIを呼び出し
 			fetcher := apiSignDataFetcher{
-				UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+				UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 				CommonFetcher:        common.NewCommonFetcher(),
 				EmailTemplateService: templates.NewEmailTemplateManager(),
 				RedisService:         config.NewRedisManager(),
@@ -939,7 +1131,7 @@ func TestGetRefreshTokenApi(t *testing.T) {
 
 			// モックを使ってAPIを呼び出し
 			fetcher := apiSignDataFetcher{
-				UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+				UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 				CommonFetcher:        common.NewCommonFetcher(),
 				EmailTemplateService: templates.NewEmailTemplateManager(),
 				RedisService:         config.NewRedisManager(),
@@ -1029,6 +1221,9 @@ func TestGetRefreshTokenApi(t *testing.T) {
 		// モックの MapClaims を定義
 		mockClaims := jwt.MapClaims{
 			"UserId": float64(1),
+			"typ":    utils.TokenTypeRefresh,
+			"aud":    utils.RefreshTokenAudience,
+			"iss":    utils.JwtIssuer,
 			"exp":    float64(time.Now().Add(time.Hour).Unix()),
 		}
 
@@ -1105,6 +1300,9 @@ func TestGetRefreshTokenApi(t *testing.T) {
 		// モックの MapClaims を定義
 		mockClaims := jwt.MapClaims{
 			"UserId": float64(1),
+			"typ":    utils.TokenTypeRefresh,
+			"aud":    utils.RefreshTokenAudience,
+			"iss":    utils.JwtIssuer,
 			"exp":    float64(time.Now().Add(time.Hour).Unix()),
 		}
 
@@ -1126,7 +1324,7 @@ func TestGetRefreshTokenApi(t *testing.T) {
 			Return(mockClaims, true)
 
 		mockUtilsFetcher.EXPECT().
-			NewToken(gomock.Any(), gomock.Any()).
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("", fmt.Errorf("トークン生成エラー"))
 
 		w := httptest.NewRecorder()
@@ -1186,6 +1384,9 @@ func TestGetRefreshTokenApi(t *testing.T) {
 		// モックの MapClaims を定義
 		mockClaims := jwt.MapClaims{
 			"UserId": float64(1),
+			"typ":    utils.TokenTypeRefresh,
+			"aud":    utils.RefreshTokenAudience,
+			"iss":    utils.JwtIssuer,
 			"exp":    float64(time.Now().Add(time.Hour).Unix()),
 		}
 
@@ -1206,10 +1407,15 @@ func TestGetRefreshTokenApi(t *testing.T) {
 			MapClaims(gomock.Any()).
 			Return(mockClaims, true)
 
+		// アクセストークンはアクセストークンの有効期限で発行する
 		mockUtilsFetcher.EXPECT().
-			NewToken(gomock.Any(), gomock.Any()).
+			NewToken(1, gomock.Any(), gomock.Any(), utils.AuthTokenHour).
 			Return("new_token", nil)
 
+		mockUtilsFetcher.EXPECT().
+			RotateRefreshToken(mockClaims, utils.RefreshAuthTokenHour).
+			Return("new_refresh_token", nil)
+
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
@@ -1253,133 +1459,475 @@ func TestGetRefreshTokenApi(t *testing.T) {
 			Result: "新しいアクセストークンが発行されました。",
 		}
 		assert.Equal(t, responseBody.Result, expectedOK.Result)
+
+		// リフレッシュトークンもローテーションされていること
+		var refreshCookie *http.Cookie
+		for _, cookie := range w.Result().Cookies() {
+			if cookie.Name == utils.RefreshAuthToken {
+				refreshCookie = cookie
+			}
+		}
+		assert.NotNil(t, refreshCookie)
+		assert.Equal(t, "new_refresh_token", refreshCookie.Value)
 	})
-}
 
-func TestTemporaryPostSignUpApi(t *testing.T) {
+	t.Run("TestGetRefreshTokenApi 他のユーザーのIDを指定した", func(t *testing.T) {
 
-	gin.SetMode(gin.TestMode)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
 
-	var UserEmail string = "test@example.com"
-	var UserPassword string = "Test12345!"
-	var UserName string = "test!"
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
 
-	t.Run("TemporaryPostSignUpApi JSON不正", func(t *testing.T) {
+		// サインインしているユーザー(ID:1)のリフレッシュトークン
+		mockClaims := jwt.MapClaims{
+			"UserId": float64(1),
+			"typ":    utils.TokenTypeRefresh,
+			"aud":    utils.RefreshTokenAudience,
+			"iss":    utils.JwtIssuer,
+			"exp":    float64(time.Now().Add(time.Hour).Unix()),
+		}
+
+		mockUtilsFetcher.EXPECT().
+			ParseWithClaims(gomock.Any()).
+			Return(&jwt.Token{Claims: mockClaims, Valid: true}, nil)
+
+		mockUtilsFetcher.EXPECT().
+			MapClaims(gomock.Any()).
+			Return(mockClaims, true)
+
+		// トークンの発行・ローテーションは行わない
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
-		// Invalid JSON
-		invalidJSON := `{"data": [`
-
-		c.Request = httptest.NewRequest("POST", "/api/temporary_signup", bytes.NewBufferString(invalidJSON))
-		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request = httptest.NewRequest("GET", "/api/refresh_token?user_id=2", nil)
+		c.Request.AddCookie(&http.Cookie{
+			Name:  utils.RefreshAuthToken,
+			Value: "refresh_token",
+		})
+		c.Request.AddCookie(&http.Cookie{
+			Name:  utils.UserId,
+			Value: "2",
+		})
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         mockUtilsFetcher,
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
 		}
-		fetcher.TemporaryPostSignUpApi(c)
+		fetcher.GetRefreshTokenApi(c)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
-		var response utils.ErrorMessageResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.Contains(t, response.Result, "unexpected EOF")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assertErrorMessage(t, w, "サインインユーザーが異なっています。")
+		for _, cookie := range w.Result().Cookies() {
+			assert.NotEqual(t, utils.AuthToken, cookie.Name)
+		}
 	})
 
-	t.Run("TemporaryPostSignUpApi バリデーション 必須", func(t *testing.T) {
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
+	t.Run("TestGetRefreshTokenApi アクセストークンが提示された", func(t *testing.T) {
 
-		data := models.RequestSignUpData{
-			UserEmail:    "",
-			UserPassword: "",
-			UserName:     "",
-		}
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
 
-		body, _ := json.Marshal(data)
-		c.Request = httptest.NewRequest("POST", "/api/temporary_signup", bytes.NewBuffer(body))
-		c.Request.Header.Set("Content-Type", "application/json")
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
 
-		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
-			CommonFetcher:        common.NewCommonFetcher(),
-			EmailTemplateService: templates.NewEmailTemplateManager(),
-			RedisService:         config.NewRedisManager(),
+		mockClaims := jwt.MapClaims{
+			"UserId": float64(1),
+			"typ":    utils.TokenTypeAccess,
+			"aud":    utils.AccessTokenAudience,
+			"iss":    utils.JwtIssuer,
+			"exp":    float64(time.Now().Add(time.Hour).Unix()),
 		}
-		fetcher.TemporaryPostSignUpApi(c)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
-
-		var responseBody utils.ErrorValidationResponse
-		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
-		assert.NoError(t, err)
+		mockUtilsFetcher.EXPECT().
+			ParseWithClaims(gomock.Any()).
+			Return(&jwt.Token{Claims: mockClaims, Valid: true}, nil)
 
-		expectedErrorMessage := utils.ErrorValidationResponse{
-			Result: []utils.ErrorMessages{
-				{
-					Field:   "user_email",
-					Message: "メールアドレスは必須です。",
-				},
-				{
-					Field:   "user_password",
-					Message: "パスワードは必須です。",
-				},
-				{
-					Field:   "user_name",
-					Message: "ユーザー名は必須です。",
-				},
-			},
-		}
-		test_utils.SortErrorMessages(responseBody.Result)
-		test_utils.SortErrorMessages(expectedErrorMessage.Result)
-		assert.Equal(t, responseBody, expectedErrorMessage)
-	})
+		mockUtilsFetcher.EXPECT().
+			MapClaims(gomock.Any()).
+			Return(mockClaims, true)
 
-	t.Run("TemporaryPostSignUpApi バリデーション メールアドレス不正", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
-		data := models.RequestSignUpData{
-			UserEmail:    "test@example",
-			UserPassword: UserPassword,
-			UserName:     UserName,
-		}
-
-		body, _ := json.Marshal(data)
-		c.Request = httptest.NewRequest("POST", "/api/temporary_signup", bytes.NewBuffer(body))
-		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request = httptest.NewRequest("GET", "/api/refresh_token?user_id=1", nil)
+		c.Request.AddCookie(&http.Cookie{
+			Name:  utils.RefreshAuthToken,
+			Value: "access_token",
+		})
+		c.Request.AddCookie(&http.Cookie{
+			Name:  utils.UserId,
+			Value: "1",
+		})
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         mockUtilsFetcher,
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
 		}
-		fetcher.TemporaryPostSignUpApi(c)
+		fetcher.GetRefreshTokenApi(c)
 
-		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
 
-		var responseBody utils.ErrorValidationResponse
+		var responseBody utils.ResponseData[string]
 		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
 		assert.NoError(t, err)
-
-		expectedErrorMessage := utils.ErrorValidationResponse{
-			Result: []utils.ErrorMessages{
-				{
-					Field:   "user_email",
-					Message: "正しいメールアドレス形式である必要があります。",
-				},
-			},
-		}
-		assert.Equal(t, responseBody, expectedErrorMessage)
+		assert.Equal(t, "無効なリフレッシュトークン。", responseBody.Result)
 	})
 
-	t.Run("TemporaryPostSignUpApi バリデーション パスワード不正", func(t *testing.T) {
-
-		dataList := []models.RequestSignUpData{
+	t.Run("TestGetRefreshTokenApi 使用済みのリフレッシュトークン", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
+
+		mockClaims := jwt.MapClaims{
+			"UserId": float64(1),
+			"typ":    utils.TokenTypeRefresh,
+			"aud":    utils.RefreshTokenAudience,
+			"iss":    utils.JwtIssuer,
+			"sid":    "session-1",
+			"fid":    "family",
+			"jti":    "used",
+			"exp":    float64(time.Now().Add(time.Hour).Unix()),
+		}
+
+		mockUtilsFetcher.EXPECT().
+			ParseWithClaims(gomock.Any()).
+			Return(&jwt.Token{Claims: mockClaims, Valid: true}, nil)
+
+		mockUtilsFetcher.EXPECT().
+			MapClaims(gomock.Any()).
+			Return(mockClaims, true)
+
+		mockUtilsFetcher.EXPECT().
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("new_token", nil)
+
+		mockUtilsFetcher.EXPECT().
+			RotateRefreshToken(mockClaims, utils.RefreshAuthTokenHour).
+			Return("", utils.ErrRefreshTokenReused)
+
+		// 漏洩したトークンのセッションを失効させること
+		mockUtilsFetcher.EXPECT().
+			RevokeSession("session-1").
+			Return(nil)
+
+		mockUtilsFetcher.EXPECT().
+			DateTimeStr(gomock.Any(), gomock.Any()).
+			Return("2024年12月07日 20:00")
+
+		mockEmailTemplateService.EXPECT().
+			RefreshTokenReuseTemplate("test@example.com", "2024年12月07日 20:00").
+			Return("件名", "本文", nil)
+
+		// 再利用の検知をユーザーにメールで通知すること
+		mockUtilsFetcher.EXPECT().
+			SendMail("test@example.com", "件名", "本文", true).
+			Return(nil)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"GetUserEmail",
+			func(_ *models.SignDataFetcher, userId int) (string, error) {
+				return "test@example.com", nil
+			})
+		defer patches.Reset()
+
+		var revokedSessionId string
+		var revokedUserId int
+		sessionPatches := ApplyMethod(
+			reflect.TypeOf(&models.SessionDataFetcher{}),
+			"RevokeSession",
+			func(_ *models.SessionDataFetcher, SessionId string, UserId int) error {
+				revokedSessionId = SessionId
+				revokedUserId = UserId
+				return nil
+			})
+		defer sessionPatches.Reset()
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request = httptest.NewRequest("GET", "/api/refresh_token?user_id=1", nil)
+		c.Request.AddCookie(&http.Cookie{
+			Name:  utils.RefreshAuthToken,
+			Value: "used_token",
+		})
+		c.Request.AddCookie(&http.Cookie{
+			Name:  utils.UserId,
+			Value: "1",
+		})
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mockUtilsFetcher,
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: mockEmailTemplateService,
+			RedisService:         config.NewRedisManager(),
+		}
+		fetcher.GetRefreshTokenApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var responseBody utils.ResponseData[string]
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+		assert.Equal(t, "使用済みのリフレッシュトークンが提示されたため、全てのサインインを無効にしました。再ログインしてください。", responseBody.Result)
+		assert.Equal(t, "session-1", revokedSessionId)
+		assert.Equal(t, 1, revokedUserId)
+	})
+
+	t.Run("TestGetRefreshTokenApi 使用済みのリフレッシュトークン セッションの失効に失敗", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+
+		mockClaims := jwt.MapClaims{
+			"UserId": float64(1),
+			"typ":    utils.TokenTypeRefresh,
+			"aud":    utils.RefreshTokenAudience,
+			"iss":    utils.JwtIssuer,
+			"sid":    "session-1",
+			"fid":    "family",
+			"jti":    "used",
+			"exp":    float64(time.Now().Add(time.Hour).Unix()),
+		}
+
+		mockUtilsFetcher.EXPECT().
+			ParseWithClaims(gomock.Any()).
+			Return(&jwt.Token{Claims: mockClaims, Valid: true}, nil)
+
+		mockUtilsFetcher.EXPECT().
+			MapClaims(gomock.Any()).
+			Return(mockClaims, true)
+
+		mockUtilsFetcher.EXPECT().
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("new_token", nil)
+
+		mockUtilsFetcher.EXPECT().
+			RotateRefreshToken(mockClaims, utils.RefreshAuthTokenHour).
+			Return("", utils.ErrRefreshTokenReused)
+
+		mockUtilsFetcher.EXPECT().
+			RevokeSession("session-1").
+			Return(fmt.Errorf("Redisエラー"))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request = httptest.NewRequest("GET", "/api/refresh_token?user_id=1", nil)
+		c.Request.AddCookie(&http.Cookie{
+			Name:  utils.RefreshAuthToken,
+			Value: "used_token",
+		})
+		c.Request.AddCookie(&http.Cookie{
+			Name:  utils.UserId,
+			Value: "1",
+		})
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mockUtilsFetcher,
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         config.NewRedisManager(),
+		}
+		fetcher.GetRefreshTokenApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assertErrorMessage(t, w, "セッションの失効に失敗しました。")
+	})
+
+	t.Run("TestGetRefreshTokenApi 失効済みのリフレッシュトークン", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+
+		mockClaims := jwt.MapClaims{
+			"UserId": float64(1),
+			"typ":    utils.TokenTypeRefresh,
+			"aud":    utils.RefreshTokenAudience,
+			"iss":    utils.JwtIssuer,
+			"exp":    float64(time.Now().Add(time.Hour).Unix()),
+		}
+
+		mockUtilsFetcher.EXPECT().
+			ParseWithClaims(gomock.Any()).
+			Return(&jwt.Token{Claims: mockClaims, Valid: true}, nil)
+
+		mockUtilsFetcher.EXPECT().
+			MapClaims(gomock.Any()).
+			Return(mockClaims, true)
+
+		mockUtilsFetcher.EXPECT().
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return("new_token", nil)
+
+		mockUtilsFetcher.EXPECT().
+			RotateRefreshToken(mockClaims, utils.RefreshAuthTokenHour).
+			Return("", utils.ErrRefreshTokenInvalid)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request = httptest.NewRequest("GET", "/api/refresh_token?user_id=1", nil)
+		c.Request.AddCookie(&http.Cookie{
+			Name:  utils.RefreshAuthToken,
+			Value: "revoked_token",
+		})
+		c.Request.AddCookie(&http.Cookie{
+			Name:  utils.UserId,
+			Value: "1",
+		})
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mockUtilsFetcher,
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         config.NewRedisManager(),
+		}
+		fetcher.GetRefreshTokenApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var responseBody utils.ResponseData[string]
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+		assert.Equal(t, "リフレッシュトークンが無効です。再ログインしてください。", responseBody.Result)
+	})
+}
+
+func TestTemporaryPostSignUpApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	var UserEmail string = "test@example.com"
+	var UserPassword string = "Test12345!"
+	var UserName string = "test!"
+
+	t.Run("TemporaryPostSignUpApi JSON不正", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		// Invalid JSON
+		invalidJSON := `{"data": [`
+
+		c.Request = httptest.NewRequest("POST", "/api/temporary_signup", bytes.NewBufferString(invalidJSON))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         config.NewRedisManager(),
+		}
+		fetcher.TemporaryPostSignUpApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Contains(t, response.Result, "unexpected EOF")
+	})
+
+	t.Run("TemporaryPostSignUpApi バリデーション 必須", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		data := models.RequestSignUpData{
+			UserEmail:    "",
+			UserPassword: "",
+			UserName:     "",
+		}
+
+		body, _ := json.Marshal(data)
+		c.Request = httptest.NewRequest("POST", "/api/temporary_signup", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         config.NewRedisManager(),
+		}
+		fetcher.TemporaryPostSignUpApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var responseBody utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+
+		expectedErrorMessage := utils.ErrorValidationResponse{
+			Result: []utils.ErrorMessages{
+				{
+					Field:   "user_email",
+					Message: "メールアドレスは必須です。",
+				},
+				{
+					Field:   "user_password",
+					Message: "パスワードは必須です。",
+				},
+				{
+					Field:   "user_name",
+					Message: "ユーザー名は必須です。",
+				},
+			},
+		}
+		test_utils.SortErrorMessages(responseBody.Result)
+		test_utils.SortErrorMessages(expectedErrorMessage.Result)
+		assert.Equal(t, responseBody, expectedErrorMessage)
+	})
+
+	t.Run("TemporaryPostSignUpApi バリデーション メールアドレス不正", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		data := models.RequestSignUpData{
+			UserEmail:    "test@example",
+			UserPassword: UserPassword,
+			UserName:     UserName,
+		}
+
+		body, _ := json.Marshal(data)
+		c.Request = httptest.NewRequest("POST", "/api/temporary_signup", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         config.NewRedisManager(),
+		}
+		fetcher.TemporaryPostSignUpApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var responseBody utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+
+		expectedErrorMessage := utils.ErrorValidationResponse{
+			Result: []utils.ErrorMessages{
+				{
+					Field:   "user_email",
+					Message: "正しいメールアドレス形式である必要があります。",
+				},
+			},
+		}
+		assert.Equal(t, responseBody, expectedErrorMessage)
+	})
+
+	t.Run("TemporaryPostSignUpApi バリデーション パスワード不正", func(t *testing.T) {
+
+		dataList := []models.RequestSignUpData{
 
 			{
 				UserEmail:    UserEmail,
@@ -1393,7 +1941,13 @@ func TestTemporaryPostSignUpApi(t *testing.T) {
 			},
 		}
 
-		for _, data := range dataList {
+		// 満たしていないポリシーごとに理由を返す
+		expectedMessages := []string{
+			"パスワードは8文字以上で入力してください。",
+			"パスワードには記号を1文字以上含めてください。",
+		}
+
+		for i, data := range dataList {
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
 
@@ -1402,7 +1956,7 @@ func TestTemporaryPostSignUpApi(t *testing.T) {
 			c.Request.Header.Set("Content-Type", "application/json")
 
 			fetcher := apiSignDataFetcher{
-				UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+				UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 				CommonFetcher:        common.NewCommonFetcher(),
 				EmailTemplateService: templates.NewEmailTemplateManager(),
 				RedisService:         config.NewRedisManager(),
@@ -1419,7 +1973,7 @@ func TestTemporaryPostSignUpApi(t *testing.T) {
 				Result: []utils.ErrorMessages{
 					{
 						Field:   "user_password",
-						Message: "パスワードの形式が間違っています。",
+						Message: expectedMessages[i],
 					},
 				},
 			}
@@ -1613,16 +2167,26 @@ func TestTemporaryPostSignUpApi(t *testing.T) {
 			EncryptPassword(gomock.Any()).
 			Return("hashPassword", nil)
 
+		var savedKey string
+		var saved pendingSignUp
 		mockRedisService.EXPECT().
-			RedisSet(gomock.Any(), gomock.Any(), gomock.Any()).
-			Return(nil)
+			RedisSet(gomock.Any(), gomock.Any(), time.Hour).
+			DoAndReturn(func(key string, value interface{}, duration time.Duration) error {
+				savedKey = key
+				saved = value.(pendingSignUp)
+				return nil
+			})
 
+		var mailedCode string
 		mockEmailTemplateService.EXPECT().
-			TemporayPostSignUpTemplate(gomock.Any(), gomock.Any()).
-			Return("件名", "本文", nil)
+			TemporayPostSignUpTemplate(UserName, gomock.Any()).
+			DoAndReturn(func(userName, confirmCode string) (string, string, error) {
+				mailedCode = confirmCode
+				return "件名", "本文", nil
+			})
 
 		mockUtilsFetcher.EXPECT().
-			SendMail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			SendMail(UserEmail, gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(nil)
 
 		data := models.RequestSignUpData{
@@ -1654,15 +2218,22 @@ func TestTemporaryPostSignUpApi(t *testing.T) {
 
 		expectedOk := utils.ResponseData[TemporayPostSignUpResult]{
 			Result: TemporayPostSignUpResult{
-				RedisKey:  "9355:71eb75e7-79b8-40d1-b581-d819d8470239",
+				RedisKey:  "71eb75e7-79b8-40d1-b581-d819d8470239",
 				UserEmail: UserEmail,
 				UserName:  UserName,
 			},
 		}
-		// RedisKeyは認証コード:uuidが毎回変わるので文字数でチェック
-		assert.Equal(t, len(responseBody.Result.RedisKey), 41)
+		// RedisKeyは仮登録ID(uuid)が毎回変わるので文字数でチェック
+		assert.Equal(t, len(responseBody.Result.RedisKey), 36)
 		assert.Equal(t, responseBody.Result.UserEmail, expectedOk.Result.UserEmail)
 		assert.Equal(t, responseBody.Result.UserName, expectedOk.Result.UserName)
+
+		// 認証コードはメールとRedisの値にのみ含め、キー・レスポンスには含めない
+		assert.Equal(t, pendingSignUpKey(responseBody.Result.RedisKey), savedKey)
+		assert.Len(t, saved.ConfirmCode, 4)
+		assert.Equal(t, mailedCode, saved.ConfirmCode)
+		assert.Equal(t, "hashPassword", saved.UserPassword)
+		assert.NotContains(t, w.Body.String(), saved.ConfirmCode)
 	})
 }
 
@@ -1670,7 +2241,7 @@ func TestRetryAuthEmail(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 
-	var redisKey string = "5492:672e1fed-0d7e-4735-a91c-f25dc6992eae"
+	var redisKey string = "672e1fed-0d7e-4735-a91c-f25dc6992eae"
 	var UserEmail string = "test@example.com"
 	var UserName string = "test"
 
@@ -1689,7 +2260,7 @@ func TestRetryAuthEmail(t *testing.T) {
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -1738,7 +2309,7 @@ func TestRetryAuthEmail(t *testing.T) {
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -1774,7 +2345,7 @@ func TestRetryAuthEmail(t *testing.T) {
 		// モックの挙動を定義
 		mockRedisService.EXPECT().
 			RedisGet(gomock.Any()).
-			Return("test@example.com,Test12345!,test", fmt.Errorf("redis取得エラー"))
+			Return(pendingSignUpValue("test@example.com", "Test12345!", "test"), fmt.Errorf("redis取得エラー"))
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -1790,7 +2361,7 @@ func TestRetryAuthEmail(t *testing.T) {
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         mockRedisService,
@@ -1821,7 +2392,7 @@ func TestRetryAuthEmail(t *testing.T) {
 		// モックの挙動を定義
 		mockRedisService.EXPECT().
 			RedisGet(gomock.Any()).
-			Return("test@example.com,Test12345!,test", nil)
+			Return(pendingSignUpValue("test@example.com", "Test12345!", "test"), nil)
 
 		mockRedisService.EXPECT().
 			RedisSet(gomock.Any(), gomock.Any(), gomock.Any()).
@@ -1841,10 +2412,11 @@ func TestRetryAuthEmail(t *testing.T) {
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.RetryAuthEmail(c)
 
@@ -1872,7 +2444,7 @@ func TestRetryAuthEmail(t *testing.T) {
 		// モックの挙動を定義
 		mockRedisService.EXPECT().
 			RedisGet(gomock.Any()).
-			Return("test@example.com,Test12345!,test", nil)
+			Return(pendingSignUpValue("test@example.com", "Test12345!", "test"), nil)
 
 		mockRedisService.EXPECT().
 			RedisSet(gomock.Any(), gomock.Any(), gomock.Any()).
@@ -1896,10 +2468,11 @@ func TestRetryAuthEmail(t *testing.T) {
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.RetryAuthEmail(c)
 
@@ -1928,7 +2501,7 @@ func TestRetryAuthEmail(t *testing.T) {
 		// モックの挙動を定義
 		mockRedisService.EXPECT().
 			RedisGet(gomock.Any()).
-			Return("test@example.com,Test12345!,test", nil)
+			Return(pendingSignUpValue("test@example.com", "Test12345!", "test"), nil)
 
 		mockRedisService.EXPECT().
 			RedisSet(gomock.Any(), gomock.Any(), gomock.Any()).
@@ -1956,10 +2529,11 @@ func TestRetryAuthEmail(t *testing.T) {
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: mockEmailTemplateService,
 			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.RetryAuthEmail(c)
 
@@ -1989,7 +2563,7 @@ func TestRetryAuthEmail(t *testing.T) {
 		// モックの挙動を定義
 		mockRedisService.EXPECT().
 			RedisGet(gomock.Any()).
-			Return("test@example.com,Test12345!,test", nil)
+			Return(pendingSignUpValue("test@example.com", "Test12345!", "test"), nil)
 
 		mockRedisService.EXPECT().
 			RedisSet(gomock.Any(), gomock.Any(), gomock.Any()).
@@ -2025,6 +2599,7 @@ func TestRetryAuthEmail(t *testing.T) {
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: mockEmailTemplateService,
 			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.RetryAuthEmail(c)
 
@@ -2053,23 +2628,34 @@ func TestRetryAuthEmail(t *testing.T) {
 
 		// モックの挙動を定義
 		mockRedisService.EXPECT().
-			RedisGet(gomock.Any()).
-			Return("test@example.com,Test12345!,test", nil)
+			RedisGet(pendingSignUpKey(redisKey)).
+			Return(pendingSignUpValue("test@example.com", "Test12345!", "test"), nil)
 
+		var savedKey string
+		var saved pendingSignUp
 		mockRedisService.EXPECT().
-			RedisSet(gomock.Any(), gomock.Any(), gomock.Any()).
-			Return(nil)
+			RedisSet(gomock.Any(), gomock.Any(), time.Hour).
+			DoAndReturn(func(key string, value interface{}, duration time.Duration) error {
+				savedKey = key
+				saved = value.(pendingSignUp)
+				return nil
+			})
 
 		mockRedisService.EXPECT().
-			RedisDel(gomock.Any()).
+			RedisDel(pendingSignUpKey(redisKey)).
 			Return(nil)
 
+		var mailedCode string
 		mockEmailTemplateService.EXPECT().
-			TemporayPostSignUpTemplate(gomock.Any(), gomock.Any()).
-			Return("件名", "本文", nil)
+			TemporayPostSignUpTemplate("test", gomock.Any()).
+			DoAndReturn(func(userName, confirmCode string) (string, string, error) {
+				mailedCode = confirmCode
+				return "件名", "本文", nil
+			})
 
+		// 認証コードは仮登録したメールアドレスに送信する
 		mockUtilsFetcher.EXPECT().
-			SendMail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			SendMail("test@example.com", gomock.Any(), gomock.Any(), gomock.Any()).
 			Return(nil)
 
 		w := httptest.NewRecorder()
@@ -2085,11 +2671,20 @@ func TestRetryAuthEmail(t *testing.T) {
 		c.Request = httptest.NewRequest("GET", url, nil)
 		c.Request.Header.Set("Content-Type", "application/json")
 
+		// 仮登録したメールアドレス・IPアドレス毎に送信の度に数える
+		accountKey := utils.AuthEmailRetryAccountAttemptKey("test@example.com")
+		ipKey := utils.AuthEmailRetryIPAttemptKey("192.0.2.1")
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(accountKey, ipKey).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().Fail(accountKey).Return(utils.AttemptStatus{Failures: 1}, nil)
+		mockAttemptLimiter.EXPECT().Fail(ipKey).Return(utils.AttemptStatus{Failures: 1}, nil)
+
 		fetcher := apiSignDataFetcher{
 			UtilsFetcher:         mockUtilsFetcher,
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: mockEmailTemplateService,
 			RedisService:         mockRedisService,
+			AttemptLimiter:       mockAttemptLimiter,
 		}
 		fetcher.RetryAuthEmail(c)
 
@@ -2106,19 +2701,86 @@ func TestRetryAuthEmail(t *testing.T) {
 				UserName:  UserName,
 			},
 		}
-		// redisKeyだけ認証コード:uuidなので文字数で検証
-		assert.Equal(t, len(responseBody.Result.RedisKey), 41)
+		// redisKeyは新しい仮登録ID(uuid)なので文字数で検証
+		assert.Equal(t, len(responseBody.Result.RedisKey), 36)
 		assert.Equal(t, responseBody.Result.UserEmail, expectedOk.Result.UserEmail)
 		assert.Equal(t, responseBody.Result.UserName, expectedOk.Result.UserName)
+
+		// 新しい認証コードで保存し直す
+		assert.Equal(t, pendingSignUpKey(responseBody.Result.RedisKey), savedKey)
+		assert.Equal(t, mailedCode, saved.ConfirmCode)
+		assert.Equal(t, "test@example.com", saved.UserEmail)
+		assert.NotContains(t, w.Body.String(), saved.ConfirmCode)
+	})
+
+	t.Run("RetryAuthEmail 送信回数の上限", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// 新しい仮登録・認証コードは発行しない
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisGet(pendingSignUpKey(redisKey)).
+			Return(pendingSignUpValue("test@example.com", "Test12345!", "test"), nil)
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().
+			Check(utils.AuthEmailRetryAccountAttemptKey("test@example.com"), utils.AuthEmailRetryIPAttemptKey("192.0.2.1")).
+			Return(5*time.Minute, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		url := fmt.Sprintf(
+			"/api/retry_auth_email?redis_key=%s&user_email=%s&user_name=%s",
+			redisKey,
+			UserEmail,
+			UserName,
+		)
+
+		c.Request = httptest.NewRequest("GET", url, nil)
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mock_utils.NewMockUtilsFetcher(ctrl),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: mock_templates.NewMockEmailTemplateService(ctrl),
+			RedisService:         mockRedisService,
+			AttemptLimiter:       mockAttemptLimiter,
+		}
+		fetcher.RetryAuthEmail(c)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "300", w.Header().Get("Retry-After"))
+		assertErrorMessage(t, w, authEmailRetryAttemptsMessage)
 	})
 }
 
+// pendingConfirmCode はテスト用の仮登録に保存された認証コード
+const pendingConfirmCode = "5492"
+
+// pendingSignUpValue はテスト用にRedisに保存された仮登録の情報(JSON)を作成する
+func pendingSignUpValue(userEmail, userPassword, userName string) string {
+	value, _ := json.Marshal(pendingSignUp{
+		UserEmail:    userEmail,
+		UserPassword: userPassword,
+		UserName:     userName,
+		ConfirmCode:  pendingConfirmCode,
+	})
+	return string(value)
+}
+
 func TestPostSignUpApi(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 
-	var redisKey string = "5492:672e1fed-0d7e-4735-a91c-f25dc6992eae"
-	var authEmailCode string = "5492"
+	// セッションの記録はDBに接続しない
+	sessionPatches := patchInsertSession(nil)
+	defer sessionPatches.Reset()
+
+	var redisKey string = "672e1fed-0d7e-4735-a91c-f25dc6992eae"
+	var authEmailCode string = pendingConfirmCode
 
 	t.Run("PostSignUpApi JSON不正", func(t *testing.T) {
 		w := httptest.NewRecorder()
@@ -2131,10 +2793,11 @@ func TestPostSignUpApi(t *testing.T) {
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignUpApi(c)
 
@@ -2149,6 +2812,14 @@ func TestPostSignUpApi(t *testing.T) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisGet(pendingSignUpKey(redisKey)).
+			Return(pendingSignUpValue("test@example.com", "Test12345!", "test"), nil)
+
 		data := RequestRedisKeyData{
 			RedisKey:      redisKey,
 			AuthEmailCode: "1234",
@@ -2159,10 +2830,11 @@ func TestPostSignUpApi(t *testing.T) {
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
-			RedisService:         config.NewRedisManager(),
+			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignUpApi(c)
 
@@ -2178,7 +2850,7 @@ func TestPostSignUpApi(t *testing.T) {
 		assert.Equal(t, responseBody.Result, expectedErrorMessage.Result)
 	})
 
-	t.Run("PostSignUpApi redis getエラー", func(t *testing.T) {
+	t.Run("PostSignUpApi 仮登録が存在しない", func(t *testing.T) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
@@ -2192,7 +2864,7 @@ func TestPostSignUpApi(t *testing.T) {
 		// モックの挙動を定義
 		mockRedisService.EXPECT().
 			RedisGet(gomock.Any()).
-			Return("test_user,test_password,test_username", fmt.Errorf("redisエラー"))
+			Return("", fmt.Errorf("キーが存在しません: %s", pendingSignUpKey(redisKey)))
 
 		data := RequestRedisKeyData{
 			RedisKey:      redisKey,
@@ -2204,21 +2876,23 @@ func TestPostSignUpApi(t *testing.T) {
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignUpApi(c)
 
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		// 認証コードの誤りと同様に扱う
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
 
 		var responseBody utils.ResponseData[string]
 		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
 		assert.NoError(t, err)
 
 		expectedErrorMessage := utils.ResponseData[string]{
-			Result: "redisエラー",
+			Result: "メール認証コードが間違っています。",
 		}
 		assert.Equal(t, responseBody.Result, expectedErrorMessage.Result)
 	})
@@ -2237,7 +2911,7 @@ func TestPostSignUpApi(t *testing.T) {
 		// モックの挙動を定義
 		mockRedisService.EXPECT().
 			RedisGet(gomock.Any()).
-			Return(",,", nil)
+			Return(pendingSignUpValue("", "", ""), nil)
 
 		data := RequestRedisKeyData{
 			RedisKey:      redisKey,
@@ -2249,10 +2923,11 @@ func TestPostSignUpApi(t *testing.T) {
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignUpApi(c)
 
@@ -2297,7 +2972,7 @@ func TestPostSignUpApi(t *testing.T) {
 		// モックの挙動を定義
 		mockRedisService.EXPECT().
 			RedisGet(gomock.Any()).
-			Return("test_user,test_password,test_username", nil)
+			Return(pendingSignUpValue("test_user", "test_password", "test_username"), nil)
 
 		data := RequestRedisKeyData{
 			RedisKey:      redisKey,
@@ -2309,10 +2984,11 @@ func TestPostSignUpApi(t *testing.T) {
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignUpApi(c)
 
@@ -2345,7 +3021,7 @@ func TestPostSignUpApi(t *testing.T) {
 		// モックの挙動を定義
 		mockRedisService.EXPECT().
 			RedisGet(gomock.Any()).
-			Return("test@example.com,Test12345!,test", nil)
+			Return(pendingSignUpValue("test@example.com", "Test12345!", "test"), nil)
 
 		data := RequestRedisKeyData{
 			RedisKey:      redisKey,
@@ -2368,10 +3044,11 @@ func TestPostSignUpApi(t *testing.T) {
 		defer patches.Reset()
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignUpApi(c)
 
@@ -2387,6 +3064,86 @@ func TestPostSignUpApi(t *testing.T) {
 		assert.Equal(t, responseBody.Result, expectedErrorMessage.Result)
 	})
 
+	t.Run("PostSignUpApi カンマを含むユーザー名・パスワードのハッシュを分割しない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hashedPassword := "$argon2id$v=19$m=65536,t=3,p=2$c2FsdA$a2V5"
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisGet(gomock.Any()).
+			Return(pendingSignUpValue("test@example.com", hashedPassword, "Taro, Yamada"), nil)
+
+		data := RequestRedisKeyData{
+			RedisKey:      redisKey,
+			AuthEmailCode: authEmailCode,
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		body, _ := json.Marshal(data)
+		c.Request = httptest.NewRequest("POST", "/api/signup", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		var signUpData models.RequestSignUpData
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"PostSignUp",
+			func(_ *models.SignDataFetcher, data models.RequestSignUpData) (int, error) {
+				signUpData = data
+				return 0, fmt.Errorf("sql登録失敗")
+			})
+		defer patches.Reset()
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
+		}
+		fetcher.PostSignUpApi(c)
+
+		assert.Equal(t, "test@example.com", signUpData.UserEmail)
+		assert.Equal(t, hashedPassword, signUpData.UserPassword)
+		assert.Equal(t, "Taro, Yamada", signUpData.UserName)
+	})
+
+	t.Run("PostSignUpApi 仮登録の情報が不正", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisGet(gomock.Any()).
+			Return("test@example.com,Test12345!,test", nil)
+
+		data := RequestRedisKeyData{
+			RedisKey:      redisKey,
+			AuthEmailCode: authEmailCode,
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		body, _ := json.Marshal(data)
+		c.Request = httptest.NewRequest("POST", "/api/signup", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
+		}
+		fetcher.PostSignUpApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertErrorMessage(t, w, "仮登録の情報が不正です。仮登録からやり直してください。")
+	})
+
 	t.Run("PostSignUpApi redis 削除エラー", func(t *testing.T) {
 
 		// gomock のコントローラを作成
@@ -2399,7 +3156,7 @@ func TestPostSignUpApi(t *testing.T) {
 		// モックの挙動を定義
 		mockRedisService.EXPECT().
 			RedisGet(gomock.Any()).
-			Return("test@example.com,Test12345!,test", nil)
+			Return(pendingSignUpValue("test@example.com", "Test12345!", "test"), nil)
 
 		mockRedisService.EXPECT().
 			RedisDel(gomock.Any()).
@@ -2426,10 +3183,11 @@ func TestPostSignUpApi(t *testing.T) {
 		defer patches.Reset()
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignUpApi(c)
 
@@ -2457,14 +3215,14 @@ func TestPostSignUpApi(t *testing.T) {
 		// モックの挙動を定義
 		mockRedisService.EXPECT().
 			RedisGet(gomock.Any()).
-			Return("test@example.com,Test12345!,test", nil)
+			Return(pendingSignUpValue("test@example.com", "Test12345!", "test"), nil)
 
 		mockRedisService.EXPECT().
 			RedisDel(gomock.Any()).
 			Return(nil)
 
 		mockUtilsFetcher.EXPECT().
-			NewToken(gomock.Any(), gomock.Any()).
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("", fmt.Errorf("トークン生成エラー"))
 
 		data := RequestRedisKeyData{
@@ -2493,6 +3251,7 @@ func TestPostSignUpApi(t *testing.T) {
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignUpApi(c)
 
@@ -2522,18 +3281,18 @@ func TestPostSignUpApi(t *testing.T) {
 		// モックの挙動を定義
 		mockRedisService.EXPECT().
 			RedisGet(gomock.Any()).
-			Return("test@example.com,Test12345!,test", nil)
+			Return(pendingSignUpValue("test@example.com", "Test12345!", "test"), nil)
 
 		mockRedisService.EXPECT().
 			RedisDel(gomock.Any()).
 			Return(nil)
 
 		mockUtilsFetcher.EXPECT().
-			NewToken(gomock.Any(), gomock.Any()).
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("token", nil)
 
 		mockUtilsFetcher.EXPECT().
-			RefreshToken(gomock.Any(), gomock.Any()).
+			RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("", fmt.Errorf("トークン生成エラー"))
 
 		data := RequestRedisKeyData{
@@ -2562,6 +3321,7 @@ func TestPostSignUpApi(t *testing.T) {
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignUpApi(c)
 
@@ -2593,18 +3353,18 @@ func TestPostSignUpApi(t *testing.T) {
 		// モックの挙動を定義
 		mockRedisService.EXPECT().
 			RedisGet(gomock.Any()).
-			Return("test@example.com,Test12345!,test", nil)
+			Return(pendingSignUpValue("test@example.com", "Test12345!", "test"), nil)
 
 		mockRedisService.EXPECT().
 			RedisDel(gomock.Any()).
 			Return(nil)
 
 		mockUtilsFetcher.EXPECT().
-			NewToken(gomock.Any(), gomock.Any()).
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("token", nil)
 
 		mockUtilsFetcher.EXPECT().
-			RefreshToken(gomock.Any(), gomock.Any()).
+			RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("", nil)
 
 		mockUtilsFetcher.EXPECT().
@@ -2640,6 +3400,7 @@ func TestPostSignUpApi(t *testing.T) {
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: mockEmailTemplateService,
 			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignUpApi(c)
 
@@ -2669,18 +3430,18 @@ func TestPostSignUpApi(t *testing.T) {
 		// モックの挙動を定義
 		mockRedisService.EXPECT().
 			RedisGet(gomock.Any()).
-			Return("test@example.com,Test12345!,test", nil)
+			Return(pendingSignUpValue("test@example.com", "Test12345!", "test"), nil)
 
 		mockRedisService.EXPECT().
 			RedisDel(gomock.Any()).
 			Return(nil)
 
 		mockUtilsFetcher.EXPECT().
-			NewToken(gomock.Any(), gomock.Any()).
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("token", nil)
 
 		mockUtilsFetcher.EXPECT().
-			RefreshToken(gomock.Any(), gomock.Any()).
+			RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("", nil)
 
 		mockUtilsFetcher.EXPECT().
@@ -2720,6 +3481,7 @@ func TestPostSignUpApi(t *testing.T) {
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: mockEmailTemplateService,
 			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignUpApi(c)
 
@@ -2749,18 +3511,18 @@ func TestPostSignUpApi(t *testing.T) {
 		// モックの挙動を定義
 		mockRedisService.EXPECT().
 			RedisGet(gomock.Any()).
-			Return("test@example.com,Test12345!,test", nil)
+			Return(pendingSignUpValue("test@example.com", "Test12345!", "test"), nil)
 
 		mockRedisService.EXPECT().
 			RedisDel(gomock.Any()).
 			Return(nil)
 
 		mockUtilsFetcher.EXPECT().
-			NewToken(gomock.Any(), gomock.Any()).
+			NewToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("token", nil)
 
 		mockUtilsFetcher.EXPECT().
-			RefreshToken(gomock.Any(), gomock.Any()).
+			RefreshToken(gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("", nil)
 
 		mockUtilsFetcher.EXPECT().
@@ -2800,6 +3562,7 @@ func TestPostSignUpApi(t *testing.T) {
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: mockEmailTemplateService,
 			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
 		}
 		fetcher.PostSignUpApi(c)
 
@@ -2836,7 +3599,7 @@ func TestPutSignInEditApi(t *testing.T) {
 		)
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -2873,7 +3636,7 @@ func TestPutSignInEditApi(t *testing.T) {
 		defer patches.Reset()
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -2896,6 +3659,10 @@ func TestPutSignInEditApi(t *testing.T) {
 					Field:   "user_email",
 					Message: "メールアドレスは必須です。",
 				},
+				{
+					Field:   "current_password",
+					Message: "現在のパスワードは必須です。",
+				},
 			},
 		}
 		test_utils.SortErrorMessages(responseBody.Result)
@@ -2907,12 +3674,14 @@ func TestPutSignInEditApi(t *testing.T) {
 
 		dataList := []models.RequestSignInEditData{
 			{
-				UserEmail:    "test@example.com",
-				UserPassword: "",
+				UserEmail:       "test@example.com",
+				UserPassword:    "",
+				CurrentPassword: "Current12345!",
 			},
 			{
-				UserEmail:    "test@example.com",
-				UserPassword: "",
+				UserEmail:       "test@example.com",
+				UserPassword:    "",
+				CurrentPassword: "Current12345!",
 			},
 		}
 
@@ -2932,7 +3701,7 @@ func TestPutSignInEditApi(t *testing.T) {
 			defer patches.Reset()
 
 			fetcher := apiSignDataFetcher{
-				UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+				UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 				CommonFetcher:        common.NewCommonFetcher(),
 				EmailTemplateService: templates.NewEmailTemplateManager(),
 				RedisService:         config.NewRedisManager(),
@@ -2961,8 +3730,9 @@ func TestPutSignInEditApi(t *testing.T) {
 
 	t.Run("PutSignInEditApi バリデーション メールアドレス不正", func(t *testing.T) {
 		data := models.RequestSignInEditData{
-			UserEmail:    "test@example",
-			UserPassword: "Test12345!",
+			UserEmail:       "test@example",
+			UserPassword:    "Test12345!",
+			CurrentPassword: "Current12345!",
 		}
 
 		w, c := test_utils.CreateTestRequest(
@@ -2980,7 +3750,7 @@ func TestPutSignInEditApi(t *testing.T) {
 		defer patches.Reset()
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -3008,16 +3778,24 @@ func TestPutSignInEditApi(t *testing.T) {
 
 		dataList := []models.RequestSignInEditData{
 			{
-				UserEmail:    "test@example.com",
-				UserPassword: "Test12!",
+				UserEmail:       "test@example.com",
+				UserPassword:    "Test12!",
+				CurrentPassword: "Current12345!",
 			},
 			{
-				UserEmail:    "test@example.com",
-				UserPassword: "Test123456",
+				UserEmail:       "test@example.com",
+				UserPassword:    "Test123456",
+				CurrentPassword: "Current12345!",
 			},
 		}
 
-		for _, data := range dataList {
+		// 満たしていないポリシーごとに理由を返す
+		expectedMessages := []string{
+			"パスワードは8文字以上で入力してください。",
+			"パスワードには記号を1文字以上含めてください。",
+		}
+
+		for i, data := range dataList {
 			w, c := test_utils.CreateTestRequest(
 				"PUT", "/api/signin_edit/1",
 				data,
@@ -3033,7 +3811,7 @@ func TestPutSignInEditApi(t *testing.T) {
 			defer patches.Reset()
 
 			fetcher := apiSignDataFetcher{
-				UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+				UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 				CommonFetcher:        common.NewCommonFetcher(),
 				EmailTemplateService: templates.NewEmailTemplateManager(),
 				RedisService:         config.NewRedisManager(),
@@ -3050,7 +3828,7 @@ func TestPutSignInEditApi(t *testing.T) {
 				Result: []utils.ErrorMessages{
 					{
 						Field:   "user_password",
-						Message: "パスワードの形式が間違っています。",
+						Message: expectedMessages[i],
 					},
 				},
 			}
@@ -3061,8 +3839,9 @@ func TestPutSignInEditApi(t *testing.T) {
 	t.Run("PutSignInEditApi 更新チェックエラー", func(t *testing.T) {
 
 		data := models.RequestSignInEditData{
-			UserEmail:    "test@example.com",
-			UserPassword: "Test123456!!",
+			UserEmail:       "test@example.com",
+			UserPassword:    "Test123456!!",
+			CurrentPassword: "Current12345!",
 		}
 
 		w, c := test_utils.CreateTestRequest(
@@ -3070,17 +3849,18 @@ func TestPutSignInEditApi(t *testing.T) {
 			data,
 			map[string]string{"user_id": "1"},
 		)
+		c.Set(utils.UserId, 1)
 
 		patches := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"PutCheck",
-			func(_ *models.SignDataFetcher, data models.RequestSignInEditData) (string, error) {
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestSignInEditData) (string, error) {
 				return "", fmt.Errorf("sql失敗")
 			})
 		defer patches.Reset()
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -3102,8 +3882,9 @@ func TestPutSignInEditApi(t *testing.T) {
 	t.Run("PutSignInEditApi sql取得で失敗しサインイン情報編集に失敗になる", func(t *testing.T) {
 
 		data := models.RequestSignInEditData{
-			UserEmail:    "test@example.com",
-			UserPassword: "Test123456!!",
+			UserEmail:       "test@example.com",
+			UserPassword:    "Test123456!!",
+			CurrentPassword: "Current12345!",
 		}
 
 		w, c := test_utils.CreateTestRequest(
@@ -3111,12 +3892,13 @@ func TestPutSignInEditApi(t *testing.T) {
 			data,
 			map[string]string{"user_id": "1"},
 		)
+		c.Set(utils.UserId, 1)
 
 		patches := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"PutCheck",
-			func(_ *models.SignDataFetcher, data models.RequestSignInEditData) (string, error) {
-				return "メールアドレス更新", nil
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestSignInEditData) (string, error) {
+				return "パスワード更新", nil
 			})
 		defer patches.Reset()
 
@@ -3129,7 +3911,7 @@ func TestPutSignInEditApi(t *testing.T) {
 		defer patches1.Reset()
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -3148,11 +3930,63 @@ func TestPutSignInEditApi(t *testing.T) {
 		assert.Equal(t, responseBody, expectedErrorMessage)
 	})
 
+	t.Run("PutSignInEditApi 直近に使用したパスワード", func(t *testing.T) {
+
+		data := models.RequestSignInEditData{
+			UserEmail:       "test@example.com",
+			UserPassword:    "Test123456!!",
+			CurrentPassword: "Current12345!",
+		}
+
+		w, c := test_utils.CreateTestRequest(
+			"PUT", "/api/signin_edit/1",
+			data,
+			map[string]string{"user_id": "1"},
+		)
+		c.Set(utils.UserId, 1)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"PutCheck",
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestSignInEditData) (string, error) {
+				return "パスワード更新", nil
+			})
+		defer patches.Reset()
+
+		patches1 := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"PutSignInEdit",
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestSignInEditData) error {
+				return models.ErrPasswordReused
+			})
+		defer patches1.Reset()
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         config.NewRedisManager(),
+		}
+		fetcher.PutSignInEditApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var responseBody utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []utils.ErrorMessages{{
+			Field:   "user_password",
+			Message: "パスワードは直近5回に使用したパスワード以外を指定してください。",
+		}}, responseBody.Result)
+	})
+
 	t.Run("PutSignInEditApi メールテンプレート生成エラー(更新)", func(t *testing.T) {
 
 		data := models.RequestSignInEditData{
-			UserEmail:    "test@example.com",
-			UserPassword: "Test123456!!",
+			UserEmail:       "test@example.com",
+			UserPassword:    "Test123456!!",
+			CurrentPassword: "Current12345!",
 		}
 
 		// gomock のコントローラを作成
@@ -3163,6 +3997,15 @@ func TestPutSignInEditApi(t *testing.T) {
 		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
 		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
 
+		// 発行済みのトークンを失効させること
+		mockUtilsFetcher.EXPECT().
+			RevokeUserTokens(gomock.Any()).
+			Return(nil)
+
+		// 全ての端末のセッションをサインアウト済みにすること
+		sessionPatches := patchRevokeAllSessions(nil)
+		defer sessionPatches.Reset()
+
 		// モックの挙動を定義
 		mockUtilsFetcher.EXPECT().
 			DateTimeStr(gomock.Any(), gomock.Any()).
@@ -3177,12 +4020,13 @@ func TestPutSignInEditApi(t *testing.T) {
 			data,
 			map[string]string{"user_id": "1"},
 		)
+		c.Set(utils.UserId, 1)
 
 		patches := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"PutCheck",
-			func(_ *models.SignDataFetcher, data models.RequestSignInEditData) (string, error) {
-				return "メールアドレス更新", nil
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestSignInEditData) (string, error) {
+				return "パスワード更新", nil
 			})
 		defer patches.Reset()
 
@@ -3217,8 +4061,9 @@ func TestPutSignInEditApi(t *testing.T) {
 	t.Run("PutSignInEditApi メール送信エラー(更新)", func(t *testing.T) {
 
 		data := models.RequestSignInEditData{
-			UserEmail:    "test@example.com",
-			UserPassword: "Test123456!!",
+			UserEmail:       "test@example.com",
+			UserPassword:    "Test123456!!",
+			CurrentPassword: "Current12345!",
 		}
 
 		// gomock のコントローラを作成
@@ -3229,6 +4074,15 @@ func TestPutSignInEditApi(t *testing.T) {
 		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
 		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
 
+		// 発行済みのトークンを失効させること
+		mockUtilsFetcher.EXPECT().
+			RevokeUserTokens(gomock.Any()).
+			Return(nil)
+
+		// 全ての端末のセッションをサインアウト済みにすること
+		sessionPatches := patchRevokeAllSessions(nil)
+		defer sessionPatches.Reset()
+
 		// モックの挙動を定義
 		mockUtilsFetcher.EXPECT().
 			DateTimeStr(gomock.Any(), gomock.Any()).
@@ -3247,11 +4101,12 @@ func TestPutSignInEditApi(t *testing.T) {
 			data,
 			map[string]string{"user_id": "1"},
 		)
+		c.Set(utils.UserId, 1)
 
 		patches := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"PutCheck",
-			func(_ *models.SignDataFetcher, data models.RequestSignInEditData) (string, error) {
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestSignInEditData) (string, error) {
 				return "パスワード更新", nil
 			})
 		defer patches.Reset()
@@ -3265,30 +4120,81 @@ func TestPutSignInEditApi(t *testing.T) {
 		defer patches1.Reset()
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         mockUtilsFetcher,
+			UtilsFetcher:         mockUtilsFetcher,
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: mockEmailTemplateService,
+			RedisService:         config.NewRedisManager(),
+		}
+		fetcher.PutSignInEditApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var responseBody utils.ResponseData[string]
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+
+		expectedErrorMessage := utils.ResponseData[string]{
+			Result: "メール送信エラー(更新): メール送信エラー",
+		}
+		assert.Equal(t, responseBody, expectedErrorMessage)
+	})
+
+	t.Run("PutSignInEditApi メールアドレスは即時に変更しない", func(t *testing.T) {
+
+		data := models.RequestSignInEditData{
+			UserEmail:       "new@example.com",
+			UserPassword:    "Test123456!!",
+			CurrentPassword: "Current12345!",
+		}
+
+		w, c := test_utils.CreateTestRequest(
+			"PUT", "/api/signin_edit/1",
+			data,
+			map[string]string{"user_id": "1"},
+		)
+		c.Set(utils.UserId, 1)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"PutCheck",
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestSignInEditData) (string, error) {
+				return "メールアドレス更新", nil
+			})
+		defer patches.Reset()
+
+		updated := false
+		patches1 := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"PutSignInEdit",
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestSignInEditData) error {
+				updated = true
+				return nil
+			})
+		defer patches1.Reset()
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
-			EmailTemplateService: mockEmailTemplateService,
+			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
 		}
 		fetcher.PutSignInEditApi(c)
 
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.False(t, updated)
 
 		var responseBody utils.ResponseData[string]
 		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
 		assert.NoError(t, err)
-
-		expectedErrorMessage := utils.ResponseData[string]{
-			Result: "メール送信エラー(更新): メール送信エラー",
-		}
-		assert.Equal(t, responseBody, expectedErrorMessage)
+		assert.Equal(t, "メールアドレスの変更は確認メールによる手続きが必要です。", responseBody.Result)
 	})
 
-	t.Run("PutSignInEditApi result 成功 1", func(t *testing.T) {
+	t.Run("PutSignInEditApi result 成功 2", func(t *testing.T) {
 
 		data := models.RequestSignInEditData{
-			UserEmail:    "test@example.com",
-			UserPassword: "Test123456!!",
+			UserEmail:       "test@example.com",
+			UserPassword:    "Test123456!!",
+			CurrentPassword: "Current12345!",
 		}
 
 		// gomock のコントローラを作成
@@ -3299,13 +4205,19 @@ func TestPutSignInEditApi(t *testing.T) {
 		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
 		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
 
+		// 発行済みのトークンを失効させること
+		mockUtilsFetcher.EXPECT().
+			RevokeUserTokens(gomock.Any()).
+			Return(nil)
+
 		// モックの挙動を定義
 		mockUtilsFetcher.EXPECT().
 			DateTimeStr(gomock.Any(), gomock.Any()).
 			Return("2024年12月2日")
 
+		// 新しいパスワードはメールに記載しない
 		mockEmailTemplateService.EXPECT().
-			PostSignInEditTemplate(gomock.Any(), gomock.Any(), gomock.Any()).
+			PostSignInEditTemplate("パスワード更新", signInEditPasswordNotice, gomock.Any()).
 			Return("件名", "本文", nil)
 
 		mockUtilsFetcher.EXPECT().
@@ -3313,27 +4225,44 @@ func TestPutSignInEditApi(t *testing.T) {
 			Return(nil)
 
 		w, c := test_utils.CreateTestRequest(
-			"PUT", "/api/signin_edit/1",
+			"PUT", "/api/signin_edit/2",
 			data,
-			map[string]string{"user_id": "1"},
+			map[string]string{"user_id": "2"},
 		)
+		c.Set(utils.UserId, 2)
 
 		patches := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"PutCheck",
-			func(_ *models.SignDataFetcher, data models.RequestSignInEditData) (string, error) {
-				return "メールアドレス更新", nil
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestSignInEditData) (string, error) {
+				return "パスワード更新", nil
 			})
 		defer patches.Reset()
 
+		// パスワードのみを更新し、メールアドレスは変更しない
+		var updatedData models.RequestSignInEditData
 		patches1 := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"PutSignInEdit",
 			func(_ *models.SignDataFetcher, UserId int, data models.RequestSignInEditData) error {
+				updatedData = data
 				return nil
 			})
 		defer patches1.Reset()
 
+		// 全ての端末のセッションをサインアウト済みにすること
+		var revokedUserId int
+		revokedCurrentSessionId := "-"
+		sessionPatches := ApplyMethod(
+			reflect.TypeOf(&models.SessionDataFetcher{}),
+			"RevokeOtherSessions",
+			func(_ *models.SessionDataFetcher, UserId int, CurrentSessionId string) ([]string, error) {
+				revokedUserId = UserId
+				revokedCurrentSessionId = CurrentSessionId
+				return []string{}, nil
+			})
+		defer sessionPatches.Reset()
+
 		fetcher := apiSignDataFetcher{
 			UtilsFetcher:         mockUtilsFetcher,
 			CommonFetcher:        common.NewCommonFetcher(),
@@ -3348,38 +4277,31 @@ func TestPutSignInEditApi(t *testing.T) {
 		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
 		assert.NoError(t, err)
 
+		// 新しいパスワードはレスポンスに含めない
 		expectedErrorOk := utils.ResponseData[string]{
-			Result: "サインイン編集に成功:test@example.com",
+			Result: "サインイン編集に成功",
 		}
 		assert.Equal(t, responseBody.Result, expectedErrorOk.Result)
+		assert.Equal(t, models.RequestSignInEditData{UserPassword: "Test123456!!"}, updatedData)
+		assert.Equal(t, 2, revokedUserId)
+		assert.Equal(t, "", revokedCurrentSessionId)
 	})
 
-	t.Run("PutSignInEditApi result 成功 2", func(t *testing.T) {
+	t.Run("PutSignInEditApi セッションの失効に失敗", func(t *testing.T) {
 
 		data := models.RequestSignInEditData{
-			UserEmail:    "test@example.com",
-			UserPassword: "Test123456!!",
+			UserEmail:       "test@example.com",
+			UserPassword:    "Test123456!!",
+			CurrentPassword: "Current12345!",
 		}
 
 		// gomock のコントローラを作成
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		// 各モックを作成
-		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
 		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
-
-		// モックの挙動を定義
-		mockUtilsFetcher.EXPECT().
-			DateTimeStr(gomock.Any(), gomock.Any()).
-			Return("2024年12月2日")
-
-		mockEmailTemplateService.EXPECT().
-			PostSignInEditTemplate(gomock.Any(), gomock.Any(), gomock.Any()).
-			Return("件名", "本文", nil)
-
 		mockUtilsFetcher.EXPECT().
-			SendMail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			RevokeUserTokens(gomock.Any()).
 			Return(nil)
 
 		w, c := test_utils.CreateTestRequest(
@@ -3387,11 +4309,12 @@ func TestPutSignInEditApi(t *testing.T) {
 			data,
 			map[string]string{"user_id": "2"},
 		)
+		c.Set(utils.UserId, 2)
 
 		patches := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"PutCheck",
-			func(_ *models.SignDataFetcher, data models.RequestSignInEditData) (string, error) {
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestSignInEditData) (string, error) {
 				return "パスワード更新", nil
 			})
 		defer patches.Reset()
@@ -3404,24 +4327,108 @@ func TestPutSignInEditApi(t *testing.T) {
 			})
 		defer patches1.Reset()
 
+		sessionPatches := patchRevokeAllSessions(fmt.Errorf("DBエラー"))
+		defer sessionPatches.Reset()
+
 		fetcher := apiSignDataFetcher{
 			UtilsFetcher:         mockUtilsFetcher,
 			CommonFetcher:        common.NewCommonFetcher(),
-			EmailTemplateService: mockEmailTemplateService,
+			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
 		}
 		fetcher.PutSignInEditApi(c)
 
-		assert.Equal(t, http.StatusOK, w.Code)
+		// 通知メールは送信しない
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assertErrorMessage(t, w, "セッションの失効に失敗しました。")
+	})
 
-		var responseBody utils.ResponseData[string]
-		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
-		assert.NoError(t, err)
+	t.Run("PutSignInEditApi サインインユーザーが異なっています", func(t *testing.T) {
 
-		expectedErrorOk := utils.ResponseData[string]{
-			Result: "サインイン編集に成功:Test123456!!",
+		data := models.RequestSignInEditData{
+			UserEmail:       "test@example.com",
+			UserPassword:    "Test123456!!",
+			CurrentPassword: "Current12345!",
 		}
-		assert.Equal(t, responseBody.Result, expectedErrorOk.Result)
+
+		w, c := test_utils.CreateTestRequest(
+			"PUT", "/api/signin_edit/2",
+			data,
+			map[string]string{"user_id": "2"},
+		)
+		c.Set(utils.UserId, 1)
+
+		checked := false
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"PutCheck",
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestSignInEditData) (string, error) {
+				checked = true
+				return "パスワード更新", nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         config.NewRedisManager(),
+		}
+		fetcher.PutSignInEditApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.False(t, checked)
+		assertErrorMessage(t, w, "サインインユーザーが異なっています。")
+	})
+
+	t.Run("PutSignInEditApi 現在のパスワードが一致しない", func(t *testing.T) {
+
+		data := models.RequestSignInEditData{
+			UserEmail:       "test@example.com",
+			UserPassword:    "Test123456!!",
+			CurrentPassword: "Wrong12345!",
+		}
+
+		w, c := test_utils.CreateTestRequest(
+			"PUT", "/api/signin_edit/1",
+			data,
+			map[string]string{"user_id": "1"},
+		)
+		c.Set(utils.UserId, 1)
+
+		// 現在のパスワードはサインイン中のユーザーIDで照合する
+		var checkedUserId int
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"PutCheck",
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestSignInEditData) (string, error) {
+				checkedUserId = UserId
+				return "", models.ErrCurrentPasswordMismatch
+			})
+		defer patches.Reset()
+
+		updated := false
+		patches1 := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"PutSignInEdit",
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestSignInEditData) error {
+				updated = true
+				return nil
+			})
+		defer patches1.Reset()
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         config.NewRedisManager(),
+		}
+		fetcher.PutSignInEditApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, 1, checkedUserId)
+		assert.False(t, updated)
+		assertErrorMessage(t, w, "現在のパスワードが一致しませんでした。")
 	})
 }
 
@@ -3441,7 +4448,7 @@ func TestDeleteSignInApi(t *testing.T) {
 		)
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -3470,13 +4477,13 @@ func TestDeleteSignInApi(t *testing.T) {
 		patches := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"DeleteSignIn",
-			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData) error {
+			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData, PurgeAt time.Time) error {
 				return nil
 			})
 		defer patches.Reset()
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -3525,13 +4532,13 @@ func TestDeleteSignInApi(t *testing.T) {
 		patches := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"DeleteSignIn",
-			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData) error {
+			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData, PurgeAt time.Time) error {
 				return nil
 			})
 		defer patches.Reset()
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -3573,13 +4580,13 @@ func TestDeleteSignInApi(t *testing.T) {
 		patches := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"DeleteSignIn",
-			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData) error {
+			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData, PurgeAt time.Time) error {
 				return nil
 			})
 		defer patches.Reset()
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -3621,16 +4628,19 @@ func TestDeleteSignInApi(t *testing.T) {
 		patches := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"DeleteSignIn",
-			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData) error {
+			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData, PurgeAt time.Time) error {
 				return fmt.Errorf("sql削除失敗")
 			})
 		defer patches.Reset()
 
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
-			RedisService:         config.NewRedisManager(),
+			RedisService:         expectIssueAccountRestoreToken(ctrl, 1),
 		}
 		fetcher.DeleteSignInApi(c)
 
@@ -3661,13 +4671,19 @@ func TestDeleteSignInApi(t *testing.T) {
 		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
 		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
 
+		// 発行済みのトークンを失効させること
+		mockUtilsFetcher.EXPECT().
+			RevokeUserTokens(gomock.Any()).
+			Return(nil)
+
 		// モックの挙動を定義
 		mockUtilsFetcher.EXPECT().
 			DateTimeStr(gomock.Any(), gomock.Any()).
-			Return("2024年12月2日")
+			Return("2024年12月2日").
+			Times(2)
 
 		mockEmailTemplateService.EXPECT().
-			DeleteSignInTemplate(gomock.Any(), gomock.Any(), gomock.Any()).
+			AccountDeactivatedTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("件名", "本文", fmt.Errorf("メールテンプレートエラー"))
 
 		w, c := test_utils.CreateTestRequest(
@@ -3679,7 +4695,7 @@ func TestDeleteSignInApi(t *testing.T) {
 		patches := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"DeleteSignIn",
-			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData) error {
+			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData, PurgeAt time.Time) error {
 				return nil
 			})
 		defer patches.Reset()
@@ -3688,7 +4704,7 @@ func TestDeleteSignInApi(t *testing.T) {
 			UtilsFetcher:         mockUtilsFetcher,
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: mockEmailTemplateService,
-			RedisService:         config.NewRedisManager(),
+			RedisService:         expectIssueAccountRestoreToken(ctrl, 1),
 		}
 		fetcher.DeleteSignInApi(c)
 
@@ -3719,14 +4735,20 @@ func TestDeleteSignInApi(t *testing.T) {
 		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
 		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
 
+		// 発行済みのトークンを失効させること
+		mockUtilsFetcher.EXPECT().
+			RevokeUserTokens(gomock.Any()).
+			Return(nil)
+
 		// モックの挙動を定義
 
 		mockUtilsFetcher.EXPECT().
 			DateTimeStr(gomock.Any(), gomock.Any()).
-			Return("2024年12月2日")
+			Return("2024年12月2日").
+			Times(2)
 
 		mockEmailTemplateService.EXPECT().
-			DeleteSignInTemplate(gomock.Any(), gomock.Any(), gomock.Any()).
+			AccountDeactivatedTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("件名", "本文", nil)
 
 		mockUtilsFetcher.EXPECT().
@@ -3742,7 +4764,7 @@ func TestDeleteSignInApi(t *testing.T) {
 		patches := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"DeleteSignIn",
-			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData) error {
+			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData, PurgeAt time.Time) error {
 				return nil
 			})
 		defer patches.Reset()
@@ -3751,7 +4773,7 @@ func TestDeleteSignInApi(t *testing.T) {
 			UtilsFetcher:         mockUtilsFetcher,
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: mockEmailTemplateService,
-			RedisService:         config.NewRedisManager(),
+			RedisService:         expectIssueAccountRestoreToken(ctrl, 1),
 		}
 		fetcher.DeleteSignInApi(c)
 
@@ -3768,6 +4790,7 @@ func TestDeleteSignInApi(t *testing.T) {
 	})
 
 	t.Run("DeleteSignInApi result 成功", func(t *testing.T) {
+		var purgeAt time.Time
 
 		data := models.RequestSignInDeleteData{
 			UserEmail:  "test@example.com",
@@ -3782,13 +4805,19 @@ func TestDeleteSignInApi(t *testing.T) {
 		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
 		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
 
+		// 発行済みのトークンを失効させること
+		mockUtilsFetcher.EXPECT().
+			RevokeUserTokens(gomock.Any()).
+			Return(nil)
+
 		// モックの挙動を定義
 		mockUtilsFetcher.EXPECT().
 			DateTimeStr(gomock.Any(), gomock.Any()).
-			Return("2024年12月2日")
+			Return("2024年12月2日").
+			Times(2)
 
 		mockEmailTemplateService.EXPECT().
-			DeleteSignInTemplate(gomock.Any(), gomock.Any(), gomock.Any()).
+			AccountDeactivatedTemplate(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 			Return("件名", "本文", nil)
 
 		mockUtilsFetcher.EXPECT().
@@ -3804,7 +4833,8 @@ func TestDeleteSignInApi(t *testing.T) {
 		patches := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"DeleteSignIn",
-			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData) error {
+			func(_ *models.SignDataFetcher, userId int, data models.RequestSignInDeleteData, PurgeAt time.Time) error {
+				purgeAt = PurgeAt
 				return nil
 			})
 		defer patches.Reset()
@@ -3813,11 +4843,13 @@ func TestDeleteSignInApi(t *testing.T) {
 			UtilsFetcher:         mockUtilsFetcher,
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: mockEmailTemplateService,
-			RedisService:         config.NewRedisManager(),
+			RedisService:         expectIssueAccountRestoreToken(ctrl, 1),
 		}
 		fetcher.DeleteSignInApi(c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
+		// 退会の猶予期間後に削除する
+		assert.WithinDuration(t, time.Now().AddDate(0, 0, utils.AccountRestoreDays), purgeAt, time.Minute)
 
 		var responseBody utils.ResponseData[string]
 		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
@@ -3842,7 +4874,7 @@ func TestSignOutApi(t *testing.T) {
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -3876,7 +4908,7 @@ func TestSignOutApi(t *testing.T) {
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -4020,8 +5052,179 @@ func TestSignOutApi(t *testing.T) {
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 
-		c.Request = httptest.NewRequest("GET", "/api/signout?user_email=test@example.com", nil)
-		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request = httptest.NewRequest("GET", "/api/signout?user_email=test@example.com", nil)
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mockUtilsFetcher,
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: mockEmailTemplateService,
+			RedisService:         config.NewRedisManager(),
+		}
+		fetcher.SignOutApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var responseBody utils.ResponseData[string]
+		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.NoError(t, err)
+
+		expectedOk := utils.ResponseData[string]{
+			Result: "サインアウトに成功",
+		}
+		assert.Equal(t, responseBody.Result, expectedOk.Result)
+	})
+
+	t.Run("SignOutApi サインアウトした端末のトークンを失効させる", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+
+		mockClaims := jwt.MapClaims{
+			"UserId": float64(1),
+			"sid":    "session-1",
+			"jti":    "access",
+			"exp":    float64(time.Now().Add(time.Hour).Unix()),
+		}
+
+		mockUtilsFetcher.EXPECT().
+			ParseWithClaims("access_token").
+			Return(&jwt.Token{Claims: mockClaims, Valid: true}, nil).
+			Times(2)
+
+		mockUtilsFetcher.EXPECT().
+			MapClaims(gomock.Any()).
+			Return(mockClaims, true).
+			Times(2)
+
+		mockUtilsFetcher.EXPECT().
+			RevokeToken(mockClaims).
+			Return(nil)
+
+		mockUtilsFetcher.EXPECT().
+			RevokeRefreshTokenFamily("refresh_token").
+			Return(nil)
+
+		mockUtilsFetcher.EXPECT().
+			DateTimeStr(gomock.Any(), gomock.Any()).
+			Return("2024年12月2日")
+
+		mockEmailTemplateService.EXPECT().
+			SignOutTemplate(gomock.Any(), gomock.Any()).
+			Return("件名", "本文", nil)
+
+		mockUtilsFetcher.EXPECT().
+			SendMail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request = httptest.NewRequest("GET", "/api/signout?user_email=test@example.com", nil)
+		c.Request.AddCookie(&http.Cookie{
+			Name:  utils.AuthToken,
+			Value: "access_token",
+		})
+		c.Request.AddCookie(&http.Cookie{
+			Name:  utils.RefreshAuthToken,
+			Value: "refresh_token",
+		})
+
+		// サインアウトした端末のセッションをサインアウト済みにすること
+		var revokedSessionId string
+		var revokedUserId int
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SessionDataFetcher{}),
+			"RevokeSession",
+			func(_ *models.SessionDataFetcher, SessionId string, UserId int) error {
+				revokedSessionId = SessionId
+				revokedUserId = UserId
+				return nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mockUtilsFetcher,
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: mockEmailTemplateService,
+			RedisService:         config.NewRedisManager(),
+		}
+		fetcher.SignOutApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "session-1", revokedSessionId)
+		assert.Equal(t, 1, revokedUserId)
+	})
+
+	t.Run("SignOutApi アクセストークンが期限切れの場合はリフレッシュトークンのセッションを失効させる", func(t *testing.T) {
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+
+		refreshClaims := jwt.MapClaims{
+			"UserId": float64(1),
+			"sid":    "session-1",
+			"jti":    "refresh",
+		}
+
+		mockUtilsFetcher.EXPECT().
+			ParseWithClaims("access_token").
+			Return(nil, fmt.Errorf("token is expired")).
+			Times(2)
+
+		mockUtilsFetcher.EXPECT().
+			ParseWithClaims("refresh_token").
+			Return(&jwt.Token{Claims: refreshClaims, Valid: true}, nil)
+
+		mockUtilsFetcher.EXPECT().
+			MapClaims(gomock.Any()).
+			Return(refreshClaims, true)
+
+		mockUtilsFetcher.EXPECT().
+			RevokeRefreshTokenFamily("refresh_token").
+			Return(nil)
+
+		mockUtilsFetcher.EXPECT().
+			DateTimeStr(gomock.Any(), gomock.Any()).
+			Return("2024年12月2日")
+
+		mockEmailTemplateService.EXPECT().
+			SignOutTemplate(gomock.Any(), gomock.Any()).
+			Return("件名", "本文", nil)
+
+		mockUtilsFetcher.EXPECT().
+			SendMail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		c.Request = httptest.NewRequest("GET", "/api/signout?user_email=test@example.com", nil)
+		c.Request.AddCookie(&http.Cookie{
+			Name:  utils.AuthToken,
+			Value: "access_token",
+		})
+		c.Request.AddCookie(&http.Cookie{
+			Name:  utils.RefreshAuthToken,
+			Value: "refresh_token",
+		})
+
+		// 失効済みでセッションが存在しない場合もサインアウトは継続すること
+		var revokedSessionId string
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SessionDataFetcher{}),
+			"RevokeSession",
+			func(_ *models.SessionDataFetcher, SessionId string, UserId int) error {
+				revokedSessionId = SessionId
+				return fmt.Errorf("対象のセッションが存在しません。")
+			})
+		defer patches.Reset()
 
 		fetcher := apiSignDataFetcher{
 			UtilsFetcher:         mockUtilsFetcher,
@@ -4032,16 +5235,67 @@ func TestSignOutApi(t *testing.T) {
 		fetcher.SignOutApi(c)
 
 		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "session-1", revokedSessionId)
+	})
+}
 
-		var responseBody utils.ResponseData[string]
-		err := json.Unmarshal(w.Body.Bytes(), &responseBody)
-		assert.NoError(t, err)
+// testResetToken はメールのリンクに含めるパスワード再設定トークン
+const testResetToken = "Hk3p4Xq0m1b2C3d4E5f6G7h8I9j0K1l2M3n4O5p6Q7r"
+
+// expectIssuePasswordResetToken はパスワード再設定トークンの保存をモック化する
+func expectIssuePasswordResetToken(ctrl *gomock.Controller, UserId int) *mock_config.MockRedisService {
+	mockRedisService := mock_config.NewMockRedisService(ctrl)
+	mockRedisService.EXPECT().
+		RedisGet(fmt.Sprintf("password_reset_user:%d", UserId)).
+		Return("", fmt.Errorf("キーが存在しません"))
+	mockRedisService.EXPECT().
+		RedisSet(gomock.Any(), common.AnyToStr(UserId), gomock.Any()).
+		Return(nil)
+	mockRedisService.EXPECT().
+		RedisSet(fmt.Sprintf("password_reset_user:%d", UserId), gomock.Any(), gomock.Any()).
+		Return(nil)
+	return mockRedisService
+}
 
-		expectedOk := utils.ResponseData[string]{
-			Result: "サインアウトに成功",
-		}
-		assert.Equal(t, responseBody.Result, expectedOk.Result)
-	})
+// expectPasswordResetTokenUser はパスワード再設定トークンの確認(使用済みにしない)をモック化する
+func expectPasswordResetTokenUser(ctrl *gomock.Controller, token string, UserId int) *mock_config.MockRedisService {
+	mockRedisService := mock_config.NewMockRedisService(ctrl)
+	mockRedisService.EXPECT().
+		RedisGet("password_reset:"+utils.HashLinkToken(token)).
+		Return(common.AnyToStr(UserId), nil)
+	return mockRedisService
+}
+
+// expectConsumePasswordResetToken はパスワード再設定トークンの確認と使用をモック化する
+func expectConsumePasswordResetToken(ctrl *gomock.Controller, token string, UserId int) *mock_config.MockRedisService {
+	mockRedisService := expectPasswordResetTokenUser(ctrl, token, UserId)
+	mockRedisService.EXPECT().
+		RedisGetDel("password_reset:"+utils.HashLinkToken(token)).
+		Return(common.AnyToStr(UserId), nil)
+	mockRedisService.EXPECT().
+		RedisDel(fmt.Sprintf("password_reset_user:%d", UserId)).
+		Return(nil)
+	return mockRedisService
+}
+
+// patchCheckPasswordReuse はパスワードの履歴の確認をモック化する
+func patchCheckPasswordReuse(err error) *Patches {
+	return ApplyMethod(
+		reflect.TypeOf(&models.SignDataFetcher{}),
+		"CheckPasswordReuse",
+		func(_ *models.SignDataFetcher, UserId int, password string) error {
+			return err
+		})
+}
+
+// patchRevokeAllSessions は全てのセッションの失効をモック化する
+func patchRevokeAllSessions(err error) *Patches {
+	return ApplyMethod(
+		reflect.TypeOf(&models.SessionDataFetcher{}),
+		"RevokeOtherSessions",
+		func(_ *models.SessionDataFetcher, UserId int, CurrentSessionId string) ([]string, error) {
+			return []string{}, err
+		})
 }
 
 func TestRegisterEmailCheckNotice(t *testing.T) {
@@ -4056,7 +5310,7 @@ func TestRegisterEmailCheckNotice(t *testing.T) {
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -4090,7 +5344,7 @@ func TestRegisterEmailCheckNotice(t *testing.T) {
 		c.Request.Header.Set("Content-Type", "application/json")
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -4133,7 +5387,7 @@ func TestRegisterEmailCheckNotice(t *testing.T) {
 		defer patches.Reset()
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -4189,7 +5443,7 @@ func TestRegisterEmailCheckNotice(t *testing.T) {
 			UtilsFetcher:         mockUtilsFetcher,
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: mockEmailTemplateService,
-			RedisService:         config.NewRedisManager(),
+			RedisService:         expectIssuePasswordResetToken(ctrl, 1),
 		}
 		fetcher.RegisterEmailCheckNotice(c)
 
@@ -4246,7 +5500,7 @@ func TestRegisterEmailCheckNotice(t *testing.T) {
 			UtilsFetcher:         mockUtilsFetcher,
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: mockEmailTemplateService,
-			RedisService:         config.NewRedisManager(),
+			RedisService:         expectIssuePasswordResetToken(ctrl, 1),
 		}
 		fetcher.RegisterEmailCheckNotice(c)
 
@@ -4303,7 +5557,7 @@ func TestRegisterEmailCheckNotice(t *testing.T) {
 			UtilsFetcher:         mockUtilsFetcher,
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: mockEmailTemplateService,
-			RedisService:         config.NewRedisManager(),
+			RedisService:         expectIssuePasswordResetToken(ctrl, 1),
 		}
 		fetcher.RegisterEmailCheckNotice(c)
 
@@ -4318,12 +5572,117 @@ func TestRegisterEmailCheckNotice(t *testing.T) {
 		}
 		assert.Equal(t, responseBody.Result, expectedOk.Result)
 	})
+
+	t.Run("RegisterEmailCheckNotice 再発行時は以前のリンクを無効にする", func(t *testing.T) {
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().
+			DateTimeStr(gomock.Any(), gomock.Any()).
+			Return("2024年12月2日")
+
+		// リンクには平文のトークンを含め、Redisにはハッシュのみを保存する
+		var link, savedHash string
+		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
+		mockEmailTemplateService.EXPECT().
+			RegisterEmailCheckNoticeTemplate(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(Link, DateTime string) (string, string, error) {
+				link = Link
+				return "件名", "本文", nil
+			})
+		mockUtilsFetcher.EXPECT().
+			SendMail("text@exmaple.com", gomock.Any(), gomock.Any(), true).
+			Return(nil)
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGet("password_reset_user:1").Return("previous_hash", nil)
+		mockRedisService.EXPECT().RedisDel("password_reset:previous_hash").Return(nil)
+		mockRedisService.EXPECT().
+			RedisSet(gomock.Any(), "1", utils.PasswordResetTokenMinutes*time.Minute).
+			DoAndReturn(func(key string, value interface{}, duration time.Duration) error {
+				savedHash = strings.TrimPrefix(key, "password_reset:")
+				return nil
+			})
+		mockRedisService.EXPECT().
+			RedisSet("password_reset_user:1", gomock.Any(), utils.PasswordResetTokenMinutes*time.Minute).
+			Return(nil)
+
+		c.Request = httptest.NewRequest("GET", "/api/register_email_check_notice?user_email=text@exmaple.com", nil)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"GetUserId",
+			func(_ *models.SignDataFetcher, UserEmail string) (int, error) {
+				return 1, nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mockUtilsFetcher,
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: mockEmailTemplateService,
+			RedisService:         mockRedisService,
+		}
+		fetcher.RegisterEmailCheckNotice(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		token := link[strings.Index(link, "token_id=")+len("token_id="):]
+		assert.Len(t, token, 43)
+		assert.Equal(t, utils.HashLinkToken(token), savedHash)
+	})
+
+	t.Run("RegisterEmailCheckNotice トークンの保存に失敗", func(t *testing.T) {
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGet("password_reset_user:1").Return("", fmt.Errorf("キーが存在しません"))
+		mockRedisService.EXPECT().RedisSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("保存エラー"))
+
+		c.Request = httptest.NewRequest("GET", "/api/register_email_check_notice?user_email=text@exmaple.com", nil)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"GetUserId",
+			func(_ *models.SignDataFetcher, UserEmail string) (int, error) {
+				return 1, nil
+			})
+		defer patches.Reset()
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mock_utils.NewMockUtilsFetcher(ctrl),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: mock_templates.NewMockEmailTemplateService(ctrl),
+			RedisService:         mockRedisService,
+		}
+		fetcher.RegisterEmailCheckNotice(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var responseBody utils.ErrorMessageResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, "パスワード再設定トークンの発行に失敗しました。", responseBody.Result)
+	})
 }
 
 func TestNewPasswordUpdate(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 
+	// パスワードの履歴の確認はDBに接続しない
+	reusePatches := patchCheckPasswordReuse(nil)
+	defer reusePatches.Reset()
+
 	t.Run("TestNewPasswordUpdate JSON不正", func(t *testing.T) {
 		// Invalid JSON
 		invalidJSON := `{"data": [`
@@ -4335,7 +5694,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 		)
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -4364,7 +5723,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 		)
 
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
 			RedisService:         config.NewRedisManager(),
@@ -4402,17 +5761,17 @@ func TestNewPasswordUpdate(t *testing.T) {
 
 		dataList := []models.RequestNewPasswordUpdateData{
 			{
-				TokenId:         "token12",
+				TokenId:         testResetToken,
 				NewUserPassword: "Test12345",
 				ConfirmPassword: "Test12345!",
 			},
 			{
-				TokenId:         "token12",
+				TokenId:         testResetToken,
 				NewUserPassword: "Test12345!",
 				ConfirmPassword: "test12345",
 			},
 			{
-				TokenId:         "token12",
+				TokenId:         testResetToken,
 				NewUserPassword: "test12345",
 				ConfirmPassword: "Test12!",
 			},
@@ -4423,7 +5782,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 				Result: []utils.ErrorMessages{
 					{
 						Field:   "new_user_password",
-						Message: "新しいパスワードの形式が間違っています。",
+						Message: "新しいパスワードには記号を1文字以上含めてください。",
 					},
 				},
 			},
@@ -4431,7 +5790,11 @@ func TestNewPasswordUpdate(t *testing.T) {
 				Result: []utils.ErrorMessages{
 					{
 						Field:   "confirm_password",
-						Message: "確認パスワードの形式が間違っています。",
+						Message: "確認パスワードには英大文字を1文字以上含めてください。",
+					},
+					{
+						Field:   "confirm_password",
+						Message: "確認パスワードには記号を1文字以上含めてください。",
 					},
 				},
 			},
@@ -4439,11 +5802,15 @@ func TestNewPasswordUpdate(t *testing.T) {
 				Result: []utils.ErrorMessages{
 					{
 						Field:   "new_user_password",
-						Message: "新しいパスワードの形式が間違っています。",
+						Message: "新しいパスワードには英大文字を1文字以上含めてください。",
+					},
+					{
+						Field:   "new_user_password",
+						Message: "新しいパスワードには記号を1文字以上含めてください。",
 					},
 					{
 						Field:   "confirm_password",
-						Message: "確認パスワードの形式が間違っています。",
+						Message: "確認パスワードは8文字以上で入力してください。",
 					},
 				},
 			},
@@ -4457,7 +5824,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 			)
 
 			fetcher := apiSignDataFetcher{
-				UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+				UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 				CommonFetcher:        common.NewCommonFetcher(),
 				EmailTemplateService: templates.NewEmailTemplateManager(),
 				RedisService:         config.NewRedisManager(),
@@ -4478,7 +5845,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 	t.Run("TestNewPasswordUpdate sql処理で失敗しパスワード更新できない", func(t *testing.T) {
 
 		data := models.RequestNewPasswordUpdateData{
-			TokenId:         "token12",
+			TokenId:         testResetToken,
 			NewUserPassword: "Test12345!",
 			ConfirmPassword: "Test12345!",
 		}
@@ -4492,16 +5859,19 @@ func TestNewPasswordUpdate(t *testing.T) {
 		patches := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"NewPasswordUpdate",
-			func(_ *models.SignDataFetcher, data models.RequestNewPasswordUpdateData) (string, error) {
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestNewPasswordUpdateData) (string, error) {
 				return "", fmt.Errorf("sql更新失敗")
 			})
 		defer patches.Reset()
 
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
 		fetcher := apiSignDataFetcher{
-			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtSecret),
+			UtilsFetcher:         utils.NewUtilsFetcher(utils.JwtKeys),
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
-			RedisService:         config.NewRedisManager(),
+			RedisService:         expectConsumePasswordResetToken(ctrl, testResetToken, 1),
 		}
 		fetcher.NewPasswordUpdate(c)
 
@@ -4520,7 +5890,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 	t.Run("TestNewPasswordUpdate メールテンプレート生成エラー(パスワード再発行メール)", func(t *testing.T) {
 
 		data := models.RequestNewPasswordUpdateData{
-			TokenId:         "token12",
+			TokenId:         testResetToken,
 			NewUserPassword: "Test12345!",
 			ConfirmPassword: "Test12345!",
 		}
@@ -4534,10 +5904,12 @@ func TestNewPasswordUpdate(t *testing.T) {
 		patches := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"NewPasswordUpdate",
-			func(_ *models.SignDataFetcher, data models.RequestNewPasswordUpdateData) (string, error) {
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestNewPasswordUpdateData) (string, error) {
 				return "test@exmaple.com", nil
 			})
 		defer patches.Reset()
+		sessionPatches := patchRevokeAllSessions(nil)
+		defer sessionPatches.Reset()
 
 		// gomock のコントローラを作成
 		ctrl := gomock.NewController(t)
@@ -4545,6 +5917,12 @@ func TestNewPasswordUpdate(t *testing.T) {
 
 		// UtilsFetcher のモックを作成
 		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+
+		// 発行済みのトークンを失効させること
+		mockUtilsFetcher.EXPECT().
+			RevokeUserTokens(gomock.Any()).
+			Return(nil)
+
 		// EmailTemplateService のモックを作成
 		mockEmailTemplateService := mock_templates.NewMockEmailTemplateService(ctrl)
 
@@ -4554,14 +5932,14 @@ func TestNewPasswordUpdate(t *testing.T) {
 			Return("2024年12月2日")
 
 		mockEmailTemplateService.EXPECT().
-			NewPasswordUpdateTemplate(gomock.Any(), gomock.Any()).
+			NewPasswordUpdateTemplate(gomock.Any()).
 			Return("", "", fmt.Errorf("テンプレート生成エラー"))
 
 		fetcher := apiSignDataFetcher{
 			UtilsFetcher:         mockUtilsFetcher,
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: mockEmailTemplateService,
-			RedisService:         config.NewRedisManager(),
+			RedisService:         expectConsumePasswordResetToken(ctrl, testResetToken, 1),
 		}
 		fetcher.NewPasswordUpdate(c)
 
@@ -4579,7 +5957,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 
 	t.Run("TestNewPasswordUpdate メール送信エラー(パスワード再発行メール)", func(t *testing.T) {
 		data := models.RequestNewPasswordUpdateData{
-			TokenId:         "token12",
+			TokenId:         testResetToken,
 			NewUserPassword: "Test12345!",
 			ConfirmPassword: "Test12345!",
 		}
@@ -4593,10 +5971,12 @@ func TestNewPasswordUpdate(t *testing.T) {
 		patches := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"NewPasswordUpdate",
-			func(_ *models.SignDataFetcher, data models.RequestNewPasswordUpdateData) (string, error) {
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestNewPasswordUpdateData) (string, error) {
 				return "test@exmaple.com", nil
 			})
 		defer patches.Reset()
+		sessionPatches := patchRevokeAllSessions(nil)
+		defer sessionPatches.Reset()
 
 		// gomock のコントローラを作成
 		ctrl := gomock.NewController(t)
@@ -4605,6 +5985,11 @@ func TestNewPasswordUpdate(t *testing.T) {
 		// UtilsFetcher のモックを作成
 		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
 
+		// 発行済みのトークンを失効させること
+		mockUtilsFetcher.EXPECT().
+			RevokeUserTokens(gomock.Any()).
+			Return(nil)
+
 		// モックの挙動を定義
 		mockUtilsFetcher.EXPECT().
 			DateTimeStr(gomock.Any(), gomock.Any()).
@@ -4618,7 +6003,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 			UtilsFetcher:         mockUtilsFetcher,
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
-			RedisService:         config.NewRedisManager(),
+			RedisService:         expectConsumePasswordResetToken(ctrl, testResetToken, 1),
 		}
 		fetcher.NewPasswordUpdate(c)
 
@@ -4636,7 +6021,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 
 	t.Run("TestNewPasswordUpdate result 成功", func(t *testing.T) {
 		data := models.RequestNewPasswordUpdateData{
-			TokenId:         "token12",
+			TokenId:         testResetToken,
 			NewUserPassword: "Test12345!",
 			ConfirmPassword: "Test12345!",
 		}
@@ -4650,10 +6035,12 @@ func TestNewPasswordUpdate(t *testing.T) {
 		patches := ApplyMethod(
 			reflect.TypeOf(&models.SignDataFetcher{}),
 			"NewPasswordUpdate",
-			func(_ *models.SignDataFetcher, data models.RequestNewPasswordUpdateData) (string, error) {
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestNewPasswordUpdateData) (string, error) {
 				return "test@exmaple.com", nil
 			})
 		defer patches.Reset()
+		sessionPatches := patchRevokeAllSessions(nil)
+		defer sessionPatches.Reset()
 
 		// gomock のコントローラを作成
 		ctrl := gomock.NewController(t)
@@ -4662,6 +6049,11 @@ func TestNewPasswordUpdate(t *testing.T) {
 		// UtilsFetcher のモックを作成
 		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
 
+		// 発行済みのトークンを失効させること
+		mockUtilsFetcher.EXPECT().
+			RevokeUserTokens(gomock.Any()).
+			Return(nil)
+
 		// モックの挙動を定義
 		mockUtilsFetcher.EXPECT().
 			DateTimeStr(gomock.Any(), gomock.Any()).
@@ -4675,7 +6067,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 			UtilsFetcher:         mockUtilsFetcher,
 			CommonFetcher:        common.NewCommonFetcher(),
 			EmailTemplateService: templates.NewEmailTemplateManager(),
-			RedisService:         config.NewRedisManager(),
+			RedisService:         expectConsumePasswordResetToken(ctrl, testResetToken, 1),
 		}
 		fetcher.NewPasswordUpdate(c)
 
@@ -4690,4 +6082,177 @@ func TestNewPasswordUpdate(t *testing.T) {
 		}
 		assert.Equal(t, responseBody.Result, expectedOk.Result)
 	})
+	t.Run("TestNewPasswordUpdate 直近に使用したパスワードの場合はトークンを使用しない", func(t *testing.T) {
+		data := models.RequestNewPasswordUpdateData{
+			TokenId:         testResetToken,
+			NewUserPassword: "Test12345!",
+			ConfirmPassword: "Test12345!",
+		}
+
+		w, c := test_utils.CreateTestRequest(
+			"PUT", "/api/new_password_update",
+			data,
+			map[string]string{},
+		)
+
+		var checkedUserId int
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"CheckPasswordReuse",
+			func(_ *models.SignDataFetcher, UserId int, password string) error {
+				checkedUserId = UserId
+				return models.ErrPasswordReused
+			})
+		defer patches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// トークンの確認のみ行い、使用済みにしない(有効期限も延長しない)
+		mockRedisService := expectPasswordResetTokenUser(ctrl, testResetToken, 1)
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mock_utils.NewMockUtilsFetcher(ctrl),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mockRedisService,
+		}
+		fetcher.NewPasswordUpdate(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, 1, checkedUserId)
+
+		var responseBody utils.ErrorValidationResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, []utils.ErrorMessages{{
+			Field:   "new_user_password",
+			Message: "新しいパスワードは直近5回に使用したパスワード以外を指定してください。",
+		}}, responseBody.Result)
+	})
+
+	t.Run("TestNewPasswordUpdate 確認用のパスワードが一致しない場合はトークンを使用しない", func(t *testing.T) {
+		data := models.RequestNewPasswordUpdateData{
+			TokenId:         testResetToken,
+			NewUserPassword: "Test12345!",
+			ConfirmPassword: "Test12345?",
+		}
+
+		w, c := test_utils.CreateTestRequest(
+			"PUT", "/api/new_password_update",
+			data,
+			map[string]string{},
+		)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mock_utils.NewMockUtilsFetcher(ctrl),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mock_config.NewMockRedisService(ctrl),
+		}
+		fetcher.NewPasswordUpdate(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var responseBody utils.ErrorMessageResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, "新しいパスワードと確認用のパスワードが一致しませんでした。", responseBody.Result)
+	})
+
+	t.Run("TestNewPasswordUpdate 発行されていない又は使用済みのトークン", func(t *testing.T) {
+		// 以前の形式(uuid + user_id)のトークンも受け付けない
+		data := models.RequestNewPasswordUpdateData{
+			TokenId:         "b2781af7-794a-1871-9865-bdc3c19291ff1",
+			NewUserPassword: "Test12345!",
+			ConfirmPassword: "Test12345!",
+		}
+
+		w, c := test_utils.CreateTestRequest(
+			"PUT", "/api/new_password_update",
+			data,
+			map[string]string{},
+		)
+
+		updated := false
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"NewPasswordUpdate",
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestNewPasswordUpdateData) (string, error) {
+				updated = true
+				return "test@exmaple.com", nil
+			})
+		defer patches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisGet("password_reset:"+utils.HashLinkToken(data.TokenId)).
+			Return("", fmt.Errorf("キーが存在しません"))
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mock_utils.NewMockUtilsFetcher(ctrl),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mockRedisService,
+		}
+		fetcher.NewPasswordUpdate(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.False(t, updated)
+
+		var responseBody utils.ErrorMessageResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, "パスワード再設定のリンクが無効か有効期限が切れています。", responseBody.Result)
+	})
+
+	t.Run("TestNewPasswordUpdate セッションの失効に失敗", func(t *testing.T) {
+		data := models.RequestNewPasswordUpdateData{
+			TokenId:         testResetToken,
+			NewUserPassword: "Test12345!",
+			ConfirmPassword: "Test12345!",
+		}
+
+		w, c := test_utils.CreateTestRequest(
+			"PUT", "/api/new_password_update",
+			data,
+			map[string]string{},
+		)
+
+		var updatedUserId int
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"NewPasswordUpdate",
+			func(_ *models.SignDataFetcher, UserId int, data models.RequestNewPasswordUpdateData) (string, error) {
+				updatedUserId = UserId
+				return "test@exmaple.com", nil
+			})
+		defer patches.Reset()
+		sessionPatches := patchRevokeAllSessions(fmt.Errorf("クエリー実行エラー"))
+		defer sessionPatches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().RevokeUserTokens(2).Return(nil)
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mockUtilsFetcher,
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         expectConsumePasswordResetToken(ctrl, testResetToken, 2),
+		}
+		fetcher.NewPasswordUpdate(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, 2, updatedUserId)
+
+		var responseBody utils.ErrorMessageResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, "セッションの失効に失敗しました。", responseBody.Result)
+	})
 }
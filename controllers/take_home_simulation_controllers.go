@@ -0,0 +1,109 @@
+// controllers/take_home_simulation_controllers.go
+package controllers
+
+import (
+	"net/http"
+	"server/common"
+	"server/tax"
+	"server/utils"
+	"server/validation"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	TakeHomeSimulationFetcher interface {
+		GetTakeHomeSimulationApi(c *gin.Context)
+	}
+
+	apiTakeHomeSimulationFetcher struct {
+		CommonFetcher common.CommonFetcher
+	}
+)
+
+func NewTakeHomeSimulationFetcher(CommonFetcher common.CommonFetcher) TakeHomeSimulationFetcher {
+	return &apiTakeHomeSimulationFetcher{
+		CommonFetcher: CommonFetcher,
+	}
+}
+
+// GetTakeHomeSimulationApi は額面の月給・賞与から手取り額を試算するAPI
+// 社会保険料は標準報酬月額と都道府県別の健康保険料率、源泉徴収税額は電子計算機特例で算出する。
+// 料率表は年度毎にサーバーに同梱しており、year 未指定の場合は今年の表を使う。
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+// 期待するURL:
+//
+//	GET /take_home_simulation?monthly_salary=300000&bonus=1200000&bonus_times=2&age=30&prefecture=東京都&dependents=0
+//
+
+func (th *apiTakeHomeSimulationFetcher) GetTakeHomeSimulationApi(c *gin.Context) {
+
+	validator := validation.RequestTakeHomeSimulationData{
+		MonthlySalary: c.Query("monthly_salary"),
+		Bonus:         c.Query("bonus"),
+		BonusTimes:    c.Query("bonus_times"),
+		Age:           c.Query("age"),
+		Prefecture:    c.Query("prefecture"),
+		Dependents:    c.Query("dependents"),
+		Year:          c.Query("year"),
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	data, err := th.CommonFetcher.IntgetPrameter(c, "monthly_salary", "bonus", "age", "dependents")
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	bonusTimes, _ := strconv.Atoi(c.DefaultQuery("bonus_times", "2"))
+	year, _ := strconv.Atoi(c.DefaultQuery("year", strconv.Itoa(time.Now().Year())))
+
+	table, err := tax.LookupRateTable(year)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	result, err := table.CalcTakeHome(tax.TakeHomeInput{
+		MonthlySalary: data["monthly_salary"],
+		AnnualBonus:   data["bonus"],
+		BonusTimes:    bonusTimes,
+		Age:           data["age"],
+		Prefecture:    validator.Prefecture,
+		Dependents:    data["dependents"],
+	})
+	if err != nil {
+		response := utils.ErrorValidationResponse{
+			Result: []utils.ErrorMessages{
+				{
+					Field:   "prefecture",
+					Message: err.Error(),
+				},
+			},
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := utils.ResponseData[tax.TakeHomeResult]{
+		Result: result,
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"server/common"
+	"server/tax"
+	"server/utils"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTakeHomeSimulationApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success GetTakeHomeSimulationApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?monthly_salary=300000&bonus=600000&age=30&year=2024&prefecture="+url.QueryEscape("東京都"), nil)
+
+		fetcher := apiTakeHomeSimulationFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetTakeHomeSimulationApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response utils.ResponseData[tax.TakeHomeResult]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 2024, response.Result.Year)
+		assert.Equal(t, 44220, response.Result.Monthly.SocialInsurance.Total)
+		assert.Equal(t, 6760, response.Result.Monthly.IncomeTax)
+		// 賞与の支給回数は未指定の場合2回
+		assert.Equal(t, 2, response.Result.Bonus.Times)
+		assert.Equal(t, 300000, response.Result.Bonus.Gross)
+		assert.Equal(t, 4200000, response.Result.Annual.Gross)
+	})
+
+	t.Run("error GetTakeHomeSimulationApi 都道府県が不正", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?monthly_salary=300000&age=30&prefecture="+url.QueryEscape("東京"), nil)
+
+		fetcher := apiTakeHomeSimulationFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetTakeHomeSimulationApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, []utils.ErrorMessages{
+			{Field: "prefecture", Message: "都道府県が正しくありません。"},
+		}, response.Result)
+	})
+
+	t.Run("error GetTakeHomeSimulationApi 料率表が存在しない年度", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?monthly_salary=300000&age=30&year=2000&prefecture="+url.QueryEscape("東京都"), nil)
+
+		fetcher := apiTakeHomeSimulationFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetTakeHomeSimulationApi(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		var response utils.ErrorMessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "2000年度の料率表が存在しません。", response.Result)
+	})
+
+	t.Run("バリデーションエラー GetTakeHomeSimulationApi", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/?monthly_salary=abc&bonus_times=13&age=10&dependents=11", nil)
+
+		fetcher := apiTakeHomeSimulationFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+		}
+		fetcher.GetTakeHomeSimulationApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response utils.ErrorValidationResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []utils.ErrorMessages{
+			{Field: "monthly_salary", Message: "額面の月給は整数値のみです。"},
+			{Field: "prefecture", Message: "都道府県は必須です。"},
+			{Field: "bonus_times", Message: "賞与の支給回数は1以上12以下の整数値のみです。"},
+			{Field: "age", Message: "年齢は15以上100以下の整数値のみです。"},
+			{Field: "dependents", Message: "扶養親族の数は0以上10以下の整数値のみです。"},
+		}, response.Result)
+	})
+}
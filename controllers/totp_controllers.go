@@ -0,0 +1,587 @@
+// controllers/totp_controllers.go
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"server/common"
+	"server/config"
+	"server/enum"
+	"server/models"
+	"server/utils"
+	"server/validation"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	TotpManagementFetcher interface {
+		TotpEnrollApi(c *gin.Context)
+		TotpVerifyApi(c *gin.Context)
+		TotpDisableApi(c *gin.Context)
+	}
+
+	requestTotpUserData struct {
+		UserId interface{} `json:"user_id"`
+	}
+
+	requestTotpCodeData struct {
+		UserId interface{} `json:"user_id"`
+		Code   string      `json:"code"`
+	}
+
+	RequestSignInTotpData struct {
+		ChallengeId string `json:"challenge_id"`
+		Code        string `json:"code"`
+	}
+
+	TotpEnrollResult struct {
+		Secret     string `json:"secret"`
+		OtpauthUri string `json:"otpauth_uri"`
+	}
+
+	TotpVerifyResult struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+
+	// TotpChallengeResult はパスワードの確認後、ワンタイムパスワードの入力を求めるレスポンス
+	TotpChallengeResult struct {
+		TwoFactorRequired bool   `json:"two_factor_required"`
+		ChallengeId       string `json:"challenge_id"`
+		ExpiresIn         int    `json:"expires_in"`
+	}
+
+	// totpChallenge はパスワード(又はマジックリンク)の確認が済んだユーザーをRedisに保存する
+	totpChallenge struct {
+		UserId    int    `json:"user_id"`
+		UserEmail string `json:"user_email"`
+		// サインイン方法(未設定の場合はパスワード)
+		SignInType string `json:"sign_in_type,omitempty"`
+	}
+
+	apiTotpManagementFetcher struct {
+		CommonFetcher  common.CommonFetcher
+		RedisService   config.RedisService
+		AttemptLimiter utils.AttemptLimiter
+	}
+)
+
+// ワンタイムパスワードの入力を待つ時間
+const totpChallengeDuration = 5 * time.Minute
+
+const totpAttemptsMessage = "二段階認証の試行回数が多すぎます。しばらく時間をおいて再度お試しください。"
+
+func NewTotpManagementFetcher(
+	CommonFetcher common.CommonFetcher,
+	RedisService config.RedisService,
+	AttemptLimiter utils.AttemptLimiter,
+) TotpManagementFetcher {
+	return &apiTotpManagementFetcher{
+		CommonFetcher:  CommonFetcher,
+		RedisService:   RedisService,
+		AttemptLimiter: AttemptLimiter,
+	}
+}
+
+// totpChallengeKey はサインイン途中のチャレンジを保存するRedisのキー
+func totpChallengeKey(challengeId string) string {
+	return fmt.Sprintf("totp_challenge:%s", challengeId)
+}
+
+// totpLastStepKey は最後に使用したワンタイムパスワードのステップを保存するRedisのキー
+func totpLastStepKey(userId int) string {
+	return fmt.Sprintf("totp_last_step:%d", userId)
+}
+
+// consumeTotpStep はワンタイムパスワードのステップを使用済みにする
+// 同じコード(又はそれより前のコード)が再度使われた場合はfalseを返す
+// 同時に同じコードが送られた場合も1回のみ成功するよう、比較と更新はRedis上でまとめて行う
+func consumeTotpStep(redisService config.RedisService, userId int, step int64) (bool, error) {
+	// 許容するずれの範囲を過ぎたステップは再利用できないため、その間のみ保持する
+	duration := time.Duration((2*utils.TotpSkew+1)*utils.TotpPeriod) * time.Second
+	return redisService.RedisSetIfGreater(totpLastStepKey(userId), step, duration)
+}
+
+// verifyTotpCode はワンタイムパスワードを検証し、使用済みにする
+func verifyTotpCode(redisService config.RedisService, totp models.TotpData, code string) (bool, error) {
+	step, ok := utils.ValidateTotp(totp.Secret, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+	return consumeTotpStep(redisService, totp.UserId, step)
+}
+
+// verifySecondFactor はワンタイムパスワード又はリカバリーコードを検証する
+// リカバリーコードは一致した場合に使用済みにする
+//
+// 引数:
+//   - redisService: 使用済みのワンタイムパスワードを記録するRedis
+//   - totp: ユーザーの二段階認証の設定
+//   - code: 入力されたワンタイムパスワード又はリカバリーコード
+//
+// 戻り値:
+//
+//	戻り値1: 一致した場合はtrue
+//	戻り値2: Redisの参照に失敗した場合のエラー
+//
+
+func verifySecondFactor(redisService config.RedisService, totp models.TotpData, code string) (bool, error) {
+	if len(code) == utils.TotpDigits {
+		return verifyTotpCode(redisService, totp, code)
+	}
+
+	dbFetcher, _, _ := models.NewTotpDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.UseRecoveryCode(totp.UserId, utils.HashRecoveryCode(code)); err != nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// issueTotpChallenge はパスワード・マジックリンク又は外部認証の確認が済んだユーザーにワンタイムパスワードの入力を求める
+// クッキーは設定せず、PostSignInTotpApi でサインインを完了する
+func (af *apiSignDataFetcher) issueTotpChallenge(c *gin.Context, result models.SignInData, signInType string) {
+	challengeId := uuid.New().String()
+	challenge := totpChallenge{
+		UserId:     result.UserId,
+		UserEmail:  result.UserEmail,
+		SignInType: signInType,
+	}
+	if err := af.RedisService.RedisSet(totpChallengeKey(challengeId), challenge, totpChallengeDuration); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "二段階認証の開始に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[TotpChallengeResult]{
+		Result: TotpChallengeResult{
+			TwoFactorRequired: true,
+			ChallengeId:       challengeId,
+			ExpiresIn:         int(totpChallengeDuration.Seconds()),
+		},
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// PostSignInTotpApi は二段階認証のワンタイムパスワード(又はリカバリーコード)を確認してサインインを完了するAPI
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (af *apiSignDataFetcher) PostSignInTotpApi(c *gin.Context) {
+	var requestData RequestSignInTotpData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	validator := validation.RequestSignInTotpData{
+		ChallengeId: requestData.ChallengeId,
+		Code:        requestData.Code,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	challengeKey := totpChallengeKey(requestData.ChallengeId)
+	value, err := af.RedisService.RedisGet(challengeKey)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "二段階認証の有効期限が切れています。再度サインインしてください。",
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+	var challenge totpChallenge
+	if err := json.Unmarshal([]byte(value), &challenge); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "二段階認証の有効期限が切れています。再度サインインしてください。",
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	attemptKey := utils.TotpAttemptKey(challenge.UserId)
+	if !af.checkAttempts(c, totpAttemptsMessage, attemptKey) {
+		return
+	}
+
+	totpFetcher, _, _ := models.NewTotpDataFetcher(config.GetDataBaseSource())
+	totp, err := totpFetcher.GetTotp(challenge.UserId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "二段階認証の設定の取得に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	ok, err := verifySecondFactor(af.RedisService, totp, requestData.Code)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "認証コードの確認に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	if !ok {
+		statuses, err := af.recordFailedAttempts(attemptKey)
+		if err != nil {
+			response := utils.ErrorMessageResponse{
+				Result: "試行回数の記録に失敗しました。",
+			}
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+		if wait, locked := lockedWait(statuses); locked {
+			// ロックされた場合はパスワードの確認からやり直してもらう
+			_ = af.RedisService.RedisDel(challengeKey)
+			tooManyAttempts(c, wait, totpAttemptsMessage)
+			return
+		}
+
+		response := utils.ErrorMessageResponse{
+			Result: "認証コードが間違っています。",
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	// 同時に送信された場合も1回のみサインインできるよう、取得と削除を1回の操作で行う
+	if _, err := af.RedisService.RedisGetDel(challengeKey); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "二段階認証の有効期限が切れています。再度サインインしてください。",
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+	_ = af.AttemptLimiter.Reset(attemptKey)
+
+	signInType := challenge.SignInType
+	if signInType == "" {
+		signInType = enum.SIGN_IN_PASSWORD
+	}
+	af.completeSignIn(c, models.SignInData{
+		UserId:    challenge.UserId,
+		UserEmail: challenge.UserEmail,
+	}, signInType)
+}
+
+// TotpEnrollApi は二段階認証の登録を開始し、シークレットと otpauth URI を返すAPI
+// 認証アプリで読み込んだ後、TotpVerifyApi でワンタイムパスワードを確認して有効にする
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (tm *apiTotpManagementFetcher) TotpEnrollApi(c *gin.Context) {
+	var requestData requestTotpUserData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userIdPrams := common.AnyToStr(requestData.UserId)
+	validator := validation.RequestTotpUserData{
+		UserId: userIdPrams,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, _ := tm.CommonFetcher.StrToInt(userIdPrams)
+	if !isSignInUser(c, userId) {
+		signInUserMismatch(c)
+		return
+	}
+
+	dbFetcher, _, _ := models.NewTotpDataFetcher(config.GetDataBaseSource())
+	totp, err := dbFetcher.GetTotp(userId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	if !totp.PasswordAccount {
+		response := utils.ErrorMessageResponse{
+			Result: "外部認証で登録したユーザーは二段階認証を利用できません。",
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	if totp.Enabled {
+		response := utils.ErrorMessageResponse{
+			Result: "二段階認証は既に有効です。",
+		}
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+
+	secret, err := utils.GenerateTotpSecret()
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	dbFetcher, _, _ = models.NewTotpDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.UpsertTotpSecret(userId, secret); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[TotpEnrollResult]{
+		Result: TotpEnrollResult{
+			Secret:     secret,
+			OtpauthUri: utils.TotpURI(secret, totp.UserEmail),
+		},
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// TotpVerifyApi は認証アプリのワンタイムパスワードを確認して二段階認証を有効にするAPI
+// リカバリーコードはこのレスポンスでのみ返す
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (tm *apiTotpManagementFetcher) TotpVerifyApi(c *gin.Context) {
+	var requestData requestTotpCodeData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userIdPrams := common.AnyToStr(requestData.UserId)
+	validator := validation.RequestTotpVerifyData{
+		UserId: userIdPrams,
+		Code:   requestData.Code,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, _ := tm.CommonFetcher.StrToInt(userIdPrams)
+	if !isSignInUser(c, userId) {
+		signInUserMismatch(c)
+		return
+	}
+
+	dbFetcher, _, _ := models.NewTotpDataFetcher(config.GetDataBaseSource())
+	totp, err := dbFetcher.GetTotp(userId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	if totp.Enabled {
+		response := utils.ErrorMessageResponse{
+			Result: "二段階認証は既に有効です。",
+		}
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+	if totp.Secret == "" {
+		response := utils.ErrorMessageResponse{
+			Result: "二段階認証の登録が開始されていません。",
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	ok, err := verifyTotpCode(tm.RedisService, totp, requestData.Code)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "ワンタイムパスワードの確認に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	if !ok {
+		response := utils.ErrorMessageResponse{
+			Result: "ワンタイムパスワードが間違っています。",
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	recoveryCodes, err := utils.GenerateRecoveryCodes()
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	codeHashes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		codeHashes = append(codeHashes, utils.HashRecoveryCode(code))
+	}
+
+	dbFetcher, _, _ = models.NewTotpDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.EnableTotp(userId, codeHashes); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := utils.ResponseData[TotpVerifyResult]{
+		Result: TotpVerifyResult{
+			RecoveryCodes: recoveryCodes,
+		},
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// TotpDisableApi はワンタイムパスワード(又はリカバリーコード)を確認して二段階認証を無効にするAPI
+//
+// 引数:
+//   - c: Ginコンテキスト
+//
+
+func (tm *apiTotpManagementFetcher) TotpDisableApi(c *gin.Context) {
+	var requestData requestTotpCodeData
+	if err := c.ShouldBindJSON(&requestData); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userIdPrams := common.AnyToStr(requestData.UserId)
+	validator := validation.RequestTotpDisableData{
+		UserId: userIdPrams,
+		Code:   requestData.Code,
+	}
+
+	if valid, errMsgList := validator.Validate(); !valid {
+		response := utils.ErrorValidationResponse{
+			Result: errMsgList,
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userId, _ := tm.CommonFetcher.StrToInt(userIdPrams)
+	if !isSignInUser(c, userId) {
+		signInUserMismatch(c)
+		return
+	}
+
+	attemptKey := utils.TotpAttemptKey(userId)
+	wait, err := tm.AttemptLimiter.Check(attemptKey)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "試行回数の確認に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	if wait > 0 {
+		tooManyAttempts(c, wait, totpAttemptsMessage)
+		return
+	}
+
+	dbFetcher, _, _ := models.NewTotpDataFetcher(config.GetDataBaseSource())
+	totp, err := dbFetcher.GetTotp(userId)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	if !totp.Enabled {
+		response := utils.ErrorMessageResponse{
+			Result: "二段階認証は有効になっていません。",
+		}
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	ok, err := verifySecondFactor(tm.RedisService, totp, requestData.Code)
+	if err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: "認証コードの確認に失敗しました。",
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	if !ok {
+		status, err := tm.AttemptLimiter.Fail(attemptKey)
+		if err != nil {
+			response := utils.ErrorMessageResponse{
+				Result: "試行回数の記録に失敗しました。",
+			}
+			c.JSON(http.StatusInternalServerError, response)
+			return
+		}
+		if status.Locked {
+			tooManyAttempts(c, status.Wait, totpAttemptsMessage)
+			return
+		}
+
+		response := utils.ErrorMessageResponse{
+			Result: "認証コードが間違っています。",
+		}
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	dbFetcher, _, _ = models.NewTotpDataFetcher(config.GetDataBaseSource())
+	if err := dbFetcher.DisableTotp(userId); err != nil {
+		response := utils.ErrorMessageResponse{
+			Result: err.Error(),
+		}
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	_ = tm.AttemptLimiter.Reset(attemptKey)
+
+	response := utils.ResponseData[string]{
+		Result: "二段階認証を無効にしました。",
+	}
+	c.JSON(http.StatusOK, response)
+}
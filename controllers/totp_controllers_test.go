@@ -0,0 +1,744 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"server/common"
+	"server/enum"
+	mock_config "server/mock/config"
+	mock_limiter "server/mock/limiter"
+	mock_utils "server/mock/utils"
+	"server/models"
+	"server/templates"
+	"server/utils"
+	"testing"
+	"time"
+
+	. "github.com/agiledragon/gomonkey/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTotpSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+const testChallengeId = "8df939de-5a97-4f20-b41b-9ac355c16e36"
+
+// patchGetTotp はユーザーの二段階認証の設定の取得をモック化する
+func patchGetTotp(data models.TotpData, err error) *Patches {
+	return ApplyMethod(
+		reflect.TypeOf(&models.TotpDataFetcher{}),
+		"GetTotp",
+		func(_ *models.TotpDataFetcher, UserId int) (models.TotpData, error) {
+			return data, err
+		})
+}
+
+// enabledTotp は二段階認証が有効なユーザーの設定
+func enabledTotp() models.TotpData {
+	return models.TotpData{
+		UserId:          3,
+		UserEmail:       "test@example.com",
+		PasswordAccount: true,
+		Secret:          testTotpSecret,
+		Enabled:         true,
+	}
+}
+
+// expectUnusedTotpStep はワンタイムパスワードが未使用であることをモック化する
+func expectUnusedTotpStep(mockRedisService *mock_config.MockRedisService) {
+	mockRedisService.EXPECT().RedisSetIfGreater("totp_last_step:3", gomock.Any(), 90*time.Second).Return(true, nil)
+}
+
+func totpRequest(c *gin.Context, method, path string, body interface{}) {
+	data, _ := json.Marshal(body)
+	c.Request = httptest.NewRequest(method, path, bytes.NewBuffer(data))
+	c.Request.Header.Set("Content-Type", "application/json")
+}
+
+func TestPostSignInApiTotpChallenge(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("TestPostSignInApi 二段階認証が有効な場合はチャレンジを返す", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		signInRequest(c)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"GetSignIn",
+			func(_ *models.SignDataFetcher, data models.RequestSignInData) (models.SignInData, error) {
+				return models.SignInData{UserId: 3, UserEmail: "test@example.com"}, nil
+			})
+		defer patches.Reset()
+		totpPatches := patchGetTotp(enabledTotp(), nil)
+		defer totpPatches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisSet(gomock.Any(), totpChallenge{UserId: 3, UserEmail: "test@example.com", SignInType: enum.SIGN_IN_PASSWORD}, 5*time.Minute).
+			Return(nil)
+
+		// トークンは発行しない
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mock_utils.NewMockUtilsFetcher(ctrl),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
+		}
+		fetcher.PostSignInApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Result().Cookies())
+
+		var responseBody utils.ResponseData[TotpChallengeResult]
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.True(t, responseBody.Result.TwoFactorRequired)
+		assert.Len(t, responseBody.Result.ChallengeId, utils.Uuid)
+		assert.Equal(t, 300, responseBody.Result.ExpiresIn)
+	})
+
+	t.Run("TestPostSignInApi 二段階認証の設定の取得に失敗", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		signInRequest(c)
+
+		patches := ApplyMethod(
+			reflect.TypeOf(&models.SignDataFetcher{}),
+			"GetSignIn",
+			func(_ *models.SignDataFetcher, data models.RequestSignInData) (models.SignInData, error) {
+				return models.SignInData{UserId: 3, UserEmail: "test@example.com"}, nil
+			})
+		defer patches.Reset()
+		totpPatches := patchGetTotp(models.TotpData{}, fmt.Errorf("クエリー実行エラー"))
+		defer totpPatches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mock_utils.NewMockUtilsFetcher(ctrl),
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mock_config.NewMockRedisService(ctrl),
+			AttemptLimiter:       allowAllAttempts(t),
+		}
+		fetcher.PostSignInApi(c)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestConsumeTotpStep(t *testing.T) {
+
+	t.Run("consumeTotpStep 前回より後のステップのみ使用済みにする", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// 比較と更新はRedis上でまとめて行うこと
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisSetIfGreater("totp_last_step:3", int64(100), 90*time.Second).
+			Return(true, nil)
+		mockRedisService.EXPECT().
+			RedisSetIfGreater("totp_last_step:3", int64(100), 90*time.Second).
+			Return(false, nil)
+
+		consumed, err := consumeTotpStep(mockRedisService, 3, 100)
+		assert.NoError(t, err)
+		assert.True(t, consumed)
+
+		consumed, err = consumeTotpStep(mockRedisService, 3, 100)
+		assert.NoError(t, err)
+		assert.False(t, consumed)
+	})
+
+	t.Run("consumeTotpStep Redisエラー", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisSetIfGreater("totp_last_step:3", int64(100), 90*time.Second).
+			Return(false, fmt.Errorf("更新エラー"))
+
+		consumed, err := consumeTotpStep(mockRedisService, 3, 100)
+		assert.Error(t, err)
+		assert.False(t, consumed)
+	})
+}
+
+func TestPostSignInTotpApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	challengeKey := "totp_challenge:" + testChallengeId
+	challengeValue := `{"user_id":3,"user_email":"test@example.com"}`
+
+	t.Run("PostSignInTotpApi バリデーション 認証コード不正", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		totpRequest(c, "POST", "/api/signin_totp", RequestSignInTotpData{
+			ChallengeId: testChallengeId,
+			Code:        "12345",
+		})
+
+		fetcher := apiSignDataFetcher{}
+		fetcher.PostSignInTotpApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var responseBody utils.ErrorValidationResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, []utils.ErrorMessages{
+			{Field: "code", Message: "認証コードの形式が間違っています。"},
+		}, responseBody.Result)
+	})
+
+	t.Run("PostSignInTotpApi チャレンジの有効期限切れ", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		totpRequest(c, "POST", "/api/signin_totp", RequestSignInTotpData{
+			ChallengeId: testChallengeId,
+			Code:        "123456",
+		})
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGet(challengeKey).Return("", fmt.Errorf("キーが存在しません"))
+
+		fetcher := apiSignDataFetcher{
+			RedisService: mockRedisService,
+		}
+		fetcher.PostSignInTotpApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var responseBody utils.ErrorMessageResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, "二段階認証の有効期限が切れています。再度サインインしてください。", responseBody.Result)
+	})
+
+	t.Run("PostSignInTotpApi ワンタイムパスワードが間違っている", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		code, _ := utils.TotpCode(testTotpSecret, time.Now().Add(-10*time.Minute))
+		totpRequest(c, "POST", "/api/signin_totp", RequestSignInTotpData{
+			ChallengeId: testChallengeId,
+			Code:        code,
+		})
+
+		totpPatches := patchGetTotp(enabledTotp(), nil)
+		defer totpPatches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGet(challengeKey).Return(challengeValue, nil)
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(utils.TotpAttemptKey(3)).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().
+			Fail(utils.TotpAttemptKey(3)).
+			Return(utils.AttemptStatus{Failures: 1, Wait: time.Second}, nil)
+
+		fetcher := apiSignDataFetcher{
+			RedisService:   mockRedisService,
+			AttemptLimiter: mockAttemptLimiter,
+		}
+		fetcher.PostSignInTotpApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var responseBody utils.ErrorMessageResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, "認証コードが間違っています。", responseBody.Result)
+	})
+
+	t.Run("PostSignInTotpApi 失敗回数が上限に達しチャレンジを破棄", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		totpRequest(c, "POST", "/api/signin_totp", RequestSignInTotpData{
+			ChallengeId: testChallengeId,
+			Code:        "abcde-fghij",
+		})
+
+		totpPatches := patchGetTotp(enabledTotp(), nil)
+		defer totpPatches.Reset()
+		totpPatches.ApplyMethod(
+			reflect.TypeOf(&models.TotpDataFetcher{}),
+			"UseRecoveryCode",
+			func(_ *models.TotpDataFetcher, UserId int, CodeHash string) error {
+				return fmt.Errorf("対象のリカバリーコードが存在しません。")
+			})
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGet(challengeKey).Return(challengeValue, nil)
+		mockRedisService.EXPECT().RedisDel(challengeKey).Return(nil)
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(utils.TotpAttemptKey(3)).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().
+			Fail(utils.TotpAttemptKey(3)).
+			Return(utils.AttemptStatus{Failures: 5, Wait: 30 * time.Minute, Locked: true, JustLocked: true}, nil)
+
+		fetcher := apiSignDataFetcher{
+			RedisService:   mockRedisService,
+			AttemptLimiter: mockAttemptLimiter,
+		}
+		fetcher.PostSignInTotpApi(c)
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "1800", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("PostSignInTotpApi 使用済みのワンタイムパスワード", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		code, _ := utils.TotpCode(testTotpSecret, time.Now())
+		totpRequest(c, "POST", "/api/signin_totp", RequestSignInTotpData{
+			ChallengeId: testChallengeId,
+			Code:        code,
+		})
+
+		totpPatches := patchGetTotp(enabledTotp(), nil)
+		defer totpPatches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// 現在のステップは使用済み
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGet(challengeKey).Return(challengeValue, nil)
+		mockRedisService.EXPECT().
+			RedisSetIfGreater("totp_last_step:3", gomock.Any(), 90*time.Second).
+			Return(false, nil)
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(utils.TotpAttemptKey(3)).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().
+			Fail(utils.TotpAttemptKey(3)).
+			Return(utils.AttemptStatus{Failures: 1, Wait: time.Second}, nil)
+
+		fetcher := apiSignDataFetcher{
+			RedisService:   mockRedisService,
+			AttemptLimiter: mockAttemptLimiter,
+		}
+		fetcher.PostSignInTotpApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("PostSignInTotpApi ワンタイムパスワードでサインイン成功", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		code, _ := utils.TotpCode(testTotpSecret, time.Now())
+		totpRequest(c, "POST", "/api/signin_totp", RequestSignInTotpData{
+			ChallengeId: testChallengeId,
+			Code:        code,
+		})
+
+		totpPatches := patchGetTotp(enabledTotp(), nil)
+		defer totpPatches.Reset()
+		sessionPatches := patchInsertSession(nil)
+		defer sessionPatches.Reset()
+		rolePatches := patchGetUserRole(models.UserRoleData{Role: enum.ROLE_USER}, nil)
+		defer rolePatches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGet(challengeKey).Return(challengeValue, nil)
+		expectUnusedTotpStep(mockRedisService)
+		mockRedisService.EXPECT().RedisGetDel(challengeKey).Return(challengeValue, nil)
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(utils.TotpAttemptKey(3)).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().Reset(utils.TotpAttemptKey(3)).Return(nil)
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().NewToken(3, gomock.Any(), gomock.Any(), utils.AuthTokenHour).Return("new_token", nil)
+		mockUtilsFetcher.EXPECT().RefreshToken(3, gomock.Any(), utils.RefreshAuthTokenHour).Return("refresh_token", nil)
+		mockUtilsFetcher.EXPECT().DateTimeStr(gomock.Any(), gomock.Any()).Return("2024年12月2日")
+		mockUtilsFetcher.EXPECT().SendMail("test@example.com", gomock.Any(), gomock.Any(), true).Return(nil)
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mockUtilsFetcher,
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mockRedisService,
+			AttemptLimiter:       mockAttemptLimiter,
+		}
+		fetcher.PostSignInTotpApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		cookies := map[string]string{}
+		for _, cookie := range w.Result().Cookies() {
+			cookies[cookie.Name] = cookie.Value
+		}
+		assert.Equal(t, "new_token", cookies[utils.AuthToken])
+		assert.Equal(t, "refresh_token", cookies[utils.RefreshAuthToken])
+
+		var responseBody utils.ResponseData[SignInResult]
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, 3, responseBody.Result.UserId)
+		assert.Equal(t, "test@example.com", responseBody.Result.UserEmail)
+	})
+
+	t.Run("PostSignInTotpApi 同時に送信された場合は1回のみサインインできる", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		code, _ := utils.TotpCode(testTotpSecret, time.Now())
+		totpRequest(c, "POST", "/api/signin_totp", RequestSignInTotpData{
+			ChallengeId: testChallengeId,
+			Code:        code,
+		})
+
+		totpPatches := patchGetTotp(enabledTotp(), nil)
+		defer totpPatches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGet(challengeKey).Return(challengeValue, nil)
+		expectUnusedTotpStep(mockRedisService)
+		// 先に完了したリクエストがチャレンジを削除済み
+		mockRedisService.EXPECT().RedisGetDel(challengeKey).Return("", fmt.Errorf("キーが存在しません"))
+
+		// トークンは発行しない
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:   mockUtilsFetcher,
+			RedisService:   mockRedisService,
+			AttemptLimiter: allowAllAttempts(t),
+		}
+		fetcher.PostSignInTotpApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assertErrorMessage(t, w, "二段階認証の有効期限が切れています。再度サインインしてください。")
+		assert.Nil(t, responseCookie(w, utils.AuthToken))
+	})
+
+	t.Run("PostSignInTotpApi リカバリーコードでサインイン成功", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		totpRequest(c, "POST", "/api/signin_totp", RequestSignInTotpData{
+			ChallengeId: testChallengeId,
+			Code:        "ABCDE-FGHIJ",
+		})
+
+		var usedHash string
+		totpPatches := patchGetTotp(enabledTotp(), nil)
+		defer totpPatches.Reset()
+		totpPatches.ApplyMethod(
+			reflect.TypeOf(&models.TotpDataFetcher{}),
+			"UseRecoveryCode",
+			func(_ *models.TotpDataFetcher, UserId int, CodeHash string) error {
+				usedHash = CodeHash
+				return nil
+			})
+		sessionPatches := patchInsertSession(nil)
+		defer sessionPatches.Reset()
+		rolePatches := patchGetUserRole(models.UserRoleData{Role: enum.ROLE_USER}, nil)
+		defer rolePatches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGet(challengeKey).Return(challengeValue, nil)
+		mockRedisService.EXPECT().RedisGetDel(challengeKey).Return(challengeValue, nil)
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().NewToken(3, gomock.Any(), gomock.Any(), gomock.Any()).Return("new_token", nil)
+		mockUtilsFetcher.EXPECT().RefreshToken(3, gomock.Any(), gomock.Any()).Return("refresh_token", nil)
+		mockUtilsFetcher.EXPECT().DateTimeStr(gomock.Any(), gomock.Any()).Return("2024年12月2日")
+		mockUtilsFetcher.EXPECT().SendMail(gomock.Any(), gomock.Any(), gomock.Any(), true).Return(nil)
+
+		fetcher := apiSignDataFetcher{
+			UtilsFetcher:         mockUtilsFetcher,
+			CommonFetcher:        common.NewCommonFetcher(),
+			EmailTemplateService: templates.NewEmailTemplateManager(),
+			RedisService:         mockRedisService,
+			AttemptLimiter:       allowAllAttempts(t),
+		}
+		fetcher.PostSignInTotpApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, utils.HashRecoveryCode("abcdefghij"), usedHash)
+	})
+}
+
+func TestTotpEnrollApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	fetcher := apiTotpManagementFetcher{
+		CommonFetcher: common.NewCommonFetcher(),
+	}
+
+	t.Run("TotpEnrollApi サインインユーザーが異なっています", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, httptest.NewRequest("POST", "/api/totp_enroll", nil), 2, testChallengeId)
+		totpRequest(c, "POST", "/api/totp_enroll", map[string]interface{}{"user_id": 3})
+
+		fetcher.TotpEnrollApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("TotpEnrollApi 外部認証のユーザー", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, nil, 3, testChallengeId)
+		totpRequest(c, "POST", "/api/totp_enroll", map[string]interface{}{"user_id": 3})
+
+		totpPatches := patchGetTotp(models.TotpData{UserId: 3, UserEmail: "test@example.com"}, nil)
+		defer totpPatches.Reset()
+
+		fetcher.TotpEnrollApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var responseBody utils.ErrorMessageResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, "外部認証で登録したユーザーは二段階認証を利用できません。", responseBody.Result)
+	})
+
+	t.Run("TotpEnrollApi 有効化済み", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, nil, 3, testChallengeId)
+		totpRequest(c, "POST", "/api/totp_enroll", map[string]interface{}{"user_id": 3})
+
+		totpPatches := patchGetTotp(enabledTotp(), nil)
+		defer totpPatches.Reset()
+
+		fetcher.TotpEnrollApi(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("TotpEnrollApi 成功", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, nil, 3, testChallengeId)
+		totpRequest(c, "POST", "/api/totp_enroll", map[string]interface{}{"user_id": "3"})
+
+		var savedSecret string
+		totpPatches := patchGetTotp(models.TotpData{UserId: 3, UserEmail: "test@example.com", PasswordAccount: true}, nil)
+		defer totpPatches.Reset()
+		totpPatches.ApplyMethod(
+			reflect.TypeOf(&models.TotpDataFetcher{}),
+			"UpsertTotpSecret",
+			func(_ *models.TotpDataFetcher, UserId int, Secret string) error {
+				savedSecret = Secret
+				return nil
+			})
+
+		fetcher.TotpEnrollApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var responseBody utils.ResponseData[TotpEnrollResult]
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, savedSecret, responseBody.Result.Secret)
+		assert.Equal(t, utils.TotpURI(savedSecret, "test@example.com"), responseBody.Result.OtpauthUri)
+	})
+}
+
+func TestTotpVerifyApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	pendingTotp := models.TotpData{
+		UserId:          3,
+		UserEmail:       "test@example.com",
+		PasswordAccount: true,
+		Secret:          testTotpSecret,
+	}
+
+	t.Run("TotpVerifyApi バリデーション 桁数不正", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, nil, 3, testChallengeId)
+		totpRequest(c, "POST", "/api/totp_verify", map[string]interface{}{"user_id": 3, "code": "abcde-fghij"})
+
+		fetcher := apiTotpManagementFetcher{CommonFetcher: common.NewCommonFetcher()}
+		fetcher.TotpVerifyApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var responseBody utils.ErrorValidationResponse
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Equal(t, []utils.ErrorMessages{
+			{Field: "code", Message: "ワンタイムパスワードは6桁の数字のみです。"},
+		}, responseBody.Result)
+	})
+
+	t.Run("TotpVerifyApi 登録開始前", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, nil, 3, testChallengeId)
+		totpRequest(c, "POST", "/api/totp_verify", map[string]interface{}{"user_id": 3, "code": "123456"})
+
+		totpPatches := patchGetTotp(models.TotpData{UserId: 3, PasswordAccount: true}, nil)
+		defer totpPatches.Reset()
+
+		fetcher := apiTotpManagementFetcher{CommonFetcher: common.NewCommonFetcher()}
+		fetcher.TotpVerifyApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("TotpVerifyApi ワンタイムパスワードが間違っている", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, nil, 3, testChallengeId)
+		code, _ := utils.TotpCode(testTotpSecret, time.Now().Add(-10*time.Minute))
+		totpRequest(c, "POST", "/api/totp_verify", map[string]interface{}{"user_id": 3, "code": code})
+
+		totpPatches := patchGetTotp(pendingTotp, nil)
+		defer totpPatches.Reset()
+
+		fetcher := apiTotpManagementFetcher{CommonFetcher: common.NewCommonFetcher()}
+		fetcher.TotpVerifyApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("TotpVerifyApi 成功", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, nil, 3, testChallengeId)
+		code, _ := utils.TotpCode(testTotpSecret, time.Now())
+		totpRequest(c, "POST", "/api/totp_verify", map[string]interface{}{"user_id": 3, "code": code})
+
+		var savedHashes []string
+		totpPatches := patchGetTotp(pendingTotp, nil)
+		defer totpPatches.Reset()
+		totpPatches.ApplyMethod(
+			reflect.TypeOf(&models.TotpDataFetcher{}),
+			"EnableTotp",
+			func(_ *models.TotpDataFetcher, UserId int, RecoveryCodeHashes []string) error {
+				savedHashes = RecoveryCodeHashes
+				return nil
+			})
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		expectUnusedTotpStep(mockRedisService)
+
+		fetcher := apiTotpManagementFetcher{
+			CommonFetcher: common.NewCommonFetcher(),
+			RedisService:  mockRedisService,
+		}
+		fetcher.TotpVerifyApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var responseBody utils.ResponseData[TotpVerifyResult]
+		json.Unmarshal(w.Body.Bytes(), &responseBody)
+		assert.Len(t, responseBody.Result.RecoveryCodes, utils.RecoveryCodeCount)
+		// 保存するのはハッシュのみ
+		assert.Len(t, savedHashes, utils.RecoveryCodeCount)
+		assert.Equal(t, utils.HashRecoveryCode(responseBody.Result.RecoveryCodes[0]), savedHashes[0])
+	})
+}
+
+func TestTotpDisableApi(t *testing.T) {
+
+	gin.SetMode(gin.TestMode)
+
+	t.Run("TotpDisableApi 二段階認証が無効", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, nil, 3, testChallengeId)
+		totpRequest(c, "POST", "/api/totp_disable", map[string]interface{}{"user_id": 3, "code": "123456"})
+
+		totpPatches := patchGetTotp(models.TotpData{UserId: 3, PasswordAccount: true}, nil)
+		defer totpPatches.Reset()
+
+		fetcher := apiTotpManagementFetcher{
+			CommonFetcher:  common.NewCommonFetcher(),
+			AttemptLimiter: allowAllAttempts(t),
+		}
+		fetcher.TotpDisableApi(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("TotpDisableApi 認証コードが間違っている", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, nil, 3, testChallengeId)
+		code, _ := utils.TotpCode(testTotpSecret, time.Now().Add(-10*time.Minute))
+		totpRequest(c, "POST", "/api/totp_disable", map[string]interface{}{"user_id": 3, "code": code})
+
+		totpPatches := patchGetTotp(enabledTotp(), nil)
+		defer totpPatches.Reset()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAttemptLimiter := mock_limiter.NewMockAttemptLimiter(ctrl)
+		mockAttemptLimiter.EXPECT().Check(utils.TotpAttemptKey(3)).Return(time.Duration(0), nil)
+		mockAttemptLimiter.EXPECT().
+			Fail(utils.TotpAttemptKey(3)).
+			Return(utils.AttemptStatus{Failures: 1, Wait: time.Second}, nil)
+
+		fetcher := apiTotpManagementFetcher{
+			CommonFetcher:  common.NewCommonFetcher(),
+			AttemptLimiter: mockAttemptLimiter,
+		}
+		fetcher.TotpDisableApi(c)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("TotpDisableApi 成功", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c := signInContext(w, nil, 3, testChallengeId)
+		code, _ := utils.TotpCode(testTotpSecret, time.Now())
+		totpRequest(c, "POST", "/api/totp_disable", map[string]interface{}{"user_id": 3, "code": code})
+
+		disabled := false
+		totpPatches := patchGetTotp(enabledTotp(), nil)
+		defer totpPatches.Reset()
+		totpPatches.ApplyMethod(
+			reflect.TypeOf(&models.TotpDataFetcher{}),
+			"DisableTotp",
+			func(_ *models.TotpDataFetcher, UserId int) error {
+				disabled = true
+				return nil
+			})
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		expectUnusedTotpStep(mockRedisService)
+
+		fetcher := apiTotpManagementFetcher{
+			CommonFetcher:  common.NewCommonFetcher(),
+			RedisService:   mockRedisService,
+			AttemptLimiter: allowAllAttempts(t),
+		}
+		fetcher.TotpDisableApi(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, disabled)
+	})
+}
@@ -2,3 +2,38 @@
 package enum
 
 const ERROR = "error"
+
+// 年収推移データの分類
+const (
+	SALARY = "給料"
+	BONUS  = "賞与"
+)
+
+// サインイン方法
+// 外部認証の場合は外部認証の名称(config.OIDCProviderConfig の Name)
+const (
+	SIGN_IN_PASSWORD   = "password"
+	SIGN_IN_MAGIC_LINK = "magic_link"
+)
+
+// ユーザーのロール
+// サポートは管理者用APIの参照のみ可能
+const (
+	ROLE_USER    = "user"
+	ROLE_ADMIN   = "admin"
+	ROLE_SUPPORT = "support"
+)
+
+// ユーザーの削除フラグ
+// 退会したユーザーは削除予定日時(猶予期間)まで復元できる
+const (
+	DELETE_FLAG_OFF = 0
+	DELETE_FLAG_ON  = 1
+)
+
+// 個人データのエクスポートの状態
+const (
+	EXPORT_STATUS_PENDING = "pending"
+	EXPORT_STATUS_READY   = "ready"
+	EXPORT_STATUS_FAILED  = "failed"
+)
@@ -4,8 +4,11 @@ import (
 	"log"
 	"server/common"
 	"server/config"
+	"server/controllers"
 	"server/middleware"
 	"server/routes"
+	"server/templates"
+	"server/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -20,8 +23,31 @@ func main() {
 	// 環境変数の初期化
 	config.InitGoogleEnvs()
 
+	// 署名鍵の読み込み(環境変数の初期化後に行う)
+	if err := utils.InitKeyManager(); err != nil {
+		log.Fatalf("Error loading jwt keys: %v", err)
+	}
+	if err := utils.InitTokenConfig(); err != nil {
+		log.Fatalf("Error loading token config: %v", err)
+	}
+	if err := utils.InitPasswordHashConfig(); err != nil {
+		log.Fatalf("Error loading password hash config: %v", err)
+	}
+	if err := utils.InitPasswordPolicyConfig(); err != nil {
+		log.Fatalf("Error loading password policy: %v", err)
+	}
+	// 外部認証の登録(環境変数の初期化後に行う)
+	if err := utils.InitOIDCProviders(); err != nil {
+		log.Fatalf("Error loading oidc providers: %v", err)
+	}
+
 	common.InitLogger(config.GlobalEnv.OutPutLoggerFile)
 
+	// 削除予定日時を過ぎた退会ユーザーの定期削除
+	controllers.StartAccountPurgeJob(templates.NewEmailTemplateManager(), utils.NewUtilsFetcher(utils.JwtKeys))
+	// 有効期限を過ぎた個人データのエクスポートの定期削除
+	controllers.StartDataExportCleanupJob()
+
 	r := gin.Default()
 	log.Println("start server...")
 	corsMiddleware := middleware.CORSMiddleware()
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"server/config"
+	"server/enum"
 	"server/utils"
 
 	"github.com/gin-contrib/cors"
@@ -20,7 +21,7 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func JWTAuthMiddleware(utilsFetcher utils.UtilsDataFetcher) gin.HandlerFunc {
+func JWTAuthMiddleware(utilsFetcher utils.UtilsFetcher) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// authHeader := c.GetHeader("Authorization")
 		requestID, _ := c.Get("request_id")
@@ -37,10 +38,8 @@ func JWTAuthMiddleware(utilsFetcher utils.UtilsDataFetcher) gin.HandlerFunc {
 
 		tokenString := strings.Replace(authToken, "Bearer ", "", 1)
 
-		// トークンの検証
-		token, err := jwt.ParseWithClaims(tokenString, jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-			return utils.JwtSecret, nil
-		})
+		// トークンの検証(ヘッダーの鍵IDから検証鍵を選ぶ)
+		token, err := utils.JwtKeys.Parse(tokenString, jwt.MapClaims{})
 		if err != nil || !token.Valid {
 			response := utils.ErrorMessageResponse{
 				Result: "無効なトークンです",
@@ -63,6 +62,50 @@ func JWTAuthMiddleware(utilsFetcher utils.UtilsDataFetcher) gin.HandlerFunc {
 					c.Abort()
 					return
 				}
+
+				// リフレッシュトークンをアクセストークンとして使わせない
+				if err := utils.CheckTokenType(claims, utils.TokenTypeAccess); err != nil {
+					response := utils.ErrorMessageResponse{
+						Result: "トークンの種類が不正です",
+					}
+					c.JSON(http.StatusUnauthorized, response)
+					c.Abort()
+					return
+				}
+
+				// サインアウト等で失効したトークンでないか確認
+				revoked, err := utilsFetcher.IsTokenRevoked(claims)
+				if err != nil {
+					response := utils.ErrorMessageResponse{
+						Result: "トークンの失効確認に失敗しました",
+					}
+					logrus.WithField("request_id", requestID).Error(err.Error())
+					c.JSON(http.StatusInternalServerError, response)
+					c.Abort()
+					return
+				}
+				if revoked {
+					response := utils.ErrorMessageResponse{
+						Result: "トークンは失効しています",
+					}
+					c.JSON(http.StatusUnauthorized, response)
+					c.Abort()
+					return
+				}
+
+				// 後続のハンドラーでサインイン中のユーザーとセッションを参照できるようにする
+				if userId, ok := claims["UserId"].(float64); ok {
+					c.Set(utils.UserId, int(userId))
+				}
+				if sessionId, ok := claims["sid"].(string); ok {
+					c.Set(utils.SessionId, sessionId)
+				}
+				// ロールを含まない(ロール導入前の)トークンは一般ユーザーとして扱う
+				role, _ := claims["role"].(string)
+				if role == "" {
+					role = enum.ROLE_USER
+				}
+				c.Set(utils.Role, role)
 			} else {
 				response := utils.ErrorMessageResponse{
 					Result: "トークンの有効期限が不正です",
@@ -85,6 +128,31 @@ func JWTAuthMiddleware(utilsFetcher utils.UtilsDataFetcher) gin.HandlerFunc {
 	}
 }
 
+// RoleMiddleware はサインイン中のユーザーのロールでルートへのアクセスを認可する
+// JWTAuthMiddleware の後に使用し、許可したロール以外は403を返す
+//
+// 引数:
+//   - roles: アクセスを許可するロール
+//
+
+func RoleMiddleware(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString(utils.Role)
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		response := utils.ErrorMessageResponse{
+			Result: "この操作を行う権限がありません。",
+		}
+		c.JSON(http.StatusForbidden, response)
+		c.Abort()
+	}
+}
+
 func CORSMiddleware() gin.HandlerFunc {
 	config := cors.Config{
 		AllowOrigins: []string{
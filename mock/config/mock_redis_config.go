@@ -49,6 +49,21 @@ func (mr *MockRedisServiceMockRecorder) InitRedisClient() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitRedisClient", reflect.TypeOf((*MockRedisService)(nil).InitRedisClient))
 }
 
+// RedisCompareAndSet mocks base method.
+func (m *MockRedisService) RedisCompareAndSet(key, expected string, value interface{}, duration time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedisCompareAndSet", key, expected, value, duration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedisCompareAndSet indicates an expected call of RedisCompareAndSet.
+func (mr *MockRedisServiceMockRecorder) RedisCompareAndSet(key, expected, value, duration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedisCompareAndSet", reflect.TypeOf((*MockRedisService)(nil).RedisCompareAndSet), key, expected, value, duration)
+}
+
 // RedisDel mocks base method.
 func (m *MockRedisService) RedisDel(key string) error {
 	m.ctrl.T.Helper()
@@ -63,6 +78,21 @@ func (mr *MockRedisServiceMockRecorder) RedisDel(key interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedisDel", reflect.TypeOf((*MockRedisService)(nil).RedisDel), key)
 }
 
+// RedisExists mocks base method.
+func (m *MockRedisService) RedisExists(key string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedisExists", key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedisExists indicates an expected call of RedisExists.
+func (mr *MockRedisServiceMockRecorder) RedisExists(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedisExists", reflect.TypeOf((*MockRedisService)(nil).RedisExists), key)
+}
+
 // RedisGet mocks base method.
 func (m *MockRedisService) RedisGet(key string) (string, error) {
 	m.ctrl.T.Helper()
@@ -78,6 +108,36 @@ func (mr *MockRedisServiceMockRecorder) RedisGet(key interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedisGet", reflect.TypeOf((*MockRedisService)(nil).RedisGet), key)
 }
 
+// RedisGetDel mocks base method.
+func (m *MockRedisService) RedisGetDel(key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedisGetDel", key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedisGetDel indicates an expected call of RedisGetDel.
+func (mr *MockRedisServiceMockRecorder) RedisGetDel(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedisGetDel", reflect.TypeOf((*MockRedisService)(nil).RedisGetDel), key)
+}
+
+// RedisIncr mocks base method.
+func (m *MockRedisService) RedisIncr(key string, duration time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedisIncr", key, duration)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedisIncr indicates an expected call of RedisIncr.
+func (mr *MockRedisServiceMockRecorder) RedisIncr(key, duration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedisIncr", reflect.TypeOf((*MockRedisService)(nil).RedisIncr), key, duration)
+}
+
 // RedisSet mocks base method.
 func (m *MockRedisService) RedisSet(key string, value interface{}, duration time.Duration) error {
 	m.ctrl.T.Helper()
@@ -91,3 +151,18 @@ func (mr *MockRedisServiceMockRecorder) RedisSet(key, value, duration interface{
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedisSet", reflect.TypeOf((*MockRedisService)(nil).RedisSet), key, value, duration)
 }
+
+// RedisSetIfGreater mocks base method.
+func (m *MockRedisService) RedisSetIfGreater(key string, value int64, duration time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedisSetIfGreater", key, value, duration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedisSetIfGreater indicates an expected call of RedisSetIfGreater.
+func (mr *MockRedisServiceMockRecorder) RedisSetIfGreater(key, value, duration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedisSetIfGreater", reflect.TypeOf((*MockRedisService)(nil).RedisSetIfGreater), key, value, duration)
+}
@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./controllers/admin_controllers.go
+
+// Package mock_controllers is a generated GoMock package.
+package mock_controllers
+
+import (
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAdminManagementFetcher is a mock of AdminManagementFetcher interface.
+type MockAdminManagementFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminManagementFetcherMockRecorder
+}
+
+// MockAdminManagementFetcherMockRecorder is the mock recorder for MockAdminManagementFetcher.
+type MockAdminManagementFetcherMockRecorder struct {
+	mock *MockAdminManagementFetcher
+}
+
+// NewMockAdminManagementFetcher creates a new mock instance.
+func NewMockAdminManagementFetcher(ctrl *gomock.Controller) *MockAdminManagementFetcher {
+	mock := &MockAdminManagementFetcher{ctrl: ctrl}
+	mock.recorder = &MockAdminManagementFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminManagementFetcher) EXPECT() *MockAdminManagementFetcherMockRecorder {
+	return m.recorder
+}
+
+// DisableUserApi mocks base method.
+func (m *MockAdminManagementFetcher) DisableUserApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DisableUserApi", c)
+}
+
+// DisableUserApi indicates an expected call of DisableUserApi.
+func (mr *MockAdminManagementFetcherMockRecorder) DisableUserApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableUserApi", reflect.TypeOf((*MockAdminManagementFetcher)(nil).DisableUserApi), c)
+}
+
+// EnableUserApi mocks base method.
+func (m *MockAdminManagementFetcher) EnableUserApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "EnableUserApi", c)
+}
+
+// EnableUserApi indicates an expected call of EnableUserApi.
+func (mr *MockAdminManagementFetcherMockRecorder) EnableUserApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableUserApi", reflect.TypeOf((*MockAdminManagementFetcher)(nil).EnableUserApi), c)
+}
+
+// ForcePasswordResetApi mocks base method.
+func (m *MockAdminManagementFetcher) ForcePasswordResetApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ForcePasswordResetApi", c)
+}
+
+// ForcePasswordResetApi indicates an expected call of ForcePasswordResetApi.
+func (mr *MockAdminManagementFetcherMockRecorder) ForcePasswordResetApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForcePasswordResetApi", reflect.TypeOf((*MockAdminManagementFetcher)(nil).ForcePasswordResetApi), c)
+}
+
+// GetAdminUsersApi mocks base method.
+func (m *MockAdminManagementFetcher) GetAdminUsersApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetAdminUsersApi", c)
+}
+
+// GetAdminUsersApi indicates an expected call of GetAdminUsersApi.
+func (mr *MockAdminManagementFetcherMockRecorder) GetAdminUsersApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminUsersApi", reflect.TypeOf((*MockAdminManagementFetcher)(nil).GetAdminUsersApi), c)
+}
+
+// GetAuditLogApi mocks base method.
+func (m *MockAdminManagementFetcher) GetAuditLogApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetAuditLogApi", c)
+}
+
+// GetAuditLogApi indicates an expected call of GetAuditLogApi.
+func (mr *MockAdminManagementFetcherMockRecorder) GetAuditLogApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogApi", reflect.TypeOf((*MockAdminManagementFetcher)(nil).GetAuditLogApi), c)
+}
+
+// GetSignUpStatsApi mocks base method.
+func (m *MockAdminManagementFetcher) GetSignUpStatsApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetSignUpStatsApi", c)
+}
+
+// GetSignUpStatsApi indicates an expected call of GetSignUpStatsApi.
+func (mr *MockAdminManagementFetcherMockRecorder) GetSignUpStatsApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSignUpStatsApi", reflect.TypeOf((*MockAdminManagementFetcher)(nil).GetSignUpStatsApi), c)
+}
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./controllers/data_export_controllers.go
+
+// Package mock_controllers is a generated GoMock package.
+package mock_controllers
+
+import (
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDataExportManagementFetcher is a mock of DataExportManagementFetcher interface.
+type MockDataExportManagementFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataExportManagementFetcherMockRecorder
+}
+
+// MockDataExportManagementFetcherMockRecorder is the mock recorder for MockDataExportManagementFetcher.
+type MockDataExportManagementFetcherMockRecorder struct {
+	mock *MockDataExportManagementFetcher
+}
+
+// NewMockDataExportManagementFetcher creates a new mock instance.
+func NewMockDataExportManagementFetcher(ctrl *gomock.Controller) *MockDataExportManagementFetcher {
+	mock := &MockDataExportManagementFetcher{ctrl: ctrl}
+	mock.recorder = &MockDataExportManagementFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataExportManagementFetcher) EXPECT() *MockDataExportManagementFetcherMockRecorder {
+	return m.recorder
+}
+
+// DownloadDataExportApi mocks base method.
+func (m *MockDataExportManagementFetcher) DownloadDataExportApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DownloadDataExportApi", c)
+}
+
+// DownloadDataExportApi indicates an expected call of DownloadDataExportApi.
+func (mr *MockDataExportManagementFetcherMockRecorder) DownloadDataExportApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadDataExportApi", reflect.TypeOf((*MockDataExportManagementFetcher)(nil).DownloadDataExportApi), c)
+}
+
+// ImportDataApi mocks base method.
+func (m *MockDataExportManagementFetcher) ImportDataApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ImportDataApi", c)
+}
+
+// ImportDataApi indicates an expected call of ImportDataApi.
+func (mr *MockDataExportManagementFetcherMockRecorder) ImportDataApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportDataApi", reflect.TypeOf((*MockDataExportManagementFetcher)(nil).ImportDataApi), c)
+}
+
+// RequestDataExportApi mocks base method.
+func (m *MockDataExportManagementFetcher) RequestDataExportApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RequestDataExportApi", c)
+}
+
+// RequestDataExportApi indicates an expected call of RequestDataExportApi.
+func (mr *MockDataExportManagementFetcherMockRecorder) RequestDataExportApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestDataExportApi", reflect.TypeOf((*MockDataExportManagementFetcher)(nil).RequestDataExportApi), c)
+}
@@ -0,0 +1,59 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./controllers/identity_controllers.go
+
+// Package mock_controllers is a generated GoMock package.
+package mock_controllers
+
+import (
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIdentityManagementFetcher is a mock of IdentityManagementFetcher interface.
+type MockIdentityManagementFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityManagementFetcherMockRecorder
+}
+
+// MockIdentityManagementFetcherMockRecorder is the mock recorder for MockIdentityManagementFetcher.
+type MockIdentityManagementFetcherMockRecorder struct {
+	mock *MockIdentityManagementFetcher
+}
+
+// NewMockIdentityManagementFetcher creates a new mock instance.
+func NewMockIdentityManagementFetcher(ctrl *gomock.Controller) *MockIdentityManagementFetcher {
+	mock := &MockIdentityManagementFetcher{ctrl: ctrl}
+	mock.recorder = &MockIdentityManagementFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityManagementFetcher) EXPECT() *MockIdentityManagementFetcherMockRecorder {
+	return m.recorder
+}
+
+// GetIdentityApi mocks base method.
+func (m *MockIdentityManagementFetcher) GetIdentityApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetIdentityApi", c)
+}
+
+// GetIdentityApi indicates an expected call of GetIdentityApi.
+func (mr *MockIdentityManagementFetcherMockRecorder) GetIdentityApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdentityApi", reflect.TypeOf((*MockIdentityManagementFetcher)(nil).GetIdentityApi), c)
+}
+
+// UnlinkIdentityApi mocks base method.
+func (m *MockIdentityManagementFetcher) UnlinkIdentityApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UnlinkIdentityApi", c)
+}
+
+// UnlinkIdentityApi indicates an expected call of UnlinkIdentityApi.
+func (mr *MockIdentityManagementFetcherMockRecorder) UnlinkIdentityApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlinkIdentityApi", reflect.TypeOf((*MockIdentityManagementFetcher)(nil).UnlinkIdentityApi), c)
+}
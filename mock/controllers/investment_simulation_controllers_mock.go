@@ -0,0 +1,47 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./controllers/investment_simulation_controllers.go
+
+// Package mock_controllers is a generated GoMock package.
+package mock_controllers
+
+import (
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockInvestmentSimulationFetcher is a mock of InvestmentSimulationFetcher interface.
+type MockInvestmentSimulationFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvestmentSimulationFetcherMockRecorder
+}
+
+// MockInvestmentSimulationFetcherMockRecorder is the mock recorder for MockInvestmentSimulationFetcher.
+type MockInvestmentSimulationFetcherMockRecorder struct {
+	mock *MockInvestmentSimulationFetcher
+}
+
+// NewMockInvestmentSimulationFetcher creates a new mock instance.
+func NewMockInvestmentSimulationFetcher(ctrl *gomock.Controller) *MockInvestmentSimulationFetcher {
+	mock := &MockInvestmentSimulationFetcher{ctrl: ctrl}
+	mock.recorder = &MockInvestmentSimulationFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvestmentSimulationFetcher) EXPECT() *MockInvestmentSimulationFetcherMockRecorder {
+	return m.recorder
+}
+
+// GetInvestmentSimulationApi mocks base method.
+func (m *MockInvestmentSimulationFetcher) GetInvestmentSimulationApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetInvestmentSimulationApi", c)
+}
+
+// GetInvestmentSimulationApi indicates an expected call of GetInvestmentSimulationApi.
+func (mr *MockInvestmentSimulationFetcherMockRecorder) GetInvestmentSimulationApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvestmentSimulationApi", reflect.TypeOf((*MockInvestmentSimulationFetcher)(nil).GetInvestmentSimulationApi), c)
+}
@@ -0,0 +1,47 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./controllers/jwks_controllers.go
+
+// Package mock_controllers is a generated GoMock package.
+package mock_controllers
+
+import (
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockJwksFetcher is a mock of JwksFetcher interface.
+type MockJwksFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockJwksFetcherMockRecorder
+}
+
+// MockJwksFetcherMockRecorder is the mock recorder for MockJwksFetcher.
+type MockJwksFetcherMockRecorder struct {
+	mock *MockJwksFetcher
+}
+
+// NewMockJwksFetcher creates a new mock instance.
+func NewMockJwksFetcher(ctrl *gomock.Controller) *MockJwksFetcher {
+	mock := &MockJwksFetcher{ctrl: ctrl}
+	mock.recorder = &MockJwksFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJwksFetcher) EXPECT() *MockJwksFetcherMockRecorder {
+	return m.recorder
+}
+
+// GetJwksApi mocks base method.
+func (m *MockJwksFetcher) GetJwksApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetJwksApi", c)
+}
+
+// GetJwksApi indicates an expected call of GetJwksApi.
+func (mr *MockJwksFetcherMockRecorder) GetJwksApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJwksApi", reflect.TypeOf((*MockJwksFetcher)(nil).GetJwksApi), c)
+}
@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./controllers/loan_controllers.go
+
+// Package mock_controllers is a generated GoMock package.
+package mock_controllers
+
+import (
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLoanManagementFetcher is a mock of LoanManagementFetcher interface.
+type MockLoanManagementFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoanManagementFetcherMockRecorder
+}
+
+// MockLoanManagementFetcherMockRecorder is the mock recorder for MockLoanManagementFetcher.
+type MockLoanManagementFetcherMockRecorder struct {
+	mock *MockLoanManagementFetcher
+}
+
+// NewMockLoanManagementFetcher creates a new mock instance.
+func NewMockLoanManagementFetcher(ctrl *gomock.Controller) *MockLoanManagementFetcher {
+	mock := &MockLoanManagementFetcher{ctrl: ctrl}
+	mock.recorder = &MockLoanManagementFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoanManagementFetcher) EXPECT() *MockLoanManagementFetcherMockRecorder {
+	return m.recorder
+}
+
+// DeleteLoanApi mocks base method.
+func (m *MockLoanManagementFetcher) DeleteLoanApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteLoanApi", c)
+}
+
+// DeleteLoanApi indicates an expected call of DeleteLoanApi.
+func (mr *MockLoanManagementFetcherMockRecorder) DeleteLoanApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLoanApi", reflect.TypeOf((*MockLoanManagementFetcher)(nil).DeleteLoanApi), c)
+}
+
+// GetLoanApi mocks base method.
+func (m *MockLoanManagementFetcher) GetLoanApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetLoanApi", c)
+}
+
+// GetLoanApi indicates an expected call of GetLoanApi.
+func (mr *MockLoanManagementFetcherMockRecorder) GetLoanApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoanApi", reflect.TypeOf((*MockLoanManagementFetcher)(nil).GetLoanApi), c)
+}
+
+// GetLoanScheduleApi mocks base method.
+func (m *MockLoanManagementFetcher) GetLoanScheduleApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetLoanScheduleApi", c)
+}
+
+// GetLoanScheduleApi indicates an expected call of GetLoanScheduleApi.
+func (mr *MockLoanManagementFetcherMockRecorder) GetLoanScheduleApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoanScheduleApi", reflect.TypeOf((*MockLoanManagementFetcher)(nil).GetLoanScheduleApi), c)
+}
+
+// InsertLoanApi mocks base method.
+func (m *MockLoanManagementFetcher) InsertLoanApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "InsertLoanApi", c)
+}
+
+// InsertLoanApi indicates an expected call of InsertLoanApi.
+func (mr *MockLoanManagementFetcherMockRecorder) InsertLoanApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertLoanApi", reflect.TypeOf((*MockLoanManagementFetcher)(nil).InsertLoanApi), c)
+}
+
+// UpdateLoanApi mocks base method.
+func (m *MockLoanManagementFetcher) UpdateLoanApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateLoanApi", c)
+}
+
+// UpdateLoanApi indicates an expected call of UpdateLoanApi.
+func (mr *MockLoanManagementFetcherMockRecorder) UpdateLoanApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoanApi", reflect.TypeOf((*MockLoanManagementFetcher)(nil).UpdateLoanApi), c)
+}
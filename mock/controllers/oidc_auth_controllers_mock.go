@@ -0,0 +1,131 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./controllers/oidc_auth_controllers.go
+
+// Package mock_controllers is a generated GoMock package.
+package mock_controllers
+
+import (
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOIDCService is a mock of OIDCService interface.
+type MockOIDCService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCServiceMockRecorder
+}
+
+// MockOIDCServiceMockRecorder is the mock recorder for MockOIDCService.
+type MockOIDCServiceMockRecorder struct {
+	mock *MockOIDCService
+}
+
+// NewMockOIDCService creates a new mock instance.
+func NewMockOIDCService(ctrl *gomock.Controller) *MockOIDCService {
+	mock := &MockOIDCService{ctrl: ctrl}
+	mock.recorder = &MockOIDCServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCService) EXPECT() *MockOIDCServiceMockRecorder {
+	return m.recorder
+}
+
+// OIDCDeleteAuth mocks base method.
+func (m *MockOIDCService) OIDCDeleteAuth(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OIDCDeleteAuth", c)
+}
+
+// OIDCDeleteAuth indicates an expected call of OIDCDeleteAuth.
+func (mr *MockOIDCServiceMockRecorder) OIDCDeleteAuth(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCDeleteAuth", reflect.TypeOf((*MockOIDCService)(nil).OIDCDeleteAuth), c)
+}
+
+// OIDCDeleteCallback mocks base method.
+func (m *MockOIDCService) OIDCDeleteCallback(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OIDCDeleteCallback", c)
+}
+
+// OIDCDeleteCallback indicates an expected call of OIDCDeleteCallback.
+func (mr *MockOIDCServiceMockRecorder) OIDCDeleteCallback(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCDeleteCallback", reflect.TypeOf((*MockOIDCService)(nil).OIDCDeleteCallback), c)
+}
+
+// OIDCLinkAuth mocks base method.
+func (m *MockOIDCService) OIDCLinkAuth(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OIDCLinkAuth", c)
+}
+
+// OIDCLinkAuth indicates an expected call of OIDCLinkAuth.
+func (mr *MockOIDCServiceMockRecorder) OIDCLinkAuth(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCLinkAuth", reflect.TypeOf((*MockOIDCService)(nil).OIDCLinkAuth), c)
+}
+
+// OIDCLinkCallback mocks base method.
+func (m *MockOIDCService) OIDCLinkCallback(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OIDCLinkCallback", c)
+}
+
+// OIDCLinkCallback indicates an expected call of OIDCLinkCallback.
+func (mr *MockOIDCServiceMockRecorder) OIDCLinkCallback(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCLinkCallback", reflect.TypeOf((*MockOIDCService)(nil).OIDCLinkCallback), c)
+}
+
+// OIDCSignInAuth mocks base method.
+func (m *MockOIDCService) OIDCSignInAuth(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OIDCSignInAuth", c)
+}
+
+// OIDCSignInAuth indicates an expected call of OIDCSignInAuth.
+func (mr *MockOIDCServiceMockRecorder) OIDCSignInAuth(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCSignInAuth", reflect.TypeOf((*MockOIDCService)(nil).OIDCSignInAuth), c)
+}
+
+// OIDCSignInCallback mocks base method.
+func (m *MockOIDCService) OIDCSignInCallback(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OIDCSignInCallback", c)
+}
+
+// OIDCSignInCallback indicates an expected call of OIDCSignInCallback.
+func (mr *MockOIDCServiceMockRecorder) OIDCSignInCallback(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCSignInCallback", reflect.TypeOf((*MockOIDCService)(nil).OIDCSignInCallback), c)
+}
+
+// OIDCSignUpAuth mocks base method.
+func (m *MockOIDCService) OIDCSignUpAuth(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OIDCSignUpAuth", c)
+}
+
+// OIDCSignUpAuth indicates an expected call of OIDCSignUpAuth.
+func (mr *MockOIDCServiceMockRecorder) OIDCSignUpAuth(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCSignUpAuth", reflect.TypeOf((*MockOIDCService)(nil).OIDCSignUpAuth), c)
+}
+
+// OIDCSignUpCallback mocks base method.
+func (m *MockOIDCService) OIDCSignUpCallback(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OIDCSignUpCallback", c)
+}
+
+// OIDCSignUpCallback indicates an expected call of OIDCSignUpCallback.
+func (mr *MockOIDCServiceMockRecorder) OIDCSignUpCallback(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCSignUpCallback", reflect.TypeOf((*MockOIDCService)(nil).OIDCSignUpCallback), c)
+}
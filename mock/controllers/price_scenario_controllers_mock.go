@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./controllers/price_scenario_controllers.go
+
+// Package mock_controllers is a generated GoMock package.
+package mock_controllers
+
+import (
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPriceScenarioManagementFetcher is a mock of PriceScenarioManagementFetcher interface.
+type MockPriceScenarioManagementFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPriceScenarioManagementFetcherMockRecorder
+}
+
+// MockPriceScenarioManagementFetcherMockRecorder is the mock recorder for MockPriceScenarioManagementFetcher.
+type MockPriceScenarioManagementFetcherMockRecorder struct {
+	mock *MockPriceScenarioManagementFetcher
+}
+
+// NewMockPriceScenarioManagementFetcher creates a new mock instance.
+func NewMockPriceScenarioManagementFetcher(ctrl *gomock.Controller) *MockPriceScenarioManagementFetcher {
+	mock := &MockPriceScenarioManagementFetcher{ctrl: ctrl}
+	mock.recorder = &MockPriceScenarioManagementFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPriceScenarioManagementFetcher) EXPECT() *MockPriceScenarioManagementFetcherMockRecorder {
+	return m.recorder
+}
+
+// ActivatePriceScenarioApi mocks base method.
+func (m *MockPriceScenarioManagementFetcher) ActivatePriceScenarioApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ActivatePriceScenarioApi", c)
+}
+
+// ActivatePriceScenarioApi indicates an expected call of ActivatePriceScenarioApi.
+func (mr *MockPriceScenarioManagementFetcherMockRecorder) ActivatePriceScenarioApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivatePriceScenarioApi", reflect.TypeOf((*MockPriceScenarioManagementFetcher)(nil).ActivatePriceScenarioApi), c)
+}
+
+// DeletePriceScenarioApi mocks base method.
+func (m *MockPriceScenarioManagementFetcher) DeletePriceScenarioApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeletePriceScenarioApi", c)
+}
+
+// DeletePriceScenarioApi indicates an expected call of DeletePriceScenarioApi.
+func (mr *MockPriceScenarioManagementFetcherMockRecorder) DeletePriceScenarioApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePriceScenarioApi", reflect.TypeOf((*MockPriceScenarioManagementFetcher)(nil).DeletePriceScenarioApi), c)
+}
+
+// GetPriceScenarioApi mocks base method.
+func (m *MockPriceScenarioManagementFetcher) GetPriceScenarioApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetPriceScenarioApi", c)
+}
+
+// GetPriceScenarioApi indicates an expected call of GetPriceScenarioApi.
+func (mr *MockPriceScenarioManagementFetcherMockRecorder) GetPriceScenarioApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceScenarioApi", reflect.TypeOf((*MockPriceScenarioManagementFetcher)(nil).GetPriceScenarioApi), c)
+}
+
+// InsertPriceScenarioApi mocks base method.
+func (m *MockPriceScenarioManagementFetcher) InsertPriceScenarioApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "InsertPriceScenarioApi", c)
+}
+
+// InsertPriceScenarioApi indicates an expected call of InsertPriceScenarioApi.
+func (mr *MockPriceScenarioManagementFetcherMockRecorder) InsertPriceScenarioApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPriceScenarioApi", reflect.TypeOf((*MockPriceScenarioManagementFetcher)(nil).InsertPriceScenarioApi), c)
+}
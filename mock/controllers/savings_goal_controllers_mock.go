@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./controllers/savings_goal_controllers.go
+
+// Package mock_controllers is a generated GoMock package.
+package mock_controllers
+
+import (
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSavingsGoalManagementFetcher is a mock of SavingsGoalManagementFetcher interface.
+type MockSavingsGoalManagementFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockSavingsGoalManagementFetcherMockRecorder
+}
+
+// MockSavingsGoalManagementFetcherMockRecorder is the mock recorder for MockSavingsGoalManagementFetcher.
+type MockSavingsGoalManagementFetcherMockRecorder struct {
+	mock *MockSavingsGoalManagementFetcher
+}
+
+// NewMockSavingsGoalManagementFetcher creates a new mock instance.
+func NewMockSavingsGoalManagementFetcher(ctrl *gomock.Controller) *MockSavingsGoalManagementFetcher {
+	mock := &MockSavingsGoalManagementFetcher{ctrl: ctrl}
+	mock.recorder = &MockSavingsGoalManagementFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSavingsGoalManagementFetcher) EXPECT() *MockSavingsGoalManagementFetcherMockRecorder {
+	return m.recorder
+}
+
+// DeleteSavingsGoalApi mocks base method.
+func (m *MockSavingsGoalManagementFetcher) DeleteSavingsGoalApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DeleteSavingsGoalApi", c)
+}
+
+// DeleteSavingsGoalApi indicates an expected call of DeleteSavingsGoalApi.
+func (mr *MockSavingsGoalManagementFetcherMockRecorder) DeleteSavingsGoalApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSavingsGoalApi", reflect.TypeOf((*MockSavingsGoalManagementFetcher)(nil).DeleteSavingsGoalApi), c)
+}
+
+// GetSavingsGoalApi mocks base method.
+func (m *MockSavingsGoalManagementFetcher) GetSavingsGoalApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetSavingsGoalApi", c)
+}
+
+// GetSavingsGoalApi indicates an expected call of GetSavingsGoalApi.
+func (mr *MockSavingsGoalManagementFetcherMockRecorder) GetSavingsGoalApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSavingsGoalApi", reflect.TypeOf((*MockSavingsGoalManagementFetcher)(nil).GetSavingsGoalApi), c)
+}
+
+// GetSavingsGoalSnapshotApi mocks base method.
+func (m *MockSavingsGoalManagementFetcher) GetSavingsGoalSnapshotApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetSavingsGoalSnapshotApi", c)
+}
+
+// GetSavingsGoalSnapshotApi indicates an expected call of GetSavingsGoalSnapshotApi.
+func (mr *MockSavingsGoalManagementFetcherMockRecorder) GetSavingsGoalSnapshotApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSavingsGoalSnapshotApi", reflect.TypeOf((*MockSavingsGoalManagementFetcher)(nil).GetSavingsGoalSnapshotApi), c)
+}
+
+// InsertSavingsGoalApi mocks base method.
+func (m *MockSavingsGoalManagementFetcher) InsertSavingsGoalApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "InsertSavingsGoalApi", c)
+}
+
+// InsertSavingsGoalApi indicates an expected call of InsertSavingsGoalApi.
+func (mr *MockSavingsGoalManagementFetcherMockRecorder) InsertSavingsGoalApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSavingsGoalApi", reflect.TypeOf((*MockSavingsGoalManagementFetcher)(nil).InsertSavingsGoalApi), c)
+}
+
+// RecordSavingsGoalSnapshotApi mocks base method.
+func (m *MockSavingsGoalManagementFetcher) RecordSavingsGoalSnapshotApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RecordSavingsGoalSnapshotApi", c)
+}
+
+// RecordSavingsGoalSnapshotApi indicates an expected call of RecordSavingsGoalSnapshotApi.
+func (mr *MockSavingsGoalManagementFetcherMockRecorder) RecordSavingsGoalSnapshotApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSavingsGoalSnapshotApi", reflect.TypeOf((*MockSavingsGoalManagementFetcher)(nil).RecordSavingsGoalSnapshotApi), c)
+}
+
+// UpdateSavingsGoalApi mocks base method.
+func (m *MockSavingsGoalManagementFetcher) UpdateSavingsGoalApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateSavingsGoalApi", c)
+}
+
+// UpdateSavingsGoalApi indicates an expected call of UpdateSavingsGoalApi.
+func (mr *MockSavingsGoalManagementFetcherMockRecorder) UpdateSavingsGoalApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSavingsGoalApi", reflect.TypeOf((*MockSavingsGoalManagementFetcher)(nil).UpdateSavingsGoalApi), c)
+}
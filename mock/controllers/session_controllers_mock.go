@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./controllers/session_controllers.go
+
+// Package mock_controllers is a generated GoMock package.
+package mock_controllers
+
+import (
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSessionManagementFetcher is a mock of SessionManagementFetcher interface.
+type MockSessionManagementFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionManagementFetcherMockRecorder
+}
+
+// MockSessionManagementFetcherMockRecorder is the mock recorder for MockSessionManagementFetcher.
+type MockSessionManagementFetcherMockRecorder struct {
+	mock *MockSessionManagementFetcher
+}
+
+// NewMockSessionManagementFetcher creates a new mock instance.
+func NewMockSessionManagementFetcher(ctrl *gomock.Controller) *MockSessionManagementFetcher {
+	mock := &MockSessionManagementFetcher{ctrl: ctrl}
+	mock.recorder = &MockSessionManagementFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionManagementFetcher) EXPECT() *MockSessionManagementFetcherMockRecorder {
+	return m.recorder
+}
+
+// GetSessionApi mocks base method.
+func (m *MockSessionManagementFetcher) GetSessionApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetSessionApi", c)
+}
+
+// GetSessionApi indicates an expected call of GetSessionApi.
+func (mr *MockSessionManagementFetcherMockRecorder) GetSessionApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionApi", reflect.TypeOf((*MockSessionManagementFetcher)(nil).GetSessionApi), c)
+}
+
+// RevokeOtherSessionsApi mocks base method.
+func (m *MockSessionManagementFetcher) RevokeOtherSessionsApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RevokeOtherSessionsApi", c)
+}
+
+// RevokeOtherSessionsApi indicates an expected call of RevokeOtherSessionsApi.
+func (mr *MockSessionManagementFetcherMockRecorder) RevokeOtherSessionsApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOtherSessionsApi", reflect.TypeOf((*MockSessionManagementFetcher)(nil).RevokeOtherSessionsApi), c)
+}
+
+// RevokeSessionApi mocks base method.
+func (m *MockSessionManagementFetcher) RevokeSessionApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RevokeSessionApi", c)
+}
+
+// RevokeSessionApi indicates an expected call of RevokeSessionApi.
+func (mr *MockSessionManagementFetcherMockRecorder) RevokeSessionApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessionApi", reflect.TypeOf((*MockSessionManagementFetcher)(nil).RevokeSessionApi), c)
+}
@@ -34,6 +34,42 @@ func (m *MockSignDataFetcher) EXPECT() *MockSignDataFetcherMockRecorder {
 	return m.recorder
 }
 
+// AccountRestoreApi mocks base method.
+func (m *MockSignDataFetcher) AccountRestoreApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AccountRestoreApi", c)
+}
+
+// AccountRestoreApi indicates an expected call of AccountRestoreApi.
+func (mr *MockSignDataFetcherMockRecorder) AccountRestoreApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountRestoreApi", reflect.TypeOf((*MockSignDataFetcher)(nil).AccountRestoreApi), c)
+}
+
+// AccountUnlockApi mocks base method.
+func (m *MockSignDataFetcher) AccountUnlockApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AccountUnlockApi", c)
+}
+
+// AccountUnlockApi indicates an expected call of AccountUnlockApi.
+func (mr *MockSignDataFetcherMockRecorder) AccountUnlockApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountUnlockApi", reflect.TypeOf((*MockSignDataFetcher)(nil).AccountUnlockApi), c)
+}
+
+// AdminAccountUnlockApi mocks base method.
+func (m *MockSignDataFetcher) AdminAccountUnlockApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AdminAccountUnlockApi", c)
+}
+
+// AdminAccountUnlockApi indicates an expected call of AdminAccountUnlockApi.
+func (mr *MockSignDataFetcherMockRecorder) AdminAccountUnlockApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminAccountUnlockApi", reflect.TypeOf((*MockSignDataFetcher)(nil).AdminAccountUnlockApi), c)
+}
+
 // DeleteSignInApi mocks base method.
 func (m *MockSignDataFetcher) DeleteSignInApi(c *gin.Context) {
 	m.ctrl.T.Helper()
@@ -46,6 +82,42 @@ func (mr *MockSignDataFetcherMockRecorder) DeleteSignInApi(c interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSignInApi", reflect.TypeOf((*MockSignDataFetcher)(nil).DeleteSignInApi), c)
 }
 
+// EmailChangeCancelApi mocks base method.
+func (m *MockSignDataFetcher) EmailChangeCancelApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "EmailChangeCancelApi", c)
+}
+
+// EmailChangeCancelApi indicates an expected call of EmailChangeCancelApi.
+func (mr *MockSignDataFetcherMockRecorder) EmailChangeCancelApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmailChangeCancelApi", reflect.TypeOf((*MockSignDataFetcher)(nil).EmailChangeCancelApi), c)
+}
+
+// EmailChangeConfirmApi mocks base method.
+func (m *MockSignDataFetcher) EmailChangeConfirmApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "EmailChangeConfirmApi", c)
+}
+
+// EmailChangeConfirmApi indicates an expected call of EmailChangeConfirmApi.
+func (mr *MockSignDataFetcherMockRecorder) EmailChangeConfirmApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmailChangeConfirmApi", reflect.TypeOf((*MockSignDataFetcher)(nil).EmailChangeConfirmApi), c)
+}
+
+// EmailChangeRequestApi mocks base method.
+func (m *MockSignDataFetcher) EmailChangeRequestApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "EmailChangeRequestApi", c)
+}
+
+// EmailChangeRequestApi indicates an expected call of EmailChangeRequestApi.
+func (mr *MockSignDataFetcherMockRecorder) EmailChangeRequestApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmailChangeRequestApi", reflect.TypeOf((*MockSignDataFetcher)(nil).EmailChangeRequestApi), c)
+}
+
 // GetRefreshTokenApi mocks base method.
 func (m *MockSignDataFetcher) GetRefreshTokenApi(c *gin.Context) {
 	m.ctrl.T.Helper()
@@ -58,6 +130,42 @@ func (mr *MockSignDataFetcherMockRecorder) GetRefreshTokenApi(c interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshTokenApi", reflect.TypeOf((*MockSignDataFetcher)(nil).GetRefreshTokenApi), c)
 }
 
+// MagicLinkRequestApi mocks base method.
+func (m *MockSignDataFetcher) MagicLinkRequestApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "MagicLinkRequestApi", c)
+}
+
+// MagicLinkRequestApi indicates an expected call of MagicLinkRequestApi.
+func (mr *MockSignDataFetcherMockRecorder) MagicLinkRequestApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MagicLinkRequestApi", reflect.TypeOf((*MockSignDataFetcher)(nil).MagicLinkRequestApi), c)
+}
+
+// MagicLinkSignInApi mocks base method.
+func (m *MockSignDataFetcher) MagicLinkSignInApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "MagicLinkSignInApi", c)
+}
+
+// MagicLinkSignInApi indicates an expected call of MagicLinkSignInApi.
+func (mr *MockSignDataFetcherMockRecorder) MagicLinkSignInApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MagicLinkSignInApi", reflect.TypeOf((*MockSignDataFetcher)(nil).MagicLinkSignInApi), c)
+}
+
+// NewPasswordUpdate mocks base method.
+func (m *MockSignDataFetcher) NewPasswordUpdate(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NewPasswordUpdate", c)
+}
+
+// NewPasswordUpdate indicates an expected call of NewPasswordUpdate.
+func (mr *MockSignDataFetcherMockRecorder) NewPasswordUpdate(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewPasswordUpdate", reflect.TypeOf((*MockSignDataFetcher)(nil).NewPasswordUpdate), c)
+}
+
 // PostSignInApi mocks base method.
 func (m *MockSignDataFetcher) PostSignInApi(c *gin.Context) {
 	m.ctrl.T.Helper()
@@ -70,6 +178,18 @@ func (mr *MockSignDataFetcherMockRecorder) PostSignInApi(c interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostSignInApi", reflect.TypeOf((*MockSignDataFetcher)(nil).PostSignInApi), c)
 }
 
+// PostSignInTotpApi mocks base method.
+func (m *MockSignDataFetcher) PostSignInTotpApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PostSignInTotpApi", c)
+}
+
+// PostSignInTotpApi indicates an expected call of PostSignInTotpApi.
+func (mr *MockSignDataFetcherMockRecorder) PostSignInTotpApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostSignInTotpApi", reflect.TypeOf((*MockSignDataFetcher)(nil).PostSignInTotpApi), c)
+}
+
 // PostSignUpApi mocks base method.
 func (m *MockSignDataFetcher) PostSignUpApi(c *gin.Context) {
 	m.ctrl.T.Helper()
@@ -94,6 +214,18 @@ func (mr *MockSignDataFetcherMockRecorder) PutSignInEditApi(c interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutSignInEditApi", reflect.TypeOf((*MockSignDataFetcher)(nil).PutSignInEditApi), c)
 }
 
+// RegisterEmailCheckNotice mocks base method.
+func (m *MockSignDataFetcher) RegisterEmailCheckNotice(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RegisterEmailCheckNotice", c)
+}
+
+// RegisterEmailCheckNotice indicates an expected call of RegisterEmailCheckNotice.
+func (mr *MockSignDataFetcherMockRecorder) RegisterEmailCheckNotice(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterEmailCheckNotice", reflect.TypeOf((*MockSignDataFetcher)(nil).RegisterEmailCheckNotice), c)
+}
+
 // RetryAuthEmail mocks base method.
 func (m *MockSignDataFetcher) RetryAuthEmail(c *gin.Context) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,47 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./controllers/take_home_simulation_controllers.go
+
+// Package mock_controllers is a generated GoMock package.
+package mock_controllers
+
+import (
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTakeHomeSimulationFetcher is a mock of TakeHomeSimulationFetcher interface.
+type MockTakeHomeSimulationFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockTakeHomeSimulationFetcherMockRecorder
+}
+
+// MockTakeHomeSimulationFetcherMockRecorder is the mock recorder for MockTakeHomeSimulationFetcher.
+type MockTakeHomeSimulationFetcherMockRecorder struct {
+	mock *MockTakeHomeSimulationFetcher
+}
+
+// NewMockTakeHomeSimulationFetcher creates a new mock instance.
+func NewMockTakeHomeSimulationFetcher(ctrl *gomock.Controller) *MockTakeHomeSimulationFetcher {
+	mock := &MockTakeHomeSimulationFetcher{ctrl: ctrl}
+	mock.recorder = &MockTakeHomeSimulationFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTakeHomeSimulationFetcher) EXPECT() *MockTakeHomeSimulationFetcherMockRecorder {
+	return m.recorder
+}
+
+// GetTakeHomeSimulationApi mocks base method.
+func (m *MockTakeHomeSimulationFetcher) GetTakeHomeSimulationApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "GetTakeHomeSimulationApi", c)
+}
+
+// GetTakeHomeSimulationApi indicates an expected call of GetTakeHomeSimulationApi.
+func (mr *MockTakeHomeSimulationFetcherMockRecorder) GetTakeHomeSimulationApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTakeHomeSimulationApi", reflect.TypeOf((*MockTakeHomeSimulationFetcher)(nil).GetTakeHomeSimulationApi), c)
+}
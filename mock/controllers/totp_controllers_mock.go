@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./controllers/totp_controllers.go
+
+// Package mock_controllers is a generated GoMock package.
+package mock_controllers
+
+import (
+	reflect "reflect"
+
+	gin "github.com/gin-gonic/gin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTotpManagementFetcher is a mock of TotpManagementFetcher interface.
+type MockTotpManagementFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockTotpManagementFetcherMockRecorder
+}
+
+// MockTotpManagementFetcherMockRecorder is the mock recorder for MockTotpManagementFetcher.
+type MockTotpManagementFetcherMockRecorder struct {
+	mock *MockTotpManagementFetcher
+}
+
+// NewMockTotpManagementFetcher creates a new mock instance.
+func NewMockTotpManagementFetcher(ctrl *gomock.Controller) *MockTotpManagementFetcher {
+	mock := &MockTotpManagementFetcher{ctrl: ctrl}
+	mock.recorder = &MockTotpManagementFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTotpManagementFetcher) EXPECT() *MockTotpManagementFetcherMockRecorder {
+	return m.recorder
+}
+
+// TotpDisableApi mocks base method.
+func (m *MockTotpManagementFetcher) TotpDisableApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TotpDisableApi", c)
+}
+
+// TotpDisableApi indicates an expected call of TotpDisableApi.
+func (mr *MockTotpManagementFetcherMockRecorder) TotpDisableApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TotpDisableApi", reflect.TypeOf((*MockTotpManagementFetcher)(nil).TotpDisableApi), c)
+}
+
+// TotpEnrollApi mocks base method.
+func (m *MockTotpManagementFetcher) TotpEnrollApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TotpEnrollApi", c)
+}
+
+// TotpEnrollApi indicates an expected call of TotpEnrollApi.
+func (mr *MockTotpManagementFetcherMockRecorder) TotpEnrollApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TotpEnrollApi", reflect.TypeOf((*MockTotpManagementFetcher)(nil).TotpEnrollApi), c)
+}
+
+// TotpVerifyApi mocks base method.
+func (m *MockTotpManagementFetcher) TotpVerifyApi(c *gin.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "TotpVerifyApi", c)
+}
+
+// TotpVerifyApi indicates an expected call of TotpVerifyApi.
+func (mr *MockTotpManagementFetcherMockRecorder) TotpVerifyApi(c interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TotpVerifyApi", reflect.TypeOf((*MockTotpManagementFetcher)(nil).TotpVerifyApi), c)
+}
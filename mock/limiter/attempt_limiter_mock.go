@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./utils/attempt_limiter.go
+
+// Package mock_limiter is a generated GoMock package.
+package mock_limiter
+
+import (
+	reflect "reflect"
+	utils "server/utils"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAttemptLimiter is a mock of AttemptLimiter interface.
+type MockAttemptLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttemptLimiterMockRecorder
+}
+
+// MockAttemptLimiterMockRecorder is the mock recorder for MockAttemptLimiter.
+type MockAttemptLimiterMockRecorder struct {
+	mock *MockAttemptLimiter
+}
+
+// NewMockAttemptLimiter creates a new mock instance.
+func NewMockAttemptLimiter(ctrl *gomock.Controller) *MockAttemptLimiter {
+	mock := &MockAttemptLimiter{ctrl: ctrl}
+	mock.recorder = &MockAttemptLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttemptLimiter) EXPECT() *MockAttemptLimiterMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockAttemptLimiter) Check(keys ...utils.AttemptKey) (time.Duration, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Check", varargs...)
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockAttemptLimiterMockRecorder) Check(keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockAttemptLimiter)(nil).Check), keys...)
+}
+
+// Fail mocks base method.
+func (m *MockAttemptLimiter) Fail(key utils.AttemptKey) (utils.AttemptStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", key)
+	ret0, _ := ret[0].(utils.AttemptStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockAttemptLimiterMockRecorder) Fail(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockAttemptLimiter)(nil).Fail), key)
+}
+
+// Reset mocks base method.
+func (m *MockAttemptLimiter) Reset(keys ...utils.AttemptKey) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Reset", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockAttemptLimiterMockRecorder) Reset(keys ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockAttemptLimiter)(nil).Reset), keys...)
+}
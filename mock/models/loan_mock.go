@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./models/loan.go
+
+// Package mock_models is a generated GoMock package.
+package mock_models
+
+import (
+	reflect "reflect"
+	models "server/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLoanFetcher is a mock of LoanFetcher interface.
+type MockLoanFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoanFetcherMockRecorder
+}
+
+// MockLoanFetcherMockRecorder is the mock recorder for MockLoanFetcher.
+type MockLoanFetcherMockRecorder struct {
+	mock *MockLoanFetcher
+}
+
+// NewMockLoanFetcher creates a new mock instance.
+func NewMockLoanFetcher(ctrl *gomock.Controller) *MockLoanFetcher {
+	mock := &MockLoanFetcher{ctrl: ctrl}
+	mock.recorder = &MockLoanFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoanFetcher) EXPECT() *MockLoanFetcherMockRecorder {
+	return m.recorder
+}
+
+// DeleteLoan mocks base method.
+func (m *MockLoanFetcher) DeleteLoan(data []models.DeleteLoanData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLoan", data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLoan indicates an expected call of DeleteLoan.
+func (mr *MockLoanFetcherMockRecorder) DeleteLoan(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLoan", reflect.TypeOf((*MockLoanFetcher)(nil).DeleteLoan), data)
+}
+
+// GetLoan mocks base method.
+func (m *MockLoanFetcher) GetLoan(LoanId string, UserId int) (models.LoanData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoan", LoanId, UserId)
+	ret0, _ := ret[0].(models.LoanData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoan indicates an expected call of GetLoan.
+func (mr *MockLoanFetcherMockRecorder) GetLoan(LoanId, UserId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoan", reflect.TypeOf((*MockLoanFetcher)(nil).GetLoan), LoanId, UserId)
+}
+
+// GetLoans mocks base method.
+func (m *MockLoanFetcher) GetLoans(UserId int) ([]models.LoanData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoans", UserId)
+	ret0, _ := ret[0].([]models.LoanData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoans indicates an expected call of GetLoans.
+func (mr *MockLoanFetcherMockRecorder) GetLoans(UserId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoans", reflect.TypeOf((*MockLoanFetcher)(nil).GetLoans), UserId)
+}
+
+// InsertLoan mocks base method.
+func (m *MockLoanFetcher) InsertLoan(data []models.InsertLoanData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertLoan", data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertLoan indicates an expected call of InsertLoan.
+func (mr *MockLoanFetcherMockRecorder) InsertLoan(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertLoan", reflect.TypeOf((*MockLoanFetcher)(nil).InsertLoan), data)
+}
+
+// UpdateLoan mocks base method.
+func (m *MockLoanFetcher) UpdateLoan(data []models.UpdateLoanData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLoan", data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLoan indicates an expected call of UpdateLoan.
+func (mr *MockLoanFetcherMockRecorder) UpdateLoan(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLoan", reflect.TypeOf((*MockLoanFetcher)(nil).UpdateLoan), data)
+}
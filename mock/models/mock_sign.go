@@ -5,8 +5,10 @@
 package mock_models
 
 import (
+	sql "database/sql"
 	reflect "reflect"
 	models "server/models"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,18 +36,61 @@ func (m *MockSignInFetcher) EXPECT() *MockSignInFetcherMockRecorder {
 	return m.recorder
 }
 
+// ChangeUserEmail mocks base method.
+func (m *MockSignInFetcher) ChangeUserEmail(UserId int, CurrentEmail, NewEmail string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeUserEmail", UserId, CurrentEmail, NewEmail)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeUserEmail indicates an expected call of ChangeUserEmail.
+func (mr *MockSignInFetcherMockRecorder) ChangeUserEmail(UserId, CurrentEmail, NewEmail interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeUserEmail", reflect.TypeOf((*MockSignInFetcher)(nil).ChangeUserEmail), UserId, CurrentEmail, NewEmail)
+}
+
+// CheckPasswordReuse mocks base method.
+func (m *MockSignInFetcher) CheckPasswordReuse(UserId int, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckPasswordReuse", UserId, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckPasswordReuse indicates an expected call of CheckPasswordReuse.
+func (mr *MockSignInFetcherMockRecorder) CheckPasswordReuse(UserId, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPasswordReuse", reflect.TypeOf((*MockSignInFetcher)(nil).CheckPasswordReuse), UserId, password)
+}
+
 // DeleteSignIn mocks base method.
-func (m *MockSignInFetcher) DeleteSignIn(data models.RequestSignInDeleteData) error {
+func (m *MockSignInFetcher) DeleteSignIn(userId int, data models.RequestSignInDeleteData, PurgeAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSignIn", data)
+	ret := m.ctrl.Call(m, "DeleteSignIn", userId, data, PurgeAt)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteSignIn indicates an expected call of DeleteSignIn.
-func (mr *MockSignInFetcherMockRecorder) DeleteSignIn(data interface{}) *gomock.Call {
+func (mr *MockSignInFetcherMockRecorder) DeleteSignIn(userId, data, PurgeAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSignIn", reflect.TypeOf((*MockSignInFetcher)(nil).DeleteSignIn), data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSignIn", reflect.TypeOf((*MockSignInFetcher)(nil).DeleteSignIn), userId, data, PurgeAt)
+}
+
+// GetExternalAuth mocks base method.
+func (m *MockSignInFetcher) GetExternalAuth(UserEmail string) (models.ExternalAuthData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExternalAuth", UserEmail)
+	ret0, _ := ret[0].(models.ExternalAuthData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExternalAuth indicates an expected call of GetExternalAuth.
+func (mr *MockSignInFetcherMockRecorder) GetExternalAuth(UserEmail interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalAuth", reflect.TypeOf((*MockSignInFetcher)(nil).GetExternalAuth), UserEmail)
 }
 
 // GetSignIn mocks base method.
@@ -63,45 +108,192 @@ func (mr *MockSignInFetcherMockRecorder) GetSignIn(data interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSignIn", reflect.TypeOf((*MockSignInFetcher)(nil).GetSignIn), data)
 }
 
-// PostSignUp mocks base method.
-func (m *MockSignInFetcher) PostSignUp(data models.RequestSignUpData) error {
+// GetUserAccount mocks base method.
+func (m *MockSignInFetcher) GetUserAccount(UserId int) (models.UserAccountData, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PostSignUp", data)
+	ret := m.ctrl.Call(m, "GetUserAccount", UserId)
+	ret0, _ := ret[0].(models.UserAccountData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserAccount indicates an expected call of GetUserAccount.
+func (mr *MockSignInFetcherMockRecorder) GetUserAccount(UserId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAccount", reflect.TypeOf((*MockSignInFetcher)(nil).GetUserAccount), UserId)
+}
+
+// GetUserEmail mocks base method.
+func (m *MockSignInFetcher) GetUserEmail(userId int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserEmail", userId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserEmail indicates an expected call of GetUserEmail.
+func (mr *MockSignInFetcherMockRecorder) GetUserEmail(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserEmail", reflect.TypeOf((*MockSignInFetcher)(nil).GetUserEmail), userId)
+}
+
+// GetUserId mocks base method.
+func (m *MockSignInFetcher) GetUserId(UserEmail string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserId", UserEmail)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserId indicates an expected call of GetUserId.
+func (mr *MockSignInFetcherMockRecorder) GetUserId(UserEmail interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserId", reflect.TypeOf((*MockSignInFetcher)(nil).GetUserId), UserEmail)
+}
+
+// InvalidatePassword mocks base method.
+func (m *MockSignInFetcher) InvalidatePassword(UserId int, unusablePassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidatePassword", UserId, unusablePassword)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// InvalidatePassword indicates an expected call of InvalidatePassword.
+func (mr *MockSignInFetcherMockRecorder) InvalidatePassword(UserId, unusablePassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidatePassword", reflect.TypeOf((*MockSignInFetcher)(nil).InvalidatePassword), UserId, unusablePassword)
+}
+
+// NewPasswordUpdate mocks base method.
+func (m *MockSignInFetcher) NewPasswordUpdate(UserId int, data models.RequestNewPasswordUpdateData) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewPasswordUpdate", UserId, data)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewPasswordUpdate indicates an expected call of NewPasswordUpdate.
+func (mr *MockSignInFetcherMockRecorder) NewPasswordUpdate(UserId, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewPasswordUpdate", reflect.TypeOf((*MockSignInFetcher)(nil).NewPasswordUpdate), UserId, data)
+}
+
+// PostSignUp mocks base method.
+func (m *MockSignInFetcher) PostSignUp(data models.RequestSignUpData) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostSignUp", data)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // PostSignUp indicates an expected call of PostSignUp.
 func (mr *MockSignInFetcherMockRecorder) PostSignUp(data interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostSignUp", reflect.TypeOf((*MockSignInFetcher)(nil).PostSignUp), data)
 }
 
+// PurgeDeactivatedUsers mocks base method.
+func (m *MockSignInFetcher) PurgeDeactivatedUsers(Now time.Time) ([]models.DeactivatedUserData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeactivatedUsers", Now)
+	ret0, _ := ret[0].([]models.DeactivatedUserData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeactivatedUsers indicates an expected call of PurgeDeactivatedUsers.
+func (mr *MockSignInFetcherMockRecorder) PurgeDeactivatedUsers(Now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeactivatedUsers", reflect.TypeOf((*MockSignInFetcher)(nil).PurgeDeactivatedUsers), Now)
+}
+
 // PutCheck mocks base method.
-func (m *MockSignInFetcher) PutCheck(data models.RequestSignInEditData) (string, error) {
+func (m *MockSignInFetcher) PutCheck(UserId int, data models.RequestSignInEditData) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PutCheck", data)
+	ret := m.ctrl.Call(m, "PutCheck", UserId, data)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // PutCheck indicates an expected call of PutCheck.
-func (mr *MockSignInFetcherMockRecorder) PutCheck(data interface{}) *gomock.Call {
+func (mr *MockSignInFetcherMockRecorder) PutCheck(UserId, data interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutCheck", reflect.TypeOf((*MockSignInFetcher)(nil).PutCheck), data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutCheck", reflect.TypeOf((*MockSignInFetcher)(nil).PutCheck), UserId, data)
 }
 
 // PutSignInEdit mocks base method.
-func (m *MockSignInFetcher) PutSignInEdit(data models.RequestSignInEditData) error {
+func (m *MockSignInFetcher) PutSignInEdit(UserId int, data models.RequestSignInEditData) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PutSignInEdit", data)
+	ret := m.ctrl.Call(m, "PutSignInEdit", UserId, data)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // PutSignInEdit indicates an expected call of PutSignInEdit.
-func (mr *MockSignInFetcherMockRecorder) PutSignInEdit(data interface{}) *gomock.Call {
+func (mr *MockSignInFetcherMockRecorder) PutSignInEdit(UserId, data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutSignInEdit", reflect.TypeOf((*MockSignInFetcher)(nil).PutSignInEdit), UserId, data)
+}
+
+// RestoreSignIn mocks base method.
+func (m *MockSignInFetcher) RestoreSignIn(UserId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSignIn", UserId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreSignIn indicates an expected call of RestoreSignIn.
+func (mr *MockSignInFetcherMockRecorder) RestoreSignIn(UserId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSignIn", reflect.TypeOf((*MockSignInFetcher)(nil).RestoreSignIn), UserId)
+}
+
+// MockpasswordHistoryQueryer is a mock of passwordHistoryQueryer interface.
+type MockpasswordHistoryQueryer struct {
+	ctrl     *gomock.Controller
+	recorder *MockpasswordHistoryQueryerMockRecorder
+}
+
+// MockpasswordHistoryQueryerMockRecorder is the mock recorder for MockpasswordHistoryQueryer.
+type MockpasswordHistoryQueryerMockRecorder struct {
+	mock *MockpasswordHistoryQueryer
+}
+
+// NewMockpasswordHistoryQueryer creates a new mock instance.
+func NewMockpasswordHistoryQueryer(ctrl *gomock.Controller) *MockpasswordHistoryQueryer {
+	mock := &MockpasswordHistoryQueryer{ctrl: ctrl}
+	mock.recorder = &MockpasswordHistoryQueryerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpasswordHistoryQueryer) EXPECT() *MockpasswordHistoryQueryerMockRecorder {
+	return m.recorder
+}
+
+// Query mocks base method.
+func (m *MockpasswordHistoryQueryer) Query(query string, args ...any) (*sql.Rows, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{query}
+	for _, a := range args {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Query", varargs...)
+	ret0, _ := ret[0].(*sql.Rows)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Query indicates an expected call of Query.
+func (mr *MockpasswordHistoryQueryerMockRecorder) Query(query interface{}, args ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutSignInEdit", reflect.TypeOf((*MockSignInFetcher)(nil).PutSignInEdit), data)
+	varargs := append([]interface{}{query}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockpasswordHistoryQueryer)(nil).Query), varargs...)
 }
@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./models/price_scenario.go
+
+// Package mock_models is a generated GoMock package.
+package mock_models
+
+import (
+	reflect "reflect"
+	models "server/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPriceScenarioFetcher is a mock of PriceScenarioFetcher interface.
+type MockPriceScenarioFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockPriceScenarioFetcherMockRecorder
+}
+
+// MockPriceScenarioFetcherMockRecorder is the mock recorder for MockPriceScenarioFetcher.
+type MockPriceScenarioFetcherMockRecorder struct {
+	mock *MockPriceScenarioFetcher
+}
+
+// NewMockPriceScenarioFetcher creates a new mock instance.
+func NewMockPriceScenarioFetcher(ctrl *gomock.Controller) *MockPriceScenarioFetcher {
+	mock := &MockPriceScenarioFetcher{ctrl: ctrl}
+	mock.recorder = &MockPriceScenarioFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPriceScenarioFetcher) EXPECT() *MockPriceScenarioFetcherMockRecorder {
+	return m.recorder
+}
+
+// ActivatePriceScenario mocks base method.
+func (m *MockPriceScenarioFetcher) ActivatePriceScenario(ScenarioId string, UserId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivatePriceScenario", ScenarioId, UserId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ActivatePriceScenario indicates an expected call of ActivatePriceScenario.
+func (mr *MockPriceScenarioFetcherMockRecorder) ActivatePriceScenario(ScenarioId, UserId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivatePriceScenario", reflect.TypeOf((*MockPriceScenarioFetcher)(nil).ActivatePriceScenario), ScenarioId, UserId)
+}
+
+// DeletePriceScenario mocks base method.
+func (m *MockPriceScenarioFetcher) DeletePriceScenario(data []models.DeletePriceScenarioData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePriceScenario", data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePriceScenario indicates an expected call of DeletePriceScenario.
+func (mr *MockPriceScenarioFetcherMockRecorder) DeletePriceScenario(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePriceScenario", reflect.TypeOf((*MockPriceScenarioFetcher)(nil).DeletePriceScenario), data)
+}
+
+// GetActivePriceScenario mocks base method.
+func (m *MockPriceScenarioFetcher) GetActivePriceScenario(UserId int) (models.PriceScenarioData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActivePriceScenario", UserId)
+	ret0, _ := ret[0].(models.PriceScenarioData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActivePriceScenario indicates an expected call of GetActivePriceScenario.
+func (mr *MockPriceScenarioFetcherMockRecorder) GetActivePriceScenario(UserId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActivePriceScenario", reflect.TypeOf((*MockPriceScenarioFetcher)(nil).GetActivePriceScenario), UserId)
+}
+
+// GetPriceScenarios mocks base method.
+func (m *MockPriceScenarioFetcher) GetPriceScenarios(UserId int) ([]models.PriceScenarioData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriceScenarios", UserId)
+	ret0, _ := ret[0].([]models.PriceScenarioData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriceScenarios indicates an expected call of GetPriceScenarios.
+func (mr *MockPriceScenarioFetcherMockRecorder) GetPriceScenarios(UserId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceScenarios", reflect.TypeOf((*MockPriceScenarioFetcher)(nil).GetPriceScenarios), UserId)
+}
+
+// InsertPriceScenario mocks base method.
+func (m *MockPriceScenarioFetcher) InsertPriceScenario(data []models.InsertPriceScenarioData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertPriceScenario", data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertPriceScenario indicates an expected call of InsertPriceScenario.
+func (mr *MockPriceScenarioFetcherMockRecorder) InsertPriceScenario(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPriceScenario", reflect.TypeOf((*MockPriceScenarioFetcher)(nil).InsertPriceScenario), data)
+}
@@ -0,0 +1,121 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./models/savings_goal.go
+
+// Package mock_models is a generated GoMock package.
+package mock_models
+
+import (
+	reflect "reflect"
+	models "server/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSavingsGoalFetcher is a mock of SavingsGoalFetcher interface.
+type MockSavingsGoalFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockSavingsGoalFetcherMockRecorder
+}
+
+// MockSavingsGoalFetcherMockRecorder is the mock recorder for MockSavingsGoalFetcher.
+type MockSavingsGoalFetcherMockRecorder struct {
+	mock *MockSavingsGoalFetcher
+}
+
+// NewMockSavingsGoalFetcher creates a new mock instance.
+func NewMockSavingsGoalFetcher(ctrl *gomock.Controller) *MockSavingsGoalFetcher {
+	mock := &MockSavingsGoalFetcher{ctrl: ctrl}
+	mock.recorder = &MockSavingsGoalFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSavingsGoalFetcher) EXPECT() *MockSavingsGoalFetcherMockRecorder {
+	return m.recorder
+}
+
+// DeleteSavingsGoal mocks base method.
+func (m *MockSavingsGoalFetcher) DeleteSavingsGoal(data []models.DeleteSavingsGoalData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSavingsGoal", data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSavingsGoal indicates an expected call of DeleteSavingsGoal.
+func (mr *MockSavingsGoalFetcherMockRecorder) DeleteSavingsGoal(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSavingsGoal", reflect.TypeOf((*MockSavingsGoalFetcher)(nil).DeleteSavingsGoal), data)
+}
+
+// GetSavingsGoalSnapshots mocks base method.
+func (m *MockSavingsGoalFetcher) GetSavingsGoalSnapshots(GoalId string, UserId int) ([]models.SavingsGoalSnapshotData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSavingsGoalSnapshots", GoalId, UserId)
+	ret0, _ := ret[0].([]models.SavingsGoalSnapshotData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSavingsGoalSnapshots indicates an expected call of GetSavingsGoalSnapshots.
+func (mr *MockSavingsGoalFetcherMockRecorder) GetSavingsGoalSnapshots(GoalId, UserId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSavingsGoalSnapshots", reflect.TypeOf((*MockSavingsGoalFetcher)(nil).GetSavingsGoalSnapshots), GoalId, UserId)
+}
+
+// GetSavingsGoals mocks base method.
+func (m *MockSavingsGoalFetcher) GetSavingsGoals(UserId int) ([]models.SavingsGoalData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSavingsGoals", UserId)
+	ret0, _ := ret[0].([]models.SavingsGoalData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSavingsGoals indicates an expected call of GetSavingsGoals.
+func (mr *MockSavingsGoalFetcherMockRecorder) GetSavingsGoals(UserId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSavingsGoals", reflect.TypeOf((*MockSavingsGoalFetcher)(nil).GetSavingsGoals), UserId)
+}
+
+// InsertSavingsGoal mocks base method.
+func (m *MockSavingsGoalFetcher) InsertSavingsGoal(data []models.InsertSavingsGoalData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSavingsGoal", data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertSavingsGoal indicates an expected call of InsertSavingsGoal.
+func (mr *MockSavingsGoalFetcherMockRecorder) InsertSavingsGoal(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSavingsGoal", reflect.TypeOf((*MockSavingsGoalFetcher)(nil).InsertSavingsGoal), data)
+}
+
+// InsertSavingsGoalSnapshot mocks base method.
+func (m *MockSavingsGoalFetcher) InsertSavingsGoalSnapshot(data []models.SavingsGoalSnapshotData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertSavingsGoalSnapshot", data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertSavingsGoalSnapshot indicates an expected call of InsertSavingsGoalSnapshot.
+func (mr *MockSavingsGoalFetcherMockRecorder) InsertSavingsGoalSnapshot(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSavingsGoalSnapshot", reflect.TypeOf((*MockSavingsGoalFetcher)(nil).InsertSavingsGoalSnapshot), data)
+}
+
+// UpdateSavingsGoal mocks base method.
+func (m *MockSavingsGoalFetcher) UpdateSavingsGoal(data []models.UpdateSavingsGoalData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSavingsGoal", data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSavingsGoal indicates an expected call of UpdateSavingsGoal.
+func (mr *MockSavingsGoalFetcherMockRecorder) UpdateSavingsGoal(data interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSavingsGoal", reflect.TypeOf((*MockSavingsGoalFetcher)(nil).UpdateSavingsGoal), data)
+}
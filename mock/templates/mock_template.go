@@ -33,6 +33,54 @@ func (m *MockEmailTemplateService) EXPECT() *MockEmailTemplateServiceMockRecorde
 	return m.recorder
 }
 
+// AccountDeactivatedTemplate mocks base method.
+func (m *MockEmailTemplateService) AccountDeactivatedTemplate(Name, UserEmail, Link, PurgeDate, DateTime string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountDeactivatedTemplate", Name, UserEmail, Link, PurgeDate, DateTime)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AccountDeactivatedTemplate indicates an expected call of AccountDeactivatedTemplate.
+func (mr *MockEmailTemplateServiceMockRecorder) AccountDeactivatedTemplate(Name, UserEmail, Link, PurgeDate, DateTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountDeactivatedTemplate", reflect.TypeOf((*MockEmailTemplateService)(nil).AccountDeactivatedTemplate), Name, UserEmail, Link, PurgeDate, DateTime)
+}
+
+// AccountLockedTemplate mocks base method.
+func (m *MockEmailTemplateService) AccountLockedTemplate(UserEmail, Link, DateTime string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountLockedTemplate", UserEmail, Link, DateTime)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AccountLockedTemplate indicates an expected call of AccountLockedTemplate.
+func (mr *MockEmailTemplateServiceMockRecorder) AccountLockedTemplate(UserEmail, Link, DateTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountLockedTemplate", reflect.TypeOf((*MockEmailTemplateService)(nil).AccountLockedTemplate), UserEmail, Link, DateTime)
+}
+
+// DataExportReadyTemplate mocks base method.
+func (m *MockEmailTemplateService) DataExportReadyTemplate(UserEmail, Link, ExpireDate, DateTime string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DataExportReadyTemplate", UserEmail, Link, ExpireDate, DateTime)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// DataExportReadyTemplate indicates an expected call of DataExportReadyTemplate.
+func (mr *MockEmailTemplateServiceMockRecorder) DataExportReadyTemplate(UserEmail, Link, ExpireDate, DateTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DataExportReadyTemplate", reflect.TypeOf((*MockEmailTemplateService)(nil).DataExportReadyTemplate), UserEmail, Link, ExpireDate, DateTime)
+}
+
 // DeleteSignInTemplate mocks base method.
 func (m *MockEmailTemplateService) DeleteSignInTemplate(Name, UserEmail, DateTime string) (string, string, error) {
 	m.ctrl.T.Helper()
@@ -49,10 +97,74 @@ func (mr *MockEmailTemplateServiceMockRecorder) DeleteSignInTemplate(Name, UserE
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSignInTemplate", reflect.TypeOf((*MockEmailTemplateService)(nil).DeleteSignInTemplate), Name, UserEmail, DateTime)
 }
 
+// EmailChangeCanceledTemplate mocks base method.
+func (m *MockEmailTemplateService) EmailChangeCanceledTemplate(UserEmail, DateTime string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmailChangeCanceledTemplate", UserEmail, DateTime)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EmailChangeCanceledTemplate indicates an expected call of EmailChangeCanceledTemplate.
+func (mr *MockEmailTemplateServiceMockRecorder) EmailChangeCanceledTemplate(UserEmail, DateTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmailChangeCanceledTemplate", reflect.TypeOf((*MockEmailTemplateService)(nil).EmailChangeCanceledTemplate), UserEmail, DateTime)
+}
+
+// EmailChangeConfirmTemplate mocks base method.
+func (m *MockEmailTemplateService) EmailChangeConfirmTemplate(UserEmail, Link, DateTime string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmailChangeConfirmTemplate", UserEmail, Link, DateTime)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EmailChangeConfirmTemplate indicates an expected call of EmailChangeConfirmTemplate.
+func (mr *MockEmailTemplateServiceMockRecorder) EmailChangeConfirmTemplate(UserEmail, Link, DateTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmailChangeConfirmTemplate", reflect.TypeOf((*MockEmailTemplateService)(nil).EmailChangeConfirmTemplate), UserEmail, Link, DateTime)
+}
+
+// EmailChangeNoticeTemplate mocks base method.
+func (m *MockEmailTemplateService) EmailChangeNoticeTemplate(UserEmail, Link, DateTime string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmailChangeNoticeTemplate", UserEmail, Link, DateTime)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EmailChangeNoticeTemplate indicates an expected call of EmailChangeNoticeTemplate.
+func (mr *MockEmailTemplateServiceMockRecorder) EmailChangeNoticeTemplate(UserEmail, Link, DateTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmailChangeNoticeTemplate", reflect.TypeOf((*MockEmailTemplateService)(nil).EmailChangeNoticeTemplate), UserEmail, Link, DateTime)
+}
+
+// MagicLinkTemplate mocks base method.
+func (m *MockEmailTemplateService) MagicLinkTemplate(UserEmail, Link, ExpireDate, DateTime string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MagicLinkTemplate", UserEmail, Link, ExpireDate, DateTime)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// MagicLinkTemplate indicates an expected call of MagicLinkTemplate.
+func (mr *MockEmailTemplateServiceMockRecorder) MagicLinkTemplate(UserEmail, Link, ExpireDate, DateTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MagicLinkTemplate", reflect.TypeOf((*MockEmailTemplateService)(nil).MagicLinkTemplate), UserEmail, Link, ExpireDate, DateTime)
+}
+
 // NewPasswordUpdateTemplate mocks base method.
-func (m *MockEmailTemplateService) NewPasswordUpdateTemplate(NewPassword, DateTime string) (string, string, error) {
+func (m *MockEmailTemplateService) NewPasswordUpdateTemplate(DateTime string) (string, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewPasswordUpdateTemplate", NewPassword, DateTime)
+	ret := m.ctrl.Call(m, "NewPasswordUpdateTemplate", DateTime)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(string)
 	ret2, _ := ret[2].(error)
@@ -60,9 +172,9 @@ func (m *MockEmailTemplateService) NewPasswordUpdateTemplate(NewPassword, DateTi
 }
 
 // NewPasswordUpdateTemplate indicates an expected call of NewPasswordUpdateTemplate.
-func (mr *MockEmailTemplateServiceMockRecorder) NewPasswordUpdateTemplate(NewPassword, DateTime interface{}) *gomock.Call {
+func (mr *MockEmailTemplateServiceMockRecorder) NewPasswordUpdateTemplate(DateTime interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewPasswordUpdateTemplate", reflect.TypeOf((*MockEmailTemplateService)(nil).NewPasswordUpdateTemplate), NewPassword, DateTime)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewPasswordUpdateTemplate", reflect.TypeOf((*MockEmailTemplateService)(nil).NewPasswordUpdateTemplate), DateTime)
 }
 
 // PostSignInEditTemplate mocks base method.
@@ -113,6 +225,22 @@ func (mr *MockEmailTemplateServiceMockRecorder) PostSignUpTemplate(Name, UserEma
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostSignUpTemplate", reflect.TypeOf((*MockEmailTemplateService)(nil).PostSignUpTemplate), Name, UserEmail, DateTime)
 }
 
+// RefreshTokenReuseTemplate mocks base method.
+func (m *MockEmailTemplateService) RefreshTokenReuseTemplate(UserEmail, DateTime string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshTokenReuseTemplate", UserEmail, DateTime)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RefreshTokenReuseTemplate indicates an expected call of RefreshTokenReuseTemplate.
+func (mr *MockEmailTemplateServiceMockRecorder) RefreshTokenReuseTemplate(UserEmail, DateTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokenReuseTemplate", reflect.TypeOf((*MockEmailTemplateService)(nil).RefreshTokenReuseTemplate), UserEmail, DateTime)
+}
+
 // RegisterEmailCheckNoticeTemplate mocks base method.
 func (m *MockEmailTemplateService) RegisterEmailCheckNoticeTemplate(Link, DateTime string) (string, string, error) {
 	m.ctrl.T.Helper()
@@ -37,11 +37,12 @@ func (m *MockUtilsFetcher) EXPECT() *MockUtilsFetcherMockRecorder {
 }
 
 // CompareHashPassword mocks base method.
-func (m *MockUtilsFetcher) CompareHashPassword(hashedPassword, requestPassword string) error {
+func (m *MockUtilsFetcher) CompareHashPassword(hashedPassword, requestPassword string) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CompareHashPassword", hashedPassword, requestPassword)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CompareHashPassword indicates an expected call of CompareHashPassword.
@@ -50,6 +51,21 @@ func (mr *MockUtilsFetcherMockRecorder) CompareHashPassword(hashedPassword, requ
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareHashPassword", reflect.TypeOf((*MockUtilsFetcher)(nil).CompareHashPassword), hashedPassword, requestPassword)
 }
 
+// ConsumeMagicLinkToken mocks base method.
+func (m *MockUtilsFetcher) ConsumeMagicLinkToken(magicLinkToken, DeviceId string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeMagicLinkToken", magicLinkToken, DeviceId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeMagicLinkToken indicates an expected call of ConsumeMagicLinkToken.
+func (mr *MockUtilsFetcherMockRecorder) ConsumeMagicLinkToken(magicLinkToken, DeviceId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeMagicLinkToken", reflect.TypeOf((*MockUtilsFetcher)(nil).ConsumeMagicLinkToken), magicLinkToken, DeviceId)
+}
+
 // DateTimeStr mocks base method.
 func (m *MockUtilsFetcher) DateTimeStr(t time.Time, format string) string {
 	m.ctrl.T.Helper()
@@ -94,6 +110,21 @@ func (mr *MockUtilsFetcherMockRecorder) GenerateJWT(UserId, ExpirationDate inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateJWT", reflect.TypeOf((*MockUtilsFetcher)(nil).GenerateJWT), UserId, ExpirationDate)
 }
 
+// IsTokenRevoked mocks base method.
+func (m *MockUtilsFetcher) IsTokenRevoked(claims jwt.MapClaims) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", claims)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockUtilsFetcherMockRecorder) IsTokenRevoked(claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockUtilsFetcher)(nil).IsTokenRevoked), claims)
+}
+
 // MapClaims mocks base method.
 func (m *MockUtilsFetcher) MapClaims(token *jwt.Token) (interface{}, bool) {
 	m.ctrl.T.Helper()
@@ -109,19 +140,34 @@ func (mr *MockUtilsFetcherMockRecorder) MapClaims(token interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MapClaims", reflect.TypeOf((*MockUtilsFetcher)(nil).MapClaims), token)
 }
 
+// NewMagicLinkToken mocks base method.
+func (m *MockUtilsFetcher) NewMagicLinkToken(UserId int, DeviceId string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewMagicLinkToken", UserId, DeviceId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NewMagicLinkToken indicates an expected call of NewMagicLinkToken.
+func (mr *MockUtilsFetcherMockRecorder) NewMagicLinkToken(UserId, DeviceId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewMagicLinkToken", reflect.TypeOf((*MockUtilsFetcher)(nil).NewMagicLinkToken), UserId, DeviceId)
+}
+
 // NewToken mocks base method.
-func (m *MockUtilsFetcher) NewToken(UserId, ExpirationDate int) (string, error) {
+func (m *MockUtilsFetcher) NewToken(UserId int, SessionId, Role string, ExpirationDate int) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewToken", UserId, ExpirationDate)
+	ret := m.ctrl.Call(m, "NewToken", UserId, SessionId, Role, ExpirationDate)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // NewToken indicates an expected call of NewToken.
-func (mr *MockUtilsFetcherMockRecorder) NewToken(UserId, ExpirationDate interface{}) *gomock.Call {
+func (mr *MockUtilsFetcherMockRecorder) NewToken(UserId, SessionId, Role, ExpirationDate interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewToken", reflect.TypeOf((*MockUtilsFetcher)(nil).NewToken), UserId, ExpirationDate)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewToken", reflect.TypeOf((*MockUtilsFetcher)(nil).NewToken), UserId, SessionId, Role, ExpirationDate)
 }
 
 // ParseWithClaims mocks base method.
@@ -140,18 +186,89 @@ func (mr *MockUtilsFetcherMockRecorder) ParseWithClaims(validationToken interfac
 }
 
 // RefreshToken mocks base method.
-func (m *MockUtilsFetcher) RefreshToken(UserId, ExpirationDate int) (string, error) {
+func (m *MockUtilsFetcher) RefreshToken(UserId int, SessionId string, ExpirationDate int) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RefreshToken", UserId, ExpirationDate)
+	ret := m.ctrl.Call(m, "RefreshToken", UserId, SessionId, ExpirationDate)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // RefreshToken indicates an expected call of RefreshToken.
-func (mr *MockUtilsFetcherMockRecorder) RefreshToken(UserId, ExpirationDate interface{}) *gomock.Call {
+func (mr *MockUtilsFetcherMockRecorder) RefreshToken(UserId, SessionId, ExpirationDate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockUtilsFetcher)(nil).RefreshToken), UserId, SessionId, ExpirationDate)
+}
+
+// RevokeRefreshTokenFamily mocks base method.
+func (m *MockUtilsFetcher) RevokeRefreshTokenFamily(refreshToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRefreshTokenFamily", refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRefreshTokenFamily indicates an expected call of RevokeRefreshTokenFamily.
+func (mr *MockUtilsFetcherMockRecorder) RevokeRefreshTokenFamily(refreshToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRefreshTokenFamily", reflect.TypeOf((*MockUtilsFetcher)(nil).RevokeRefreshTokenFamily), refreshToken)
+}
+
+// RevokeSession mocks base method.
+func (m *MockUtilsFetcher) RevokeSession(SessionId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", SessionId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockUtilsFetcherMockRecorder) RevokeSession(SessionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockUtilsFetcher)(nil).RevokeSession), SessionId)
+}
+
+// RevokeToken mocks base method.
+func (m *MockUtilsFetcher) RevokeToken(claims jwt.MapClaims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", claims)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockUtilsFetcherMockRecorder) RevokeToken(claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockUtilsFetcher)(nil).RevokeToken), claims)
+}
+
+// RevokeUserTokens mocks base method.
+func (m *MockUtilsFetcher) RevokeUserTokens(UserId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserTokens", UserId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserTokens indicates an expected call of RevokeUserTokens.
+func (mr *MockUtilsFetcherMockRecorder) RevokeUserTokens(UserId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserTokens", reflect.TypeOf((*MockUtilsFetcher)(nil).RevokeUserTokens), UserId)
+}
+
+// RotateRefreshToken mocks base method.
+func (m *MockUtilsFetcher) RotateRefreshToken(claims jwt.MapClaims, ExpirationDate int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateRefreshToken", claims, ExpirationDate)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateRefreshToken indicates an expected call of RotateRefreshToken.
+func (mr *MockUtilsFetcherMockRecorder) RotateRefreshToken(claims, ExpirationDate interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockUtilsFetcher)(nil).RefreshToken), UserId, ExpirationDate)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateRefreshToken", reflect.TypeOf((*MockUtilsFetcher)(nil).RotateRefreshToken), claims, ExpirationDate)
 }
 
 // SendMail mocks base method.
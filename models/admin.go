@@ -0,0 +1,351 @@
+// models/admin.go
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"server/DB"
+	"strings"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/lib/pq"
+)
+
+type (
+	AdminFetcher interface {
+		GetUserRole(UserId int) (UserRoleData, error)
+		GetAdminUsers(data RequestAdminUsersData) ([]AdminUserData, int, error)
+		SetUserDisabled(UserId int, Disabled bool) error
+		GetSignUpStats(From, To time.Time) (SignUpStatsData, error)
+		InsertAuditLog(data AuditLogData) error
+		GetAuditLogs(TargetUserId, Limit, Offset int) ([]AuditLogData, int, error)
+	}
+
+	UserRoleData struct {
+		Role string
+		// 管理者に無効化されている場合はtrue
+		Disabled bool
+		// 退会中(削除予定日時まで復元できる)の場合はtrue
+		Deactivated bool
+	}
+
+	RequestAdminUsersData struct {
+		// メールアドレス又はユーザー名の部分一致(空の場合は全件)
+		Search string
+		// ロールの完全一致(空の場合は全件)
+		Role   string
+		Limit  int
+		Offset int
+	}
+
+	AdminUserData struct {
+		UserId    int    `json:"user_id"`
+		UserEmail string `json:"user_email"`
+		UserName  string `json:"user_name"`
+		Role      string `json:"role"`
+		// パスワードで登録したユーザーの場合はtrue
+		PasswordAccount bool       `json:"password_account"`
+		DisabledAt      *time.Time `json:"disabled_at"`
+		CreatedAt       time.Time  `json:"created_at"`
+	}
+
+	SignUpStatsData struct {
+		TotalUsers    int                `json:"total_users"`
+		DisabledUsers int                `json:"disabled_users"`
+		SignUps       int                `json:"sign_ups"`
+		Daily         []DailySignUpsData `json:"daily"`
+	}
+
+	DailySignUpsData struct {
+		Date  string `json:"date"`
+		Count int    `json:"count"`
+	}
+
+	AuditLogData struct {
+		AuditId     string `json:"audit_id"`
+		ActorUserId int    `json:"actor_user_id"`
+		ActorRole   string `json:"actor_role"`
+		Action      string `json:"action"`
+		// 特定のユーザーに対する操作でない場合はnil
+		TargetUserId *int      `json:"target_user_id"`
+		Detail       string    `json:"detail"`
+		IpAddress    string    `json:"ip_address"`
+		CreatedAt    time.Time `json:"created_at"`
+	}
+
+	AdminDataFetcher struct{ db *sql.DB }
+)
+
+// ErrUserNotFound は対象のユーザーが登録されていない場合のエラー
+var ErrUserNotFound = errors.New("登録ユーザーが存在しません")
+
+// LIKE の検索文字列で特殊な意味を持つ文字をエスケープする
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func NewAdminDataFetcher(dataSourceName string) (*AdminDataFetcher, sqlmock.Sqlmock, error) {
+	if dataSourceName == "test" {
+		db, mock, err := sqlmock.New()
+		return &AdminDataFetcher{db: db}, mock, err
+	} else {
+		// test実行時に以下のカバレッジは無視する
+		db, err := sql.Open("postgres", dataSourceName)
+		if err != nil {
+			log.Printf("sql.Open error %s", err)
+		}
+		return &AdminDataFetcher{db: db}, nil, nil
+	}
+}
+
+// GetUserRole はユーザーのロールと無効化・退会されているかを返す
+// トークンの発行時に呼び出し、ロールをクレームに含める
+//
+// 引数:
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: ユーザーのロール
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (adf *AdminDataFetcher) GetUserRole(UserId int) (UserRoleData, error) {
+	var data UserRoleData
+
+	defer adf.db.Close()
+
+	err := adf.db.QueryRow(DB.GetUserRoleSyntax, UserId).Scan(&data.Role, &data.Disabled, &data.Deactivated)
+	if errors.Is(err, sql.ErrNoRows) {
+		return data, ErrUserNotFound
+	} else if err != nil {
+		return data, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+
+	return data, nil
+}
+
+// GetAdminUsers は管理者用にユーザーを検索してユーザーIDの昇順で返す
+//
+// 引数:
+//   - data: 検索条件
+//
+// 戻り値:
+//
+//	戻り値1: 検索したユーザー
+//	戻り値2: ページングの前の総件数
+//	戻り値3: エラー内容(エラーがない場合はnil)
+//
+
+func (adf *AdminDataFetcher) GetAdminUsers(data RequestAdminUsersData) ([]AdminUserData, int, error) {
+	users := []AdminUserData{}
+	total := 0
+
+	defer adf.db.Close()
+
+	// データベースクエリを実行
+	rows, err := adf.db.Query(
+		DB.GetAdminUsersSyntax,
+		likeEscaper.Replace(data.Search),
+		data.Role,
+		data.Limit,
+		data.Offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record AdminUserData
+		var disabledAt sql.NullTime
+		if err := rows.Scan(
+			&record.UserId,
+			&record.UserEmail,
+			&record.UserName,
+			&record.Role,
+			&record.PasswordAccount,
+			&disabledAt,
+			&record.CreatedAt,
+			&total,
+		); err != nil {
+			return nil, 0, err
+		}
+		if disabledAt.Valid {
+			record.DisabledAt = &disabledAt.Time
+		}
+
+		users = append(users, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// SetUserDisabled はユーザーを無効化、又は無効化を解除する
+// 無効化されたユーザーはサインイン・トークンの再発行ができない
+//
+// 引数:
+//   - UserId: ユーザーID
+//   - Disabled: 無効化する場合はtrue
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (adf *AdminDataFetcher) SetUserDisabled(UserId int, Disabled bool) error {
+	now := time.Now()
+	// 初期値nullにするためポインター型で定義
+	var disabledAt *time.Time
+	if Disabled {
+		disabledAt = &now
+	}
+
+	defer adf.db.Close()
+
+	result, err := adf.db.Exec(DB.SetUserDisabledSyntax, disabledAt, now, UserId)
+	if err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+// GetSignUpStats はユーザー数と期間内の日別の登録数を返す
+//
+// 引数:
+//   - From: 集計の開始日時
+//   - To: 集計の終了日時(この日時を含まない)
+//
+// 戻り値:
+//
+//	戻り値1: 登録数の集計
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (adf *AdminDataFetcher) GetSignUpStats(From, To time.Time) (SignUpStatsData, error) {
+	data := SignUpStatsData{Daily: []DailySignUpsData{}}
+
+	defer adf.db.Close()
+
+	if err := adf.db.QueryRow(DB.GetUserCountsSyntax).Scan(&data.TotalUsers, &data.DisabledUsers); err != nil {
+		return data, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+
+	rows, err := adf.db.Query(DB.GetSignUpStatsSyntax, From, To)
+	if err != nil {
+		return data, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record DailySignUpsData
+		if err := rows.Scan(&record.Date, &record.Count); err != nil {
+			return data, err
+		}
+		data.SignUps += record.Count
+		data.Daily = append(data.Daily, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return data, err
+	}
+
+	return data, nil
+}
+
+// InsertAuditLog は管理者用APIの操作履歴を登録する
+//
+// 引数:
+//   - data: 登録する操作履歴
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (adf *AdminDataFetcher) InsertAuditLog(data AuditLogData) error {
+
+	defer adf.db.Close()
+
+	if _, err := adf.db.Exec(
+		DB.InsertAuditLogSyntax,
+		data.AuditId,
+		data.ActorUserId,
+		data.ActorRole,
+		data.Action,
+		data.TargetUserId,
+		data.Detail,
+		data.IpAddress,
+		data.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+
+	return nil
+}
+
+// GetAuditLogs は管理者用APIの操作履歴を新しい順で返す
+//
+// 引数:
+//   - TargetUserId: 対象ユーザーID(0の場合は全ての操作履歴)
+//   - Limit: 取得件数
+//   - Offset: 取得開始位置
+//
+// 戻り値:
+//
+//	戻り値1: 操作履歴
+//	戻り値2: ページングの前の総件数
+//	戻り値3: エラー内容(エラーがない場合はnil)
+//
+
+func (adf *AdminDataFetcher) GetAuditLogs(TargetUserId, Limit, Offset int) ([]AuditLogData, int, error) {
+	logs := []AuditLogData{}
+	total := 0
+
+	defer adf.db.Close()
+
+	rows, err := adf.db.Query(DB.GetAuditLogsSyntax, TargetUserId, Limit, Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record AuditLogData
+		var targetUserId sql.NullInt64
+		if err := rows.Scan(
+			&record.AuditId,
+			&record.ActorUserId,
+			&record.ActorRole,
+			&record.Action,
+			&targetUserId,
+			&record.Detail,
+			&record.IpAddress,
+			&record.CreatedAt,
+			&total,
+		); err != nil {
+			return nil, 0, err
+		}
+		if targetUserId.Valid {
+			userId := int(targetUserId.Int64)
+			record.TargetUserId = &userId
+		}
+
+		logs = append(logs, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
@@ -0,0 +1,347 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"server/DB"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var adminUserColumns = []string{"user_id", "user_email", "user_name", "role", "password_account", "disabled_at", "create_at", "total"}
+
+var auditLogColumns = []string{"audit_id", "actor_user_id", "actor_role", "action", "target_user_id", "detail", "ip_address", "created_at", "total"}
+
+func TestNewAdminDataFetcher(t *testing.T) {
+	// 通常のテストケース
+	dbFetcher, mock, err := NewAdminDataFetcher("test")
+	assert.NotNil(t, dbFetcher)
+	assert.NotNil(t, mock)
+	assert.NoError(t, err)
+
+	// カバレッジを通すためのテストケース
+	dbFetcher, _, err = NewAdminDataFetcher("")
+	assert.NotNil(t, dbFetcher)
+	assert.NoError(t, err)
+}
+
+func TestGetUserRole(t *testing.T) {
+	t.Run("success GetUserRole", func(t *testing.T) {
+		dbFetcher, mock, err := NewAdminDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetUserRoleSyntax)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"role", "disabled", "deactivated"}).AddRow("admin", false, true))
+
+		result, err := dbFetcher.GetUserRole(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, UserRoleData{Role: "admin", Disabled: false, Deactivated: true}, result)
+	})
+
+	t.Run("error GetUserRole ユーザーが存在しない", func(t *testing.T) {
+		dbFetcher, mock, err := NewAdminDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetUserRoleSyntax)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"role", "disabled", "deactivated"}))
+
+		_, err = dbFetcher.GetUserRole(1)
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("error GetUserRole クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewAdminDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetUserRoleSyntax)).
+			WithArgs(1).
+			WillReturnError(fmt.Errorf("database error"))
+
+		_, err = dbFetcher.GetUserRole(1)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestGetAdminUsers(t *testing.T) {
+	t.Run("success GetAdminUsers", func(t *testing.T) {
+		dbFetcher, mock, err := NewAdminDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		createdAt := time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC)
+		disabledAt := time.Date(2024, 4, 2, 10, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows(adminUserColumns).
+			AddRow(1, "test@example.com", "test", "user", true, nil, createdAt, 2).
+			AddRow(2, "test_2@gmail.com", "test_2", "user", false, disabledAt, createdAt, 2)
+		// LIKE の特殊文字はエスケープして検索する
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetAdminUsersSyntax)).
+			WithArgs(`test\_\%`, "user", 20, 0).
+			WillReturnRows(rows)
+
+		result, total, err := dbFetcher.GetAdminUsers(RequestAdminUsersData{Search: "test_%", Role: "user", Limit: 20})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Equal(t, []AdminUserData{
+			{UserId: 1, UserEmail: "test@example.com", UserName: "test", Role: "user", PasswordAccount: true, CreatedAt: createdAt},
+			{UserId: 2, UserEmail: "test_2@gmail.com", UserName: "test_2", Role: "user", DisabledAt: &disabledAt, CreatedAt: createdAt},
+		}, result)
+	})
+
+	t.Run("success GetAdminUsers 該当なし", func(t *testing.T) {
+		dbFetcher, mock, err := NewAdminDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetAdminUsersSyntax)).
+			WithArgs("", "", 20, 40).
+			WillReturnRows(sqlmock.NewRows(adminUserColumns))
+
+		result, total, err := dbFetcher.GetAdminUsers(RequestAdminUsersData{Limit: 20, Offset: 40})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, total)
+		assert.Equal(t, []AdminUserData{}, result)
+	})
+
+	t.Run("error GetAdminUsers クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewAdminDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetAdminUsersSyntax)).
+			WillReturnError(fmt.Errorf("database error"))
+
+		_, _, err = dbFetcher.GetAdminUsers(RequestAdminUsersData{Limit: 20})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestSetUserDisabled(t *testing.T) {
+	t.Run("success SetUserDisabled 無効化", func(t *testing.T) {
+		dbFetcher, mock, err := NewAdminDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.SetUserDisabledSyntax)).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = dbFetcher.SetUserDisabled(2, true)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success SetUserDisabled 無効化の解除", func(t *testing.T) {
+		dbFetcher, mock, err := NewAdminDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.SetUserDisabledSyntax)).
+			WithArgs(nil, sqlmock.AnyArg(), 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = dbFetcher.SetUserDisabled(2, false)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error SetUserDisabled ユーザーが存在しない", func(t *testing.T) {
+		dbFetcher, mock, err := NewAdminDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.SetUserDisabledSyntax)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = dbFetcher.SetUserDisabled(2, true)
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("error SetUserDisabled クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewAdminDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.SetUserDisabledSyntax)).
+			WillReturnError(fmt.Errorf("database error"))
+
+		err = dbFetcher.SetUserDisabled(2, true)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestGetSignUpStats(t *testing.T) {
+	from := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success GetSignUpStats", func(t *testing.T) {
+		dbFetcher, mock, err := NewAdminDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetUserCountsSyntax)).
+			WillReturnRows(sqlmock.NewRows([]string{"total", "disabled"}).AddRow(10, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSignUpStatsSyntax)).
+			WithArgs(from, to).
+			WillReturnRows(sqlmock.NewRows([]string{"date", "count"}).
+				AddRow("2024-04-01", 2).
+				AddRow("2024-04-03", 1))
+
+		result, err := dbFetcher.GetSignUpStats(from, to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, SignUpStatsData{
+			TotalUsers:    10,
+			DisabledUsers: 1,
+			SignUps:       3,
+			Daily: []DailySignUpsData{
+				{Date: "2024-04-01", Count: 2},
+				{Date: "2024-04-03", Count: 1},
+			},
+		}, result)
+	})
+
+	t.Run("error GetSignUpStats ユーザー数の取得エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewAdminDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetUserCountsSyntax)).
+			WillReturnError(fmt.Errorf("database error"))
+
+		_, err = dbFetcher.GetSignUpStats(from, to)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("error GetSignUpStats 登録数の取得エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewAdminDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetUserCountsSyntax)).
+			WillReturnRows(sqlmock.NewRows([]string{"total", "disabled"}).AddRow(10, 1))
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSignUpStatsSyntax)).
+			WillReturnError(fmt.Errorf("database error"))
+
+		_, err = dbFetcher.GetSignUpStats(from, to)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestInsertAuditLog(t *testing.T) {
+	targetUserId := 2
+	data := AuditLogData{
+		AuditId:      "b3f1c1a0-0000-4000-8000-000000000001",
+		ActorUserId:  1,
+		ActorRole:    "admin",
+		Action:       "user_disable",
+		TargetUserId: &targetUserId,
+		Detail:       "{}",
+		IpAddress:    "192.0.2.1",
+		CreatedAt:    time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("success InsertAuditLog", func(t *testing.T) {
+		dbFetcher, mock, err := NewAdminDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertAuditLogSyntax)).
+			WithArgs(data.AuditId, 1, "admin", "user_disable", &targetUserId, "{}", "192.0.2.1", data.CreatedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = dbFetcher.InsertAuditLog(data)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error InsertAuditLog クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewAdminDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertAuditLogSyntax)).
+			WillReturnError(fmt.Errorf("database error"))
+
+		err = dbFetcher.InsertAuditLog(data)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestGetAuditLogs(t *testing.T) {
+	t.Run("success GetAuditLogs", func(t *testing.T) {
+		dbFetcher, mock, err := NewAdminDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		createdAt := time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows(auditLogColumns).
+			AddRow("b3f1c1a0-0000-4000-8000-000000000002", 1, "admin", "user_disable", 2, "{}", "192.0.2.1", createdAt, 2).
+			AddRow("b3f1c1a0-0000-4000-8000-000000000001", 1, "support", "user_search", nil, `{"search":""}`, "192.0.2.1", createdAt, 2)
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetAuditLogsSyntax)).
+			WithArgs(0, 20, 0).
+			WillReturnRows(rows)
+
+		result, total, err := dbFetcher.GetAuditLogs(0, 20, 0)
+
+		targetUserId := 2
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Equal(t, []AuditLogData{
+			{AuditId: "b3f1c1a0-0000-4000-8000-000000000002", ActorUserId: 1, ActorRole: "admin", Action: "user_disable", TargetUserId: &targetUserId, Detail: "{}", IpAddress: "192.0.2.1", CreatedAt: createdAt},
+			{AuditId: "b3f1c1a0-0000-4000-8000-000000000001", ActorUserId: 1, ActorRole: "support", Action: "user_search", Detail: `{"search":""}`, IpAddress: "192.0.2.1", CreatedAt: createdAt},
+		}, result)
+	})
+
+	t.Run("error GetAuditLogs クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewAdminDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetAuditLogsSyntax)).
+			WillReturnError(fmt.Errorf("database error"))
+
+		_, _, err = dbFetcher.GetAuditLogs(0, 20, 0)
+
+		assert.Error(t, err)
+	})
+}
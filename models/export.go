@@ -0,0 +1,571 @@
+// models/export.go
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"server/DB"
+	"server/enum"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+type (
+	ExportFetcher interface {
+		InsertDataExport(data DataExportData, StaleBefore time.Time) error
+		CompleteDataExport(ExportId, FilePath, TokenHash string, ExpiresAt time.Time) error
+		FailDataExport(ExportId string) error
+		GetDataExport(TokenHash string, Now time.Time) (DataExportData, error)
+		DeleteExpiredDataExports(Now time.Time) ([]string, error)
+		GetExportProfile(UserId int) (ExportProfileData, error)
+		GetExportIncome(UserId int) ([]IncomeData, error)
+		GetExportAuditLogs(UserId int) ([]ExportAuditLogData, error)
+		ImportUserData(UserId int, data ImportData, DryRun bool) (ImportReportData, error)
+	}
+
+	DataExportData struct {
+		ExportId  string
+		UserId    int
+		Status    string
+		FilePath  string
+		CreatedAt time.Time
+		ExpiresAt time.Time
+	}
+
+	// エクスポートするユーザー情報(パスワードは含めない)
+	ExportProfileData struct {
+		UserId    int       `json:"user_id"`
+		UserEmail string    `json:"user_email"`
+		UserName  string    `json:"user_name"`
+		Role      string    `json:"role"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	// エクスポートする操作履歴(操作した管理者のユーザーID・IPアドレスは含めない)
+	ExportAuditLogData struct {
+		AuditId   uuid.UUID `json:"audit_id"`
+		ActorRole string    `json:"actor_role"`
+		Action    string    `json:"action"`
+		Detail    string    `json:"detail"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	// エクスポートファイル(manifest.json)の内容
+	ExportManifestData struct {
+		Version    int       `json:"version"`
+		UserId     int       `json:"user_id"`
+		ExportedAt time.Time `json:"exported_at"`
+	}
+
+	// インポートするデータ(ユーザーIDはインポート先のユーザーに置き換える)
+	ImportData struct {
+		Income         []InsertIncomeData
+		PriceScenarios []InsertPriceScenarioData
+		Loans          []InsertLoanData
+		SavingsGoals   []InsertSavingsGoalData
+	}
+
+	// インポートの結果(ドライランの場合は登録せずに件数のみ返す)
+	ImportReportData struct {
+		DryRun bool `json:"dry_run"`
+		// 登録データがなくインポートできる場合はtrue
+		Importable             bool `json:"importable"`
+		Income                 int  `json:"income"`
+		PriceScenarios         int  `json:"price_scenarios"`
+		Loans                  int  `json:"loans"`
+		SavingsGoals           int  `json:"savings_goals"`
+		ExistingIncome         int  `json:"existing_income"`
+		ExistingPriceScenarios int  `json:"existing_price_scenarios"`
+		ExistingLoans          int  `json:"existing_loans"`
+		ExistingSavingsGoals   int  `json:"existing_savings_goals"`
+	}
+
+	ExportDataFetcher struct{ db *sql.DB }
+)
+
+// ExportArchiveVersion はエクスポートファイルの形式のバージョン
+// ファイルの構成を変更した場合は値を上げる
+const ExportArchiveVersion = 1
+
+// ErrExportPending は作成中のエクスポートが既にある場合のエラー
+var ErrExportPending = errors.New("作成中のエクスポートがあります。")
+
+// ErrImportNotEmpty はインポート先のユーザーに登録データがある場合のエラー
+var ErrImportNotEmpty = errors.New("インポートは年収推移・収支シナリオ・ローン・貯蓄目標が未登録のアカウントのみ可能です。")
+
+// ErrExportNotFound はダウンロードできるエクスポートが存在しない場合のエラー
+var ErrExportNotFound = errors.New("ダウンロードできるエクスポートが存在しません。")
+
+func NewExportDataFetcher(dataSourceName string) (*ExportDataFetcher, sqlmock.Sqlmock, error) {
+	if dataSourceName == "test" {
+		db, mock, err := sqlmock.New()
+		return &ExportDataFetcher{db: db}, mock, err
+	} else {
+		// test実行時に以下のカバレッジは無視する
+		db, err := sql.Open("postgres", dataSourceName)
+		if err != nil {
+			log.Printf("sql.Open error %s", err)
+		}
+		return &ExportDataFetcher{db: db}, nil, nil
+	}
+}
+
+// InsertDataExport は作成中のエクスポートを登録する
+// 作成中のエクスポートはユーザーごとに1件のみとし、StaleBefore より前に作成を始めたものは失敗扱いにする
+//
+// 引数:
+//   - data: 登録するエクスポート
+//   - StaleBefore: 作成中のまま中断したとみなす作成日時
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(作成中のエクスポートがある場合はErrExportPending)
+//
+
+func (ef *ExportDataFetcher) InsertDataExport(data DataExportData, StaleBefore time.Time) error {
+
+	var err error
+
+	// データベースのクローズをdeferで最初に宣言
+	defer ef.db.Close()
+
+	// トランザクションを開始
+	tx, err := ef.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %v", err)
+	}
+
+	// ロールバックをデフォルトに設定
+	rollback := true
+	defer func() {
+		if rollback {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.Exec(
+		DB.FailStaleDataExportSyntax,
+		enum.EXPORT_STATUS_FAILED,
+		data.CreatedAt,
+		data.UserId,
+		enum.EXPORT_STATUS_PENDING,
+		StaleBefore,
+	); err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+
+	result, err := tx.Exec(
+		DB.InsertDataExportSyntax,
+		data.ExportId,
+		data.UserId,
+		enum.EXPORT_STATUS_PENDING,
+		data.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return ErrExportPending
+	}
+
+	// コミット処理
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %v", err)
+	}
+
+	rollback = false
+
+	return nil
+}
+
+// CompleteDataExport はエクスポートを作成済みにし、ダウンロード用トークンのハッシュを登録する
+//
+// 引数:
+//   - ExportId: エクスポートID
+//   - FilePath: 作成したファイルのパス
+//   - TokenHash: ダウンロード用トークンのハッシュ
+//   - ExpiresAt: ダウンロードの有効期限
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (ef *ExportDataFetcher) CompleteDataExport(ExportId, FilePath, TokenHash string, ExpiresAt time.Time) error {
+
+	defer ef.db.Close()
+
+	result, err := ef.db.Exec(
+		DB.CompleteDataExportSyntax,
+		enum.EXPORT_STATUS_READY,
+		FilePath,
+		TokenHash,
+		time.Now(),
+		ExpiresAt,
+		ExportId,
+	)
+	if err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return errors.New("対象のエクスポートが存在しません。")
+	}
+
+	return nil
+}
+
+// FailDataExport はエクスポートを失敗にする
+//
+// 引数:
+//   - ExportId: エクスポートID
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (ef *ExportDataFetcher) FailDataExport(ExportId string) error {
+
+	defer ef.db.Close()
+
+	if _, err := ef.db.Exec(DB.FailDataExportSyntax, enum.EXPORT_STATUS_FAILED, time.Now(), ExportId); err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+
+	return nil
+}
+
+// GetDataExport はダウンロード用トークンのハッシュから有効期限内の作成済みエクスポートを返す
+//
+// 引数:
+//   - TokenHash: ダウンロード用トークンのハッシュ
+//   - Now: 基準日時
+//
+// 戻り値:
+//
+//	戻り値1: エクスポート
+//	戻り値2: エラー内容(存在しない場合はErrExportNotFound)
+//
+
+func (ef *ExportDataFetcher) GetDataExport(TokenHash string, Now time.Time) (DataExportData, error) {
+	var data DataExportData
+
+	defer ef.db.Close()
+
+	err := ef.db.QueryRow(DB.GetDataExportSyntax, TokenHash, enum.EXPORT_STATUS_READY, Now).Scan(
+		&data.ExportId,
+		&data.UserId,
+		&data.Status,
+		&data.FilePath,
+		&data.CreatedAt,
+		&data.ExpiresAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return data, ErrExportNotFound
+	} else if err != nil {
+		return data, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+
+	return data, nil
+}
+
+// DeleteExpiredDataExports は有効期限を過ぎたエクスポートを削除する
+//
+// 引数:
+//   - Now: 基準日時
+//
+// 戻り値:
+//
+//	戻り値1: 削除したエクスポートのファイルのパス(ファイルがない場合は空文字)
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (ef *ExportDataFetcher) DeleteExpiredDataExports(Now time.Time) ([]string, error) {
+	filePaths := []string{}
+
+	defer ef.db.Close()
+
+	rows, err := ef.db.Query(DB.DeleteExpiredDataExportsSyntax, Now)
+	if err != nil {
+		return nil, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var filePath string
+		if err := rows.Scan(&filePath); err != nil {
+			return nil, err
+		}
+		filePaths = append(filePaths, filePath)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return filePaths, nil
+}
+
+// GetExportProfile はエクスポートするユーザー情報を返す
+//
+// 引数:
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: ユーザー情報
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (ef *ExportDataFetcher) GetExportProfile(UserId int) (ExportProfileData, error) {
+	var data ExportProfileData
+
+	defer ef.db.Close()
+
+	err := ef.db.QueryRow(DB.GetExportProfileSyntax, UserId).Scan(
+		&data.UserId,
+		&data.UserEmail,
+		&data.UserName,
+		&data.Role,
+		&data.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return data, ErrUserNotFound
+	} else if err != nil {
+		return data, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+
+	return data, nil
+}
+
+// GetExportIncome は対象ユーザーの年収推移データを支給日の昇順で全て返す
+//
+// 引数:
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: 年収推移データ
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (ef *ExportDataFetcher) GetExportIncome(UserId int) ([]IncomeData, error) {
+	incomeData := []IncomeData{}
+
+	defer ef.db.Close()
+
+	rows, err := ef.db.Query(DB.GetExportIncomeSyntax, UserId)
+	if err != nil {
+		return nil, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data IncomeData
+		if err := rows.Scan(
+			&data.IncomeForecastID,
+			&data.PaymentDate,
+			&data.Age,
+			&data.Industry,
+			&data.TotalAmount,
+			&data.DeductionAmount,
+			&data.TakeHomeAmount,
+			&data.Classification,
+			&data.UserID,
+		); err != nil {
+			return nil, err
+		}
+		incomeData = append(incomeData, data)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return incomeData, nil
+}
+
+// GetExportAuditLogs は対象ユーザーに対する管理者の操作履歴を古い順で全て返す
+//
+// 引数:
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: 操作履歴
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (ef *ExportDataFetcher) GetExportAuditLogs(UserId int) ([]ExportAuditLogData, error) {
+	logs := []ExportAuditLogData{}
+
+	defer ef.db.Close()
+
+	rows, err := ef.db.Query(DB.GetExportAuditLogsSyntax, UserId)
+	if err != nil {
+		return nil, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record ExportAuditLogData
+		if err := rows.Scan(
+			&record.AuditId,
+			&record.ActorRole,
+			&record.Action,
+			&record.Detail,
+			&record.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		logs = append(logs, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// ImportUserData はエクスポートしたデータをトランザクション内で登録する
+// 重複して登録しないよう、年収推移・収支シナリオ・ローン・貯蓄目標が未登録のユーザーのみインポートできる
+// ドライランの場合も同じ登録を行い、DBの制約を確認してからロールバックする
+//
+// 引数:
+//   - UserId: インポート先のユーザーID
+//   - data: インポートするデータ
+//   - DryRun: 登録せずに結果のみ返す場合はtrue
+//
+// 戻り値:
+//
+//	戻り値1: インポートの結果
+//	戻り値2: エラー内容(登録データがある場合はErrImportNotEmpty)
+//
+
+func (ef *ExportDataFetcher) ImportUserData(UserId int, data ImportData, DryRun bool) (ImportReportData, error) {
+	report := ImportReportData{
+		DryRun:         DryRun,
+		Income:         len(data.Income),
+		PriceScenarios: len(data.PriceScenarios),
+		Loans:          len(data.Loans),
+		SavingsGoals:   len(data.SavingsGoals),
+	}
+	var err error
+	createdAt := time.Now()
+
+	// データベースのクローズをdeferで最初に宣言
+	defer ef.db.Close()
+
+	// トランザクションを開始
+	tx, err := ef.db.Begin()
+	if err != nil {
+		return report, fmt.Errorf("トランザクションの開始に失敗しました: %v", err)
+	}
+
+	// ロールバックをデフォルトに設定
+	rollback := true
+	defer func() {
+		if rollback {
+			tx.Rollback()
+		}
+	}()
+
+	err = tx.QueryRow(DB.LockImportUserSyntax, UserId).Scan(
+		&report.ExistingIncome,
+		&report.ExistingPriceScenarios,
+		&report.ExistingLoans,
+		&report.ExistingSavingsGoals,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return report, ErrUserNotFound
+	} else if err != nil {
+		return report, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	report.Importable = report.ExistingIncome == 0 && report.ExistingPriceScenarios == 0 &&
+		report.ExistingLoans == 0 && report.ExistingSavingsGoals == 0
+	if !report.Importable && !DryRun {
+		return report, ErrImportNotEmpty
+	}
+
+	for _, income := range data.Income {
+		if _, err = tx.Exec(DB.InsertIncomeSyntax,
+			uuid.New().String(),
+			income.PaymentDate,
+			income.Age,
+			income.Industry,
+			income.TotalAmount,
+			income.DeductionAmount,
+			income.TakeHomeAmount,
+			createdAt,
+			income.Classification,
+			UserId); err != nil {
+			return report, fmt.Errorf("クエリー実行エラー： %v", err)
+		}
+	}
+
+	for _, scenario := range data.PriceScenarios {
+		if _, err = tx.Exec(DB.InsertPriceScenarioSyntax,
+			uuid.New().String(),
+			UserId,
+			scenario.ScenarioName,
+			scenario.MoneyReceived,
+			scenario.Bouns,
+			scenario.FixedCost,
+			scenario.Loan,
+			scenario.Private,
+			scenario.Insurance,
+			scenario.ActiveFlag,
+			createdAt); err != nil {
+			return report, fmt.Errorf("クエリー実行エラー： %v", err)
+		}
+	}
+
+	for _, loan := range data.Loans {
+		if _, err = tx.Exec(DB.InsertLoanSyntax,
+			uuid.New().String(),
+			UserId,
+			loan.LoanName,
+			loan.Principal,
+			loan.AnnualRate,
+			loan.TermMonths,
+			loan.StartDate,
+			loan.RateType,
+			loan.RepaymentMethod,
+			createdAt); err != nil {
+			return report, fmt.Errorf("クエリー実行エラー： %v", err)
+		}
+	}
+
+	for _, goal := range data.SavingsGoals {
+		if _, err = tx.Exec(DB.InsertSavingsGoalSyntax,
+			uuid.New().String(),
+			UserId,
+			goal.GoalName,
+			goal.TargetAmount,
+			goal.TargetDate,
+			goal.CurrentBalance,
+			createdAt); err != nil {
+			return report, fmt.Errorf("クエリー実行エラー： %v", err)
+		}
+	}
+
+	// ドライランの場合はロールバックする
+	if DryRun {
+		return report, nil
+	}
+
+	// コミット処理
+	if err := tx.Commit(); err != nil {
+		return report, fmt.Errorf("トランザクションのコミットに失敗しました: %v", err)
+	}
+
+	rollback = false
+
+	return report, nil
+}
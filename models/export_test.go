@@ -0,0 +1,516 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"server/DB"
+	"server/enum"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var dataExportColumns = []string{"export_id", "user_id", "status", "file_path", "created_at", "expires_at"}
+
+var exportIncomeColumns = []string{"income_forecast_id", "payment_date", "age", "industry", "total_amount", "deduction_amount", "take_home_amount", "classification", "user_id"}
+
+func TestNewExportDataFetcher(t *testing.T) {
+	// 通常のテストケース
+	dbFetcher, mock, err := NewExportDataFetcher("test")
+	assert.NotNil(t, dbFetcher)
+	assert.NotNil(t, mock)
+	assert.NoError(t, err)
+
+	// カバレッジを通すためのテストケース
+	dbFetcher, _, err = NewExportDataFetcher("")
+	assert.NotNil(t, dbFetcher)
+	assert.NoError(t, err)
+}
+
+func TestInsertDataExport(t *testing.T) {
+	now := time.Date(2025, 1, 6, 20, 0, 0, 0, time.Local)
+	staleBefore := now.Add(-30 * time.Minute)
+	data := DataExportData{ExportId: "export-id", UserId: 1, CreatedAt: now}
+
+	t.Run("success InsertDataExport", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.FailStaleDataExportSyntax)).
+			WithArgs(enum.EXPORT_STATUS_FAILED, now, 1, enum.EXPORT_STATUS_PENDING, staleBefore).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertDataExportSyntax)).
+			WithArgs("export-id", 1, enum.EXPORT_STATUS_PENDING, now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err = dbFetcher.InsertDataExport(data, staleBefore)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error InsertDataExport 作成中のエクスポートがある", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.FailStaleDataExportSyntax)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertDataExportSyntax)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err = dbFetcher.InsertDataExport(data, staleBefore)
+
+		assert.ErrorIs(t, err, ErrExportPending)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error InsertDataExport クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.FailStaleDataExportSyntax)).
+			WillReturnError(fmt.Errorf("database error"))
+		mock.ExpectRollback()
+
+		err = dbFetcher.InsertDataExport(data, staleBefore)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCompleteDataExport(t *testing.T) {
+	expiresAt := time.Date(2025, 1, 7, 20, 0, 0, 0, time.Local)
+
+	t.Run("success CompleteDataExport", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.CompleteDataExportSyntax)).
+			WithArgs(enum.EXPORT_STATUS_READY, "/tmp/export-id.zip", "hash", sqlmock.AnyArg(), expiresAt, "export-id").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = dbFetcher.CompleteDataExport("export-id", "/tmp/export-id.zip", "hash", expiresAt)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("error CompleteDataExport 対象なし", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.CompleteDataExportSyntax)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = dbFetcher.CompleteDataExport("export-id", "/tmp/export-id.zip", "hash", expiresAt)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestFailDataExport(t *testing.T) {
+	dbFetcher, mock, err := NewExportDataFetcher("test")
+	if err != nil {
+		t.Fatalf("Error creating DB mock: %v", err)
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta(DB.FailDataExportSyntax)).
+		WithArgs(enum.EXPORT_STATUS_FAILED, sqlmock.AnyArg(), "export-id").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = dbFetcher.FailDataExport("export-id")
+
+	assert.NoError(t, err)
+}
+
+func TestGetDataExport(t *testing.T) {
+	now := time.Date(2025, 1, 6, 20, 0, 0, 0, time.Local)
+	expiresAt := now.Add(24 * time.Hour)
+
+	t.Run("success GetDataExport", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetDataExportSyntax)).
+			WithArgs("hash", enum.EXPORT_STATUS_READY, now).
+			WillReturnRows(sqlmock.NewRows(dataExportColumns).
+				AddRow("export-id", 1, enum.EXPORT_STATUS_READY, "/tmp/export-id.zip", now, expiresAt))
+
+		result, err := dbFetcher.GetDataExport("hash", now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, DataExportData{
+			ExportId:  "export-id",
+			UserId:    1,
+			Status:    enum.EXPORT_STATUS_READY,
+			FilePath:  "/tmp/export-id.zip",
+			CreatedAt: now,
+			ExpiresAt: expiresAt,
+		}, result)
+	})
+
+	t.Run("error GetDataExport 有効期限切れ又は存在しない", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetDataExportSyntax)).
+			WillReturnRows(sqlmock.NewRows(dataExportColumns))
+
+		_, err = dbFetcher.GetDataExport("hash", now)
+
+		assert.ErrorIs(t, err, ErrExportNotFound)
+	})
+}
+
+func TestDeleteExpiredDataExports(t *testing.T) {
+	now := time.Date(2025, 1, 6, 20, 0, 0, 0, time.Local)
+
+	t.Run("success DeleteExpiredDataExports", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.DeleteExpiredDataExportsSyntax)).
+			WithArgs(now).
+			WillReturnRows(sqlmock.NewRows([]string{"file_path"}).AddRow("/tmp/export-id.zip").AddRow(""))
+
+		result, err := dbFetcher.DeleteExpiredDataExports(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/tmp/export-id.zip", ""}, result)
+	})
+
+	t.Run("error DeleteExpiredDataExports クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.DeleteExpiredDataExportsSyntax)).
+			WillReturnError(fmt.Errorf("database error"))
+
+		_, err = dbFetcher.DeleteExpiredDataExports(now)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestGetExportProfile(t *testing.T) {
+	createdAt := time.Date(2024, 4, 1, 9, 0, 0, 0, time.Local)
+
+	t.Run("success GetExportProfile", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetExportProfileSyntax)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "user_email", "user_name", "role", "create_at"}).
+				AddRow(1, "test@example.com", "test", "user", createdAt))
+
+		result, err := dbFetcher.GetExportProfile(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ExportProfileData{
+			UserId:    1,
+			UserEmail: "test@example.com",
+			UserName:  "test",
+			Role:      "user",
+			CreatedAt: createdAt,
+		}, result)
+	})
+
+	t.Run("error GetExportProfile ユーザーが存在しない", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetExportProfileSyntax)).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "user_email", "user_name", "role", "create_at"}))
+
+		_, err = dbFetcher.GetExportProfile(1)
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
+
+func TestGetExportIncome(t *testing.T) {
+	incomeId := uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")
+	paymentDate := time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success GetExportIncome", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetExportIncomeSyntax)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(exportIncomeColumns).
+				AddRow(incomeId, paymentDate, "30", "IT", 300000, 50000, 250000, "給料", 1))
+
+		result, err := dbFetcher.GetExportIncome(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []IncomeData{{
+			IncomeForecastID: incomeId,
+			PaymentDate:      paymentDate,
+			Age:              "30",
+			Industry:         "IT",
+			TotalAmount:      300000,
+			DeductionAmount:  50000,
+			TakeHomeAmount:   250000,
+			Classification:   "給料",
+			UserID:           1,
+		}}, result)
+	})
+
+	t.Run("success GetExportIncome データなし", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetExportIncomeSyntax)).
+			WillReturnRows(sqlmock.NewRows(exportIncomeColumns))
+
+		result, err := dbFetcher.GetExportIncome(1)
+
+		// JSONで null ではなく [] を出力するため空のスライスを返す
+		assert.NoError(t, err)
+		assert.Equal(t, []IncomeData{}, result)
+	})
+
+	t.Run("error GetExportIncome クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetExportIncomeSyntax)).
+			WillReturnError(fmt.Errorf("database error"))
+
+		_, err = dbFetcher.GetExportIncome(1)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestGetExportAuditLogs(t *testing.T) {
+	auditId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")
+	createdAt := time.Date(2025, 1, 6, 20, 0, 0, 0, time.Local)
+
+	dbFetcher, mock, err := NewExportDataFetcher("test")
+	if err != nil {
+		t.Fatalf("Error creating DB mock: %v", err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(DB.GetExportAuditLogsSyntax)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"audit_id", "actor_role", "action", "detail", "created_at"}).
+			AddRow(auditId, "admin", "account_unlock", "{}", createdAt))
+
+	result, err := dbFetcher.GetExportAuditLogs(1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ExportAuditLogData{{
+		AuditId:   auditId,
+		ActorRole: "admin",
+		Action:    "account_unlock",
+		Detail:    "{}",
+		CreatedAt: createdAt,
+	}}, result)
+}
+
+func TestImportUserData(t *testing.T) {
+	data := ImportData{
+		Income: []InsertIncomeData{{
+			PaymentDate:     "2024-02-10",
+			Age:             30,
+			Industry:        "IT",
+			TotalAmount:     300000,
+			DeductionAmount: 50000,
+			TakeHomeAmount:  250000,
+			Classification:  "給料",
+			UserID:          2,
+		}},
+		PriceScenarios: []InsertPriceScenarioData{{
+			ScenarioName:  "基本",
+			MoneyReceived: 250000,
+			Bouns:         0,
+			FixedCost:     80000,
+			Loan:          0,
+			Private:       50000,
+			Insurance:     10000,
+			ActiveFlag:    true,
+			UserId:        2,
+		}},
+		Loans: []InsertLoanData{{
+			LoanName:        "住宅ローン",
+			Principal:       30000000,
+			AnnualRate:      0.5,
+			TermMonths:      420,
+			StartDate:       "2024-04-01",
+			RateType:        "variable",
+			RepaymentMethod: "equal_payment",
+			UserId:          2,
+		}},
+		SavingsGoals: []InsertSavingsGoalData{{
+			GoalName:       "車の購入",
+			TargetAmount:   2000000,
+			TargetDate:     "2026-03-31",
+			CurrentBalance: 500000,
+			UserId:         2,
+		}},
+	}
+
+	expectImport := func(mock sqlmock.Sqlmock, existingIncome, existingScenarios, existingLoans, existingGoals int) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(DB.LockImportUserSyntax)).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"income", "price_scenario", "loan", "savings_goal"}).
+				AddRow(existingIncome, existingScenarios, existingLoans, existingGoals))
+	}
+
+	expectInserts := func(mock sqlmock.Sqlmock) {
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertIncomeSyntax)).
+			WithArgs(sqlmock.AnyArg(), "2024-02-10", 30, "IT", 300000, 50000, 250000, sqlmock.AnyArg(), "給料", 2).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertPriceScenarioSyntax)).
+			WithArgs(sqlmock.AnyArg(), 2, "基本", 250000, 0, 80000, 0, 50000, 10000, true, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertLoanSyntax)).
+			WithArgs(sqlmock.AnyArg(), 2, "住宅ローン", 30000000, 0.5, 420, "2024-04-01", "variable", "equal_payment", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertSavingsGoalSyntax)).
+			WithArgs(sqlmock.AnyArg(), 2, "車の購入", 2000000, "2026-03-31", 500000, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+
+	t.Run("success ImportUserData", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		expectImport(mock, 0, 0, 0, 0)
+		expectInserts(mock)
+		mock.ExpectCommit()
+
+		report, err := dbFetcher.ImportUserData(2, data, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ImportReportData{DryRun: false, Importable: true, Income: 1, PriceScenarios: 1, Loans: 1, SavingsGoals: 1}, report)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success ImportUserData ドライランはロールバックする", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		expectImport(mock, 0, 0, 0, 0)
+		expectInserts(mock)
+		mock.ExpectRollback()
+
+		report, err := dbFetcher.ImportUserData(2, data, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ImportReportData{DryRun: true, Importable: true, Income: 1, PriceScenarios: 1, Loans: 1, SavingsGoals: 1}, report)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success ImportUserData ドライランは登録データがあっても結果を返す", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		expectImport(mock, 3, 0, 0, 0)
+		expectInserts(mock)
+		mock.ExpectRollback()
+
+		report, err := dbFetcher.ImportUserData(2, data, true)
+
+		assert.NoError(t, err)
+		assert.False(t, report.Importable)
+		assert.Equal(t, 3, report.ExistingIncome)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error ImportUserData 登録データがある", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		expectImport(mock, 0, 1, 0, 0)
+		mock.ExpectRollback()
+
+		_, err = dbFetcher.ImportUserData(2, data, false)
+
+		assert.ErrorIs(t, err, ErrImportNotEmpty)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error ImportUserData ローン・貯蓄目標が登録済み", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		expectImport(mock, 0, 0, 1, 2)
+		mock.ExpectRollback()
+
+		report, err := dbFetcher.ImportUserData(2, data, false)
+
+		assert.ErrorIs(t, err, ErrImportNotEmpty)
+		assert.Equal(t, 1, report.ExistingLoans)
+		assert.Equal(t, 2, report.ExistingSavingsGoals)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error ImportUserData 登録エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewExportDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		expectImport(mock, 0, 0, 0, 0)
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertIncomeSyntax)).
+			WillReturnError(fmt.Errorf("database error"))
+		mock.ExpectRollback()
+
+		_, err = dbFetcher.ImportUserData(2, data, false)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
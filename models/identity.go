@@ -0,0 +1,323 @@
+// models/identity.go
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"server/DB"
+	"server/enum"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/lib/pq"
+)
+
+type (
+	IdentityFetcher interface {
+		GetIdentities(UserId int) ([]IdentityData, error)
+		GetIdentityUser(Provider, ProviderSubject string) (ExternalAuthData, error)
+		GetLegacyExternalUser(Provider, UserEmail string) (ExternalAuthData, error)
+		LinkIdentity(UserId int, data IdentityData) error
+		PostIdentitySignUp(data RequestSignUpData, identity IdentityData) (int, error)
+		UnlinkIdentity(UserId int, Provider string) error
+	}
+
+	IdentityData struct {
+		// 外部認証の名称(google・line など設定で登録した名称)
+		Provider string `json:"provider"`
+		// 外部認証のユーザーID
+		ProviderSubject string    `json:"provider_id"`
+		ProviderEmail   string    `json:"provider_email"`
+		LinkedAt        time.Time `json:"linked_at"`
+	}
+
+	IdentityDataFetcher struct{ db *sql.DB }
+)
+
+func NewIdentityDataFetcher(dataSourceName string) (*IdentityDataFetcher, sqlmock.Sqlmock, error) {
+	if dataSourceName == "test" {
+		db, mock, err := sqlmock.New()
+		return &IdentityDataFetcher{db: db}, mock, err
+	} else {
+		// test実行時に以下のカバレッジは無視する
+		db, err := sql.Open("postgres", dataSourceName)
+		if err != nil {
+			log.Printf("sql.Open error %s", err)
+		}
+		return &IdentityDataFetcher{db: db}, nil, nil
+	}
+}
+
+// GetIdentities はユーザーに連携済みの外部認証を連携日時の昇順で返す
+//
+// 引数:
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: 連携済みの外部認証
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (idf *IdentityDataFetcher) GetIdentities(UserId int) ([]IdentityData, error) {
+	identities := []IdentityData{}
+
+	defer idf.db.Close()
+
+	// データベースクエリを実行
+	rows, err := idf.db.Query(DB.GetIdentitiesSyntax, UserId)
+	if err != nil {
+		return nil, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record IdentityData
+		if err := rows.Scan(
+			&record.Provider,
+			&record.ProviderSubject,
+			&record.ProviderEmail,
+			&record.LinkedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		identities = append(identities, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+// GetIdentityUser は外部アカウントを連携しているユーザーを返す
+//
+// 引数:
+//   - Provider: 外部認証の名称
+//   - ProviderSubject: 外部認証のユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: 連携しているユーザー
+//	戻り値2: エラー内容(連携されていない場合はエラー)
+//
+
+func (idf *IdentityDataFetcher) GetIdentityUser(Provider, ProviderSubject string) (ExternalAuthData, error) {
+	var data ExternalAuthData
+
+	defer idf.db.Close()
+
+	err := idf.db.QueryRow(DB.GetIdentityUserSyntax, Provider, ProviderSubject).Scan(
+		&data.UserId,
+		&data.UserEmail,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return data, errors.New("連携された外部アカウントが存在しません。")
+	} else if err != nil {
+		return data, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+
+	return data, nil
+}
+
+// GetLegacyExternalUser は連携テーブルの追加前に外部認証で登録し、まだ連携されていないユーザーを返す
+//
+// 引数:
+//   - Provider: 外部認証の名称
+//   - UserEmail: 外部認証のメールアドレス
+//
+// 戻り値:
+//
+//	戻り値1: 対象のユーザー
+//	戻り値2: エラー内容(対象のユーザーがいない場合はエラー)
+//
+
+func (idf *IdentityDataFetcher) GetLegacyExternalUser(Provider, UserEmail string) (ExternalAuthData, error) {
+	var data ExternalAuthData
+
+	defer idf.db.Close()
+
+	err := idf.db.QueryRow(DB.GetLegacyExternalUserSyntax, UserEmail, Provider).Scan(
+		&data.UserId,
+		&data.UserEmail,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return data, errors.New("連携された外部アカウントが存在しません。")
+	} else if err != nil {
+		return data, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+
+	return data, nil
+}
+
+// LinkIdentity はユーザーに外部アカウントを連携する
+//
+// 引数:
+//   - UserId: ユーザーID
+//   - data: 連携する外部アカウント(LinkedAtは現在日時で登録)
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(外部アカウントが連携済みの場合はエラー)
+//
+
+func (idf *IdentityDataFetcher) LinkIdentity(UserId int, data IdentityData) error {
+
+	defer idf.db.Close()
+
+	result, err := idf.db.Exec(
+		DB.InsertIdentitySyntax,
+		data.Provider,
+		data.ProviderSubject,
+		UserId,
+		data.ProviderEmail,
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return errors.New("外部アカウントが他のユーザーに連携済みか、同じ外部認証が連携済みです。")
+	}
+
+	return nil
+}
+
+// PostIdentitySignUp は外部認証でユーザーを登録し、外部アカウントを連携する
+//
+// 引数:
+//   - data: 登録するユーザー(パスワードには外部認証の名称を登録)
+//   - identity: 連携する外部アカウント
+//
+// 戻り値:
+//
+//	戻り値1: 登録したユーザーID
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (idf *IdentityDataFetcher) PostIdentitySignUp(data RequestSignUpData, identity IdentityData) (int, error) {
+
+	var err error
+	var userId int
+	createdAt := time.Now()
+
+	// データベースのクローズをdeferで最初に宣言
+	defer idf.db.Close()
+
+	// トランザクションを開始
+	tx, err := idf.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("トランザクションの開始に失敗しました: %v", err)
+	}
+
+	// ロールバックをデフォルトに設定
+	rollback := true
+	defer func() {
+		if rollback {
+			tx.Rollback()
+		}
+	}()
+
+	err = tx.QueryRow(DB.PostSignUpSyntax,
+		data.UserEmail,
+		data.UserPassword,
+		data.UserName,
+		createdAt,
+		data.UserName,
+		createdAt,
+		enum.DELETE_FLAG_OFF).Scan(&userId)
+	if err != nil {
+		return 0, fmt.Errorf("ユーザー情報の登録に失敗しました: %v", err)
+	}
+
+	result, err := tx.Exec(
+		DB.InsertIdentitySyntax,
+		identity.Provider,
+		identity.ProviderSubject,
+		userId,
+		identity.ProviderEmail,
+		createdAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return 0, errors.New("外部アカウントが他のユーザーに連携済みです。")
+	}
+
+	// コミット処理
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("トランザクションのコミットに失敗しました: %v", err)
+	}
+
+	rollback = false
+
+	return userId, nil
+}
+
+// UnlinkIdentity は外部アカウントの連携を解除する
+// パスワードも他の外部認証もないユーザーの場合、最後のサインイン方法のため解除しない
+//
+// 引数:
+//   - UserId: ユーザーID
+//   - Provider: 外部認証の名称
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (idf *IdentityDataFetcher) UnlinkIdentity(UserId int, Provider string) error {
+
+	var err error
+	var passwordAccount bool
+	var identityCount int
+
+	// データベースのクローズをdeferで最初に宣言
+	defer idf.db.Close()
+
+	// トランザクションを開始
+	tx, err := idf.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %v", err)
+	}
+
+	// ロールバックをデフォルトに設定
+	rollback := true
+	defer func() {
+		if rollback {
+			tx.Rollback()
+		}
+	}()
+
+	err = tx.QueryRow(DB.LockSignInMethodsSyntax, UserId).Scan(&passwordAccount, &identityCount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("対象のユーザーが存在しません。")
+	} else if err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+
+	result, err := tx.Exec(DB.DeleteIdentitySyntax, UserId, Provider)
+	if err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return errors.New("連携されていない外部認証です。")
+	}
+	if !passwordAccount && identityCount <= 1 {
+		return errors.New("最後のサインイン方法は解除できません。")
+	}
+
+	// コミット処理
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %v", err)
+	}
+
+	rollback = false
+
+	return nil
+}
@@ -0,0 +1,432 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"server/DB"
+	"server/enum"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var identityColumns = []string{"provider", "provider_subject", "provider_email", "linked_at"}
+
+func testIdentity() IdentityData {
+	return IdentityData{
+		Provider:        "google",
+		ProviderSubject: "109876543210",
+		ProviderEmail:   "test@gmail.com",
+	}
+}
+
+func TestNewIdentityDataFetcher(t *testing.T) {
+	// 通常のテストケース
+	dbFetcher, mock, err := NewIdentityDataFetcher("test")
+	assert.NotNil(t, dbFetcher)
+	assert.NotNil(t, mock)
+	assert.NoError(t, err)
+
+	// カバレッジを通すためのテストケース
+	dbFetcher, _, err = NewIdentityDataFetcher("")
+	assert.NotNil(t, dbFetcher)
+	assert.NoError(t, err)
+}
+
+func TestGetIdentities(t *testing.T) {
+	t.Run("success GetIdentities", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		linkedAt := time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows(identityColumns).
+			AddRow("google", "109876543210", "test@gmail.com", linkedAt).
+			AddRow("line", "U1234567890", "test@line.me", linkedAt)
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetIdentitiesSyntax)).
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		result, err := dbFetcher.GetIdentities(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []IdentityData{
+			{Provider: "google", ProviderSubject: "109876543210", ProviderEmail: "test@gmail.com", LinkedAt: linkedAt},
+			{Provider: "line", ProviderSubject: "U1234567890", ProviderEmail: "test@line.me", LinkedAt: linkedAt},
+		}, result)
+	})
+
+	t.Run("success GetIdentities 連携なし", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetIdentitiesSyntax)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(identityColumns))
+
+		result, err := dbFetcher.GetIdentities(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []IdentityData{}, result)
+	})
+
+	t.Run("error GetIdentities クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetIdentitiesSyntax)).
+			WillReturnError(fmt.Errorf("クエリの実行に失敗しました"))
+
+		result, err := dbFetcher.GetIdentities(1)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "クエリー実行エラー： クエリの実行に失敗しました")
+	})
+}
+
+func TestGetIdentityUser(t *testing.T) {
+	t.Run("success GetIdentityUser", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		rows := sqlmock.NewRows([]string{"user_id", "user_email"}).
+			AddRow(1, "test@example.com")
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetIdentityUserSyntax)).
+			WithArgs("google", "109876543210").
+			WillReturnRows(rows)
+
+		result, err := dbFetcher.GetIdentityUser("google", "109876543210")
+
+		assert.NoError(t, err)
+		assert.Equal(t, ExternalAuthData{UserId: 1, UserEmail: "test@example.com"}, result)
+	})
+
+	t.Run("error GetIdentityUser 連携なし", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetIdentityUserSyntax)).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err = dbFetcher.GetIdentityUser("google", "109876543210")
+
+		assert.EqualError(t, err, "連携された外部アカウントが存在しません。")
+	})
+
+	t.Run("error GetIdentityUser クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetIdentityUserSyntax)).
+			WillReturnError(fmt.Errorf("クエリの実行に失敗しました"))
+
+		_, err = dbFetcher.GetIdentityUser("google", "109876543210")
+
+		assert.EqualError(t, err, "クエリー実行エラー： クエリの実行に失敗しました")
+	})
+}
+
+func TestGetLegacyExternalUser(t *testing.T) {
+	t.Run("success GetLegacyExternalUser", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		rows := sqlmock.NewRows([]string{"user_id", "user_email"}).
+			AddRow(1, "test@gmail.com")
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetLegacyExternalUserSyntax)).
+			WithArgs("test@gmail.com", "google").
+			WillReturnRows(rows)
+
+		result, err := dbFetcher.GetLegacyExternalUser("google", "test@gmail.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, ExternalAuthData{UserId: 1, UserEmail: "test@gmail.com"}, result)
+	})
+
+	t.Run("error GetLegacyExternalUser 対象なし", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetLegacyExternalUserSyntax)).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err = dbFetcher.GetLegacyExternalUser("google", "test@gmail.com")
+
+		assert.EqualError(t, err, "連携された外部アカウントが存在しません。")
+	})
+
+	t.Run("error GetLegacyExternalUser クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetLegacyExternalUserSyntax)).
+			WillReturnError(fmt.Errorf("クエリの実行に失敗しました"))
+
+		_, err = dbFetcher.GetLegacyExternalUser("google", "test@gmail.com")
+
+		assert.EqualError(t, err, "クエリー実行エラー： クエリの実行に失敗しました")
+	})
+}
+
+func TestLinkIdentity(t *testing.T) {
+	t.Run("success LinkIdentity", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertIdentitySyntax)).
+			WithArgs("google", "109876543210", 1, "test@gmail.com", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = dbFetcher.LinkIdentity(1, testIdentity())
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error LinkIdentity 連携済み", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertIdentitySyntax)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = dbFetcher.LinkIdentity(1, testIdentity())
+
+		assert.EqualError(t, err, "外部アカウントが他のユーザーに連携済みか、同じ外部認証が連携済みです。")
+	})
+
+	t.Run("error LinkIdentity クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertIdentitySyntax)).
+			WillReturnError(fmt.Errorf("クエリの実行に失敗しました"))
+
+		err = dbFetcher.LinkIdentity(1, testIdentity())
+
+		assert.EqualError(t, err, "クエリー実行エラー： クエリの実行に失敗しました")
+	})
+}
+
+func TestPostIdentitySignUp(t *testing.T) {
+	signUpData := RequestSignUpData{
+		UserEmail:    "test@gmail.com",
+		UserPassword: "google",
+		UserName:     "test",
+	}
+
+	t.Run("success PostIdentitySignUp", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(DB.PostSignUpSyntax)).
+			WithArgs("test@gmail.com", "google", "test", sqlmock.AnyArg(), "test", sqlmock.AnyArg(), enum.DELETE_FLAG_OFF).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertIdentitySyntax)).
+			WithArgs("google", "109876543210", 1, "test@gmail.com", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		userId, err := dbFetcher.PostIdentitySignUp(signUpData, testIdentity())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, userId)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error PostIdentitySignUp ユーザー登録エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(DB.PostSignUpSyntax)).
+			WillReturnError(fmt.Errorf("duplicate key value"))
+		mock.ExpectRollback()
+
+		_, err = dbFetcher.PostIdentitySignUp(signUpData, testIdentity())
+
+		assert.EqualError(t, err, "ユーザー情報の登録に失敗しました: duplicate key value")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error PostIdentitySignUp 外部アカウントが連携済み", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(DB.PostSignUpSyntax)).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1))
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertIdentitySyntax)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		_, err = dbFetcher.PostIdentitySignUp(signUpData, testIdentity())
+
+		assert.EqualError(t, err, "外部アカウントが他のユーザーに連携済みです。")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error PostIdentitySignUp トランザクション開始エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin().WillReturnError(fmt.Errorf("begin error"))
+
+		_, err = dbFetcher.PostIdentitySignUp(signUpData, testIdentity())
+
+		assert.EqualError(t, err, "トランザクションの開始に失敗しました: begin error")
+	})
+}
+
+func TestUnlinkIdentity(t *testing.T) {
+	lockColumns := []string{"password_account", "identity_count"}
+
+	t.Run("success UnlinkIdentity パスワード登録あり", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(DB.LockSignInMethodsSyntax)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(lockColumns).AddRow(true, 1))
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteIdentitySyntax)).
+			WithArgs(1, "google").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err = dbFetcher.UnlinkIdentity(1, "google")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success UnlinkIdentity 他の外部認証が連携済み", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(DB.LockSignInMethodsSyntax)).
+			WillReturnRows(sqlmock.NewRows(lockColumns).AddRow(false, 2))
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteIdentitySyntax)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err = dbFetcher.UnlinkIdentity(1, "google")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error UnlinkIdentity 最後のサインイン方法", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(DB.LockSignInMethodsSyntax)).
+			WillReturnRows(sqlmock.NewRows(lockColumns).AddRow(false, 1))
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteIdentitySyntax)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectRollback()
+
+		err = dbFetcher.UnlinkIdentity(1, "google")
+
+		assert.EqualError(t, err, "最後のサインイン方法は解除できません。")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error UnlinkIdentity 連携なし", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(DB.LockSignInMethodsSyntax)).
+			WillReturnRows(sqlmock.NewRows(lockColumns).AddRow(true, 0))
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteIdentitySyntax)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err = dbFetcher.UnlinkIdentity(1, "line")
+
+		assert.EqualError(t, err, "連携されていない外部認証です。")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error UnlinkIdentity ユーザーが存在しない", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(DB.LockSignInMethodsSyntax)).
+			WillReturnRows(sqlmock.NewRows(lockColumns))
+		mock.ExpectRollback()
+
+		err = dbFetcher.UnlinkIdentity(1, "google")
+
+		assert.EqualError(t, err, "対象のユーザーが存在しません。")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error UnlinkIdentity クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewIdentityDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(DB.LockSignInMethodsSyntax)).
+			WillReturnRows(sqlmock.NewRows(lockColumns).AddRow(true, 1))
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteIdentitySyntax)).
+			WillReturnError(fmt.Errorf("クエリの実行に失敗しました"))
+		mock.ExpectRollback()
+
+		err = dbFetcher.UnlinkIdentity(1, "google")
+
+		assert.EqualError(t, err, "クエリー実行エラー： クエリの実行に失敗しました")
+	})
+}
@@ -0,0 +1,332 @@
+// models/loan.go
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"server/DB"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+type (
+	LoanFetcher interface {
+		GetLoans(UserId int) ([]LoanData, error)
+		GetLoan(LoanId string, UserId int) (LoanData, error)
+		InsertLoan(data []InsertLoanData) error
+		UpdateLoan(data []UpdateLoanData) error
+		DeleteLoan(data []DeleteLoanData) error
+	}
+
+	LoanData struct {
+		LoanId          uuid.UUID `json:"loan_id"`
+		UserId          int       `json:"user_id"`
+		LoanName        string    `json:"loan_name"`
+		Principal       int       `json:"principal"`
+		AnnualRate      float64   `json:"annual_rate"`
+		TermMonths      int       `json:"term_months"`
+		StartDate       time.Time `json:"start_date"`
+		RateType        string    `json:"rate_type"`
+		RepaymentMethod string    `json:"repayment_method"`
+	}
+
+	InsertLoanData struct {
+		LoanName        string      `json:"loan_name"`
+		Principal       interface{} `json:"principal"`
+		AnnualRate      float64     `json:"annual_rate"`
+		TermMonths      interface{} `json:"term_months"`
+		StartDate       string      `json:"start_date"`
+		RateType        string      `json:"rate_type"`
+		RepaymentMethod string      `json:"repayment_method"`
+		UserId          interface{} `json:"user_id"`
+	}
+
+	UpdateLoanData struct {
+		LoanId          string      `json:"loan_id"`
+		LoanName        string      `json:"loan_name"`
+		Principal       interface{} `json:"principal"`
+		AnnualRate      float64     `json:"annual_rate"`
+		TermMonths      interface{} `json:"term_months"`
+		StartDate       string      `json:"start_date"`
+		RateType        string      `json:"rate_type"`
+		RepaymentMethod string      `json:"repayment_method"`
+		UserId          interface{} `json:"user_id"`
+	}
+
+	DeleteLoanData struct {
+		LoanId string      `json:"loan_id"`
+		UserId interface{} `json:"user_id"`
+	}
+
+	LoanDataFetcher struct{ db *sql.DB }
+)
+
+func NewLoanDataFetcher(dataSourceName string) (*LoanDataFetcher, sqlmock.Sqlmock, error) {
+	if dataSourceName == "test" {
+		db, mock, err := sqlmock.New()
+		return &LoanDataFetcher{db: db}, mock, err
+	} else {
+		// test実行時に以下のカバレッジは無視する
+		db, err := sql.Open("postgres", dataSourceName)
+		if err != nil {
+			log.Printf("sql.Open error %s", err)
+		}
+		return &LoanDataFetcher{db: db}, nil, nil
+	}
+}
+
+// GetLoans は対象ユーザーの登録済みローンを全て返す。
+//
+// 引数:
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: 取得したDBの構造体
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (lf *LoanDataFetcher) GetLoans(UserId int) ([]LoanData, error) {
+	var loanData []LoanData
+
+	// データベースクエリを実行
+	rows, err := lf.db.Query(DB.GetLoansSyntax, UserId)
+	if err != nil {
+		return nil, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data LoanData
+		err := rows.Scan(
+			&data.LoanId,
+			&data.UserId,
+			&data.LoanName,
+			&data.Principal,
+			&data.AnnualRate,
+			&data.TermMonths,
+			&data.StartDate,
+			&data.RateType,
+			&data.RepaymentMethod,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		loanData = append(loanData, data)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return loanData, nil
+}
+
+// GetLoan は指定されたローンを1件返す。
+//
+// 引数:
+//   - LoanId: ローンID
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: 取得したDBの構造体
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (lf *LoanDataFetcher) GetLoan(LoanId string, UserId int) (LoanData, error) {
+	var data LoanData
+
+	// データベースクエリを実行
+	row := lf.db.QueryRow(DB.GetLoanSyntax, LoanId, UserId)
+	err := row.Scan(
+		&data.LoanId,
+		&data.UserId,
+		&data.LoanName,
+		&data.Principal,
+		&data.AnnualRate,
+		&data.TermMonths,
+		&data.StartDate,
+		&data.RateType,
+		&data.RepaymentMethod,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return data, errors.New("対象のローンが存在しません。")
+		}
+		return data, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+
+	return data, nil
+}
+
+// InsertLoan はローンの新規登録
+//
+// 引数:
+//   - data: 登録するローン情報
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (lf *LoanDataFetcher) InsertLoan(data []InsertLoanData) error {
+
+	var err error
+	createdAt := time.Now()
+
+	// データベースのクローズをdeferで最初に宣言
+	defer lf.db.Close()
+
+	// トランザクションを開始
+	tx, err := lf.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %v", err)
+	}
+
+	// ロールバックをデフォルトに設定
+	rollback := true
+	defer func() {
+		if rollback {
+			tx.Rollback()
+		}
+	}()
+
+	for _, insertData := range data {
+		loanId := uuid.New().String()
+		if _, err = tx.Exec(DB.InsertLoanSyntax,
+			loanId,
+			insertData.UserId,
+			insertData.LoanName,
+			insertData.Principal,
+			insertData.AnnualRate,
+			insertData.TermMonths,
+			insertData.StartDate,
+			insertData.RateType,
+			insertData.RepaymentMethod,
+			createdAt); err != nil {
+			return err
+		}
+	}
+
+	// コミット処理
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %v", err)
+	}
+
+	rollback = false
+
+	return nil
+}
+
+// UpdateLoan はローンの更新
+// 変動金利の金利見直しもこちらで更新する
+//
+// 引数:
+//   - data: 更新するローン情報
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (lf *LoanDataFetcher) UpdateLoan(data []UpdateLoanData) error {
+
+	var err error
+	updateAt := time.Now()
+
+	// データベースのクローズをdeferで最初に宣言
+	defer lf.db.Close()
+
+	// トランザクションを開始
+	tx, err := lf.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %v", err)
+	}
+
+	// ロールバックをデフォルトに設定
+	rollback := true
+	defer func() {
+		if rollback {
+			tx.Rollback()
+		}
+	}()
+
+	for _, updateData := range data {
+		if _, err = tx.Exec(DB.UpdateLoanSyntax,
+			updateData.LoanName,
+			updateData.Principal,
+			updateData.AnnualRate,
+			updateData.TermMonths,
+			updateData.StartDate,
+			updateData.RateType,
+			updateData.RepaymentMethod,
+			updateAt,
+			updateData.LoanId,
+			updateData.UserId); err != nil {
+			return err
+		}
+	}
+
+	// コミット処理
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %v", err)
+	}
+
+	rollback = false
+
+	return nil
+}
+
+// DeleteLoan はローンの削除
+//
+// 引数:
+//   - data: 削除するローンID
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (lf *LoanDataFetcher) DeleteLoan(data []DeleteLoanData) error {
+
+	var err error
+
+	// データベースのクローズをdeferで最初に宣言
+	defer lf.db.Close()
+
+	// トランザクションを開始
+	tx, err := lf.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %v", err)
+	}
+
+	// ロールバックをデフォルトに設定
+	rollback := true
+	defer func() {
+		if rollback {
+			tx.Rollback()
+		}
+	}()
+
+	for _, deleteData := range data {
+		if _, err = tx.Exec(DB.DeleteLoanSyntax, deleteData.LoanId, deleteData.UserId); err != nil {
+			return err
+		}
+	}
+
+	// コミット処理
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %v", err)
+	}
+
+	rollback = false
+
+	return nil
+}
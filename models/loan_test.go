@@ -0,0 +1,362 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"server/DB"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var loanColumns = []string{
+	"loan_id", "user_id", "loan_name", "principal", "annual_rate",
+	"term_months", "start_date", "rate_type", "repayment_method",
+}
+
+func TestNewLoanDataFetcher(t *testing.T) {
+	// 通常のテストケース
+	dbFetcher, mock, err := NewLoanDataFetcher("test")
+	assert.NotNil(t, dbFetcher)
+	assert.NotNil(t, mock)
+	assert.NoError(t, err)
+
+	// カバレッジを通すためのテストケース
+	dbFetcher, _, err = NewLoanDataFetcher("")
+	assert.NotNil(t, dbFetcher)
+	assert.NoError(t, err)
+}
+
+func TestGetLoans(t *testing.T) {
+	t.Run("success GetLoans", func(t *testing.T) {
+		dbFetcher, mock, err := NewLoanDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		expected := []LoanData{
+			{
+				LoanId:          uuid.MustParse("8df939de-5a97-4f20-b41b-9ac355c16e36"),
+				UserId:          1,
+				LoanName:        "住宅ローン",
+				Principal:       30000000,
+				AnnualRate:      0.5,
+				TermMonths:      420,
+				StartDate:       time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC),
+				RateType:        "variable",
+				RepaymentMethod: "equal_payment",
+			},
+		}
+
+		rows := sqlmock.NewRows(loanColumns)
+		for _, data := range expected {
+			rows.AddRow(
+				data.LoanId.String(),
+				data.UserId,
+				data.LoanName,
+				data.Principal,
+				data.AnnualRate,
+				data.TermMonths,
+				data.StartDate,
+				data.RateType,
+				data.RepaymentMethod,
+			)
+		}
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetLoansSyntax)).
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		result, err := dbFetcher.GetLoans(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("error GetLoans クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewLoanDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetLoansSyntax)).
+			WithArgs(1).
+			WillReturnError(fmt.Errorf("クエリの実行に失敗しました"))
+
+		result, err := dbFetcher.GetLoans(1)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "クエリー実行エラー： クエリの実行に失敗しました")
+	})
+
+	t.Run("error GetLoans rows.Scan時にエラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewLoanDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		rows := sqlmock.NewRows(loanColumns).
+			AddRow("invalid-uuid", 1, "住宅ローン", 30000000, 0.5, 420, time.Now(), "fixed", "equal_payment")
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetLoansSyntax)).
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		result, err := dbFetcher.GetLoans(1)
+
+		assert.Nil(t, result)
+		assert.Error(t, err)
+	})
+}
+
+func TestGetLoan(t *testing.T) {
+	loanId := "8df939de-5a97-4f20-b41b-9ac355c16e36"
+
+	t.Run("success GetLoan", func(t *testing.T) {
+		dbFetcher, mock, err := NewLoanDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		startDate := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows(loanColumns).
+			AddRow(loanId, 1, "車のローン", 2000000, 2.5, 60, startDate, "fixed", "equal_principal")
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetLoanSyntax)).
+			WithArgs(loanId, 1).
+			WillReturnRows(rows)
+
+		result, err := dbFetcher.GetLoan(loanId, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "車のローン", result.LoanName)
+		assert.Equal(t, 2000000, result.Principal)
+		assert.Equal(t, 2.5, result.AnnualRate)
+		assert.Equal(t, "equal_principal", result.RepaymentMethod)
+	})
+
+	t.Run("error GetLoan 存在しない", func(t *testing.T) {
+		dbFetcher, mock, err := NewLoanDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetLoanSyntax)).
+			WithArgs(loanId, 1).
+			WillReturnRows(sqlmock.NewRows(loanColumns))
+
+		_, err = dbFetcher.GetLoan(loanId, 1)
+
+		assert.EqualError(t, err, "対象のローンが存在しません。")
+	})
+
+	t.Run("error GetLoan クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewLoanDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetLoanSyntax)).
+			WithArgs(loanId, 1).
+			WillReturnError(fmt.Errorf("クエリの実行に失敗しました"))
+
+		_, err = dbFetcher.GetLoan(loanId, 1)
+
+		assert.EqualError(t, err, "クエリー実行エラー： クエリの実行に失敗しました")
+	})
+}
+
+func TestInsertLoan(t *testing.T) {
+	testData := []InsertLoanData{
+		{
+			LoanName:        "住宅ローン",
+			Principal:       30000000,
+			AnnualRate:      0.5,
+			TermMonths:      420,
+			StartDate:       "2024-04-01",
+			RateType:        "variable",
+			RepaymentMethod: "equal_payment",
+			UserId:          1,
+		},
+	}
+
+	t.Run("success InsertLoan", func(t *testing.T) {
+		dbFetcher, mock, err := NewLoanDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertLoanSyntax)).
+			WithArgs(
+				sqlmock.AnyArg(),
+				1,
+				"住宅ローン",
+				30000000,
+				0.5,
+				420,
+				"2024-04-01",
+				"variable",
+				"equal_payment",
+				sqlmock.AnyArg(),
+			).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err = dbFetcher.InsertLoan(testData)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error InsertLoan トランザクションエラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewLoanDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin().WillReturnError(errors.New("transaction error"))
+
+		err = dbFetcher.InsertLoan(testData)
+
+		assert.EqualError(t, err, "トランザクションの開始に失敗しました: transaction error")
+	})
+
+	t.Run("error InsertLoan クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewLoanDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertLoanSyntax)).
+			WillReturnError(errors.New("insert error"))
+		mock.ExpectRollback()
+
+		err = dbFetcher.InsertLoan(testData)
+
+		assert.EqualError(t, err, "insert error")
+	})
+
+	t.Run("error InsertLoan コミットエラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewLoanDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertLoanSyntax)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit().WillReturnError(errors.New("commit error"))
+
+		err = dbFetcher.InsertLoan(testData)
+
+		assert.EqualError(t, err, "トランザクションのコミットに失敗しました: commit error")
+	})
+}
+
+func TestUpdateLoan(t *testing.T) {
+	testData := []UpdateLoanData{
+		{
+			LoanId:          "8df939de-5a97-4f20-b41b-9ac355c16e36",
+			LoanName:        "住宅ローン",
+			Principal:       30000000,
+			AnnualRate:      0.75,
+			TermMonths:      420,
+			StartDate:       "2024-04-01",
+			RateType:        "variable",
+			RepaymentMethod: "equal_payment",
+			UserId:          1,
+		},
+	}
+
+	t.Run("success UpdateLoan", func(t *testing.T) {
+		dbFetcher, mock, err := NewLoanDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.UpdateLoanSyntax)).
+			WithArgs(
+				"住宅ローン",
+				30000000,
+				0.75,
+				420,
+				"2024-04-01",
+				"variable",
+				"equal_payment",
+				sqlmock.AnyArg(),
+				"8df939de-5a97-4f20-b41b-9ac355c16e36",
+				1,
+			).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err = dbFetcher.UpdateLoan(testData)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error UpdateLoan クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewLoanDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.UpdateLoanSyntax)).
+			WillReturnError(errors.New("update error"))
+		mock.ExpectRollback()
+
+		err = dbFetcher.UpdateLoan(testData)
+
+		assert.EqualError(t, err, "update error")
+	})
+}
+
+func TestDeleteLoan(t *testing.T) {
+	testData := []DeleteLoanData{
+		{
+			LoanId: "8df939de-5a97-4f20-b41b-9ac355c16e36",
+			UserId: 1,
+		},
+	}
+
+	t.Run("success DeleteLoan", func(t *testing.T) {
+		dbFetcher, mock, err := NewLoanDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteLoanSyntax)).
+			WithArgs("8df939de-5a97-4f20-b41b-9ac355c16e36", 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err = dbFetcher.DeleteLoan(testData)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error DeleteLoan クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewLoanDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteLoanSyntax)).
+			WillReturnError(errors.New("delete error"))
+		mock.ExpectRollback()
+
+		err = dbFetcher.DeleteLoan(testData)
+
+		assert.EqualError(t, err, "delete error")
+	})
+}
@@ -0,0 +1,322 @@
+// models/price_scenario.go
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"server/DB"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+type (
+	PriceScenarioFetcher interface {
+		GetPriceScenarios(UserId int) ([]PriceScenarioData, error)
+		GetActivePriceScenario(UserId int) (PriceScenarioData, error)
+		InsertPriceScenario(data []InsertPriceScenarioData) error
+		ActivatePriceScenario(ScenarioId string, UserId int) error
+		DeletePriceScenario(data []DeletePriceScenarioData) error
+	}
+
+	PriceScenarioData struct {
+		ScenarioId    uuid.UUID `json:"scenario_id"`
+		UserId        int       `json:"user_id"`
+		ScenarioName  string    `json:"scenario_name"`
+		MoneyReceived int       `json:"money_received"`
+		Bouns         int       `json:"bouns"`
+		FixedCost     int       `json:"fixed_cost"`
+		Loan          int       `json:"loan"`
+		Private       int       `json:"private"`
+		Insurance     int       `json:"insurance"`
+		ActiveFlag    bool      `json:"active_flag"`
+	}
+
+	InsertPriceScenarioData struct {
+		ScenarioName  string      `json:"scenario_name"`
+		MoneyReceived interface{} `json:"money_received"`
+		Bouns         interface{} `json:"bouns"`
+		FixedCost     interface{} `json:"fixed_cost"`
+		Loan          interface{} `json:"loan"`
+		Private       interface{} `json:"private"`
+		Insurance     interface{} `json:"insurance"`
+		ActiveFlag    bool        `json:"active_flag"`
+		UserId        interface{} `json:"user_id"`
+	}
+
+	DeletePriceScenarioData struct {
+		ScenarioId string      `json:"scenario_id"`
+		UserId     interface{} `json:"user_id"`
+	}
+
+	PriceScenarioDataFetcher struct{ db *sql.DB }
+)
+
+// ErrActivePriceScenarioNotFound は有効な収支シナリオが登録されていない場合のエラー
+var ErrActivePriceScenarioNotFound = errors.New("有効な収支シナリオが存在しません。")
+
+func NewPriceScenarioDataFetcher(dataSourceName string) (*PriceScenarioDataFetcher, sqlmock.Sqlmock, error) {
+	if dataSourceName == "test" {
+		db, mock, err := sqlmock.New()
+		return &PriceScenarioDataFetcher{db: db}, mock, err
+	} else {
+		// test実行時に以下のカバレッジは無視する
+		db, err := sql.Open("postgres", dataSourceName)
+		if err != nil {
+			log.Printf("sql.Open error %s", err)
+		}
+		return &PriceScenarioDataFetcher{db: db}, nil, nil
+	}
+}
+
+// scanPriceScenario はシナリオ1行分を構造体に詰める
+func scanPriceScenario(scanner interface{ Scan(dest ...any) error }) (PriceScenarioData, error) {
+	var data PriceScenarioData
+	err := scanner.Scan(
+		&data.ScenarioId,
+		&data.UserId,
+		&data.ScenarioName,
+		&data.MoneyReceived,
+		&data.Bouns,
+		&data.FixedCost,
+		&data.Loan,
+		&data.Private,
+		&data.Insurance,
+		&data.ActiveFlag,
+	)
+	return data, err
+}
+
+// GetPriceScenarios は対象ユーザーの収支シナリオを全て返す。
+//
+// 引数:
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: 取得したDBの構造体
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (pf *PriceScenarioDataFetcher) GetPriceScenarios(UserId int) ([]PriceScenarioData, error) {
+	var scenarioData []PriceScenarioData
+
+	// データベースクエリを実行
+	rows, err := pf.db.Query(DB.GetPriceScenariosSyntax, UserId)
+	if err != nil {
+		return nil, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data, err := scanPriceScenario(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		scenarioData = append(scenarioData, data)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return scenarioData, nil
+}
+
+// GetActivePriceScenario は対象ユーザーの有効な収支シナリオを返す。
+//
+// 引数:
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: 取得したDBの構造体
+//	戻り値2: エラー内容(有効なシナリオがない場合はErrActivePriceScenarioNotFound)
+//
+
+func (pf *PriceScenarioDataFetcher) GetActivePriceScenario(UserId int) (PriceScenarioData, error) {
+	// データベースクエリを実行
+	data, err := scanPriceScenario(pf.db.QueryRow(DB.GetActivePriceScenarioSyntax, UserId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return data, ErrActivePriceScenarioNotFound
+		}
+		return data, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+
+	return data, nil
+}
+
+// InsertPriceScenario は収支シナリオの新規登録
+// 有効フラグが立っている場合は既存の有効なシナリオを無効にしてから登録する
+//
+// 引数:
+//   - data: 登録する収支シナリオ
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (pf *PriceScenarioDataFetcher) InsertPriceScenario(data []InsertPriceScenarioData) error {
+
+	var err error
+	createdAt := time.Now()
+
+	// データベースのクローズをdeferで最初に宣言
+	defer pf.db.Close()
+
+	// トランザクションを開始
+	tx, err := pf.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %v", err)
+	}
+
+	// ロールバックをデフォルトに設定
+	rollback := true
+	defer func() {
+		if rollback {
+			tx.Rollback()
+		}
+	}()
+
+	for _, insertData := range data {
+		if insertData.ActiveFlag {
+			if _, err = tx.Exec(DB.DeactivatePriceScenarioSyntax, createdAt, insertData.UserId); err != nil {
+				return err
+			}
+		}
+
+		scenarioId := uuid.New().String()
+		if _, err = tx.Exec(DB.InsertPriceScenarioSyntax,
+			scenarioId,
+			insertData.UserId,
+			insertData.ScenarioName,
+			insertData.MoneyReceived,
+			insertData.Bouns,
+			insertData.FixedCost,
+			insertData.Loan,
+			insertData.Private,
+			insertData.Insurance,
+			insertData.ActiveFlag,
+			createdAt); err != nil {
+			return err
+		}
+	}
+
+	// コミット処理
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %v", err)
+	}
+
+	rollback = false
+
+	return nil
+}
+
+// ActivatePriceScenario は指定した収支シナリオを有効にし、それ以外を無効にする
+//
+// 引数:
+//   - ScenarioId: シナリオID
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (pf *PriceScenarioDataFetcher) ActivatePriceScenario(ScenarioId string, UserId int) error {
+
+	var err error
+	updateAt := time.Now()
+
+	// データベースのクローズをdeferで最初に宣言
+	defer pf.db.Close()
+
+	// トランザクションを開始
+	tx, err := pf.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %v", err)
+	}
+
+	// ロールバックをデフォルトに設定
+	rollback := true
+	defer func() {
+		if rollback {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.Exec(DB.DeactivatePriceScenarioSyntax, updateAt, UserId); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(DB.ActivatePriceScenarioSyntax, updateAt, ScenarioId, UserId)
+	if err != nil {
+		return err
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return errors.New("対象の収支シナリオが存在しません。")
+	}
+
+	// コミット処理
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %v", err)
+	}
+
+	rollback = false
+
+	return nil
+}
+
+// DeletePriceScenario は収支シナリオの削除
+//
+// 引数:
+//   - data: 削除するシナリオID
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (pf *PriceScenarioDataFetcher) DeletePriceScenario(data []DeletePriceScenarioData) error {
+
+	var err error
+
+	// データベースのクローズをdeferで最初に宣言
+	defer pf.db.Close()
+
+	// トランザクションを開始
+	tx, err := pf.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %v", err)
+	}
+
+	// ロールバックをデフォルトに設定
+	rollback := true
+	defer func() {
+		if rollback {
+			tx.Rollback()
+		}
+	}()
+
+	for _, deleteData := range data {
+		if _, err = tx.Exec(DB.DeletePriceScenarioSyntax, deleteData.ScenarioId, deleteData.UserId); err != nil {
+			return err
+		}
+	}
+
+	// コミット処理
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %v", err)
+	}
+
+	rollback = false
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"server/DB"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var priceScenarioColumns = []string{
+	"scenario_id", "user_id", "scenario_name", "money_received", "bouns",
+	"fixed_cost", "loan", "private", "insurance", "active_flag",
+}
+
+func TestNewPriceScenarioDataFetcher(t *testing.T) {
+	// 通常のテストケース
+	dbFetcher, mock, err := NewPriceScenarioDataFetcher("test")
+	assert.NotNil(t, dbFetcher)
+	assert.NotNil(t, mock)
+	assert.NoError(t, err)
+
+	// カバレッジを通すためのテストケース
+	dbFetcher, _, err = NewPriceScenarioDataFetcher("")
+	assert.NotNil(t, dbFetcher)
+	assert.NoError(t, err)
+}
+
+func TestGetPriceScenarios(t *testing.T) {
+	t.Run("success GetPriceScenarios", func(t *testing.T) {
+		dbFetcher, mock, err := NewPriceScenarioDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		rows := sqlmock.NewRows(priceScenarioColumns).
+			AddRow("8df939de-5a97-4f20-b41b-9ac355c16e36", 1, "現状", 300000, 600000, 100000, 80000, 50000, 10000, true).
+			AddRow("8df939de-5a97-4f20-b41b-9ac355c16e37", 1, "節約", 300000, 600000, 80000, 80000, 30000, 10000, false)
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetPriceScenariosSyntax)).
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		result, err := dbFetcher.GetPriceScenarios(1)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "現状", result[0].ScenarioName)
+		assert.True(t, result[0].ActiveFlag)
+		assert.Equal(t, 30000, result[1].Private)
+	})
+
+	t.Run("error GetPriceScenarios クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewPriceScenarioDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetPriceScenariosSyntax)).
+			WithArgs(1).
+			WillReturnError(fmt.Errorf("クエリの実行に失敗しました"))
+
+		result, err := dbFetcher.GetPriceScenarios(1)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "クエリー実行エラー： クエリの実行に失敗しました")
+	})
+}
+
+func TestGetActivePriceScenario(t *testing.T) {
+	t.Run("success GetActivePriceScenario", func(t *testing.T) {
+		dbFetcher, mock, err := NewPriceScenarioDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		rows := sqlmock.NewRows(priceScenarioColumns).
+			AddRow("8df939de-5a97-4f20-b41b-9ac355c16e36", 1, "現状", 300000, 600000, 100000, 80000, 50000, 10000, true)
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetActivePriceScenarioSyntax)).
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		result, err := dbFetcher.GetActivePriceScenario(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 300000, result.MoneyReceived)
+		assert.True(t, result.ActiveFlag)
+	})
+
+	t.Run("error GetActivePriceScenario 有効なシナリオなし", func(t *testing.T) {
+		dbFetcher, mock, err := NewPriceScenarioDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetActivePriceScenarioSyntax)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(priceScenarioColumns))
+
+		_, err = dbFetcher.GetActivePriceScenario(1)
+
+		assert.ErrorIs(t, err, ErrActivePriceScenarioNotFound)
+	})
+}
+
+func TestInsertPriceScenario(t *testing.T) {
+	testData := []InsertPriceScenarioData{
+		{
+			ScenarioName:  "現状",
+			MoneyReceived: 300000,
+			Bouns:         600000,
+			FixedCost:     100000,
+			Loan:          80000,
+			Private:       50000,
+			Insurance:     10000,
+			ActiveFlag:    true,
+			UserId:        1,
+		},
+	}
+
+	t.Run("success InsertPriceScenario 有効なシナリオを切り替える", func(t *testing.T) {
+		dbFetcher, mock, err := NewPriceScenarioDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeactivatePriceScenarioSyntax)).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertPriceScenarioSyntax)).
+			WithArgs(sqlmock.AnyArg(), 1, "現状", 300000, 600000, 100000, 80000, 50000, 10000, true, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err = dbFetcher.InsertPriceScenario(testData)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error InsertPriceScenario トランザクションエラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewPriceScenarioDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin().WillReturnError(errors.New("transaction error"))
+
+		err = dbFetcher.InsertPriceScenario(testData)
+
+		assert.EqualError(t, err, "トランザクションの開始に失敗しました: transaction error")
+	})
+}
+
+func TestActivatePriceScenario(t *testing.T) {
+	scenarioId := "8df939de-5a97-4f20-b41b-9ac355c16e36"
+
+	t.Run("success ActivatePriceScenario", func(t *testing.T) {
+		dbFetcher, mock, err := NewPriceScenarioDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeactivatePriceScenarioSyntax)).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(DB.ActivatePriceScenarioSyntax)).
+			WithArgs(sqlmock.AnyArg(), scenarioId, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err = dbFetcher.ActivatePriceScenario(scenarioId, 1)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error ActivatePriceScenario 対象なし", func(t *testing.T) {
+		dbFetcher, mock, err := NewPriceScenarioDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeactivatePriceScenarioSyntax)).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(DB.ActivatePriceScenarioSyntax)).
+			WithArgs(sqlmock.AnyArg(), scenarioId, 1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err = dbFetcher.ActivatePriceScenario(scenarioId, 1)
+
+		assert.EqualError(t, err, "対象の収支シナリオが存在しません。")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeletePriceScenario(t *testing.T) {
+	t.Run("success DeletePriceScenario", func(t *testing.T) {
+		dbFetcher, mock, err := NewPriceScenarioDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeletePriceScenarioSyntax)).
+			WithArgs("8df939de-5a97-4f20-b41b-9ac355c16e36", 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err = dbFetcher.DeletePriceScenario([]DeletePriceScenarioData{
+			{ScenarioId: "8df939de-5a97-4f20-b41b-9ac355c16e36", UserId: 1},
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,390 @@
+// models/savings_goal.go
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"server/DB"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+type (
+	SavingsGoalFetcher interface {
+		GetSavingsGoals(UserId int) ([]SavingsGoalData, error)
+		InsertSavingsGoal(data []InsertSavingsGoalData) error
+		UpdateSavingsGoal(data []UpdateSavingsGoalData) error
+		DeleteSavingsGoal(data []DeleteSavingsGoalData) error
+		GetSavingsGoalSnapshots(GoalId string, UserId int) ([]SavingsGoalSnapshotData, error)
+		InsertSavingsGoalSnapshot(data []SavingsGoalSnapshotData) error
+	}
+
+	SavingsGoalData struct {
+		GoalId         uuid.UUID `json:"goal_id"`
+		UserId         int       `json:"user_id"`
+		GoalName       string    `json:"goal_name"`
+		TargetAmount   int       `json:"target_amount"`
+		TargetDate     time.Time `json:"target_date"`
+		CurrentBalance int       `json:"current_balance"`
+	}
+
+	InsertSavingsGoalData struct {
+		GoalName       string      `json:"goal_name"`
+		TargetAmount   interface{} `json:"target_amount"`
+		TargetDate     string      `json:"target_date"`
+		CurrentBalance interface{} `json:"current_balance"`
+		UserId         interface{} `json:"user_id"`
+	}
+
+	UpdateSavingsGoalData struct {
+		GoalId         string      `json:"goal_id"`
+		GoalName       string      `json:"goal_name"`
+		TargetAmount   interface{} `json:"target_amount"`
+		TargetDate     string      `json:"target_date"`
+		CurrentBalance interface{} `json:"current_balance"`
+		UserId         interface{} `json:"user_id"`
+	}
+
+	DeleteSavingsGoalData struct {
+		GoalId string      `json:"goal_id"`
+		UserId interface{} `json:"user_id"`
+	}
+
+	SavingsGoalSnapshotData struct {
+		SnapshotId      uuid.UUID `json:"snapshot_id"`
+		GoalId          uuid.UUID `json:"goal_id"`
+		Balance         int       `json:"balance"`
+		RequiredMonthly int       `json:"required_monthly"`
+		LeftAmount      int       `json:"left_amount"`
+		Status          string    `json:"status"`
+		CreatedAt       time.Time `json:"created_at"`
+	}
+
+	SavingsGoalDataFetcher struct{ db *sql.DB }
+)
+
+func NewSavingsGoalDataFetcher(dataSourceName string) (*SavingsGoalDataFetcher, sqlmock.Sqlmock, error) {
+	if dataSourceName == "test" {
+		db, mock, err := sqlmock.New()
+		return &SavingsGoalDataFetcher{db: db}, mock, err
+	} else {
+		// test実行時に以下のカバレッジは無視する
+		db, err := sql.Open("postgres", dataSourceName)
+		if err != nil {
+			log.Printf("sql.Open error %s", err)
+		}
+		return &SavingsGoalDataFetcher{db: db}, nil, nil
+	}
+}
+
+// GetSavingsGoals は対象ユーザーの貯金目標を目標日の昇順で返す。
+//
+// 引数:
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: 取得したDBの構造体
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (sf *SavingsGoalDataFetcher) GetSavingsGoals(UserId int) ([]SavingsGoalData, error) {
+	var goalData []SavingsGoalData
+
+	// データベースクエリを実行
+	rows, err := sf.db.Query(DB.GetSavingsGoalsSyntax, UserId)
+	if err != nil {
+		return nil, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data SavingsGoalData
+		err := rows.Scan(
+			&data.GoalId,
+			&data.UserId,
+			&data.GoalName,
+			&data.TargetAmount,
+			&data.TargetDate,
+			&data.CurrentBalance,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		goalData = append(goalData, data)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return goalData, nil
+}
+
+// InsertSavingsGoal は貯金目標の新規登録
+//
+// 引数:
+//   - data: 登録する貯金目標
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (sf *SavingsGoalDataFetcher) InsertSavingsGoal(data []InsertSavingsGoalData) error {
+
+	var err error
+	createdAt := time.Now()
+
+	// データベースのクローズをdeferで最初に宣言
+	defer sf.db.Close()
+
+	// トランザクションを開始
+	tx, err := sf.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %v", err)
+	}
+
+	// ロールバックをデフォルトに設定
+	rollback := true
+	defer func() {
+		if rollback {
+			tx.Rollback()
+		}
+	}()
+
+	for _, insertData := range data {
+		goalId := uuid.New().String()
+		if _, err = tx.Exec(DB.InsertSavingsGoalSyntax,
+			goalId,
+			insertData.UserId,
+			insertData.GoalName,
+			insertData.TargetAmount,
+			insertData.TargetDate,
+			insertData.CurrentBalance,
+			createdAt); err != nil {
+			return err
+		}
+	}
+
+	// コミット処理
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %v", err)
+	}
+
+	rollback = false
+
+	return nil
+}
+
+// UpdateSavingsGoal は貯金目標の更新
+// 現在の貯金額の更新もこちらで行う
+//
+// 引数:
+//   - data: 更新する貯金目標
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (sf *SavingsGoalDataFetcher) UpdateSavingsGoal(data []UpdateSavingsGoalData) error {
+
+	var err error
+	updateAt := time.Now()
+
+	// データベースのクローズをdeferで最初に宣言
+	defer sf.db.Close()
+
+	// トランザクションを開始
+	tx, err := sf.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %v", err)
+	}
+
+	// ロールバックをデフォルトに設定
+	rollback := true
+	defer func() {
+		if rollback {
+			tx.Rollback()
+		}
+	}()
+
+	for _, updateData := range data {
+		if _, err = tx.Exec(DB.UpdateSavingsGoalSyntax,
+			updateData.GoalName,
+			updateData.TargetAmount,
+			updateData.TargetDate,
+			updateData.CurrentBalance,
+			updateAt,
+			updateData.GoalId,
+			updateData.UserId); err != nil {
+			return err
+		}
+	}
+
+	// コミット処理
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %v", err)
+	}
+
+	rollback = false
+
+	return nil
+}
+
+// DeleteSavingsGoal は貯金目標の削除
+// 進捗履歴は外部キーで合わせて削除される
+//
+// 引数:
+//   - data: 削除する目標ID
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (sf *SavingsGoalDataFetcher) DeleteSavingsGoal(data []DeleteSavingsGoalData) error {
+
+	var err error
+
+	// データベースのクローズをdeferで最初に宣言
+	defer sf.db.Close()
+
+	// トランザクションを開始
+	tx, err := sf.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %v", err)
+	}
+
+	// ロールバックをデフォルトに設定
+	rollback := true
+	defer func() {
+		if rollback {
+			tx.Rollback()
+		}
+	}()
+
+	for _, deleteData := range data {
+		if _, err = tx.Exec(DB.DeleteSavingsGoalSyntax, deleteData.GoalId, deleteData.UserId); err != nil {
+			return err
+		}
+	}
+
+	// コミット処理
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %v", err)
+	}
+
+	rollback = false
+
+	return nil
+}
+
+// GetSavingsGoalSnapshots は貯金目標の進捗履歴を古い順に返す。
+//
+// 引数:
+//   - GoalId: 目標ID
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: 取得したDBの構造体
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (sf *SavingsGoalDataFetcher) GetSavingsGoalSnapshots(GoalId string, UserId int) ([]SavingsGoalSnapshotData, error) {
+	var snapshotData []SavingsGoalSnapshotData
+
+	// データベースクエリを実行
+	rows, err := sf.db.Query(DB.GetSavingsGoalSnapshotsSyntax, GoalId, UserId)
+	if err != nil {
+		return nil, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data SavingsGoalSnapshotData
+		err := rows.Scan(
+			&data.SnapshotId,
+			&data.GoalId,
+			&data.Balance,
+			&data.RequiredMonthly,
+			&data.LeftAmount,
+			&data.Status,
+			&data.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshotData = append(snapshotData, data)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return snapshotData, nil
+}
+
+// InsertSavingsGoalSnapshot は貯金目標の進捗を記録する
+//
+// 引数:
+//   - data: 記録する進捗
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (sf *SavingsGoalDataFetcher) InsertSavingsGoalSnapshot(data []SavingsGoalSnapshotData) error {
+
+	var err error
+	createdAt := time.Now()
+
+	// データベースのクローズをdeferで最初に宣言
+	defer sf.db.Close()
+
+	// トランザクションを開始
+	tx, err := sf.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %v", err)
+	}
+
+	// ロールバックをデフォルトに設定
+	rollback := true
+	defer func() {
+		if rollback {
+			tx.Rollback()
+		}
+	}()
+
+	for _, snapshot := range data {
+		snapshotId := uuid.New().String()
+		if _, err = tx.Exec(DB.InsertSavingsGoalSnapshotSyntax,
+			snapshotId,
+			snapshot.GoalId.String(),
+			snapshot.Balance,
+			snapshot.RequiredMonthly,
+			snapshot.LeftAmount,
+			snapshot.Status,
+			createdAt); err != nil {
+			return err
+		}
+	}
+
+	// コミット処理
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %v", err)
+	}
+
+	rollback = false
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"server/DB"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSavingsGoalDataFetcher(t *testing.T) {
+	// 通常のテストケース
+	dbFetcher, mock, err := NewSavingsGoalDataFetcher("test")
+	assert.NotNil(t, dbFetcher)
+	assert.NotNil(t, mock)
+	assert.NoError(t, err)
+
+	// カバレッジを通すためのテストケース
+	dbFetcher, _, err = NewSavingsGoalDataFetcher("")
+	assert.NotNil(t, dbFetcher)
+	assert.NoError(t, err)
+}
+
+func TestGetSavingsGoals(t *testing.T) {
+	t.Run("success GetSavingsGoals", func(t *testing.T) {
+		dbFetcher, mock, err := NewSavingsGoalDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		expected := []SavingsGoalData{
+			{
+				GoalId:         uuid.MustParse("8df939de-5a97-4f20-b41b-9ac355c16e36"),
+				UserId:         1,
+				GoalName:       "旅行",
+				TargetAmount:   300000,
+				TargetDate:     time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+				CurrentBalance: 100000,
+			},
+		}
+
+		rows := sqlmock.NewRows([]string{"goal_id", "user_id", "goal_name", "target_amount", "target_date", "current_balance"})
+		for _, data := range expected {
+			rows.AddRow(data.GoalId.String(), data.UserId, data.GoalName, data.TargetAmount, data.TargetDate, data.CurrentBalance)
+		}
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSavingsGoalsSyntax)).
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		result, err := dbFetcher.GetSavingsGoals(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+	})
+
+	t.Run("error GetSavingsGoals クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewSavingsGoalDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSavingsGoalsSyntax)).
+			WithArgs(1).
+			WillReturnError(fmt.Errorf("クエリの実行に失敗しました"))
+
+		result, err := dbFetcher.GetSavingsGoals(1)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "クエリー実行エラー： クエリの実行に失敗しました")
+	})
+}
+
+func TestInsertSavingsGoal(t *testing.T) {
+	testData := []InsertSavingsGoalData{
+		{
+			GoalName:       "旅行",
+			TargetAmount:   300000,
+			TargetDate:     "2025-03-01",
+			CurrentBalance: 100000,
+			UserId:         1,
+		},
+	}
+
+	t.Run("success InsertSavingsGoal", func(t *testing.T) {
+		dbFetcher, mock, err := NewSavingsGoalDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertSavingsGoalSyntax)).
+			WithArgs(sqlmock.AnyArg(), 1, "旅行", 300000, "2025-03-01", 100000, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err = dbFetcher.InsertSavingsGoal(testData)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error InsertSavingsGoal コミットエラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewSavingsGoalDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertSavingsGoalSyntax)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit().WillReturnError(errors.New("commit error"))
+
+		err = dbFetcher.InsertSavingsGoal(testData)
+
+		assert.EqualError(t, err, "トランザクションのコミットに失敗しました: commit error")
+	})
+}
+
+func TestUpdateSavingsGoal(t *testing.T) {
+	t.Run("success UpdateSavingsGoal", func(t *testing.T) {
+		dbFetcher, mock, err := NewSavingsGoalDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.UpdateSavingsGoalSyntax)).
+			WithArgs("旅行", 300000, "2025-03-01", 150000, sqlmock.AnyArg(), "8df939de-5a97-4f20-b41b-9ac355c16e36", 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err = dbFetcher.UpdateSavingsGoal([]UpdateSavingsGoalData{
+			{
+				GoalId:         "8df939de-5a97-4f20-b41b-9ac355c16e36",
+				GoalName:       "旅行",
+				TargetAmount:   300000,
+				TargetDate:     "2025-03-01",
+				CurrentBalance: 150000,
+				UserId:         1,
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDeleteSavingsGoal(t *testing.T) {
+	t.Run("error DeleteSavingsGoal クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewSavingsGoalDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteSavingsGoalSyntax)).
+			WillReturnError(errors.New("delete error"))
+		mock.ExpectRollback()
+
+		err = dbFetcher.DeleteSavingsGoal([]DeleteSavingsGoalData{
+			{GoalId: "8df939de-5a97-4f20-b41b-9ac355c16e36", UserId: 1},
+		})
+
+		assert.EqualError(t, err, "delete error")
+	})
+}
+
+func TestSavingsGoalSnapshot(t *testing.T) {
+	goalId := uuid.MustParse("8df939de-5a97-4f20-b41b-9ac355c16e36")
+
+	t.Run("success InsertSavingsGoalSnapshot", func(t *testing.T) {
+		dbFetcher, mock, err := NewSavingsGoalDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertSavingsGoalSnapshotSyntax)).
+			WithArgs(sqlmock.AnyArg(), goalId.String(), 100000, 20000, 30000, "on_track", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err = dbFetcher.InsertSavingsGoalSnapshot([]SavingsGoalSnapshotData{
+			{
+				GoalId:          goalId,
+				Balance:         100000,
+				RequiredMonthly: 20000,
+				LeftAmount:      30000,
+				Status:          "on_track",
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("success GetSavingsGoalSnapshots", func(t *testing.T) {
+		dbFetcher, mock, err := NewSavingsGoalDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		createdAt := time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows([]string{"snapshot_id", "goal_id", "balance", "required_monthly", "left_amount", "status", "created_at"}).
+			AddRow("8df939de-5a97-4f20-b41b-9ac355c16e40", goalId.String(), 100000, 20000, 30000, "on_track", createdAt)
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSavingsGoalSnapshotsSyntax)).
+			WithArgs(goalId.String(), 1).
+			WillReturnRows(rows)
+
+		result, err := dbFetcher.GetSavingsGoalSnapshots(goalId.String(), 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 1)
+		assert.Equal(t, goalId, result[0].GoalId)
+		assert.Equal(t, "on_track", result[0].Status)
+		assert.Equal(t, createdAt, result[0].CreatedAt)
+	})
+}
@@ -0,0 +1,214 @@
+// models/session.go
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"server/DB"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/lib/pq"
+)
+
+type (
+	SessionFetcher interface {
+		GetSessions(UserId int, since time.Time) ([]SessionData, error)
+		InsertSession(data SessionData) error
+		TouchSession(SessionId string) error
+		RevokeSession(SessionId string, UserId int) error
+		RevokeOtherSessions(UserId int, CurrentSessionId string) ([]string, error)
+	}
+
+	SessionData struct {
+		SessionId  string    `json:"session_id"`
+		UserId     int       `json:"user_id"`
+		SignInType string    `json:"sign_in_type"`
+		UserAgent  string    `json:"user_agent"`
+		IpAddress  string    `json:"ip_address"`
+		CreatedAt  time.Time `json:"created_at"`
+		LastSeenAt time.Time `json:"last_seen_at"`
+		Current    bool      `json:"current"`
+	}
+
+	SessionDataFetcher struct{ db *sql.DB }
+)
+
+func NewSessionDataFetcher(dataSourceName string) (*SessionDataFetcher, sqlmock.Sqlmock, error) {
+	if dataSourceName == "test" {
+		db, mock, err := sqlmock.New()
+		return &SessionDataFetcher{db: db}, mock, err
+	} else {
+		// test実行時に以下のカバレッジは無視する
+		db, err := sql.Open("postgres", dataSourceName)
+		if err != nil {
+			log.Printf("sql.Open error %s", err)
+		}
+		return &SessionDataFetcher{db: db}, nil, nil
+	}
+}
+
+// GetSessions は対象ユーザーの有効なセッションを最終利用日時の新しい順で返す。
+//
+// 引数:
+//   - UserId: ユーザーID
+//   - since: この日時以降に利用されたセッションのみ返す(期限切れのセッションを除くため)
+//
+// 戻り値:
+//
+//	戻り値1: 取得したDBの構造体
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (sf *SessionDataFetcher) GetSessions(UserId int, since time.Time) ([]SessionData, error) {
+	var sessions []SessionData
+
+	defer sf.db.Close()
+
+	// データベースクエリを実行
+	rows, err := sf.db.Query(DB.GetSessionsSyntax, UserId, since)
+	if err != nil {
+		return nil, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data SessionData
+		if err := rows.Scan(
+			&data.SessionId,
+			&data.UserId,
+			&data.SignInType,
+			&data.UserAgent,
+			&data.IpAddress,
+			&data.CreatedAt,
+			&data.LastSeenAt,
+		); err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, data)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// InsertSession はサインイン成功時のセッションを登録する
+//
+// 引数:
+//   - data: 登録するセッション
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (sf *SessionDataFetcher) InsertSession(data SessionData) error {
+
+	defer sf.db.Close()
+
+	if _, err := sf.db.Exec(
+		DB.InsertSessionSyntax,
+		data.SessionId,
+		data.UserId,
+		data.SignInType,
+		data.UserAgent,
+		data.IpAddress,
+		data.CreatedAt,
+		data.LastSeenAt,
+	); err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+
+	return nil
+}
+
+// TouchSession はセッションの最終利用日時を更新する
+//
+// 引数:
+//   - SessionId: セッションID
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (sf *SessionDataFetcher) TouchSession(SessionId string) error {
+
+	defer sf.db.Close()
+
+	if _, err := sf.db.Exec(DB.TouchSessionSyntax, time.Now(), SessionId); err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+
+	return nil
+}
+
+// RevokeSession は指定したセッションを失効させる
+//
+// 引数:
+//   - SessionId: セッションID
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (sf *SessionDataFetcher) RevokeSession(SessionId string, UserId int) error {
+
+	defer sf.db.Close()
+
+	result, err := sf.db.Exec(DB.RevokeSessionSyntax, time.Now(), SessionId, UserId)
+	if err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return errors.New("対象のセッションが存在しません。")
+	}
+
+	return nil
+}
+
+// RevokeOtherSessions は現在のセッション以外の全てのセッションを失効させる
+//
+// 引数:
+//   - UserId: ユーザーID
+//   - CurrentSessionId: 現在のセッションID
+//
+// 戻り値:
+//
+//	戻り値1: 失効させたセッションID
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (sf *SessionDataFetcher) RevokeOtherSessions(UserId int, CurrentSessionId string) ([]string, error) {
+	sessionIds := []string{}
+
+	defer sf.db.Close()
+
+	rows, err := sf.db.Query(DB.RevokeOtherSessionsSyntax, time.Now(), UserId, CurrentSessionId)
+	if err != nil {
+		return nil, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sessionId string
+		if err := rows.Scan(&sessionId); err != nil {
+			return nil, err
+		}
+		sessionIds = append(sessionIds, sessionId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessionIds, nil
+}
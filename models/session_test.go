@@ -0,0 +1,204 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"server/DB"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var sessionColumns = []string{
+	"session_id", "user_id", "sign_in_type", "user_agent", "ip_address", "created_at", "last_seen_at",
+}
+
+func TestNewSessionDataFetcher(t *testing.T) {
+	// 通常のテストケース
+	dbFetcher, mock, err := NewSessionDataFetcher("test")
+	assert.NotNil(t, dbFetcher)
+	assert.NotNil(t, mock)
+	assert.NoError(t, err)
+
+	// カバレッジを通すためのテストケース
+	dbFetcher, _, err = NewSessionDataFetcher("")
+	assert.NotNil(t, dbFetcher)
+	assert.NoError(t, err)
+}
+
+func TestGetSessions(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success GetSessions", func(t *testing.T) {
+		dbFetcher, mock, err := NewSessionDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		now := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+		rows := sqlmock.NewRows(sessionColumns).
+			AddRow("8df939de-5a97-4f20-b41b-9ac355c16e36", 1, "password", "Mozilla/5.0", "192.0.2.1", now, now).
+			AddRow("8df939de-5a97-4f20-b41b-9ac355c16e37", 1, "google", "curl/8.0", "192.0.2.2", now, now)
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSessionsSyntax)).
+			WithArgs(1, since).
+			WillReturnRows(rows)
+
+		result, err := dbFetcher.GetSessions(1, since)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "password", result[0].SignInType)
+		assert.Equal(t, "192.0.2.2", result[1].IpAddress)
+	})
+
+	t.Run("error GetSessions クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewSessionDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSessionsSyntax)).
+			WithArgs(1, since).
+			WillReturnError(fmt.Errorf("クエリの実行に失敗しました"))
+
+		result, err := dbFetcher.GetSessions(1, since)
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "クエリー実行エラー： クエリの実行に失敗しました")
+	})
+}
+
+func TestInsertSession(t *testing.T) {
+	now := time.Now()
+	data := SessionData{
+		SessionId:  "8df939de-5a97-4f20-b41b-9ac355c16e36",
+		UserId:     1,
+		SignInType: "password",
+		UserAgent:  "Mozilla/5.0",
+		IpAddress:  "192.0.2.1",
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+
+	t.Run("success InsertSession", func(t *testing.T) {
+		dbFetcher, mock, err := NewSessionDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertSessionSyntax)).
+			WithArgs(data.SessionId, 1, "password", "Mozilla/5.0", "192.0.2.1", now, now).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err = dbFetcher.InsertSession(data)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error InsertSession クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewSessionDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertSessionSyntax)).
+			WillReturnError(fmt.Errorf("クエリの実行に失敗しました"))
+
+		err = dbFetcher.InsertSession(data)
+
+		assert.EqualError(t, err, "クエリー実行エラー： クエリの実行に失敗しました")
+	})
+}
+
+func TestTouchSession(t *testing.T) {
+	t.Run("success TouchSession", func(t *testing.T) {
+		dbFetcher, mock, err := NewSessionDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.TouchSessionSyntax)).
+			WithArgs(sqlmock.AnyArg(), "8df939de-5a97-4f20-b41b-9ac355c16e36").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = dbFetcher.TouchSession("8df939de-5a97-4f20-b41b-9ac355c16e36")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRevokeSession(t *testing.T) {
+	t.Run("success RevokeSession", func(t *testing.T) {
+		dbFetcher, mock, err := NewSessionDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.RevokeSessionSyntax)).
+			WithArgs(sqlmock.AnyArg(), "8df939de-5a97-4f20-b41b-9ac355c16e36", 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = dbFetcher.RevokeSession("8df939de-5a97-4f20-b41b-9ac355c16e36", 1)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("error RevokeSession 対象なし", func(t *testing.T) {
+		dbFetcher, mock, err := NewSessionDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.RevokeSessionSyntax)).
+			WithArgs(sqlmock.AnyArg(), "8df939de-5a97-4f20-b41b-9ac355c16e36", 1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = dbFetcher.RevokeSession("8df939de-5a97-4f20-b41b-9ac355c16e36", 1)
+
+		assert.EqualError(t, err, "対象のセッションが存在しません。")
+	})
+}
+
+func TestRevokeOtherSessions(t *testing.T) {
+	t.Run("success RevokeOtherSessions", func(t *testing.T) {
+		dbFetcher, mock, err := NewSessionDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		rows := sqlmock.NewRows([]string{"session_id"}).
+			AddRow("8df939de-5a97-4f20-b41b-9ac355c16e37").
+			AddRow("8df939de-5a97-4f20-b41b-9ac355c16e38")
+		mock.ExpectQuery(regexp.QuoteMeta(DB.RevokeOtherSessionsSyntax)).
+			WithArgs(sqlmock.AnyArg(), 1, "8df939de-5a97-4f20-b41b-9ac355c16e36").
+			WillReturnRows(rows)
+
+		result, err := dbFetcher.RevokeOtherSessions(1, "8df939de-5a97-4f20-b41b-9ac355c16e36")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"8df939de-5a97-4f20-b41b-9ac355c16e37",
+			"8df939de-5a97-4f20-b41b-9ac355c16e38",
+		}, result)
+	})
+
+	t.Run("error RevokeOtherSessions クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewSessionDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.RevokeOtherSessionsSyntax)).
+			WillReturnError(fmt.Errorf("クエリの実行に失敗しました"))
+
+		result, err := dbFetcher.RevokeOtherSessions(1, "8df939de-5a97-4f20-b41b-9ac355c16e36")
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "クエリー実行エラー： クエリの実行に失敗しました")
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"server/DB"
+	"server/enum"
 	"server/utils"
 	"time"
 
@@ -20,10 +21,17 @@ type (
 		GetExternalAuth(UserEmail string) (ExternalAuthData, error)
 		PostSignUp(data RequestSignUpData) (int, error)
 		PutSignInEdit(UserId int, data RequestSignInEditData) error
-		PutCheck(data RequestSignInEditData) (string, error)
-		DeleteSignIn(userId int, data RequestSignInDeleteData) error
+		PutCheck(UserId int, data RequestSignInEditData) (string, error)
+		DeleteSignIn(userId int, data RequestSignInDeleteData, PurgeAt time.Time) error
+		RestoreSignIn(UserId int) error
+		PurgeDeactivatedUsers(Now time.Time) ([]DeactivatedUserData, error)
 		GetUserId(UserEmail string) (int, error)
-		NewPasswordUpdate(data RequestNewPasswordUpdateData) (string, error)
+		GetUserEmail(userId int) (string, error)
+		NewPasswordUpdate(UserId int, data RequestNewPasswordUpdateData) (string, error)
+		CheckPasswordReuse(UserId int, password string) error
+		InvalidatePassword(UserId int, unusablePassword string) error
+		GetUserAccount(UserId int) (UserAccountData, error)
+		ChangeUserEmail(UserId int, CurrentEmail, NewEmail string) error
 	}
 
 	RequestSignInData struct {
@@ -38,8 +46,9 @@ type (
 	}
 
 	RequestSignInEditData struct {
-		UserEmail    string `json:"user_email"`
-		UserPassword string `json:"user_password"`
+		UserEmail       string `json:"user_email"`
+		UserPassword    string `json:"user_password"`
+		CurrentPassword string `json:"current_password"`
 	}
 
 	RequestSignInDeleteData struct {
@@ -64,6 +73,20 @@ type (
 		UserEmail string
 	}
 
+	// 削除予定日時を過ぎて削除したユーザー
+	DeactivatedUserData struct {
+		UserId    int
+		UserEmail string
+		UserName  string
+	}
+
+	UserAccountData struct {
+		UserId    int
+		UserEmail string
+		// パスワードで登録したユーザーの場合はtrue
+		PasswordAccount bool
+	}
+
 	SignUpData struct {
 		UserEmail    string
 		UserPassword string
@@ -85,6 +108,17 @@ type (
 	}
 )
 
+// passwordHistoryQueryer はパスワードの履歴を取得するDB(*sql.DB)又はトランザクション(*sql.Tx)
+type passwordHistoryQueryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// ErrPasswordReused は直近に使用したパスワードに変更しようとした場合のエラー
+var ErrPasswordReused = errors.New("直近に使用したパスワードは使用できません。")
+
+// ErrCurrentPasswordMismatch はサイン情報の編集時に現在のパスワードが一致しない場合のエラー
+var ErrCurrentPasswordMismatch = errors.New("現在のパスワードが一致しませんでした。")
+
 func NewSignDataFetcher(dataSourceName string, UtilsFetcher utils.UtilsFetcher) (*SignDataFetcher, sqlmock.Sqlmock, error) {
 	if dataSourceName == "test" {
 		db, mock, err := sqlmock.New()
@@ -115,6 +149,7 @@ func (pf *SignDataFetcher) GetSignIn(data RequestSignInData) (SignInData, error)
 	var result SignInData
 	var err error
 	var found bool = false
+	var newHash string
 
 	// データベースクエリを実行
 	rows, err := pf.db.Query(DB.GetSignInSyntax, data.UserEmail)
@@ -138,7 +173,7 @@ func (pf *SignDataFetcher) GetSignIn(data RequestSignInData) (SignInData, error)
 		found = true
 
 		// パスワードの整合性を確認
-		err = pf.UtilsFetcher.CompareHashPassword(record.UserPassword, data.UserPassword)
+		newHash, err = pf.UtilsFetcher.CompareHashPassword(record.UserPassword, data.UserPassword)
 		if err == nil {
 			// パスワードが一致する場合のみ結果に追加
 			result = record
@@ -154,6 +189,14 @@ func (pf *SignDataFetcher) GetSignIn(data RequestSignInData) (SignInData, error)
 		return result, errors.New("存在しないメールアドレスです。")
 	}
 
+	// ハッシュの方式・パラメーターが古い場合は再作成したハッシュに置き換える
+	// 更新に失敗してもサインインは継続し、次回のサインインで再度更新する
+	if newHash != "" {
+		if _, err := pf.db.Exec(DB.UpdatePasswordHashSyntax, newHash, result.UserId, result.UserPassword); err != nil {
+			log.Printf("パスワードのハッシュ更新エラー user_id=%d: %v", result.UserId, err)
+		}
+	}
+
 	return result, nil
 }
 
@@ -246,7 +289,7 @@ func (pf *SignDataFetcher) PostSignUp(data RequestSignUpData) (int, error) {
 		createdAt,
 		data.UserName,
 		createdAt,
-		1).Scan(&userId)
+		enum.DELETE_FLAG_OFF).Scan(&userId)
 
 	if err != nil {
 		return 0, fmt.Errorf("ユーザー情報の登録に失敗しました: %v", err)
@@ -304,7 +347,9 @@ func (pf *SignDataFetcher) PutSignInEdit(UserId int, data RequestSignInEditData)
 	}
 
 	if data.UserPassword != "" {
-		// TBD:変更する値もハッシュ化する
+		if err := pf.savePasswordHistory(tx, UserId, data.UserPassword, updateAt); err != nil {
+			return err
+		}
 		hashPassword, _ := pf.UtilsFetcher.EncryptPassword(data.UserPassword)
 		userPassword = &hashPassword
 	}
@@ -331,23 +376,26 @@ func (pf *SignDataFetcher) PutSignInEdit(UserId int, data RequestSignInEditData)
 	return nil
 }
 
-// PutCheck サイン情報修正した際に、メールアドレスかパスワードどちらを更新したかチェックする
+// PutCheck サイン情報修正した際に、現在のパスワードを確認し、メールアドレスかパスワードどちらを更新したかチェックする
+// 現在のパスワードはリクエストのメールアドレスではなく、ユーザーIDの登録情報と照合する
 //
 // 引数:
-//   - data: { user_id: int, user_email: string, user_password: string }
+//   - UserId: サインイン中のユーザーID
+//   - data: { user_email: string, user_password: string, current_password: string }
 //
 // 戻り値:
 //
-//	戻り値1: 文字列, nil(errorの場合error)
+//	戻り値1: 文字列, nil(errorの場合error、現在のパスワードが一致しない場合はErrCurrentPasswordMismatch)
 //
 
-func (pf *SignDataFetcher) PutCheck(data RequestSignInEditData) (string, error) {
+func (pf *SignDataFetcher) PutCheck(UserId int, data RequestSignInEditData) (string, error) {
 
 	var err error
 	var result string
+	var found bool = false
 
 	// データベースクエリを実行
-	rows, err := pf.db.Query(DB.GetSignInSyntax, data.UserEmail)
+	rows, err := pf.db.Query(DB.GetSignInByUserIdSyntax, UserId)
 	if err != nil {
 		return "", fmt.Errorf("クエリー実行エラー： %v", err)
 	}
@@ -355,7 +403,6 @@ func (pf *SignDataFetcher) PutCheck(data RequestSignInEditData) (string, error)
 
 	// `rows.Next()`で結果があるかを確認
 	if rows.Next() {
-		// 最初の行をスキャン（ユーザーネームが存在する）
 		var record SignInData
 		err := rows.Scan(
 			&record.UserId,
@@ -366,15 +413,18 @@ func (pf *SignDataFetcher) PutCheck(data RequestSignInEditData) (string, error)
 			return "", err
 		}
 
-		// パスワードの整合性を確認
-		err = pf.UtilsFetcher.CompareHashPassword(record.UserPassword, data.UserPassword)
-		if err != nil {
-			// パスワードが異なる場合（更新の必要あり）
+		found = true
+
+		// 現在のパスワードの整合性を確認
+		if _, err := pf.UtilsFetcher.CompareHashPassword(record.UserPassword, data.CurrentPassword); err != nil {
+			return "", ErrCurrentPasswordMismatch
+		}
+
+		if data.UserEmail != record.UserEmail {
+			result = "メールアドレス更新"
+		} else {
 			result = "パスワード更新"
 		}
-	} else {
-		// ユーザーネームが存在しない場合
-		result = "メールアドレス更新"
 	}
 
 	// `rows.Err()`でカーソル操作中のエラーを確認
@@ -382,20 +432,27 @@ func (pf *SignDataFetcher) PutCheck(data RequestSignInEditData) (string, error)
 		return "", err
 	}
 
+	if !found {
+		return "", errors.New("登録ユーザーが存在しません")
+	}
+
 	return result, nil
 }
 
-// DeleteSignIn サインイン情報を削除API
+// DeleteSignIn はユーザーを退会状態にする
+// 削除予定日時まではデータを残し、復元できるようにする
 //
 // 引数:
-//   - data: { user_id: int}
+//   - userId: ユーザーID
+//   - data: { user_email: string }
+//   - PurgeAt: 削除予定日時
 //
 // 戻り値:
 //
 //	戻り値1: エラー内容(エラーがない場合はnil)
 //
 
-func (pf *SignDataFetcher) DeleteSignIn(userId int, data RequestSignInDeleteData) error {
+func (pf *SignDataFetcher) DeleteSignIn(userId int, data RequestSignInDeleteData, PurgeAt time.Time) error {
 
 	var err error
 
@@ -418,12 +475,19 @@ func (pf *SignDataFetcher) DeleteSignIn(userId int, data RequestSignInDeleteData
 
 	signInDelete := DB.DeleteSignInSyntax
 
-	if _, err = tx.Exec(
+	result, err := tx.Exec(
 		signInDelete,
+		enum.DELETE_FLAG_ON,
+		PurgeAt,
+		time.Now(),
 		userId,
-		data.UserEmail); err != nil {
+		data.UserEmail)
+	if err != nil {
 		return fmt.Errorf("ユーザー削除に失敗しました: %v", err)
 	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return errors.New("削除対象のユーザーが存在しないか、退会済みです。")
+	}
 
 	// コミット処理
 	if err := tx.Commit(); err != nil {
@@ -435,6 +499,93 @@ func (pf *SignDataFetcher) DeleteSignIn(userId int, data RequestSignInDeleteData
 	return nil
 }
 
+// RestoreSignIn は退会中のユーザーを復元する
+//
+// 引数:
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(退会中でない・削除予定日時を過ぎた場合はエラー)
+//
+
+func (pf *SignDataFetcher) RestoreSignIn(UserId int) error {
+
+	defer pf.db.Close()
+
+	result, err := pf.db.Exec(DB.RestoreSignInSyntax, enum.DELETE_FLAG_OFF, time.Now(), UserId)
+	if err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return errors.New("復元できるユーザーが存在しません。")
+	}
+
+	return nil
+}
+
+// PurgeDeactivatedUsers は削除予定日時を過ぎたユーザーと年収推移データを削除する
+//
+// 引数:
+//   - Now: 基準日時(この日時以前に削除予定のユーザーを削除)
+//
+// 戻り値:
+//
+//	戻り値1: 削除したユーザー
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (pf *SignDataFetcher) PurgeDeactivatedUsers(Now time.Time) ([]DeactivatedUserData, error) {
+	users := []DeactivatedUserData{}
+
+	// データベースのクローズをdeferで最初に宣言
+	defer pf.db.Close()
+
+	// トランザクションを開始
+	tx, err := pf.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("トランザクションの開始に失敗しました: %v", err)
+	}
+
+	// ロールバックをデフォルトに設定
+	rollback := true
+	defer func() {
+		if rollback {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err := tx.Exec(DB.PurgeIncomeSyntax, Now); err != nil {
+		return nil, fmt.Errorf("年収推移データの削除に失敗しました: %v", err)
+	}
+
+	rows, err := tx.Query(DB.PurgeUsersSyntax, Now)
+	if err != nil {
+		return nil, fmt.Errorf("ユーザー削除に失敗しました: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record DeactivatedUserData
+		if err := rows.Scan(&record.UserId, &record.UserEmail, &record.UserName); err != nil {
+			return nil, err
+		}
+		users = append(users, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// コミット処理
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("トランザクションのコミットに失敗しました: %v", err)
+	}
+
+	rollback = false
+
+	return users, nil
+}
+
 // GetUserId user_idを返す
 //
 // 引数:
@@ -460,17 +611,44 @@ func (pf *SignDataFetcher) GetUserId(UserEmail string) (int, error) {
 	return record.UserId, nil
 }
 
+// GetUserEmail ユーザーIDから登録メールアドレスを取得
+//
+// 引数:
+//   - userId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: 登録メールアドレス
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (pf *SignDataFetcher) GetUserEmail(userId int) (string, error) {
+
+	// データベースクエリを実行
+	row := pf.db.QueryRow(DB.PasswordCheckSyntax, userId)
+	var userEmail string
+	if err := row.Scan(&userEmail); err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("登録ユーザーが存在しません")
+		}
+		return "", err
+	}
+	return userEmail, nil
+}
+
 // NewPasswordUpdate 新しいパスワードに更新
+// ユーザーIDは検証済みのパスワード再設定トークンから取得したものを指定する
 //
 // 引数:
-//   - data: { current_password: string, new_user_password: string, confirm_password: string }
+//   - UserId: ユーザーID
+//   - data: { token_id: string, new_user_password: string, confirm_password: string }
 //
 // 戻り値:
 //
 //	戻り値1: エラー内容(エラーがない場合はnil)
 //
 
-func (pf *SignDataFetcher) NewPasswordUpdate(data RequestNewPasswordUpdateData) (string, error) {
+func (pf *SignDataFetcher) NewPasswordUpdate(UserId int, data RequestNewPasswordUpdateData) (string, error) {
 
 	var hashPassword string
 	var userEmail string
@@ -480,10 +658,8 @@ func (pf *SignDataFetcher) NewPasswordUpdate(data RequestNewPasswordUpdateData)
 
 	// 1. 登録済みのユーザーパスワードの整合性チェック
 
-	userId := data.TokenId[utils.Uuid:]
-
 	// データベースクエリを実行
-	row := pf.db.QueryRow(DB.PasswordCheckSyntax, userId)
+	row := pf.db.QueryRow(DB.PasswordCheckSyntax, UserId)
 	if err := row.Scan(&userEmail); err != nil {
 		if err == sql.ErrNoRows {
 			return "", fmt.Errorf("登録ユーザーが存在しません")
@@ -509,13 +685,18 @@ func (pf *SignDataFetcher) NewPasswordUpdate(data RequestNewPasswordUpdateData)
 	if data.NewUserPassword != data.ConfirmPassword {
 		return "", fmt.Errorf("新しいパスワードと確認用のパスワードが一致しませんでした。")
 	}
+
+	updateAt := time.Now()
+	if err := pf.savePasswordHistory(tx, UserId, data.NewUserPassword, updateAt); err != nil {
+		return "", err
+	}
 	hashPassword, _ = pf.UtilsFetcher.EncryptPassword(data.NewUserPassword)
 
 	if _, err = tx.Exec(
 		DB.PutPasswordSyntax,
 		hashPassword,
-		time.Now(),
-		userId,
+		updateAt,
+		UserId,
 	); err != nil {
 		return "", fmt.Errorf("パスワード更新クエリの実行に失敗しました: %v", err)
 	}
@@ -529,3 +710,188 @@ func (pf *SignDataFetcher) NewPasswordUpdate(data RequestNewPasswordUpdateData)
 
 	return userEmail, nil
 }
+
+// savePasswordHistory は直近のパスワードの再利用を確認し、変更前のパスワードのハッシュを履歴に登録する
+// パスワードの更新と同じトランザクションで実行する
+//
+// 引数:
+//   - tx: パスワードを更新するトランザクション
+//   - UserId: ユーザーID
+//   - password: 変更後の平文のパスワード
+//   - now: 変更日時
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(直近のパスワードと一致する場合はErrPasswordReused)
+//
+
+func (pf *SignDataFetcher) savePasswordHistory(tx *sql.Tx, UserId int, password string, now time.Time) error {
+	historyCount := utils.CurrentPasswordPolicy.HistoryCount
+
+	if err := pf.checkPasswordHistory(tx, UserId, password); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(DB.InsertPasswordHistorySyntax, UserId, now); err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	if _, err := tx.Exec(DB.PrunePasswordHistorySyntax, UserId, max(historyCount-1, 0)); err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	return nil
+}
+
+// checkPasswordHistory は変更後のパスワードが現在のパスワード又は直近の履歴と一致しないか確認する
+//
+// 引数:
+//   - q: 履歴を取得するDB又はトランザクション
+//   - UserId: ユーザーID
+//   - password: 変更後の平文のパスワード
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(直近のパスワードと一致する場合はErrPasswordReused)
+//
+
+func (pf *SignDataFetcher) checkPasswordHistory(q passwordHistoryQueryer, UserId int, password string) error {
+	historyCount := utils.CurrentPasswordPolicy.HistoryCount
+	if historyCount <= 0 {
+		return nil
+	}
+
+	// 現在のパスワードと直近の履歴(現在のパスワードを含めてポリシーの件数分)
+	rows, err := q.Query(DB.GetPasswordHistorySyntax, UserId, historyCount-1)
+	if err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			rows.Close()
+			return err
+		}
+		hashes = append(hashes, hash)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, hash := range hashes {
+		if _, err := pf.UtilsFetcher.CompareHashPassword(hash, password); err == nil {
+			return ErrPasswordReused
+		}
+	}
+	return nil
+}
+
+// CheckPasswordReuse 変更後のパスワードが直近に使用したパスワードと一致しないか確認
+// パスワード再設定のリンクを使用済みにする前に確認し、再利用の場合はリンクを消費しない
+//
+// 引数:
+//   - UserId: ユーザーID
+//   - password: 変更後の平文のパスワード
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(直近のパスワードと一致する場合はErrPasswordReused)
+//
+
+func (pf *SignDataFetcher) CheckPasswordReuse(UserId int, password string) error {
+
+	defer pf.db.Close()
+
+	return pf.checkPasswordHistory(pf.db, UserId, password)
+}
+
+// InvalidatePassword 現在のパスワードを使用できない値に置き換える
+// 管理者による強制リセットで使用し、置き換え後の値はパスワードの履歴に登録しない
+//
+// 引数:
+//   - UserId: ユーザーID
+//   - unusablePassword: 置き換え後のランダムな平文(ハッシュ化して保存する)
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (pf *SignDataFetcher) InvalidatePassword(UserId int, unusablePassword string) error {
+
+	defer pf.db.Close()
+
+	hashPassword, err := pf.UtilsFetcher.EncryptPassword(unusablePassword)
+	if err != nil {
+		return err
+	}
+
+	if _, err := pf.db.Exec(
+		DB.PutPasswordSyntax,
+		hashPassword,
+		time.Now(),
+		UserId,
+	); err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+
+	return nil
+}
+
+// GetUserAccount ユーザーIDから登録メールアドレスと認証方法を取得
+//
+// 引数:
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: 登録情報
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (pf *SignDataFetcher) GetUserAccount(UserId int) (UserAccountData, error) {
+	var data UserAccountData
+
+	defer pf.db.Close()
+
+	err := pf.db.QueryRow(DB.GetUserAccountSyntax, UserId).Scan(
+		&data.UserId,
+		&data.UserEmail,
+		&data.PasswordAccount,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return data, errors.New("登録ユーザーが存在しません")
+	} else if err != nil {
+		return data, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+
+	return data, nil
+}
+
+// ChangeUserEmail 登録メールアドレスを変更
+// 確認済みの変更(又は変更の取り消し)のみで使用し、現在のメールアドレスが一致する場合のみ更新する
+//
+// 引数:
+//   - UserId: ユーザーID
+//   - CurrentEmail: 現在のメールアドレス
+//   - NewEmail: 変更後のメールアドレス
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (pf *SignDataFetcher) ChangeUserEmail(UserId int, CurrentEmail, NewEmail string) error {
+
+	defer pf.db.Close()
+
+	result, err := pf.db.Exec(DB.ChangeUserEmailSyntax, NewEmail, time.Now(), UserId, CurrentEmail)
+	if err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return errors.New("メールアドレスを変更できませんでした。既に変更済みか、変更先のメールアドレスが登録済みです。")
+	}
+
+	return nil
+}
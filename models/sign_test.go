@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"regexp"
 	"server/DB"
+	"server/enum"
 	"server/utils"
 	"testing"
+	"time"
 
 	mock_utils "server/mock/utils"
 
@@ -23,7 +25,7 @@ func TestGetSignIn(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -52,7 +54,7 @@ func TestGetSignIn(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -89,7 +91,7 @@ func TestGetSignIn(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -130,7 +132,7 @@ func TestGetSignIn(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -142,8 +144,8 @@ func TestGetSignIn(t *testing.T) {
 			UserPassword: "Test12345!",
 		}
 
-		// 平文のパスワードをハッシュ化
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(requestData.UserPassword), bcrypt.DefaultCost)
+		// 平文のパスワードを現在の設定でハッシュ化
+		hashedPassword, err := utils.NewUtilsFetcher(utils.JwtKeys).EncryptPassword(requestData.UserPassword)
 		if err != nil {
 			t.Fatalf("Error hashing password: %v", err)
 		}
@@ -151,7 +153,7 @@ func TestGetSignIn(t *testing.T) {
 		mockData := SignInData{
 			UserId:       1,
 			UserEmail:    "test@exmple.com",
-			UserPassword: string(hashedPassword),
+			UserPassword: hashedPassword,
 		}
 
 		expectedData := mockData
@@ -187,11 +189,95 @@ func TestGetSignIn(t *testing.T) {
 		}
 	})
 
+	t.Run("GetSignIn 成功 bcryptのハッシュをArgon2idに更新", func(t *testing.T) {
+		dbFetcher, mock, err := NewSignDataFetcher(
+			"test",
+			utils.NewUtilsFetcher(utils.JwtKeys),
+		)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		requestData := RequestSignInData{
+			UserEmail:    "test@exmple.com",
+			UserPassword: "Test12345!",
+		}
+
+		// Argon2id導入前のbcryptのハッシュ
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(requestData.UserPassword), bcrypt.DefaultCost)
+		if err != nil {
+			t.Fatalf("Error hashing password: %v", err)
+		}
+
+		rows := sqlmock.NewRows([]string{
+			"user_id", "user_email", "user_password",
+		}).AddRow(1, requestData.UserEmail, string(hashedPassword))
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSignInSyntax)).
+			WithArgs(requestData.UserEmail).
+			WillReturnRows(rows)
+		mock.ExpectExec(regexp.QuoteMeta(DB.UpdatePasswordHashSyntax)).
+			WithArgs(sqlmock.AnyArg(), 1, string(hashedPassword)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		result, err := dbFetcher.GetSignIn(requestData)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.UserId)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("mock check: %s", err)
+		}
+	})
+
+	t.Run("GetSignIn 成功 ハッシュの更新に失敗してもサインインできる", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().
+			CompareHashPassword("old-hash", "Test12345!").
+			Return("new-hash", nil)
+
+		dbFetcher, mock, err := NewSignDataFetcher(
+			"test",
+			mockUtilsFetcher,
+		)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		requestData := RequestSignInData{
+			UserEmail:    "test@exmple.com",
+			UserPassword: "Test12345!",
+		}
+
+		rows := sqlmock.NewRows([]string{
+			"user_id", "user_email", "user_password",
+		}).AddRow(1, requestData.UserEmail, "old-hash")
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSignInSyntax)).
+			WithArgs(requestData.UserEmail).
+			WillReturnRows(rows)
+		mock.ExpectExec(regexp.QuoteMeta(DB.UpdatePasswordHashSyntax)).
+			WithArgs("new-hash", 1, "old-hash").
+			WillReturnError(fmt.Errorf("update error"))
+
+		result, err := dbFetcher.GetSignIn(requestData)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.UserId)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("mock check: %s", err)
+		}
+	})
+
 	t.Run("GetSignIn 存在しないメールアドレスです", func(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -231,7 +317,7 @@ func TestGetSignIn(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -289,7 +375,7 @@ func TestGetExternalAuth(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -315,7 +401,7 @@ func TestGetExternalAuth(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -345,7 +431,7 @@ func TestGetExternalAuth(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -377,7 +463,7 @@ func TestGetExternalAuth(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -427,7 +513,7 @@ func TestGetExternalAuth(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -466,7 +552,7 @@ func TestPostSignUp(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -504,7 +590,7 @@ func TestPostSignUp(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -546,7 +632,7 @@ func TestPostSignUp(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -576,7 +662,7 @@ func TestPostSignUp(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -614,12 +700,29 @@ func TestPostSignUp(t *testing.T) {
 	})
 }
 
+// expectSavePasswordHistory はパスワード変更時の履歴の確認と登録を期待する
+func expectSavePasswordHistory(mock sqlmock.Sqlmock, UserId int, hashes ...string) {
+	rows := sqlmock.NewRows([]string{"user_password"})
+	for _, hash := range hashes {
+		rows.AddRow(hash)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(DB.GetPasswordHistorySyntax)).
+		WithArgs(UserId, utils.CurrentPasswordPolicy.HistoryCount-1).
+		WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta(DB.InsertPasswordHistorySyntax)).
+		WithArgs(UserId, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(DB.PrunePasswordHistorySyntax)).
+		WithArgs(UserId, utils.CurrentPasswordPolicy.HistoryCount-1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
 func TestPutSignInEdit(t *testing.T) {
 	t.Run("PutSignInEdit 登録成功 1", func(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -632,6 +735,7 @@ func TestPutSignInEdit(t *testing.T) {
 
 		// モックの準備
 		mock.ExpectBegin()
+		expectSavePasswordHistory(mock, 1)
 		mock.ExpectExec(regexp.QuoteMeta(DB.PutSignInEditSyntax)).
 			WithArgs(
 				sqlmock.AnyArg(),
@@ -652,7 +756,7 @@ func TestPutSignInEdit(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -685,7 +789,7 @@ func TestPutSignInEdit(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -699,6 +803,7 @@ func TestPutSignInEdit(t *testing.T) {
 
 		// モックの準備
 		mock.ExpectBegin()
+		expectSavePasswordHistory(mock, 1)
 		mock.ExpectExec(regexp.QuoteMeta(DB.PutSignInEditSyntax)).
 			WithArgs(
 				sqlmock.AnyArg(),
@@ -722,7 +827,7 @@ func TestPutSignInEdit(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -750,7 +855,7 @@ func TestPutSignInEdit(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -763,6 +868,7 @@ func TestPutSignInEdit(t *testing.T) {
 
 		// モックの準備
 		mock.ExpectBegin()
+		expectSavePasswordHistory(mock, 1)
 		mock.ExpectExec(regexp.QuoteMeta(DB.PutSignInEditSyntax)).
 			WithArgs(
 				sqlmock.AnyArg(),
@@ -781,35 +887,97 @@ func TestPutSignInEdit(t *testing.T) {
 		assert.Error(t, err)
 		assert.Equal(t, "トランザクションのコミットに失敗しました: transaction error", err.Error())
 	})
+	t.Run("PutSignInEdit 直近に使用したパスワード", func(t *testing.T) {
+		utilsFetcher := utils.NewUtilsFetcher(utils.JwtKeys)
+		dbFetcher, mock, err := NewSignDataFetcher("test", utilsFetcher)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		testData := RequestSignInEditData{
+			UserPassword: "Test12345!",
+		}
+		otherHash, _ := utilsFetcher.EncryptPassword("Other12345!")
+		usedHash, _ := utilsFetcher.EncryptPassword(testData.UserPassword)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetPasswordHistorySyntax)).
+			WithArgs(1, utils.CurrentPasswordPolicy.HistoryCount-1).
+			WillReturnRows(sqlmock.NewRows([]string{"user_password"}).AddRow(otherHash).AddRow(usedHash))
+		mock.ExpectRollback()
+
+		err = dbFetcher.PutSignInEdit(1, testData)
+
+		assert.ErrorIs(t, err, ErrPasswordReused)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("mock check: %s", err)
+		}
+	})
+	t.Run("PutSignInEdit 履歴を確認しない設定", func(t *testing.T) {
+		defaultPolicy := utils.CurrentPasswordPolicy
+		defer func() { utils.CurrentPasswordPolicy = defaultPolicy }()
+		utils.CurrentPasswordPolicy.HistoryCount = 0
+
+		dbFetcher, mock, err := NewSignDataFetcher("test", utils.NewUtilsFetcher(utils.JwtKeys))
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		// 履歴は確認せず、保持している履歴は全て削除する
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertPasswordHistorySyntax)).
+			WithArgs(1, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(DB.PrunePasswordHistorySyntax)).
+			WithArgs(1, 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(DB.PutSignInEditSyntax)).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err = dbFetcher.PutSignInEdit(1, RequestSignInEditData{UserPassword: "Test12345!"})
+
+		assert.NoError(t, err)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("mock check: %s", err)
+		}
+	})
 }
 
 func TestPutCheck(t *testing.T) {
+	UserId := 1
+
+	// signInRows はユーザーIDの登録情報の行データを作成する
+	signInRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{
+			"user_id", "user_email", "user_password",
+		}).AddRow(UserId, "test@exmple.com", "currentHash")
+	}
+
 	t.Run("PutCheck クエリー実行時エラー", func(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
 		}
 
-		// テスト対象のデータ
-		requestData := RequestSignInEditData{
-			UserEmail:    "test@exmple.com",
-			UserPassword: "Test12345!",
-		}
-
 		// クエリ実行時にエラーを返すようにモックを設定
-		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSignInSyntax)).
-			WithArgs(requestData.UserEmail).
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSignInByUserIdSyntax)).
+			WithArgs(UserId).
 			WillReturnError(fmt.Errorf("クエリの実行に失敗しました"))
 
 		// テストを実行
-		_, err = dbFetcher.PutCheck(requestData)
+		_, err = dbFetcher.PutCheck(UserId, RequestSignInEditData{
+			UserEmail:       "test@exmple.com",
+			UserPassword:    "Test12345!",
+			CurrentPassword: "Current12345!",
+		})
 
 		// クエリエラーが発生したことを確認
-		assert.Error(t, err)
 		assert.EqualError(t, err, "クエリー実行エラー： クエリの実行に失敗しました")
 	})
 
@@ -817,206 +985,222 @@ func TestPutCheck(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
 		}
 
-		// テスト対象のデータ
-		requestData := RequestSignInEditData{
-			UserEmail:    "test@exmple.com",
-			UserPassword: "Test12345!",
-		}
-
 		// テスト用の行データを設定
 		rows := sqlmock.NewRows([]string{
 			"user_id", "user_email", "user_password",
-		}).AddRow(
-			"test",
-			"test@exmple.com",
-			"Test12345!",
-		)
+		}).AddRow("test", "test@exmple.com", "currentHash")
 
-		// クエリ実行時にエラーを返すようにモックを設定
-		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSignInSyntax)).
-			WithArgs(requestData.UserEmail).
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSignInByUserIdSyntax)).
+			WithArgs(UserId).
 			WillReturnRows(rows)
 
 		// テストを実行
-		_, err = dbFetcher.PutCheck(requestData)
+		_, err = dbFetcher.PutCheck(UserId, RequestSignInEditData{
+			UserEmail:       "test@exmple.com",
+			CurrentPassword: "Current12345!",
+		})
 
-		// クエリエラーが発生したことを確認
+		// スキャンエラーが発生したことを確認
 		assert.Error(t, err)
 	})
 
-	t.Run("PutCheck rows.Err()にエラー発生", func(t *testing.T) {
+	t.Run("PutCheck 登録ユーザーが存在しない", func(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
 		}
 
-		// テスト対象のデータ
-		requestData := RequestSignInEditData{
-			UserEmail:    "test@exmple.com",
-			UserPassword: "Test12345!",
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSignInByUserIdSyntax)).
+			WithArgs(UserId).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "user_email", "user_password"}))
+
+		// テストを実行
+		_, err = dbFetcher.PutCheck(UserId, RequestSignInEditData{
+			UserEmail:       "test@exmple.com",
+			CurrentPassword: "Current12345!",
+		})
+
+		assert.EqualError(t, err, "登録ユーザーが存在しません")
+	})
+
+	t.Run("PutCheck 現在のパスワードが一致しない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().
+			CompareHashPassword("currentHash", "Wrong12345!").
+			Return("", fmt.Errorf("不一致"))
+
+		// テスト用のDBモックを作成
+		dbFetcher, mock, err := NewSignDataFetcher("test", mockUtilsFetcher)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
 		}
 
-		// 正常な行データを用意
-		rows := sqlmock.NewRows([]string{
-			"user_id", "user_email", "user_password",
-		}).AddRow(
-			1,
-			"test@exmple.com",
-			"Test12345!",
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSignInByUserIdSyntax)).
+			WithArgs(UserId).
+			WillReturnRows(signInRows())
+
+		// テストを実行
+		_, err = dbFetcher.PutCheck(UserId, RequestSignInEditData{
+			UserEmail:       "test@exmple.com",
+			UserPassword:    "Test12345!",
+			CurrentPassword: "Wrong12345!",
+		})
+
+		assert.ErrorIs(t, err, ErrCurrentPasswordMismatch)
+	})
+
+	t.Run("PutCheck rows.Err()にエラー発生", func(t *testing.T) {
+		// テスト用のDBモックを作成
+		dbFetcher, mock, err := NewSignDataFetcher(
+			"test",
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
 
 		// クエリ結果が返った後にエラーを発生させる
+		rows := signInRows()
 		rows.RowError(0, fmt.Errorf("forced row error"))
 
-		// クエリ実行時にエラーを返すようにモックを設定
-		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSignInSyntax)).
-			WithArgs(requestData.UserEmail).
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSignInByUserIdSyntax)).
+			WithArgs(UserId).
 			WillReturnRows(rows)
 
 		// テストを実行
-		_, err = dbFetcher.PutCheck(requestData)
+		_, err = dbFetcher.PutCheck(UserId, RequestSignInEditData{
+			UserEmail:       "test@exmple.com",
+			CurrentPassword: "Current12345!",
+		})
 
-		// クエリエラーが発生したことを確認
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "forced row error")
 	})
 
 	t.Run("PutCheck 成功 パスワード更新", func(t *testing.T) {
-		// gomock のコントローラを作成
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		// UtilsFetcher のモックを作成
+		// リクエストのメールアドレスではなく、ユーザーIDの登録情報と照合する
 		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
-
 		mockUtilsFetcher.EXPECT().
-			CompareHashPassword(gomock.Any(), gomock.Any()).
-			Return(fmt.Errorf("不一致"))
+			CompareHashPassword("currentHash", "Current12345!").
+			Return("", nil)
 
 		// テスト用のDBモックを作成
-		dbFetcher, mock, err := NewSignDataFetcher(
-			"test",
-			mockUtilsFetcher,
-		)
+		dbFetcher, mock, err := NewSignDataFetcher("test", mockUtilsFetcher)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
 		}
 
-		// テスト対象のデータ
-		requestData := RequestSignInEditData{
-			UserEmail:    "test@exmple.com",
-			UserPassword: "Test12345!",
-		}
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSignInByUserIdSyntax)).
+			WithArgs(UserId).
+			WillReturnRows(signInRows())
 
-		// 平文のパスワードをハッシュ化
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(requestData.UserPassword), bcrypt.DefaultCost)
-		if err != nil {
-			t.Fatalf("Error hashing password: %v", err)
-		}
+		// テストを実行
+		result, err := dbFetcher.PutCheck(UserId, RequestSignInEditData{
+			UserEmail:       "test@exmple.com",
+			UserPassword:    "Test12345!",
+			CurrentPassword: "Current12345!",
+		})
 
-		mockData := []SignInData{
-			{
-				UserId:       1,
-				UserEmail:    "test@exmple.com",
-				UserPassword: string(hashedPassword),
-			},
+		assert.NoError(t, err)
+		assert.Equal(t, "パスワード更新", result)
+
+		// モックが期待通りのクエリを受け取ったか確認
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("mock check: %s", err)
 		}
+	})
 
-		expectedData := "パスワード更新"
+	t.Run("PutCheck 成功 メールアドレス更新", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
 
-		// テスト用の行データを設定
-		rows := sqlmock.NewRows([]string{
-			"user_id", "user_email", "user_password",
-		})
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().
+			CompareHashPassword("currentHash", "Current12345!").
+			Return("", nil)
 
-		for _, data := range mockData {
-			rows.AddRow(
-				data.UserId,
-				data.UserEmail,
-				data.UserPassword,
-			)
+		// テスト用のDBモックを作成
+		dbFetcher, mock, err := NewSignDataFetcher("test", mockUtilsFetcher)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
 		}
 
-		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSignInSyntax)).
-			WithArgs(requestData.UserEmail).
-			WillReturnRows(rows)
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSignInByUserIdSyntax)).
+			WithArgs(UserId).
+			WillReturnRows(signInRows())
 
 		// テストを実行
-		result, err := dbFetcher.PutCheck(requestData)
+		result, err := dbFetcher.PutCheck(UserId, RequestSignInEditData{
+			UserEmail:       "new@exmple.com",
+			CurrentPassword: "Current12345!",
+		})
 
-		// エラーがないことを検証
 		assert.NoError(t, err)
-
-		t.Log("result : ", result)
-		// 取得したデータが期待値と一致することを検証
-		assert.Equal(t, expectedData, result)
+		assert.Equal(t, "メールアドレス更新", result)
 
 		// モックが期待通りのクエリを受け取ったか確認
 		if err := mock.ExpectationsWereMet(); err != nil {
 			t.Errorf("mock check: %s", err)
 		}
 	})
+}
 
-	t.Run("PutCheck 成功 メールアドレス更新", func(t *testing.T) {
+func TestDeleteSignIn(t *testing.T) {
+	purgeAt := time.Date(2025, 1, 6, 20, 0, 0, 0, time.UTC)
+
+	t.Run("DeleteSignIn 削除成功", func(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
 		}
 
-		// テスト対象のデータ
-		requestData := RequestSignInEditData{
-			UserEmail:    "test@exmple.com",
-			UserPassword: "Test12345!",
+		testData := RequestSignInDeleteData{
+			UserEmail: "text@example.com",
 		}
 
-		expectedData := "メールアドレス更新"
+		// モックの準備
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteSignInSyntax)).
+			WithArgs(
+				enum.DELETE_FLAG_ON,
+				purgeAt,
+				sqlmock.AnyArg(),
+				1,
+				"text@example.com",
+			).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
 
-		// テスト用の行データを設定
-		rows := sqlmock.NewRows([]string{
-			"user_id", "user_email", "user_password",
-		})
-
-		mock.ExpectQuery(regexp.QuoteMeta(DB.GetSignInSyntax)).
-			WithArgs(requestData.UserEmail).
-			WillReturnRows(rows)
-
-		// テストを実行
-		result, err := dbFetcher.PutCheck(requestData)
+		// InsertIncomeメソッドを呼び出し
+		err = dbFetcher.DeleteSignIn(1, testData, purgeAt)
 
 		// エラーがないことを検証
 		assert.NoError(t, err)
-
-		t.Log("result : ", result)
-		// 取得したデータが期待値と一致することを検証
-		assert.Equal(t, expectedData, result)
-
-		// モックが期待通りのクエリを受け取ったか確認
-		if err := mock.ExpectationsWereMet(); err != nil {
-			t.Errorf("mock check: %s", err)
-		}
 	})
-}
-
-func TestDeleteSignIn(t *testing.T) {
-	t.Run("DeleteSignIn 削除成功", func(t *testing.T) {
-		// テスト用のDBモックを作成
+	t.Run("DeleteSignIn 対象のユーザーが存在しないか退会済み", func(t *testing.T) {
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -1026,27 +1210,22 @@ func TestDeleteSignIn(t *testing.T) {
 			UserEmail: "text@example.com",
 		}
 
-		// モックの準備
 		mock.ExpectBegin()
 		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteSignInSyntax)).
-			WithArgs(
-				sqlmock.AnyArg(),
-				sqlmock.AnyArg(),
-			).
-			WillReturnResult(sqlmock.NewResult(1, 1))
-		mock.ExpectCommit()
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
 
-		// InsertIncomeメソッドを呼び出し
-		err = dbFetcher.DeleteSignIn(1, testData)
+		err = dbFetcher.DeleteSignIn(1, testData, purgeAt)
 
-		// エラーがないことを検証
-		assert.NoError(t, err)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "退会済み")
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 	t.Run("DeleteSignIn 失敗", func(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -1061,14 +1240,17 @@ func TestDeleteSignIn(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteSignInSyntax)).
 			WithArgs(
+				enum.DELETE_FLAG_ON,
+				purgeAt,
 				sqlmock.AnyArg(),
-				sqlmock.AnyArg(),
+				1,
+				"text@example.com",
 			).
 			WillReturnError(errors.New("delete failed")) // Execの結果にエラーを返す
 		mock.ExpectRollback() // エラー発生時にはロールバックを期待
 
 		// InsertIncomeメソッドを呼び出し
-		err = dbFetcher.DeleteSignIn(1, testData)
+		err = dbFetcher.DeleteSignIn(1, testData, purgeAt)
 
 		// エラーが発生すること
 		assert.Error(t, err)
@@ -1080,7 +1262,7 @@ func TestDeleteSignIn(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -1094,7 +1276,7 @@ func TestDeleteSignIn(t *testing.T) {
 			UserEmail: "",
 		}
 		// InsertIncomeメソッドを呼び出し
-		err = dbFetcher.DeleteSignIn(1, testData)
+		err = dbFetcher.DeleteSignIn(1, testData, purgeAt)
 
 		// エラーが発生することを検証
 		assert.Error(t, err)
@@ -1106,7 +1288,7 @@ func TestDeleteSignIn(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -1120,15 +1302,18 @@ func TestDeleteSignIn(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteSignInSyntax)).
 			WithArgs(
+				enum.DELETE_FLAG_ON,
+				purgeAt,
 				sqlmock.AnyArg(),
-				sqlmock.AnyArg(),
+				1,
+				"text@example.com",
 			).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit().WillReturnError(errors.New("transaction error"))
 		mock.ExpectRollback()
 
 		// InsertIncomeメソッドを呼び出し
-		err = dbFetcher.DeleteSignIn(1, testData)
+		err = dbFetcher.DeleteSignIn(1, testData, purgeAt)
 
 		// エラーがないことを検証
 		assert.Error(t, err)
@@ -1136,13 +1321,161 @@ func TestDeleteSignIn(t *testing.T) {
 	})
 }
 
+func TestRestoreSignIn(t *testing.T) {
+	t.Run("success RestoreSignIn", func(t *testing.T) {
+		dbFetcher, mock, err := NewSignDataFetcher(
+			"test",
+			utils.NewUtilsFetcher(utils.JwtKeys),
+		)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.RestoreSignInSyntax)).
+			WithArgs(enum.DELETE_FLAG_OFF, sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = dbFetcher.RestoreSignIn(1)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error RestoreSignIn 退会中でないか削除予定日時を過ぎている", func(t *testing.T) {
+		dbFetcher, mock, err := NewSignDataFetcher(
+			"test",
+			utils.NewUtilsFetcher(utils.JwtKeys),
+		)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.RestoreSignInSyntax)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = dbFetcher.RestoreSignIn(1)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("error RestoreSignIn クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewSignDataFetcher(
+			"test",
+			utils.NewUtilsFetcher(utils.JwtKeys),
+		)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.RestoreSignInSyntax)).
+			WillReturnError(errors.New("database error"))
+
+		err = dbFetcher.RestoreSignIn(1)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestPurgeDeactivatedUsers(t *testing.T) {
+	now := time.Date(2025, 1, 6, 20, 0, 0, 0, time.UTC)
+
+	t.Run("success PurgeDeactivatedUsers", func(t *testing.T) {
+		dbFetcher, mock, err := NewSignDataFetcher(
+			"test",
+			utils.NewUtilsFetcher(utils.JwtKeys),
+		)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		// 年収推移データを先に削除する
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.PurgeIncomeSyntax)).
+			WithArgs(now).
+			WillReturnResult(sqlmock.NewResult(0, 12))
+		mock.ExpectQuery(regexp.QuoteMeta(DB.PurgeUsersSyntax)).
+			WithArgs(now).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "user_email", "create_user"}).
+				AddRow(1, "test@example.com", "test").
+				AddRow(2, "test_2@example.com", ""))
+		mock.ExpectCommit()
+
+		result, err := dbFetcher.PurgeDeactivatedUsers(now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []DeactivatedUserData{
+			{UserId: 1, UserEmail: "test@example.com", UserName: "test"},
+			{UserId: 2, UserEmail: "test_2@example.com", UserName: ""},
+		}, result)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error PurgeDeactivatedUsers 年収推移データの削除エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewSignDataFetcher(
+			"test",
+			utils.NewUtilsFetcher(utils.JwtKeys),
+		)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.PurgeIncomeSyntax)).
+			WillReturnError(errors.New("delete failed"))
+		mock.ExpectRollback()
+
+		_, err = dbFetcher.PurgeDeactivatedUsers(now)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error PurgeDeactivatedUsers ユーザーの削除エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewSignDataFetcher(
+			"test",
+			utils.NewUtilsFetcher(utils.JwtKeys),
+		)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.PurgeIncomeSyntax)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta(DB.PurgeUsersSyntax)).
+			WillReturnError(errors.New("delete failed"))
+		mock.ExpectRollback()
+
+		_, err = dbFetcher.PurgeDeactivatedUsers(now)
+
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error PurgeDeactivatedUsers トランザクションエラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewSignDataFetcher(
+			"test",
+			utils.NewUtilsFetcher(utils.JwtKeys),
+		)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin().WillReturnError(errors.New("transaction error"))
+
+		_, err = dbFetcher.PurgeDeactivatedUsers(now)
+
+		assert.Error(t, err)
+	})
+}
+
 func TestGetUserId(t *testing.T) {
 	UserEmail := "text@example.com"
 	t.Run("GetUserId 登録ユーザーが存在しない", func(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -1165,7 +1498,7 @@ func TestGetUserId(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -1188,7 +1521,7 @@ func TestGetUserId(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -1217,19 +1550,60 @@ func TestGetUserId(t *testing.T) {
 	})
 }
 
+func TestGetUserEmail(t *testing.T) {
+	t.Run("GetUserEmail 登録ユーザーが存在しない", func(t *testing.T) {
+		dbFetcher, mock, err := NewSignDataFetcher(
+			"test",
+			utils.NewUtilsFetcher(utils.JwtKeys),
+		)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.PasswordCheckSyntax)).
+			WithArgs(1).
+			WillReturnError(sql.ErrNoRows)
+
+		userEmail, err := dbFetcher.GetUserEmail(1)
+
+		assert.Error(t, err)
+		assert.Empty(t, userEmail)
+		assert.Equal(t, err.Error(), "登録ユーザーが存在しません")
+	})
+	t.Run("GetUserEmail 登録ユーザー存在", func(t *testing.T) {
+		dbFetcher, mock, err := NewSignDataFetcher(
+			"test",
+			utils.NewUtilsFetcher(utils.JwtKeys),
+		)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		row := sqlmock.NewRows([]string{"user_email"}).AddRow("test@example.com")
+		mock.ExpectQuery(regexp.QuoteMeta(DB.PasswordCheckSyntax)).
+			WithArgs(1).
+			WillReturnRows(row)
+
+		userEmail, err := dbFetcher.GetUserEmail(1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "test@example.com", userEmail)
+	})
+}
+
 func TestNewPasswordUpdate(t *testing.T) {
 	Data := RequestNewPasswordUpdateData{
-		TokenId:         "b2781af7-794a-1871-9865-bdc3c19291ff1",
+		TokenId:         "Hk3p4Xq0m1b2C3d4E5f6G7h8I9j0K1l2M3n4O5p6Q7r",
 		NewUserPassword: "Test12345!",
 		ConfirmPassword: "Test12345!",
 	}
-	UserId := Data.TokenId[utils.Uuid:]
+	UserId := 1
 
 	t.Run("NewPasswordUpdate 登録ユーザーが存在しない", func(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -1241,7 +1615,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 			WillReturnError(sql.ErrNoRows)
 
 		// テスト実行
-		userEmail, err := dbFetcher.NewPasswordUpdate(Data)
+		userEmail, err := dbFetcher.NewPasswordUpdate(UserId, Data)
 
 		// 検証
 		assert.Error(t, err)
@@ -1252,7 +1626,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -1264,7 +1638,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 			WillReturnError(fmt.Errorf("dbエラー"))
 
 		// テスト実行
-		userEmail, err := dbFetcher.NewPasswordUpdate(Data)
+		userEmail, err := dbFetcher.NewPasswordUpdate(UserId, Data)
 
 		// 検証
 		assert.Error(t, err)
@@ -1280,7 +1654,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -1300,7 +1674,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 		mock.ExpectBegin().WillReturnError(errors.New("transaction error"))
 
 		// テスト実行
-		userEmail, err := dbFetcher.NewPasswordUpdate(Data)
+		userEmail, err := dbFetcher.NewPasswordUpdate(UserId, Data)
 
 		// 検証
 		assert.Error(t, err)
@@ -1339,6 +1713,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 
 		// トランザクション
 		mock.ExpectBegin()
+		expectSavePasswordHistory(mock, UserId)
 		mock.ExpectExec(regexp.QuoteMeta(DB.PutPasswordSyntax)).
 			WithArgs(
 				sqlmock.AnyArg(),
@@ -1350,7 +1725,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 		mock.ExpectRollback()
 
 		// テスト実行
-		userEmail, err := dbFetcher.NewPasswordUpdate(Data)
+		userEmail, err := dbFetcher.NewPasswordUpdate(UserId, Data)
 
 		// 検証
 		assert.Error(t, err)
@@ -1365,7 +1740,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 		// テスト用のDBモックを作成
 		dbFetcher, mock, err := NewSignDataFetcher(
 			"test",
-			utils.NewUtilsFetcher(utils.JwtSecret),
+			utils.NewUtilsFetcher(utils.JwtKeys),
 		)
 		if err != nil {
 			t.Fatalf("Error creating DB mock: %v", err)
@@ -1398,7 +1773,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 		Data.ConfirmPassword = "Test1234567!"
 
 		// テスト実行
-		userEmail, err := dbFetcher.NewPasswordUpdate(Data)
+		userEmail, err := dbFetcher.NewPasswordUpdate(UserId, Data)
 
 		// 検証
 		assert.Error(t, err)
@@ -1438,6 +1813,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 
 		// トランザクション
 		mock.ExpectBegin()
+		expectSavePasswordHistory(mock, UserId)
 		mock.ExpectExec(regexp.QuoteMeta(DB.PutPasswordSyntax)).
 			WithArgs(
 				sqlmock.AnyArg(),
@@ -1448,7 +1824,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 		mock.ExpectRollback() // エラー発生時にはロールバックを期待
 
 		// テスト実行
-		userEmail, err := dbFetcher.NewPasswordUpdate(Data)
+		userEmail, err := dbFetcher.NewPasswordUpdate(UserId, Data)
 
 		// 検証
 		assert.Error(t, err)
@@ -1487,6 +1863,7 @@ func TestNewPasswordUpdate(t *testing.T) {
 
 		// トランザクション
 		mock.ExpectBegin()
+		expectSavePasswordHistory(mock, UserId)
 		mock.ExpectExec(regexp.QuoteMeta(DB.PutPasswordSyntax)).
 			WithArgs(
 				sqlmock.AnyArg(),
@@ -1497,11 +1874,247 @@ func TestNewPasswordUpdate(t *testing.T) {
 		mock.ExpectCommit()
 
 		// テスト実行
-		userEmail, err := dbFetcher.NewPasswordUpdate(Data)
+		userEmail, err := dbFetcher.NewPasswordUpdate(UserId, Data)
 
 		// 検証
 		assert.NoError(t, err)
 		assert.Equal(t, "test@exmaple.com", userEmail)
 		assert.Nil(t, err)
 	})
+	t.Run("NewPasswordUpdate 直近に使用したパスワード", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().
+			CompareHashPassword("currentHash", Data.NewUserPassword).
+			Return("", nil)
+
+		dbFetcher, mock, err := NewSignDataFetcher("test", mockUtilsFetcher)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.PasswordCheckSyntax)).
+			WithArgs(UserId).
+			WillReturnRows(sqlmock.NewRows([]string{"user_email"}).AddRow("test@exmaple.com"))
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetPasswordHistorySyntax)).
+			WithArgs(UserId, utils.CurrentPasswordPolicy.HistoryCount-1).
+			WillReturnRows(sqlmock.NewRows([]string{"user_password"}).AddRow("currentHash"))
+		mock.ExpectRollback()
+
+		userEmail, err := dbFetcher.NewPasswordUpdate(UserId, Data)
+
+		assert.ErrorIs(t, err, ErrPasswordReused)
+		assert.Equal(t, "", userEmail)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("mock check: %s", err)
+		}
+	})
+}
+
+func TestCheckPasswordReuse(t *testing.T) {
+	UserId := 1
+
+	t.Run("CheckPasswordReuse 直近に使用したパスワード", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().
+			CompareHashPassword("currentHash", "Test12345!").
+			Return("", fmt.Errorf("パスワードが一致しません"))
+		mockUtilsFetcher.EXPECT().
+			CompareHashPassword("previousHash", "Test12345!").
+			Return("", nil)
+
+		dbFetcher, mock, err := NewSignDataFetcher("test", mockUtilsFetcher)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetPasswordHistorySyntax)).
+			WithArgs(UserId, utils.CurrentPasswordPolicy.HistoryCount-1).
+			WillReturnRows(sqlmock.NewRows([]string{"user_password"}).AddRow("currentHash").AddRow("previousHash"))
+
+		err = dbFetcher.CheckPasswordReuse(UserId, "Test12345!")
+
+		assert.ErrorIs(t, err, ErrPasswordReused)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("mock check: %s", err)
+		}
+	})
+
+	t.Run("CheckPasswordReuse 使用したことのないパスワード", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().
+			CompareHashPassword("currentHash", "Test12345!").
+			Return("", fmt.Errorf("パスワードが一致しません"))
+
+		dbFetcher, mock, err := NewSignDataFetcher("test", mockUtilsFetcher)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetPasswordHistorySyntax)).
+			WithArgs(UserId, utils.CurrentPasswordPolicy.HistoryCount-1).
+			WillReturnRows(sqlmock.NewRows([]string{"user_password"}).AddRow("currentHash"))
+
+		err = dbFetcher.CheckPasswordReuse(UserId, "Test12345!")
+
+		assert.NoError(t, err)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("mock check: %s", err)
+		}
+	})
+}
+
+func TestInvalidatePassword(t *testing.T) {
+	UserId := 1
+
+	t.Run("InvalidatePassword 履歴に登録せずにパスワードを置き換える", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().EncryptPassword("unusable").Return("$argon2id$unusableHash", nil)
+
+		dbFetcher, mock, err := NewSignDataFetcher("test", mockUtilsFetcher)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		// パスワードの履歴の取得・登録は行わない
+		mock.ExpectExec(regexp.QuoteMeta(DB.PutPasswordSyntax)).
+			WithArgs("$argon2id$unusableHash", sqlmock.AnyArg(), UserId).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = dbFetcher.InvalidatePassword(UserId, "unusable")
+
+		assert.NoError(t, err)
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("mock check: %s", err)
+		}
+	})
+
+	t.Run("InvalidatePassword クエリー実行エラー", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockUtilsFetcher := mock_utils.NewMockUtilsFetcher(ctrl)
+		mockUtilsFetcher.EXPECT().EncryptPassword("unusable").Return("$argon2id$unusableHash", nil)
+
+		dbFetcher, mock, err := NewSignDataFetcher("test", mockUtilsFetcher)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.PutPasswordSyntax)).
+			WithArgs("$argon2id$unusableHash", sqlmock.AnyArg(), UserId).
+			WillReturnError(fmt.Errorf("DBエラー"))
+
+		err = dbFetcher.InvalidatePassword(UserId, "unusable")
+
+		assert.EqualError(t, err, "クエリー実行エラー： DBエラー")
+	})
+}
+
+func TestGetUserAccount(t *testing.T) {
+	t.Run("GetUserAccount 登録ユーザーが存在しない", func(t *testing.T) {
+		dbFetcher, mock, err := NewSignDataFetcher(
+			"test",
+			utils.NewUtilsFetcher(utils.JwtKeys),
+		)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetUserAccountSyntax)).
+			WithArgs(1).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err = dbFetcher.GetUserAccount(1)
+
+		assert.EqualError(t, err, "登録ユーザーが存在しません")
+	})
+
+	t.Run("GetUserAccount 外部認証のユーザー", func(t *testing.T) {
+		dbFetcher, mock, err := NewSignDataFetcher(
+			"test",
+			utils.NewUtilsFetcher(utils.JwtKeys),
+		)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		row := sqlmock.NewRows([]string{"user_id", "user_email", "password_account"}).
+			AddRow(1, "test@example.com", false)
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetUserAccountSyntax)).
+			WithArgs(1).
+			WillReturnRows(row)
+
+		result, err := dbFetcher.GetUserAccount(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, UserAccountData{UserId: 1, UserEmail: "test@example.com"}, result)
+	})
+}
+
+func TestChangeUserEmail(t *testing.T) {
+	t.Run("ChangeUserEmail 成功", func(t *testing.T) {
+		dbFetcher, mock, err := NewSignDataFetcher(
+			"test",
+			utils.NewUtilsFetcher(utils.JwtKeys),
+		)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.ChangeUserEmailSyntax)).
+			WithArgs("new@example.com", sqlmock.AnyArg(), 1, "old@example.com").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = dbFetcher.ChangeUserEmail(1, "old@example.com", "new@example.com")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("ChangeUserEmail 変更済み又は登録済みのメールアドレス", func(t *testing.T) {
+		dbFetcher, mock, err := NewSignDataFetcher(
+			"test",
+			utils.NewUtilsFetcher(utils.JwtKeys),
+		)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.ChangeUserEmailSyntax)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = dbFetcher.ChangeUserEmail(1, "old@example.com", "new@example.com")
+
+		assert.EqualError(t, err, "メールアドレスを変更できませんでした。既に変更済みか、変更先のメールアドレスが登録済みです。")
+	})
+
+	t.Run("ChangeUserEmail クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewSignDataFetcher(
+			"test",
+			utils.NewUtilsFetcher(utils.JwtKeys),
+		)
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.ChangeUserEmailSyntax)).
+			WillReturnError(fmt.Errorf("クエリの実行に失敗しました"))
+
+		err = dbFetcher.ChangeUserEmail(1, "old@example.com", "new@example.com")
+
+		assert.EqualError(t, err, "クエリー実行エラー： クエリの実行に失敗しました")
+	})
 }
@@ -0,0 +1,246 @@
+// models/totp.go
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"server/DB"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/lib/pq"
+)
+
+type (
+	TotpFetcher interface {
+		GetTotp(UserId int) (TotpData, error)
+		UpsertTotpSecret(UserId int, Secret string) error
+		EnableTotp(UserId int, RecoveryCodeHashes []string) error
+		DisableTotp(UserId int) error
+		UseRecoveryCode(UserId int, CodeHash string) error
+	}
+
+	TotpData struct {
+		UserId    int
+		UserEmail string
+		// パスワードで登録したユーザーの場合はtrue(外部認証のユーザーは二段階認証の対象外)
+		PasswordAccount bool
+		// 登録開始前の場合は空文字
+		Secret  string
+		Enabled bool
+	}
+
+	TotpDataFetcher struct{ db *sql.DB }
+)
+
+func NewTotpDataFetcher(dataSourceName string) (*TotpDataFetcher, sqlmock.Sqlmock, error) {
+	if dataSourceName == "test" {
+		db, mock, err := sqlmock.New()
+		return &TotpDataFetcher{db: db}, mock, err
+	} else {
+		// test実行時に以下のカバレッジは無視する
+		db, err := sql.Open("postgres", dataSourceName)
+		if err != nil {
+			log.Printf("sql.Open error %s", err)
+		}
+		return &TotpDataFetcher{db: db}, nil, nil
+	}
+}
+
+// GetTotp はユーザーの二段階認証の設定を返す
+//
+// 引数:
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: 二段階認証の設定(未登録の場合は Secret が空文字)
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (tf *TotpDataFetcher) GetTotp(UserId int) (TotpData, error) {
+	var data TotpData
+
+	defer tf.db.Close()
+
+	err := tf.db.QueryRow(DB.GetTotpSyntax, UserId).Scan(
+		&data.UserId,
+		&data.UserEmail,
+		&data.PasswordAccount,
+		&data.Secret,
+		&data.Enabled,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return data, errors.New("対象のユーザーが存在しません。")
+	} else if err != nil {
+		return data, fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+
+	return data, nil
+}
+
+// UpsertTotpSecret は二段階認証の登録を開始し、シークレットを保存する
+// 登録途中の場合はシークレットを作り直し、有効化済みの場合はエラーとする
+//
+// 引数:
+//   - UserId: ユーザーID
+//   - Secret: Base32のシークレット
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (tf *TotpDataFetcher) UpsertTotpSecret(UserId int, Secret string) error {
+
+	defer tf.db.Close()
+
+	result, err := tf.db.Exec(DB.UpsertTotpSecretSyntax, UserId, Secret, time.Now())
+	if err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return errors.New("二段階認証は既に有効です。")
+	}
+
+	return nil
+}
+
+// EnableTotp は二段階認証を有効にし、リカバリーコードを登録し直す
+//
+// 引数:
+//   - UserId: ユーザーID
+//   - RecoveryCodeHashes: ハッシュ化したリカバリーコード
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (tf *TotpDataFetcher) EnableTotp(UserId int, RecoveryCodeHashes []string) error {
+
+	var err error
+
+	// データベースのクローズをdeferで最初に宣言
+	defer tf.db.Close()
+
+	// トランザクションを開始
+	tx, err := tf.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %v", err)
+	}
+
+	// ロールバックをデフォルトに設定
+	rollback := true
+	defer func() {
+		if rollback {
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.Exec(DB.EnableTotpSyntax, time.Now(), UserId)
+	if err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return errors.New("対象の二段階認証の登録が存在しません。")
+	}
+
+	if _, err = tx.Exec(DB.DeleteRecoveryCodesSyntax, UserId); err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	for _, codeHash := range RecoveryCodeHashes {
+		if _, err = tx.Exec(DB.InsertRecoveryCodeSyntax, UserId, codeHash); err != nil {
+			return fmt.Errorf("クエリー実行エラー： %v", err)
+		}
+	}
+
+	// コミット処理
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %v", err)
+	}
+
+	rollback = false
+
+	return nil
+}
+
+// DisableTotp は二段階認証を無効にし、リカバリーコードを削除する
+//
+// 引数:
+//   - UserId: ユーザーID
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (tf *TotpDataFetcher) DisableTotp(UserId int) error {
+
+	var err error
+
+	// データベースのクローズをdeferで最初に宣言
+	defer tf.db.Close()
+
+	// トランザクションを開始
+	tx, err := tf.db.Begin()
+	if err != nil {
+		return fmt.Errorf("トランザクションの開始に失敗しました: %v", err)
+	}
+
+	// ロールバックをデフォルトに設定
+	rollback := true
+	defer func() {
+		if rollback {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.Exec(DB.DeleteRecoveryCodesSyntax, UserId); err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	result, err := tx.Exec(DB.DeleteTotpSyntax, UserId)
+	if err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return errors.New("対象の二段階認証が存在しません。")
+	}
+
+	// コミット処理
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("トランザクションのコミットに失敗しました: %v", err)
+	}
+
+	rollback = false
+
+	return nil
+}
+
+// UseRecoveryCode は未使用のリカバリーコードを使用済みにする
+//
+// 引数:
+//   - UserId: ユーザーID
+//   - CodeHash: ハッシュ化したリカバリーコード
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(一致する未使用のコードがない場合はエラー)
+//
+
+func (tf *TotpDataFetcher) UseRecoveryCode(UserId int, CodeHash string) error {
+
+	defer tf.db.Close()
+
+	result, err := tf.db.Exec(DB.UseRecoveryCodeSyntax, time.Now(), UserId, CodeHash)
+	if err != nil {
+		return fmt.Errorf("クエリー実行エラー： %v", err)
+	}
+	if count, _ := result.RowsAffected(); count == 0 {
+		return errors.New("対象のリカバリーコードが存在しません。")
+	}
+
+	return nil
+}
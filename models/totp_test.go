@@ -0,0 +1,255 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"server/DB"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var totpColumns = []string{"user_id", "user_email", "password_account", "secret", "enabled"}
+
+func TestNewTotpDataFetcher(t *testing.T) {
+	// 通常のテストケース
+	dbFetcher, mock, err := NewTotpDataFetcher("test")
+	assert.NotNil(t, dbFetcher)
+	assert.NotNil(t, mock)
+	assert.NoError(t, err)
+
+	// カバレッジを通すためのテストケース
+	dbFetcher, _, err = NewTotpDataFetcher("")
+	assert.NotNil(t, dbFetcher)
+	assert.NoError(t, err)
+}
+
+func TestGetTotp(t *testing.T) {
+	t.Run("success GetTotp", func(t *testing.T) {
+		dbFetcher, mock, err := NewTotpDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		rows := sqlmock.NewRows(totpColumns).
+			AddRow(1, "test@example.com", true, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", true)
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetTotpSyntax)).
+			WithArgs(1).
+			WillReturnRows(rows)
+
+		result, err := dbFetcher.GetTotp(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, TotpData{
+			UserId:          1,
+			UserEmail:       "test@example.com",
+			PasswordAccount: true,
+			Secret:          "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+			Enabled:         true,
+		}, result)
+	})
+
+	t.Run("error GetTotp ユーザーが存在しない", func(t *testing.T) {
+		dbFetcher, mock, err := NewTotpDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetTotpSyntax)).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows(totpColumns))
+
+		_, err = dbFetcher.GetTotp(1)
+
+		assert.EqualError(t, err, "対象のユーザーが存在しません。")
+	})
+
+	t.Run("error GetTotp クエリー実行時エラー", func(t *testing.T) {
+		dbFetcher, mock, err := NewTotpDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta(DB.GetTotpSyntax)).
+			WillReturnError(fmt.Errorf("クエリの実行に失敗しました"))
+
+		_, err = dbFetcher.GetTotp(1)
+
+		assert.EqualError(t, err, "クエリー実行エラー： クエリの実行に失敗しました")
+	})
+}
+
+func TestUpsertTotpSecret(t *testing.T) {
+	t.Run("success UpsertTotpSecret", func(t *testing.T) {
+		dbFetcher, mock, err := NewTotpDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.UpsertTotpSecretSyntax)).
+			WithArgs(1, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = dbFetcher.UpsertTotpSecret(1, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error UpsertTotpSecret 有効化済み", func(t *testing.T) {
+		dbFetcher, mock, err := NewTotpDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.UpsertTotpSecretSyntax)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = dbFetcher.UpsertTotpSecret(1, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+
+		assert.EqualError(t, err, "二段階認証は既に有効です。")
+	})
+}
+
+func TestEnableTotp(t *testing.T) {
+	t.Run("success EnableTotp", func(t *testing.T) {
+		dbFetcher, mock, err := NewTotpDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.EnableTotpSyntax)).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteRecoveryCodesSyntax)).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertRecoveryCodeSyntax)).
+			WithArgs(1, "hash1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertRecoveryCodeSyntax)).
+			WithArgs(1, "hash2").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err = dbFetcher.EnableTotp(1, []string{"hash1", "hash2"})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error EnableTotp 登録開始前", func(t *testing.T) {
+		dbFetcher, mock, err := NewTotpDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.EnableTotpSyntax)).
+			WithArgs(sqlmock.AnyArg(), 1).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err = dbFetcher.EnableTotp(1, []string{"hash1"})
+
+		assert.EqualError(t, err, "対象の二段階認証の登録が存在しません。")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error EnableTotp リカバリーコードの登録に失敗", func(t *testing.T) {
+		dbFetcher, mock, err := NewTotpDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.EnableTotpSyntax)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteRecoveryCodesSyntax)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(DB.InsertRecoveryCodeSyntax)).
+			WillReturnError(fmt.Errorf("クエリの実行に失敗しました"))
+		mock.ExpectRollback()
+
+		err = dbFetcher.EnableTotp(1, []string{"hash1"})
+
+		assert.EqualError(t, err, "クエリー実行エラー： クエリの実行に失敗しました")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDisableTotp(t *testing.T) {
+	t.Run("success DisableTotp", func(t *testing.T) {
+		dbFetcher, mock, err := NewTotpDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteRecoveryCodesSyntax)).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 10))
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteTotpSyntax)).
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err = dbFetcher.DisableTotp(1)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("error DisableTotp 対象なし", func(t *testing.T) {
+		dbFetcher, mock, err := NewTotpDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteRecoveryCodesSyntax)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(DB.DeleteTotpSyntax)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err = dbFetcher.DisableTotp(1)
+
+		assert.EqualError(t, err, "対象の二段階認証が存在しません。")
+	})
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	t.Run("success UseRecoveryCode", func(t *testing.T) {
+		dbFetcher, mock, err := NewTotpDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.UseRecoveryCodeSyntax)).
+			WithArgs(sqlmock.AnyArg(), 1, "hash1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err = dbFetcher.UseRecoveryCode(1, "hash1")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("error UseRecoveryCode 使用済み又は存在しない", func(t *testing.T) {
+		dbFetcher, mock, err := NewTotpDataFetcher("test")
+		if err != nil {
+			t.Fatalf("Error creating DB mock: %v", err)
+		}
+
+		mock.ExpectExec(regexp.QuoteMeta(DB.UseRecoveryCodeSyntax)).
+			WithArgs(sqlmock.AnyArg(), 1, "hash1").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err = dbFetcher.UseRecoveryCode(1, "hash1")
+
+		assert.EqualError(t, err, "対象のリカバリーコードが存在しません。")
+	})
+}
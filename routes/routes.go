@@ -4,6 +4,7 @@ import (
 	"server/common"
 	"server/config"
 	"server/controllers"
+	"server/enum"
 	"server/middleware"
 	"server/templates"
 	"server/utils"
@@ -15,55 +16,111 @@ func SetupRoutes(r *gin.Engine) {
 
 	// APiインターフェイスのインスタンス定義
 	var signAPI controllers.SignDataFetcher = controllers.NewSignDataFetcher(
-		utils.NewUtilsFetcher(utils.JwtSecret),
+		utils.NewUtilsFetcher(utils.JwtKeys),
 		common.NewCommonFetcher(),
 		templates.NewEmailTemplateManager(),
 		config.NewRedisManager(),
+		utils.NewAttemptLimiter(config.NewRedisManager()),
 	)
-	var googleApi controllers.GoogleService = controllers.NewGoogleService(
-		config.NewGoogleManager(),
+	var oidcApi controllers.OIDCService = controllers.NewOIDCService(
+		utils.OIDCProviders,
 		templates.NewEmailTemplateManager(),
-		utils.NewUtilsFetcher(utils.JwtSecret),
-	)
-	var lineApi controllers.LineService = controllers.NewLineService(
-		templates.NewEmailTemplateManager(),
-		utils.NewUtilsFetcher(utils.JwtSecret),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+		config.NewRedisManager(),
 	)
 	var priceAPI controllers.PriceManagementFetcher = controllers.NewPriceManagementFetcher(
 		common.NewCommonFetcher(),
 	)
+	var investmentAPI controllers.InvestmentSimulationFetcher = controllers.NewInvestmentSimulationFetcher(
+		common.NewCommonFetcher(),
+	)
+	var takeHomeAPI controllers.TakeHomeSimulationFetcher = controllers.NewTakeHomeSimulationFetcher(
+		common.NewCommonFetcher(),
+	)
 	var incomeAPI controllers.IncomeDataFetcher = controllers.NewIncomeDataFetcher(
 		common.NewCommonFetcher(),
 	)
+	var loanAPI controllers.LoanManagementFetcher = controllers.NewLoanManagementFetcher(
+		common.NewCommonFetcher(),
+	)
+	var scenarioAPI controllers.PriceScenarioManagementFetcher = controllers.NewPriceScenarioManagementFetcher(
+		common.NewCommonFetcher(),
+	)
+	var savingsGoalAPI controllers.SavingsGoalManagementFetcher = controllers.NewSavingsGoalManagementFetcher(
+		common.NewCommonFetcher(),
+		priceAPI,
+	)
+	var jwksAPI controllers.JwksFetcher = controllers.NewJwksFetcher(utils.JwtKeys)
+	var totpAPI controllers.TotpManagementFetcher = controllers.NewTotpManagementFetcher(
+		common.NewCommonFetcher(),
+		config.NewRedisManager(),
+		utils.NewAttemptLimiter(config.NewRedisManager()),
+	)
+	var identityAPI controllers.IdentityManagementFetcher = controllers.NewIdentityManagementFetcher(
+		common.NewCommonFetcher(),
+	)
+	var sessionAPI controllers.SessionManagementFetcher = controllers.NewSessionManagementFetcher(
+		common.NewCommonFetcher(),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+	)
+	var dataExportAPI controllers.DataExportManagementFetcher = controllers.NewDataExportManagementFetcher(
+		common.NewCommonFetcher(),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+		templates.NewEmailTemplateManager(),
+	)
+	var adminAPI controllers.AdminManagementFetcher = controllers.NewAdminManagementFetcher(
+		common.NewCommonFetcher(),
+		utils.NewUtilsFetcher(utils.JwtKeys),
+		templates.NewEmailTemplateManager(),
+		config.NewRedisManager(),
+	)
+
+	// トークン検証用の公開鍵
+	r.GET("/.well-known/jwks.json", jwksAPI.GetJwksApi)
 
 	// ルートの設定
 	Routes := r.Group("/api")
 	{
 		Routes.POST("/signin", signAPI.PostSignInApi)
+		// 二段階認証が有効なユーザーのサインイン完了
+		Routes.POST("/signin_totp", signAPI.PostSignInTotpApi)
+		// パスワードなしのサインイン(リンクの送信とメールのリンクからのサインイン)
+		Routes.POST("/magic_link", signAPI.MagicLinkRequestApi)
+		Routes.POST("/magic_link_signin", signAPI.MagicLinkSignInApi)
 		Routes.GET("/refresh_token", signAPI.GetRefreshTokenApi)
 		Routes.POST("/temporary_signup", signAPI.TemporaryPostSignUpApi)
 		Routes.GET("/retry_auth_email", signAPI.RetryAuthEmail)
 		Routes.POST("/signup", signAPI.PostSignUpApi)
-		Routes.PUT("/signin_edit/:user_id", signAPI.PutSignInEditApi)
 		Routes.DELETE("/signin_delete/:user_id", signAPI.DeleteSignInApi)
 		Routes.GET("/signout", signAPI.SignOutApi)
 		Routes.GET("/register_email_check_notice", signAPI.RegisterEmailCheckNotice)
 		// tokenIdからUserIdを取得していて、トークン漏洩防止のためパラメータにUserIdは含めない
 		Routes.PUT("/new_password_update", signAPI.NewPasswordUpdate)
-		// google認証
-		Routes.GET("/google/signin/callback", googleApi.GoogleSignInCallback)
-		Routes.GET("/google/signup/callback", googleApi.GoogleSignUpCallback)
-		Routes.GET("/google/delete/callback", googleApi.GoogleDeleteCallback)
-		// line認証
-		Routes.GET("/line/signin/callback", lineApi.LineSignInCallback)
-		Routes.GET("/line/signup/callback", lineApi.LineSignUpCallback)
-		Routes.GET("/line/delete/callback", lineApi.LineDeleteCallback)
+		// サインイン失敗によるアカウントロックの解除(メールのリンクから)
+		Routes.GET("/account_unlock", signAPI.AccountUnlockApi)
+		// メールアドレス変更の確認・取り消し(メールのリンクから)
+		Routes.GET("/email_change_confirm", signAPI.EmailChangeConfirmApi)
+		Routes.GET("/email_change_cancel", signAPI.EmailChangeCancelApi)
+		// 退会したアカウントの復元(メールのリンクから)
+		Routes.GET("/account_restore", signAPI.AccountRestoreApi)
+		// 外部認証(認可画面へのリダイレクトと認可コードの受け取り)
+		// :provider は設定で登録した外部認証の名称(google・line など)
+		Routes.GET("/auth/:provider/signin", oidcApi.OIDCSignInAuth)
+		Routes.GET("/auth/:provider/signup", oidcApi.OIDCSignUpAuth)
+		Routes.GET("/auth/:provider/delete", oidcApi.OIDCDeleteAuth)
+		Routes.GET("/auth/:provider/signin/callback", oidcApi.OIDCSignInCallback)
+		Routes.GET("/auth/:provider/signup/callback", oidcApi.OIDCSignUpCallback)
+		Routes.GET("/auth/:provider/delete/callback", oidcApi.OIDCDeleteCallback)
 
 		// 認証が必要なルートにミドルウェアを追加
 		authRoutes := Routes.Group("/")
-		authRoutes.Use(middleware.JWTAuthMiddleware(utils.UtilsDataFetcher{}))
+		authRoutes.Use(middleware.JWTAuthMiddleware(utils.NewUtilsFetcher(utils.JwtKeys)))
 		{
+			// サインイン中のユーザー本人のみ変更できる
+			authRoutes.PUT("/signin_edit/:user_id", signAPI.PutSignInEditApi)
 			authRoutes.GET("/price", priceAPI.GetPriceInfoApi)
+			authRoutes.GET("/investment_simulation", investmentAPI.GetInvestmentSimulationApi)
+			authRoutes.GET("/take_home_simulation", takeHomeAPI.GetTakeHomeSimulationApi)
 			authRoutes.GET("/income_data", incomeAPI.GetIncomeDataInRangeApi)
 			authRoutes.GET("/range_date", incomeAPI.GetDateRangeApi)
 			authRoutes.GET("/years_income_date", incomeAPI.GetYearIncomeAndDeductionApi)
@@ -71,7 +128,66 @@ func SetupRoutes(r *gin.Engine) {
 			// データが複数件の場合があるため、urlにキーは付与しない
 			authRoutes.PUT("/income_update", incomeAPI.UpdateIncomeDataApi)
 			authRoutes.POST("/income_delete", incomeAPI.DeleteIncomeDataApi)
+			// ローン管理
+			authRoutes.GET("/loan", loanAPI.GetLoanApi)
+			authRoutes.POST("/loan_create", loanAPI.InsertLoanApi)
+			authRoutes.PUT("/loan_update", loanAPI.UpdateLoanApi)
+			authRoutes.POST("/loan_delete", loanAPI.DeleteLoanApi)
+			authRoutes.POST("/loan_schedule", loanAPI.GetLoanScheduleApi)
+			// 収支シナリオ
+			authRoutes.GET("/price_scenario", scenarioAPI.GetPriceScenarioApi)
+			authRoutes.POST("/price_scenario_create", scenarioAPI.InsertPriceScenarioApi)
+			authRoutes.PUT("/price_scenario_activate", scenarioAPI.ActivatePriceScenarioApi)
+			authRoutes.POST("/price_scenario_delete", scenarioAPI.DeletePriceScenarioApi)
+			// 貯金目標
+			authRoutes.GET("/savings_goal", savingsGoalAPI.GetSavingsGoalApi)
+			authRoutes.POST("/savings_goal_create", savingsGoalAPI.InsertSavingsGoalApi)
+			authRoutes.PUT("/savings_goal_update", savingsGoalAPI.UpdateSavingsGoalApi)
+			authRoutes.POST("/savings_goal_delete", savingsGoalAPI.DeleteSavingsGoalApi)
+			authRoutes.POST("/savings_goal_snapshot", savingsGoalAPI.RecordSavingsGoalSnapshotApi)
+			authRoutes.GET("/savings_goal_snapshot", savingsGoalAPI.GetSavingsGoalSnapshotApi)
+			// サインイン中のセッション
+			authRoutes.GET("/session", sessionAPI.GetSessionApi)
+			authRoutes.POST("/session_revoke", sessionAPI.RevokeSessionApi)
+			authRoutes.POST("/session_revoke_others", sessionAPI.RevokeOtherSessionsApi)
+			// 二段階認証(TOTP)
+			authRoutes.POST("/totp_enroll", totpAPI.TotpEnrollApi)
+			authRoutes.POST("/totp_verify", totpAPI.TotpVerifyApi)
+			authRoutes.POST("/totp_disable", totpAPI.TotpDisableApi)
+			// メールアドレス変更
+			authRoutes.POST("/email_change", signAPI.EmailChangeRequestApi)
+			// 外部認証の連携
+			authRoutes.GET("/identity", identityAPI.GetIdentityApi)
+			authRoutes.POST("/identity_unlink", identityAPI.UnlinkIdentityApi)
+			authRoutes.GET("/auth/:provider/link", oidcApi.OIDCLinkAuth)
+			authRoutes.GET("/auth/:provider/link/callback", oidcApi.OIDCLinkCallback)
+			// 個人データのエクスポート
+			authRoutes.POST("/data_export", dataExportAPI.RequestDataExportApi)
+			authRoutes.GET("/data_export_download", dataExportAPI.DownloadDataExportApi)
+			authRoutes.POST("/data_import", dataExportAPI.ImportDataApi)
 			// 他のエンドポイントのルーティングもここで設定
 		}
+
+		// 管理者用API
+		// サポートは参照のみ、更新は管理者のみ許可する
+		adminRoutes := Routes.Group("/admin")
+		adminRoutes.Use(
+			middleware.JWTAuthMiddleware(utils.NewUtilsFetcher(utils.JwtKeys)),
+			middleware.RoleMiddleware(enum.ROLE_ADMIN, enum.ROLE_SUPPORT),
+		)
+		{
+			adminRoutes.GET("/users", adminAPI.GetAdminUsersApi)
+			adminRoutes.GET("/stats/sign_up", adminAPI.GetSignUpStatsApi)
+			adminRoutes.GET("/audit_log", adminAPI.GetAuditLogApi)
+
+			adminOnlyRoutes := adminRoutes.Group("/")
+			adminOnlyRoutes.Use(middleware.RoleMiddleware(enum.ROLE_ADMIN))
+			{
+				adminOnlyRoutes.PUT("/users/:user_id/disable", adminAPI.DisableUserApi)
+				adminOnlyRoutes.PUT("/users/:user_id/enable", adminAPI.EnableUserApi)
+				adminOnlyRoutes.POST("/users/:user_id/password_reset", adminAPI.ForcePasswordResetApi)
+				adminOnlyRoutes.POST("/account_unlock", signAPI.AdminAccountUnlockApi)
+			}
+		}
 	}
 }
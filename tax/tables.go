@@ -0,0 +1,174 @@
+// tax/tables.go
+package tax
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+)
+
+type (
+	// RateTable は年度毎の社会保険料率・税率の表
+	// 料率・税率は全て百分率(%)で保持する
+	RateTable struct {
+		Year                  int                     `json:"year"`
+		HealthInsurance       HealthInsuranceTable    `json:"health_insurance"`
+		Pension               PensionTable            `json:"pension"`
+		EmploymentInsurance   EmploymentInsuranceRate `json:"employment_insurance"`
+		StandardMonthlyGrades []StandardMonthlyGrade  `json:"standard_monthly_grades"`
+		Withholding           WithholdingTable        `json:"withholding"`
+		EmploymentDeduction   []WithholdingDeduction  `json:"employment_income_deduction"`
+		IncomeTax             IncomeTaxTable          `json:"income_tax"`
+		ResidentTax           ResidentTaxTable        `json:"resident_tax"`
+	}
+
+	// HealthInsuranceTable は協会けんぽの都道府県別の健康保険料率
+	HealthInsuranceTable struct {
+		Rates            map[string]float64 `json:"rates"`
+		NursingRate      float64            `json:"nursing_rate"`
+		NursingMinAge    int                `json:"nursing_min_age"`
+		NursingMaxAge    int                `json:"nursing_max_age"`
+		BonusAnnualLimit int                `json:"bonus_annual_limit"`
+	}
+
+	// PensionTable は厚生年金保険料率と標準報酬月額の上下限
+	PensionTable struct {
+		Rate        float64 `json:"rate"`
+		MinStandard int     `json:"min_standard"`
+		MaxStandard int     `json:"max_standard"`
+		BonusLimit  int     `json:"bonus_limit"`
+		MaxAge      int     `json:"max_age"`
+	}
+
+	// EmploymentInsuranceRate は雇用保険料率(労働者負担分、一般の事業)
+	EmploymentInsuranceRate struct {
+		Rate float64 `json:"rate"`
+	}
+
+	// StandardMonthlyGrade は標準報酬月額の等級
+	// 報酬月額が Lower 以上、次の等級の Lower 未満の場合にこの等級となる
+	StandardMonthlyGrade struct {
+		Grade  int `json:"grade"`
+		Amount int `json:"amount"`
+		Lower  int `json:"lower"`
+	}
+
+	// WithholdingTable は源泉徴収税額の電子計算機特例の表(月額)
+	WithholdingTable struct {
+		EmploymentDeduction []WithholdingDeduction `json:"employment_deduction"`
+		BasicDeduction      []WithholdingDeduction `json:"basic_deduction"`
+		DependentDeduction  int                    `json:"dependent_deduction"`
+		Rates               []WithholdingRate      `json:"rates"`
+	}
+
+	// WithholdingDeduction は控除額の表の1行分
+	// Upper が0の行は上限なしとして扱う
+	WithholdingDeduction struct {
+		Upper  int     `json:"upper"`
+		Rate   float64 `json:"rate"`
+		Amount int     `json:"amount"`
+	}
+
+	// WithholdingRate は税率の表の1行分
+	// Upper が0の行は上限なしとして扱う
+	WithholdingRate struct {
+		Upper     int     `json:"upper"`
+		Rate      float64 `json:"rate"`
+		Deduction int     `json:"deduction"`
+	}
+
+	// IncomeTaxTable は所得税の速算表と控除額
+	IncomeTaxTable struct {
+		BasicDeduction     int               `json:"basic_deduction"`
+		ReconstructionRate float64           `json:"reconstruction_rate"`
+		Brackets           []WithholdingRate `json:"brackets"`
+	}
+
+	// ResidentTaxTable は住民税の税率と控除額
+	ResidentTaxTable struct {
+		Rate                  float64 `json:"rate"`
+		BasicDeduction        int     `json:"basic_deduction"`
+		DependentDeduction    int     `json:"dependent_deduction"`
+		AdjustmentDeduction   int     `json:"adjustment_deduction"`
+		PerCapita             int     `json:"per_capita"`
+		ExemptIncome          int     `json:"exempt_income"`
+		ExemptIncomePerMember int     `json:"exempt_income_per_member"`
+		ExemptIncomeAddition  int     `json:"exempt_income_addition"`
+	}
+)
+
+//go:embed tables/*.json
+var tableFiles embed.FS
+
+// rateTables は年度をキーにした料率表(起動時に読み込む)
+var rateTables = mustLoadRateTables()
+
+func mustLoadRateTables() map[int]RateTable {
+	tables, err := loadRateTables()
+	if err != nil {
+		panic(err)
+	}
+	return tables
+}
+
+// loadRateTables は同梱されている料率表を全て読み込む
+func loadRateTables() (map[int]RateTable, error) {
+	entries, err := tableFiles.ReadDir("tables")
+	if err != nil {
+		return nil, err
+	}
+
+	tables := map[int]RateTable{}
+	for _, entry := range entries {
+		raw, err := tableFiles.ReadFile(path.Join("tables", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var table RateTable
+		if err := json.Unmarshal(raw, &table); err != nil {
+			return nil, fmt.Errorf("料率表 %s の読み込みに失敗しました: %v", entry.Name(), err)
+		}
+		tables[table.Year] = table
+	}
+
+	return tables, nil
+}
+
+// TableYears は同梱されている料率表の年度を昇順で返す
+func TableYears() []int {
+	years := make([]int, 0, len(rateTables))
+	for year := range rateTables {
+		years = append(years, year)
+	}
+	sort.Ints(years)
+	return years
+}
+
+// LookupRateTable は指定した年度に適用する料率表を返す
+// 指定年度の表がない場合は、それより前で最も新しい年度の表を返す
+//
+// 引数:
+//   - year: 年度
+//
+// 戻り値:
+//   - RateTable: 料率表
+//   - error: 適用できる料率表がない場合のエラー
+
+func LookupRateTable(year int) (RateTable, error) {
+	years := TableYears()
+	for idx := len(years) - 1; idx >= 0; idx-- {
+		if years[idx] <= year {
+			return rateTables[years[idx]], nil
+		}
+	}
+	return RateTable{}, fmt.Errorf("%d年度の料率表が存在しません。", year)
+}
+
+// HealthRate は都道府県の健康保険料率を返す
+func (rt RateTable) HealthRate(prefecture string) (float64, bool) {
+	rate, ok := rt.HealthInsurance.Rates[prefecture]
+	return rate, ok
+}
@@ -0,0 +1,303 @@
+// tax/take_home.go
+package tax
+
+import (
+	"errors"
+	"math"
+)
+
+type (
+	// TakeHomeInput は手取り額を試算する条件
+	TakeHomeInput struct {
+		MonthlySalary int
+		AnnualBonus   int
+		BonusTimes    int
+		Age           int
+		Prefecture    string
+		Dependents    int
+	}
+
+	// SocialInsurance は社会保険料(本人負担分)の内訳
+	SocialInsurance struct {
+		HealthInsurance     int `json:"health_insurance"`
+		NursingInsurance    int `json:"nursing_insurance"`
+		Pension             int `json:"pension"`
+		EmploymentInsurance int `json:"employment_insurance"`
+		Total               int `json:"total"`
+	}
+
+	TakeHomeMonthly struct {
+		Gross           int             `json:"gross"`
+		HealthGrade     int             `json:"health_grade"`
+		StandardMonthly int             `json:"standard_monthly"`
+		PensionStandard int             `json:"pension_standard"`
+		SocialInsurance SocialInsurance `json:"social_insurance"`
+		IncomeTax       int             `json:"income_tax"`
+		ResidentTax     int             `json:"resident_tax"`
+		TakeHome        int             `json:"take_home"`
+	}
+
+	TakeHomeBonus struct {
+		Times           int             `json:"times"`
+		Gross           int             `json:"gross"`
+		StandardBonus   int             `json:"standard_bonus"`
+		SocialInsurance SocialInsurance `json:"social_insurance"`
+		IncomeTax       int             `json:"income_tax"`
+		TakeHome        int             `json:"take_home"`
+	}
+
+	TakeHomeAnnual struct {
+		Gross           int `json:"gross"`
+		SocialInsurance int `json:"social_insurance"`
+		IncomeTax       int `json:"income_tax"`
+		ResidentTax     int `json:"resident_tax"`
+		TakeHome        int `json:"take_home"`
+	}
+
+	TakeHomeResult struct {
+		Year       int             `json:"year"`
+		Prefecture string          `json:"prefecture"`
+		Monthly    TakeHomeMonthly `json:"monthly"`
+		Bonus      TakeHomeBonus   `json:"bonus"`
+		Annual     TakeHomeAnnual  `json:"annual"`
+	}
+)
+
+// employeePremium は被保険者負担分の保険料を返す
+// 給与から控除する場合は50銭以下切り捨て、50銭超切り上げとなる
+func employeePremium(amount int, rate float64, split bool) int {
+	premium := float64(amount) * rate / 100
+	if split {
+		premium /= 2
+	}
+	// 浮動小数点の誤差を銭単位で丸めてから端数処理する
+	premium = math.Round(premium*100) / 100
+	if premium-math.Floor(premium) > 0.5 {
+		return int(math.Ceil(premium))
+	}
+	return int(math.Floor(premium))
+}
+
+// StandardMonthly は報酬月額から健康保険の標準報酬月額の等級と金額を返す
+//
+// 引数:
+//   - salary: 報酬月額
+//
+// 戻り値:
+//   - int: 等級
+//   - int: 標準報酬月額
+
+func (rt RateTable) StandardMonthly(salary int) (int, int) {
+	grade := rt.StandardMonthlyGrades[0]
+	for _, row := range rt.StandardMonthlyGrades {
+		if salary < row.Lower {
+			break
+		}
+		grade = row
+	}
+	return grade.Grade, grade.Amount
+}
+
+// PensionStandard は健康保険の標準報酬月額を厚生年金の上下限に収める
+func (rt RateTable) PensionStandard(standard int) int {
+	return min(max(standard, rt.Pension.MinStandard), rt.Pension.MaxStandard)
+}
+
+// socialInsurance は標準報酬(月額又は賞与額)と給与額から社会保険料を算出する
+func (rt RateTable) socialInsurance(healthStandard, pensionStandard, gross int, healthRate float64, age int) SocialInsurance {
+	var insurance SocialInsurance
+
+	insurance.HealthInsurance = employeePremium(healthStandard, healthRate, true)
+	if age >= rt.HealthInsurance.NursingMinAge && age <= rt.HealthInsurance.NursingMaxAge {
+		// 介護保険料は健康保険料と合わせて算出し、差額を介護保険料とする
+		total := employeePremium(healthStandard, healthRate+rt.HealthInsurance.NursingRate, true)
+		insurance.NursingInsurance = total - insurance.HealthInsurance
+	}
+	if age <= rt.Pension.MaxAge {
+		insurance.Pension = employeePremium(pensionStandard, rt.Pension.Rate, true)
+	}
+	insurance.EmploymentInsurance = employeePremium(gross, rt.EmploymentInsurance.Rate, false)
+
+	insurance.Total = insurance.HealthInsurance + insurance.NursingInsurance + insurance.Pension + insurance.EmploymentInsurance
+	return insurance
+}
+
+// MonthlyWithholding は月額表の電子計算機特例で給与の源泉徴収税額(甲欄)を返す
+//
+// 引数:
+//   - salary: 社会保険料等控除後の給与等の金額
+//   - dependents: 扶養親族等の数
+//
+// 戻り値:
+//   - int: 源泉徴収税額
+
+func (rt RateTable) MonthlyWithholding(salary, dependents int) int {
+	// 月額表で88,000円未満は税額0円
+	if salary < 88000 {
+		return 0
+	}
+
+	table := rt.Withholding
+
+	var employmentDeduction int
+	for _, row := range table.EmploymentDeduction {
+		if row.Upper == 0 || salary <= row.Upper {
+			// 給与所得控除の額は1円未満の端数を切り上げる
+			employmentDeduction = int(math.Ceil(float64(salary)*row.Rate/100)) + row.Amount
+			break
+		}
+	}
+
+	var basicDeduction int
+	for _, row := range table.BasicDeduction {
+		if row.Upper == 0 || salary <= row.Upper {
+			basicDeduction = row.Amount
+			break
+		}
+	}
+
+	taxable := salary - employmentDeduction - basicDeduction - table.DependentDeduction*dependents
+	if taxable <= 0 {
+		return 0
+	}
+
+	for _, row := range table.Rates {
+		if row.Upper == 0 || taxable <= row.Upper {
+			// 税額は10円未満の端数を四捨五入する
+			withholding := float64(taxable)*row.Rate/100 - float64(row.Deduction)
+			return max(int(math.Round(withholding/10))*10, 0)
+		}
+	}
+
+	return 0
+}
+
+// BonusWithholding は賞与の源泉徴収税額を概算する
+// 賞与の6分の1を前月の給与に加算した税額と前月の給与の税額の差額を6倍する
+//
+// 引数:
+//   - bonus: 社会保険料等控除後の賞与の金額
+//   - salary: 前月の社会保険料等控除後の給与等の金額
+//   - dependents: 扶養親族等の数
+//
+// 戻り値:
+//   - int: 源泉徴収税額
+
+func (rt RateTable) BonusWithholding(bonus, salary, dependents int) int {
+	if bonus <= 0 {
+		return 0
+	}
+	withBonus := rt.MonthlyWithholding(salary+bonus/6, dependents)
+	withoutBonus := rt.MonthlyWithholding(salary, dependents)
+	return (withBonus - withoutBonus) * 6
+}
+
+// AnnualResidentTax は前年も同額の収入があったものとして住民税の年税額を返す
+//
+// 引数:
+//   - gross: 給与の年間収入金額
+//   - socialInsurance: 年間の社会保険料
+//   - dependents: 扶養親族の数
+//
+// 戻り値:
+//   - int: 住民税(所得割と均等割)の年税額
+
+func (rt RateTable) AnnualResidentTax(gross, socialInsurance, dependents int) int {
+	table := rt.ResidentTax
+	income := gross - rt.EmploymentIncomeDeduction(gross)
+
+	// 非課税限度額以下の場合は均等割も課税されない
+	exempt := table.ExemptIncome
+	if dependents > 0 {
+		exempt = table.ExemptIncomePerMember*(dependents+1) + table.ExemptIncomeAddition
+	}
+	if income <= exempt {
+		return 0
+	}
+
+	taxable := (income - socialInsurance - table.BasicDeduction - table.DependentDeduction*dependents) / 1000 * 1000
+	incomeLevy := 0
+	if taxable > 0 {
+		incomeLevy = max(int(float64(taxable)*table.Rate/100)-table.AdjustmentDeduction, 0) / 100 * 100
+	}
+
+	return incomeLevy + table.PerCapita
+}
+
+// CalcTakeHome は額面の給与と賞与から社会保険料・税金を差し引いた手取り額を試算する。
+// 住民税は前年も同額の収入があったものとして、特別徴収の月額(100円未満切り捨て)を計上する。
+//
+// 引数:
+//   - input: 試算条件
+//
+// 戻り値:
+//   - TakeHomeResult: 月額・賞与・年額の手取り額
+//   - error: 都道府県が料率表に存在しない場合のエラー
+
+func (rt RateTable) CalcTakeHome(input TakeHomeInput) (TakeHomeResult, error) {
+	healthRate, ok := rt.HealthRate(input.Prefecture)
+	if !ok {
+		return TakeHomeResult{}, errors.New("都道府県が正しくありません。")
+	}
+
+	result := TakeHomeResult{
+		Year:       rt.Year,
+		Prefecture: input.Prefecture,
+	}
+
+	// 月額の給与
+	monthly := &result.Monthly
+	monthly.Gross = input.MonthlySalary
+	monthly.HealthGrade, monthly.StandardMonthly = rt.StandardMonthly(input.MonthlySalary)
+	monthly.PensionStandard = rt.PensionStandard(monthly.StandardMonthly)
+	monthly.SocialInsurance = rt.socialInsurance(monthly.StandardMonthly, monthly.PensionStandard, input.MonthlySalary, healthRate, input.Age)
+	salaryAfterInsurance := input.MonthlySalary - monthly.SocialInsurance.Total
+	monthly.IncomeTax = rt.MonthlyWithholding(salaryAfterInsurance, input.Dependents)
+
+	// 賞与(支給回数で均等に分けて支給し、割り切れない端数は最後の支給に加える)
+	annualBonusGross, annualBonusInsurance, annualBonusTax := 0, 0, 0
+	if input.AnnualBonus > 0 && input.BonusTimes > 0 {
+		bonus := &result.Bonus
+		bonus.Times = input.BonusTimes
+		bonus.Gross = input.AnnualBonus / input.BonusTimes
+		bonus.StandardBonus = bonus.Gross / 1000 * 1000
+		annualBonusGross = input.AnnualBonus
+
+		healthUsed := 0
+		for idx := 0; idx < input.BonusTimes; idx++ {
+			gross := bonus.Gross
+			if idx == input.BonusTimes-1 {
+				gross += input.AnnualBonus % input.BonusTimes
+			}
+			standardBonus := gross / 1000 * 1000
+
+			// 健康保険は年度の累計、厚生年金は1回あたりの上限がある
+			healthStandard := min(standardBonus, rt.HealthInsurance.BonusAnnualLimit-healthUsed)
+			healthUsed += healthStandard
+			pensionStandard := min(standardBonus, rt.Pension.BonusLimit)
+
+			insurance := rt.socialInsurance(healthStandard, pensionStandard, gross, healthRate, input.Age)
+			withholding := rt.BonusWithholding(gross-insurance.Total, salaryAfterInsurance, input.Dependents)
+			if idx == 0 {
+				bonus.SocialInsurance = insurance
+				bonus.IncomeTax = withholding
+				bonus.TakeHome = bonus.Gross - insurance.Total - withholding
+			}
+			annualBonusInsurance += insurance.Total
+			annualBonusTax += withholding
+		}
+	}
+
+	// 年額
+	annual := &result.Annual
+	annual.Gross = input.MonthlySalary*12 + annualBonusGross
+	annual.SocialInsurance = monthly.SocialInsurance.Total*12 + annualBonusInsurance
+	annual.IncomeTax = monthly.IncomeTax*12 + annualBonusTax
+	annual.ResidentTax = rt.AnnualResidentTax(annual.Gross, annual.SocialInsurance, input.Dependents)
+	annual.TakeHome = annual.Gross - annual.SocialInsurance - annual.IncomeTax - annual.ResidentTax
+
+	monthly.ResidentTax = annual.ResidentTax / 12 / 100 * 100
+	monthly.TakeHome = input.MonthlySalary - monthly.SocialInsurance.Total - monthly.IncomeTax - monthly.ResidentTax
+
+	return result, nil
+}
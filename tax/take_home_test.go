@@ -0,0 +1,194 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupRateTable(t *testing.T) {
+	t.Run("success 同梱されている年度", func(t *testing.T) {
+		table, err := LookupRateTable(2024)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2024, table.Year)
+		assert.Len(t, table.HealthInsurance.Rates, 47)
+		assert.Len(t, table.StandardMonthlyGrades, 50)
+		assert.Len(t, table.EmploymentDeduction, 6)
+		assert.Len(t, table.IncomeTax.Brackets, 7)
+		assert.Equal(t, 480000, table.IncomeTax.BasicDeduction)
+	})
+
+	t.Run("success 新しい年度は直近の表を使う", func(t *testing.T) {
+		table, err := LookupRateTable(2099)
+
+		assert.NoError(t, err)
+		assert.Equal(t, TableYears()[len(TableYears())-1], table.Year)
+	})
+
+	t.Run("error 表がない年度", func(t *testing.T) {
+		_, err := LookupRateTable(2000)
+
+		assert.EqualError(t, err, "2000年度の料率表が存在しません。")
+	})
+}
+
+func TestStandardMonthly(t *testing.T) {
+	table, _ := LookupRateTable(2024)
+
+	tests := []struct {
+		salary   int
+		grade    int
+		standard int
+		pension  int
+	}{
+		{salary: 50000, grade: 1, standard: 58000, pension: 88000},
+		{salary: 300000, grade: 22, standard: 300000, pension: 300000},
+		{salary: 309999, grade: 22, standard: 300000, pension: 300000},
+		{salary: 310000, grade: 23, standard: 320000, pension: 320000},
+		{salary: 700000, grade: 37, standard: 710000, pension: 650000},
+		{salary: 2000000, grade: 50, standard: 1390000, pension: 650000},
+	}
+
+	for _, tt := range tests {
+		grade, standard := table.StandardMonthly(tt.salary)
+		assert.Equal(t, tt.grade, grade, "salary: %d", tt.salary)
+		assert.Equal(t, tt.standard, standard, "salary: %d", tt.salary)
+		assert.Equal(t, tt.pension, table.PensionStandard(standard), "salary: %d", tt.salary)
+	}
+}
+
+func TestEmployeePremium(t *testing.T) {
+	// 50銭以下は切り捨て
+	assert.Equal(t, 100, employeePremium(201, 100, true))
+	// 50銭超は切り上げ
+	assert.Equal(t, 101, employeePremium(1006, 10, false))
+	assert.Equal(t, 14970, employeePremium(300000, 9.98, true))
+}
+
+func TestMonthlyWithholding(t *testing.T) {
+	table, _ := LookupRateTable(2024)
+
+	t.Run("success 扶養親族なし", func(t *testing.T) {
+		// 給与所得控除 83,401円、基礎控除 40,000円、課税 132,379円 × 5.105%
+		assert.Equal(t, 6760, table.MonthlyWithholding(255780, 0))
+	})
+
+	t.Run("success 扶養親族あり", func(t *testing.T) {
+		assert.Less(t, table.MonthlyWithholding(255780, 2), table.MonthlyWithholding(255780, 0))
+	})
+
+	t.Run("success 88,000円未満は0円", func(t *testing.T) {
+		assert.Equal(t, 0, table.MonthlyWithholding(87999, 0))
+	})
+}
+
+func TestCalcTakeHome(t *testing.T) {
+	table, _ := LookupRateTable(2024)
+
+	t.Run("success 東京都 月30万円 30歳", func(t *testing.T) {
+		result, err := table.CalcTakeHome(TakeHomeInput{
+			MonthlySalary: 300000,
+			Age:           30,
+			Prefecture:    "東京都",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, SocialInsurance{
+			HealthInsurance:     14970,
+			NursingInsurance:    0,
+			Pension:             27450,
+			EmploymentInsurance: 1800,
+			Total:               44220,
+		}, result.Monthly.SocialInsurance)
+		assert.Equal(t, 6760, result.Monthly.IncomeTax)
+		assert.Equal(t, 3600000, result.Annual.Gross)
+		assert.Equal(t, 530640, result.Annual.SocialInsurance)
+		assert.Greater(t, result.Annual.ResidentTax, 0)
+		assert.Equal(t, result.Annual.ResidentTax/12/100*100, result.Monthly.ResidentTax)
+		assert.Equal(t, 300000-44220-6760-result.Monthly.ResidentTax, result.Monthly.TakeHome)
+	})
+
+	t.Run("success 介護保険の対象年齢", func(t *testing.T) {
+		result, err := table.CalcTakeHome(TakeHomeInput{
+			MonthlySalary: 300000,
+			Age:           45,
+			Prefecture:    "東京都",
+		})
+
+		assert.NoError(t, err)
+		// (9.98% + 1.60%) / 2 = 17,370円
+		assert.Equal(t, 17370-14970, result.Monthly.SocialInsurance.NursingInsurance)
+	})
+
+	t.Run("success 賞与あり", func(t *testing.T) {
+		result, err := table.CalcTakeHome(TakeHomeInput{
+			MonthlySalary: 300000,
+			AnnualBonus:   1200000,
+			BonusTimes:    2,
+			Age:           30,
+			Prefecture:    "大阪府",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 600000, result.Bonus.Gross)
+		assert.Equal(t, 2, result.Bonus.Times)
+		// 600,000 × 10.34% / 2
+		assert.Equal(t, 31020, result.Bonus.SocialInsurance.HealthInsurance)
+		assert.Equal(t, 54900, result.Bonus.SocialInsurance.Pension)
+		assert.Greater(t, result.Bonus.IncomeTax, 0)
+		assert.Equal(t, 4800000, result.Annual.Gross)
+		assert.Equal(t, result.Annual.Gross-result.Annual.SocialInsurance-result.Annual.IncomeTax-result.Annual.ResidentTax, result.Annual.TakeHome)
+	})
+
+	t.Run("success 賞与の端数は最後の支給に加える", func(t *testing.T) {
+		result, err := table.CalcTakeHome(TakeHomeInput{
+			MonthlySalary: 300000,
+			AnnualBonus:   1000000,
+			BonusTimes:    3,
+			Age:           30,
+			Prefecture:    "東京都",
+		})
+
+		assert.NoError(t, err)
+		// 1回目の支給額を表示する(最後の支給は333,334円)
+		assert.Equal(t, 333333, result.Bonus.Gross)
+		assert.Equal(t, 4600000, result.Annual.Gross)
+	})
+
+	t.Run("success 厚生年金の賞与上限", func(t *testing.T) {
+		result, err := table.CalcTakeHome(TakeHomeInput{
+			MonthlySalary: 300000,
+			AnnualBonus:   2000000,
+			BonusTimes:    1,
+			Age:           30,
+			Prefecture:    "東京都",
+		})
+
+		assert.NoError(t, err)
+		// 1,500,000 × 18.3% / 2
+		assert.Equal(t, 137250, result.Bonus.SocialInsurance.Pension)
+	})
+
+	t.Run("success 低所得は住民税非課税", func(t *testing.T) {
+		result, err := table.CalcTakeHome(TakeHomeInput{
+			MonthlySalary: 80000,
+			Age:           20,
+			Prefecture:    "東京都",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Annual.ResidentTax)
+		assert.Equal(t, 0, result.Monthly.IncomeTax)
+	})
+
+	t.Run("error 都道府県が存在しない", func(t *testing.T) {
+		_, err := table.CalcTakeHome(TakeHomeInput{
+			MonthlySalary: 300000,
+			Age:           30,
+			Prefecture:    "東京",
+		})
+
+		assert.EqualError(t, err, "都道府県が正しくありません。")
+	})
+}
@@ -0,0 +1,105 @@
+// tax/tax.go
+package tax
+
+// 給与明細から社会保険料が分からない場合の概算率
+const SocialInsuranceRate = 0.15
+
+// EmploymentIncomeDeduction は給与の収入金額から給与所得控除額を返す
+// 控除額は給与の収入金額を上限とする
+//
+// 引数:
+//   - gross: 給与の年間収入金額
+//
+// 戻り値:
+//   - int: 給与所得控除額
+
+func (rt RateTable) EmploymentIncomeDeduction(gross int) int {
+	for _, row := range rt.EmploymentDeduction {
+		if row.Upper == 0 || gross <= row.Upper {
+			return min(gross, int(float64(gross)*row.Rate/100)+row.Amount)
+		}
+	}
+	return 0
+}
+
+// CalcIncomeTax は課税所得金額から所得税額(復興特別所得税を含む)を返す
+// 課税所得は1,000円未満、税額は100円未満を切り捨てる
+//
+// 引数:
+//   - taxable: 課税所得金額
+//
+// 戻り値:
+//   - int: 所得税額
+
+func (rt RateTable) CalcIncomeTax(taxable int) int {
+	taxable = taxable / 1000 * 1000
+	if taxable <= 0 {
+		return 0
+	}
+
+	table := rt.IncomeTax
+	for _, bracket := range table.Brackets {
+		if bracket.Upper == 0 || taxable <= bracket.Upper {
+			base := float64(taxable)*bracket.Rate/100 - float64(bracket.Deduction)
+			return int(base*(100+table.ReconstructionRate)/100) / 100 * 100
+		}
+	}
+
+	return 0
+}
+
+// CalcResidentTax は住民税の課税所得金額から所得割額を返す
+// 課税所得は1,000円未満、税額は100円未満を切り捨てる
+//
+// 引数:
+//   - taxable: 住民税の課税所得金額
+//
+// 戻り値:
+//   - int: 住民税(所得割)額
+
+func (rt RateTable) CalcResidentTax(taxable int) int {
+	taxable = taxable / 1000 * 1000
+	if taxable <= 0 {
+		return 0
+	}
+	return int(float64(taxable)*rt.ResidentTax.Rate/100) / 100 * 100
+}
+
+// AnnualTax は給与の年収と所得控除から所得税と住民税の合計を返す
+//
+// 引数:
+//   - gross: 給与の年間収入金額
+//   - socialInsurance: 社会保険料
+//   - otherDeduction: その他の所得控除(iDeCoの掛金など)
+//
+// 戻り値:
+//   - int: 所得税(復興特別所得税を含む)
+//   - int: 住民税(所得割)
+
+func (rt RateTable) AnnualTax(gross, socialInsurance, otherDeduction int) (int, int) {
+	income := gross - rt.EmploymentIncomeDeduction(gross)
+	deduction := socialInsurance + otherDeduction
+
+	incomeTax := rt.CalcIncomeTax(income - deduction - rt.IncomeTax.BasicDeduction)
+	residentTax := rt.CalcResidentTax(income - deduction - rt.ResidentTax.BasicDeduction)
+
+	return incomeTax, residentTax
+}
+
+// IdecoTaxSaving はiDeCoの年間掛金による所得税・住民税の軽減額を返す
+// 掛金は全額が小規模企業共済等掛金控除の対象となる
+//
+// 引数:
+//   - gross: 給与の年間収入金額
+//   - socialInsurance: 社会保険料
+//   - contribution: iDeCoの年間掛金
+//
+// 戻り値:
+//   - int: 所得税と住民税の軽減額の合計
+
+func (rt RateTable) IdecoTaxSaving(gross, socialInsurance, contribution int) int {
+	baseIncomeTax, baseResidentTax := rt.AnnualTax(gross, socialInsurance, 0)
+	incomeTax, residentTax := rt.AnnualTax(gross, socialInsurance, contribution)
+
+	return (baseIncomeTax + baseResidentTax) - (incomeTax + residentTax)
+}
@@ -0,0 +1,80 @@
+package tax
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmploymentIncomeDeduction(t *testing.T) {
+	table, _ := LookupRateTable(2024)
+
+	tests := []struct {
+		gross    int
+		expected int
+	}{
+		{gross: 500000, expected: 500000},
+		{gross: 1000000, expected: 550000},
+		{gross: 1700000, expected: 580000},
+		{gross: 3000000, expected: 980000},
+		{gross: 5000000, expected: 1440000},
+		{gross: 7000000, expected: 1800000},
+		{gross: 10000000, expected: 1950000},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, table.EmploymentIncomeDeduction(tt.gross), "gross: %d", tt.gross)
+	}
+}
+
+func TestIncomeTax(t *testing.T) {
+	table, _ := LookupRateTable(2024)
+
+	t.Run("success 速算表", func(t *testing.T) {
+		// 1,000,000 * 5% * 1.021 = 51,050 → 100円未満切り捨て
+		assert.Equal(t, 51000, table.CalcIncomeTax(1000000))
+		// (2,330,000 * 10% - 97,500) * 1.021 = 138,345
+		assert.Equal(t, 138300, table.CalcIncomeTax(2330000))
+		// (50,000,000 * 45% - 4,796,000) * 1.021 = 18,075,784
+		assert.Equal(t, 18075700, table.CalcIncomeTax(50000000))
+	})
+
+	t.Run("success 年度の表の税率で計算する", func(t *testing.T) {
+		changed := table
+		changed.IncomeTax = IncomeTaxTable{
+			Brackets: []WithholdingRate{{Rate: 10}},
+		}
+
+		// 復興特別所得税がない年度の表では 1,000,000 * 10% = 100,000
+		assert.Equal(t, 100000, changed.CalcIncomeTax(1000000))
+	})
+
+	t.Run("success 課税所得が0以下", func(t *testing.T) {
+		assert.Equal(t, 0, table.CalcIncomeTax(0))
+		assert.Equal(t, 0, table.CalcIncomeTax(-100000))
+	})
+}
+
+func TestResidentTax(t *testing.T) {
+	table, _ := LookupRateTable(2024)
+
+	assert.Equal(t, 238000, table.CalcResidentTax(2380999))
+	assert.Equal(t, 0, table.CalcResidentTax(-1))
+}
+
+func TestIdecoTaxSaving(t *testing.T) {
+	table, _ := LookupRateTable(2024)
+
+	t.Run("success 年収500万円 月23,000円", func(t *testing.T) {
+		// 所得税 138,300 → 110,100、住民税 238,000 → 210,400
+		assert.Equal(t, 55800, table.IdecoTaxSaving(5000000, 750000, 276000))
+	})
+
+	t.Run("success 課税所得がない場合は節税額0", func(t *testing.T) {
+		assert.Equal(t, 0, table.IdecoTaxSaving(1000000, 150000, 276000))
+	})
+
+	t.Run("success 掛金0", func(t *testing.T) {
+		assert.Equal(t, 0, table.IdecoTaxSaving(5000000, 750000, 0))
+	})
+}
@@ -16,9 +16,8 @@ func TestEmailTemplateService(t *testing.T) {
 	var Update string = "ユーザーパスワード"
 	var UpdateValue string = "Update"
 	var DateTime string = "2024年12月07日 20:00"
-	var Year string = utils.NewUtilsFetcher(utils.JwtSecret).DateTimeStr(time.Now(), "2006年")
+	var Year string = utils.NewUtilsFetcher(utils.JwtKeys).DateTimeStr(time.Now(), "2006年")
 	var Link string = "http://exmaple/test"
-	var NewPassword string = "Test12345!"
 
 	t.Run("TemporayPostSignUpTemplate テンプレート", func(t *testing.T) {
 		emailTemplateService := NewEmailTemplateManager()
@@ -165,12 +164,11 @@ func TestEmailTemplateService(t *testing.T) {
 	t.Run("NewPasswordUpdateTemplate テンプレート", func(t *testing.T) {
 		emailTemplateService := NewEmailTemplateManager()
 
-		subject, body, err := emailTemplateService.NewPasswordUpdateTemplate(NewPassword, DateTime)
+		subject, body, err := emailTemplateService.NewPasswordUpdateTemplate(DateTime)
 
 		data := GenericEmailData{
-			NewPassword: NewPassword,
-			DateTime:    DateTime,
-			Year:        Year,
+			DateTime: DateTime,
+			Year:     Year,
 		}
 
 		var expectedBody bytes.Buffer
@@ -181,4 +179,181 @@ func TestEmailTemplateService(t *testing.T) {
 		assert.Equal(t, subject, "【たくわえる】パスワード再発行成功のお知らせ")
 		assert.Equal(t, body, expectedBody.String())
 	})
+
+	t.Run("RefreshTokenReuseTemplate テンプレート", func(t *testing.T) {
+		emailTemplateService := NewEmailTemplateManager()
+
+		subject, body, err := emailTemplateService.RefreshTokenReuseTemplate(UserEmail, DateTime)
+
+		data := GenericEmailData{
+			UserEmail: UserEmail,
+			DateTime:  DateTime,
+			Year:      Year,
+		}
+
+		var expectedBody bytes.Buffer
+		refreshTokenReuseTemplate.Execute(&expectedBody, data)
+
+		assert.NoError(t, err)
+
+		assert.Equal(t, subject, "【たくわえる】不正なアクセスの可能性を検知しました")
+		assert.Equal(t, body, expectedBody.String())
+	})
+	t.Run("AccountLockedTemplate テンプレート", func(t *testing.T) {
+		emailTemplateService := NewEmailTemplateManager()
+
+		subject, body, err := emailTemplateService.AccountLockedTemplate(UserEmail, Link, DateTime)
+
+		data := GenericEmailData{
+			UserEmail: UserEmail,
+			Link:      Link,
+			DateTime:  DateTime,
+			Year:      Year,
+		}
+
+		var expectedBody bytes.Buffer
+		accountLockedTemplate.Execute(&expectedBody, data)
+
+		assert.NoError(t, err)
+
+		assert.Equal(t, subject, "【たくわえる】アカウントを一時的にロックしました")
+		assert.Equal(t, body, expectedBody.String())
+	})
+	t.Run("EmailChangeConfirmTemplate テンプレート", func(t *testing.T) {
+		emailTemplateService := NewEmailTemplateManager()
+
+		subject, body, err := emailTemplateService.EmailChangeConfirmTemplate(UserEmail, Link, DateTime)
+
+		data := GenericEmailData{
+			UserEmail: UserEmail,
+			Link:      Link,
+			DateTime:  DateTime,
+			Year:      Year,
+		}
+
+		var expectedBody bytes.Buffer
+		emailChangeConfirmTemplate.Execute(&expectedBody, data)
+
+		assert.NoError(t, err)
+
+		assert.Equal(t, subject, "【たくわえる】メールアドレス変更の確認")
+		assert.Equal(t, body, expectedBody.String())
+	})
+
+	t.Run("EmailChangeNoticeTemplate テンプレート", func(t *testing.T) {
+		emailTemplateService := NewEmailTemplateManager()
+
+		subject, body, err := emailTemplateService.EmailChangeNoticeTemplate(UserEmail, Link, DateTime)
+
+		data := GenericEmailData{
+			UserEmail: UserEmail,
+			Link:      Link,
+			DateTime:  DateTime,
+			Year:      Year,
+		}
+
+		var expectedBody bytes.Buffer
+		emailChangeNoticeTemplate.Execute(&expectedBody, data)
+
+		assert.NoError(t, err)
+
+		assert.Equal(t, subject, "【たくわえる】メールアドレス変更のお知らせ")
+		assert.Equal(t, body, expectedBody.String())
+	})
+
+	t.Run("EmailChangeCanceledTemplate テンプレート", func(t *testing.T) {
+		emailTemplateService := NewEmailTemplateManager()
+
+		subject, body, err := emailTemplateService.EmailChangeCanceledTemplate(UserEmail, DateTime)
+
+		data := GenericEmailData{
+			UserEmail: UserEmail,
+			DateTime:  DateTime,
+			Year:      Year,
+		}
+
+		var expectedBody bytes.Buffer
+		emailChangeCanceledTemplate.Execute(&expectedBody, data)
+
+		assert.NoError(t, err)
+
+		assert.Equal(t, subject, "【たくわえる】メールアドレス変更を取り消しました")
+		assert.Equal(t, body, expectedBody.String())
+	})
+
+	t.Run("AccountDeactivatedTemplate テンプレート", func(t *testing.T) {
+		emailTemplateService := NewEmailTemplateManager()
+		var PurgeDate string = "2025年01月06日 20:00"
+
+		subject, body, err := emailTemplateService.AccountDeactivatedTemplate(Name, UserEmail, Link, PurgeDate, DateTime)
+
+		data := GenericEmailData{
+			Name:      Name,
+			UserEmail: UserEmail,
+			Link:      Link,
+			PurgeDate: PurgeDate,
+			DateTime:  DateTime,
+			Year:      Year,
+		}
+
+		var expectedBody bytes.Buffer
+		accountDeactivatedTemplate.Execute(&expectedBody, data)
+
+		assert.NoError(t, err)
+
+		assert.Equal(t, subject, "【たくわえる】アカウント退会受付のお知らせ")
+		assert.Equal(t, body, expectedBody.String())
+		assert.Contains(t, body, Link)
+		assert.Contains(t, body, PurgeDate)
+	})
+
+	t.Run("DataExportReadyTemplate テンプレート", func(t *testing.T) {
+		emailTemplateService := NewEmailTemplateManager()
+		var ExpireDate string = "2025年01月07日 20:00"
+
+		subject, body, err := emailTemplateService.DataExportReadyTemplate(UserEmail, Link, ExpireDate, DateTime)
+
+		data := GenericEmailData{
+			UserEmail:  UserEmail,
+			Link:       Link,
+			ExpireDate: ExpireDate,
+			DateTime:   DateTime,
+			Year:       Year,
+		}
+
+		var expectedBody bytes.Buffer
+		dataExportReadyTemplate.Execute(&expectedBody, data)
+
+		assert.NoError(t, err)
+
+		assert.Equal(t, subject, "【たくわえる】データエクスポート完了のお知らせ")
+		assert.Equal(t, body, expectedBody.String())
+		assert.Contains(t, body, Link)
+		assert.Contains(t, body, ExpireDate)
+	})
+
+	t.Run("MagicLinkTemplate テンプレート", func(t *testing.T) {
+		emailTemplateService := NewEmailTemplateManager()
+		var ExpireDate string = "2025年01月07日 20:15"
+
+		subject, body, err := emailTemplateService.MagicLinkTemplate(UserEmail, Link, ExpireDate, DateTime)
+
+		data := GenericEmailData{
+			UserEmail:  UserEmail,
+			Link:       Link,
+			ExpireDate: ExpireDate,
+			DateTime:   DateTime,
+			Year:       Year,
+		}
+
+		var expectedBody bytes.Buffer
+		magicLinkTemplate.Execute(&expectedBody, data)
+
+		assert.NoError(t, err)
+
+		assert.Equal(t, subject, "【たくわえる】サインイン用リンクのお知らせ")
+		assert.Equal(t, body, expectedBody.String())
+		assert.Contains(t, body, Link)
+		assert.Contains(t, body, ExpireDate)
+	})
 }
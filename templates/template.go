@@ -16,7 +16,15 @@ type (
 		DeleteSignInTemplate(Name, UserEmail, DateTime string) (string, string, error)
 		SignOutTemplate(UserEmail, DateTime string) (string, string, error)
 		RegisterEmailCheckNoticeTemplate(Link, DateTime string) (string, string, error)
-		NewPasswordUpdateTemplate(NewPassword, DateTime string) (string, string, error)
+		NewPasswordUpdateTemplate(DateTime string) (string, string, error)
+		RefreshTokenReuseTemplate(UserEmail, DateTime string) (string, string, error)
+		AccountLockedTemplate(UserEmail, Link, DateTime string) (string, string, error)
+		EmailChangeConfirmTemplate(UserEmail, Link, DateTime string) (string, string, error)
+		EmailChangeNoticeTemplate(UserEmail, Link, DateTime string) (string, string, error)
+		EmailChangeCanceledTemplate(UserEmail, DateTime string) (string, string, error)
+		AccountDeactivatedTemplate(Name, UserEmail, Link, PurgeDate, DateTime string) (string, string, error)
+		DataExportReadyTemplate(UserEmail, Link, ExpireDate, DateTime string) (string, string, error)
+		MagicLinkTemplate(UserEmail, Link, ExpireDate, DateTime string) (string, string, error)
 	}
 	// データ構造体
 	TemporayPostSignUpEmailData struct {
@@ -34,7 +42,8 @@ type (
 		Update      string
 		UpdateValue string
 		Link        string
-		NewPassword string
+		PurgeDate   string
+		ExpireDate  string
 	}
 
 	EmailTemplateManager struct{}
@@ -229,6 +238,171 @@ var postSignInTemplate = template.Must(template.Must(commonTemplate.Clone()).Par
 	</html>
 `))
 
+var refreshTokenReuseTemplate = template.Must(template.Must(commonTemplate.Clone()).Parse(`
+	{{template "Style"}}
+	<!DOCTYPE html>
+	<html>
+		<head>
+			<title>不正なアクセスの検知</title>
+		</head>
+		<body>
+			<div class="container">
+				<div class="header">
+					たくわえる<br>
+					不正なアクセスの可能性を検知しました
+				</div>
+				<div class="body">
+					<p>いつもたくわえるをご利用いただき、誠にありがとうございます。</p>
+					<p>お客様のサインイン情報(リフレッシュトークン)が再利用されたため、安全のため全ての端末のサインインを無効にしました。</p>
+
+					<div class="info-section">
+						<h4>登録メールアドレス</h4>
+						<p>{{.UserEmail}}</p>
+						<h4>検知日時</h4>
+						<p>{{.DateTime}}</p>
+					</div>
+					<p>お手数ですが、再度サインインしてください。お心当たりがない場合はパスワードの変更をお勧めします。</p>
+					{{template "Support"}}
+				</div>
+				{{template "Footer" .}}
+			</div>
+		</body>
+	</html>
+`))
+
+var accountLockedTemplate = template.Must(template.Must(commonTemplate.Clone()).Parse(`
+	{{template "Style"}}
+	<!DOCTYPE html>
+	<html>
+		<head>
+			<title>アカウントロックのお知らせ</title>
+		</head>
+		<body>
+			<div class="container">
+				<div class="header">
+					たくわえる<br>
+					アカウントを一時的にロックしました
+				</div>
+				<div class="body">
+					<p>いつもたくわえるをご利用いただき、誠にありがとうございます。</p>
+					<p>サインインに続けて失敗したため、安全のためお客様のアカウントを一時的にロックしました。</p>
+
+					<div class="info-section">
+						<h4>登録メールアドレス</h4>
+						<p>{{.UserEmail}}</p>
+						<h4>ロック日時</h4>
+						<p>{{.DateTime}}</p>
+					</div>
+					<p>ご本人による操作の場合は、こちらのリンクからロックを解除できます。</p>
+						{{.Link}}
+					<p>お心当たりがない場合は、第三者がサインインを試みた可能性があります。パスワードの変更をお勧めします。</p>
+					{{template "Support"}}
+				</div>
+				{{template "Footer" .}}
+			</div>
+		</body>
+	</html>
+`))
+
+var emailChangeConfirmTemplate = template.Must(template.Must(commonTemplate.Clone()).Parse(`
+	{{template "Style"}}
+	<!DOCTYPE html>
+	<html>
+		<head>
+			<title>メールアドレス変更の確認</title>
+		</head>
+		<body>
+			<div class="container">
+				<div class="header">
+					たくわえる<br>
+					メールアドレス変更の確認
+				</div>
+				<div class="body">
+					<p>いつもたくわえるをご利用いただき、誠にありがとうございます。</p>
+					<p>登録メールアドレスをこちらのメールアドレスへ変更する手続きを受け付けました。</p>
+
+					<div class="info-section">
+						<h4>新しいメールアドレス</h4>
+						<p>{{.UserEmail}}</p>
+						<h4>受付日時</h4>
+						<p>{{.DateTime}}</p>
+					</div>
+					<p>こちらのリンクから変更を確定してください。リンクの確認が完了するまでメールアドレスは変更されません。</p>
+						{{.Link}}
+					<p>お心当たりがない場合は、このメールを破棄してください。</p>
+					{{template "Support"}}
+				</div>
+				{{template "Footer" .}}
+			</div>
+		</body>
+	</html>
+`))
+
+var emailChangeNoticeTemplate = template.Must(template.Must(commonTemplate.Clone()).Parse(`
+	{{template "Style"}}
+	<!DOCTYPE html>
+	<html>
+		<head>
+			<title>メールアドレス変更のお知らせ</title>
+		</head>
+		<body>
+			<div class="container">
+				<div class="header">
+					たくわえる<br>
+					メールアドレス変更のお知らせ
+				</div>
+				<div class="body">
+					<p>いつもたくわえるをご利用いただき、誠にありがとうございます。</p>
+					<p>登録メールアドレスを変更する手続きを受け付けました。新しいメールアドレスで確認が完了すると、こちらのメールアドレスではサインインできなくなります。</p>
+
+					<div class="info-section">
+						<h4>新しいメールアドレス</h4>
+						<p>{{.UserEmail}}</p>
+						<h4>受付日時</h4>
+						<p>{{.DateTime}}</p>
+					</div>
+					<p>お心当たりがない場合は、こちらのリンクから変更を取り消してください。変更の確定後も一定期間は取り消すことができます。</p>
+						{{.Link}}
+					{{template "Support"}}
+				</div>
+				{{template "Footer" .}}
+			</div>
+		</body>
+	</html>
+`))
+
+var emailChangeCanceledTemplate = template.Must(template.Must(commonTemplate.Clone()).Parse(`
+	{{template "Style"}}
+	<!DOCTYPE html>
+	<html>
+		<head>
+			<title>メールアドレス変更の取り消し</title>
+		</head>
+		<body>
+			<div class="container">
+				<div class="header">
+					たくわえる<br>
+					メールアドレス変更の取り消し
+				</div>
+				<div class="body">
+					<p>いつもたくわえるをご利用いただき、誠にありがとうございます。</p>
+					<p>メールアドレスの変更を取り消しました。安全のため、全ての端末からサインアウトしました。</p>
+
+					<div class="info-section">
+						<h4>登録メールアドレス</h4>
+						<p>{{.UserEmail}}</p>
+						<h4>取り消し日時</h4>
+						<p>{{.DateTime}}</p>
+					</div>
+					<p>第三者による操作の可能性があるため、パスワードの変更をお勧めします。</p>
+					{{template "Support"}}
+				</div>
+				{{template "Footer" .}}
+			</div>
+		</body>
+	</html>
+`))
+
 var deleteSignInTemplate = template.Must(template.Must(commonTemplate.Clone()).Parse(`
 	{{template "Style"}}
 	<!DOCTYPE html>
@@ -263,6 +437,118 @@ var deleteSignInTemplate = template.Must(template.Must(commonTemplate.Clone()).P
 	</html>
 `))
 
+var accountDeactivatedTemplate = template.Must(template.Must(commonTemplate.Clone()).Parse(`
+	{{template "Style"}}
+	<!DOCTYPE html>
+	<html>
+		<head>
+			<title>退会受付通知</title>
+		</head>
+		<body>
+			<div class="container">
+				<div class="header">
+					たくわえる<br>
+					アカウント退会受付のお知らせ
+				</div>
+				<div class="body">
+					<p>{{.Name}}さん、この度はたくわえるをご利用いただき、誠にありがとうございました。</p>
+					<p>以下の内容でアカウントの退会を受け付けました。全ての端末からサインアウトしました。</p>
+
+					<div class="info-section">
+						<h4>退会ユーザ名</h4>
+						<p>{{.UserEmail}}</p>
+						<h4>受付日時</h4>
+						<p>{{.DateTime}}</p>
+						<h4>削除予定日時</h4>
+						<p>{{.PurgeDate}}</p>
+					</div>
+
+					<p>削除予定日時を過ぎると、アカウントと関連するすべてのデータを削除します。</p>
+					<p>削除予定日時までは、こちらのリンクからアカウントを復元できます。</p>
+						{{.Link}}
+					<p>お心当たりがない場合は、至急アカウントを復元し、パスワードを変更してください。</p>
+					{{template "Support"}}
+				</div>
+				{{template "Footer" .}}
+			</div>
+		</body>
+	</html>
+`))
+
+var dataExportReadyTemplate = template.Must(template.Must(commonTemplate.Clone()).Parse(`
+	{{template "Style"}}
+	<!DOCTYPE html>
+	<html>
+		<head>
+			<title>データエクスポート完了通知</title>
+		</head>
+		<body>
+			<div class="container">
+				<div class="header">
+					たくわえる<br>
+					データエクスポート完了のお知らせ
+				</div>
+				<div class="body">
+					<p>ご依頼いただいた個人データのエクスポートが完了しました。</p>
+
+					<div class="info-section">
+						<h4>ユーザ名</h4>
+						<p>{{.UserEmail}}</p>
+						<h4>作成日時</h4>
+						<p>{{.DateTime}}</p>
+						<h4>ダウンロード期限</h4>
+						<p>{{.ExpireDate}}</p>
+					</div>
+
+					<p>サインインした状態で、こちらのリンクからダウンロードしてください。</p>
+						{{.Link}}
+					<p>ダウンロード期限を過ぎるとファイルは削除されます。再度エクスポートを依頼してください。</p>
+					<p>お心当たりがない場合は、至急パスワードを変更してください。</p>
+					{{template "Support"}}
+				</div>
+				{{template "Footer" .}}
+			</div>
+		</body>
+	</html>
+`))
+
+var magicLinkTemplate = template.Must(template.Must(commonTemplate.Clone()).Parse(`
+	{{template "Style"}}
+	<!DOCTYPE html>
+	<html>
+		<head>
+			<title>サインイン用リンク</title>
+		</head>
+		<body>
+			<div class="container">
+				<div class="header">
+					たくわえる<br>
+					サインイン用リンクのお知らせ
+				</div>
+				<div class="body">
+					<p>パスワードを使わずにサインインするためのリンクをお送りします。</p>
+
+					<div class="info-section">
+						<h4>ユーザ名</h4>
+						<p>{{.UserEmail}}</p>
+						<h4>要求日時</h4>
+						<p>{{.DateTime}}</p>
+						<h4>有効期限</h4>
+						<p>{{.ExpireDate}}</p>
+					</div>
+
+					<p>リンクを要求したブラウザで、こちらのリンクを開いてください。</p>
+						{{.Link}}
+					<p>リンクは1回のみ使用できます。有効期限を過ぎた場合は再度リンクを要求してください。</p>
+					<p>このリンクは他の方に転送しないでください。お心当たりがない場合は、このメールを破棄してください。</p>
+					{{template "Support"}}
+				</div>
+				{{template "Footer" .}}
+			</div>
+		</body>
+	</html>
+`))
+
 var registerEmailCheckNoticeTemplate = template.Must(template.Must(commonTemplate.Clone()).Parse(`
 	{{template "Style"}}
 	<!DOCTYPE html>
@@ -300,17 +586,16 @@ var newPasswordUpdateTemplate = template.Must(template.Must(commonTemplate.Clone
 					パスワード再発行成功
 				</div>
 				<div class="body">
-					<p>こちらが新しいパスワードです。</p>
+					<p>パスワードの再設定が完了しました。</p>
 
 					<div class="info-section">
-						<h4>新しいパスワード</h4>
-						<p>{{.NewPassword}}</p>
 						<h4>更新日時</h4>
 						<p>{{.DateTime}}</p>
 					</div>
 						<p>
 							こちらはご登録ユーザーでパスワード再発行した際に通知されます。<br>
-							今後、サインインされる場合はこちらのパスワードをご使用ください。
+							セキュリティのため、全ての端末からサインアウトしました。新しいパスワードで再度サインインしてください。<br>
+							お心当たりがない場合は、至急パスワードを再設定してください。
 						</p>
 					{{template "Support"}}
 				</div>
@@ -375,7 +660,7 @@ func (et *EmailTemplateManager) PostSignUpTemplate(Name, UserEmail, DateTime str
 	subject := "【たくわえる】登録を完了致しました"
 	// メールテンプレート定義
 
-	var year = utils.NewUtilsFetcher(utils.JwtSecret).DateTimeStr(time.Now(), "2006年")
+	var year = utils.NewUtilsFetcher(utils.JwtKeys).DateTimeStr(time.Now(), "2006年")
 
 	// テンプレートに渡すデータを作成
 	data := GenericEmailData{
@@ -398,7 +683,7 @@ func (et *EmailTemplateManager) PostSignInEditTemplate(Update, UpdateValue, Date
 	subject := "【たくわえる】登録情報編集致しました"
 	// メールテンプレート定義
 
-	var year = utils.NewUtilsFetcher(utils.JwtSecret).DateTimeStr(time.Now(), "2006年")
+	var year = utils.NewUtilsFetcher(utils.JwtKeys).DateTimeStr(time.Now(), "2006年")
 
 	// テンプレートに渡すデータを作成
 	data := GenericEmailData{
@@ -419,7 +704,7 @@ func (et *EmailTemplateManager) PostSignInEditTemplate(Update, UpdateValue, Date
 
 func (et *EmailTemplateManager) PostSignInTemplate(UserEmail, DateTime string) (string, string, error) {
 	subject := "【たくわえる】サインイン致しました"
-	var year = utils.NewUtilsFetcher(utils.JwtSecret).DateTimeStr(time.Now(), "2006年")
+	var year = utils.NewUtilsFetcher(utils.JwtKeys).DateTimeStr(time.Now(), "2006年")
 	// メールテンプレート定義
 
 	// テンプレートに渡すデータを作成
@@ -440,7 +725,7 @@ func (et *EmailTemplateManager) PostSignInTemplate(UserEmail, DateTime string) (
 
 func (et *EmailTemplateManager) DeleteSignInTemplate(Name, UserEmail, DateTime string) (string, string, error) {
 	subject := "【たくわえる】アカウント削除完了のお知らせ"
-	var year = utils.NewUtilsFetcher(utils.JwtSecret).DateTimeStr(time.Now(), "2006年")
+	var year = utils.NewUtilsFetcher(utils.JwtKeys).DateTimeStr(time.Now(), "2006年")
 	// メールテンプレート定義
 
 	// テンプレートに渡すデータを作成
@@ -462,7 +747,7 @@ func (et *EmailTemplateManager) DeleteSignInTemplate(Name, UserEmail, DateTime s
 
 func (et *EmailTemplateManager) SignOutTemplate(UserEmail, DateTime string) (string, string, error) {
 	subject := "【たくわえる】サインアウトのお知らせ"
-	var year = utils.NewUtilsFetcher(utils.JwtSecret).DateTimeStr(time.Now(), "2006年")
+	var year = utils.NewUtilsFetcher(utils.JwtKeys).DateTimeStr(time.Now(), "2006年")
 	// メールテンプレート定義
 
 	// テンプレートに渡すデータを作成
@@ -483,7 +768,7 @@ func (et *EmailTemplateManager) SignOutTemplate(UserEmail, DateTime string) (str
 
 func (et *EmailTemplateManager) RegisterEmailCheckNoticeTemplate(Link, DateTime string) (string, string, error) {
 	subject := "【たくわえる】パスワード再設定通知のお知らせ"
-	var year = utils.NewUtilsFetcher(utils.JwtSecret).DateTimeStr(time.Now(), "2006年")
+	var year = utils.NewUtilsFetcher(utils.JwtKeys).DateTimeStr(time.Now(), "2006年")
 	// メールテンプレート定義
 
 	// テンプレートに渡すデータを作成
@@ -502,16 +787,15 @@ func (et *EmailTemplateManager) RegisterEmailCheckNoticeTemplate(Link, DateTime
 	return subject, body.String(), nil
 }
 
-func (et *EmailTemplateManager) NewPasswordUpdateTemplate(NewPassword, DateTime string) (string, string, error) {
+func (et *EmailTemplateManager) NewPasswordUpdateTemplate(DateTime string) (string, string, error) {
 	subject := "【たくわえる】パスワード再発行成功のお知らせ"
-	var year = utils.NewUtilsFetcher(utils.JwtSecret).DateTimeStr(time.Now(), "2006年")
+	var year = utils.NewUtilsFetcher(utils.JwtKeys).DateTimeStr(time.Now(), "2006年")
 	// メールテンプレート定義
 
 	// テンプレートに渡すデータを作成
 	data := GenericEmailData{
-		NewPassword: NewPassword,
-		DateTime:    DateTime,
-		Year:        year,
+		DateTime: DateTime,
+		Year:     year,
 	}
 
 	// テンプレートの実行と結果の取得
@@ -522,3 +806,173 @@ func (et *EmailTemplateManager) NewPasswordUpdateTemplate(NewPassword, DateTime
 
 	return subject, body.String(), nil
 }
+
+func (et *EmailTemplateManager) RefreshTokenReuseTemplate(UserEmail, DateTime string) (string, string, error) {
+	subject := "【たくわえる】不正なアクセスの可能性を検知しました"
+	var year = utils.NewUtilsFetcher(utils.JwtKeys).DateTimeStr(time.Now(), "2006年")
+
+	// テンプレートに渡すデータを作成
+	data := GenericEmailData{
+		UserEmail: UserEmail,
+		DateTime:  DateTime,
+		Year:      year,
+	}
+
+	// テンプレートの実行と結果の取得
+	var body bytes.Buffer
+	if err := refreshTokenReuseTemplate.Execute(&body, data); err != nil {
+		return "", "", err // エラー時に空の件名と本文を返す
+	}
+
+	return subject, body.String(), nil
+}
+
+func (et *EmailTemplateManager) AccountLockedTemplate(UserEmail, Link, DateTime string) (string, string, error) {
+	subject := "【たくわえる】アカウントを一時的にロックしました"
+	var year = utils.NewUtilsFetcher(utils.JwtKeys).DateTimeStr(time.Now(), "2006年")
+
+	// テンプレートに渡すデータを作成
+	data := GenericEmailData{
+		UserEmail: UserEmail,
+		Link:      Link,
+		DateTime:  DateTime,
+		Year:      year,
+	}
+
+	// テンプレートの実行と結果の取得
+	var body bytes.Buffer
+	if err := accountLockedTemplate.Execute(&body, data); err != nil {
+		return "", "", err // エラー時に空の件名と本文を返す
+	}
+
+	return subject, body.String(), nil
+}
+
+func (et *EmailTemplateManager) EmailChangeConfirmTemplate(UserEmail, Link, DateTime string) (string, string, error) {
+	subject := "【たくわえる】メールアドレス変更の確認"
+	var year = utils.NewUtilsFetcher(utils.JwtKeys).DateTimeStr(time.Now(), "2006年")
+
+	// テンプレートに渡すデータを作成
+	data := GenericEmailData{
+		UserEmail: UserEmail,
+		Link:      Link,
+		DateTime:  DateTime,
+		Year:      year,
+	}
+
+	// テンプレートの実行と結果の取得
+	var body bytes.Buffer
+	if err := emailChangeConfirmTemplate.Execute(&body, data); err != nil {
+		return "", "", err // エラー時に空の件名と本文を返す
+	}
+
+	return subject, body.String(), nil
+}
+
+func (et *EmailTemplateManager) EmailChangeNoticeTemplate(UserEmail, Link, DateTime string) (string, string, error) {
+	subject := "【たくわえる】メールアドレス変更のお知らせ"
+	var year = utils.NewUtilsFetcher(utils.JwtKeys).DateTimeStr(time.Now(), "2006年")
+
+	// テンプレートに渡すデータを作成
+	data := GenericEmailData{
+		UserEmail: UserEmail,
+		Link:      Link,
+		DateTime:  DateTime,
+		Year:      year,
+	}
+
+	// テンプレートの実行と結果の取得
+	var body bytes.Buffer
+	if err := emailChangeNoticeTemplate.Execute(&body, data); err != nil {
+		return "", "", err // エラー時に空の件名と本文を返す
+	}
+
+	return subject, body.String(), nil
+}
+
+func (et *EmailTemplateManager) EmailChangeCanceledTemplate(UserEmail, DateTime string) (string, string, error) {
+	subject := "【たくわえる】メールアドレス変更を取り消しました"
+	var year = utils.NewUtilsFetcher(utils.JwtKeys).DateTimeStr(time.Now(), "2006年")
+
+	// テンプレートに渡すデータを作成
+	data := GenericEmailData{
+		UserEmail: UserEmail,
+		DateTime:  DateTime,
+		Year:      year,
+	}
+
+	// テンプレートの実行と結果の取得
+	var body bytes.Buffer
+	if err := emailChangeCanceledTemplate.Execute(&body, data); err != nil {
+		return "", "", err // エラー時に空の件名と本文を返す
+	}
+
+	return subject, body.String(), nil
+}
+
+func (et *EmailTemplateManager) AccountDeactivatedTemplate(Name, UserEmail, Link, PurgeDate, DateTime string) (string, string, error) {
+	subject := "【たくわえる】アカウント退会受付のお知らせ"
+	var year = utils.NewUtilsFetcher(utils.JwtKeys).DateTimeStr(time.Now(), "2006年")
+
+	// テンプレートに渡すデータを作成
+	data := GenericEmailData{
+		Name:      Name,
+		UserEmail: UserEmail,
+		Link:      Link,
+		PurgeDate: PurgeDate,
+		DateTime:  DateTime,
+		Year:      year,
+	}
+
+	// テンプレートの実行と結果の取得
+	var body bytes.Buffer
+	if err := accountDeactivatedTemplate.Execute(&body, data); err != nil {
+		return "", "", err // エラー時に空の件名と本文を返す
+	}
+
+	return subject, body.String(), nil
+}
+
+func (et *EmailTemplateManager) DataExportReadyTemplate(UserEmail, Link, ExpireDate, DateTime string) (string, string, error) {
+	subject := "【たくわえる】データエクスポート完了のお知らせ"
+	var year = utils.NewUtilsFetcher(utils.JwtKeys).DateTimeStr(time.Now(), "2006年")
+
+	// テンプレートに渡すデータを作成
+	data := GenericEmailData{
+		UserEmail:  UserEmail,
+		Link:       Link,
+		ExpireDate: ExpireDate,
+		DateTime:   DateTime,
+		Year:       year,
+	}
+
+	// テンプレートの実行と結果の取得
+	var body bytes.Buffer
+	if err := dataExportReadyTemplate.Execute(&body, data); err != nil {
+		return "", "", err // エラー時に空の件名と本文を返す
+	}
+
+	return subject, body.String(), nil
+}
+
+func (et *EmailTemplateManager) MagicLinkTemplate(UserEmail, Link, ExpireDate, DateTime string) (string, string, error) {
+	subject := "【たくわえる】サインイン用リンクのお知らせ"
+	var year = utils.NewUtilsFetcher(utils.JwtKeys).DateTimeStr(time.Now(), "2006年")
+
+	// テンプレートに渡すデータを作成
+	data := GenericEmailData{
+		UserEmail:  UserEmail,
+		Link:       Link,
+		ExpireDate: ExpireDate,
+		DateTime:   DateTime,
+		Year:       year,
+	}
+
+	// テンプレートの実行と結果の取得
+	var body bytes.Buffer
+	if err := magicLinkTemplate.Execute(&body, data); err != nil {
+		return "", "", err // エラー時に空の件名と本文を返す
+	}
+
+	return subject, body.String(), nil
+}
@@ -0,0 +1,294 @@
+// test_utils/fake_oidc.go
+package test_utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"server/utils"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type (
+	// FakeOIDCServer はテスト用の外部認証(OpenID Connect)サーバー
+	// 認可リクエストで受け取ったPKCEのチャレンジとnonceを認可コードに紐づけ、
+	// トークンリクエストでコードベリファイアを検証してから署名済みのIDトークンを返す
+	// ディスカバリードキュメント・UserInfo・トークン無効化のエンドポイントを持つ
+	FakeOIDCServer struct {
+		*httptest.Server
+		ClientId string
+		Key      *rsa.PrivateKey
+		Kid      string
+		// 設定した場合はIDトークンを共有鍵(HS256)で署名する(LINEのチャネルシークレット)
+		SigningSecret []byte
+
+		// IDトークンのユーザー情報
+		Subject       string
+		Email         string
+		EmailVerified bool
+		Name          string
+		// IDトークンのクレームを上書きする(不正なIDトークンのテスト用)
+		OverrideClaims jwt.MapClaims
+		// UserInfoエンドポイントが返す表示名
+		UserInfoName string
+
+		mu            sync.Mutex
+		codes         map[string]fakeAuthRequest
+		JwksRequests  int
+		TokenRequests []url.Values
+		// 無効化したアクセストークン
+		RevokedTokens []string
+		// 設定した場合はトークン無効化でこのステータスコードを返す(無効化エラーのテスト用)
+		RevokeStatus int
+	}
+
+	fakeAuthRequest struct {
+		codeChallenge string
+		nonce         string
+		redirectURI   string
+	}
+)
+
+// NewFakeOIDCServer はテスト用の外部認証サーバーを起動する
+// テスト終了時に Close すること
+func NewFakeOIDCServer(clientId string) *FakeOIDCServer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	fake := &FakeOIDCServer{
+		ClientId:      clientId,
+		Key:           key,
+		Kid:           "fake-key-1",
+		Subject:       "109876543210",
+		Email:         "test@example.com",
+		EmailVerified: true,
+		Name:          "test",
+		codes:         map[string]fakeAuthRequest{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", fake.discovery)
+	mux.HandleFunc("/authorize", fake.authorize)
+	mux.HandleFunc("/token", fake.token)
+	mux.HandleFunc("/certs", fake.certs)
+	mux.HandleFunc("/userinfo", fake.userInfo)
+	mux.HandleFunc("/revoke", fake.revoke)
+	fake.Server = httptest.NewServer(mux)
+	return fake
+}
+
+// Issuer はIDトークンの発行者(iss)
+func (fake *FakeOIDCServer) Issuer() string {
+	return fake.URL
+}
+
+// JwksURL は公開鍵(JWKS)のURL
+func (fake *FakeOIDCServer) JwksURL() string {
+	return fake.URL + "/certs"
+}
+
+// Authorize は認可URLにアクセスし、リダイレクト先に渡される認可コードとstateを返す
+func (fake *FakeOIDCServer) Authorize(authURL string) (string, string, error) {
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		return "", "", fmt.Errorf("認可リクエストエラー: ステータスコード %d", resp.StatusCode)
+	}
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return "", "", err
+	}
+	return location.Query().Get("code"), location.Query().Get("state"), nil
+}
+
+// SignIdToken はテスト用の鍵でIDトークンに署名する
+func (fake *FakeOIDCServer) SignIdToken(claims jwt.MapClaims) string {
+	var signed string
+	var err error
+	if len(fake.SigningSecret) > 0 {
+		signed, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(fake.SigningSecret)
+	} else {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = fake.Kid
+		signed, err = token.SignedString(fake.Key)
+	}
+	if err != nil {
+		panic(err)
+	}
+	return signed
+}
+
+func (fake *FakeOIDCServer) discovery(w http.ResponseWriter, r *http.Request) {
+	algs := []string{"RS256"}
+	if len(fake.SigningSecret) > 0 {
+		algs = []string{"HS256"}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"issuer":                                fake.Issuer(),
+		"authorization_endpoint":                fake.URL + "/authorize",
+		"token_endpoint":                        fake.URL + "/token",
+		"jwks_uri":                              fake.JwksURL(),
+		"userinfo_endpoint":                     fake.URL + "/userinfo",
+		"revocation_endpoint":                   fake.URL + "/revoke",
+		"id_token_signing_alg_values_supported": algs,
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (fake *FakeOIDCServer) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != fake.ClientId || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+
+	codeBytes := make([]byte, 16)
+	_, _ = rand.Read(codeBytes)
+	code := base64.RawURLEncoding.EncodeToString(codeBytes)
+	fake.mu.Lock()
+	fake.codes[code] = fakeAuthRequest{
+		codeChallenge: query.Get("code_challenge"),
+		nonce:         query.Get("nonce"),
+		redirectURI:   query.Get("redirect_uri"),
+	}
+	fake.mu.Unlock()
+
+	redirect, _ := url.Parse(query.Get("redirect_uri"))
+	values := redirect.Query()
+	values.Set("code", code)
+	values.Set("state", query.Get("state"))
+	redirect.RawQuery = values.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (fake *FakeOIDCServer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+
+	fake.mu.Lock()
+	fake.TokenRequests = append(fake.TokenRequests, r.PostForm)
+	request, ok := fake.codes[r.PostForm.Get("code")]
+	// 認可コードは1回のみ使用できる
+	delete(fake.codes, r.PostForm.Get("code"))
+	fake.mu.Unlock()
+
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok ||
+		base64.RawURLEncoding.EncodeToString(verifier[:]) != request.codeChallenge ||
+		r.PostForm.Get("redirect_uri") != request.redirectURI {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            fake.Issuer(),
+		"aud":            fake.ClientId,
+		"sub":            fake.Subject,
+		"email":          fake.Email,
+		"email_verified": fake.EmailVerified,
+		"name":           fake.Name,
+		"nonce":          request.nonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+	}
+	for key, value := range fake.OverrideClaims {
+		claims[key] = value
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "fake-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     fake.SignIdToken(claims),
+	})
+}
+
+func (fake *FakeOIDCServer) certs(w http.ResponseWriter, r *http.Request) {
+	fake.mu.Lock()
+	fake.JwksRequests++
+	fake.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	_ = json.NewEncoder(w).Encode(utils.JSONWebKeySet{
+		Keys: []utils.JSONWebKey{
+			{
+				Kty: "RSA",
+				Use: "sig",
+				Alg: "RS256",
+				Kid: fake.Kid,
+				N:   base64.RawURLEncoding.EncodeToString(fake.Key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(fake.Key.E)).Bytes()),
+			},
+		},
+	})
+}
+
+func (fake *FakeOIDCServer) userInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer fake-access-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"sub":   fake.Subject,
+		"email": fake.Email,
+		"name":  fake.UserInfoName,
+	})
+}
+
+// revoke はRFC 7009の token とLINEの access_token のどちらでもアクセストークンを受け付ける
+func (fake *FakeOIDCServer) revoke(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("client_id") != fake.ClientId {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+	token := r.PostForm.Get("token")
+	if token == "" {
+		token = r.PostForm.Get("access_token")
+	}
+	if token == "" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+
+	if fake.RevokeStatus != 0 {
+		http.Error(w, "server_error", fake.RevokeStatus)
+		return
+	}
+
+	fake.mu.Lock()
+	fake.RevokedTokens = append(fake.RevokedTokens, token)
+	fake.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
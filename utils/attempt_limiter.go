@@ -0,0 +1,276 @@
+// utils/attempt_limiter.go
+package utils
+
+import (
+	"fmt"
+	"server/config"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// AttemptLimiter は認証の失敗回数を記録し、総当たり攻撃を制限する
+	AttemptLimiter interface {
+		Check(keys ...AttemptKey) (time.Duration, error)
+		Fail(key AttemptKey) (AttemptStatus, error)
+		Reset(keys ...AttemptKey) error
+	}
+
+	// AttemptPolicy は失敗回数に応じた待機時間とロックの設定
+	AttemptPolicy struct {
+		// ロックするまでの失敗回数
+		Threshold int64
+		// 1回目の失敗後の待機時間(失敗する度に2倍にする)
+		BaseBackoff time.Duration
+		// ロック前の待機時間の上限
+		MaxBackoff time.Duration
+		// ロック期間(失敗回数もこの期間保持する)
+		Lockout time.Duration
+	}
+
+	// AttemptKey は失敗回数を数える対象(アカウント・IPアドレス等)
+	AttemptKey struct {
+		Key    string
+		Policy AttemptPolicy
+	}
+
+	// AttemptStatus は失敗を記録した後の状態
+	AttemptStatus struct {
+		Failures int64
+		Wait     time.Duration
+		Locked   bool
+		// 今回の失敗でロックされた場合はtrue
+		JustLocked bool
+	}
+
+	AttemptLimitManager struct {
+		RedisService config.RedisService
+	}
+)
+
+var (
+	// アカウント単位のサインイン試行の制限
+	AccountAttemptPolicy = AttemptPolicy{
+		Threshold:   5,
+		BaseBackoff: time.Second,
+		MaxBackoff:  30 * time.Second,
+		Lockout:     30 * time.Minute,
+	}
+	// IPアドレス単位の制限(複数アカウントへの試行を考慮して閾値を高くする)
+	IPAttemptPolicy = AttemptPolicy{
+		Threshold:   20,
+		BaseBackoff: time.Second,
+		MaxBackoff:  30 * time.Second,
+		Lockout:     30 * time.Minute,
+	}
+	// メール認証コード(4桁)の制限
+	// 仮登録の有効期限(1時間)の間はロックを保持する
+	ConfirmCodeAttemptPolicy = AttemptPolicy{
+		Threshold:   5,
+		BaseBackoff: time.Second,
+		MaxBackoff:  30 * time.Second,
+		Lockout:     time.Hour,
+	}
+	// メール認証コードの再送信の制限
+	// 失敗ではなく送信の度に数え、同じ仮登録へのメールの連続送信を防ぐ
+	AuthEmailRetryPolicy = AttemptPolicy{
+		Threshold:   5,
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  5 * time.Minute,
+		Lockout:     time.Hour,
+	}
+	// マジックリンクの送信の制限
+	// 失敗ではなく送信の度に数え、同じアカウントへのメールの連続送信を防ぐ
+	MagicLinkRequestPolicy = AttemptPolicy{
+		Threshold:   5,
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  5 * time.Minute,
+		Lockout:     time.Hour,
+	}
+)
+
+func NewAttemptLimiter(RedisService config.RedisService) AttemptLimiter {
+	return &AttemptLimitManager{
+		RedisService: RedisService,
+	}
+}
+
+// SignInAccountAttemptKey はサインインのアカウント単位の試行キー
+func SignInAccountAttemptKey(UserEmail string) AttemptKey {
+	return AttemptKey{
+		Key:    "signin:account:" + strings.ToLower(UserEmail),
+		Policy: AccountAttemptPolicy,
+	}
+}
+
+// SignInIPAttemptKey はサインインのIPアドレス単位の試行キー
+func SignInIPAttemptKey(ip string) AttemptKey {
+	return AttemptKey{
+		Key:    "signin:ip:" + ip,
+		Policy: IPAttemptPolicy,
+	}
+}
+
+// ConfirmCodeAttemptKey は仮登録したメールアドレス単位のメール認証コードの試行キー
+// 認証コードの再送信で仮登録のIDが変わっても失敗回数を引き継ぐ
+func ConfirmCodeAttemptKey(UserEmail string) AttemptKey {
+	return AttemptKey{
+		Key:    "confirm_code:account:" + strings.ToLower(UserEmail),
+		Policy: ConfirmCodeAttemptPolicy,
+	}
+}
+
+// ConfirmCodeIPAttemptKey はメール認証コードのIPアドレス単位の試行キー
+func ConfirmCodeIPAttemptKey(ip string) AttemptKey {
+	return AttemptKey{
+		Key:    "confirm_code:ip:" + ip,
+		Policy: IPAttemptPolicy,
+	}
+}
+
+// TotpAttemptKey は二段階認証のユーザー単位の試行キー
+// サインインをやり直して総当たりされないよう、チャレンジ毎ではなくユーザー毎に数える
+func TotpAttemptKey(UserId int) AttemptKey {
+	return AttemptKey{
+		Key:    fmt.Sprintf("totp:user:%d", UserId),
+		Policy: AccountAttemptPolicy,
+	}
+}
+
+// MagicLinkAccountAttemptKey はマジックリンクの送信のアカウント単位の試行キー
+func MagicLinkAccountAttemptKey(UserEmail string) AttemptKey {
+	return AttemptKey{
+		Key:    "magic_link:account:" + strings.ToLower(UserEmail),
+		Policy: MagicLinkRequestPolicy,
+	}
+}
+
+// MagicLinkIPAttemptKey はマジックリンクの送信のIPアドレス単位の試行キー
+func MagicLinkIPAttemptKey(ip string) AttemptKey {
+	return AttemptKey{
+		Key:    "magic_link:ip:" + ip,
+		Policy: IPAttemptPolicy,
+	}
+}
+
+// AuthEmailRetryAccountAttemptKey はメール認証コードの再送信のメールアドレス単位の試行キー
+func AuthEmailRetryAccountAttemptKey(UserEmail string) AttemptKey {
+	return AttemptKey{
+		Key:    "auth_email_retry:account:" + strings.ToLower(UserEmail),
+		Policy: AuthEmailRetryPolicy,
+	}
+}
+
+// AuthEmailRetryIPAttemptKey はメール認証コードの再送信のIPアドレス単位の試行キー
+func AuthEmailRetryIPAttemptKey(ip string) AttemptKey {
+	return AttemptKey{
+		Key:    "auth_email_retry:ip:" + ip,
+		Policy: IPAttemptPolicy,
+	}
+}
+
+// attemptFailKey は失敗回数を保存するRedisのキー
+func attemptFailKey(key AttemptKey) string {
+	return fmt.Sprintf("attempt_fail:%s", key.Key)
+}
+
+// attemptWaitKey は次に試行できる日時(unix時間)を保存するRedisのキー
+func attemptWaitKey(key AttemptKey) string {
+	return fmt.Sprintf("attempt_wait:%s", key.Key)
+}
+
+// backoff は失敗回数に応じた待機時間を返す
+func (p AttemptPolicy) backoff(failures int64) time.Duration {
+	wait := p.BaseBackoff
+	for i := int64(1); i < failures && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, p.MaxBackoff)
+}
+
+// Check は試行を受け付けるまでの残りの待機時間を返す
+// 複数のキーが指定された場合は最も長い待機時間を返し、待機不要の場合は0を返す
+//
+// 引数:
+//   - keys: 試行キー
+//
+// 戻り値:
+//
+//	戻り値1: 残りの待機時間
+//	戻り値2: Redisの参照に失敗した場合のエラー
+//
+
+func (am *AttemptLimitManager) Check(keys ...AttemptKey) (time.Duration, error) {
+	var wait time.Duration
+	for _, key := range keys {
+		exists, err := am.RedisService.RedisExists(attemptWaitKey(key))
+		if err != nil {
+			return 0, err
+		}
+		if !exists {
+			continue
+		}
+		value, err := am.RedisService.RedisGet(attemptWaitKey(key))
+		if err != nil {
+			return 0, err
+		}
+		until, _ := strconv.ParseInt(value, 10, 64)
+		wait = max(wait, time.Until(time.Unix(until, 0)))
+	}
+	return wait, nil
+}
+
+// Fail は失敗回数を加算し、回数に応じて次の試行までの待機時間を設定する
+// 失敗回数が閾値に達した場合はロック期間の間試行を受け付けない
+//
+// 引数:
+//   - key: 試行キー
+//
+// 戻り値:
+//
+//	戻り値1: 失敗を記録した後の状態
+//	戻り値2: Redisの更新に失敗した場合のエラー
+//
+
+func (am *AttemptLimitManager) Fail(key AttemptKey) (AttemptStatus, error) {
+	failures, err := am.RedisService.RedisIncr(attemptFailKey(key), key.Policy.Lockout)
+	if err != nil {
+		return AttemptStatus{}, err
+	}
+
+	status := AttemptStatus{
+		Failures: failures,
+		Wait:     key.Policy.backoff(failures),
+	}
+	if failures >= key.Policy.Threshold {
+		status.Wait = key.Policy.Lockout
+		status.Locked = true
+		status.JustLocked = failures == key.Policy.Threshold
+	}
+
+	until := strconv.FormatInt(time.Now().Add(status.Wait).Unix(), 10)
+	if err := am.RedisService.RedisSet(attemptWaitKey(key), until, status.Wait); err != nil {
+		return AttemptStatus{}, err
+	}
+	return status, nil
+}
+
+// Reset は失敗回数と待機時間を削除する(ロックの解除)
+func (am *AttemptLimitManager) Reset(keys ...AttemptKey) error {
+	for _, key := range keys {
+		for _, redisKey := range []string{attemptFailKey(key), attemptWaitKey(key)} {
+			exists, err := am.RedisService.RedisExists(redisKey)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				continue
+			}
+			if err := am.RedisService.RedisDel(redisKey); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
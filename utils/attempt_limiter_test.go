@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"fmt"
+	mock_config "server/mock/config"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var testAttemptKey = AttemptKey{
+	Key: "test",
+	Policy: AttemptPolicy{
+		Threshold:   3,
+		BaseBackoff: time.Second,
+		MaxBackoff:  4 * time.Second,
+		Lockout:     time.Minute,
+	},
+}
+
+func TestAttemptPolicyBackoff(t *testing.T) {
+	policy := AttemptPolicy{BaseBackoff: time.Second, MaxBackoff: 30 * time.Second}
+
+	assert.Equal(t, time.Second, policy.backoff(1))
+	assert.Equal(t, 2*time.Second, policy.backoff(2))
+	assert.Equal(t, 16*time.Second, policy.backoff(5))
+	// 上限を超えない
+	assert.Equal(t, 30*time.Second, policy.backoff(6))
+	assert.Equal(t, 30*time.Second, policy.backoff(100))
+}
+
+func TestAttemptLimiterCheck(t *testing.T) {
+	t.Run("Check 待機不要", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisExists("attempt_wait:test").Return(false, nil)
+
+		wait, err := NewAttemptLimiter(mockRedisService).Check(testAttemptKey)
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), wait)
+	})
+
+	t.Run("Check 最も長い待機時間を返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		otherKey := AttemptKey{Key: "other", Policy: testAttemptKey.Policy}
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisExists("attempt_wait:test").Return(true, nil)
+		mockRedisService.EXPECT().
+			RedisGet("attempt_wait:test").
+			Return(strconv.FormatInt(time.Now().Add(10*time.Second).Unix(), 10), nil)
+		mockRedisService.EXPECT().RedisExists("attempt_wait:other").Return(true, nil)
+		mockRedisService.EXPECT().
+			RedisGet("attempt_wait:other").
+			Return(strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10), nil)
+
+		wait, err := NewAttemptLimiter(mockRedisService).Check(testAttemptKey, otherKey)
+
+		assert.NoError(t, err)
+		assert.InDelta(t, time.Minute.Seconds(), wait.Seconds(), 1)
+	})
+
+	t.Run("Check Redisエラー", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisExists("attempt_wait:test").Return(false, fmt.Errorf("存在確認エラー"))
+
+		_, err := NewAttemptLimiter(mockRedisService).Check(testAttemptKey)
+
+		assert.EqualError(t, err, "存在確認エラー")
+	})
+}
+
+func TestAttemptLimiterFail(t *testing.T) {
+	t.Run("Fail 閾値未満は失敗回数に応じて待機", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisIncr("attempt_fail:test", time.Minute).Return(int64(2), nil)
+		mockRedisService.EXPECT().RedisSet("attempt_wait:test", gomock.Any(), 2*time.Second).Return(nil)
+
+		status, err := NewAttemptLimiter(mockRedisService).Fail(testAttemptKey)
+
+		assert.NoError(t, err)
+		assert.Equal(t, AttemptStatus{Failures: 2, Wait: 2 * time.Second}, status)
+	})
+
+	t.Run("Fail 閾値に達するとロック", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisIncr("attempt_fail:test", time.Minute).Return(int64(3), nil)
+		mockRedisService.EXPECT().RedisSet("attempt_wait:test", gomock.Any(), time.Minute).Return(nil)
+
+		status, err := NewAttemptLimiter(mockRedisService).Fail(testAttemptKey)
+
+		assert.NoError(t, err)
+		assert.Equal(t, AttemptStatus{Failures: 3, Wait: time.Minute, Locked: true, JustLocked: true}, status)
+	})
+
+	t.Run("Fail Redisエラー", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisIncr("attempt_fail:test", time.Minute).Return(int64(0), fmt.Errorf("加算エラー"))
+
+		_, err := NewAttemptLimiter(mockRedisService).Fail(testAttemptKey)
+
+		assert.EqualError(t, err, "加算エラー")
+	})
+}
+
+func TestAttemptLimiterReset(t *testing.T) {
+	t.Run("Reset 存在するキーのみ削除", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisExists("attempt_fail:test").Return(true, nil)
+		mockRedisService.EXPECT().RedisDel("attempt_fail:test").Return(nil)
+		mockRedisService.EXPECT().RedisExists("attempt_wait:test").Return(false, nil)
+
+		err := NewAttemptLimiter(mockRedisService).Reset(testAttemptKey)
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,343 @@
+// utils/key_manager.go
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"server/config"
+	"sort"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type (
+	// JwtKey はトークンの署名・検証に使用する鍵
+	// 退役済みの鍵は SignKey を持たず、発行済みトークンの検証にのみ使用する
+	JwtKey struct {
+		Kid       string
+		Method    jwt.SigningMethod
+		SignKey   interface{}
+		VerifyKey interface{}
+	}
+
+	// KeyManager は署名鍵と検証鍵を鍵ID(kid)で管理する
+	// 署名には signingKid の鍵を使い、検証はトークンヘッダーの kid で鍵を選ぶ
+	KeyManager struct {
+		mu         sync.RWMutex
+		signingKid string
+		keys       map[string]*JwtKey
+	}
+
+	// jwtKeysFile は JWT_KEYS_FILE で指定する鍵設定ファイルの形式
+	//
+	//	{
+	//	  "signing_kid": "2024-07",
+	//	  "keys": [
+	//	    {"kid": "2024-07", "alg": "EdDSA", "private_key_file": "keys/2024-07.pem"},
+	//	    {"kid": "2024-01", "alg": "RS256", "public_key_file": "keys/2024-01.pub.pem"},
+	//	    {"kid": "default", "alg": "HS256", "secret_env": "JWT_SECRET"}
+	//	  ]
+	//	}
+	jwtKeysFile struct {
+		SigningKid string             `json:"signing_kid"`
+		Keys       []jwtKeysFileEntry `json:"keys"`
+	}
+
+	jwtKeysFileEntry struct {
+		Kid            string `json:"kid"`
+		Alg            string `json:"alg"`
+		SecretEnv      string `json:"secret_env"`       // HS256: 共有鍵を格納した環境変数名
+		PrivateKeyFile string `json:"private_key_file"` // RS256/EdDSA: PEM形式の秘密鍵
+		PublicKeyFile  string `json:"public_key_file"`  // RS256/EdDSA: 検証のみに使うPEM形式の公開鍵
+	}
+
+	// JSONWebKey はJWKSで公開する公開鍵(RFC 7517)
+	JSONWebKey struct {
+		Kty string `json:"kty"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+		N   string `json:"n,omitempty"`
+		E   string `json:"e,omitempty"`
+		Crv string `json:"crv,omitempty"`
+		X   string `json:"x,omitempty"`
+		Y   string `json:"y,omitempty"`
+	}
+
+	JSONWebKeySet struct {
+		Keys []JSONWebKey `json:"keys"`
+	}
+)
+
+// DefaultJwtKid は kid を持たない(鍵管理導入前の)トークンを検証する鍵ID
+// JWT_KEYS_FILE を指定しない場合は JWT_SECRET の HS256 鍵をこの鍵IDで登録する
+const DefaultJwtKid = "default"
+
+var ErrNoSigningKey = errors.New("署名鍵が設定されていません。")
+
+// JwtKeys はアプリケーション全体で使用する鍵
+// 環境変数の読み込み後に InitKeyManager で鍵を登録する
+var JwtKeys = &KeyManager{}
+
+// NewHMACKey は共有鍵によるHS256の鍵を作成する
+func NewHMACKey(kid string, secret []byte) *JwtKey {
+	return &JwtKey{Kid: kid, Method: jwt.SigningMethodHS256, SignKey: secret, VerifyKey: secret}
+}
+
+// NewRSAKey はRS256の署名鍵を作成する
+func NewRSAKey(kid string, privateKey *rsa.PrivateKey) *JwtKey {
+	return &JwtKey{Kid: kid, Method: jwt.SigningMethodRS256, SignKey: privateKey, VerifyKey: &privateKey.PublicKey}
+}
+
+// NewEd25519Key はEdDSAの署名鍵を作成する
+func NewEd25519Key(kid string, privateKey ed25519.PrivateKey) *JwtKey {
+	return &JwtKey{Kid: kid, Method: jwt.SigningMethodEdDSA, SignKey: privateKey, VerifyKey: privateKey.Public()}
+}
+
+// NewVerifyOnlyKey は検証のみに使う退役済みの鍵を作成する
+func NewVerifyOnlyKey(kid string, method jwt.SigningMethod, publicKey interface{}) *JwtKey {
+	return &JwtKey{Kid: kid, Method: method, VerifyKey: publicKey}
+}
+
+// NewKeyManager は鍵を登録したKeyManagerを作成する
+func NewKeyManager(signingKid string, keys ...*JwtKey) (*KeyManager, error) {
+	km := &KeyManager{}
+	if err := km.SetKeys(signingKid, keys...); err != nil {
+		return nil, err
+	}
+	return km, nil
+}
+
+// SetKeys は登録済みの鍵を置き換える
+// 鍵のローテーションでは、新しい署名鍵と共に旧鍵を検証用に残すことで発行済みトークンを有効なままにする
+//
+// 引数:
+//   - signingKid: 署名に使用する鍵ID
+//   - keys: 署名・検証に使用する鍵
+//
+// 戻り値:
+//
+//	戻り値1: エラー内容(エラーがない場合はnil)
+//
+
+func (km *KeyManager) SetKeys(signingKid string, keys ...*JwtKey) error {
+	keyMap := make(map[string]*JwtKey, len(keys))
+	for _, key := range keys {
+		if key.Kid == "" {
+			return errors.New("鍵IDが設定されていない鍵があります。")
+		}
+		if _, ok := keyMap[key.Kid]; ok {
+			return fmt.Errorf("鍵IDが重複しています: %s", key.Kid)
+		}
+		keyMap[key.Kid] = key
+	}
+
+	signingKey, ok := keyMap[signingKid]
+	if !ok || signingKey.SignKey == nil {
+		return fmt.Errorf("署名に使用する鍵が存在しません: %s", signingKid)
+	}
+
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	km.signingKid = signingKid
+	km.keys = keyMap
+	return nil
+}
+
+// SigningKid は署名に使用している鍵IDを返す
+func (km *KeyManager) SigningKid() string {
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+	return km.signingKid
+}
+
+// Sign はクレームに署名し、ヘッダーに kid を付与したトークンを返す
+func (km *KeyManager) Sign(claims jwt.Claims) (string, error) {
+	km.mu.RLock()
+	key := km.keys[km.signingKid]
+	km.mu.RUnlock()
+	if key == nil {
+		return "", ErrNoSigningKey
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.Kid
+	return token.SignedString(key.SignKey)
+}
+
+// Keyfunc はトークンヘッダーの kid から検証鍵を返す
+// 鍵と異なるアルゴリズムのトークンは受け付けない
+func (km *KeyManager) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		kid = DefaultJwtKid
+	}
+
+	km.mu.RLock()
+	key := km.keys[kid]
+	km.mu.RUnlock()
+	if key == nil {
+		return nil, fmt.Errorf("不明な鍵IDです: %s", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("署名アルゴリズムが鍵と一致しません: %s", token.Method.Alg())
+	}
+	return key.VerifyKey, nil
+}
+
+// Parse はトークンを検証してクレームを取得する
+func (km *KeyManager) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, km.Keyfunc)
+}
+
+// JWKS は登録済みの公開鍵をJWKSの形式で返す
+// HS256の共有鍵は公開しない
+func (km *KeyManager) JWKS() JSONWebKeySet {
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+
+	jwks := JSONWebKeySet{Keys: []JSONWebKey{}}
+	for _, key := range km.keys {
+		jwk := JSONWebKey{
+			Use: "sig",
+			Alg: key.Method.Alg(),
+			Kid: key.Kid,
+		}
+		switch publicKey := key.VerifyKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		default:
+			continue
+		}
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool {
+		return jwks.Keys[i].Kid < jwks.Keys[j].Kid
+	})
+	return jwks
+}
+
+// InitKeyManager は環境変数の読み込み後に JwtKeys へ鍵を登録する
+// JWT_KEYS_FILE が未指定の場合は JWT_SECRET によるHS256の鍵のみを登録する
+func InitKeyManager() error {
+	signingKid, keys, err := LoadJwtKeys(config.GlobalEnv.JwtKeysFile, config.GlobalEnv.JwtSecret)
+	if err != nil {
+		return err
+	}
+	return JwtKeys.SetKeys(signingKid, keys...)
+}
+
+// LoadJwtKeys は鍵設定ファイルから鍵を読み込む
+//
+// 引数:
+//   - path: 鍵設定ファイルのパス(空の場合は secret を使う)
+//   - secret: 鍵設定ファイルを使わない場合のHS256の共有鍵
+//
+// 戻り値:
+//
+//	戻り値1: 署名に使用する鍵ID
+//	戻り値2: 読み込んだ鍵
+//	戻り値3: エラー内容(エラーがない場合はnil)
+//
+
+func LoadJwtKeys(path, secret string) (string, []*JwtKey, error) {
+	if path == "" {
+		if secret == "" {
+			return "", nil, errors.New("JWT_SECRET又はJWT_KEYS_FILEが設定されていません。")
+		}
+		return DefaultJwtKid, []*JwtKey{NewHMACKey(DefaultJwtKid, []byte(secret))}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("鍵設定ファイルの読み込みエラー: %v", err)
+	}
+	var keysFile jwtKeysFile
+	if err := json.Unmarshal(data, &keysFile); err != nil {
+		return "", nil, fmt.Errorf("鍵設定ファイルの形式が不正です: %v", err)
+	}
+
+	keys := make([]*JwtKey, 0, len(keysFile.Keys))
+	for _, entry := range keysFile.Keys {
+		key, err := loadJwtKey(entry)
+		if err != nil {
+			return "", nil, fmt.Errorf("鍵(%s)の読み込みエラー: %v", entry.Kid, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keysFile.SigningKid, keys, nil
+}
+
+// loadJwtKey は鍵設定ファイルの1件分の鍵を読み込む
+func loadJwtKey(entry jwtKeysFileEntry) (*JwtKey, error) {
+	switch entry.Alg {
+	case jwt.SigningMethodHS256.Alg():
+		secret := os.Getenv(entry.SecretEnv)
+		if entry.SecretEnv == "" || secret == "" {
+			return nil, errors.New("共有鍵の環境変数が設定されていません。")
+		}
+		return NewHMACKey(entry.Kid, []byte(secret)), nil
+
+	case jwt.SigningMethodRS256.Alg():
+		if entry.PrivateKeyFile != "" {
+			pem, err := os.ReadFile(entry.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			return NewRSAKey(entry.Kid, privateKey), nil
+		}
+		pem, err := os.ReadFile(entry.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, err
+		}
+		return NewVerifyOnlyKey(entry.Kid, jwt.SigningMethodRS256, publicKey), nil
+
+	case jwt.SigningMethodEdDSA.Alg():
+		if entry.PrivateKeyFile != "" {
+			pem, err := os.ReadFile(entry.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			privateKey, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+			if err != nil {
+				return nil, err
+			}
+			return NewEd25519Key(entry.Kid, privateKey.(ed25519.PrivateKey)), nil
+		}
+		pem, err := os.ReadFile(entry.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		publicKey, err := jwt.ParseEdPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, err
+		}
+		return NewVerifyOnlyKey(entry.Kid, jwt.SigningMethodEdDSA, publicKey), nil
+
+	default:
+		return nil, fmt.Errorf("対応していない署名アルゴリズムです: %s", entry.Alg)
+	}
+}
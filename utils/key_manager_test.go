@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// testJwtKeys はテストで使用するHS256の鍵
+var testJwtKeys, _ = NewKeyManager(DefaultJwtKid, NewHMACKey(DefaultJwtKid, []byte("test-secret")))
+
+func testClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"UserId": 1,
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+// writePEM はテスト用の鍵をPEM形式で一時ディレクトリに書き出す
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("鍵ファイルの作成に失敗しました: %v", err)
+	}
+	return path
+}
+
+func TestKeyManagerSign(t *testing.T) {
+	t.Run("Sign ヘッダーに鍵IDが付与される", func(t *testing.T) {
+		_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+		km, err := NewKeyManager("ed-1", NewEd25519Key("ed-1", edKey))
+		assert.NoError(t, err)
+
+		tokenString, err := km.Sign(testClaims())
+		assert.NoError(t, err)
+
+		token, err := km.Parse(tokenString, jwt.MapClaims{})
+		assert.NoError(t, err)
+		assert.Equal(t, "ed-1", token.Header["kid"])
+		assert.Equal(t, "EdDSA", token.Method.Alg())
+	})
+
+	t.Run("Sign 鍵が登録されていない", func(t *testing.T) {
+		tokenString, err := (&KeyManager{}).Sign(testClaims())
+
+		assert.ErrorIs(t, err, ErrNoSigningKey)
+		assert.Empty(t, tokenString)
+	})
+}
+
+func TestKeyManagerRotation(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	km, _ := NewKeyManager("rsa-1", NewRSAKey("rsa-1", rsaKey))
+	oldToken, _ := km.Sign(testClaims())
+
+	t.Run("ローテーション後も旧鍵で署名したトークンを検証できる", func(t *testing.T) {
+		err := km.SetKeys("ed-2",
+			NewEd25519Key("ed-2", edKey),
+			NewVerifyOnlyKey("rsa-1", jwt.SigningMethodRS256, &rsaKey.PublicKey),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, "ed-2", km.SigningKid())
+
+		_, err = km.Parse(oldToken, jwt.MapClaims{})
+		assert.NoError(t, err)
+
+		newToken, _ := km.Sign(testClaims())
+		token, err := km.Parse(newToken, jwt.MapClaims{})
+		assert.NoError(t, err)
+		assert.Equal(t, "ed-2", token.Header["kid"])
+	})
+
+	t.Run("旧鍵を削除すると検証できない", func(t *testing.T) {
+		err := km.SetKeys("ed-2", NewEd25519Key("ed-2", edKey))
+		assert.NoError(t, err)
+
+		_, err = km.Parse(oldToken, jwt.MapClaims{})
+		assert.ErrorContains(t, err, "不明な鍵IDです: rsa-1")
+	})
+
+	t.Run("検証のみの鍵は署名に使用できない", func(t *testing.T) {
+		err := km.SetKeys("rsa-1", NewVerifyOnlyKey("rsa-1", jwt.SigningMethodRS256, &rsaKey.PublicKey))
+
+		assert.EqualError(t, err, "署名に使用する鍵が存在しません: rsa-1")
+		// 失敗した場合は既存の鍵を維持する
+		assert.Equal(t, "ed-2", km.SigningKid())
+	})
+}
+
+func TestKeyManagerKeyfunc(t *testing.T) {
+	t.Run("鍵IDのないトークンはデフォルトの鍵で検証する", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+		tokenString, _ := token.SignedString([]byte("test-secret"))
+
+		_, err := testJwtKeys.Parse(tokenString, jwt.MapClaims{})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("鍵と異なるアルゴリズムのトークンは受け付けない", func(t *testing.T) {
+		rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+		km, _ := NewKeyManager("rsa-1", NewRSAKey("rsa-1", rsaKey))
+
+		// 公開鍵を共有鍵としたHS256のトークン
+		publicDER, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+		token.Header["kid"] = "rsa-1"
+		tokenString, _ := token.SignedString(publicDER)
+
+		_, err := km.Parse(tokenString, jwt.MapClaims{})
+
+		assert.ErrorContains(t, err, "署名アルゴリズムが鍵と一致しません: HS256")
+	})
+}
+
+func TestKeyManagerJWKS(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	edPublic, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	km, _ := NewKeyManager("ed-2",
+		NewEd25519Key("ed-2", edKey),
+		NewVerifyOnlyKey("rsa-1", jwt.SigningMethodRS256, &rsaKey.PublicKey),
+		NewHMACKey(DefaultJwtKid, []byte("test-secret")),
+	)
+
+	jwks := km.JWKS()
+
+	// 共有鍵は公開しない
+	assert.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "ed-2", jwks.Keys[0].Kid)
+	assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Crv)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(edPublic), jwks.Keys[0].X)
+	assert.Equal(t, "rsa-1", jwks.Keys[1].Kid)
+	assert.Equal(t, "RSA", jwks.Keys[1].Kty)
+	assert.Equal(t, "RS256", jwks.Keys[1].Alg)
+	assert.Equal(t, "AQAB", jwks.Keys[1].E)
+}
+
+func TestLoadJwtKeys(t *testing.T) {
+	t.Run("LoadJwtKeys 鍵設定ファイル未指定", func(t *testing.T) {
+		signingKid, keys, err := LoadJwtKeys("", "secret")
+
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultJwtKid, signingKid)
+		assert.Len(t, keys, 1)
+		assert.Equal(t, "HS256", keys[0].Method.Alg())
+	})
+
+	t.Run("LoadJwtKeys 鍵が設定されていない", func(t *testing.T) {
+		_, _, err := LoadJwtKeys("", "")
+
+		assert.EqualError(t, err, "JWT_SECRET又はJWT_KEYS_FILEが設定されていません。")
+	})
+
+	t.Run("LoadJwtKeys 鍵設定ファイルから読み込む", func(t *testing.T) {
+		rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+		_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+		edDER, _ := x509.MarshalPKCS8PrivateKey(edKey)
+		rsaPublicDER, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+
+		edPath := writePEM(t, "ed.pem", "PRIVATE KEY", edDER)
+		rsaPath := writePEM(t, "rsa.pub.pem", "PUBLIC KEY", rsaPublicDER)
+		t.Setenv("TEST_JWT_SECRET", "secret")
+
+		config := fmt.Sprintf(`{
+			"signing_kid": "ed-2",
+			"keys": [
+				{"kid": "ed-2", "alg": "EdDSA", "private_key_file": %q},
+				{"kid": "rsa-1", "alg": "RS256", "public_key_file": %q},
+				{"kid": "default", "alg": "HS256", "secret_env": "TEST_JWT_SECRET"}
+			]
+		}`, edPath, rsaPath)
+		configPath := filepath.Join(t.TempDir(), "keys.json")
+		os.WriteFile(configPath, []byte(config), 0600)
+
+		signingKid, keys, err := LoadJwtKeys(configPath, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ed-2", signingKid)
+		assert.Len(t, keys, 3)
+
+		km, err := NewKeyManager(signingKid, keys...)
+		assert.NoError(t, err)
+		tokenString, _ := km.Sign(testClaims())
+		_, err = km.Parse(tokenString, jwt.MapClaims{})
+		assert.NoError(t, err)
+	})
+
+	t.Run("LoadJwtKeys 対応していないアルゴリズム", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "keys.json")
+		os.WriteFile(configPath, []byte(`{"signing_kid": "a", "keys": [{"kid": "a", "alg": "none"}]}`), 0600)
+
+		_, _, err := LoadJwtKeys(configPath, "")
+
+		assert.EqualError(t, err, "鍵(a)の読み込みエラー: 対応していない署名アルゴリズムです: none")
+	})
+}
@@ -0,0 +1,45 @@
+// utils/link_token.go
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// メールのリンクで使用するワンタイムトークンの設定
+const (
+	// パスワード再設定リンクの有効期限(分)
+	PasswordResetTokenMinutes = 30
+	// メールアドレス変更の確認リンクの有効期限(分)
+	EmailChangeConfirmMinutes = 60
+	// メールアドレス変更を取り消せる猶予期間(時間)
+	EmailChangeCancelHours = 72
+	// 退会したアカウントを復元できる猶予期間(日)
+	AccountRestoreDays = 30
+	// 個人データのエクスポートのダウンロードリンクの有効期限(時間)
+	DataExportLinkHours = 24
+	// マジックリンク(パスワードなしのサインイン)の有効期限(分)
+	MagicLinkMinutes = 15
+	// トークンの乱数のバイト数
+	linkTokenBytes = 32
+)
+
+// GenerateLinkToken はメールのリンクに含めるワンタイムトークンを生成する
+// URLに含めるため、URLセーフなBase64(パディングなし)とする
+func GenerateLinkToken() (string, error) {
+	buf := make([]byte, linkTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("トークンの生成に失敗しました: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashLinkToken はトークンを保存用にハッシュ化する
+// Redisの内容が漏洩してもリンクを復元できないよう、平文のトークンは保存しない
+func HashLinkToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
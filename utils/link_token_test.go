@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateLinkToken(t *testing.T) {
+	token, err := GenerateLinkToken()
+
+	assert.NoError(t, err)
+	// 32バイトをURLセーフなBase64にした43文字
+	assert.Regexp(t, regexp.MustCompile(`^[A-Za-z0-9_-]{43}$`), token)
+
+	other, _ := GenerateLinkToken()
+	assert.NotEqual(t, token, other)
+}
+
+func TestHashLinkToken(t *testing.T) {
+	hash := HashLinkToken("token")
+
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, HashLinkToken("token"))
+	assert.NotEqual(t, hash, HashLinkToken("token2"))
+}
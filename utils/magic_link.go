@@ -0,0 +1,103 @@
+// utils/magic_link.go
+package utils
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrMagicLinkInvalid は署名・有効期限が不正、又は使用済みのマジックリンクの場合のエラー
+	ErrMagicLinkInvalid = errors.New("サインインのリンクが無効又は有効期限切れです。")
+	// ErrMagicLinkDeviceMismatch はリンクを要求したブラウザ以外で開かれた場合のエラー
+	ErrMagicLinkDeviceMismatch = errors.New("サインインのリンクはリンクを要求したブラウザで開いてください。")
+)
+
+// magicLinkKey はマジックリンクを要求した端末を保存するRedisのキー
+// サインインに使用した時点で削除し、同じリンクを再度使用できないようにする
+func magicLinkKey(tokenId string) string {
+	return fmt.Sprintf("magic_link:%s", tokenId)
+}
+
+// NewMagicLinkToken はメールで送信するサインイン用の署名付きトークンを発行する
+// 要求した端末の識別子をハッシュ化してRedisに保存し、同じ端末からのみ使用できるようにする
+//
+// 引数:
+//   - UserId: サインインするユーザーID
+//   - DeviceId: リンクを要求した端末の識別子(クッキーに保存した乱数)
+//
+// 戻り値:
+//
+//	戻り値1: マジックリンクのトークン
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (ud *UtilsDataFetcher) NewMagicLinkToken(UserId int, DeviceId string) (string, error) {
+	tokenId := uuid.New().String()
+	duration := MagicLinkMinutes * time.Minute
+	if err := ud.RedisService.RedisSet(magicLinkKey(tokenId), HashLinkToken(DeviceId), duration); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := typedClaims(TokenTypeMagicLink, UserId, jwt.MapClaims{
+		"jti": tokenId,
+		"iat": now.Unix(),
+		"exp": now.Add(duration).Unix(),
+	})
+	return ud.KeyManager.Sign(claims)
+}
+
+// ConsumeMagicLinkToken はマジックリンクのトークンを検証し、使用済みにする
+// 端末が一致しない場合はトークンを使用済みにせず、要求したブラウザで開き直せるようにする
+//
+// 引数:
+//   - magicLinkToken: メールのリンクに含まれるトークン
+//   - DeviceId: リンクを開いた端末の識別子
+//
+// 戻り値:
+//
+//	戻り値1: サインインするユーザーID
+//	戻り値2: ErrMagicLinkInvalid 又は ErrMagicLinkDeviceMismatch
+//
+
+func (ud *UtilsDataFetcher) ConsumeMagicLinkToken(magicLinkToken, DeviceId string) (int, error) {
+	token, err := ud.ParseWithClaims(magicLinkToken)
+	if err != nil {
+		return 0, ErrMagicLinkInvalid
+	}
+	mapClaims, ok := ud.MapClaims(token.(*jwt.Token))
+	if !ok {
+		return 0, ErrMagicLinkInvalid
+	}
+	claims := mapClaims.(jwt.MapClaims)
+	if err := CheckTokenType(claims, TokenTypeMagicLink); err != nil {
+		return 0, ErrMagicLinkInvalid
+	}
+	tokenId, _ := claims["jti"].(string)
+	userId, ok := claims["UserId"].(float64)
+	if tokenId == "" || !ok {
+		return 0, ErrMagicLinkInvalid
+	}
+
+	key := magicLinkKey(tokenId)
+	deviceHash, err := ud.RedisService.RedisGet(key)
+	if err != nil {
+		// 有効期限切れ又は使用済みのリンク
+		return 0, ErrMagicLinkInvalid
+	}
+	if DeviceId == "" || subtle.ConstantTimeCompare([]byte(deviceHash), []byte(HashLinkToken(DeviceId))) != 1 {
+		return 0, ErrMagicLinkDeviceMismatch
+	}
+
+	// 同時に開かれた場合も1回のみサインインできるよう、取得と削除を1回の操作で行う
+	if _, err := ud.RedisService.RedisGetDel(key); err != nil {
+		return 0, ErrMagicLinkInvalid
+	}
+	return int(userId), nil
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"fmt"
+	mock_config "server/mock/config"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// parseMagicLinkClaims はテスト用にマジックリンクのトークンのクレームを取り出す
+func parseMagicLinkClaims(t *testing.T, magicLinkToken string) jwt.MapClaims {
+	token, err := testJwtKeys.Parse(magicLinkToken, jwt.MapClaims{})
+	assert.NoError(t, err)
+	return token.Claims.(jwt.MapClaims)
+}
+
+func TestNewMagicLinkToken(t *testing.T) {
+
+	t.Run("NewMagicLinkToken 端末の識別子のハッシュを有効期限付きで保存する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var key string
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisSet(gomock.Any(), HashLinkToken("device-1"), MagicLinkMinutes*time.Minute).
+			DoAndReturn(func(k string, value interface{}, duration time.Duration) error {
+				key = k
+				return nil
+			})
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		token, err := utilsFetcher.NewMagicLinkToken(3, "device-1")
+
+		assert.NoError(t, err)
+		claims := parseMagicLinkClaims(t, token)
+		assert.NoError(t, CheckTokenType(claims, TokenTypeMagicLink))
+		assert.Equal(t, float64(3), claims["UserId"])
+		assert.Equal(t, fmt.Sprintf("magic_link:%s", claims["jti"]), key)
+		// アクセストークンとしては受け付けない
+		assert.ErrorIs(t, CheckTokenType(claims, TokenTypeAccess), ErrInvalidTokenType)
+	})
+
+	t.Run("NewMagicLinkToken Redis保存エラー", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisSet(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("保存エラー"))
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		token, err := utilsFetcher.NewMagicLinkToken(3, "device-1")
+
+		assert.EqualError(t, err, "保存エラー")
+		assert.Empty(t, token)
+	})
+}
+
+func TestConsumeMagicLinkToken(t *testing.T) {
+
+	// newMagicLinkToken はテスト用のトークンと保存先のキーを作成する
+	newMagicLinkToken := func(t *testing.T) (string, string) {
+		ctrl := gomock.NewController(t)
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+		token, err := utilsFetcher.NewMagicLinkToken(3, "device-1")
+		assert.NoError(t, err)
+		return token, fmt.Sprintf("magic_link:%s", parseMagicLinkClaims(t, token)["jti"])
+	}
+
+	t.Run("ConsumeMagicLinkToken リンクを要求した端末で使用できる", func(t *testing.T) {
+		token, key := newMagicLinkToken(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGet(key).Return(HashLinkToken("device-1"), nil)
+		mockRedisService.EXPECT().RedisGetDel(key).Return(HashLinkToken("device-1"), nil)
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		userId, err := utilsFetcher.ConsumeMagicLinkToken(token, "device-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, userId)
+	})
+
+	t.Run("ConsumeMagicLinkToken 端末が異なる場合は使用済みにしない", func(t *testing.T) {
+		token, key := newMagicLinkToken(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGet(key).Return(HashLinkToken("device-1"), nil).Times(2)
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		_, err := utilsFetcher.ConsumeMagicLinkToken(token, "other-device")
+		assert.ErrorIs(t, err, ErrMagicLinkDeviceMismatch)
+
+		// 端末の識別子のクッキーがない場合
+		_, err = utilsFetcher.ConsumeMagicLinkToken(token, "")
+		assert.ErrorIs(t, err, ErrMagicLinkDeviceMismatch)
+	})
+
+	t.Run("ConsumeMagicLinkToken 使用済み又は有効期限切れ", func(t *testing.T) {
+		token, key := newMagicLinkToken(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGet(key).Return("", fmt.Errorf("キーが存在しません: %s", key))
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		_, err := utilsFetcher.ConsumeMagicLinkToken(token, "device-1")
+
+		assert.ErrorIs(t, err, ErrMagicLinkInvalid)
+	})
+
+	t.Run("ConsumeMagicLinkToken 同時に使用された場合は1回のみ使用できる", func(t *testing.T) {
+		token, key := newMagicLinkToken(t)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisGet(key).Return(HashLinkToken("device-1"), nil)
+		mockRedisService.EXPECT().RedisGetDel(key).Return("", fmt.Errorf("キーが存在しません: %s", key))
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		_, err := utilsFetcher.ConsumeMagicLinkToken(token, "device-1")
+
+		assert.ErrorIs(t, err, ErrMagicLinkInvalid)
+	})
+
+	t.Run("ConsumeMagicLinkToken アクセストークンは使用できない", func(t *testing.T) {
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys}
+		accessToken, _ := utilsFetcher.NewToken(3, "session1", "user", 1)
+
+		_, err := utilsFetcher.ConsumeMagicLinkToken(accessToken, "device-1")
+
+		assert.ErrorIs(t, err, ErrMagicLinkInvalid)
+	})
+
+	t.Run("ConsumeMagicLinkToken 署名が不正", func(t *testing.T) {
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys}
+
+		_, err := utilsFetcher.ConsumeMagicLinkToken("invalid.token.value", "device-1")
+
+		assert.ErrorIs(t, err, ErrMagicLinkInvalid)
+	})
+}
@@ -0,0 +1,259 @@
+// utils/oidc.go
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type (
+	// IdTokenClaims は外部認証(OpenID Connect)のIDトークンのクレーム
+	IdTokenClaims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+		Nonce         string `json:"nonce"`
+		jwt.RegisteredClaims
+	}
+
+	// JwksCache は外部認証の公開鍵(JWKS)を取得し、有効期限まで保持する
+	JwksCache struct {
+		URL    string
+		Client *http.Client
+
+		mu        sync.Mutex
+		keys      map[string]interface{}
+		fetchedAt time.Time
+		expiresAt time.Time
+	}
+
+	// IdTokenVerifier はIDトークンの署名・発行者・対象者・有効期限・nonceを検証する
+	IdTokenVerifier struct {
+		Issuers []string
+		Methods []string
+		Jwks    *JwksCache
+		// HS256で署名されたIDトークンの検証に使う共有鍵(LINEのチャネルシークレット)
+		Secret []byte
+	}
+)
+
+const (
+	// Cache-Control に max-age がない場合の公開鍵の保持期間
+	jwksDefaultCacheDuration = time.Hour
+	// 未知のkidによる公開鍵の再取得の最短間隔
+	jwksRefetchInterval = time.Minute
+	// IDトークンの有効期限の検証で許容する時刻のずれ
+	idTokenLeeway = time.Minute
+)
+
+func NewJwksCache(url string) *JwksCache {
+	return &JwksCache{
+		URL:    url,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NewIdTokenVerifier はJWKSのURLと発行者からIDトークンの検証を作成する
+//
+// 引数:
+//   - jwksURL: 公開鍵(JWKS)のURL
+//   - issuers: 許可する発行者(iss)
+//   - methods: 許可する署名アルゴリズム
+//
+
+func NewIdTokenVerifier(jwksURL string, issuers []string, methods ...string) *IdTokenVerifier {
+	return &IdTokenVerifier{
+		Issuers: issuers,
+		Methods: methods,
+		Jwks:    NewJwksCache(jwksURL),
+	}
+}
+
+// Key は鍵ID(kid)の公開鍵を返す
+// 保持していないkidの場合は鍵のローテーションに備えて公開鍵を取得し直す
+//
+// 引数:
+//   - kid: 鍵ID
+//
+// 戻り値:
+//
+//	戻り値1: 公開鍵(*rsa.PublicKey 又は *ecdsa.PublicKey)
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (jc *JwksCache) Key(kid string) (interface{}, error) {
+	jc.mu.Lock()
+	defer jc.mu.Unlock()
+
+	now := time.Now()
+	fresh := now.Before(jc.expiresAt)
+	if key, ok := jc.keys[kid]; ok && fresh {
+		return key, nil
+	}
+	if fresh && now.Sub(jc.fetchedAt) < jwksRefetchInterval {
+		return nil, fmt.Errorf("未登録の鍵IDです: %s", kid)
+	}
+
+	if err := jc.fetch(now); err != nil {
+		return nil, err
+	}
+	if key, ok := jc.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("未登録の鍵IDです: %s", kid)
+}
+
+// fetch は公開鍵を取得し、Cache-Control の max-age まで保持する
+func (jc *JwksCache) fetch(now time.Time) error {
+	resp, err := jc.Client.Get(jc.URL)
+	if err != nil {
+		return fmt.Errorf("公開鍵の取得エラー: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("公開鍵の取得エラー: ステータスコード %d", resp.StatusCode)
+	}
+
+	var jwks JSONWebKeySet
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return fmt.Errorf("公開鍵の読み込みエラー: %v", err)
+	}
+
+	keys := make(map[string]interface{}, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	jc.keys = keys
+	jc.fetchedAt = now
+	jc.expiresAt = now.Add(cacheMaxAge(resp.Header.Get("Cache-Control")))
+	return nil
+}
+
+// cacheMaxAge は Cache-Control の max-age を返す
+func cacheMaxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(directive), "max-age=")
+		if !ok {
+			continue
+		}
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return jwksDefaultCacheDuration
+}
+
+// publicKey はJWKを公開鍵に変換する
+func (jwk JSONWebKey) publicKey() (interface{}, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		if jwk.Crv != "P-256" {
+			return nil, fmt.Errorf("未対応の曲線です: %s", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	default:
+		return nil, fmt.Errorf("未対応の鍵の種類です: %s", jwk.Kty)
+	}
+}
+
+// Verify はIDトークンを検証してクレームを返す
+//
+// 引数:
+//   - rawIdToken: IDトークン
+//   - audience: 対象者(aud)として許可するクライアントID
+//   - nonce: 認証開始時に発行したnonce
+//
+// 戻り値:
+//
+//	戻り値1: IDトークンのクレーム
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (v *IdTokenVerifier) Verify(rawIdToken, audience, nonce string) (IdTokenClaims, error) {
+	var claims IdTokenClaims
+
+	_, err := jwt.ParseWithClaims(
+		rawIdToken,
+		&claims,
+		func(token *jwt.Token) (interface{}, error) {
+			// 共有鍵の署名は共有鍵を設定した場合のみ受け付ける
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+				if len(v.Secret) == 0 {
+					return nil, errors.New("共有鍵が設定されていません。")
+				}
+				return v.Secret, nil
+			}
+			kid, _ := token.Header["kid"].(string)
+			return v.Jwks.Key(kid)
+		},
+		jwt.WithValidMethods(v.Methods),
+		jwt.WithAudience(audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(idTokenLeeway),
+	)
+	if err != nil {
+		return IdTokenClaims{}, fmt.Errorf("IDトークンの検証エラー: %v", err)
+	}
+
+	if !slices.Contains(v.Issuers, claims.Issuer) {
+		return IdTokenClaims{}, fmt.Errorf("IDトークンの発行者が不正です: %s", claims.Issuer)
+	}
+	if claims.Subject == "" {
+		return IdTokenClaims{}, errors.New("IDトークンにユーザーIDがありません。")
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return IdTokenClaims{}, errors.New("IDトークンのnonceが一致しません。")
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,344 @@
+// utils/oidc_provider.go
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"server/config"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+type (
+	// OIDCProvider は外部認証(OpenID Connect)の認可・トークン交換・IDトークンの検証を行う
+	OIDCProvider interface {
+		Config() config.OIDCProviderConfig
+		AuthURL(redirectURI, state, codeVerifier, nonce string) (string, error)
+		Exchange(ctx context.Context, code, redirectURI, codeVerifier string) (*oauth2.Token, error)
+		VerifyIdToken(rawIdToken, nonce string) (IdTokenClaims, error)
+		UserInfo(ctx context.Context, token *oauth2.Token) (OIDCUserInfo, error)
+		Revoke(ctx context.Context, token *oauth2.Token) error
+	}
+
+	// OIDCProviderManager は発行者のディスカバリードキュメントからエンドポイントを取得する汎用の外部認証
+	OIDCProviderManager struct {
+		ProviderConfig config.OIDCProviderConfig
+		Client         *http.Client
+
+		mu       sync.Mutex
+		metadata *OIDCDiscovery
+		verifier *IdTokenVerifier
+	}
+
+	// OIDCDiscovery はディスカバリードキュメント(/.well-known/openid-configuration)
+	OIDCDiscovery struct {
+		Issuer                string   `json:"issuer"`
+		AuthorizationEndpoint string   `json:"authorization_endpoint"`
+		TokenEndpoint         string   `json:"token_endpoint"`
+		JwksURI               string   `json:"jwks_uri"`
+		UserInfoEndpoint      string   `json:"userinfo_endpoint"`
+		RevocationEndpoint    string   `json:"revocation_endpoint"`
+		IdTokenSigningAlgs    []string `json:"id_token_signing_alg_values_supported"`
+		CodeChallengeMethods  []string `json:"code_challenge_methods_supported"`
+	}
+
+	// OIDCUserInfo はUserInfoエンドポイントのユーザー情報
+	OIDCUserInfo struct {
+		Sub   string `json:"sub"`
+		Email string `json:"email"`
+		Name  string `json:"name"`
+	}
+)
+
+// OIDCProviders はアプリケーション全体で使用する外部認証
+// 環境変数の読み込み後に InitOIDCProviders で登録する
+var OIDCProviders []OIDCProvider
+
+// IDトークンの署名アルゴリズムがディスカバリードキュメントにない場合の既定値
+const defaultIdTokenSigningAlg = "RS256"
+
+func NewOIDCProvider(providerConfig config.OIDCProviderConfig) OIDCProvider {
+	return &OIDCProviderManager{
+		ProviderConfig: providerConfig,
+		Client:         &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// InitOIDCProviders は環境変数の読み込み後に OIDCProviders へ外部認証を登録する
+func InitOIDCProviders() error {
+	providerConfigs, err := config.OIDCProviderConfigs()
+	if err != nil {
+		return err
+	}
+
+	providers := make([]OIDCProvider, 0, len(providerConfigs))
+	for _, providerConfig := range providerConfigs {
+		providers = append(providers, NewOIDCProvider(providerConfig))
+	}
+	OIDCProviders = providers
+	return nil
+}
+
+func (pm *OIDCProviderManager) Config() config.OIDCProviderConfig {
+	return pm.ProviderConfig
+}
+
+// discovery はディスカバリードキュメントを取得して保持する
+// 取得に失敗した場合は保持せず、次の呼び出しで取得し直す
+//
+// 戻り値:
+//
+//	戻り値1: ディスカバリードキュメント
+//	戻り値2: IDトークンの検証
+//	戻り値3: エラー内容(エラーがない場合はnil)
+//
+
+func (pm *OIDCProviderManager) discovery() (*OIDCDiscovery, *IdTokenVerifier, error) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	if pm.metadata != nil {
+		return pm.metadata, pm.verifier, nil
+	}
+
+	issuer := strings.TrimSuffix(pm.ProviderConfig.Issuer, "/")
+	resp, err := pm.Client.Get(issuer + "/.well-known/openid-configuration")
+	if err != nil {
+		return nil, nil, fmt.Errorf("ディスカバリードキュメントの取得エラー: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("ディスカバリードキュメントの取得エラー: ステータスコード %d", resp.StatusCode)
+	}
+
+	var metadata OIDCDiscovery
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return nil, nil, fmt.Errorf("ディスカバリードキュメントの読み込みエラー: %v", err)
+	}
+	// 発行者の異なるドキュメントは受け付けない(OpenID Connect Discovery 4.3)
+	if metadata.Issuer != pm.ProviderConfig.Issuer {
+		return nil, nil, fmt.Errorf("ディスカバリードキュメントの発行者が一致しません: %s", metadata.Issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JwksURI == "" {
+		return nil, nil, fmt.Errorf("ディスカバリードキュメントに必要なエンドポイントがありません。")
+	}
+	if !metadata.supportsS256() {
+		return nil, nil, fmt.Errorf("PKCE(S256)に対応していない外部認証です。")
+	}
+
+	// 共有鍵の署名は設定で許可した場合のみ受け付ける
+	methods := []string{}
+	for _, alg := range metadata.IdTokenSigningAlgs {
+		if alg != "none" && !strings.HasPrefix(alg, "HS") {
+			methods = append(methods, alg)
+		}
+	}
+	if len(methods) == 0 {
+		methods = append(methods, defaultIdTokenSigningAlg)
+	}
+	if pm.ProviderConfig.SymmetricIdToken {
+		methods = append(methods, "HS256")
+	}
+
+	verifier := NewIdTokenVerifier(
+		metadata.JwksURI,
+		append([]string{metadata.Issuer}, pm.ProviderConfig.ExtraIssuers...),
+		methods...,
+	)
+	if pm.ProviderConfig.SymmetricIdToken {
+		verifier.Secret = []byte(pm.ProviderConfig.ClientSecret)
+	}
+
+	pm.metadata = &metadata
+	pm.verifier = verifier
+	return pm.metadata, pm.verifier, nil
+}
+
+// oauth2Config は認可・トークン交換に使用するOAuth2の設定
+func (pm *OIDCProviderManager) oauth2Config(metadata *OIDCDiscovery, redirectURI string) *oauth2.Config {
+	authStyle := oauth2.AuthStyleAutoDetect
+	if pm.ProviderConfig.TokenAuthInParams {
+		authStyle = oauth2.AuthStyleInParams
+	}
+
+	return &oauth2.Config{
+		ClientID:     pm.ProviderConfig.ClientID,
+		ClientSecret: pm.ProviderConfig.ClientSecret,
+		RedirectURL:  redirectURI,
+		Scopes:       pm.ProviderConfig.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   metadata.AuthorizationEndpoint,
+			TokenURL:  metadata.TokenEndpoint,
+			AuthStyle: authStyle,
+		},
+	}
+}
+
+// AuthURL はPKCE(S256)とnonceを付けた認可URLを返す
+//
+// 引数:
+//   - redirectURI: 認可後のリダイレクト先
+//   - state: CSRF対策のstate
+//   - codeVerifier: PKCEのコードベリファイア
+//   - nonce: IDトークンに含めるnonce
+//
+
+func (pm *OIDCProviderManager) AuthURL(redirectURI, state, codeVerifier, nonce string) (string, error) {
+	metadata, _, err := pm.discovery()
+	if err != nil {
+		return "", err
+	}
+
+	options := []oauth2.AuthCodeOption{
+		oauth2.S256ChallengeOption(codeVerifier),
+		oauth2.SetAuthURLParam("nonce", nonce),
+	}
+	for key, value := range pm.ProviderConfig.AuthParams {
+		options = append(options, oauth2.SetAuthURLParam(key, value))
+	}
+	return pm.oauth2Config(metadata, redirectURI).AuthCodeURL(state, options...), nil
+}
+
+// Exchange は認可コードをトークンと交換する
+//
+// 引数:
+//   - ctx: コンテキスト
+//   - code: 認可コード
+//   - redirectURI: 認可後のリダイレクト先(認可リクエストと同じ値)
+//   - codeVerifier: PKCEのコードベリファイア
+//
+
+func (pm *OIDCProviderManager) Exchange(ctx context.Context, code, redirectURI, codeVerifier string) (*oauth2.Token, error) {
+	metadata, _, err := pm.discovery()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, pm.Client)
+	return pm.oauth2Config(metadata, redirectURI).Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+}
+
+// VerifyIdToken はIDトークンの署名・発行者・対象者(クライアントID)・有効期限・nonceを検証する
+func (pm *OIDCProviderManager) VerifyIdToken(rawIdToken, nonce string) (IdTokenClaims, error) {
+	_, verifier, err := pm.discovery()
+	if err != nil {
+		return IdTokenClaims{}, err
+	}
+	return verifier.Verify(rawIdToken, pm.ProviderConfig.ClientID, nonce)
+}
+
+// UserInfo はアクセストークンでUserInfoエンドポイントからユーザー情報を取得する
+//
+// 引数:
+//   - ctx: コンテキスト
+//   - token: 認可コードと交換したトークン
+//
+// 戻り値:
+//
+//	戻り値1: ユーザー情報
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func (pm *OIDCProviderManager) UserInfo(ctx context.Context, token *oauth2.Token) (OIDCUserInfo, error) {
+	var userInfo OIDCUserInfo
+
+	metadata, _, err := pm.discovery()
+	if err != nil {
+		return userInfo, err
+	}
+	if metadata.UserInfoEndpoint == "" {
+		return userInfo, fmt.Errorf("UserInfoエンドポイントがありません。")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadata.UserInfoEndpoint, nil)
+	if err != nil {
+		return userInfo, err
+	}
+	token.SetAuthHeader(req)
+
+	resp, err := pm.Client.Do(req)
+	if err != nil {
+		return userInfo, fmt.Errorf("ユーザー情報の取得エラー: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return userInfo, fmt.Errorf("ユーザー情報の取得エラー: ステータスコード %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&userInfo); err != nil {
+		return userInfo, fmt.Errorf("ユーザー情報の読み込みエラー: %v", err)
+	}
+	return userInfo, nil
+}
+
+// Revoke はアクセストークンを無効化する(RFC 7009)
+// 無効化のエンドポイントがない外部認証では何もしない
+//
+// 引数:
+//   - ctx: コンテキスト
+//   - token: 認可コードと交換したトークン
+//
+
+func (pm *OIDCProviderManager) Revoke(ctx context.Context, token *oauth2.Token) error {
+	metadata, _, err := pm.discovery()
+	if err != nil {
+		return err
+	}
+
+	revocationURL := metadata.RevocationEndpoint
+	if revocationURL == "" {
+		revocationURL = pm.ProviderConfig.RevocationURL
+	}
+	if revocationURL == "" {
+		return nil
+	}
+
+	tokenParam := pm.ProviderConfig.RevocationTokenParam
+	if tokenParam == "" {
+		tokenParam = "token"
+	}
+	data := url.Values{}
+	data.Set(tokenParam, token.AccessToken)
+	data.Set("client_id", pm.ProviderConfig.ClientID)
+	data.Set("client_secret", pm.ProviderConfig.ClientSecret)
+	if tokenParam == "token" {
+		data.Set("token_type_hint", "access_token")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, revocationURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := pm.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("トークン無効化エラー: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf(
+			"トークン無効化に失敗しました。ステータスコード:%d エラー内容：%s",
+			resp.StatusCode,
+			body,
+		)
+	}
+	return nil
+}
+
+// supportsS256 はPKCEのS256に対応しているかを返す
+// code_challenge_methods_supported がない場合は対応しているものとして扱う
+func (metadata *OIDCDiscovery) supportsS256() bool {
+	return len(metadata.CodeChallengeMethods) == 0 || slices.Contains(metadata.CodeChallengeMethods, "S256")
+}
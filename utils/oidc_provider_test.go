@@ -0,0 +1,229 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"server/config"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+// testOIDCServer はテスト用のディスカバリードキュメント・公開鍵・トークン無効化を返すサーバー
+type testOIDCServer struct {
+	*httptest.Server
+	key       *rsa.PrivateKey
+	discovery map[string]interface{}
+	requests  int32
+	// トークン無効化で受け取ったパラメータ
+	revoked url.Values
+}
+
+func newTestOIDCServer(t *testing.T) *testOIDCServer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	server := &testOIDCServer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&server.requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(server.discovery)
+	})
+	mux.HandleFunc("/certs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(JSONWebKeySet{Keys: []JSONWebKey{rsaJWK("kid-1", key)}})
+	})
+	mux.HandleFunc("/revoke", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		server.revoked = r.PostForm
+		w.WriteHeader(http.StatusOK)
+	})
+	server.Server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	server.discovery = map[string]interface{}{
+		"issuer":                                server.URL,
+		"authorization_endpoint":                server.URL + "/authorize",
+		"token_endpoint":                        server.URL + "/token",
+		"jwks_uri":                              server.URL + "/certs",
+		"revocation_endpoint":                   server.URL + "/revoke",
+		"id_token_signing_alg_values_supported": []string{"RS256", "HS256"},
+		"code_challenge_methods_supported":      []string{"plain", "S256"},
+	}
+	return server
+}
+
+func (server *testOIDCServer) providerConfig() config.OIDCProviderConfig {
+	return config.OIDCProviderConfig{
+		Name:         "example",
+		Issuer:       server.URL,
+		ClientID:     testOIDCAudience,
+		ClientSecret: "test-client-secret",
+		Scopes:       []string{"openid", "email"},
+	}
+}
+
+// idTokenClaims はテスト用サーバーが発行したIDトークンのクレーム
+func (server *testOIDCServer) idTokenClaims() jwt.MapClaims {
+	claims := testIdTokenClaims()
+	claims["iss"] = server.URL
+	return claims
+}
+
+func TestOIDCProviderAuthURL(t *testing.T) {
+	server := newTestOIDCServer(t)
+
+	providerConfig := server.providerConfig()
+	providerConfig.AuthParams = map[string]string{"prompt": "select_account"}
+	provider := NewOIDCProvider(providerConfig)
+
+	authURL, err := provider.AuthURL("http://localhost:3000/auth/example/signin/callback", "state", "verifier-0123456789-0123456789-0123456789", "nonce")
+	assert.NoError(t, err)
+
+	location, err := url.Parse(authURL)
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+
+	query := location.Query()
+	assert.Equal(t, testOIDCAudience, query.Get("client_id"))
+	assert.Equal(t, "openid email", query.Get("scope"))
+	assert.Equal(t, "state", query.Get("state"))
+	assert.Equal(t, "nonce", query.Get("nonce"))
+	assert.Equal(t, "select_account", query.Get("prompt"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, oauth2.S256ChallengeFromVerifier("verifier-0123456789-0123456789-0123456789"), query.Get("code_challenge"))
+
+	// ディスカバリードキュメントは1回のみ取得する
+	_, err = provider.AuthURL("http://localhost:3000/", "state", "verifier", "nonce")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&server.requests))
+}
+
+func TestOIDCProviderDiscovery(t *testing.T) {
+
+	t.Run("error discovery 発行者が一致しない", func(t *testing.T) {
+		server := newTestOIDCServer(t)
+		server.discovery["issuer"] = "https://evil.example.com"
+		provider := NewOIDCProvider(server.providerConfig())
+
+		_, err := provider.AuthURL("http://localhost:3000/", "state", "verifier", "nonce")
+		assert.Error(t, err)
+
+		// 取得に失敗したドキュメントは保持せず取得し直す
+		server.discovery["issuer"] = server.URL
+		_, err = provider.AuthURL("http://localhost:3000/", "state", "verifier", "nonce")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&server.requests))
+	})
+
+	t.Run("error discovery PKCE(S256)に未対応", func(t *testing.T) {
+		server := newTestOIDCServer(t)
+		server.discovery["code_challenge_methods_supported"] = []string{"plain"}
+		provider := NewOIDCProvider(server.providerConfig())
+
+		_, err := provider.AuthURL("http://localhost:3000/", "state", "verifier", "nonce")
+		assert.Error(t, err)
+	})
+
+	t.Run("error discovery 必要なエンドポイントがない", func(t *testing.T) {
+		server := newTestOIDCServer(t)
+		delete(server.discovery, "jwks_uri")
+		provider := NewOIDCProvider(server.providerConfig())
+
+		_, err := provider.VerifyIdToken("token", testOIDCNonce)
+		assert.Error(t, err)
+	})
+}
+
+func TestOIDCProviderVerifyIdToken(t *testing.T) {
+	server := newTestOIDCServer(t)
+	const secret = "test-client-secret"
+
+	t.Run("success VerifyIdToken 公開鍵(RS256)", func(t *testing.T) {
+		provider := NewOIDCProvider(server.providerConfig())
+		rawIdToken := signTestIdToken(t, jwt.SigningMethodRS256, "kid-1", server.key, server.idTokenClaims())
+
+		claims, err := provider.VerifyIdToken(rawIdToken, testOIDCNonce)
+		assert.NoError(t, err)
+		assert.Equal(t, "109876543210", claims.Subject)
+	})
+
+	t.Run("error VerifyIdToken 共有鍵(HS256)は設定で許可した場合のみ", func(t *testing.T) {
+		// ディスカバリードキュメントに HS256 があっても受け付けない
+		provider := NewOIDCProvider(server.providerConfig())
+		rawIdToken := signTestIdToken(t, jwt.SigningMethodHS256, "", []byte(secret), server.idTokenClaims())
+
+		_, err := provider.VerifyIdToken(rawIdToken, testOIDCNonce)
+		assert.Error(t, err)
+	})
+
+	t.Run("success VerifyIdToken 共有鍵(HS256)", func(t *testing.T) {
+		providerConfig := server.providerConfig()
+		providerConfig.SymmetricIdToken = true
+		provider := NewOIDCProvider(providerConfig)
+		rawIdToken := signTestIdToken(t, jwt.SigningMethodHS256, "", []byte(secret), server.idTokenClaims())
+
+		_, err := provider.VerifyIdToken(rawIdToken, testOIDCNonce)
+		assert.NoError(t, err)
+	})
+
+	t.Run("VerifyIdToken 追加で許可した発行者", func(t *testing.T) {
+		claims := server.idTokenClaims()
+		claims["iss"] = "accounts.example.com"
+		rawIdToken := signTestIdToken(t, jwt.SigningMethodRS256, "kid-1", server.key, claims)
+
+		_, err := NewOIDCProvider(server.providerConfig()).VerifyIdToken(rawIdToken, testOIDCNonce)
+		assert.Error(t, err)
+
+		providerConfig := server.providerConfig()
+		providerConfig.ExtraIssuers = []string{"accounts.example.com"}
+		_, err = NewOIDCProvider(providerConfig).VerifyIdToken(rawIdToken, testOIDCNonce)
+		assert.NoError(t, err)
+	})
+}
+
+func TestOIDCProviderRevoke(t *testing.T) {
+	token := &oauth2.Token{AccessToken: "access-token"}
+
+	t.Run("success Revoke RFC 7009", func(t *testing.T) {
+		server := newTestOIDCServer(t)
+
+		err := NewOIDCProvider(server.providerConfig()).Revoke(context.Background(), token)
+		assert.NoError(t, err)
+		assert.Equal(t, "access-token", server.revoked.Get("token"))
+		assert.Equal(t, "access_token", server.revoked.Get("token_type_hint"))
+		assert.Equal(t, testOIDCAudience, server.revoked.Get("client_id"))
+	})
+
+	t.Run("success Revoke 設定した無効化のURLとパラメータ名", func(t *testing.T) {
+		server := newTestOIDCServer(t)
+		delete(server.discovery, "revocation_endpoint")
+
+		providerConfig := server.providerConfig()
+		providerConfig.RevocationURL = server.URL + "/revoke"
+		providerConfig.RevocationTokenParam = "access_token"
+
+		err := NewOIDCProvider(providerConfig).Revoke(context.Background(), token)
+		assert.NoError(t, err)
+		assert.Equal(t, "access-token", server.revoked.Get("access_token"))
+		assert.Empty(t, server.revoked.Get("token"))
+	})
+
+	t.Run("success Revoke 無効化のエンドポイントがない", func(t *testing.T) {
+		server := newTestOIDCServer(t)
+		delete(server.discovery, "revocation_endpoint")
+
+		err := NewOIDCProvider(server.providerConfig()).Revoke(context.Background(), token)
+		assert.NoError(t, err)
+		assert.Nil(t, server.revoked)
+	})
+}
@@ -0,0 +1,321 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testOIDCIssuer   = "https://accounts.example.com"
+	testOIDCAudience = "test-client"
+	testOIDCNonce    = "test-nonce"
+)
+
+// testJwksServer はテスト用の公開鍵(JWKS)を返すサーバー
+type testJwksServer struct {
+	*httptest.Server
+	keys         []JSONWebKey
+	cacheControl string
+	requests     int32
+}
+
+func newTestJwksServer(t *testing.T, keys ...JSONWebKey) *testJwksServer {
+	server := &testJwksServer{keys: keys, cacheControl: "public, max-age=3600"}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&server.requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", server.cacheControl)
+		_ = json.NewEncoder(w).Encode(JSONWebKeySet{Keys: server.keys})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func rsaJWK(kid string, key *rsa.PrivateKey) JSONWebKey {
+	return JSONWebKey{
+		Kty: "RSA",
+		Use: "sig",
+		Alg: "RS256",
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func testIdTokenClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            testOIDCIssuer,
+		"aud":            testOIDCAudience,
+		"sub":            "109876543210",
+		"email":          "test@example.com",
+		"email_verified": true,
+		"name":           "test",
+		"nonce":          testOIDCNonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+	}
+}
+
+func signTestIdToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	assert.NoError(t, err)
+	return signed
+}
+
+func TestCacheMaxAge(t *testing.T) {
+	assert.Equal(t, 300*time.Second, cacheMaxAge("public, max-age=300, must-revalidate"))
+	assert.Equal(t, 20*time.Second, cacheMaxAge("max-age=20"))
+	// max-age がない・不正な場合は既定の保持期間
+	assert.Equal(t, jwksDefaultCacheDuration, cacheMaxAge("no-cache"))
+	assert.Equal(t, jwksDefaultCacheDuration, cacheMaxAge("max-age=abc"))
+	assert.Equal(t, jwksDefaultCacheDuration, cacheMaxAge(""))
+}
+
+func TestJwksCacheKey(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	t.Run("有効期限まで保持した公開鍵を返す", func(t *testing.T) {
+		server := newTestJwksServer(t, rsaJWK("kid-1", key))
+		cache := NewJwksCache(server.URL)
+
+		for i := 0; i < 3; i++ {
+			publicKey, err := cache.Key("kid-1")
+			assert.NoError(t, err)
+			assert.Equal(t, &key.PublicKey, publicKey)
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&server.requests))
+		assert.WithinDuration(t, time.Now().Add(time.Hour), cache.expiresAt, 5*time.Second)
+	})
+
+	t.Run("有効期限切れの場合は取得し直す", func(t *testing.T) {
+		server := newTestJwksServer(t, rsaJWK("kid-1", key))
+		cache := NewJwksCache(server.URL)
+
+		_, err := cache.Key("kid-1")
+		assert.NoError(t, err)
+
+		cache.expiresAt = time.Now().Add(-time.Second)
+		_, err = cache.Key("kid-1")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&server.requests))
+	})
+
+	t.Run("未知のkidの場合は鍵のローテーションに備えて取得し直す", func(t *testing.T) {
+		newKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+		server := newTestJwksServer(t, rsaJWK("kid-1", key))
+		cache := NewJwksCache(server.URL)
+
+		_, err := cache.Key("kid-1")
+		assert.NoError(t, err)
+
+		// 前回の取得から再取得の最短間隔が経過している
+		cache.fetchedAt = time.Now().Add(-jwksRefetchInterval)
+		server.keys = []JSONWebKey{rsaJWK("kid-1", key), rsaJWK("kid-2", newKey)}
+
+		publicKey, err := cache.Key("kid-2")
+		assert.NoError(t, err)
+		assert.Equal(t, &newKey.PublicKey, publicKey)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&server.requests))
+	})
+
+	t.Run("未知のkidの再取得は最短間隔を空ける", func(t *testing.T) {
+		server := newTestJwksServer(t, rsaJWK("kid-1", key))
+		cache := NewJwksCache(server.URL)
+
+		_, err := cache.Key("kid-1")
+		assert.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			_, err = cache.Key("unknown")
+			assert.EqualError(t, err, "未登録の鍵IDです: unknown")
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&server.requests))
+	})
+
+	t.Run("EC(P-256)の公開鍵", func(t *testing.T) {
+		ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		server := newTestJwksServer(t, JSONWebKey{
+			Kty: "EC",
+			Use: "sig",
+			Alg: "ES256",
+			Kid: "ec-1",
+			Crv: "P-256",
+			X:   base64.RawURLEncoding.EncodeToString(ecKey.X.Bytes()),
+			Y:   base64.RawURLEncoding.EncodeToString(ecKey.Y.Bytes()),
+		})
+		cache := NewJwksCache(server.URL)
+
+		publicKey, err := cache.Key("ec-1")
+		assert.NoError(t, err)
+		assert.True(t, ecKey.PublicKey.Equal(publicKey))
+	})
+
+	t.Run("暗号化用の鍵は使用しない", func(t *testing.T) {
+		encKey := rsaJWK("enc-1", key)
+		encKey.Use = "enc"
+		server := newTestJwksServer(t, encKey)
+		cache := NewJwksCache(server.URL)
+
+		_, err := cache.Key("enc-1")
+		assert.EqualError(t, err, "未登録の鍵IDです: enc-1")
+	})
+
+	t.Run("取得エラー", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+		cache := NewJwksCache(server.URL)
+
+		_, err := cache.Key("kid-1")
+		assert.EqualError(t, err, "公開鍵の取得エラー: ステータスコード 500")
+	})
+}
+
+func TestIdTokenVerifierVerify(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	server := newTestJwksServer(t, rsaJWK("kid-1", key))
+	verifier := NewIdTokenVerifier(server.URL, []string{testOIDCIssuer, "accounts.example.com"}, "RS256")
+
+	t.Run("success Verify", func(t *testing.T) {
+		rawIdToken := signTestIdToken(t, jwt.SigningMethodRS256, "kid-1", key, testIdTokenClaims())
+
+		claims, err := verifier.Verify(rawIdToken, testOIDCAudience, testOIDCNonce)
+		assert.NoError(t, err)
+		assert.Equal(t, "109876543210", claims.Subject)
+		assert.Equal(t, "test@example.com", claims.Email)
+		assert.True(t, claims.EmailVerified)
+		assert.Equal(t, "test", claims.Name)
+	})
+
+	t.Run("success Verify 発行者のスキームなし", func(t *testing.T) {
+		idTokenClaims := testIdTokenClaims()
+		idTokenClaims["iss"] = "accounts.example.com"
+		rawIdToken := signTestIdToken(t, jwt.SigningMethodRS256, "kid-1", key, idTokenClaims)
+
+		_, err := verifier.Verify(rawIdToken, testOIDCAudience, testOIDCNonce)
+		assert.NoError(t, err)
+	})
+
+	t.Run("success Verify 有効期限の許容範囲内", func(t *testing.T) {
+		idTokenClaims := testIdTokenClaims()
+		idTokenClaims["exp"] = time.Now().Add(-30 * time.Second).Unix()
+		rawIdToken := signTestIdToken(t, jwt.SigningMethodRS256, "kid-1", key, idTokenClaims)
+
+		_, err := verifier.Verify(rawIdToken, testOIDCAudience, testOIDCNonce)
+		assert.NoError(t, err)
+	})
+
+	invalidTests := []struct {
+		name   string
+		claims jwt.MapClaims
+	}{
+		{name: "対象者(aud)不正", claims: jwt.MapClaims{"aud": "other-client"}},
+		{name: "発行者(iss)不正", claims: jwt.MapClaims{"iss": "https://evil.example.com"}},
+		{name: "有効期限切れ", claims: jwt.MapClaims{"exp": time.Now().Add(-2 * time.Minute).Unix()}},
+		{name: "有効期限なし", claims: jwt.MapClaims{"exp": nil}},
+		{name: "発行日時が未来", claims: jwt.MapClaims{"iat": time.Now().Add(time.Hour).Unix()}},
+		{name: "nonce不一致", claims: jwt.MapClaims{"nonce": "other-nonce"}},
+		{name: "ユーザーIDなし", claims: jwt.MapClaims{"sub": ""}},
+	}
+	for _, tt := range invalidTests {
+		t.Run("error Verify "+tt.name, func(t *testing.T) {
+			idTokenClaims := testIdTokenClaims()
+			for claim, value := range tt.claims {
+				if value == nil {
+					delete(idTokenClaims, claim)
+					continue
+				}
+				idTokenClaims[claim] = value
+			}
+			rawIdToken := signTestIdToken(t, jwt.SigningMethodRS256, "kid-1", key, idTokenClaims)
+
+			_, err := verifier.Verify(rawIdToken, testOIDCAudience, testOIDCNonce)
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("error Verify 署名不正", func(t *testing.T) {
+		rawIdToken := signTestIdToken(t, jwt.SigningMethodRS256, "kid-1", otherKey, testIdTokenClaims())
+
+		_, err := verifier.Verify(rawIdToken, testOIDCAudience, testOIDCNonce)
+		assert.Error(t, err)
+	})
+
+	t.Run("error Verify 許可していない署名アルゴリズム", func(t *testing.T) {
+		rawIdToken := signTestIdToken(t, jwt.SigningMethodHS256, "kid-1", []byte("secret"), testIdTokenClaims())
+
+		_, err := verifier.Verify(rawIdToken, testOIDCAudience, testOIDCNonce)
+		assert.Error(t, err)
+	})
+
+	t.Run("error Verify 未登録のkid", func(t *testing.T) {
+		rawIdToken := signTestIdToken(t, jwt.SigningMethodRS256, "unknown", key, testIdTokenClaims())
+
+		_, err := verifier.Verify(rawIdToken, testOIDCAudience, testOIDCNonce)
+		assert.Error(t, err)
+	})
+
+	t.Run("error Verify 共有鍵を設定していない場合のHS256", func(t *testing.T) {
+		hsVerifier := NewIdTokenVerifier(server.URL, []string{testOIDCIssuer}, "HS256")
+		rawIdToken := signTestIdToken(t, jwt.SigningMethodHS256, "", []byte("secret"), testIdTokenClaims())
+
+		_, err := hsVerifier.Verify(rawIdToken, testOIDCAudience, testOIDCNonce)
+		assert.Error(t, err)
+	})
+}
+
+func TestIdTokenVerifierVerifySecret(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	server := newTestJwksServer(t, JSONWebKey{
+		Kty: "EC",
+		Use: "sig",
+		Alg: "ES256",
+		Kid: "ec-1",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(key.X.Bytes()),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.Bytes()),
+	})
+	// LINEと同じくチャネルシークレット(HS256)とJWKS(ES256)の両方を受け付ける
+	verifier := NewIdTokenVerifier(server.URL, []string{testOIDCIssuer}, "HS256", "ES256")
+	verifier.Secret = []byte("channel-secret")
+
+	t.Run("success Verify HS256", func(t *testing.T) {
+		rawIdToken := signTestIdToken(t, jwt.SigningMethodHS256, "", []byte("channel-secret"), testIdTokenClaims())
+
+		claims, err := verifier.Verify(rawIdToken, testOIDCAudience, testOIDCNonce)
+		assert.NoError(t, err)
+		assert.Equal(t, "109876543210", claims.Subject)
+	})
+
+	t.Run("success Verify ES256", func(t *testing.T) {
+		rawIdToken := signTestIdToken(t, jwt.SigningMethodES256, "ec-1", key, testIdTokenClaims())
+
+		_, err := verifier.Verify(rawIdToken, testOIDCAudience, testOIDCNonce)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error Verify 共有鍵が異なる", func(t *testing.T) {
+		rawIdToken := signTestIdToken(t, jwt.SigningMethodHS256, "", []byte("other-secret"), testIdTokenClaims())
+
+		_, err := verifier.Verify(rawIdToken, testOIDCAudience, testOIDCNonce)
+		assert.Error(t, err)
+	})
+}
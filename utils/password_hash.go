@@ -0,0 +1,153 @@
+// utils/password_hash.go
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"server/config"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Argon2idのパラメーター
+type Argon2Params struct {
+	// メモリ使用量(KiB)
+	Memory uint32
+	// 反復回数
+	Time uint32
+	// 並列度
+	Parallelism uint8
+	// ソルトの長さ(バイト)
+	SaltLength uint32
+	// ハッシュの長さ(バイト)
+	KeyLength uint32
+}
+
+// PasswordHashParams は新しくハッシュ化する際のパラメーター
+// ARGON2_MEMORY, ARGON2_TIME, ARGON2_PARALLELISM が設定されている場合は InitPasswordHashConfig で上書きする
+var PasswordHashParams = Argon2Params{
+	Memory:      64 * 1024,
+	Time:        3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// PHC文字列形式のアルゴリズム名
+const argon2idPrefix = "$argon2id$"
+
+var ErrInvalidPasswordHash = errors.New("パスワードのハッシュの形式が不正です。")
+var ErrPasswordMismatch = errors.New("パスワードが一致しません。")
+
+// InitPasswordHashConfig は環境変数からArgon2idのパラメーターを設定する
+// 未設定の項目は既定値のままとする
+func InitPasswordHashConfig() error {
+	params := []struct {
+		name  string
+		value string
+		max   uint64
+		dest  func(uint64)
+	}{
+		{"ARGON2_MEMORY", config.GlobalEnv.Argon2Memory, math.MaxUint32, func(v uint64) { PasswordHashParams.Memory = uint32(v) }},
+		{"ARGON2_TIME", config.GlobalEnv.Argon2Time, math.MaxUint32, func(v uint64) { PasswordHashParams.Time = uint32(v) }},
+		{"ARGON2_PARALLELISM", config.GlobalEnv.Argon2Parallelism, math.MaxUint8, func(v uint64) { PasswordHashParams.Parallelism = uint8(v) }},
+	}
+	for _, param := range params {
+		if param.value == "" {
+			continue
+		}
+		value, err := strconv.ParseUint(param.value, 10, 64)
+		if err != nil || value == 0 || value > param.max {
+			return fmt.Errorf("%sは1以上%d以下の整数で指定してください: %s", param.name, param.max, param.value)
+		}
+		param.dest(value)
+	}
+
+	// Argon2idのメモリは並列度×8KiB以上が必要
+	if PasswordHashParams.Memory < 8*uint32(PasswordHashParams.Parallelism) {
+		return errors.New("ARGON2_MEMORYは並列度×8以上の値を指定してください。")
+	}
+	return nil
+}
+
+// hashArgon2id はパスワードをArgon2idでハッシュ化し、PHC文字列形式で返す
+// 例: $argon2id$v=19$m=65536,t=3,p=2$<ソルト>$<ハッシュ>
+func hashArgon2id(password string, params Argon2Params) (string, error) {
+	salt := make([]byte, params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Parallelism, params.KeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix, argon2.Version,
+		params.Memory, params.Time, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// parseArgon2id はPHC文字列形式のハッシュからパラメーター・ソルト・ハッシュを取り出す
+func parseArgon2id(hashedPassword string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", ソルト, ハッシュ
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+	if params.Memory == 0 || params.Time == 0 || params.Parallelism == 0 {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
+
+// comparePasswordHash はハッシュの方式に応じてパスワードを比較する
+// 戻り値はハッシュの再作成が必要かどうか(方式またはパラメーターが現在の設定と異なる)
+func comparePasswordHash(hashedPassword, requestPassword string) (bool, error) {
+	if !strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		// Argon2id導入前に登録したbcryptのハッシュ
+		if err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(requestPassword)); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	params, salt, key, err := parseArgon2id(hashedPassword)
+	if err != nil {
+		return false, err
+	}
+	requestKey := argon2.IDKey([]byte(requestPassword), salt, params.Time, params.Memory, params.Parallelism, params.KeyLength)
+	if subtle.ConstantTimeCompare(key, requestKey) != 1 {
+		return false, ErrPasswordMismatch
+	}
+
+	return params != PasswordHashParams, nil
+}
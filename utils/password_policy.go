@@ -0,0 +1,230 @@
+// utils/password_policy.go
+package utils
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"server/config"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// パスワードポリシー
+type PasswordPolicy struct {
+	// 最小文字数
+	MinLength int
+	// 最大文字数
+	MaxLength int
+	// 英大文字・英小文字・数字・記号をそれぞれ必須とするか
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// 再利用を禁止する直近のパスワードの数(現在のパスワードを含む、0の場合は確認しない)
+	HistoryCount int
+}
+
+// PasswordMaxLengthLimit は設定できる最大文字数の上限
+// ハッシュ化の負荷を抑えるため、サインイン時もこの文字数を超えるパスワードは受け付けない
+const PasswordMaxLengthLimit = 128
+
+// CurrentPasswordPolicy は新しく設定するパスワードに適用するポリシー
+// PASSWORD_* の環境変数が設定されている場合は InitPasswordPolicyConfig で上書きする
+var CurrentPasswordPolicy = PasswordPolicy{
+	MinLength:     8,
+	MaxLength:     24,
+	RequireUpper:  true,
+	RequireSymbol: true,
+	HistoryCount:  5,
+}
+
+// 流出したパスワードのSHA-1ハッシュ(大文字の16進数)
+var breachedPasswordHashes = map[string]struct{}{}
+
+// InitPasswordPolicyConfig は環境変数からパスワードポリシーを設定し、流出したパスワードの一覧を読み込む
+// 未設定の項目は既定値のままとする
+//
+// 環境変数:
+//   - PASSWORD_MIN_LENGTH, PASSWORD_MAX_LENGTH: 文字数
+//   - PASSWORD_CHARACTER_CLASSES: 必須の文字種をカンマ区切りで指定(upper, lower, digit, symbol)
+//   - PASSWORD_HISTORY_COUNT: 再利用を禁止する直近のパスワードの数
+//   - BREACHED_PASSWORDS_FILE: 流出したパスワードのSHA-1ハッシュの一覧ファイル
+func InitPasswordPolicyConfig() error {
+	policy := CurrentPasswordPolicy
+
+	lengths := []struct {
+		name  string
+		value string
+		min   int
+		dest  *int
+	}{
+		{"PASSWORD_MIN_LENGTH", config.GlobalEnv.PasswordMinLength, 1, &policy.MinLength},
+		{"PASSWORD_MAX_LENGTH", config.GlobalEnv.PasswordMaxLength, 1, &policy.MaxLength},
+		{"PASSWORD_HISTORY_COUNT", config.GlobalEnv.PasswordHistoryCount, 0, &policy.HistoryCount},
+	}
+	for _, length := range lengths {
+		if length.value == "" {
+			continue
+		}
+		value, err := strconv.Atoi(length.value)
+		if err != nil || value < length.min {
+			return fmt.Errorf("%sは%d以上の整数で指定してください: %s", length.name, length.min, length.value)
+		}
+		*length.dest = value
+	}
+	if policy.MaxLength > PasswordMaxLengthLimit {
+		return fmt.Errorf("PASSWORD_MAX_LENGTHは%d以下で指定してください: %d", PasswordMaxLengthLimit, policy.MaxLength)
+	}
+	if policy.MinLength > policy.MaxLength {
+		return errors.New("PASSWORD_MIN_LENGTHはPASSWORD_MAX_LENGTH以下で指定してください。")
+	}
+
+	if classes := config.GlobalEnv.PasswordCharacterClasses; classes != "" {
+		policy.RequireUpper, policy.RequireLower, policy.RequireDigit, policy.RequireSymbol = false, false, false, false
+		for _, class := range strings.Split(classes, ",") {
+			switch strings.TrimSpace(class) {
+			case "upper":
+				policy.RequireUpper = true
+			case "lower":
+				policy.RequireLower = true
+			case "digit":
+				policy.RequireDigit = true
+			case "symbol":
+				policy.RequireSymbol = true
+			case "", "none":
+			default:
+				return fmt.Errorf("PASSWORD_CHARACTER_CLASSESはupper, lower, digit, symbolから指定してください: %s", class)
+			}
+		}
+	}
+
+	if path := config.GlobalEnv.BreachedPasswordsFile; path != "" {
+		hashes, err := LoadBreachedPasswords(path)
+		if err != nil {
+			return err
+		}
+		breachedPasswordHashes = hashes
+	}
+
+	CurrentPasswordPolicy = policy
+	return nil
+}
+
+// LoadBreachedPasswords は流出したパスワードのSHA-1ハッシュの一覧ファイルを読み込む
+// 1行に1件のハッシュを記載し、"ハッシュ:件数" の形式(Have I Been Pwned の形式)も読み込める
+// 空行と # から始まる行は読み飛ばす
+//
+// 引数:
+//   - path: 一覧ファイルのパス
+//
+// 戻り値:
+//
+//	戻り値1: 大文字の16進数のハッシュの集合
+//	戻り値2: エラー内容(エラーがない場合はnil)
+//
+
+func LoadBreachedPasswords(path string) (map[string]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("流出したパスワードの一覧を読み込めません: %v", err)
+	}
+	defer file.Close()
+
+	hashes := map[string]struct{}{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		hash, _, _ := strings.Cut(text, ":")
+		if len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("流出したパスワードの一覧の形式が間違っています(%d行目)", line)
+		}
+		if _, err := hex.DecodeString(hash); err != nil {
+			return nil, fmt.Errorf("流出したパスワードの一覧の形式が間違っています(%d行目)", line)
+		}
+		hashes[strings.ToUpper(hash)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("流出したパスワードの一覧を読み込めません: %v", err)
+	}
+	return hashes, nil
+}
+
+// IsBreachedPassword はパスワードが流出したパスワードの一覧に含まれるかを返す
+func IsBreachedPassword(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	_, ok := breachedPasswordHashes[strings.ToUpper(hex.EncodeToString(sum[:]))]
+	return ok
+}
+
+// CheckPasswordPolicy はパスワードがポリシーを満たしているかを確認する
+//
+// 引数:
+//   - label: メッセージに表示する項目名(例: パスワード、新しいパスワード)
+//   - password: 平文のパスワード
+//
+// 戻り値:
+//
+//	戻り値1: 満たしていない項目ごとの理由(全て満たしている場合は空)
+//
+
+func CheckPasswordPolicy(label, password string) []string {
+	var reasons []string
+	policy := CurrentPasswordPolicy
+
+	length := utf8.RuneCountInString(password)
+	if length < policy.MinLength {
+		reasons = append(reasons, fmt.Sprintf("%sは%d文字以上で入力してください。", label, policy.MinLength))
+	}
+	if length > policy.MaxLength {
+		reasons = append(reasons, fmt.Sprintf("%sは%d文字以内で入力してください。", label, policy.MaxLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+	classes := []struct {
+		required bool
+		found    bool
+		name     string
+	}{
+		{policy.RequireUpper, hasUpper, "英大文字"},
+		{policy.RequireLower, hasLower, "英小文字"},
+		{policy.RequireDigit, hasDigit, "数字"},
+		{policy.RequireSymbol, hasSymbol, "記号"},
+	}
+	for _, class := range classes {
+		if class.required && !class.found {
+			reasons = append(reasons, fmt.Sprintf("%sには%sを1文字以上含めてください。", label, class.name))
+		}
+	}
+
+	if IsBreachedPassword(password) {
+		reasons = append(reasons, fmt.Sprintf("%sは過去に流出したパスワードのため使用できません。", label))
+	}
+
+	return reasons
+}
+
+// PasswordHistoryMessage は直近に使用したパスワードを再利用した場合の理由を返す
+func PasswordHistoryMessage(label string) string {
+	return fmt.Sprintf("%sは直近%d回に使用したパスワード以外を指定してください。", label, CurrentPasswordPolicy.HistoryCount)
+}
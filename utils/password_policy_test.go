@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"server/config"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// usePasswordPolicy はテスト中のみパスワードポリシーと流出したパスワードの一覧を差し替える
+func usePasswordPolicy(t *testing.T, policy PasswordPolicy, hashes map[string]struct{}) {
+	defaultPolicy, defaultHashes := CurrentPasswordPolicy, breachedPasswordHashes
+	t.Cleanup(func() {
+		CurrentPasswordPolicy, breachedPasswordHashes = defaultPolicy, defaultHashes
+	})
+	CurrentPasswordPolicy, breachedPasswordHashes = policy, hashes
+}
+
+func TestCheckPasswordPolicy(t *testing.T) {
+
+	t.Run("CheckPasswordPolicy 既定のポリシーを満たす", func(t *testing.T) {
+		assert.Empty(t, CheckPasswordPolicy("パスワード", "Test12345!"))
+	})
+
+	t.Run("CheckPasswordPolicy 満たしていない項目ごとに理由を返す", func(t *testing.T) {
+		usePasswordPolicy(t, PasswordPolicy{
+			MinLength:     10,
+			MaxLength:     12,
+			RequireUpper:  true,
+			RequireLower:  true,
+			RequireDigit:  true,
+			RequireSymbol: true,
+		}, map[string]struct{}{})
+
+		assert.Equal(t, []string{
+			"新しいパスワードは10文字以上で入力してください。",
+			"新しいパスワードには英大文字を1文字以上含めてください。",
+			"新しいパスワードには数字を1文字以上含めてください。",
+			"新しいパスワードには記号を1文字以上含めてください。",
+		}, CheckPasswordPolicy("新しいパスワード", "abc"))
+		assert.Equal(t, []string{
+			"パスワードは12文字以内で入力してください。",
+		}, CheckPasswordPolicy("パスワード", "Test12345!Test"))
+	})
+
+	t.Run("CheckPasswordPolicy 文字数はバイト数でなく文字数で数える", func(t *testing.T) {
+		usePasswordPolicy(t, PasswordPolicy{MinLength: 4, MaxLength: 4}, map[string]struct{}{})
+
+		assert.Empty(t, CheckPasswordPolicy("パスワード", "たくわえ"))
+	})
+
+	t.Run("CheckPasswordPolicy 流出したパスワード", func(t *testing.T) {
+		// SHA-1("Password1!")
+		usePasswordPolicy(t, CurrentPasswordPolicy, map[string]struct{}{
+			"32CA9FC1A0F5B6330E3F4C8C1BBECDE9BEDB9573": {},
+		})
+
+		assert.Equal(t, []string{
+			"パスワードは過去に流出したパスワードのため使用できません。",
+		}, CheckPasswordPolicy("パスワード", "Password1!"))
+	})
+}
+
+// writeBreachedPasswords はテスト用の流出したパスワードの一覧ファイルを作成する
+func writeBreachedPasswords(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Error writing breached passwords: %v", err)
+	}
+	return path
+}
+
+func TestLoadBreachedPasswords(t *testing.T) {
+
+	t.Run("LoadBreachedPasswords ハッシュのみと件数付きの形式を読み込む", func(t *testing.T) {
+		path := writeBreachedPasswords(t, "# 流出したパスワード\n"+
+			"5baa61e4c9b93f3f0682250b6cf8331b7ee68fd8\n"+
+			"\n"+
+			"7C4A8D09CA3762AF61E59520943DC26494F8941B:24230577\n")
+
+		hashes, err := LoadBreachedPasswords(path)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]struct{}{
+			"5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8": {},
+			"7C4A8D09CA3762AF61E59520943DC26494F8941B": {},
+		}, hashes)
+	})
+
+	t.Run("LoadBreachedPasswords 形式が間違っている", func(t *testing.T) {
+		path := writeBreachedPasswords(t, "5baa61e4c9b93f3f0682250b6cf8331b7ee68fd8\npassword\n")
+
+		_, err := LoadBreachedPasswords(path)
+
+		assert.EqualError(t, err, "流出したパスワードの一覧の形式が間違っています(2行目)")
+	})
+
+	t.Run("LoadBreachedPasswords ファイルが存在しない", func(t *testing.T) {
+		_, err := LoadBreachedPasswords(filepath.Join(t.TempDir(), "none.txt"))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestInitPasswordPolicyConfig(t *testing.T) {
+	defaultEnv := config.GlobalEnv
+	t.Cleanup(func() { config.GlobalEnv = defaultEnv })
+
+	t.Run("InitPasswordPolicyConfig 環境変数で上書きできること", func(t *testing.T) {
+		usePasswordPolicy(t, CurrentPasswordPolicy, map[string]struct{}{})
+		config.GlobalEnv = defaultEnv
+		config.GlobalEnv.PasswordMinLength = "12"
+		config.GlobalEnv.PasswordMaxLength = "64"
+		config.GlobalEnv.PasswordCharacterClasses = "lower, digit"
+		config.GlobalEnv.PasswordHistoryCount = "0"
+		config.GlobalEnv.BreachedPasswordsFile = writeBreachedPasswords(t, "5baa61e4c9b93f3f0682250b6cf8331b7ee68fd8\n")
+
+		assert.NoError(t, InitPasswordPolicyConfig())
+		assert.Equal(t, PasswordPolicy{
+			MinLength:    12,
+			MaxLength:    64,
+			RequireLower: true,
+			RequireDigit: true,
+			HistoryCount: 0,
+		}, CurrentPasswordPolicy)
+		assert.True(t, IsBreachedPassword("password"))
+	})
+
+	t.Run("InitPasswordPolicyConfig 最小文字数が最大文字数を超える", func(t *testing.T) {
+		usePasswordPolicy(t, CurrentPasswordPolicy, map[string]struct{}{})
+		config.GlobalEnv = defaultEnv
+		config.GlobalEnv.PasswordMinLength = "30"
+
+		assert.EqualError(t, InitPasswordPolicyConfig(), "PASSWORD_MIN_LENGTHはPASSWORD_MAX_LENGTH以下で指定してください。")
+	})
+
+	t.Run("InitPasswordPolicyConfig 最大文字数の上限を超える", func(t *testing.T) {
+		usePasswordPolicy(t, CurrentPasswordPolicy, map[string]struct{}{})
+		config.GlobalEnv = defaultEnv
+		config.GlobalEnv.PasswordMaxLength = "129"
+
+		assert.EqualError(t, InitPasswordPolicyConfig(), "PASSWORD_MAX_LENGTHは128以下で指定してください: 129")
+	})
+
+	t.Run("InitPasswordPolicyConfig 不明な文字種", func(t *testing.T) {
+		usePasswordPolicy(t, CurrentPasswordPolicy, map[string]struct{}{})
+		config.GlobalEnv = defaultEnv
+		config.GlobalEnv.PasswordCharacterClasses = "upper,kana"
+
+		assert.EqualError(t, InitPasswordPolicyConfig(), "PASSWORD_CHARACTER_CLASSESはupper, lower, digit, symbolから指定してください: kana")
+	})
+}
@@ -0,0 +1,157 @@
+// utils/refresh_token.go
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// RefreshTokenFamily はサインイン毎に発行するリフレッシュトークンの系列
+// ローテーションの度に TokenId を最新のトークンのものへ更新する
+type RefreshTokenFamily struct {
+	UserId    int    `json:"user_id"`
+	SessionId string `json:"session_id"`
+	TokenId   string `json:"token_id"`
+}
+
+var (
+	// ErrRefreshTokenInvalid はファミリーが失効済み又はクレームが不正な場合のエラー
+	ErrRefreshTokenInvalid = errors.New("リフレッシュトークンが無効です。")
+	// ErrRefreshTokenReused は使用済みのリフレッシュトークンが提示された場合のエラー
+	ErrRefreshTokenReused = errors.New("使用済みのリフレッシュトークンが提示されました。")
+)
+
+// refreshTokenFamilyKey はファミリーを保存するRedisのキー
+func refreshTokenFamilyKey(familyId string) string {
+	return fmt.Sprintf("refresh_family:%s", familyId)
+}
+
+// generateRefreshJWT はファミリーIDとトークンIDをクレームに含めたリフレッシュトークンを生成する
+func (ud *UtilsDataFetcher) generateRefreshJWT(family RefreshTokenFamily, familyId string, ExpirationDate int) (string, error) {
+	now := time.Now()
+	claims := typedClaims(TokenTypeRefresh, family.UserId, jwt.MapClaims{
+		"sid": family.SessionId,
+		"fid": familyId,
+		"jti": family.TokenId,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Duration(ExpirationDate) * time.Hour).Unix(),
+	})
+
+	return ud.KeyManager.Sign(claims)
+}
+
+// 新規有効期限付きのリフレッシュトークン発行
+// 新しいファミリーを作成してRedisに保存する
+func (ud *UtilsDataFetcher) RefreshToken(UserId int, SessionId string, ExpirationDate int) (string, error) {
+	familyId := uuid.New().String()
+
+	family := RefreshTokenFamily{
+		UserId:    UserId,
+		SessionId: SessionId,
+		TokenId:   uuid.New().String(),
+	}
+	duration := time.Duration(ExpirationDate) * time.Hour
+	if err := ud.RedisService.RedisSet(refreshTokenFamilyKey(familyId), family, duration); err != nil {
+		return "", err
+	}
+
+	return ud.generateRefreshJWT(family, familyId, ExpirationDate)
+}
+
+// RotateRefreshToken はリフレッシュトークンを新しいものに交換し、古いトークンを無効にする
+// 使用済みのトークンが提示された場合は漏洩とみなしてファミリー全体を失効させる
+//
+// 引数:
+//   - claims: 検証済みのリフレッシュトークンのクレーム
+//   - ExpirationDate: 新しいリフレッシュトークンの有効期限(時間)
+//
+// 戻り値:
+//   - string: 新しいリフレッシュトークン
+//   - error: ErrRefreshTokenInvalid 又は ErrRefreshTokenReused
+
+func (ud *UtilsDataFetcher) RotateRefreshToken(claims jwt.MapClaims, ExpirationDate int) (string, error) {
+	if err := CheckTokenType(claims, TokenTypeRefresh); err != nil {
+		return "", ErrRefreshTokenInvalid
+	}
+	familyId, _ := claims["fid"].(string)
+	tokenId, _ := claims["jti"].(string)
+	userId, ok := claims["UserId"].(float64)
+	if familyId == "" || tokenId == "" || !ok {
+		return "", ErrRefreshTokenInvalid
+	}
+
+	// パスワード変更等でユーザーのトークンが全て失効している場合
+	revoked, err := ud.IsTokenRevoked(claims)
+	if err != nil {
+		return "", err
+	}
+	if revoked {
+		return "", ErrRefreshTokenInvalid
+	}
+
+	key := refreshTokenFamilyKey(familyId)
+	value, err := ud.RedisService.RedisGet(key)
+	if err != nil {
+		// 有効期限切れ又は失効済みのファミリー
+		return "", ErrRefreshTokenInvalid
+	}
+
+	var family RefreshTokenFamily
+	if err := json.Unmarshal([]byte(value), &family); err != nil {
+		return "", ErrRefreshTokenInvalid
+	}
+	// ファミリーを作成したユーザー以外のトークンは受け付けない
+	if family.UserId != int(userId) {
+		return "", ErrRefreshTokenInvalid
+	}
+
+	if family.TokenId != tokenId {
+		if err := ud.RedisService.RedisDel(key); err != nil {
+			return "", err
+		}
+		return "", ErrRefreshTokenReused
+	}
+
+	// 同じトークンで同時に交換された場合に両方へ発行しないよう、取得した値から変わっていない場合のみ更新する
+	family.TokenId = uuid.New().String()
+	duration := time.Duration(ExpirationDate) * time.Hour
+	swapped, err := ud.RedisService.RedisCompareAndSet(key, value, family, duration)
+	if err != nil {
+		return "", err
+	}
+	if !swapped {
+		// 先に交換されたトークンは使用済みとなるため、再利用として扱う
+		if err := ud.RedisService.RedisDel(key); err != nil {
+			return "", err
+		}
+		return "", ErrRefreshTokenReused
+	}
+
+	return ud.generateRefreshJWT(family, familyId, ExpirationDate)
+}
+
+// RevokeRefreshTokenFamily はリフレッシュトークンのファミリーを失効させる
+// サインアウト時などに使用する
+func (ud *UtilsDataFetcher) RevokeRefreshTokenFamily(refreshToken string) error {
+	token, err := ud.ParseWithClaims(refreshToken)
+	if err != nil {
+		return err
+	}
+	claims, ok := ud.MapClaims(token.(*jwt.Token))
+	if !ok {
+		return ErrRefreshTokenInvalid
+	}
+	if err := CheckTokenType(claims.(jwt.MapClaims), TokenTypeRefresh); err != nil {
+		return ErrRefreshTokenInvalid
+	}
+	familyId, _ := claims.(jwt.MapClaims)["fid"].(string)
+	if familyId == "" {
+		return ErrRefreshTokenInvalid
+	}
+	return ud.RedisService.RedisDel(refreshTokenFamilyKey(familyId))
+}
@@ -0,0 +1,110 @@
+// utils/revocation.go
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// revokedTokenKey は失効したトークンのjtiを保存するRedisのキー
+func revokedTokenKey(tokenId string) string {
+	return fmt.Sprintf("revoked_token:%s", tokenId)
+}
+
+// revokedUserKey はユーザーのトークンを一括で失効させた日時を保存するRedisのキー
+func revokedUserKey(UserId int) string {
+	return fmt.Sprintf("revoked_user:%d", UserId)
+}
+
+// revokedSessionKey は失効したセッションIDを保存するRedisのキー
+func revokedSessionKey(SessionId string) string {
+	return fmt.Sprintf("revoked_session:%s", SessionId)
+}
+
+// claimUserId はクレームからユーザーIDを取得する
+func claimUserId(claims jwt.MapClaims) (int, bool) {
+	userId, ok := claims["UserId"].(float64)
+	return int(userId), ok
+}
+
+// RevokeToken はトークンのjtiを失効リストに登録する
+// 失効リストはトークンの有効期限が切れるまで保持する
+func (ud *UtilsDataFetcher) RevokeToken(claims jwt.MapClaims) error {
+	tokenId, _ := claims["jti"].(string)
+	exp, ok := claims["exp"].(float64)
+	if tokenId == "" || !ok {
+		return fmt.Errorf("失効対象のトークンのクレームが不正です。")
+	}
+
+	duration := time.Until(time.Unix(int64(exp), 0))
+	if duration <= 0 {
+		// 有効期限切れのトークンは登録不要
+		return nil
+	}
+	return ud.RedisService.RedisSet(revokedTokenKey(tokenId), "1", duration)
+}
+
+// RevokeUserTokens はユーザーにこれまで発行した全てのトークンを失効させる
+// 失効させた日時より前に発行(iat)されたトークンを無効とし、最も長いトークンの有効期限まで保持する
+func (ud *UtilsDataFetcher) RevokeUserTokens(UserId int) error {
+	duration := time.Duration(RefreshAuthTokenHour) * time.Hour
+	revokedAt := strconv.FormatInt(time.Now().Unix(), 10)
+	return ud.RedisService.RedisSet(revokedUserKey(UserId), revokedAt, duration)
+}
+
+// RevokeSession はセッションに紐づく全てのトークンを失効させる
+// セッションで発行されるトークンの最も長い有効期限まで保持する
+func (ud *UtilsDataFetcher) RevokeSession(SessionId string) error {
+	duration := time.Duration(RefreshAuthTokenHour) * time.Hour
+	return ud.RedisService.RedisSet(revokedSessionKey(SessionId), "1", duration)
+}
+
+// IsTokenRevoked はトークンが失効しているかを返す
+//
+// 引数:
+//   - claims: 検証済みのトークンのクレーム
+//
+// 戻り値:
+//   - bool: 失効している場合はtrue
+//   - error: Redisの参照に失敗した場合のエラー
+
+func (ud *UtilsDataFetcher) IsTokenRevoked(claims jwt.MapClaims) (bool, error) {
+	// jtiを持たない(失効リスト導入前の)トークンは受け付けない
+	tokenId, _ := claims["jti"].(string)
+	if tokenId == "" {
+		return true, nil
+	}
+
+	revoked, err := ud.RedisService.RedisExists(revokedTokenKey(tokenId))
+	if err != nil || revoked {
+		return revoked, err
+	}
+
+	// 他の端末からサインアウトされたセッション
+	if sessionId, _ := claims["sid"].(string); sessionId != "" {
+		revoked, err := ud.RedisService.RedisExists(revokedSessionKey(sessionId))
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	userId, ok := claimUserId(claims)
+	if !ok {
+		return true, nil
+	}
+	key := revokedUserKey(userId)
+	exists, err := ud.RedisService.RedisExists(key)
+	if err != nil || !exists {
+		return false, err
+	}
+	value, err := ud.RedisService.RedisGet(key)
+	if err != nil {
+		return false, err
+	}
+	revokedAt, _ := strconv.ParseInt(value, 10, 64)
+	issuedAt, _ := claims["iat"].(float64)
+	return int64(issuedAt) < revokedAt, nil
+}
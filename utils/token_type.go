@@ -0,0 +1,111 @@
+// utils/token_type.go
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"server/config"
+	"slices"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// トークンの種類(typクレーム)
+const (
+	TokenTypeAccess    = "access"
+	TokenTypeRefresh   = "refresh"
+	TokenTypeMagicLink = "magic_link"
+)
+
+// トークンの利用先(audクレーム)
+// リフレッシュトークンはトークンの再発行APIでのみ、マジックリンクはサインインAPIでのみ受け付ける
+const (
+	AccessTokenAudience    = "money_management_api"
+	RefreshTokenAudience   = "money_management_refresh"
+	MagicLinkTokenAudience = "money_management_magic_link"
+)
+
+// JwtIssuer はトークンの発行者(issクレーム)
+// JWT_ISSUER が設定されている場合は InitTokenConfig で上書きする
+var JwtIssuer = "money_management_server"
+
+var ErrInvalidTokenType = errors.New("トークンの種類が不正です。")
+
+// tokenAudience はトークンの種類に対応する利用先を返す
+func tokenAudience(tokenType string) string {
+	switch tokenType {
+	case TokenTypeRefresh:
+		return RefreshTokenAudience
+	case TokenTypeMagicLink:
+		return MagicLinkTokenAudience
+	}
+	return AccessTokenAudience
+}
+
+// typedClaims はトークンの種類に応じた共通のクレームを作成する
+func typedClaims(tokenType string, UserId int, claims jwt.MapClaims) jwt.MapClaims {
+	claims["UserId"] = UserId
+	claims["typ"] = tokenType
+	claims["aud"] = tokenAudience(tokenType)
+	claims["iss"] = JwtIssuer
+	return claims
+}
+
+// CheckTokenType はクレームが期待する種類・利用先・発行者のトークンかを確認する
+// アクセストークンとリフレッシュトークンを取り違えて受け付けないようにする
+//
+// 引数:
+//   - claims: 検証済みのトークンのクレーム
+//   - tokenType: 期待するトークンの種類(TokenTypeAccess, TokenTypeRefresh 又は TokenTypeMagicLink)
+//
+// 戻り値:
+//
+//	戻り値1: 種類が一致しない場合は ErrInvalidTokenType
+//
+
+func CheckTokenType(claims jwt.MapClaims, tokenType string) error {
+	if typ, _ := claims["typ"].(string); typ != tokenType {
+		return ErrInvalidTokenType
+	}
+	if issuer, err := claims.GetIssuer(); err != nil || issuer != JwtIssuer {
+		return ErrInvalidTokenType
+	}
+	audience, err := claims.GetAudience()
+	if err != nil || !slices.Contains(audience, tokenAudience(tokenType)) {
+		return ErrInvalidTokenType
+	}
+	return nil
+}
+
+// InitTokenConfig は環境変数からトークンの発行者と有効期限を設定する
+// 未設定の項目は既定値のままとする
+func InitTokenConfig() error {
+	if config.GlobalEnv.JwtIssuer != "" {
+		JwtIssuer = config.GlobalEnv.JwtIssuer
+	}
+
+	hours := []struct {
+		name  string
+		value string
+		dest  *int
+	}{
+		{"ACCESS_TOKEN_HOUR", config.GlobalEnv.AccessTokenHour, &AuthTokenHour},
+		{"REFRESH_TOKEN_HOUR", config.GlobalEnv.RefreshTokenHour, &RefreshAuthTokenHour},
+	}
+	for _, hour := range hours {
+		if hour.value == "" {
+			continue
+		}
+		value, err := strconv.Atoi(hour.value)
+		if err != nil || value <= 0 {
+			return fmt.Errorf("%sは1以上の整数で指定してください: %s", hour.name, hour.value)
+		}
+		*hour.dest = value
+	}
+
+	if AuthTokenHour > RefreshAuthTokenHour {
+		return errors.New("アクセストークンの有効期限はリフレッシュトークン以下にしてください。")
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"server/config"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTokenType(t *testing.T) {
+	utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys}
+
+	t.Run("CheckTokenType アクセストークン", func(t *testing.T) {
+		tokenString, _ := utilsFetcher.NewToken(1, "session1", "user", 1)
+		token, _ := utilsFetcher.ParseWithClaims(tokenString)
+		claims := token.(*jwt.Token).Claims.(jwt.MapClaims)
+
+		assert.NoError(t, CheckTokenType(claims, TokenTypeAccess))
+		assert.ErrorIs(t, CheckTokenType(claims, TokenTypeRefresh), ErrInvalidTokenType)
+	})
+
+	t.Run("CheckTokenType 発行者が異なる", func(t *testing.T) {
+		claims := jwt.MapClaims{
+			"typ": TokenTypeAccess,
+			"aud": AccessTokenAudience,
+			"iss": "other",
+		}
+
+		assert.ErrorIs(t, CheckTokenType(claims, TokenTypeAccess), ErrInvalidTokenType)
+	})
+
+	t.Run("CheckTokenType 利用先が異なる", func(t *testing.T) {
+		claims := jwt.MapClaims{
+			"typ": TokenTypeRefresh,
+			"aud": []interface{}{AccessTokenAudience},
+			"iss": JwtIssuer,
+		}
+
+		assert.ErrorIs(t, CheckTokenType(claims, TokenTypeRefresh), ErrInvalidTokenType)
+	})
+
+	t.Run("CheckTokenType 種類のないトークン", func(t *testing.T) {
+		claims := jwt.MapClaims{"UserId": float64(1)}
+
+		assert.ErrorIs(t, CheckTokenType(claims, TokenTypeAccess), ErrInvalidTokenType)
+	})
+}
+
+func TestInitTokenConfig(t *testing.T) {
+	// 他のテストに影響しないように元の設定に戻す
+	defer func(env config.Env, issuer string, access, refresh int) {
+		config.GlobalEnv = env
+		JwtIssuer = issuer
+		AuthTokenHour = access
+		RefreshAuthTokenHour = refresh
+	}(config.GlobalEnv, JwtIssuer, AuthTokenHour, RefreshAuthTokenHour)
+
+	t.Run("InitTokenConfig 環境変数で上書きする", func(t *testing.T) {
+		config.GlobalEnv.JwtIssuer = "issuer"
+		config.GlobalEnv.AccessTokenHour = "2"
+		config.GlobalEnv.RefreshTokenHour = "48"
+
+		err := InitTokenConfig()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "issuer", JwtIssuer)
+		assert.Equal(t, 2, AuthTokenHour)
+		assert.Equal(t, 48, RefreshAuthTokenHour)
+	})
+
+	t.Run("InitTokenConfig 整数以外", func(t *testing.T) {
+		config.GlobalEnv.AccessTokenHour = "1h"
+
+		err := InitTokenConfig()
+
+		assert.EqualError(t, err, "ACCESS_TOKEN_HOURは1以上の整数で指定してください: 1h")
+	})
+
+	t.Run("InitTokenConfig アクセストークンの方が長い", func(t *testing.T) {
+		config.GlobalEnv.AccessTokenHour = "24"
+		config.GlobalEnv.RefreshTokenHour = "12"
+
+		err := InitTokenConfig()
+
+		assert.EqualError(t, err, "アクセストークンの有効期限はリフレッシュトークン以下にしてください。")
+	})
+}
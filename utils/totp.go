@@ -0,0 +1,161 @@
+// utils/totp.go
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP(RFC 6238)の設定
+// Google Authenticator 等の認証アプリが対応する既定値(SHA1・6桁・30秒)とする
+const (
+	TotpIssuer = "たくわえる"
+	TotpDigits = 6
+	TotpPeriod = 30
+	// 端末の時刻のずれを考慮して前後1ステップまで受け付ける
+	TotpSkew = 1
+	// シークレットのバイト数(RFC 4226 の推奨値)
+	totpSecretBytes = 20
+)
+
+// リカバリーコードの設定
+const (
+	RecoveryCodeCount = 10
+	// 1つのリカバリーコードの文字数(区切りのハイフンを除く)
+	recoveryCodeLength = 10
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTotpSecret はTOTPのシークレットをBase32で生成する
+func GenerateTotpSecret() (string, error) {
+	secret := make([]byte, totpSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("シークレットの生成に失敗しました: %v", err)
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// TotpURI は認証アプリにQRコードで読み込ませる otpauth URI を返す
+//
+// 引数:
+//   - secret: Base32のシークレット
+//   - accountName: 認証アプリに表示するアカウント名(メールアドレス)
+//
+// 戻り値:
+//
+//	戻り値1: otpauth://totp/ 形式のURI
+//
+
+func TotpURI(secret, accountName string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", TotpIssuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprintf("%d", TotpDigits))
+	query.Set("period", fmt.Sprintf("%d", TotpPeriod))
+
+	label := url.PathEscape(TotpIssuer + ":" + accountName)
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, query.Encode())
+}
+
+// TotpStep は時刻に対応するTOTPのステップ(30秒毎のカウンター)を返す
+func TotpStep(t time.Time) int64 {
+	return t.Unix() / TotpPeriod
+}
+
+// totpCode はステップに対応するワンタイムパスワードを生成する(RFC 4226 HOTP)
+func totpCode(key []byte, step int64) string {
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	// 動的切り捨て
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < TotpDigits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", TotpDigits, value%modulo)
+}
+
+// TotpCode は時刻に対応するワンタイムパスワードを返す
+func TotpCode(secret string, t time.Time) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("シークレットの形式が不正です: %v", err)
+	}
+	return totpCode(key, TotpStep(t)), nil
+}
+
+// ValidateTotp はワンタイムパスワードを検証し、一致したステップを返す
+// 同じコードの再利用を防ぐため、呼び出し側で使用済みのステップを記録する
+//
+// 引数:
+//   - secret: Base32のシークレット
+//   - code: 入力されたワンタイムパスワード
+//   - t: 検証する時刻
+//
+// 戻り値:
+//
+//	戻り値1: 一致したステップ
+//	戻り値2: 一致した場合はtrue
+//
+
+func ValidateTotp(secret, code string, t time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != TotpDigits {
+		return 0, false
+	}
+
+	current := TotpStep(t)
+	for step := current - TotpSkew; step <= current+TotpSkew; step++ {
+		if hmac.Equal([]byte(totpCode(key, step)), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// GenerateRecoveryCodes は1回のみ使用できるリカバリーコードを生成する
+// 読み間違いを減らすため、小文字のBase32を5文字毎にハイフンで区切る
+func GenerateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, RecoveryCodeCount)
+	for i := 0; i < RecoveryCodeCount; i++ {
+		random := make([]byte, recoveryCodeLength*5/8)
+		if _, err := rand.Read(random); err != nil {
+			return nil, fmt.Errorf("リカバリーコードの生成に失敗しました: %v", err)
+		}
+		code := strings.ToLower(totpEncoding.EncodeToString(random))
+		half := recoveryCodeLength / 2
+		codes = append(codes, code[:half]+"-"+code[half:])
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode は入力されたリカバリーコードから区切り文字を除き、小文字に揃える
+func NormalizeRecoveryCode(code string) string {
+	replacer := strings.NewReplacer("-", "", " ", "")
+	return strings.ToLower(replacer.Replace(code))
+}
+
+// HashRecoveryCode はリカバリーコードを保存用にハッシュ化する
+// 十分な長さの乱数のため、パスワードと異なりソルトなしのSHA-256とする
+func HashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(NormalizeRecoveryCode(code)))
+	return hex.EncodeToString(sum[:])
+}
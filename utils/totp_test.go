@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 6238 Appendix B のテスト用シークレット("12345678901234567890")
+const rfcTotpSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTotpCode(t *testing.T) {
+	// RFC 6238 Appendix B (SHA1) の8桁の値の下6桁
+	cases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, expected := range cases {
+		code, err := TotpCode(rfcTotpSecret, time.Unix(unix, 0))
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code)
+	}
+
+	t.Run("TotpCode シークレットの形式が不正", func(t *testing.T) {
+		_, err := TotpCode("!!", time.Now())
+
+		assert.ErrorContains(t, err, "シークレットの形式が不正です")
+	})
+}
+
+func TestValidateTotp(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+
+	t.Run("ValidateTotp 現在のコード", func(t *testing.T) {
+		step, ok := ValidateTotp(rfcTotpSecret, "081804", now)
+
+		assert.True(t, ok)
+		assert.Equal(t, TotpStep(now), step)
+	})
+
+	t.Run("ValidateTotp 前後1ステップのずれは許容する", func(t *testing.T) {
+		previous, _ := TotpCode(rfcTotpSecret, now.Add(-TotpPeriod*time.Second))
+
+		step, ok := ValidateTotp(rfcTotpSecret, previous, now)
+
+		assert.True(t, ok)
+		assert.Equal(t, TotpStep(now)-1, step)
+	})
+
+	t.Run("ValidateTotp 2ステップ以上のずれは受け付けない", func(t *testing.T) {
+		old, _ := TotpCode(rfcTotpSecret, now.Add(-2*TotpPeriod*time.Second))
+
+		_, ok := ValidateTotp(rfcTotpSecret, old, now)
+
+		assert.False(t, ok)
+	})
+
+	t.Run("ValidateTotp 桁数不正", func(t *testing.T) {
+		_, ok := ValidateTotp(rfcTotpSecret, "81804", now)
+
+		assert.False(t, ok)
+	})
+}
+
+func TestGenerateTotpSecret(t *testing.T) {
+	secret, err := GenerateTotpSecret()
+
+	assert.NoError(t, err)
+	// 20バイトのBase32(パディングなし)
+	assert.Len(t, secret, 32)
+
+	_, err = TotpCode(secret, time.Now())
+	assert.NoError(t, err)
+}
+
+func TestTotpURI(t *testing.T) {
+	uri := TotpURI(rfcTotpSecret, "test@example.com")
+
+	parsed, err := url.Parse(uri)
+	assert.NoError(t, err)
+	assert.Equal(t, "otpauth", parsed.Scheme)
+	assert.Equal(t, "totp", parsed.Host)
+	assert.Equal(t, "/たくわえる:test@example.com", parsed.Path)
+	assert.Equal(t, rfcTotpSecret, parsed.Query().Get("secret"))
+	assert.Equal(t, TotpIssuer, parsed.Query().Get("issuer"))
+	assert.Equal(t, "6", parsed.Query().Get("digits"))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes()
+
+	assert.NoError(t, err)
+	assert.Len(t, codes, RecoveryCodeCount)
+	for _, code := range codes {
+		assert.Regexp(t, regexp.MustCompile(`^[a-z2-7]{5}-[a-z2-7]{5}$`), code)
+	}
+
+	t.Run("HashRecoveryCode 区切り文字と大文字・小文字は区別しない", func(t *testing.T) {
+		assert.Equal(t, HashRecoveryCode("abcde-fghij"), HashRecoveryCode("ABCDE FGHIJ"))
+		assert.NotEqual(t, HashRecoveryCode("abcde-fghij"), HashRecoveryCode("abcde-fghik"))
+	})
+}
@@ -8,17 +8,25 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/google/uuid"
 	"gopkg.in/gomail.v2"
 )
 
 // UtilsFetcher インターフェースの定義
 type UtilsFetcher interface {
 	GenerateJWT(UserId int, ExpirationDate int) (string, error)
-	NewToken(UserId int, ExpirationDate int) (string, error)
-	RefreshToken(UserId int, ExpirationDate int) (string, error)
+	NewToken(UserId int, SessionId string, Role string, ExpirationDate int) (string, error)
+	RefreshToken(UserId int, SessionId string, ExpirationDate int) (string, error)
+	RotateRefreshToken(claims jwt.MapClaims, ExpirationDate int) (string, error)
+	RevokeRefreshTokenFamily(refreshToken string) error
+	RevokeToken(claims jwt.MapClaims) error
+	RevokeUserTokens(UserId int) error
+	RevokeSession(SessionId string) error
+	IsTokenRevoked(claims jwt.MapClaims) (bool, error)
+	NewMagicLinkToken(UserId int, DeviceId string) (string, error)
+	ConsumeMagicLinkToken(magicLinkToken, DeviceId string) (int, error)
 	EncryptPassword(password string) (string, error)
-	CompareHashPassword(hashedPassword, requestPassword string) error
+	CompareHashPassword(hashedPassword, requestPassword string) (string, error)
 	ParseWithClaims(validationToken string) (interface{}, error)
 	MapClaims(token *jwt.Token) (interface{}, bool)
 	SendMail(toEmail, subject, body string, isHTML bool) error
@@ -44,8 +52,9 @@ func (s *SMTPMailDialer) DialAndSend(m ...*gomail.Message) error {
 }
 
 type UtilsDataFetcher struct {
-	JwtSecret  []byte
-	MailDialer MailDialer
+	KeyManager   *KeyManager
+	MailDialer   MailDialer
+	RedisService config.RedisService
 }
 
 type ErrorMessages struct {
@@ -68,14 +77,17 @@ type ErrorStruct struct {
 	Result string `json:"error_msg"`
 }
 
-var JwtSecret = []byte(config.GlobalEnv.JwtSecret)
-
 var AuthToken = "auth_token"
 var GoogleToken = "google_token"
 var LineToken = "line_token"
 var RefreshAuthToken = "refresh_auth_token"
 var UserId = "user_id"
+var SessionId = "session_id"
+var Role = "role"
 var OauthState = "oauth_state"
+var MagicLinkDevice = "magic_link_device"
+// アクセストークンの有効期限(時間)
+// ACCESS_TOKEN_HOUR が設定されている場合は InitTokenConfig で上書きする
 var AuthTokenHour = 1
 
 var Uuid = 36
@@ -83,11 +95,13 @@ var Uuid = 36
 type ErrorValidationResponse = ResponseData[[]ErrorMessages]
 type ErrorMessageResponse = ResponseData[string]
 
+// リフレッシュトークンの有効期限(時間)
 // 推奨：90日間だが、一旦12時間で設定
+// REFRESH_TOKEN_HOUR が設定されている場合は InitTokenConfig で上書きする
 var RefreshAuthTokenHour = 12
 var SecondsInHour = 3600
 
-func NewUtilsFetcher(JwtSecret []byte) UtilsFetcher {
+func NewUtilsFetcher(KeyManager *KeyManager) UtilsFetcher {
 	var common common.CommonFetcher = common.NewCommonFetcher()
 	smtpHost := config.GlobalEnv.SmtpHost                     // SMTPサーバー
 	smtpPort, _ := common.StrToInt(config.GlobalEnv.SmtpPort) // SMTPポート
@@ -95,8 +109,9 @@ func NewUtilsFetcher(JwtSecret []byte) UtilsFetcher {
 	password := config.GlobalEnv.EmailPassword                // 送信元メールのパスワード（またはアプリパスワード）
 	mailDialer := NewSMTPMailDialer(smtpHost, smtpPort, fromEmail, password)
 	return &UtilsDataFetcher{
-		JwtSecret:  JwtSecret,
-		MailDialer: mailDialer,
+		KeyManager:   KeyManager,
+		MailDialer:   mailDialer,
+		RedisService: config.NewRedisManager(),
 	}
 }
 
@@ -112,66 +127,69 @@ func GetBaseURL() string {
 
 // トークン生成関数
 func (ud *UtilsDataFetcher) GenerateJWT(UserId int, ExpirationDate int) (string, error) {
+	return ud.generateJWT(UserId, "", "", ExpirationDate)
+}
+
+// generateJWT はセッションID・ロールをクレームに含めてトークンを生成する
+// セッションID・ロールが空の場合はsid・roleクレームを含めない
+func (ud *UtilsDataFetcher) generateJWT(UserId int, SessionId string, Role string, ExpirationDate int) (string, error) {
 	// トークンの有効期限を設定
 
 	// トークンのクレーム（データペイロード）を作成
 	// 検証時はtime.Now().Add(time.Duration(ExpirationDate) * time.Minute).Unix()で確認する
-	claims := jwt.MapClaims{
-		"UserId": UserId,
-		"exp":    time.Now().Add(time.Duration(ExpirationDate) * time.Hour).Unix(),
+	// jtiは失効リストでトークンを特定するために使用する
+	// typ/audでアクセストークンであることを示し、リフレッシュトークンと区別する
+	now := time.Now()
+	claims := typedClaims(TokenTypeAccess, UserId, jwt.MapClaims{
+		"jti": uuid.New().String(),
+		"iat": now.Unix(),
+		"exp": now.Add(time.Duration(ExpirationDate) * time.Hour).Unix(),
+	})
+	if SessionId != "" {
+		claims["sid"] = SessionId
 	}
-
-	// トークンを生成
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// トークンのクレームを確認
-	// tokenClaims, _ := token.Claims.(jwt.MapClaims)
-	// fmt.Printf("生成されたトークンのクレーム: %+v\n", tokenClaims)
-
-	// トークンに署名
-	tokenString, err := token.SignedString(ud.JwtSecret)
-	if err != nil {
-		return "", err
+	if Role != "" {
+		claims["role"] = Role
 	}
 
-	return tokenString, nil
+	// 現在の署名鍵でトークンを生成し、ヘッダーに鍵IDを付与する
+	return ud.KeyManager.Sign(claims)
 }
 
 // 新規有効期限付きのトークン発行
-func (ud *UtilsDataFetcher) NewToken(UserId int, ExpirationDate int) (string, error) {
-	return ud.GenerateJWT(UserId, ExpirationDate)
-}
-
-// 新規有効期限付きのリフレッシュトークン発行
-func (ud *UtilsDataFetcher) RefreshToken(UserId int, ExpirationDate int) (string, error) {
-	return ud.GenerateJWT(UserId, ExpirationDate)
+// セッションIDはセッション単位の失効確認、ロールは管理者用APIの認可に使用する
+func (ud *UtilsDataFetcher) NewToken(UserId int, SessionId string, Role string, ExpirationDate int) (string, error) {
+	return ud.generateJWT(UserId, SessionId, Role, ExpirationDate)
 }
 
 // パスワードの平文をハッシュ化
+// Argon2idでハッシュ化し、方式とパラメーターを含むPHC文字列形式で返す
 func (ud *UtilsDataFetcher) EncryptPassword(password string) (string, error) {
-	// パスワードの文字列をハッシュ化する
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return "", err
-	}
-	return string(hash), nil
+	return hashArgon2id(password, PasswordHashParams)
 }
 
 // ハッシュを比較
-func (ud *UtilsDataFetcher) CompareHashPassword(hashedPassword, requestPassword string) error {
-	// パスワードの文字列をハッシュ化して、既に登録されているハッシュ化したパスワードと比較します
-	if err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(requestPassword)); err != nil {
-		return err
+// 一致した場合、ハッシュの方式(bcrypt)やパラメーターが現在の設定と異なる場合は
+// 現在の設定で再作成したハッシュを返す(再作成が不要な場合は空文字)
+func (ud *UtilsDataFetcher) CompareHashPassword(hashedPassword, requestPassword string) (string, error) {
+	needsRehash, err := comparePasswordHash(hashedPassword, requestPassword)
+	if err != nil || !needsRehash {
+		return "", err
 	}
-	return nil
+
+	// 再作成に失敗しても比較結果は一致のため、既存のハッシュを使い続ける
+	newHash, err := hashArgon2id(requestPassword, PasswordHashParams)
+	if err != nil {
+		return "", nil
+	}
+	return newHash, nil
 }
 
 // トークンの検証
 // テストの都合上、*jwt.Tokenだと厳密チェックができないためinterfaceで対応
 func (ud *UtilsDataFetcher) ParseWithClaims(validationToken string) (interface{}, error) {
-	token, err := jwt.ParseWithClaims(validationToken, jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return JwtSecret, nil
-	})
+	// 鍵IDで検証鍵を選ぶため、ローテーション前の鍵で署名されたトークンも検証できる
+	token, err := ud.KeyManager.Parse(validationToken, jwt.MapClaims{})
 	if err != nil {
 		return nil, err
 	}
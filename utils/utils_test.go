@@ -2,14 +2,18 @@ package utils
 
 import (
 	"fmt"
+	"server/config"
+	"strings"
 	"testing"
 	"time"
 
+	mock_config "server/mock/config"
 	mock_utils "server/mock/utils"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestGetBaseURL(t *testing.T) {
@@ -24,7 +28,7 @@ func TestGetBaseURL(t *testing.T) {
 
 func TestGenerateJWT(t *testing.T) {
 	t.Run("GenerateJWT token発行できる", func(t *testing.T) {
-		utilsFetcher := NewUtilsFetcher(JwtSecret)
+		utilsFetcher := NewUtilsFetcher(testJwtKeys)
 
 		token, err := utilsFetcher.GenerateJWT(1, 3)
 
@@ -60,9 +64,9 @@ func TestGenerateJWT(t *testing.T) {
 
 func TestNewToken(t *testing.T) {
 	t.Run("NewToken token発行できる", func(t *testing.T) {
-		utilsFetcher := NewUtilsFetcher(JwtSecret)
+		utilsFetcher := NewUtilsFetcher(testJwtKeys)
 
-		token, err := utilsFetcher.NewToken(1, 3)
+		token, err := utilsFetcher.NewToken(1, "session1", "user", 3)
 
 		// クエリエラーが発生したことを確認
 		assert.NoError(t, err)
@@ -72,34 +76,422 @@ func TestNewToken(t *testing.T) {
 
 func TestRefreshToken(t *testing.T) {
 	t.Run("RefreshToken token発行できる", func(t *testing.T) {
-		utilsFetcher := NewUtilsFetcher(JwtSecret)
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisSet(gomock.Any(), gomock.Any(), 3*time.Hour).
+			Return(nil)
 
-		token, err := utilsFetcher.RefreshToken(1, 3)
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		token, err := utilsFetcher.RefreshToken(1, "session1", 3)
 
-		// クエリエラーが発生したことを確認
 		assert.NoError(t, err)
 		assert.NotEmpty(t, token)
 	})
+	t.Run("RefreshToken Redis保存エラー", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisSet(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("保存エラー"))
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		token, err := utilsFetcher.RefreshToken(1, "session1", 3)
+
+		assert.EqualError(t, err, "保存エラー")
+		assert.Empty(t, token)
+	})
+}
+
+func TestRotateRefreshToken(t *testing.T) {
+	claims := jwt.MapClaims{
+		"UserId": float64(1),
+		"typ":    TokenTypeRefresh,
+		"aud":    RefreshTokenAudience,
+		"iss":    JwtIssuer,
+		"fid":    "family",
+		"jti":    "token1",
+	}
+
+	t.Run("RotateRefreshToken アクセストークンは交換できない", func(t *testing.T) {
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys}
+
+		token, err := utilsFetcher.RotateRefreshToken(jwt.MapClaims{
+			"UserId": float64(1),
+			"typ":    TokenTypeAccess,
+			"aud":    AccessTokenAudience,
+			"iss":    JwtIssuer,
+			"jti":    "token1",
+		}, 3)
+
+		assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+		assert.Empty(t, token)
+	})
+
+	t.Run("RotateRefreshToken 新しいトークンに交換できる", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisExists("revoked_token:token1").
+			Return(false, nil)
+		mockRedisService.EXPECT().
+			RedisExists("revoked_user:1").
+			Return(false, nil)
+		mockRedisService.EXPECT().
+			RedisGet("refresh_family:family").
+			Return(`{"user_id":1,"token_id":"token1"}`, nil)
+		mockRedisService.EXPECT().
+			RedisCompareAndSet("refresh_family:family", `{"user_id":1,"token_id":"token1"}`, gomock.Any(), 3*time.Hour).
+			DoAndReturn(func(key, expected string, value interface{}, duration time.Duration) (bool, error) {
+				// 最新のトークンIDに更新されていること
+				family := value.(RefreshTokenFamily)
+				assert.Equal(t, 1, family.UserId)
+				assert.NotEqual(t, "token1", family.TokenId)
+				return true, nil
+			})
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		token, err := utilsFetcher.RotateRefreshToken(claims, 3)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token)
+	})
+	t.Run("RotateRefreshToken 使用済みのトークンはファミリーごと失効", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisExists("revoked_token:token1").
+			Return(false, nil)
+		mockRedisService.EXPECT().
+			RedisExists("revoked_user:1").
+			Return(false, nil)
+		mockRedisService.EXPECT().
+			RedisGet("refresh_family:family").
+			Return(`{"user_id":1,"token_id":"token2"}`, nil)
+		mockRedisService.EXPECT().
+			RedisDel("refresh_family:family").
+			Return(nil)
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		token, err := utilsFetcher.RotateRefreshToken(claims, 3)
+
+		assert.ErrorIs(t, err, ErrRefreshTokenReused)
+		assert.Empty(t, token)
+	})
+	t.Run("RotateRefreshToken 同時に交換された場合はファミリーごと失効", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisExists("revoked_token:token1").
+			Return(false, nil)
+		mockRedisService.EXPECT().
+			RedisExists("revoked_user:1").
+			Return(false, nil)
+		mockRedisService.EXPECT().
+			RedisGet("refresh_family:family").
+			Return(`{"user_id":1,"token_id":"token1"}`, nil)
+		// 取得後に他のリクエストが先に交換した
+		mockRedisService.EXPECT().
+			RedisCompareAndSet("refresh_family:family", `{"user_id":1,"token_id":"token1"}`, gomock.Any(), 3*time.Hour).
+			Return(false, nil)
+		mockRedisService.EXPECT().
+			RedisDel("refresh_family:family").
+			Return(nil)
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		token, err := utilsFetcher.RotateRefreshToken(claims, 3)
+
+		assert.ErrorIs(t, err, ErrRefreshTokenReused)
+		assert.Empty(t, token)
+	})
+	t.Run("RotateRefreshToken 他のユーザーのファミリー", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisExists("revoked_token:token1").
+			Return(false, nil)
+		mockRedisService.EXPECT().
+			RedisExists("revoked_user:1").
+			Return(false, nil)
+		mockRedisService.EXPECT().
+			RedisGet("refresh_family:family").
+			Return(`{"user_id":2,"token_id":"token1"}`, nil)
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		token, err := utilsFetcher.RotateRefreshToken(claims, 3)
+
+		assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+		assert.Empty(t, token)
+	})
+	t.Run("RotateRefreshToken 失効済みのファミリー", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisExists("revoked_token:token1").
+			Return(false, nil)
+		mockRedisService.EXPECT().
+			RedisExists("revoked_user:1").
+			Return(false, nil)
+		mockRedisService.EXPECT().
+			RedisGet("refresh_family:family").
+			Return("", fmt.Errorf("キーが存在しません: refresh_family:family"))
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		token, err := utilsFetcher.RotateRefreshToken(claims, 3)
+
+		assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+		assert.Empty(t, token)
+	})
+	t.Run("RotateRefreshToken ファミリーIDがないトークン", func(t *testing.T) {
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys}
+
+		token, err := utilsFetcher.RotateRefreshToken(jwt.MapClaims{"UserId": float64(1)}, 3)
+
+		assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+		assert.Empty(t, token)
+	})
+}
+
+func TestRevokeToken(t *testing.T) {
+	t.Run("RevokeToken 有効期限まで失効リストに登録する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisSet("revoked_token:token1", "1", gomock.Any()).
+			DoAndReturn(func(key string, value interface{}, duration time.Duration) error {
+				assert.LessOrEqual(t, duration, time.Hour)
+				assert.Greater(t, duration, 59*time.Minute)
+				return nil
+			})
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		err := utilsFetcher.RevokeToken(jwt.MapClaims{
+			"jti": "token1",
+			"exp": float64(time.Now().Add(time.Hour).Unix()),
+		})
+
+		assert.NoError(t, err)
+	})
+	t.Run("RevokeToken jtiがないトークン", func(t *testing.T) {
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys}
+
+		err := utilsFetcher.RevokeToken(jwt.MapClaims{"UserId": float64(1)})
+
+		assert.EqualError(t, err, "失効対象のトークンのクレームが不正です。")
+	})
+}
+
+func TestRevokeUserTokens(t *testing.T) {
+	t.Run("RevokeUserTokens 失効日時を登録する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisSet("revoked_user:1", gomock.Any(), time.Duration(RefreshAuthTokenHour)*time.Hour).
+			Return(nil)
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		err := utilsFetcher.RevokeUserTokens(1)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestRevokeSession(t *testing.T) {
+	t.Run("RevokeSession セッションを失効リストに登録する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisSet("revoked_session:session1", "1", time.Duration(RefreshAuthTokenHour)*time.Hour).
+			Return(nil)
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		err := utilsFetcher.RevokeSession("session1")
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestIsTokenRevoked(t *testing.T) {
+	now := time.Now().Unix()
+	claims := jwt.MapClaims{
+		"UserId": float64(1),
+		"jti":    "token1",
+		"iat":    float64(now),
+	}
+
+	t.Run("IsTokenRevoked 失効していない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisExists("revoked_token:token1").Return(false, nil)
+		mockRedisService.EXPECT().RedisExists("revoked_user:1").Return(false, nil)
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		revoked, err := utilsFetcher.IsTokenRevoked(claims)
+
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+	t.Run("IsTokenRevoked jtiが失効リストに登録済み", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisExists("revoked_token:token1").Return(true, nil)
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		revoked, err := utilsFetcher.IsTokenRevoked(claims)
+
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	})
+	t.Run("IsTokenRevoked 一括失効より前に発行されたトークン", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisExists("revoked_token:token1").Return(false, nil)
+		mockRedisService.EXPECT().RedisExists("revoked_user:1").Return(true, nil)
+		mockRedisService.EXPECT().RedisGet("revoked_user:1").Return(fmt.Sprintf("%d", now+1), nil)
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		revoked, err := utilsFetcher.IsTokenRevoked(claims)
+
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	})
+	t.Run("IsTokenRevoked 一括失効後に発行されたトークン", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisExists("revoked_token:token1").Return(false, nil)
+		mockRedisService.EXPECT().RedisExists("revoked_user:1").Return(true, nil)
+		mockRedisService.EXPECT().RedisGet("revoked_user:1").Return(fmt.Sprintf("%d", now-10), nil)
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		revoked, err := utilsFetcher.IsTokenRevoked(claims)
+
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+	t.Run("IsTokenRevoked セッションが失効済み", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().RedisExists("revoked_token:token1").Return(false, nil)
+		mockRedisService.EXPECT().RedisExists("revoked_session:session1").Return(true, nil)
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+
+		revoked, err := utilsFetcher.IsTokenRevoked(jwt.MapClaims{
+			"UserId": float64(1),
+			"sid":    "session1",
+			"jti":    "token1",
+			"iat":    float64(now),
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	})
+	t.Run("IsTokenRevoked jtiがないトークン", func(t *testing.T) {
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys}
+
+		revoked, err := utilsFetcher.IsTokenRevoked(jwt.MapClaims{"UserId": float64(1)})
+
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	})
+}
+
+func TestRevokeRefreshTokenFamily(t *testing.T) {
+	t.Run("RevokeRefreshTokenFamily ファミリーを削除できる", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRedisService := mock_config.NewMockRedisService(ctrl)
+		mockRedisService.EXPECT().
+			RedisSet(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil)
+		mockRedisService.EXPECT().
+			RedisDel(gomock.Any()).
+			Return(nil)
+
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys, RedisService: mockRedisService}
+		token, _ := utilsFetcher.RefreshToken(1, "session1", 3)
+
+		err := utilsFetcher.RevokeRefreshTokenFamily(token)
+
+		assert.NoError(t, err)
+	})
+	t.Run("RevokeRefreshTokenFamily ファミリーIDがないトークン", func(t *testing.T) {
+		utilsFetcher := &UtilsDataFetcher{KeyManager: testJwtKeys}
+		token, _ := utilsFetcher.NewToken(1, "session1", "user", 3)
+
+		err := utilsFetcher.RevokeRefreshTokenFamily(token)
+
+		assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+	})
 }
 
 func TestEncryptPassword(t *testing.T) {
 	t.Run("EncryptPassword ハッシュ化できること", func(t *testing.T) {
-		utilsFetcher := NewUtilsFetcher(JwtSecret)
+		utilsFetcher := NewUtilsFetcher(testJwtKeys)
 		val := "test"
 
 		// パスワードをハッシュ化
 		result, err := utilsFetcher.EncryptPassword(val)
 		assert.NoError(t, err, "ハッシュ化時にエラーが発生しました")
 
+		// 方式とパラメーターがPHC文字列形式で記録されていることを確認
+		assert.Regexp(t, `^\$argon2id\$v=19\$m=65536,t=3,p=2\$[A-Za-z0-9+/]{22}\$[A-Za-z0-9+/]{43}$`, result)
+
 		// ハッシュが平文のパスワードと一致するかを確認
-		err = utilsFetcher.CompareHashPassword(result, val)
+		newHash, err := utilsFetcher.CompareHashPassword(result, val)
 		assert.NoError(t, err, "ハッシュが平文パスワードと一致しませんでした")
+		assert.Empty(t, newHash)
 	})
 }
 
 func TestCompareHashPassword(t *testing.T) {
 	t.Run("CompareHashPassword nilが返されること", func(t *testing.T) {
-		utilsFetcher := NewUtilsFetcher(JwtSecret)
+		utilsFetcher := NewUtilsFetcher(testJwtKeys)
 		val := "test"
 
 		// パスワードをハッシュ化
@@ -107,23 +499,91 @@ func TestCompareHashPassword(t *testing.T) {
 		assert.NoError(t, err, "ハッシュ化時にエラーが発生しました")
 
 		// ハッシュ化されたパスワードと元の平文パスワードを比較
-		err = utilsFetcher.CompareHashPassword(hashedPassword, val)
+		newHash, err := utilsFetcher.CompareHashPassword(hashedPassword, val)
 		assert.NoError(t, err, "ハッシュが平文パスワードと一致しませんでした")
+		assert.Empty(t, newHash)
 	})
 	t.Run("CompareHashPassword errが発生すること", func(t *testing.T) {
-		utilsFetcher := NewUtilsFetcher(JwtSecret)
+		utilsFetcher := NewUtilsFetcher(testJwtKeys)
 		val := "test"
 
 		// ハッシュ化されたパスワードと元の平文パスワードを比較
-		err := utilsFetcher.CompareHashPassword(val, val)
+		_, err := utilsFetcher.CompareHashPassword(val, val)
 		assert.NotNil(t, err)
 	})
+	t.Run("CompareHashPassword パスワードが異なる場合はエラー", func(t *testing.T) {
+		utilsFetcher := NewUtilsFetcher(testJwtKeys)
+		hashedPassword, _ := utilsFetcher.EncryptPassword("test")
+
+		newHash, err := utilsFetcher.CompareHashPassword(hashedPassword, "test2")
+		assert.ErrorIs(t, err, ErrPasswordMismatch)
+		assert.Empty(t, newHash)
+	})
+	t.Run("CompareHashPassword bcryptのハッシュはArgon2idで再作成する", func(t *testing.T) {
+		utilsFetcher := NewUtilsFetcher(testJwtKeys)
+		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("test"), bcrypt.MinCost)
+
+		newHash, err := utilsFetcher.CompareHashPassword(string(hashedPassword), "test")
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(newHash, "$argon2id$"))
+
+		// 再作成したハッシュは現在の設定と一致するため、再作成しない
+		rehash, err := utilsFetcher.CompareHashPassword(newHash, "test")
+		assert.NoError(t, err)
+		assert.Empty(t, rehash)
+	})
+	t.Run("CompareHashPassword パラメーターが変わった場合は再作成する", func(t *testing.T) {
+		utilsFetcher := NewUtilsFetcher(testJwtKeys)
+		hashedPassword, _ := utilsFetcher.EncryptPassword("test")
+
+		defaultParams := PasswordHashParams
+		defer func() { PasswordHashParams = defaultParams }()
+		PasswordHashParams.Time = defaultParams.Time + 1
+
+		newHash, err := utilsFetcher.CompareHashPassword(hashedPassword, "test")
+		assert.NoError(t, err)
+		assert.Contains(t, newHash, fmt.Sprintf("t=%d,", PasswordHashParams.Time))
+	})
+	t.Run("CompareHashPassword PHC文字列の形式が不正", func(t *testing.T) {
+		utilsFetcher := NewUtilsFetcher(testJwtKeys)
+
+		_, err := utilsFetcher.CompareHashPassword("$argon2id$v=19$m=0,t=3,p=2$c2FsdA$a2V5", "test")
+		assert.ErrorIs(t, err, ErrInvalidPasswordHash)
+	})
+}
+
+func TestInitPasswordHashConfig(t *testing.T) {
+	defaultParams := PasswordHashParams
+	defaultEnv := config.GlobalEnv
+	defer func() {
+		PasswordHashParams = defaultParams
+		config.GlobalEnv = defaultEnv
+	}()
+
+	t.Run("InitPasswordHashConfig 環境変数で上書きできること", func(t *testing.T) {
+		config.GlobalEnv.Argon2Memory = "19456"
+		config.GlobalEnv.Argon2Time = "2"
+		config.GlobalEnv.Argon2Parallelism = "1"
+
+		assert.NoError(t, InitPasswordHashConfig())
+		assert.Equal(t, uint32(19456), PasswordHashParams.Memory)
+		assert.Equal(t, uint32(2), PasswordHashParams.Time)
+		assert.Equal(t, uint8(1), PasswordHashParams.Parallelism)
+	})
+	t.Run("InitPasswordHashConfig 不正な値はエラー", func(t *testing.T) {
+		PasswordHashParams = defaultParams
+		config.GlobalEnv.Argon2Memory = ""
+		config.GlobalEnv.Argon2Time = ""
+		config.GlobalEnv.Argon2Parallelism = "256"
+
+		assert.EqualError(t, InitPasswordHashConfig(), "ARGON2_PARALLELISMは1以上255以下の整数で指定してください: 256")
+	})
 }
 
 func TestParseWithClaims(t *testing.T) {
 	t.Run("ParseWithClaims トークンが返されること", func(t *testing.T) {
-		utilsFetcher := NewUtilsFetcher(JwtSecret)
-		token, _ := utilsFetcher.NewToken(1, 3)
+		utilsFetcher := NewUtilsFetcher(testJwtKeys)
+		token, _ := utilsFetcher.NewToken(1, "session1", "user", 3)
 
 		_, err := utilsFetcher.ParseWithClaims(token)
 
@@ -131,7 +591,7 @@ func TestParseWithClaims(t *testing.T) {
 		assert.NotEmpty(t, token)
 	})
 	t.Run("ParseWithClaims エラーが発生されること", func(t *testing.T) {
-		utilsFetcher := NewUtilsFetcher(JwtSecret)
+		utilsFetcher := NewUtilsFetcher(testJwtKeys)
 
 		token, err := utilsFetcher.ParseWithClaims("token")
 
@@ -142,8 +602,8 @@ func TestParseWithClaims(t *testing.T) {
 
 func TestMapClaims(t *testing.T) {
 	t.Run("MapClaims クレームが返されて、trueが返ってくること", func(t *testing.T) {
-		utilsFetcher := NewUtilsFetcher(JwtSecret)
-		token, _ := utilsFetcher.NewToken(1, 3)
+		utilsFetcher := NewUtilsFetcher(testJwtKeys)
+		token, _ := utilsFetcher.NewToken(1, "session1", "user", 3)
 
 		token1, _ := utilsFetcher.ParseWithClaims(token)
 
@@ -153,7 +613,7 @@ func TestMapClaims(t *testing.T) {
 		assert.NotEmpty(t, claims)
 	})
 	t.Run("MapClaims クレームが空で、falseが返ってくること", func(t *testing.T) {
-		utilsFetcher := NewUtilsFetcher(JwtSecret)
+		utilsFetcher := NewUtilsFetcher(testJwtKeys)
 
 		var token *jwt.Token // nil トークンを渡す
 
@@ -228,7 +688,7 @@ func TestSendMail(t *testing.T) {
 	})
 
 	t.Run("SendMail エラー発生", func(t *testing.T) {
-		utilsFetcher := NewUtilsFetcher(JwtSecret)
+		utilsFetcher := NewUtilsFetcher(testJwtKeys)
 
 		// テスト用の引数
 		toEmail := "recipient@example.com"
@@ -259,7 +719,7 @@ func TestDateTimeStr(t *testing.T) {
 			0,             // ナノ秒
 			jst,           // タイムゾーン
 		)
-		utilsFetcher := NewUtilsFetcher(JwtSecret)
+		utilsFetcher := NewUtilsFetcher(testJwtKeys)
 
 		result := utilsFetcher.DateTimeStr(specifiedTime, "2006年01月02日 15:04")
 
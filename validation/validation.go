@@ -5,8 +5,11 @@ import (
 
 	// "server/models"
 
+	"fmt"
 	"regexp"
 	"server/utils"
+	"strconv"
+	"unicode/utf8"
 
 	"github.com/asaskevich/govalidator"
 )
@@ -57,9 +60,10 @@ type RequestRetryAuthEmail struct {
 }
 
 type RequestSignInEditData struct {
-	UserId       string `json:"user_id" valid:"required~ユーザーIDは必須です。"`
-	UserEmail    string `json:"user_email" valid:"required~メールアドレスは必須です。,email~正しいメールアドレス形式である必要があります。"`
-	UserPassword string `json:"user_password"`
+	UserId          string `json:"user_id" valid:"required~ユーザーIDは必須です。"`
+	UserEmail       string `json:"user_email" valid:"required~メールアドレスは必須です。,email~正しいメールアドレス形式である必要があります。"`
+	UserPassword    string `json:"user_password"`
+	CurrentPassword string `json:"current_password" valid:"required~現在のパスワードは必須です。"`
 }
 
 type RequestSignInDeleteData struct {
@@ -86,14 +90,9 @@ type RequestNewPasswordUpdateData struct {
 	ConfirmPassword string `json:"confirm_password" valid:"required~確認パスワードは必須です。"`
 }
 
-type RequestGoogleCallbackData struct {
-	UserEmail string `json:"user_email" valid:"required~メールアドレスは必須です。,email~正しいメールアドレス形式である必要があります。"`
-	UserName  string `json:"user_name" valid:"required~ユーザー名は必須です。"`
-}
-
-type RequestLineCallbackData struct {
-	UserEmail string `json:"user_email" valid:"required~メールアドレスは必須です。,email~正しいメールアドレス形式である必要があります。"`
-	UserName  string `json:"user_name" valid:"required~ユーザー名は必須です。"`
+type RequestOAuthCallbackData struct {
+	Code  string `json:"code" valid:"required~認可コードは必須です。"`
+	State string `json:"state" valid:"required~stateは必須です。"`
 }
 
 type RequestPriceManagementData struct {
@@ -103,6 +102,10 @@ type RequestPriceManagementData struct {
 	Loan          string `json:"loan" valid:"int~ローンは整数値のみです。"`
 	Private       string `json:"private" valid:"int~プライベートは整数値のみです。"`
 	Insurance     string `json:"insurance" valid:"int~保険は整数値のみです。"`
+	LoanAuto      string `json:"loan_auto" valid:"in(true|false)~ローン自動反映はtrueかfalseのみです。"`
+	IncomeAuto    string `json:"income_auto" valid:"in(true|false)~収入自動反映はtrueかfalseのみです。"`
+	Months        string `json:"months"`
+	UserId        string `json:"user_id"`
 }
 
 type RequestYearIncomeAndDeductiontData struct {
@@ -148,17 +151,216 @@ type RequestDeleteIncomeData struct {
 	IncomeForecastID string `json:"income_forecast_id" valid:"required~年収推移IDは必須です。"`
 }
 
-// パスワードのカスタムバリデーション関数
-func validPassword(password string) bool {
-	// 大文字が含まれているかをチェック
-	hasUpperCase := regexp.MustCompile(`[A-Z]`).MatchString(password)
-	// 特殊文字が含まれているかをチェック
-	hasSpecialChar := regexp.MustCompile(`[.!?/-]`).MatchString(password)
-	// パスワードの長さが8～24文字かをチェック
-	isCorrectLength := len(password) >= 8 && len(password) <= 24
+type RequestInsertLoanData struct {
+	LoanName        string `json:"loan_name" valid:"required~ローン名は必須です。"`
+	Principal       string `json:"principal" valid:"required~借入額は必須です。"`
+	AnnualRate      string `json:"annual_rate"`
+	TermMonths      string `json:"term_months" valid:"required~返済期間は必須です。"`
+	StartDate       string `json:"start_date" valid:"required~借入日は必須です。"`
+	RateType        string `json:"rate_type" valid:"required~金利タイプは必須です。,in(fixed|variable)~金利タイプはfixedかvariableのみです。"`
+	RepaymentMethod string `json:"repayment_method" valid:"required~返済方法は必須です。,in(equal_payment|equal_principal)~返済方法はequal_paymentかequal_principalのみです。"`
+	UserId          string `json:"user_id" valid:"required~ユーザーIDは必須です。"`
+}
 
-	// すべての条件が満たされているかどうかを返す
-	return hasUpperCase && hasSpecialChar && isCorrectLength
+type RequestUpdateLoanData struct {
+	LoanId          string `json:"loan_id" valid:"required~ローンIDは必須です。"`
+	LoanName        string `json:"loan_name" valid:"required~ローン名は必須です。"`
+	Principal       string `json:"principal" valid:"required~借入額は必須です。"`
+	AnnualRate      string `json:"annual_rate"`
+	TermMonths      string `json:"term_months" valid:"required~返済期間は必須です。"`
+	StartDate       string `json:"start_date" valid:"required~借入日は必須です。"`
+	RateType        string `json:"rate_type" valid:"required~金利タイプは必須です。,in(fixed|variable)~金利タイプはfixedかvariableのみです。"`
+	RepaymentMethod string `json:"repayment_method" valid:"required~返済方法は必須です。,in(equal_payment|equal_principal)~返済方法はequal_paymentかequal_principalのみです。"`
+	UserId          string `json:"user_id" valid:"required~ユーザーIDは必須です。"`
+}
+
+type RequestDeleteLoanData struct {
+	LoanId string `json:"loan_id" valid:"required~ローンIDは必須です。"`
+	UserId string `json:"user_id" valid:"required~ユーザーIDは必須です。"`
+}
+
+type RequestLoanScheduleData struct {
+	LoanId string `json:"loan_id" valid:"required~ローンIDは必須です。"`
+	UserId string `json:"user_id" valid:"required~ユーザーIDは必須です。"`
+}
+
+type RequestLoanPrepaymentData struct {
+	Month  string `json:"month" valid:"required~繰上返済月は必須です。"`
+	Amount string `json:"amount" valid:"required~繰上返済額は必須です。"`
+	Type   string `json:"type" valid:"required~繰上返済方法は必須です。,in(shorten|reduce)~繰上返済方法はshortenかreduceのみです。"`
+}
+
+type RequestInsertPriceScenarioData struct {
+	ScenarioName  string `json:"scenario_name" valid:"required~シナリオ名は必須です。"`
+	MoneyReceived string `json:"money_received" valid:"required~月の収入は必須です。,int~月の収入は整数値のみです。"`
+	Bouns         string `json:"bouns" valid:"required~ボーナスは必須です。,int~ボーナスは整数値のみです。"`
+	FixedCost     string `json:"fixed_cost" valid:"required~固定費は必須です。,int~固定費は整数値のみです。"`
+	Loan          string `json:"loan" valid:"required~ローンは必須です。,int~ローンは整数値のみです。"`
+	Private       string `json:"private" valid:"required~プライベートは必須です。,int~プライベートは整数値のみです。"`
+	Insurance     string `json:"insurance" valid:"required~保険は必須です。,int~保険は整数値のみです。"`
+	UserId        string `json:"user_id" valid:"required~ユーザーIDは必須です。,int~ユーザーIDは整数値のみです。"`
+}
+
+type RequestPriceScenarioData struct {
+	ScenarioId string `json:"scenario_id" valid:"required~シナリオIDは必須です。"`
+	UserId     string `json:"user_id" valid:"required~ユーザーIDは必須です。,int~ユーザーIDは整数値のみです。"`
+}
+
+type RequestInsertSavingsGoalData struct {
+	GoalName       string `json:"goal_name" valid:"required~目標名は必須です。"`
+	TargetAmount   string `json:"target_amount" valid:"required~目標金額は必須です。"`
+	TargetDate     string `json:"target_date" valid:"required~目標日は必須です。"`
+	CurrentBalance string `json:"current_balance" valid:"required~現在の貯金額は必須です。"`
+	UserId         string `json:"user_id" valid:"required~ユーザーIDは必須です。"`
+}
+
+type RequestUpdateSavingsGoalData struct {
+	GoalId         string `json:"goal_id" valid:"required~目標IDは必須です。"`
+	GoalName       string `json:"goal_name" valid:"required~目標名は必須です。"`
+	TargetAmount   string `json:"target_amount" valid:"required~目標金額は必須です。"`
+	TargetDate     string `json:"target_date" valid:"required~目標日は必須です。"`
+	CurrentBalance string `json:"current_balance" valid:"required~現在の貯金額は必須です。"`
+	UserId         string `json:"user_id" valid:"required~ユーザーIDは必須です。"`
+}
+
+type RequestSavingsGoalData struct {
+	GoalId string `json:"goal_id" valid:"required~目標IDは必須です。"`
+	UserId string `json:"user_id" valid:"required~ユーザーIDは必須です。,int~ユーザーIDは整数値のみです。"`
+}
+
+type RequestInvestmentSimulationData struct {
+	UserId        string `json:"user_id" valid:"required~ユーザーIDは必須です。,int~ユーザーIDは整数値のみです。"`
+	Years         string `json:"years" valid:"required~シミュレーション年数は必須です。"`
+	NisaTsumitate string `json:"nisa_tsumitate"`
+	NisaGrowth    string `json:"nisa_growth"`
+	Ideco         string `json:"ideco"`
+	IdecoCategory string `json:"ideco_category" valid:"in(employee|corporate_dc|civil_servant|self_employed|housewife)~iDeCoの加入区分が正しくありません。"`
+	AnnualReturn  string `json:"annual_return"`
+}
+
+type RequestTakeHomeSimulationData struct {
+	MonthlySalary string `json:"monthly_salary" valid:"required~額面の月給は必須です。,int~額面の月給は整数値のみです。"`
+	Bonus         string `json:"bonus" valid:"int~賞与は整数値のみです。"`
+	BonusTimes    string `json:"bonus_times"`
+	Age           string `json:"age" valid:"required~年齢は必須です。"`
+	Prefecture    string `json:"prefecture" valid:"required~都道府県は必須です。"`
+	Dependents    string `json:"dependents"`
+	Year          string `json:"year"`
+}
+
+type RequestSessionUserData struct {
+	UserId string `json:"user_id" valid:"required~ユーザーIDは必須です。,int~ユーザーIDは整数値のみです。"`
+}
+
+type RequestSessionData struct {
+	SessionId string `json:"session_id" valid:"required~セッションIDは必須です。,uuid~セッションIDの形式が間違っています。"`
+	UserId    string `json:"user_id" valid:"required~ユーザーIDは必須です。,int~ユーザーIDは整数値のみです。"`
+}
+
+type RequestAccountUnlockData struct {
+	Token string `json:"token" valid:"required~トークンは必須です。,uuid~トークンの形式が間違っています。"`
+}
+
+type RequestEmailChangeData struct {
+	UserId    string `json:"user_id" valid:"required~ユーザーIDは必須です。,int~ユーザーIDは整数値のみです。"`
+	UserEmail string `json:"user_email" valid:"required~メールアドレスは必須です。,email~正しいメールアドレス形式である必要があります。"`
+}
+
+type RequestEmailChangeTokenData struct {
+	Token string `json:"token" valid:"required~トークンは必須です。"`
+}
+
+type RequestAccountRestoreData struct {
+	Token string `json:"token" valid:"required~トークンは必須です。"`
+}
+
+type RequestMagicLinkSignInData struct {
+	Token string `json:"token" valid:"required~トークンは必須です。"`
+}
+
+type RequestTotpUserData struct {
+	UserId string `json:"user_id" valid:"required~ユーザーIDは必須です。,int~ユーザーIDは整数値のみです。"`
+}
+
+type RequestTotpVerifyData struct {
+	UserId string `json:"user_id" valid:"required~ユーザーIDは必須です。,int~ユーザーIDは整数値のみです。"`
+	Code   string `json:"code" valid:"required~ワンタイムパスワードは必須です。"`
+}
+
+type RequestTotpDisableData struct {
+	UserId string `json:"user_id" valid:"required~ユーザーIDは必須です。,int~ユーザーIDは整数値のみです。"`
+	Code   string `json:"code" valid:"required~認証コードは必須です。"`
+}
+
+type RequestIdentityUserData struct {
+	UserId string `json:"user_id" valid:"required~ユーザーIDは必須です。,int~ユーザーIDは整数値のみです。"`
+}
+
+type RequestIdentityUnlinkData struct {
+	UserId   string `json:"user_id" valid:"required~ユーザーIDは必須です。,int~ユーザーIDは整数値のみです。"`
+	Provider string `json:"provider" valid:"required~外部認証は必須です。,matches(^[a-z][a-z0-9_-]*$)~外部認証の名称が不正です。"`
+}
+
+type RequestSignInTotpData struct {
+	ChallengeId string `json:"challenge_id" valid:"required~チャレンジIDは必須です。,uuid~チャレンジIDの形式が間違っています。"`
+	Code        string `json:"code" valid:"required~認証コードは必須です。"`
+}
+
+type RequestAdminUserData struct {
+	UserId string `json:"user_id" valid:"required~ユーザーIDは必須です。,int~ユーザーIDは整数値のみです。"`
+}
+
+type RequestAdminUsersData struct {
+	Search string `json:"search" valid:"runelength(0|100)~検索文字列は100文字以内で入力してください。"`
+	Role   string `json:"role" valid:"in(user|admin|support)~ロールはuser・admin・supportのいずれかです。"`
+	Limit  string `json:"limit"`
+	Offset string `json:"offset"`
+}
+
+type RequestAdminStatsData struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+type RequestAdminAuditLogData struct {
+	UserId string `json:"user_id" valid:"int~ユーザーIDは整数値のみです。"`
+	Limit  string `json:"limit"`
+	Offset string `json:"offset"`
+}
+
+type RequestDataExportData struct {
+	UserId string `json:"user_id" valid:"required~ユーザーIDは必須です。,int~ユーザーIDは整数値のみです。"`
+}
+
+type RequestDataExportDownloadData struct {
+	Token string `json:"token" valid:"required~トークンは必須です。"`
+}
+
+type RequestDataImportData struct {
+	UserId string `json:"user_id" valid:"required~ユーザーIDは必須です。,int~ユーザーIDは整数値のみです。"`
+	DryRun string `json:"dry_run" valid:"in(true|false)~ドライランはtrueかfalseのみです。"`
+}
+
+// passwordPolicyErrors は新しく設定するパスワードがポリシーを満たしていない理由をエラーメッセージにする
+// 未入力の場合は必須チェックのみとするため、エラーにしない
+func passwordPolicyErrors(field, label, password string) []utils.ErrorMessages {
+	var errorMessagesList []utils.ErrorMessages
+	if password == "" {
+		return errorMessagesList
+	}
+	for _, reason := range utils.CheckPasswordPolicy(label, password) {
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   field,
+			Message: reason,
+		})
+	}
+	return errorMessagesList
+}
+
+// validSignInPassword はサインイン時のパスワードの文字数を確認する
+// ポリシー変更前に登録したパスワードでもサインインできるよう、上限の文字数のみ確認する
+func validSignInPassword(password string) bool {
+	return utf8.RuneCountInString(password) <= utils.PasswordMaxLengthLimit
 }
 
 func validDate(date string) bool {
@@ -175,6 +377,126 @@ func validInt(val string) bool {
 	return intCase
 }
 
+// validTotpCode はワンタイムパスワード(6桁の数字)の形式チェック
+func validTotpCode(code string) bool {
+	return regexp.MustCompile(`^\d{6}$`).MatchString(code)
+}
+
+// validSecondFactorCode はワンタイムパスワード又はリカバリーコード(xxxxx-xxxxx)の形式チェック
+func validSecondFactorCode(code string) bool {
+	recoveryCase := regexp.MustCompile(`^[a-zA-Z2-7]{5}[- ]?[a-zA-Z2-7]{5}$`).MatchString(code)
+
+	return validTotpCode(code) || recoveryCase
+}
+
+// validPage は一覧の取得件数(1～100件)と取得開始位置(0以上)の形式チェック
+// 省略した場合は既定値を使用するため空は許可する
+func validPage(limit, offset string) []utils.ErrorMessages {
+	var errorMessagesList []utils.ErrorMessages
+
+	if limit != "" {
+		if value, err := strconv.Atoi(limit); !validInt(limit) || err != nil || value < 1 || value > 100 {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   "limit",
+				Message: "取得件数は1～100の整数値のみです。",
+			})
+		}
+	}
+
+	if _, err := strconv.Atoi(offset); offset != "" && (!validInt(offset) || err != nil) {
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "offset",
+			Message: "取得開始位置は0以上の整数値のみです。",
+		})
+	}
+
+	return errorMessagesList
+}
+
+func validFloat(val string) bool {
+	floatCase := regexp.MustCompile(`^\d+(\.\d+)?$`).MatchString(val)
+
+	// すべての条件が満たされているかどうかを返す
+	return floatCase
+}
+
+// validLoanValues はローン登録・更新で共通の数値及び日付チェックを行う
+func validLoanValues(principal, annualRate, termMonths, startDate, userId string) []utils.ErrorMessages {
+	var errorMessagesList []utils.ErrorMessages
+
+	if Principal := validInt(principal); !Principal && principal != "" {
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "principal",
+			Message: "借入額で数値文字列以外は無効です。",
+		})
+	}
+
+	if rate, err := strconv.ParseFloat(annualRate, 64); !validFloat(annualRate) || err != nil || rate > 30 {
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "annual_rate",
+			Message: "年利は0以上30以下の数値のみです。",
+		})
+	}
+
+	if months, err := strconv.Atoi(termMonths); termMonths != "" && (err != nil || months < 1 || months > 600) {
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "term_months",
+			Message: "返済期間は1以上600以下の整数値のみです。",
+		})
+	}
+
+	if date := validDate(startDate); !date && startDate != "" {
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "start_date",
+			Message: "借入日の形式が間違っています。",
+		})
+	}
+
+	if UserId := validInt(userId); !UserId && userId != "" {
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "user_id",
+			Message: "ユーザーIDは整数値のみです。",
+		})
+	}
+
+	return errorMessagesList
+}
+
+// validSavingsGoalValues は貯金目標登録・更新で共通の数値及び日付チェックを行う
+func validSavingsGoalValues(targetAmount, targetDate, currentBalance, userId string) []utils.ErrorMessages {
+	var errorMessagesList []utils.ErrorMessages
+
+	if TargetAmount := validInt(targetAmount); (!TargetAmount || targetAmount == "0") && targetAmount != "" {
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "target_amount",
+			Message: "目標金額は1以上の整数値のみです。",
+		})
+	}
+
+	if date := validDate(targetDate); !date && targetDate != "" {
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "target_date",
+			Message: "目標日の形式が間違っています。",
+		})
+	}
+
+	if CurrentBalance := validInt(currentBalance); !CurrentBalance && currentBalance != "" {
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "current_balance",
+			Message: "現在の貯金額で数値文字列以外は無効です。",
+		})
+	}
+
+	if UserId := validInt(userId); !UserId && userId != "" {
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "user_id",
+			Message: "ユーザーIDは整数値のみです。",
+		})
+	}
+
+	return errorMessagesList
+}
+
 func (data RequestSignInData) Validate() (bool, []utils.ErrorMessages) {
 	var errorMessagesList []utils.ErrorMessages
 	validArray := [2]bool{true, true}
@@ -191,7 +513,7 @@ func (data RequestSignInData) Validate() (bool, []utils.ErrorMessages) {
 		}
 	}
 
-	if password := validPassword(data.UserPassword); !password && data.UserPassword != "" {
+	if password := validSignInPassword(data.UserPassword); !password {
 		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
 			Field:   UserPassword,
 			Message: "パスワードの形式が間違っています。",
@@ -268,11 +590,8 @@ func (data TemporayRequestSignUpData) Validate() (bool, []utils.ErrorMessages) {
 		}
 	}
 
-	if password := validPassword(data.UserPassword); !password && data.UserPassword != "" {
-		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
-			Field:   UserPassword,
-			Message: "パスワードの形式が間違っています。",
-		})
+	if errMsgList := passwordPolicyErrors(UserPassword, "パスワード", data.UserPassword); len(errMsgList) > 0 {
+		errorMessagesList = append(errorMessagesList, errMsgList...)
 		valid = false
 	}
 
@@ -299,25 +618,7 @@ func (data RequestSignUpData) Validate() (bool, []utils.ErrorMessages) {
 	return valid, errorMessagesList
 }
 
-func (data RequestGoogleCallbackData) Validate() (bool, []utils.ErrorMessages) {
-	var errorMessagesList []utils.ErrorMessages
-
-	valid, err := govalidator.ValidateStruct(data)
-
-	if err != nil {
-		errorMap := govalidator.ErrorsByField(err)
-		for field, msg := range errorMap {
-			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
-				Field:   field,
-				Message: msg,
-			})
-		}
-	}
-
-	return valid, errorMessagesList
-}
-
-func (data RequestLineCallbackData) Validate() (bool, []utils.ErrorMessages) {
+func (data RequestOAuthCallbackData) Validate() (bool, []utils.ErrorMessages) {
 	var errorMessagesList []utils.ErrorMessages
 
 	valid, err := govalidator.ValidateStruct(data)
@@ -359,11 +660,8 @@ func (data RequestSignInEditData) Validate() (bool, []utils.ErrorMessages) {
 		validArray[0] = false
 	}
 
-	if password := validPassword(data.UserPassword); !password && data.UserPassword != "" {
-		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
-			Field:   UserPassword,
-			Message: "パスワードの形式が間違っています。",
-		})
+	if errMsgList := passwordPolicyErrors(UserPassword, "パスワード", data.UserPassword); len(errMsgList) > 0 {
+		errorMessagesList = append(errorMessagesList, errMsgList...)
 		validArray[1] = false
 	}
 
@@ -455,19 +753,13 @@ func (data RequestNewPasswordUpdateData) Validate() (bool, []utils.ErrorMessages
 		}
 	}
 
-	if newUserPassword := validPassword(data.NewUserPassword); !newUserPassword && data.NewUserPassword != "" {
-		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
-			Field:   NewUserPassword,
-			Message: "新しいパスワードの形式が間違っています。",
-		})
+	if errMsgList := passwordPolicyErrors(NewUserPassword, "新しいパスワード", data.NewUserPassword); len(errMsgList) > 0 {
+		errorMessagesList = append(errorMessagesList, errMsgList...)
 		validArray[0] = false
 	}
 
-	if confirmPassword := validPassword(data.ConfirmPassword); !confirmPassword && data.ConfirmPassword != "" {
-		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
-			Field:   ConfirmPassword,
-			Message: "確認パスワードの形式が間違っています。",
-		})
+	if errMsgList := passwordPolicyErrors(ConfirmPassword, "確認パスワード", data.ConfirmPassword); len(errMsgList) > 0 {
+		errorMessagesList = append(errorMessagesList, errMsgList...)
 		validArray[1] = false
 	}
 
@@ -495,6 +787,30 @@ func (data RequestPriceManagementData) Validate() (bool, []utils.ErrorMessages)
 		}
 	}
 
+	// ローンを自動反映する場合は登録済みローンを取得するためユーザーIDが必要
+	if data.LoanAuto == "true" && !validInt(data.UserId) {
+		valid = false
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "user_id",
+			Message: "ローン自動反映時はユーザーIDが必須又は整数値のみです。",
+		})
+	} else if data.IncomeAuto == "true" && !validInt(data.UserId) {
+		// 収入を自動反映する場合も年収推移データを取得するためユーザーIDが必要
+		valid = false
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "user_id",
+			Message: "収入自動反映時はユーザーIDが必須又は整数値のみです。",
+		})
+	}
+
+	if months, err := strconv.Atoi(data.Months); data.Months != "" && (err != nil || months < 1 || months > 24) {
+		valid = false
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "months",
+			Message: "平均する月数は1以上24以下の整数値のみです。",
+		})
+	}
+
 	return valid, errorMessagesList
 }
 
@@ -741,6 +1057,739 @@ func (data RequestDeleteIncomeData) Validate() (bool, []utils.ErrorMessages) {
 	return valid, errorMessagesList
 }
 
+func (data RequestInsertLoanData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	if loanErrors := validLoanValues(data.Principal, data.AnnualRate, data.TermMonths, data.StartDate, data.UserId); len(loanErrors) > 0 {
+		errorMessagesList = append(errorMessagesList, loanErrors...)
+		valid = false
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestUpdateLoanData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	if loanErrors := validLoanValues(data.Principal, data.AnnualRate, data.TermMonths, data.StartDate, data.UserId); len(loanErrors) > 0 {
+		errorMessagesList = append(errorMessagesList, loanErrors...)
+		valid = false
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestDeleteLoanData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	if UserId := validInt(data.UserId); !UserId && data.UserId != "" {
+		valid = false
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "user_id",
+			Message: "ユーザーIDは整数値のみです。",
+		})
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestLoanScheduleData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	if UserId := validInt(data.UserId); !UserId && data.UserId != "" {
+		valid = false
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "user_id",
+			Message: "ユーザーIDは整数値のみです。",
+		})
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestLoanPrepaymentData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+	validArray := [2]bool{true, true}
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	if Month := validInt(data.Month); (!Month || data.Month == "0") && data.Month != "" {
+		validArray[0] = false
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "month",
+			Message: "繰上返済月は1以上の整数値のみです。",
+		})
+	}
+
+	if Amount := validInt(data.Amount); !Amount && data.Amount != "" {
+		validArray[1] = false
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "amount",
+			Message: "繰上返済額で数値文字列以外は無効です。",
+		})
+	}
+
+	for _, validCheck := range validArray {
+		if !validCheck {
+			valid = false
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestInsertPriceScenarioData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestPriceScenarioData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestInsertSavingsGoalData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	if goalErrors := validSavingsGoalValues(data.TargetAmount, data.TargetDate, data.CurrentBalance, data.UserId); len(goalErrors) > 0 {
+		errorMessagesList = append(errorMessagesList, goalErrors...)
+		valid = false
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestUpdateSavingsGoalData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	if goalErrors := validSavingsGoalValues(data.TargetAmount, data.TargetDate, data.CurrentBalance, data.UserId); len(goalErrors) > 0 {
+		errorMessagesList = append(errorMessagesList, goalErrors...)
+		valid = false
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestSavingsGoalData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestInvestmentSimulationData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	if years, err := strconv.Atoi(data.Years); data.Years != "" && (err != nil || years < 1 || years > 50) {
+		valid = false
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "years",
+			Message: "シミュレーション年数は1以上50以下の整数値のみです。",
+		})
+	}
+
+	// 月額の積立額は未指定の場合0円として扱う
+	amounts := []struct {
+		field string
+		value string
+		name  string
+	}{
+		{"nisa_tsumitate", data.NisaTsumitate, "つみたて投資枠の月額"},
+		{"nisa_growth", data.NisaGrowth, "成長投資枠の月額"},
+		{"ideco", data.Ideco, "iDeCoの月額"},
+	}
+	for _, amount := range amounts {
+		if !validInt(amount.value) && amount.value != "" {
+			valid = false
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   amount.field,
+				Message: fmt.Sprintf("%sは0以上の整数値のみです。", amount.name),
+			})
+		}
+	}
+
+	if rate, err := strconv.ParseFloat(data.AnnualReturn, 64); data.AnnualReturn != "" && (!validFloat(data.AnnualReturn) || err != nil || rate > 20) {
+		valid = false
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "annual_return",
+			Message: "想定利回りは0以上20以下の数値のみです。",
+		})
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestTakeHomeSimulationData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	// 範囲チェックが必要な項目(未指定の場合は既定値を使う)
+	ranges := []struct {
+		field string
+		value string
+		lower int
+		upper int
+		msg   string
+	}{
+		{"bonus_times", data.BonusTimes, 1, 12, "賞与の支給回数は1以上12以下の整数値のみです。"},
+		{"age", data.Age, 15, 100, "年齢は15以上100以下の整数値のみです。"},
+		{"dependents", data.Dependents, 0, 10, "扶養親族の数は0以上10以下の整数値のみです。"},
+		{"year", data.Year, 2000, 2100, "年度の形式が間違っています。"},
+	}
+	for _, r := range ranges {
+		if num, err := strconv.Atoi(r.value); r.value != "" && (err != nil || num < r.lower || num > r.upper) {
+			valid = false
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   r.field,
+				Message: r.msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestSessionUserData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestSessionData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestAccountUnlockData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestEmailChangeData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestEmailChangeTokenData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestAccountRestoreData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestMagicLinkSignInData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestIdentityUserData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestIdentityUnlinkData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestTotpUserData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestTotpVerifyData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	if code := validTotpCode(data.Code); !code && data.Code != "" {
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "code",
+			Message: "ワンタイムパスワードは6桁の数字のみです。",
+		})
+		valid = false
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestTotpDisableData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	if code := validSecondFactorCode(data.Code); !code && data.Code != "" {
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "code",
+			Message: "認証コードの形式が間違っています。",
+		})
+		valid = false
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestSignInTotpData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	if code := validSecondFactorCode(data.Code); !code && data.Code != "" {
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "code",
+			Message: "認証コードの形式が間違っています。",
+		})
+		valid = false
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestAdminUserData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestAdminUsersData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	if pageErrors := validPage(data.Limit, data.Offset); len(pageErrors) > 0 {
+		errorMessagesList = append(errorMessagesList, pageErrors...)
+		valid = false
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestAdminStatsData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+	valid := true
+
+	if date := validDate(data.StartDate); !date && data.StartDate != "" {
+		valid = false
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "start_date",
+			Message: "開始日の形式が間違っています。",
+		})
+	}
+
+	if date := validDate(data.EndDate); !date && data.EndDate != "" {
+		valid = false
+		errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+			Field:   "end_date",
+			Message: "終了日の形式が間違っています。",
+		})
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestAdminAuditLogData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	if pageErrors := validPage(data.Limit, data.Offset); len(pageErrors) > 0 {
+		errorMessagesList = append(errorMessagesList, pageErrors...)
+		valid = false
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestDataExportData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestDataExportDownloadData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
+func (data RequestDataImportData) Validate() (bool, []utils.ErrorMessages) {
+	var errorMessagesList []utils.ErrorMessages
+
+	valid, err := govalidator.ValidateStruct(data)
+
+	if err != nil {
+		errorMap := govalidator.ErrorsByField(err)
+		for field, msg := range errorMap {
+			errorMessagesList = append(errorMessagesList, utils.ErrorMessages{
+				Field:   field,
+				Message: msg,
+			})
+		}
+	}
+
+	return valid, errorMessagesList
+}
+
 // func (data SignInValidation) Validate() error {
 // 	//NOTE: 日本語のエラー文が不要で、デフォルトの英語のエラー文で必要十分である場合、`.Error("xxx")`は不要でOK
 // 	return validation.ValidateStruct(&data,